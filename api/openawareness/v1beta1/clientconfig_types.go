@@ -1,6 +1,10 @@
 package v1beta1
 
 import (
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -13,10 +17,251 @@ type ClientConfigSpec struct {
 	// +kubebuilder:validation:Required
 	Address string `json:"address,omitempty"`
 
+	// PathPrefix is prepended to the paths of all API requests (ruler, Alertmanager, query and
+	// status), for gateways exposing the Mimir API below a path, e.g. /mimir
+	// +kubebuilder:validation:Pattern=`^(/[A-Za-z0-9._~-]+)+/?$`
+	// +kubebuilder:validation:MaxLength=253
+	// +optional
+	PathPrefix string `json:"pathPrefix,omitempty"`
+
 	// Type specifies whether this is a Mimir or Prometheus instance
 	// +kubebuilder:validation:Enum=mimir;prometheus
 	// +kubebuilder:validation:Required
 	Type ClientType `json:"type,omitempty"`
+
+	// Auth configures how the controller authenticates against the instance.
+	// Requests are sent without credentials if unset.
+	// +optional
+	Auth *ClientAuth `json:"auth,omitempty"`
+
+	// TLS configures the CA and client certificate used to connect to the instance.
+	// The files are typically Secrets mounted into the controller pod; rotated client
+	// certificates are picked up without restarting the controller.
+	// The CA may also be read from a ConfigMap, and verification disabled for lab environments.
+	// +optional
+	TLS *ClientTLS `json:"tls,omitempty"`
+
+	// Proxy dials all connections to the instance through a SOCKS5 proxy, for instances only
+	// reachable through a bastion or an SSH tunnel. The proxy is part of the health check
+	// +optional
+	Proxy *ClientProxy `json:"proxy,omitempty"`
+
+	// Default makes this ClientConfig the one used by resources without the
+	// openawareness.io/client-name annotation. At most one default may exist per scope.
+	// +optional
+	Default bool `json:"default,omitempty"`
+
+	// DefaultScope selects the resources a default ClientConfig applies to: Namespace for
+	// resources in its own namespace, Cluster for resources in any namespace.
+	// A default in the resource's namespace takes precedence over a cluster-wide default.
+	// +kubebuilder:validation:Enum=Namespace;Cluster
+	// +kubebuilder:default=Namespace
+	// +optional
+	DefaultScope DefaultScope `json:"defaultScope,omitempty"`
+
+	// Compression selects the compression of request bodies, e.g. rule groups and Alertmanager
+	// configurations. gzip reduces push latency over slow links if the instance, or a proxy in
+	// front of it, accepts gzip encoded requests.
+	// +kubebuilder:validation:Enum=none;gzip
+	// +kubebuilder:default=none
+	// +optional
+	Compression RequestCompression `json:"compression,omitempty"`
+
+	// DefaultTenant is the Mimir tenant (org ID) of resources synced with this ClientConfig
+	// without the openawareness.io/mimir-tenant annotation, instead of "anonymous".
+	// May name an alias of tenantAliases.
+	// +kubebuilder:validation:MaxLength=150
+	// +optional
+	DefaultTenant string `json:"defaultTenant,omitempty"`
+
+	// TenantAliases maps team-facing names to Mimir tenants (org IDs). The tenant annotations of
+	// resources synced with this ClientConfig may name an alias instead of the org ID; names
+	// without alias are used as org ID. Aliases must resolve to org IDs, not to other aliases.
+	// +kubebuilder:validation:XValidation:rule="self.all(alias, self[alias].matches(\"^[a-zA-Z0-9!._*'()-]{1,150}$\"))",message="tenant aliases must resolve to valid Mimir org IDs"
+	// +kubebuilder:validation:XValidation:rule="self.all(alias, self[alias] == alias || !(self[alias] in self))",message="tenant aliases must resolve to org IDs, not to other aliases"
+	// +optional
+	TenantAliases map[string]string `json:"tenantAliases,omitempty"`
+
+	// AllowedTenants restricts the Mimir tenants (org IDs) resources may be synced to through this
+	// ClientConfig, all tenants are allowed if empty. Tenants are checked after alias resolution;
+	// pushes to other tenants are refused with reason TenantNotAllowed.
+	// +optional
+	AllowedTenants []string `json:"allowedTenants,omitempty"`
+
+	// DeniedTenants lists Mimir tenants (org IDs) resources must not be synced to through this
+	// ClientConfig. Takes precedence over allowedTenants.
+	// +optional
+	DeniedTenants []string `json:"deniedTenants,omitempty"`
+
+	// ListTenants lists the tenants of the Mimir instance through its admin API when the
+	// ClientConfig is reconciled and reports their number and a sample in status.tenants,
+	// to confirm the gateway and tenancy wiring during onboarding. Requires the distributor
+	// admin API to be reachable through address
+	// +optional
+	ListTenants bool `json:"listTenants,omitempty"`
+
+	// VerifyQuery runs an `up` query for the default tenant through the query API when the
+	// ClientConfig is reconciled and reports the outcome in the QueryReachable condition,
+	// separate from the ruler reachability in Ready, since gateways may route the rule
+	// configuration and query paths differently
+	// +optional
+	VerifyQuery bool `json:"verifyQuery,omitempty"`
+
+	// MinRuleInterval enforces a minimum evaluation interval of the rule groups pushed through
+	// this ClientConfig, protecting the ruler from rules evaluated every second, e.g. imported
+	// from development clusters. Groups without interval use the default of the ruler
+	// +optional
+	MinRuleInterval *RuleIntervalPolicy `json:"minRuleInterval,omitempty"`
+}
+
+// RuleIntervalPolicy defines the minimum evaluation interval of rule groups
+type RuleIntervalPolicy struct {
+	// Minimum is the shortest evaluation interval of a rule group, e.g. 15s
+	// +kubebuilder:validation:Required
+	Minimum metav1.Duration `json:"minimum"`
+
+	// Action is Clamp to raise shorter intervals to the minimum, reported as RuleIntervalClamped
+	// event, or Reject to refuse pushing PrometheusRules with shorter intervals
+	// +kubebuilder:validation:Enum=Clamp;Reject
+	// +kubebuilder:default=Clamp
+	// +optional
+	Action RuleIntervalAction `json:"action,omitempty"`
+}
+
+// RuleIntervalAction defines how rule groups below the minimum interval are handled
+type RuleIntervalAction string
+
+const (
+	// RuleIntervalClamp raises the interval of the groups to the minimum
+	RuleIntervalClamp RuleIntervalAction = "Clamp"
+	// RuleIntervalReject refuses to push the groups
+	RuleIntervalReject RuleIntervalAction = "Reject"
+)
+
+// RequestCompression defines the compression of request bodies sent to an instance
+type RequestCompression string
+
+const (
+	// CompressionNone sends request bodies uncompressed
+	CompressionNone RequestCompression = "none"
+	// CompressionGzip sends request bodies gzip compressed with Content-Encoding: gzip
+	CompressionGzip RequestCompression = "gzip"
+)
+
+// DefaultScope defines which resources a default ClientConfig applies to
+type DefaultScope string
+
+const (
+	// DefaultScopeNamespace applies the default to resources in the ClientConfig's namespace
+	DefaultScopeNamespace DefaultScope = "Namespace"
+	// DefaultScopeCluster applies the default to resources in all namespaces
+	DefaultScopeCluster DefaultScope = "Cluster"
+)
+
+// ClientTLS configures TLS from files in the controller pod and ConfigMaps
+// +kubebuilder:validation:XValidation:rule="has(self.certPath) == has(self.keyPath)",message="certPath and keyPath must be set together"
+type ClientTLS struct {
+	// CAPath is the path of the CA bundle used to verify the server certificate
+	// +optional
+	CAPath string `json:"caPath,omitempty"`
+
+	// CertPath is the path of the client certificate for mutual TLS
+	// +optional
+	CertPath string `json:"certPath,omitempty"`
+
+	// KeyPath is the path of the private key of the client certificate
+	// +optional
+	KeyPath string `json:"keyPath,omitempty"`
+
+	// ServerName overrides the server name used to verify the server certificate
+	// +optional
+	ServerName string `json:"serverName,omitempty"`
+
+	// CAConfigMapRef selects the key of a ConfigMap in the namespace of the ClientConfig holding
+	// a PEM CA bundle used to verify the server certificate, together with caPath. The system
+	// roots are not used if set. Changes of the ConfigMap recreate the client
+	// +optional
+	CAConfigMapRef *corev1.ConfigMapKeySelector `json:"caConfigMapRef,omitempty"`
+
+	// InsecureSkipVerify disables the verification of the server certificate, e.g. for lab
+	// environments with self-signed gateways. Reported by the InsecureTLS condition
+	// +optional
+	InsecureSkipVerify bool `json:"insecureSkipVerify,omitempty"`
+}
+
+// ClientProxy configures the proxy connections to an instance are dialed through
+type ClientProxy struct {
+	// URL is the URL of the SOCKS5 proxy without credentials, socks5://host:port to resolve
+	// the address of the instance in the controller or socks5h://host:port to resolve it
+	// through the proxy
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^socks5h?://[^/@]+/?$`
+	URL string `json:"url"`
+}
+
+// ClientAuth configures the authentication of a ClientConfig
+type ClientAuth struct {
+	// WorkloadIdentity authenticates by exchanging the projected service account token
+	// of the controller for a gateway token, so no credentials need to be stored in Secrets
+	// +optional
+	WorkloadIdentity *WorkloadIdentityAuth `json:"workloadIdentity,omitempty"`
+
+	// HMAC signs every request with a key shared with a gateway, in addition to the
+	// authentication of workloadIdentity if set
+	// +optional
+	HMAC *HMACAuth `json:"hmac,omitempty"`
+}
+
+// HMACAuth configures the HMAC signature of requests. The signature covers the method, the
+// request URI, the Unix time sent in X-Signature-Timestamp and the body as sent.
+type HMACAuth struct {
+	// SecretRef selects the key of a Secret in the namespace of the ClientConfig holding the
+	// signing key. Changes of the Secret recreate the client
+	// +kubebuilder:validation:Required
+	SecretRef corev1.SecretKeySelector `json:"secretRef"`
+
+	// Header is the header the hex encoded signature is sent in
+	// +kubebuilder:default="X-Signature"
+	// +kubebuilder:validation:Pattern=`^[A-Za-z0-9-]+$`
+	// +optional
+	Header string `json:"header,omitempty"`
+
+	// Algorithm is the hash function of the HMAC
+	// +kubebuilder:validation:Enum=SHA256;SHA512
+	// +kubebuilder:default=SHA256
+	// +optional
+	Algorithm HMACAlgorithm `json:"algorithm,omitempty"`
+}
+
+// HMACAlgorithm defines the hash function of an HMAC signature
+type HMACAlgorithm string
+
+const (
+	// HMACSHA256 signs requests with HMAC-SHA256
+	HMACSHA256 HMACAlgorithm = "SHA256"
+	// HMACSHA512 signs requests with HMAC-SHA512
+	HMACSHA512 HMACAlgorithm = "SHA512"
+)
+
+// DefaultServiceAccountTokenPath is where the projected service account token is read from by default
+const DefaultServiceAccountTokenPath = "/var/run/secrets/openawareness/serviceaccount/token"
+
+// WorkloadIdentityAuth configures an OAuth 2.0 token exchange (RFC 8693) of the projected
+// service account token. The exchanged token is sent as bearer token and refreshed before it expires.
+type WorkloadIdentityAuth struct {
+	// TokenURL is the token exchange endpoint of the identity provider or Mimir gateway
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^https?://`
+	TokenURL string `json:"tokenURL"`
+
+	// Audience is the audience requested for the exchanged token
+	// +optional
+	Audience string `json:"audience,omitempty"`
+
+	// TokenPath is the path of the projected service account token in the controller pod
+	// +kubebuilder:default="/var/run/secrets/openawareness/serviceaccount/token"
+	// +optional
+	TokenPath string `json:"tokenPath,omitempty"`
 }
 
 // ClientType defines the type of client (Mimir or Prometheus)
@@ -45,23 +290,77 @@ type ClientConfigStatus struct {
 	// +optional
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
 
-	// LastConnectionTime is the timestamp of the last successful connection attempt
+	// ObservedGeneration is the generation of the ClientConfig the status is based upon
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// LastConnectionTime is the timestamp the connection was last established, health checks
+	// of an established connection keep it
 	// +optional
 	LastConnectionTime *metav1.Time `json:"lastConnectionTime,omitempty"`
 
 	// ConnectionStatus indicates whether the client can connect to Mimir/Prometheus
+	// +kubebuilder:validation:Enum=Connected;Disconnected
 	// +optional
 	ConnectionStatus ConnectionStatus `json:"connectionStatus,omitempty"`
 
 	// ErrorMessage contains the last error message if connection failed
 	// +optional
 	ErrorMessage string `json:"errorMessage,omitempty"`
+
+	// Tenants summarizes the tenants of the Mimir instance, set if spec.listTenants is enabled
+	// +optional
+	Tenants *TenantListStatus `json:"tenants,omitempty"`
+}
+
+// TenantListStatus summarizes the tenants listed through the Mimir admin API
+type TenantListStatus struct {
+	// Count is the number of tenants
+	Count int32 `json:"count"`
+
+	// Sample are the first tenant IDs in sorted order
+	// +optional
+	Sample []string `json:"sample,omitempty"`
+
+	// LastListTime is the timestamp of the last successful listing
+	// +optional
+	LastListTime *metav1.Time `json:"lastListTime,omitempty"`
 }
 
 // Condition types for ClientConfig
 const (
 	// ConditionTypeReady indicates whether the ClientConfig is ready to use
 	ConditionTypeReady = "Ready"
+	// ConditionTypePaused indicates whether remote mutations are paused via annotation
+	ConditionTypePaused = "Paused"
+	// ConditionTypeDefaultConflict indicates whether another default ClientConfig exists for the same scope
+	ConditionTypeDefaultConflict = "DefaultConflict"
+	// ConditionTypeInsecureTLS is present while the server certificate is not verified
+	ConditionTypeInsecureTLS = "InsecureTLS"
+	// ConditionTypeTenantsListed indicates whether the tenants could be listed, present if
+	// spec.listTenants is enabled
+	ConditionTypeTenantsListed = "TenantsListed"
+	// ConditionTypeQueryReachable indicates whether the default tenant can be queried, present if
+	// spec.verifyQuery is enabled
+	ConditionTypeQueryReachable = "QueryReachable"
+	// ConditionTypeDegraded indicates whether requests to Mimir are paused by the circuit breaker
+	// after consecutive server errors, present for Mimir clients
+	ConditionTypeDegraded = "Degraded"
+
+	// The connectivity conditions break Ready down by layer, in the order the connection to
+	// the endpoint passes them. The first failing layer is False, the layers after it Unknown.
+
+	// ConditionTypeURLValid indicates whether the address is a valid URL
+	ConditionTypeURLValid = "URLValid"
+	// ConditionTypeDNSResolvable indicates whether the host of the address resolves
+	ConditionTypeDNSResolvable = "DNSResolvable"
+	// ConditionTypeTLSHandshake indicates whether the TLS handshake with the endpoint succeeds,
+	// true without handshake for http addresses
+	ConditionTypeTLSHandshake = "TLSHandshake"
+	// ConditionTypeAuthenticated indicates whether the endpoint accepts the credentials
+	ConditionTypeAuthenticated = "Authenticated"
+	// ConditionTypeAPIReachable indicates whether the API of the endpoint answers the health check
+	ConditionTypeAPIReachable = "APIReachable"
 )
 
 // Condition reasons for ClientConfig
@@ -76,6 +375,8 @@ const (
 	ReasonTimeoutError = "TimeoutError"
 	// ReasonDNSResolutionError indicates DNS resolution failed
 	ReasonDNSResolutionError = "DNSResolutionError"
+	// ReasonTokenExchangeFailed indicates the service account token could not be exchanged
+	ReasonTokenExchangeFailed = "TokenExchangeFailed"
 	// ReasonUnauthorized indicates invalid credentials (401)
 	ReasonUnauthorized = "Unauthorized"
 	// ReasonForbidden indicates insufficient permissions (403)
@@ -86,8 +387,41 @@ const (
 	ReasonTooManyRequests = "TooManyRequests"
 	// ReasonServerError indicates a server-side error (5xx)
 	ReasonServerError = "ServerError"
+	// ReasonRequestRejected indicates the request was rejected by the server (other 4xx)
+	ReasonRequestRejected = "RequestRejected"
 	// ReasonConnected indicates successful connection
 	ReasonConnected = "Connected"
+	// ReasonPaused indicates the resource is paused and no remote changes are made
+	ReasonPaused = "Paused"
+	// ReasonResumed indicates the resource was resumed after being paused
+	ReasonResumed = "Resumed"
+	// ReasonDefaultConflict indicates several ClientConfigs are the default for the same scope
+	ReasonDefaultConflict = "DefaultConflict"
+	// ReasonUniqueDefault indicates the ClientConfig is the only default for its scope
+	ReasonUniqueDefault = "UniqueDefault"
+	// ReasonInsecureSkipVerify indicates the verification of the server certificate is disabled
+	ReasonInsecureSkipVerify = "InsecureSkipVerify"
+	// ReasonTenantNotAllowed indicates the tenant of a resource is not allowed by its ClientConfig
+	ReasonTenantNotAllowed = "TenantNotAllowed"
+	// ReasonTenantsListed indicates the tenants were listed through the admin API
+	ReasonTenantsListed = "TenantsListed"
+	// ReasonTenantListingUnsupported indicates the client cannot list tenants
+	ReasonTenantListingUnsupported = "TenantListingUnsupported"
+	// ReasonQueryReachable indicates a query for the default tenant succeeded
+	ReasonQueryReachable = "QueryReachable"
+	// ReasonQueryUnsupported indicates the client cannot run queries
+	ReasonQueryUnsupported = "QueryUnsupported"
+	// ReasonCircuitOpen indicates requests are refused after consecutive server errors
+	ReasonCircuitOpen = "CircuitOpen"
+	// ReasonCircuitClosed indicates requests are sent to the endpoint
+	ReasonCircuitClosed = "CircuitClosed"
+	// ReasonCheckPassed indicates a connectivity layer was passed
+	ReasonCheckPassed = "CheckPassed"
+	// ReasonNotChecked indicates a connectivity layer was not reached, or the failure could not
+	// be attributed to a layer
+	ReasonNotChecked = "NotChecked"
+	// ReasonPlainHTTP indicates the address uses http, no TLS handshake takes place
+	ReasonPlainHTTP = "PlainHTTP"
 )
 
 // +kubebuilder:object:root=true
@@ -111,6 +445,59 @@ type ClientConfigList struct {
 	Items           []ClientConfig `json:"items"`
 }
 
+// IsDefaultFor reports whether the ClientConfig is a default applying to resources in namespace
+// within the given scope.
+func (c *ClientConfig) IsDefaultFor(namespace string, scope DefaultScope) bool {
+	if !c.Spec.Default || c.EffectiveDefaultScope() != scope {
+		return false
+	}
+	return scope == DefaultScopeCluster || c.Namespace == namespace
+}
+
+// ResolveTenant returns the Mimir tenant (org ID) of a resource synced with the ClientConfig
+// whose tenant annotation is name: the org ID of the alias name, or name itself if it is no
+// alias. An empty name resolves to the default tenant, and to an empty string if that is
+// unset. A nil ClientConfig returns name unchanged.
+func (c *ClientConfig) ResolveTenant(name string) string {
+	if c == nil {
+		return name
+	}
+	if name == "" {
+		name = c.Spec.DefaultTenant
+	}
+	if orgID, ok := c.Spec.TenantAliases[name]; ok {
+		return orgID
+	}
+	return name
+}
+
+// TenantAllowed reports whether resources may be synced to the Mimir tenant through the
+// ClientConfig: the tenant is not denied and, if allowed tenants are set, one of them.
+// A nil ClientConfig allows all tenants.
+func (c *ClientConfig) TenantAllowed(tenantID string) bool {
+	if c == nil {
+		return true
+	}
+	if slices.Contains(c.Spec.DeniedTenants, tenantID) {
+		return false
+	}
+	return len(c.Spec.AllowedTenants) == 0 || slices.Contains(c.Spec.AllowedTenants, tenantID)
+}
+
+// Endpoint returns the base URL API requests through the ClientConfig are sent to, the address
+// followed by the path prefix.
+func (c *ClientConfig) Endpoint() string {
+	return strings.TrimSuffix(c.Spec.Address, "/") + strings.TrimSuffix(c.Spec.PathPrefix, "/")
+}
+
+// EffectiveDefaultScope returns the default scope, Namespace if unset.
+func (c *ClientConfig) EffectiveDefaultScope() DefaultScope {
+	if c.Spec.DefaultScope == "" {
+		return DefaultScopeNamespace
+	}
+	return c.Spec.DefaultScope
+}
+
 func init() {
 	SchemeBuilder.Register(&ClientConfig{}, &ClientConfigList{})
 }
@@ -0,0 +1,109 @@
+/*
+Copyright 2024 Syndlex.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// MimirAlertGlobalsSpec defines the desired state of MimirAlertGlobals
+type MimirAlertGlobalsSpec struct {
+	// Tenant references the MimirAlertTenant in the same namespace the global settings are merged into.
+	// Tenants extending this tenant inherit the global settings.
+	// +kubebuilder:validation:Required
+	Tenant TenantReference `json:"tenant"`
+
+	// Global contains the Alertmanager global settings in YAML format,
+	// e.g. smtp_smarthost, slack_api_url and resolve_timeout.
+	// Its keys replace the same keys of the global section of the tenant configuration.
+	// Supports Go text/template syntax with the template data of the tenant
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Global string `json:"global"`
+}
+
+// Condition reasons for MimirAlertGlobals
+const (
+	// ReasonGlobalsMerged indicates the global settings were merged into the tenant configuration
+	ReasonGlobalsMerged = "GlobalsMerged"
+	// ReasonInvalidGlobals indicates the global settings cannot be merged
+	ReasonInvalidGlobals = "InvalidGlobals"
+	// ReasonDuplicateGlobals indicates the global settings set a key already set by other
+	// MimirAlertGlobals of the same tenant
+	ReasonDuplicateGlobals = "DuplicateGlobals"
+)
+
+// MimirAlertGlobalsStatus defines the observed state of MimirAlertGlobals
+type MimirAlertGlobalsStatus struct {
+	// Conditions represent the latest available observations of the MimirAlertGlobals' state
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Tenant",type=string,JSONPath=`.spec.tenant.name`
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+
+// MimirAlertGlobals is the Schema for the mimiralertglobals API.
+// It contributes the global section, e.g. SMTP and chat credentials, to the configuration
+// of a MimirAlertTenant, so it can be owned separately from the routes and receivers.
+type MimirAlertGlobals struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   MimirAlertGlobalsSpec   `json:"spec,omitempty"`
+	Status MimirAlertGlobalsStatus `json:"status,omitempty"`
+}
+
+// SetMergedCondition records that the global settings were merged into the configuration of the tenant.
+// Returns whether the status changed.
+func (globals *MimirAlertGlobals) SetMergedCondition(tenant string) bool {
+	return meta.SetStatusCondition(&globals.Status.Conditions, metav1.Condition{
+		Type:               ConditionTypeReady,
+		Status:             metav1.ConditionTrue,
+		Reason:             ReasonGlobalsMerged,
+		Message:            "Global settings merged into the configuration of MimirAlertTenant " + tenant,
+		ObservedGeneration: globals.Generation,
+	})
+}
+
+// SetRejectedCondition records that the global settings were left out of the tenant configuration.
+// Returns whether the status changed.
+func (globals *MimirAlertGlobals) SetRejectedCondition(reason, message string) bool {
+	return meta.SetStatusCondition(&globals.Status.Conditions, metav1.Condition{
+		Type:               ConditionTypeReady,
+		Status:             metav1.ConditionFalse,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: globals.Generation,
+	})
+}
+
+// +kubebuilder:object:root=true
+
+// MimirAlertGlobalsList contains a list of MimirAlertGlobals
+type MimirAlertGlobalsList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []MimirAlertGlobals `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&MimirAlertGlobals{}, &MimirAlertGlobalsList{})
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2024 Syndlex.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// MimirAlertRouteSpec defines the desired state of MimirAlertRoute
+type MimirAlertRouteSpec struct {
+	// Tenant references the MimirAlertTenant in the same namespace the route is merged into.
+	// Tenants extending this tenant inherit the route.
+	// +kubebuilder:validation:Required
+	Tenant TenantReference `json:"tenant"`
+
+	// Matchers select the alerts of the routing subtree, in Alertmanager matcher syntax,
+	// e.g. team="payments". They are set as matchers of the route.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinItems=1
+	Matchers []string `json:"matchers"`
+
+	// Route contains the Alertmanager route settings of the subtree in YAML format,
+	// e.g. receiver, group_by, continue and child routes. Matchers must not be set here.
+	// Supports Go text/template syntax with the template data of the tenant
+	// +optional
+	Route string `json:"route,omitempty"`
+
+	// Receivers contains the Alertmanager receivers of the subtree as YAML list.
+	// Receiver names must be unique across the tenant configuration and all its routes.
+	// Supports Go text/template syntax with the template data of the tenant
+	// +optional
+	Receivers string `json:"receivers,omitempty"`
+}
+
+// Condition reasons for MimirAlertRoute
+const (
+	// ReasonRouteMerged indicates the route was merged into the tenant configuration
+	ReasonRouteMerged = "RouteMerged"
+	// ReasonInvalidRoute indicates the route or its receivers cannot be merged
+	ReasonInvalidRoute = "InvalidRoute"
+	// ReasonDuplicateReceiver indicates the route defines a receiver name that is already in use
+	ReasonDuplicateReceiver = "DuplicateReceiver"
+)
+
+// MimirAlertRouteStatus defines the observed state of MimirAlertRoute
+type MimirAlertRouteStatus struct {
+	// Conditions represent the latest available observations of the MimirAlertRoute's state
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Tenant",type=string,JSONPath=`.spec.tenant.name`
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+
+// MimirAlertRoute is the Schema for the mimiralertroutes API.
+// It contributes a routing subtree and its receivers to the configuration of a MimirAlertTenant.
+type MimirAlertRoute struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   MimirAlertRouteSpec   `json:"spec,omitempty"`
+	Status MimirAlertRouteStatus `json:"status,omitempty"`
+}
+
+// SetMergedCondition records that the route was merged into the configuration of the tenant.
+// Returns whether the status changed.
+func (route *MimirAlertRoute) SetMergedCondition(tenant string) bool {
+	return meta.SetStatusCondition(&route.Status.Conditions, metav1.Condition{
+		Type:               ConditionTypeReady,
+		Status:             metav1.ConditionTrue,
+		Reason:             ReasonRouteMerged,
+		Message:            "Route merged into the configuration of MimirAlertTenant " + tenant,
+		ObservedGeneration: route.Generation,
+	})
+}
+
+// SetRejectedCondition records that the route was left out of the tenant configuration.
+// Returns whether the status changed.
+func (route *MimirAlertRoute) SetRejectedCondition(reason, message string) bool {
+	return meta.SetStatusCondition(&route.Status.Conditions, metav1.Condition{
+		Type:               ConditionTypeReady,
+		Status:             metav1.ConditionFalse,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: route.Generation,
+	})
+}
+
+// +kubebuilder:object:root=true
+
+// MimirAlertRouteList contains a list of MimirAlertRoute
+type MimirAlertRouteList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []MimirAlertRoute `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&MimirAlertRoute{}, &MimirAlertRouteList{})
+}
@@ -18,8 +18,11 @@ package v1beta1
 
 import (
 	"fmt"
+	"strings"
 
 	"gopkg.in/yaml.v3"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -43,24 +46,171 @@ type SecretDataReference struct {
 	Optional bool `json:"optional,omitempty"`
 }
 
+// ReferenceMergeStrategy defines how keys defined by more than one SecretDataReference are handled
+// +kubebuilder:validation:Enum=OverrideSilently;OverrideWithWarning;Error
+type ReferenceMergeStrategy string
+
+const (
+	// ReferenceMergeOverrideSilently lets later references override earlier ones without reporting
+	ReferenceMergeOverrideSilently ReferenceMergeStrategy = "OverrideSilently"
+	// ReferenceMergeOverrideWithWarning lets later references override earlier ones and reports conflicts in status
+	ReferenceMergeOverrideWithWarning ReferenceMergeStrategy = "OverrideWithWarning"
+	// ReferenceMergeError fails the reconciliation when references define conflicting values
+	ReferenceMergeError ReferenceMergeStrategy = "Error"
+)
+
+// ReferenceConflict records a key for which SecretDataReferences define different values
+type ReferenceConflict struct {
+	// Key is the colliding template variable
+	Key string `json:"key"`
+
+	// Sources lists the references defining the key in merge order, as Kind/Name
+	Sources []string `json:"sources"`
+
+	// WinningSource is the reference whose value is used, as Kind/Name
+	WinningSource string `json:"winningSource"`
+}
+
+// MatcherSyntax selects the parser of the label matchers of an Alertmanager configuration
+// +kubebuilder:validation:Enum=classic;utf8
+type MatcherSyntax string
+
+const (
+	// MatcherSyntaxClassic parses matchers like Alertmanagers in classic mode: label names of
+	// letters, digits, underscores and colons, values quoted or unquoted
+	MatcherSyntaxClassic MatcherSyntax = "classic"
+	// MatcherSyntaxUTF8 parses matchers like Alertmanagers in UTF-8 strict mode: any label name,
+	// quoted if it contains reserved characters such as whitespace, commas, braces or quotes
+	MatcherSyntaxUTF8 MatcherSyntax = "utf8"
+)
+
+// TenantReference references another MimirAlertTenant in the same namespace
+type TenantReference struct {
+	// Name of the MimirAlertTenant
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+}
+
+// AlertmanagerConfigSource selects where the Alertmanager configuration of a tenant is read from
+type AlertmanagerConfigSource struct {
+	// SecretKeyRef selects the key of a Secret in the namespace of the tenant holding the
+	// Alertmanager configuration
+	// +kubebuilder:validation:Required
+	SecretKeyRef corev1.SecretKeySelector `json:"secretKeyRef"`
+}
+
+// TemplateSource references notification template files fetched by the controller from an
+// OCI artifact or a Git repository, so templates can be versioned centrally and shared by tenants
+// +kubebuilder:validation:XValidation:rule="has(self.oci) != has(self.git)",message="exactly one of oci and git must be set"
+type TemplateSource struct {
+	// OCI fetches the layers of an OCI artifact titled with the org.opencontainers.image.title
+	// annotation, as pushed by `oras push`, each layer is a file named by its title
+	// +optional
+	OCI *OCITemplateSource `json:"oci,omitempty"`
+
+	// Git fetches the files of a directory of a Git repository
+	// +optional
+	Git *GitTemplateSource `json:"git,omitempty"`
+
+	// SecretRef names a Secret in the namespace of the tenant with the username and password
+	// keys used to authenticate, e.g. a registry robot account or a Git access token
+	// +optional
+	SecretRef *corev1.LocalObjectReference `json:"secretRef,omitempty"`
+}
+
+// OCITemplateSource references an OCI artifact holding template files
+type OCITemplateSource struct {
+	// Reference of the artifact including its tag or digest,
+	// e.g. registry.example.com/alerting/templates:v1.2.0
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Reference string `json:"reference"`
+
+	// Insecure pulls the artifact over plain HTTP
+	// +optional
+	Insecure bool `json:"insecure,omitempty"`
+}
+
+// GitTemplateSource references a directory of a Git repository holding template files
+type GitTemplateSource struct {
+	// URL of the repository, e.g. https://github.com/example/alerting-templates.git
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	URL string `json:"url"`
+
+	// Ref is the branch or tag to fetch, the default branch if empty
+	// +optional
+	Ref string `json:"ref,omitempty"`
+
+	// Path of the directory holding the template files, the repository root if empty.
+	// Files in subdirectories are ignored
+	// +optional
+	Path string `json:"path,omitempty"`
+}
+
 // MimirAlertTenantSpec defines the desired state of MimirAlertTenant
+// +kubebuilder:validation:XValidation:rule="has(self.extends) || has(self.alertmanagerConfigFrom) || (has(self.alertmanagerConfig) && size(self.alertmanagerConfig) > 0)",message="alertmanagerConfig or alertmanagerConfigFrom is required unless extends is set"
+// +kubebuilder:validation:XValidation:rule="!has(self.alertmanagerConfigFrom) || !has(self.alertmanagerConfig) || size(self.alertmanagerConfig) == 0",message="alertmanagerConfig and alertmanagerConfigFrom are mutually exclusive"
 type MimirAlertTenantSpec struct {
+	// Extends references a base MimirAlertTenant in the same namespace whose configuration
+	// is inherited. The base may extend another tenant itself.
+	// The alertmanagerConfig of this tenant is merged over the base: receivers, time
+	// intervals and mute time intervals replace base entries of the same name or are added,
+	// route children are placed before the base children, inhibit rules and templates are
+	// added and all other settings override the base. TemplateFiles override base files of
+	// the same name, SecretDataReferences are merged after those of the base.
+	// +optional
+	Extends *TenantReference `json:"extends,omitempty"`
+
 	// TemplateFiles contains Alertmanager notification templates
 	// Key is the template name, value is the template content
 	// +optional
 	TemplateFiles map[string]string `json:"templateFiles,omitempty"`
 
+	// TemplateSource fetches template files from an OCI artifact or a Git repository.
+	// TemplateFiles override fetched files of the same name. The files are fetched again
+	// after the controller's --template-source-refresh-interval
+	// +optional
+	TemplateSource *TemplateSource `json:"templateSource,omitempty"`
+
 	// AlertmanagerConfig contains the raw Alertmanager configuration in YAML format
 	// Supports Go text/template syntax with variables from SecretDataReferences
 	// This should include global settings, routes, receivers, etc.
-	// +kubebuilder:validation:Required
+	// Optional for tenants extending a base tenant
+	// +optional
 	AlertmanagerConfig string `json:"alertmanagerConfig"`
 
+	// AlertmanagerConfigFrom reads the alertmanagerConfig from a Secret, so configurations
+	// containing credentials are not stored in the resource. The content is rendered like
+	// alertmanagerConfig and changes of the Secret are synced.
+	// Mutually exclusive with alertmanagerConfig
+	// +optional
+	AlertmanagerConfigFrom *AlertmanagerConfigSource `json:"alertmanagerConfigFrom,omitempty"`
+
 	// SecretDataReferences lists ConfigMaps or Secrets containing template variables
 	// Data from these resources will be available in the alertmanagerConfig template
 	// Multiple references are merged; later references override earlier ones
 	// +optional
 	SecretDataReferences []SecretDataReference `json:"secretDataReferences,omitempty"`
+
+	// ReferenceMergeStrategy defines how keys with different values in multiple
+	// SecretDataReferences are handled
+	// Default: OverrideSilently
+	// +kubebuilder:default=OverrideSilently
+	// +optional
+	ReferenceMergeStrategy ReferenceMergeStrategy `json:"referenceMergeStrategy,omitempty"`
+
+	// SyncTimeout bounds all Mimir API operations of a single reconciliation, e.g. "30s".
+	// Defaults to the controller's --sync-timeout flag
+	// +optional
+	SyncTimeout *metav1.Duration `json:"syncTimeout,omitempty"`
+
+	// MatcherSyntax validates the matchers of the routes and inhibition rules of the rendered
+	// configuration before the push with the parser Mimir's Alertmanager uses: classic, or utf8
+	// if its UTF-8 strict mode is enabled. Matchers are left to Mimir if unset
+	// +optional
+	MatcherSyntax MatcherSyntax `json:"matcherSyntax,omitempty"`
 }
 
 // Condition types for MimirAlertTenant
@@ -69,6 +219,12 @@ const (
 	ConditionTypeConfigValid = "ConfigValid"
 	// ConditionTypeSynced indicates whether the configuration has been synced to Mimir
 	ConditionTypeSynced = "Synced"
+	// ConditionTypePolicyViolation indicates whether the configuration violates the Alertmanager policy
+	ConditionTypePolicyViolation = "PolicyViolation"
+	// ConditionTypeComposed indicates whether the configuration was composed from the extended tenants
+	ConditionTypeComposed = "Composed"
+	// ConditionTypeWouldSync indicates whether a sync would succeed, set by controllers in read-only mode
+	ConditionTypeWouldSync = "WouldSync"
 )
 
 const (
@@ -81,12 +237,49 @@ const (
 	ReasonInvalidTemplate = "InvalidTemplate"
 	// ReasonTemplateDataNotFound Template no data found
 	ReasonTemplateDataNotFound = "TemplateDataNotFound"
+	// ReasonConfigSecretNotFound The Secret key of alertmanagerConfigFrom cannot be read
+	ReasonConfigSecretNotFound = "ConfigSecretNotFound"
+	// ReasonTemplateSourceFailed The template files of templateSource cannot be fetched
+	ReasonTemplateSourceFailed = "TemplateSourceFailed"
+	// ReasonReferenceConflict SecretDataReferences define conflicting values
+	ReasonReferenceConflict = "ReferenceConflict"
+	// ReasonPolicyViolation Configuration violates the Alertmanager policy
+	ReasonPolicyViolation = "PolicyViolation"
+	// ReasonPolicyCompliant Configuration complies with the Alertmanager policy
+	ReasonPolicyCompliant = "PolicyCompliant"
+	// ReasonNoDefaultReceiver The top-level route has no receiver defined by the configuration
+	ReasonNoDefaultReceiver = "NoDefaultReceiver"
+	// ReasonQuotaExceeded The tenant exceeds the number or configuration size quota of the controller
+	ReasonQuotaExceeded = "QuotaExceeded"
+	// ReasonDefaultReceiverInjected A catch-all receiver was injected as default receiver
+	ReasonDefaultReceiverInjected = "DefaultReceiverInjected"
+	// ReasonComposed Configuration was composed from the extended tenants
+	ReasonComposed = "Composed"
+	// ReasonBaseNotFound An extended tenant does not exist
+	ReasonBaseNotFound = "BaseNotFound"
+	// ReasonExtendsCycle The extended tenants form a cycle
+	ReasonExtendsCycle = "ExtendsCycle"
+	// ReasonCompositionFailed The configurations of the extended tenants cannot be merged
+	ReasonCompositionFailed = "CompositionFailed"
+	// ReasonInvalidMatcher A matcher cannot be parsed with the matcherSyntax of the tenant
+	ReasonInvalidMatcher = "InvalidMatcher"
+
+	// ReasonFallbackConfig Mimir serves the fallback configuration instead of the pushed one
+	ReasonFallbackConfig = "FallbackConfig"
+
+	// ReasonInterrupted The sync was interrupted by a controller shutdown and is retried on start
+	ReasonInterrupted = "Interrupted"
 
 	// ReasonConflict API/network reasons (reusing from ClientConfig where possible)
 	ReasonConflict = "Conflict"
 
 	// ReasonSynced Success reasons
 	ReasonSynced = "Synced"
+
+	// ReasonWouldSync A sync would succeed, the controller is read-only
+	ReasonWouldSync = "WouldSync"
+	// ReasonWouldFail A sync would fail, the controller is read-only
+	ReasonWouldFail = "WouldFail"
 )
 
 // Sync status values
@@ -94,6 +287,10 @@ const (
 	SyncStatusSynced  = "Synced"
 	SyncStatusFailed  = "Failed"
 	SyncStatusPending = "Pending"
+	SyncStatusPaused  = "Paused"
+	// SyncStatusFallback reports a pushed configuration that Mimir treats as blank, so the
+	// tenant is served the fallback configuration
+	SyncStatusFallback = "Fallback"
 )
 
 // Configuration validation values
@@ -108,12 +305,17 @@ type MimirAlertTenantStatus struct {
 	// +optional
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
 
+	// ObservedGeneration is the generation of the MimirAlertTenant the status is based upon
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
 	// LastSyncTime is the timestamp of the last successful sync to Mimir
 	// +optional
 	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
 
 	// SyncStatus indicates the current state of the alertmanager configuration
-	// Possible values: "Synced", "Failed", "Pending"
+	// Possible values: "Synced", "Failed", "Pending", "Paused", "Fallback"
+	// +kubebuilder:validation:Enum=Synced;Failed;Pending;Paused;Fallback
 	// +optional
 	SyncStatus string `json:"syncStatus,omitempty"`
 
@@ -122,8 +324,33 @@ type MimirAlertTenantStatus struct {
 	ErrorMessage string `json:"errorMessage,omitempty"`
 
 	// ConfigurationValidation indicates whether the alertmanager config is valid
+	// +kubebuilder:validation:Enum=Valid;Invalid
 	// +optional
 	ConfigurationValidation string `json:"configurationValidation,omitempty"`
+
+	// LastConfigDiff is the redacted unified diff of the last push that changed the
+	// Alertmanager configuration in Mimir, truncated to a few kilobytes
+	// +optional
+	LastConfigDiff string `json:"lastConfigDiff,omitempty"`
+
+	// ReferenceConflicts lists keys overridden between SecretDataReferences
+	// Only reported for the OverrideWithWarning and Error merge strategies
+	// +optional
+	ReferenceConflicts []ReferenceConflict `json:"referenceConflicts,omitempty"`
+
+	// ResolvedTenant is the Mimir tenant (org ID) the configuration was last synced to,
+	// resolved through the tenant aliases and default tenant of the ClientConfig
+	// +optional
+	ResolvedTenant string `json:"resolvedTenant,omitempty"`
+
+	// ResolvedEndpoint is the address and path prefix of the ClientConfig the configuration
+	// was last synced through
+	// +optional
+	ResolvedEndpoint string `json:"resolvedEndpoint,omitempty"`
+
+	// ClientConfigGeneration is the generation of the ClientConfig at the last successful sync
+	// +optional
+	ClientConfigGeneration int64 `json:"clientConfigGeneration,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -185,6 +412,17 @@ func (tenant *MimirAlertTenant) ValidateRenderedConfig(renderedConfig string) er
 	return nil
 }
 
+// SetResolvedTarget records the Mimir tenant and the ClientConfig the configuration was synced
+// to in the status. A nil ClientConfig clears the endpoint and generation.
+func (tenant *MimirAlertTenant) SetResolvedTarget(tenantID string, clientConfig *ClientConfig) {
+	tenant.Status.ResolvedTenant = tenantID
+	tenant.Status.ResolvedEndpoint, tenant.Status.ClientConfigGeneration = "", 0
+	if clientConfig != nil {
+		tenant.Status.ResolvedEndpoint = clientConfig.Endpoint()
+		tenant.Status.ClientConfigGeneration = clientConfig.Generation
+	}
+}
+
 // SetSyncedCondition updates the status to indicate successful sync to Mimir.
 func (tenant *MimirAlertTenant) SetSyncedCondition() {
 	now := metav1.Now()
@@ -194,99 +432,183 @@ func (tenant *MimirAlertTenant) SetSyncedCondition() {
 	tenant.Status.ConfigurationValidation = ConfigValidationValid
 
 	tenant.setCondition(metav1.Condition{
-		Type:               ConditionTypeReady,
-		Status:             metav1.ConditionTrue,
-		Reason:             ReasonSynced,
-		Message:            "Alertmanager configuration successfully synced to Mimir",
-		LastTransitionTime: now,
+		Type:    ConditionTypeReady,
+		Status:  metav1.ConditionTrue,
+		Reason:  ReasonSynced,
+		Message: "Alertmanager configuration successfully synced to Mimir",
 	})
 
 	tenant.setCondition(metav1.Condition{
-		Type:               ConditionTypeConfigValid,
-		Status:             metav1.ConditionTrue,
-		Reason:             ReasonConfigValidated,
-		Message:            "Alertmanager configuration is valid",
-		LastTransitionTime: now,
+		Type:    ConditionTypeConfigValid,
+		Status:  metav1.ConditionTrue,
+		Reason:  ReasonConfigValidated,
+		Message: "Alertmanager configuration is valid",
 	})
 
 	tenant.setCondition(metav1.Condition{
-		Type:               ConditionTypeSynced,
-		Status:             metav1.ConditionTrue,
-		Reason:             ReasonSynced,
-		Message:            "Configuration synced to Mimir",
-		LastTransitionTime: now,
+		Type:    ConditionTypeSynced,
+		Status:  metav1.ConditionTrue,
+		Reason:  ReasonSynced,
+		Message: "Configuration synced to Mimir",
+	})
+}
+
+// SetFallbackCondition updates the status to indicate a configuration that was synced to Mimir,
+// but is blank, so Mimir serves its fallback configuration to the tenant. This usually means
+// the onboarding of the tenant is incomplete.
+func (tenant *MimirAlertTenant) SetFallbackCondition(tenantID string) {
+	tenant.SetSyncedCondition()
+	tenant.Status.SyncStatus = SyncStatusFallback
+
+	tenant.setCondition(metav1.Condition{
+		Type:   ConditionTypeReady,
+		Status: metav1.ConditionFalse,
+		Reason: ReasonFallbackConfig,
+		Message: fmt.Sprintf("Mimir serves the fallback Alertmanager configuration to tenant %s, "+
+			"the synced configuration is blank", tenantID),
 	})
 }
 
 // SetFailedCondition updates the status to indicate a failed sync to Mimir.
 func (tenant *MimirAlertTenant) SetFailedCondition(reason, message string) {
-	now := metav1.Now()
 	tenant.Status.SyncStatus = SyncStatusFailed
 	tenant.Status.ErrorMessage = message
 
 	tenant.setCondition(metav1.Condition{
-		Type:               ConditionTypeReady,
-		Status:             metav1.ConditionFalse,
-		Reason:             reason,
-		Message:            message,
-		LastTransitionTime: now,
+		Type:    ConditionTypeReady,
+		Status:  metav1.ConditionFalse,
+		Reason:  reason,
+		Message: message,
+	})
+
+	tenant.setCondition(metav1.Condition{
+		Type:    ConditionTypeSynced,
+		Status:  metav1.ConditionFalse,
+		Reason:  reason,
+		Message: message,
+	})
+}
+
+// SetPendingCondition updates the status to indicate a sync to Mimir that did not complete and
+// is retried, e.g. because it was interrupted by a controller shutdown.
+func (tenant *MimirAlertTenant) SetPendingCondition(reason, message string) {
+	tenant.Status.SyncStatus = SyncStatusPending
+	tenant.Status.ErrorMessage = message
+
+	tenant.setCondition(metav1.Condition{
+		Type:    ConditionTypeReady,
+		Status:  metav1.ConditionFalse,
+		Reason:  reason,
+		Message: message,
 	})
 
 	tenant.setCondition(metav1.Condition{
-		Type:               ConditionTypeSynced,
-		Status:             metav1.ConditionFalse,
-		Reason:             reason,
-		Message:            message,
-		LastTransitionTime: now,
+		Type:    ConditionTypeSynced,
+		Status:  metav1.ConditionFalse,
+		Reason:  reason,
+		Message: message,
 	})
 }
 
 // SetConfigInvalidCondition updates the status to indicate invalid configuration.
 func (tenant *MimirAlertTenant) SetConfigInvalidCondition(reason, message string) {
-	now := metav1.Now()
 	tenant.Status.SyncStatus = SyncStatusFailed
 	tenant.Status.ErrorMessage = message
 	tenant.Status.ConfigurationValidation = ConfigValidationInvalid
 
 	tenant.setCondition(metav1.Condition{
-		Type:               ConditionTypeReady,
-		Status:             metav1.ConditionFalse,
-		Reason:             reason,
-		Message:            message,
-		LastTransitionTime: now,
+		Type:    ConditionTypeReady,
+		Status:  metav1.ConditionFalse,
+		Reason:  reason,
+		Message: message,
 	})
 
 	tenant.setCondition(metav1.Condition{
-		Type:               ConditionTypeConfigValid,
-		Status:             metav1.ConditionFalse,
-		Reason:             reason,
-		Message:            message,
-		LastTransitionTime: now,
+		Type:    ConditionTypeConfigValid,
+		Status:  metav1.ConditionFalse,
+		Reason:  reason,
+		Message: message,
 	})
 
 	tenant.setCondition(metav1.Condition{
-		Type:               ConditionTypeSynced,
-		Status:             metav1.ConditionFalse,
-		Reason:             reason,
-		Message:            "Cannot sync invalid configuration",
-		LastTransitionTime: now,
+		Type:    ConditionTypeSynced,
+		Status:  metav1.ConditionFalse,
+		Reason:  reason,
+		Message: "Cannot sync invalid configuration",
 	})
 }
 
-// setCondition sets or updates a condition in the status.
-// If a condition with the same type exists, it updates it; otherwise, it appends the new condition.
-func (tenant *MimirAlertTenant) setCondition(newCondition metav1.Condition) {
-	existingConditions := tenant.Status.Conditions
-	for i, condition := range existingConditions {
-		if condition.Type == newCondition.Type {
-			// Update existing condition
-			existingConditions[i] = newCondition
-			tenant.Status.Conditions = existingConditions
-			return
-		}
+// SetPausedCondition updates the status to indicate that the configuration was
+// validated but is not synced to Mimir because the resource is paused.
+func (tenant *MimirAlertTenant) SetPausedCondition() {
+	tenant.Status.SyncStatus = SyncStatusPaused
+	tenant.Status.ErrorMessage = ""
+	tenant.Status.ConfigurationValidation = ConfigValidationValid
+
+	tenant.setCondition(metav1.Condition{
+		Type:    ConditionTypeConfigValid,
+		Status:  metav1.ConditionTrue,
+		Reason:  ReasonConfigValidated,
+		Message: "Alertmanager configuration is valid",
+	})
+
+	tenant.setCondition(metav1.Condition{
+		Type:    ConditionTypePaused,
+		Status:  metav1.ConditionTrue,
+		Reason:  ReasonPaused,
+		Message: "Remote changes are paused via annotation",
+	})
+}
+
+// SetPolicyViolationCondition records the Alertmanager policy violations of the configuration.
+// The condition is True and enumerates the violations if there are any.
+func (tenant *MimirAlertTenant) SetPolicyViolationCondition(violations []string) {
+	condition := metav1.Condition{
+		Type:    ConditionTypePolicyViolation,
+		Status:  metav1.ConditionFalse,
+		Reason:  ReasonPolicyCompliant,
+		Message: "Alertmanager configuration complies with the policy",
 	}
-	// Append new condition
-	tenant.Status.Conditions = append(existingConditions, newCondition)
+	if len(violations) > 0 {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = ReasonPolicyViolation
+		condition.Message = "Policy violations: " + strings.Join(violations, "; ")
+	}
+	tenant.setCondition(condition)
+}
+
+// SetComposedCondition records that the configuration was composed from the given base
+// tenants, ordered from the root base. The condition is removed for tenants without bases.
+func (tenant *MimirAlertTenant) SetComposedCondition(bases []string) {
+	if len(bases) == 0 {
+		meta.RemoveStatusCondition(&tenant.Status.Conditions, ConditionTypeComposed)
+		return
+	}
+	tenant.setCondition(metav1.Condition{
+		Type:    ConditionTypeComposed,
+		Status:  metav1.ConditionTrue,
+		Reason:  ReasonComposed,
+		Message: "Composed from base tenants " + strings.Join(bases, " -> "),
+	})
+}
+
+// SetCompositionFailedCondition updates the status to indicate that the configuration
+// cannot be composed from the extended tenants.
+func (tenant *MimirAlertTenant) SetCompositionFailedCondition(reason, message string) {
+	tenant.setCondition(metav1.Condition{
+		Type:    ConditionTypeComposed,
+		Status:  metav1.ConditionFalse,
+		Reason:  reason,
+		Message: message,
+	})
+	tenant.SetConfigInvalidCondition(reason, message)
+}
+
+// setCondition sets or updates a condition in the status for the tenant's generation.
+// The LastTransitionTime is only changed if the status of the condition changes.
+func (tenant *MimirAlertTenant) setCondition(newCondition metav1.Condition) {
+	newCondition.ObservedGeneration = tenant.Generation
+	meta.SetStatusCondition(&tenant.Status.Conditions, newCondition)
 }
 
 // +kubebuilder:object:root=true
@@ -0,0 +1,117 @@
+/*
+Copyright 2024 Syndlex.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// MimirInhibitRuleSpec defines the desired state of MimirInhibitRule
+type MimirInhibitRuleSpec struct {
+	// Tenant references the MimirAlertTenant in the same namespace the inhibition rule is merged into.
+	// Tenants extending this tenant inherit the inhibition rule.
+	// +kubebuilder:validation:Required
+	Tenant TenantReference `json:"tenant"`
+
+	// SourceMatchers select the alerts that inhibit others while firing, in Alertmanager matcher
+	// syntax, e.g. alertname="ClusterDown"
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinItems=1
+	SourceMatchers []string `json:"sourceMatchers"`
+
+	// TargetMatchers select the alerts that are inhibited, in Alertmanager matcher syntax,
+	// e.g. severity="warning"
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinItems=1
+	TargetMatchers []string `json:"targetMatchers"`
+
+	// Equal are the labels that must have the same value in the source and target alerts
+	// for the inhibition to take effect, e.g. cluster
+	// +optional
+	Equal []string `json:"equal,omitempty"`
+}
+
+// Condition reasons for MimirInhibitRule
+const (
+	// ReasonInhibitRuleMerged indicates the inhibition rule was merged into the tenant configuration
+	ReasonInhibitRuleMerged = "InhibitRuleMerged"
+	// ReasonInvalidInhibitRule indicates the inhibition rule cannot be merged
+	ReasonInvalidInhibitRule = "InvalidInhibitRule"
+	// ReasonDuplicateInhibitRule indicates the same inhibition rule is already in the configuration
+	ReasonDuplicateInhibitRule = "DuplicateInhibitRule"
+)
+
+// MimirInhibitRuleStatus defines the observed state of MimirInhibitRule
+type MimirInhibitRuleStatus struct {
+	// Conditions represent the latest available observations of the MimirInhibitRule's state
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Tenant",type=string,JSONPath=`.spec.tenant.name`
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+
+// MimirInhibitRule is the Schema for the mimirinhibitrules API.
+// It contributes an inhibition rule to the inhibit_rules of the configuration of a
+// MimirAlertTenant, so platform teams can declare inhibitions separately from the routing.
+type MimirInhibitRule struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   MimirInhibitRuleSpec   `json:"spec,omitempty"`
+	Status MimirInhibitRuleStatus `json:"status,omitempty"`
+}
+
+// SetMergedCondition records that the inhibition rule was merged into the configuration of the tenant.
+// Returns whether the status changed.
+func (rule *MimirInhibitRule) SetMergedCondition(tenant string) bool {
+	return meta.SetStatusCondition(&rule.Status.Conditions, metav1.Condition{
+		Type:               ConditionTypeReady,
+		Status:             metav1.ConditionTrue,
+		Reason:             ReasonInhibitRuleMerged,
+		Message:            "Inhibition rule merged into the configuration of MimirAlertTenant " + tenant,
+		ObservedGeneration: rule.Generation,
+	})
+}
+
+// SetRejectedCondition records that the inhibition rule was left out of the tenant configuration.
+// Returns whether the status changed.
+func (rule *MimirInhibitRule) SetRejectedCondition(reason, message string) bool {
+	return meta.SetStatusCondition(&rule.Status.Conditions, metav1.Condition{
+		Type:               ConditionTypeReady,
+		Status:             metav1.ConditionFalse,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: rule.Generation,
+	})
+}
+
+// +kubebuilder:object:root=true
+
+// MimirInhibitRuleList contains a list of MimirInhibitRule
+type MimirInhibitRuleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []MimirInhibitRule `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&MimirInhibitRule{}, &MimirInhibitRuleList{})
+}
@@ -0,0 +1,163 @@
+/*
+Copyright 2024 Syndlex.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// MimirMuteTimingSpec defines the desired state of MimirMuteTiming
+type MimirMuteTimingSpec struct {
+	// Tenant references the MimirAlertTenant in the same namespace the time interval is merged into.
+	// Tenants extending this tenant inherit the time interval.
+	// +kubebuilder:validation:Required
+	Tenant TenantReference `json:"tenant"`
+
+	// Name is the name of the time interval in the Alertmanager configuration, which routes
+	// reference in mute_time_intervals or active_time_intervals. Defaults to the name of the
+	// MimirMuteTiming. Names must be unique across the tenant configuration and its MimirMuteTimings
+	// +kubebuilder:validation:MaxLength=253
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// TimeIntervals are the windows of the time interval; it is active while any of them is
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinItems=1
+	TimeIntervals []TimeInterval `json:"timeIntervals"`
+}
+
+// TimeInterval is a window of an Alertmanager time interval. All its fields must match for it
+// to be active, unset fields match always.
+type TimeInterval struct {
+	// Times are the time ranges of the day, within the location
+	// +optional
+	Times []TimeRange `json:"times,omitempty"`
+
+	// Weekdays are days of the week or inclusive ranges of them, e.g. "monday:friday" or "saturday"
+	// +optional
+	Weekdays []string `json:"weekdays,omitempty"`
+
+	// DaysOfMonth are days of the month or inclusive ranges of them, e.g. "1:5"; negative
+	// days count from the end of the month, e.g. "-3:-1"
+	// +optional
+	DaysOfMonth []string `json:"daysOfMonth,omitempty"`
+
+	// Months are months by number or name or inclusive ranges of them, e.g. "1:3" or "december"
+	// +optional
+	Months []string `json:"months,omitempty"`
+
+	// Years are years or inclusive ranges of them, e.g. "2025:2026"
+	// +optional
+	Years []string `json:"years,omitempty"`
+
+	// Location is the IANA time zone the window is evaluated in, e.g. "Europe/Berlin"; UTC if empty
+	// +optional
+	Location string `json:"location,omitempty"`
+}
+
+// TimeRange is a time range of the day, starting inclusive and ending exclusive
+type TimeRange struct {
+	// StartTime is the start of the range in 24 hour HH:MM format
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^([01][0-9]|2[0-4]):[0-5][0-9]$`
+	StartTime string `json:"startTime"`
+
+	// EndTime is the end of the range in 24 hour HH:MM format, up to 24:00
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^([01][0-9]|2[0-4]):[0-5][0-9]$`
+	EndTime string `json:"endTime"`
+}
+
+// Condition reasons for MimirMuteTiming
+const (
+	// ReasonMuteTimingMerged indicates the time interval was merged into the tenant configuration
+	ReasonMuteTimingMerged = "MuteTimingMerged"
+	// ReasonInvalidMuteTiming indicates the time interval cannot be merged
+	ReasonInvalidMuteTiming = "InvalidMuteTiming"
+	// ReasonDuplicateMuteTiming indicates the time interval name is already in use
+	ReasonDuplicateMuteTiming = "DuplicateMuteTiming"
+)
+
+// MimirMuteTimingStatus defines the observed state of MimirMuteTiming
+type MimirMuteTimingStatus struct {
+	// Conditions represent the latest available observations of the MimirMuteTiming's state
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Tenant",type=string,JSONPath=`.spec.tenant.name`
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+
+// MimirMuteTiming is the Schema for the mimirmutetimings API.
+// It contributes a named time interval, e.g. an on-call mute window, to the time_intervals of
+// the configuration of a MimirAlertTenant, so routes can mute or activate notifications by it.
+type MimirMuteTiming struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   MimirMuteTimingSpec   `json:"spec,omitempty"`
+	Status MimirMuteTimingStatus `json:"status,omitempty"`
+}
+
+// IntervalName returns the name of the time interval in the Alertmanager configuration:
+// spec.name, or the name of the MimirMuteTiming if unset.
+func (timing *MimirMuteTiming) IntervalName() string {
+	if timing.Spec.Name != "" {
+		return timing.Spec.Name
+	}
+	return timing.Name
+}
+
+// SetMergedCondition records that the time interval was merged into the configuration of the tenant.
+// Returns whether the status changed.
+func (timing *MimirMuteTiming) SetMergedCondition(tenant string) bool {
+	return meta.SetStatusCondition(&timing.Status.Conditions, metav1.Condition{
+		Type:               ConditionTypeReady,
+		Status:             metav1.ConditionTrue,
+		Reason:             ReasonMuteTimingMerged,
+		Message:            "Time interval " + timing.IntervalName() + " merged into the configuration of MimirAlertTenant " + tenant,
+		ObservedGeneration: timing.Generation,
+	})
+}
+
+// SetRejectedCondition records that the time interval was left out of the tenant configuration.
+// Returns whether the status changed.
+func (timing *MimirMuteTiming) SetRejectedCondition(reason, message string) bool {
+	return meta.SetStatusCondition(&timing.Status.Conditions, metav1.Condition{
+		Type:               ConditionTypeReady,
+		Status:             metav1.ConditionFalse,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: timing.Generation,
+	})
+}
+
+// +kubebuilder:object:root=true
+
+// MimirMuteTimingList contains a list of MimirMuteTiming
+type MimirMuteTimingList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []MimirMuteTiming `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&MimirMuteTiming{}, &MimirMuteTimingList{})
+}
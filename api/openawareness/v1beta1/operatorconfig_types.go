@@ -0,0 +1,144 @@
+/*
+Copyright 2024 Syndlex.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// OperatorConfigName is the name of the OperatorConfig read by the controllers
+const OperatorConfigName = "cluster"
+
+// OperatorConfigSpec overrides the controller settings given as command-line flags.
+// Unset fields keep the value of their flag
+type OperatorConfigSpec struct {
+	// SyncTimeout is the default timeout of the Mimir API operations of a single reconciliation,
+	// overriding --sync-timeout. 0 disables the timeout
+	// +optional
+	SyncTimeout *metav1.Duration `json:"syncTimeout,omitempty"`
+
+	// ResyncInterval is the interval in which synced PrometheusRules and MimirAlertTenants are
+	// pushed again, correcting changes made directly in Mimir. Unset or 0 disables the resync
+	// +optional
+	ResyncInterval *metav1.Duration `json:"resyncInterval,omitempty"`
+
+	// Retry controls the delay of retries of failed reconciliations
+	// +optional
+	Retry *RetrySpec `json:"retry,omitempty"`
+
+	// AlertmanagerPolicy overrides the --alertmanager-policy-* flags
+	// +optional
+	AlertmanagerPolicy *AlertmanagerPolicySpec `json:"alertmanagerPolicy,omitempty"`
+
+	// RulePolicy overrides the --rule-policy-* flags
+	// +optional
+	RulePolicy *RulePolicySpec `json:"rulePolicy,omitempty"`
+
+	// RuleSelector limits the synced PrometheusRules to those with matching labels. Rules
+	// synced before they stopped matching keep their rule groups in Mimir until deleted.
+	// Unset selects all PrometheusRules
+	// +optional
+	RuleSelector *metav1.LabelSelector `json:"ruleSelector,omitempty"`
+}
+
+// RetrySpec defines the exponential backoff of failed reconciliations
+type RetrySpec struct {
+	// BaseDelay is the delay of the first retry, doubled for every further failure
+	// +optional
+	BaseDelay *metav1.Duration `json:"baseDelay,omitempty"`
+
+	// MaxDelay is the longest delay between two retries
+	// +optional
+	MaxDelay *metav1.Duration `json:"maxDelay,omitempty"`
+}
+
+// AlertmanagerPolicySpec defines the policy rendered Alertmanager configurations are checked against
+type AlertmanagerPolicySpec struct {
+	// Mode is "warn" to report violations, "block" to also stop the sync or "disabled"
+	// +kubebuilder:validation:Enum=disabled;warn;block
+	// +optional
+	Mode string `json:"mode,omitempty"`
+
+	// MinRepeatInterval is the smallest repeat_interval allowed, 0 disables the check
+	// +optional
+	MinRepeatInterval *metav1.Duration `json:"minRepeatInterval,omitempty"`
+}
+
+// RulePolicySpec defines the policy the alerting rules of PrometheusRules are checked against
+type RulePolicySpec struct {
+	// Mode is "warn" to report violations, "block" to also stop the push or "disabled"
+	// +kubebuilder:validation:Enum=disabled;warn;block
+	// +optional
+	Mode string `json:"mode,omitempty"`
+
+	// RequiredLabels are the labels every alerting rule must have. An empty list requires none
+	// +optional
+	RequiredLabels []string `json:"requiredLabels,omitempty"`
+
+	// RequiredAnnotations are the annotations every alerting rule must have. An empty list
+	// requires none
+	// +optional
+	RequiredAnnotations []string `json:"requiredAnnotations,omitempty"`
+}
+
+// Condition reasons for OperatorConfig
+const (
+	// ReasonSettingsApplied indicates the controllers use the settings of the OperatorConfig
+	ReasonSettingsApplied = "SettingsApplied"
+	// ReasonInvalidSettings indicates the OperatorConfig is ignored because of invalid settings
+	ReasonInvalidSettings = "InvalidSettings"
+	// ReasonIgnoredName indicates the OperatorConfig is ignored because it is not named
+	// OperatorConfigName
+	ReasonIgnoredName = "IgnoredName"
+)
+
+// OperatorConfigStatus defines the observed state of OperatorConfig
+type OperatorConfigStatus struct {
+	// Conditions represent the latest available observations of the OperatorConfig's state
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Reason",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].reason`
+
+// OperatorConfig is the Schema for the operatorconfigs API.
+// The OperatorConfig named "cluster" changes the settings of the controllers at runtime,
+// without restarting them. Settings it does not set keep the value of their command-line flag.
+type OperatorConfig struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   OperatorConfigSpec   `json:"spec,omitempty"`
+	Status OperatorConfigStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// OperatorConfigList contains a list of OperatorConfig
+type OperatorConfigList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OperatorConfig `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&OperatorConfig{}, &OperatorConfigList{})
+}
@@ -0,0 +1,190 @@
+/*
+Copyright 2024 Syndlex.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PrometheusRuleSyncStatus summarizes the last sync of the rule groups of a PrometheusRule
+type PrometheusRuleSyncStatus struct {
+	// ObservedGeneration is the generation of the PrometheusRule the summary is based upon
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// GroupsDesired is the number of rule groups of the PrometheusRule over all its tenants.
+	// A group mixing recording and alerting rules pushed to different tenants counts twice
+	// +optional
+	GroupsDesired int32 `json:"groupsDesired"`
+
+	// GroupsSynced is the number of rule groups whose current version is synced to Mimir
+	// +optional
+	GroupsSynced int32 `json:"groupsSynced"`
+
+	// GroupsFailed is the number of rule groups that could not be synced to Mimir
+	// +optional
+	GroupsFailed int32 `json:"groupsFailed"`
+
+	// FailedGroups lists the rule groups whose push to Mimir failed in the last sync in the form
+	// tenant/group, at most 20. Groups not pushed at all, e.g. invalid ones, are not listed
+	// +optional
+	// +kubebuilder:validation:MaxItems=20
+	FailedGroups []string `json:"failedGroups,omitempty"`
+
+	// FailureReason is the reason of the earliest failure of the last sync, empty if it succeeded
+	// +optional
+	FailureReason string `json:"failureReason,omitempty"`
+
+	// FailureMessage describes the earliest failure of the last sync
+	// +optional
+	FailureMessage string `json:"failureMessage,omitempty"`
+
+	// ResolvedTenants are the Mimir tenants (org IDs) the rule groups were last synced to,
+	// resolved through the tenant aliases and default tenant of the ClientConfig
+	// +optional
+	ResolvedTenants []string `json:"resolvedTenants,omitempty"`
+
+	// ResolvedEndpoint is the address and path prefix of the ClientConfig the rule groups were
+	// last synced through
+	// +optional
+	ResolvedEndpoint string `json:"resolvedEndpoint,omitempty"`
+
+	// ClientConfigGeneration is the generation of the ClientConfig at the last successful sync
+	// +optional
+	ClientConfigGeneration int64 `json:"clientConfigGeneration,omitempty"`
+
+	// LastEvaluation is the oldest last evaluation of the synced rule groups by the ruler over all
+	// tenants at the last activation check, see ConditionTypeActive. Set with activation checks
+	// enabled while all groups are evaluated
+	// +optional
+	LastEvaluation *metav1.Time `json:"lastEvaluation,omitempty"`
+
+	// Parity compares the series produced by the rules in Mimir and in the in-cluster Prometheus
+	// while the PrometheusRule is written to both, see the dual-write annotation
+	// +optional
+	Parity *EvaluationParity `json:"parity,omitempty"`
+
+	// Conditions represent the latest available observations of the sync, see ConditionTypeSynced
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// EvaluationParity is the result of the last comparison of the series produced by the rules of a
+// PrometheusRule in Mimir and in the in-cluster Prometheus
+type EvaluationParity struct {
+	// CheckedAt is the time of the comparison
+	CheckedAt metav1.Time `json:"checkedAt"`
+
+	// RulesCompared is the number of rules whose series were counted in both
+	RulesCompared int32 `json:"rulesCompared"`
+
+	// RulesMatching is the number of compared rules producing the same number of series in both
+	RulesMatching int32 `json:"rulesMatching"`
+
+	// Mismatches lists the rules producing a different number of series, at most 20
+	// +optional
+	// +kubebuilder:validation:MaxItems=20
+	Mismatches []RuleParity `json:"mismatches,omitempty"`
+}
+
+// RuleParity is the number of series a rule produces in Mimir and in the in-cluster Prometheus
+type RuleParity struct {
+	// Tenant is the Mimir tenant the rule is evaluated in
+	Tenant string `json:"tenant"`
+
+	// Group is the name of the rule group
+	Group string `json:"group"`
+
+	// Rule is the record or alert name of the rule
+	Rule string `json:"rule"`
+
+	// PrometheusSeries is the number of series produced in the in-cluster Prometheus
+	PrometheusSeries int64 `json:"prometheusSeries"`
+
+	// MimirSeries is the number of series produced in Mimir
+	MimirSeries int64 `json:"mimirSeries"`
+}
+
+// Condition types for PrometheusRuleSync
+const (
+	// ConditionTypePartiallySynced indicates whether some rule groups of the PrometheusRule were
+	// synced to Mimir while others failed
+	ConditionTypePartiallySynced = "PartiallySynced"
+	// ConditionTypeEvaluationParity indicates whether the rules produce the same number of series
+	// in Mimir and in the in-cluster Prometheus, set for dual-written PrometheusRules
+	ConditionTypeEvaluationParity = "EvaluationParity"
+	// ConditionTypeActive indicates whether the ruler evaluates all synced rule groups, set with
+	// activation checks enabled
+	ConditionTypeActive = "Active"
+)
+
+// Condition reasons for PrometheusRuleSync
+const (
+	// ReasonGroupsFailed indicates that some rule groups failed to sync while the others synced
+	ReasonGroupsFailed = "GroupsFailed"
+	// ReasonParityMatched indicates that every rule produces the same number of series in both
+	ReasonParityMatched = "ParityMatched"
+	// ReasonParityMismatch indicates that some rules produce a different number of series
+	ReasonParityMismatch = "ParityMismatch"
+	// ReasonParityUnknown indicates that the series could not be counted in Mimir or Prometheus
+	ReasonParityUnknown = "ParityUnknown"
+	// ReasonRuleGroupsActive indicates that the ruler evaluates all synced rule groups
+	ReasonRuleGroupsActive = "RuleGroupsActive"
+	// ReasonRuleGroupsInactive indicates that some synced rule groups are not loaded, not evaluated
+	// yet or contain unhealthy rules
+	ReasonRuleGroupsInactive = "RuleGroupsInactive"
+	// ReasonActivationUnknown indicates that the ruler state could not be read
+	ReasonActivationUnknown = "ActivationUnknown"
+	// ReasonInvalidRuleGroups indicates that the rule groups could not be converted or are invalid
+	ReasonInvalidRuleGroups = "InvalidRuleGroups"
+	// ReasonRuleGroupsBlocked indicates that the rule policy refused the rule groups
+	ReasonRuleGroupsBlocked = "RuleGroupsBlocked"
+	// ReasonRuleIntervalTooShort indicates that rule group intervals are below the minimum of the
+	// ClientConfig
+	ReasonRuleIntervalTooShort = "RuleIntervalTooShort"
+)
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Desired",type=integer,JSONPath=`.status.groupsDesired`
+// +kubebuilder:printcolumn:name="Synced",type=integer,JSONPath=`.status.groupsSynced`
+// +kubebuilder:printcolumn:name="Failed",type=integer,JSONPath=`.status.groupsFailed`
+// +kubebuilder:printcolumn:name="Reason",type=string,JSONPath=`.status.failureReason`
+
+// PrometheusRuleSync is the Schema for the prometheusrulesyncs API.
+// PrometheusRules have no status of their own, so the controller keeps a PrometheusRuleSync of
+// the same name and namespace per PrometheusRule, owned by it, that summarizes the sync of its
+// rule groups to Mimir, e.g. for kube-state-metrics custom resource state metrics.
+type PrometheusRuleSync struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Status PrometheusRuleSyncStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// PrometheusRuleSyncList contains a list of PrometheusRuleSync
+type PrometheusRuleSyncList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []PrometheusRuleSync `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&PrometheusRuleSync{}, &PrometheusRuleSyncList{})
+}
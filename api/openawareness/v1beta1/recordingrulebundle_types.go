@@ -0,0 +1,109 @@
+/*
+Copyright 2024 Syndlex.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RecordingRuleLibrary holds the recording rule groups of a bundle, inline or read from a ConfigMap
+// +kubebuilder:validation:XValidation:rule="has(self.groups) != has(self.configMapRef)",message="exactly one of groups or configMapRef must be set"
+type RecordingRuleLibrary struct {
+	// Version identifies the revision of the library, it is recorded on the generated
+	// PrometheusRules and in the status, e.g. "1.4.0"
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MaxLength=63
+	// +kubebuilder:validation:Pattern=`^[A-Za-z0-9]([A-Za-z0-9._-]*[A-Za-z0-9])?$`
+	Version string `json:"version"`
+
+	// Groups contains PrometheusRule rule groups of recording rules in YAML format
+	// +optional
+	Groups string `json:"groups,omitempty"`
+
+	// ConfigMapRef selects the key of a ConfigMap in the namespace of the bundle holding the
+	// rule groups in YAML format. Changes of the ConfigMap are rolled out to all tenants
+	// +optional
+	ConfigMapRef *corev1.ConfigMapKeySelector `json:"configMapRef,omitempty"`
+}
+
+// RecordingRuleBundleSpec defines the desired state of RecordingRuleBundle
+type RecordingRuleBundleSpec struct {
+	// Library is the versioned library of recording rules generated for every selected tenant
+	// +kubebuilder:validation:Required
+	Library RecordingRuleLibrary `json:"library"`
+
+	// TenantSelector selects the MimirAlertTenants in all namespaces the recording rules are
+	// generated for. An empty selector selects all MimirAlertTenants
+	// +kubebuilder:validation:Required
+	TenantSelector metav1.LabelSelector `json:"tenantSelector"`
+}
+
+// Condition reasons for RecordingRuleBundle
+const (
+	// ReasonLibraryNotFound indicates the ConfigMap or key holding the library does not exist
+	ReasonLibraryNotFound = "LibraryNotFound"
+	// ReasonInvalidLibrary indicates the library cannot be parsed or contains alerting rules
+	ReasonInvalidLibrary = "InvalidLibrary"
+	// ReasonInvalidTenantSelector indicates the tenant selector cannot be parsed
+	ReasonInvalidTenantSelector = "InvalidTenantSelector"
+)
+
+// RecordingRuleBundleStatus defines the observed state of RecordingRuleBundle
+type RecordingRuleBundleStatus struct {
+	// Conditions represent the latest available observations of the RecordingRuleBundle's state
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// Version is the library version last generated for the selected tenants
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// Tenants is the number of MimirAlertTenants the recording rules are generated for
+	// +optional
+	Tenants int32 `json:"tenants,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Version",type=string,JSONPath=`.spec.library.version`
+// +kubebuilder:printcolumn:name="Tenants",type=integer,JSONPath=`.status.tenants`
+// +kubebuilder:validation:XValidation:rule="size(self.metadata.name) <= 63",message="the name of a RecordingRuleBundle must be at most 63 characters"
+
+// RecordingRuleBundle is the Schema for the recordingrulebundles API.
+// It expands a library of platform-standard recording rules into a PrometheusRule per selected
+// MimirAlertTenant, in the namespace of the tenant and synced to its client and tenant.
+type RecordingRuleBundle struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   RecordingRuleBundleSpec   `json:"spec,omitempty"`
+	Status RecordingRuleBundleStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// RecordingRuleBundleList contains a list of RecordingRuleBundle
+type RecordingRuleBundleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []RecordingRuleBundle `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&RecordingRuleBundle{}, &RecordingRuleBundleList{})
+}
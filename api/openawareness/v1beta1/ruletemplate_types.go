@@ -0,0 +1,80 @@
+/*
+Copyright 2024 Syndlex.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RuleTemplateParameter declares a parameter that can be used in a RuleTemplate
+type RuleTemplateParameter struct {
+	// Name of the parameter, referenced as [[ .name ]] in the groups template
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+
+	// Description of the parameter for template users
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// Default value used when an instance does not provide the parameter
+	// +optional
+	Default string `json:"default,omitempty"`
+
+	// Required parameters must be provided by every instance
+	// Default: false
+	// +optional
+	Required bool `json:"required,omitempty"`
+}
+
+// RuleTemplateSpec defines the desired state of RuleTemplate
+type RuleTemplateSpec struct {
+	// Description of the alerts provided by this template
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// Parameters declares the parameters of the template with their defaults
+	// +optional
+	Parameters []RuleTemplateParameter `json:"parameters,omitempty"`
+
+	// Groups contains PrometheusRule rule groups in YAML format
+	// Supports Go text/template syntax with [[ ]] delimiters for parameters
+	// +kubebuilder:validation:Required
+	Groups string `json:"groups"`
+}
+
+// +kubebuilder:object:root=true
+
+// RuleTemplate is the Schema for the ruletemplates API
+type RuleTemplate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec RuleTemplateSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// RuleTemplateList contains a list of RuleTemplate
+type RuleTemplateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []RuleTemplate `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&RuleTemplate{}, &RuleTemplateList{})
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2024 Syndlex.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RuleTemplateInstanceSpec defines the desired state of RuleTemplateInstance
+type RuleTemplateInstanceSpec struct {
+	// TemplateRef is the name of the RuleTemplate in the same namespace
+	// +kubebuilder:validation:Required
+	TemplateRef string `json:"templateRef"`
+
+	// Values contains parameter values, overriding values from SecretDataReferences
+	// +optional
+	Values map[string]string `json:"values,omitempty"`
+
+	// SecretDataReferences lists ConfigMaps or Secrets containing parameter values
+	// Multiple references are merged; later references override earlier ones
+	// +optional
+	SecretDataReferences []SecretDataReference `json:"secretDataReferences,omitempty"`
+}
+
+// Condition reasons for RuleTemplateInstance
+const (
+	// ReasonTemplateNotFound indicates the referenced RuleTemplate does not exist
+	ReasonTemplateNotFound = "TemplateNotFound"
+	// ReasonMissingParameter indicates a required template parameter has no value
+	ReasonMissingParameter = "MissingParameter"
+)
+
+// RuleTemplateInstanceStatus defines the observed state of RuleTemplateInstance
+type RuleTemplateInstanceStatus struct {
+	// Conditions represent the latest available observations of the RuleTemplateInstance's state
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// PrometheusRule is the name of the generated PrometheusRule
+	// +optional
+	PrometheusRule string `json:"prometheusRule,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Template",type=string,JSONPath=`.spec.templateRef`
+
+// RuleTemplateInstance is the Schema for the ruletemplateinstances API
+type RuleTemplateInstance struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   RuleTemplateInstanceSpec   `json:"spec,omitempty"`
+	Status RuleTemplateInstanceStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// RuleTemplateInstanceList contains a list of RuleTemplateInstance
+type RuleTemplateInstanceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []RuleTemplateInstance `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&RuleTemplateInstance{}, &RuleTemplateInstanceList{})
+}
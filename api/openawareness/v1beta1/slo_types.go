@@ -0,0 +1,109 @@
+/*
+Copyright 2024 Syndlex.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SLI defines how the error ratio of a service is measured.
+// Both queries may use the {{ .window }} template variable, which is replaced
+// with the rate window of each generated recording rule (e.g. 5m, 1h).
+type SLI struct {
+	// ErrorQuery counts the failed events of the service
+	// Example: sum(rate(http_requests_total{job="api",code=~"5.."}[{{ .window }}]))
+	// +kubebuilder:validation:Required
+	ErrorQuery string `json:"errorQuery"`
+
+	// TotalQuery counts all events of the service
+	// Example: sum(rate(http_requests_total{job="api"}[{{ .window }}]))
+	// +kubebuilder:validation:Required
+	TotalQuery string `json:"totalQuery"`
+}
+
+// SLOSpec defines the desired state of SLO
+type SLOSpec struct {
+	// Service is the name of the service the objective applies to
+	// +kubebuilder:validation:Required
+	Service string `json:"service"`
+
+	// Objective is the target success percentage, e.g. "99.9"
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^[0-9]{1,2}(\.[0-9]+)?$`
+	Objective string `json:"objective"`
+
+	// SLI defines the queries used to compute the error ratio
+	// +kubebuilder:validation:Required
+	SLI SLI `json:"sli"`
+
+	// Window is the SLO period the error budget is calculated for
+	// Default: 30d
+	// +kubebuilder:default="30d"
+	// +optional
+	Window string `json:"window,omitempty"`
+
+	// Labels are added to all generated alerts
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// Condition reasons for SLO
+const (
+	// ReasonInvalidSLO indicates the SLO spec cannot be turned into rules
+	ReasonInvalidSLO = "InvalidSLO"
+	// ReasonRulesGenerated indicates the PrometheusRule was created or updated
+	ReasonRulesGenerated = "RulesGenerated"
+)
+
+// SLOStatus defines the observed state of SLO
+type SLOStatus struct {
+	// Conditions represent the latest available observations of the SLO's state
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// PrometheusRule is the name of the generated PrometheusRule
+	// +optional
+	PrometheusRule string `json:"prometheusRule,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Service",type=string,JSONPath=`.spec.service`
+// +kubebuilder:printcolumn:name="Objective",type=string,JSONPath=`.spec.objective`
+// +kubebuilder:printcolumn:name="Window",type=string,JSONPath=`.spec.window`
+
+// SLO is the Schema for the slos API
+type SLO struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   SLOSpec   `json:"spec,omitempty"`
+	Status SLOStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// SLOList contains a list of SLO
+type SLOList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SLO `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&SLO{}, &SLOList{})
+}
@@ -0,0 +1,139 @@
+/*
+Copyright 2024 Syndlex.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SnapshotDestination selects where a snapshot is written
+// +kubebuilder:validation:XValidation:rule="[has(self.configMap), has(self.secret), has(self.url)].filter(x, x).size() == 1",message="exactly one of configMap, secret and url must be set"
+// +kubebuilder:validation:XValidation:rule="!has(self.secretRef) || has(self.url)",message="secretRef requires url"
+type SnapshotDestination struct {
+	// ConfigMap is the name of the ConfigMap created in the namespace of the Snapshot
+	// +kubebuilder:validation:MaxLength=253
+	// +optional
+	ConfigMap string `json:"configMap,omitempty"`
+
+	// Secret is the name of the Secret created in the namespace of the Snapshot, for
+	// Alertmanager configurations holding credentials
+	// +kubebuilder:validation:MaxLength=253
+	// +optional
+	Secret string `json:"secret,omitempty"`
+
+	// URL receives the snapshot in an HTTP PUT request, e.g. a presigned object storage URL
+	// +kubebuilder:validation:Pattern=`^https?://`
+	// +optional
+	URL string `json:"url,omitempty"`
+
+	// SecretRef names a Secret in the namespace of the Snapshot used to authenticate the upload
+	// to URL, with a token key for bearer authentication or the username and password keys
+	// +optional
+	SecretRef *corev1.LocalObjectReference `json:"secretRef,omitempty"`
+}
+
+// SnapshotSpec defines the tenant captured by a Snapshot and where it is written
+type SnapshotSpec struct {
+	// ClientConfig references the ClientConfig of the captured tenant
+	// +kubebuilder:validation:Required
+	ClientConfig ClientConfigReference `json:"clientConfig"`
+
+	// Tenant is the captured Mimir tenant and may name one of the tenant aliases of the
+	// ClientConfig, its default tenant if empty
+	// +kubebuilder:validation:MaxLength=150
+	// +optional
+	Tenant string `json:"tenant,omitempty"`
+
+	// Destination is where the snapshot is written
+	// +kubebuilder:validation:Required
+	Destination SnapshotDestination `json:"destination"`
+
+	// Retention is how long the Snapshot is kept after it completed, e.g. 720h. The Snapshot and
+	// the ConfigMap or Secret it created are deleted afterwards, uploads to URL are kept.
+	// Snapshots are kept until deleted if unset
+	// +optional
+	Retention *metav1.Duration `json:"retention,omitempty"`
+}
+
+// Condition types and reasons for Snapshot
+const (
+	// ConditionTypeCompleted indicates whether the snapshot was captured and written
+	ConditionTypeCompleted = "Completed"
+
+	// ReasonSnapshotWritten indicates the snapshot was written to its destination
+	ReasonSnapshotWritten = "SnapshotWritten"
+	// ReasonCaptureFailed indicates the rule groups or Alertmanager configuration could not be read
+	ReasonCaptureFailed = "CaptureFailed"
+	// ReasonWriteFailed indicates the snapshot could not be written to its destination
+	ReasonWriteFailed = "WriteFailed"
+)
+
+// SnapshotStatus defines the observed state of Snapshot
+type SnapshotStatus struct {
+	// Conditions represent the latest available observations of the Snapshot's state
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// Tenant is the captured Mimir tenant (org ID)
+	// +optional
+	Tenant string `json:"tenant,omitempty"`
+
+	// CompletionTime is when the snapshot was written. Snapshots are captured only once
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// RuleGroups is the number of captured rule groups
+	// +optional
+	RuleGroups int32 `json:"ruleGroups,omitempty"`
+
+	// Location is where the snapshot was written, e.g. ConfigMap/audit-2024-05, without the
+	// query of URLs
+	// +optional
+	Location string `json:"location,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Tenant",type=string,JSONPath=`.status.tenant`
+// +kubebuilder:printcolumn:name="Completed",type=string,JSONPath=`.status.conditions[?(@.type=="Completed")].status`
+// +kubebuilder:printcolumn:name="Location",type=string,JSONPath=`.status.location`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// Snapshot is the Schema for the snapshots API.
+// It captures the rule groups and Alertmanager configuration of a tenant as stored in Mimir
+// once, e.g. for audits or as a backup before a change.
+type Snapshot struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   SnapshotSpec   `json:"spec,omitempty"`
+	Status SnapshotStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// SnapshotList contains a list of Snapshot
+type SnapshotList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Snapshot `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Snapshot{}, &SnapshotList{})
+}
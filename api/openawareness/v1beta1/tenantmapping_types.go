@@ -0,0 +1,84 @@
+/*
+Copyright 2024 Syndlex.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TenantMappingSpec defines the tenant and ClientConfig of the resources in the selected namespaces
+// +kubebuilder:validation:XValidation:rule="has(self.tenant) || has(self.clientConfig)",message="tenant or clientConfig must be set"
+type TenantMappingSpec struct {
+	// NamespaceSelector selects the namespaces whose PrometheusRules and MimirAlertTenants are mapped.
+	// An empty selector selects all namespaces
+	// +kubebuilder:validation:Required
+	NamespaceSelector metav1.LabelSelector `json:"namespaceSelector"`
+
+	// Tenant is the Mimir tenant of the resources without the openawareness.io/mimir-tenant
+	// annotation. It replaces the default tenant of their ClientConfig and may name one of its
+	// tenant aliases
+	// +kubebuilder:validation:MaxLength=150
+	// +optional
+	Tenant string `json:"tenant,omitempty"`
+
+	// ClientConfig references the ClientConfig of the resources without the
+	// openawareness.io/client-name annotation, instead of the default ClientConfig
+	// +optional
+	ClientConfig *ClientConfigReference `json:"clientConfig,omitempty"`
+}
+
+// ClientConfigReference references a ClientConfig in any namespace
+type ClientConfigReference struct {
+	// Namespace is the namespace of the ClientConfig
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Namespace string `json:"namespace"`
+
+	// Name is the name of the ClientConfig
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:printcolumn:name="Tenant",type=string,JSONPath=`.spec.tenant`
+// +kubebuilder:printcolumn:name="ClientConfig",type=string,JSONPath=`.spec.clientConfig.name`
+
+// TenantMapping is the Schema for the tenantmappings API.
+// It maps whole namespaces to a Mimir tenant and ClientConfig, so their resources need no
+// per-resource annotations. Explicit annotations take precedence. A namespace may be selected
+// by at most one TenantMapping.
+type TenantMapping struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec TenantMappingSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// TenantMappingList contains a list of TenantMapping
+type TenantMappingList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []TenantMapping `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&TenantMapping{}, &TenantMappingList{})
+}
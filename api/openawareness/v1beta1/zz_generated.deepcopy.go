@@ -5,16 +5,78 @@
 package v1beta1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AlertmanagerConfigSource) DeepCopyInto(out *AlertmanagerConfigSource) {
+	*out = *in
+	in.SecretKeyRef.DeepCopyInto(&out.SecretKeyRef)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertmanagerConfigSource.
+func (in *AlertmanagerConfigSource) DeepCopy() *AlertmanagerConfigSource {
+	if in == nil {
+		return nil
+	}
+	out := new(AlertmanagerConfigSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AlertmanagerPolicySpec) DeepCopyInto(out *AlertmanagerPolicySpec) {
+	*out = *in
+	if in.MinRepeatInterval != nil {
+		in, out := &in.MinRepeatInterval, &out.MinRepeatInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertmanagerPolicySpec.
+func (in *AlertmanagerPolicySpec) DeepCopy() *AlertmanagerPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AlertmanagerPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClientAuth) DeepCopyInto(out *ClientAuth) {
+	*out = *in
+	if in.WorkloadIdentity != nil {
+		in, out := &in.WorkloadIdentity, &out.WorkloadIdentity
+		*out = new(WorkloadIdentityAuth)
+		**out = **in
+	}
+	if in.HMAC != nil {
+		in, out := &in.HMAC, &out.HMAC
+		*out = new(HMACAuth)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClientAuth.
+func (in *ClientAuth) DeepCopy() *ClientAuth {
+	if in == nil {
+		return nil
+	}
+	out := new(ClientAuth)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClientConfig) DeepCopyInto(out *ClientConfig) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 }
 
@@ -68,9 +130,61 @@ func (in *ClientConfigList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClientConfigReference) DeepCopyInto(out *ClientConfigReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClientConfigReference.
+func (in *ClientConfigReference) DeepCopy() *ClientConfigReference {
+	if in == nil {
+		return nil
+	}
+	out := new(ClientConfigReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClientConfigSpec) DeepCopyInto(out *ClientConfigSpec) {
 	*out = *in
+	if in.Auth != nil {
+		in, out := &in.Auth, &out.Auth
+		*out = new(ClientAuth)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(ClientTLS)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Proxy != nil {
+		in, out := &in.Proxy, &out.Proxy
+		*out = new(ClientProxy)
+		**out = **in
+	}
+	if in.TenantAliases != nil {
+		in, out := &in.TenantAliases, &out.TenantAliases
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.AllowedTenants != nil {
+		in, out := &in.AllowedTenants, &out.AllowedTenants
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DeniedTenants != nil {
+		in, out := &in.DeniedTenants, &out.DeniedTenants
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.MinRuleInterval != nil {
+		in, out := &in.MinRuleInterval, &out.MinRuleInterval
+		*out = new(RuleIntervalPolicy)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClientConfigSpec.
@@ -97,6 +211,11 @@ func (in *ClientConfigStatus) DeepCopyInto(out *ClientConfigStatus) {
 		in, out := &in.LastConnectionTime, &out.LastConnectionTime
 		*out = (*in).DeepCopy()
 	}
+	if in.Tenants != nil {
+		in, out := &in.Tenants, &out.Tenants
+		*out = new(TenantListStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClientConfigStatus.
@@ -110,26 +229,113 @@ func (in *ClientConfigStatus) DeepCopy() *ClientConfigStatus {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MimirAlertTenant) DeepCopyInto(out *MimirAlertTenant) {
+func (in *ClientProxy) DeepCopyInto(out *ClientProxy) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClientProxy.
+func (in *ClientProxy) DeepCopy() *ClientProxy {
+	if in == nil {
+		return nil
+	}
+	out := new(ClientProxy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClientTLS) DeepCopyInto(out *ClientTLS) {
+	*out = *in
+	if in.CAConfigMapRef != nil {
+		in, out := &in.CAConfigMapRef, &out.CAConfigMapRef
+		*out = new(corev1.ConfigMapKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClientTLS.
+func (in *ClientTLS) DeepCopy() *ClientTLS {
+	if in == nil {
+		return nil
+	}
+	out := new(ClientTLS)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EvaluationParity) DeepCopyInto(out *EvaluationParity) {
+	*out = *in
+	in.CheckedAt.DeepCopyInto(&out.CheckedAt)
+	if in.Mismatches != nil {
+		in, out := &in.Mismatches, &out.Mismatches
+		*out = make([]RuleParity, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EvaluationParity.
+func (in *EvaluationParity) DeepCopy() *EvaluationParity {
+	if in == nil {
+		return nil
+	}
+	out := new(EvaluationParity)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitTemplateSource) DeepCopyInto(out *GitTemplateSource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitTemplateSource.
+func (in *GitTemplateSource) DeepCopy() *GitTemplateSource {
+	if in == nil {
+		return nil
+	}
+	out := new(GitTemplateSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HMACAuth) DeepCopyInto(out *HMACAuth) {
+	*out = *in
+	in.SecretRef.DeepCopyInto(&out.SecretRef)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HMACAuth.
+func (in *HMACAuth) DeepCopy() *HMACAuth {
+	if in == nil {
+		return nil
+	}
+	out := new(HMACAuth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MimirAlertGlobals) DeepCopyInto(out *MimirAlertGlobals) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
+	out.Spec = in.Spec
 	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MimirAlertTenant.
-func (in *MimirAlertTenant) DeepCopy() *MimirAlertTenant {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MimirAlertGlobals.
+func (in *MimirAlertGlobals) DeepCopy() *MimirAlertGlobals {
 	if in == nil {
 		return nil
 	}
-	out := new(MimirAlertTenant)
+	out := new(MimirAlertGlobals)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *MimirAlertTenant) DeepCopyObject() runtime.Object {
+func (in *MimirAlertGlobals) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -137,31 +343,31 @@ func (in *MimirAlertTenant) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MimirAlertTenantList) DeepCopyInto(out *MimirAlertTenantList) {
+func (in *MimirAlertGlobalsList) DeepCopyInto(out *MimirAlertGlobalsList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]MimirAlertTenant, len(*in))
+		*out = make([]MimirAlertGlobals, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MimirAlertTenantList.
-func (in *MimirAlertTenantList) DeepCopy() *MimirAlertTenantList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MimirAlertGlobalsList.
+func (in *MimirAlertGlobalsList) DeepCopy() *MimirAlertGlobalsList {
 	if in == nil {
 		return nil
 	}
-	out := new(MimirAlertTenantList)
+	out := new(MimirAlertGlobalsList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *MimirAlertTenantList) DeepCopyObject() runtime.Object {
+func (in *MimirAlertGlobalsList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -169,34 +375,125 @@ func (in *MimirAlertTenantList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MimirAlertTenantSpec) DeepCopyInto(out *MimirAlertTenantSpec) {
+func (in *MimirAlertGlobalsSpec) DeepCopyInto(out *MimirAlertGlobalsSpec) {
 	*out = *in
-	if in.TemplateFiles != nil {
-		in, out := &in.TemplateFiles, &out.TemplateFiles
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
+	out.Tenant = in.Tenant
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MimirAlertGlobalsSpec.
+func (in *MimirAlertGlobalsSpec) DeepCopy() *MimirAlertGlobalsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MimirAlertGlobalsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MimirAlertGlobalsStatus) DeepCopyInto(out *MimirAlertGlobalsStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	if in.SecretDataReferences != nil {
-		in, out := &in.SecretDataReferences, &out.SecretDataReferences
-		*out = make([]SecretDataReference, len(*in))
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MimirAlertGlobalsStatus.
+func (in *MimirAlertGlobalsStatus) DeepCopy() *MimirAlertGlobalsStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MimirAlertGlobalsStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MimirAlertRoute) DeepCopyInto(out *MimirAlertRoute) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MimirAlertRoute.
+func (in *MimirAlertRoute) DeepCopy() *MimirAlertRoute {
+	if in == nil {
+		return nil
+	}
+	out := new(MimirAlertRoute)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MimirAlertRoute) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MimirAlertRouteList) DeepCopyInto(out *MimirAlertRouteList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]MimirAlertRoute, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MimirAlertRouteList.
+func (in *MimirAlertRouteList) DeepCopy() *MimirAlertRouteList {
+	if in == nil {
+		return nil
+	}
+	out := new(MimirAlertRouteList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MimirAlertRouteList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MimirAlertRouteSpec) DeepCopyInto(out *MimirAlertRouteSpec) {
+	*out = *in
+	out.Tenant = in.Tenant
+	if in.Matchers != nil {
+		in, out := &in.Matchers, &out.Matchers
+		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MimirAlertTenantSpec.
-func (in *MimirAlertTenantSpec) DeepCopy() *MimirAlertTenantSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MimirAlertRouteSpec.
+func (in *MimirAlertRouteSpec) DeepCopy() *MimirAlertRouteSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(MimirAlertTenantSpec)
+	out := new(MimirAlertRouteSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MimirAlertTenantStatus) DeepCopyInto(out *MimirAlertTenantStatus) {
+func (in *MimirAlertRouteStatus) DeepCopyInto(out *MimirAlertRouteStatus) {
 	*out = *in
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
@@ -205,33 +502,1508 @@ func (in *MimirAlertTenantStatus) DeepCopyInto(out *MimirAlertTenantStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	if in.LastSyncTime != nil {
-		in, out := &in.LastSyncTime, &out.LastSyncTime
-		*out = (*in).DeepCopy()
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MimirAlertTenantStatus.
-func (in *MimirAlertTenantStatus) DeepCopy() *MimirAlertTenantStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MimirAlertRouteStatus.
+func (in *MimirAlertRouteStatus) DeepCopy() *MimirAlertRouteStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(MimirAlertTenantStatus)
+	out := new(MimirAlertRouteStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SecretDataReference) DeepCopyInto(out *SecretDataReference) {
+func (in *MimirAlertTenant) DeepCopyInto(out *MimirAlertTenant) {
 	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretDataReference.
-func (in *SecretDataReference) DeepCopy() *SecretDataReference {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MimirAlertTenant.
+func (in *MimirAlertTenant) DeepCopy() *MimirAlertTenant {
 	if in == nil {
 		return nil
 	}
-	out := new(SecretDataReference)
+	out := new(MimirAlertTenant)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MimirAlertTenant) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MimirAlertTenantList) DeepCopyInto(out *MimirAlertTenantList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]MimirAlertTenant, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MimirAlertTenantList.
+func (in *MimirAlertTenantList) DeepCopy() *MimirAlertTenantList {
+	if in == nil {
+		return nil
+	}
+	out := new(MimirAlertTenantList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MimirAlertTenantList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MimirAlertTenantSpec) DeepCopyInto(out *MimirAlertTenantSpec) {
+	*out = *in
+	if in.Extends != nil {
+		in, out := &in.Extends, &out.Extends
+		*out = new(TenantReference)
+		**out = **in
+	}
+	if in.TemplateFiles != nil {
+		in, out := &in.TemplateFiles, &out.TemplateFiles
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.TemplateSource != nil {
+		in, out := &in.TemplateSource, &out.TemplateSource
+		*out = new(TemplateSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AlertmanagerConfigFrom != nil {
+		in, out := &in.AlertmanagerConfigFrom, &out.AlertmanagerConfigFrom
+		*out = new(AlertmanagerConfigSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecretDataReferences != nil {
+		in, out := &in.SecretDataReferences, &out.SecretDataReferences
+		*out = make([]SecretDataReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.SyncTimeout != nil {
+		in, out := &in.SyncTimeout, &out.SyncTimeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MimirAlertTenantSpec.
+func (in *MimirAlertTenantSpec) DeepCopy() *MimirAlertTenantSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MimirAlertTenantSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MimirAlertTenantStatus) DeepCopyInto(out *MimirAlertTenantStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastSyncTime != nil {
+		in, out := &in.LastSyncTime, &out.LastSyncTime
+		*out = (*in).DeepCopy()
+	}
+	if in.ReferenceConflicts != nil {
+		in, out := &in.ReferenceConflicts, &out.ReferenceConflicts
+		*out = make([]ReferenceConflict, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MimirAlertTenantStatus.
+func (in *MimirAlertTenantStatus) DeepCopy() *MimirAlertTenantStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MimirAlertTenantStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MimirInhibitRule) DeepCopyInto(out *MimirInhibitRule) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MimirInhibitRule.
+func (in *MimirInhibitRule) DeepCopy() *MimirInhibitRule {
+	if in == nil {
+		return nil
+	}
+	out := new(MimirInhibitRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MimirInhibitRule) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MimirInhibitRuleList) DeepCopyInto(out *MimirInhibitRuleList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]MimirInhibitRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MimirInhibitRuleList.
+func (in *MimirInhibitRuleList) DeepCopy() *MimirInhibitRuleList {
+	if in == nil {
+		return nil
+	}
+	out := new(MimirInhibitRuleList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MimirInhibitRuleList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MimirInhibitRuleSpec) DeepCopyInto(out *MimirInhibitRuleSpec) {
+	*out = *in
+	out.Tenant = in.Tenant
+	if in.SourceMatchers != nil {
+		in, out := &in.SourceMatchers, &out.SourceMatchers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.TargetMatchers != nil {
+		in, out := &in.TargetMatchers, &out.TargetMatchers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Equal != nil {
+		in, out := &in.Equal, &out.Equal
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MimirInhibitRuleSpec.
+func (in *MimirInhibitRuleSpec) DeepCopy() *MimirInhibitRuleSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MimirInhibitRuleSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MimirInhibitRuleStatus) DeepCopyInto(out *MimirInhibitRuleStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MimirInhibitRuleStatus.
+func (in *MimirInhibitRuleStatus) DeepCopy() *MimirInhibitRuleStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MimirInhibitRuleStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MimirMuteTiming) DeepCopyInto(out *MimirMuteTiming) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MimirMuteTiming.
+func (in *MimirMuteTiming) DeepCopy() *MimirMuteTiming {
+	if in == nil {
+		return nil
+	}
+	out := new(MimirMuteTiming)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MimirMuteTiming) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MimirMuteTimingList) DeepCopyInto(out *MimirMuteTimingList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]MimirMuteTiming, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MimirMuteTimingList.
+func (in *MimirMuteTimingList) DeepCopy() *MimirMuteTimingList {
+	if in == nil {
+		return nil
+	}
+	out := new(MimirMuteTimingList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MimirMuteTimingList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MimirMuteTimingSpec) DeepCopyInto(out *MimirMuteTimingSpec) {
+	*out = *in
+	out.Tenant = in.Tenant
+	if in.TimeIntervals != nil {
+		in, out := &in.TimeIntervals, &out.TimeIntervals
+		*out = make([]TimeInterval, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MimirMuteTimingSpec.
+func (in *MimirMuteTimingSpec) DeepCopy() *MimirMuteTimingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MimirMuteTimingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MimirMuteTimingStatus) DeepCopyInto(out *MimirMuteTimingStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MimirMuteTimingStatus.
+func (in *MimirMuteTimingStatus) DeepCopy() *MimirMuteTimingStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MimirMuteTimingStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OCITemplateSource) DeepCopyInto(out *OCITemplateSource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OCITemplateSource.
+func (in *OCITemplateSource) DeepCopy() *OCITemplateSource {
+	if in == nil {
+		return nil
+	}
+	out := new(OCITemplateSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperatorConfig) DeepCopyInto(out *OperatorConfig) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorConfig.
+func (in *OperatorConfig) DeepCopy() *OperatorConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(OperatorConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OperatorConfig) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperatorConfigList) DeepCopyInto(out *OperatorConfigList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]OperatorConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorConfigList.
+func (in *OperatorConfigList) DeepCopy() *OperatorConfigList {
+	if in == nil {
+		return nil
+	}
+	out := new(OperatorConfigList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OperatorConfigList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperatorConfigSpec) DeepCopyInto(out *OperatorConfigSpec) {
+	*out = *in
+	if in.SyncTimeout != nil {
+		in, out := &in.SyncTimeout, &out.SyncTimeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.ResyncInterval != nil {
+		in, out := &in.ResyncInterval, &out.ResyncInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Retry != nil {
+		in, out := &in.Retry, &out.Retry
+		*out = new(RetrySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AlertmanagerPolicy != nil {
+		in, out := &in.AlertmanagerPolicy, &out.AlertmanagerPolicy
+		*out = new(AlertmanagerPolicySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RulePolicy != nil {
+		in, out := &in.RulePolicy, &out.RulePolicy
+		*out = new(RulePolicySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RuleSelector != nil {
+		in, out := &in.RuleSelector, &out.RuleSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorConfigSpec.
+func (in *OperatorConfigSpec) DeepCopy() *OperatorConfigSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OperatorConfigSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperatorConfigStatus) DeepCopyInto(out *OperatorConfigStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorConfigStatus.
+func (in *OperatorConfigStatus) DeepCopy() *OperatorConfigStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OperatorConfigStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrometheusRuleSync) DeepCopyInto(out *PrometheusRuleSync) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrometheusRuleSync.
+func (in *PrometheusRuleSync) DeepCopy() *PrometheusRuleSync {
+	if in == nil {
+		return nil
+	}
+	out := new(PrometheusRuleSync)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PrometheusRuleSync) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrometheusRuleSyncList) DeepCopyInto(out *PrometheusRuleSyncList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]PrometheusRuleSync, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrometheusRuleSyncList.
+func (in *PrometheusRuleSyncList) DeepCopy() *PrometheusRuleSyncList {
+	if in == nil {
+		return nil
+	}
+	out := new(PrometheusRuleSyncList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PrometheusRuleSyncList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrometheusRuleSyncStatus) DeepCopyInto(out *PrometheusRuleSyncStatus) {
+	*out = *in
+	if in.FailedGroups != nil {
+		in, out := &in.FailedGroups, &out.FailedGroups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ResolvedTenants != nil {
+		in, out := &in.ResolvedTenants, &out.ResolvedTenants
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastEvaluation != nil {
+		in, out := &in.LastEvaluation, &out.LastEvaluation
+		*out = (*in).DeepCopy()
+	}
+	if in.Parity != nil {
+		in, out := &in.Parity, &out.Parity
+		*out = new(EvaluationParity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrometheusRuleSyncStatus.
+func (in *PrometheusRuleSyncStatus) DeepCopy() *PrometheusRuleSyncStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PrometheusRuleSyncStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RecordingRuleBundle) DeepCopyInto(out *RecordingRuleBundle) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RecordingRuleBundle.
+func (in *RecordingRuleBundle) DeepCopy() *RecordingRuleBundle {
+	if in == nil {
+		return nil
+	}
+	out := new(RecordingRuleBundle)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RecordingRuleBundle) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RecordingRuleBundleList) DeepCopyInto(out *RecordingRuleBundleList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]RecordingRuleBundle, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RecordingRuleBundleList.
+func (in *RecordingRuleBundleList) DeepCopy() *RecordingRuleBundleList {
+	if in == nil {
+		return nil
+	}
+	out := new(RecordingRuleBundleList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RecordingRuleBundleList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RecordingRuleBundleSpec) DeepCopyInto(out *RecordingRuleBundleSpec) {
+	*out = *in
+	in.Library.DeepCopyInto(&out.Library)
+	in.TenantSelector.DeepCopyInto(&out.TenantSelector)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RecordingRuleBundleSpec.
+func (in *RecordingRuleBundleSpec) DeepCopy() *RecordingRuleBundleSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RecordingRuleBundleSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RecordingRuleBundleStatus) DeepCopyInto(out *RecordingRuleBundleStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RecordingRuleBundleStatus.
+func (in *RecordingRuleBundleStatus) DeepCopy() *RecordingRuleBundleStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RecordingRuleBundleStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RecordingRuleLibrary) DeepCopyInto(out *RecordingRuleLibrary) {
+	*out = *in
+	if in.ConfigMapRef != nil {
+		in, out := &in.ConfigMapRef, &out.ConfigMapRef
+		*out = new(corev1.ConfigMapKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RecordingRuleLibrary.
+func (in *RecordingRuleLibrary) DeepCopy() *RecordingRuleLibrary {
+	if in == nil {
+		return nil
+	}
+	out := new(RecordingRuleLibrary)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReferenceConflict) DeepCopyInto(out *ReferenceConflict) {
+	*out = *in
+	if in.Sources != nil {
+		in, out := &in.Sources, &out.Sources
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReferenceConflict.
+func (in *ReferenceConflict) DeepCopy() *ReferenceConflict {
+	if in == nil {
+		return nil
+	}
+	out := new(ReferenceConflict)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RetrySpec) DeepCopyInto(out *RetrySpec) {
+	*out = *in
+	if in.BaseDelay != nil {
+		in, out := &in.BaseDelay, &out.BaseDelay
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.MaxDelay != nil {
+		in, out := &in.MaxDelay, &out.MaxDelay
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RetrySpec.
+func (in *RetrySpec) DeepCopy() *RetrySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RetrySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuleIntervalPolicy) DeepCopyInto(out *RuleIntervalPolicy) {
+	*out = *in
+	out.Minimum = in.Minimum
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuleIntervalPolicy.
+func (in *RuleIntervalPolicy) DeepCopy() *RuleIntervalPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(RuleIntervalPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuleParity) DeepCopyInto(out *RuleParity) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuleParity.
+func (in *RuleParity) DeepCopy() *RuleParity {
+	if in == nil {
+		return nil
+	}
+	out := new(RuleParity)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RulePolicySpec) DeepCopyInto(out *RulePolicySpec) {
+	*out = *in
+	if in.RequiredLabels != nil {
+		in, out := &in.RequiredLabels, &out.RequiredLabels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.RequiredAnnotations != nil {
+		in, out := &in.RequiredAnnotations, &out.RequiredAnnotations
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RulePolicySpec.
+func (in *RulePolicySpec) DeepCopy() *RulePolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RulePolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuleTemplate) DeepCopyInto(out *RuleTemplate) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuleTemplate.
+func (in *RuleTemplate) DeepCopy() *RuleTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(RuleTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RuleTemplate) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuleTemplateInstance) DeepCopyInto(out *RuleTemplateInstance) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuleTemplateInstance.
+func (in *RuleTemplateInstance) DeepCopy() *RuleTemplateInstance {
+	if in == nil {
+		return nil
+	}
+	out := new(RuleTemplateInstance)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RuleTemplateInstance) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuleTemplateInstanceList) DeepCopyInto(out *RuleTemplateInstanceList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]RuleTemplateInstance, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuleTemplateInstanceList.
+func (in *RuleTemplateInstanceList) DeepCopy() *RuleTemplateInstanceList {
+	if in == nil {
+		return nil
+	}
+	out := new(RuleTemplateInstanceList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RuleTemplateInstanceList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuleTemplateInstanceSpec) DeepCopyInto(out *RuleTemplateInstanceSpec) {
+	*out = *in
+	if in.Values != nil {
+		in, out := &in.Values, &out.Values
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.SecretDataReferences != nil {
+		in, out := &in.SecretDataReferences, &out.SecretDataReferences
+		*out = make([]SecretDataReference, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuleTemplateInstanceSpec.
+func (in *RuleTemplateInstanceSpec) DeepCopy() *RuleTemplateInstanceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RuleTemplateInstanceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuleTemplateInstanceStatus) DeepCopyInto(out *RuleTemplateInstanceStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuleTemplateInstanceStatus.
+func (in *RuleTemplateInstanceStatus) DeepCopy() *RuleTemplateInstanceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RuleTemplateInstanceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuleTemplateList) DeepCopyInto(out *RuleTemplateList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]RuleTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuleTemplateList.
+func (in *RuleTemplateList) DeepCopy() *RuleTemplateList {
+	if in == nil {
+		return nil
+	}
+	out := new(RuleTemplateList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RuleTemplateList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuleTemplateParameter) DeepCopyInto(out *RuleTemplateParameter) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuleTemplateParameter.
+func (in *RuleTemplateParameter) DeepCopy() *RuleTemplateParameter {
+	if in == nil {
+		return nil
+	}
+	out := new(RuleTemplateParameter)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuleTemplateSpec) DeepCopyInto(out *RuleTemplateSpec) {
+	*out = *in
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make([]RuleTemplateParameter, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuleTemplateSpec.
+func (in *RuleTemplateSpec) DeepCopy() *RuleTemplateSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RuleTemplateSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SLI) DeepCopyInto(out *SLI) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SLI.
+func (in *SLI) DeepCopy() *SLI {
+	if in == nil {
+		return nil
+	}
+	out := new(SLI)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SLO) DeepCopyInto(out *SLO) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SLO.
+func (in *SLO) DeepCopy() *SLO {
+	if in == nil {
+		return nil
+	}
+	out := new(SLO)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SLO) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SLOList) DeepCopyInto(out *SLOList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]SLO, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SLOList.
+func (in *SLOList) DeepCopy() *SLOList {
+	if in == nil {
+		return nil
+	}
+	out := new(SLOList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SLOList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SLOSpec) DeepCopyInto(out *SLOSpec) {
+	*out = *in
+	out.SLI = in.SLI
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SLOSpec.
+func (in *SLOSpec) DeepCopy() *SLOSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SLOSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SLOStatus) DeepCopyInto(out *SLOStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SLOStatus.
+func (in *SLOStatus) DeepCopy() *SLOStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SLOStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretDataReference) DeepCopyInto(out *SecretDataReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretDataReference.
+func (in *SecretDataReference) DeepCopy() *SecretDataReference {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretDataReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Snapshot) DeepCopyInto(out *Snapshot) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Snapshot.
+func (in *Snapshot) DeepCopy() *Snapshot {
+	if in == nil {
+		return nil
+	}
+	out := new(Snapshot)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Snapshot) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SnapshotDestination) DeepCopyInto(out *SnapshotDestination) {
+	*out = *in
+	if in.SecretRef != nil {
+		in, out := &in.SecretRef, &out.SecretRef
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnapshotDestination.
+func (in *SnapshotDestination) DeepCopy() *SnapshotDestination {
+	if in == nil {
+		return nil
+	}
+	out := new(SnapshotDestination)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SnapshotList) DeepCopyInto(out *SnapshotList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Snapshot, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnapshotList.
+func (in *SnapshotList) DeepCopy() *SnapshotList {
+	if in == nil {
+		return nil
+	}
+	out := new(SnapshotList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SnapshotList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SnapshotSpec) DeepCopyInto(out *SnapshotSpec) {
+	*out = *in
+	out.ClientConfig = in.ClientConfig
+	in.Destination.DeepCopyInto(&out.Destination)
+	if in.Retention != nil {
+		in, out := &in.Retention, &out.Retention
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnapshotSpec.
+func (in *SnapshotSpec) DeepCopy() *SnapshotSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SnapshotSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SnapshotStatus) DeepCopyInto(out *SnapshotStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnapshotStatus.
+func (in *SnapshotStatus) DeepCopy() *SnapshotStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SnapshotStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TemplateSource) DeepCopyInto(out *TemplateSource) {
+	*out = *in
+	if in.OCI != nil {
+		in, out := &in.OCI, &out.OCI
+		*out = new(OCITemplateSource)
+		**out = **in
+	}
+	if in.Git != nil {
+		in, out := &in.Git, &out.Git
+		*out = new(GitTemplateSource)
+		**out = **in
+	}
+	if in.SecretRef != nil {
+		in, out := &in.SecretRef, &out.SecretRef
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TemplateSource.
+func (in *TemplateSource) DeepCopy() *TemplateSource {
+	if in == nil {
+		return nil
+	}
+	out := new(TemplateSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TenantListStatus) DeepCopyInto(out *TenantListStatus) {
+	*out = *in
+	if in.Sample != nil {
+		in, out := &in.Sample, &out.Sample
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastListTime != nil {
+		in, out := &in.LastListTime, &out.LastListTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantListStatus.
+func (in *TenantListStatus) DeepCopy() *TenantListStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TenantListStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TenantMapping) DeepCopyInto(out *TenantMapping) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantMapping.
+func (in *TenantMapping) DeepCopy() *TenantMapping {
+	if in == nil {
+		return nil
+	}
+	out := new(TenantMapping)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TenantMapping) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TenantMappingList) DeepCopyInto(out *TenantMappingList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]TenantMapping, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantMappingList.
+func (in *TenantMappingList) DeepCopy() *TenantMappingList {
+	if in == nil {
+		return nil
+	}
+	out := new(TenantMappingList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TenantMappingList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TenantMappingSpec) DeepCopyInto(out *TenantMappingSpec) {
+	*out = *in
+	in.NamespaceSelector.DeepCopyInto(&out.NamespaceSelector)
+	if in.ClientConfig != nil {
+		in, out := &in.ClientConfig, &out.ClientConfig
+		*out = new(ClientConfigReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantMappingSpec.
+func (in *TenantMappingSpec) DeepCopy() *TenantMappingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TenantMappingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TenantReference) DeepCopyInto(out *TenantReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantReference.
+func (in *TenantReference) DeepCopy() *TenantReference {
+	if in == nil {
+		return nil
+	}
+	out := new(TenantReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TimeInterval) DeepCopyInto(out *TimeInterval) {
+	*out = *in
+	if in.Times != nil {
+		in, out := &in.Times, &out.Times
+		*out = make([]TimeRange, len(*in))
+		copy(*out, *in)
+	}
+	if in.Weekdays != nil {
+		in, out := &in.Weekdays, &out.Weekdays
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DaysOfMonth != nil {
+		in, out := &in.DaysOfMonth, &out.DaysOfMonth
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Months != nil {
+		in, out := &in.Months, &out.Months
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Years != nil {
+		in, out := &in.Years, &out.Years
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TimeInterval.
+func (in *TimeInterval) DeepCopy() *TimeInterval {
+	if in == nil {
+		return nil
+	}
+	out := new(TimeInterval)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TimeRange) DeepCopyInto(out *TimeRange) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TimeRange.
+func (in *TimeRange) DeepCopy() *TimeRange {
+	if in == nil {
+		return nil
+	}
+	out := new(TimeRange)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkloadIdentityAuth) DeepCopyInto(out *WorkloadIdentityAuth) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkloadIdentityAuth.
+func (in *WorkloadIdentityAuth) DeepCopy() *WorkloadIdentityAuth {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkloadIdentityAuth)
 	in.DeepCopyInto(out)
 	return out
 }
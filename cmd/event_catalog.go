@@ -0,0 +1,31 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/syndlex/openawareness-controller/internal/events"
+)
+
+// eventCatalogCommand is the subcommand printing the catalog of event reasons
+const eventCatalogCommand = "event-catalog"
+
+// runEventCatalog implements `manager event-catalog`: it prints the reasons of the events
+// emitted by the controller as JSON, which `make event-catalog` writes to docs/event-reasons.json.
+// Returns the exit code of the command.
+func runEventCatalog(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet(eventCatalogCommand, flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	catalog, err := events.RenderCatalog()
+	if err != nil {
+		_, _ = fmt.Fprintln(stderr, err)
+		return 1
+	}
+	_, _ = stdout.Write(catalog)
+	return 0
+}
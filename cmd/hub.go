@@ -0,0 +1,38 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/cluster"
+
+	"github.com/syndlex/openawareness-controller/internal/controller/utils"
+)
+
+// newHubCluster connects to the hub cluster PrometheusRules and MimirAlertTenants are read
+// from, using the given kubeconfig file and context, the current context if empty.
+// Events about the resources are recorded in the hub cluster.
+func newHubCluster(
+	kubeconfig, kubeContext string,
+	scheme *runtime.Scheme,
+	eventAggregationInterval time.Duration,
+) (cluster.Cluster, error) {
+	config, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+		&clientcmd.ClientConfigLoadingRules{ExplicitPath: kubeconfig},
+		&clientcmd.ConfigOverrides{CurrentContext: kubeContext},
+	).ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("loading hub kubeconfig %s: %w", kubeconfig, err)
+	}
+
+	return cluster.New(config, func(o *cluster.Options) {
+		o.Scheme = scheme
+		o.Client = clientOptions()
+		if eventAggregationInterval > 0 {
+			// The hub cluster lives as long as the process, so the broadcaster cannot leak
+			o.EventBroadcaster = utils.NewEventBroadcaster(eventAggregationInterval) //nolint:staticcheck
+		}
+	})
+}
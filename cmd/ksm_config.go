@@ -0,0 +1,32 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/syndlex/openawareness-controller/internal/ksm"
+)
+
+// ksmConfigCommand is the subcommand printing the kube-state-metrics configuration
+const ksmConfigCommand = "ksm-config"
+
+// runKSMConfig implements `manager ksm-config`: it prints the kube-state-metrics custom
+// resource state configuration exposing the statuses of the openawareness resources, which
+// `make ksm-config` writes to config/kube-state-metrics.
+// Returns the exit code of the command.
+func runKSMConfig(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet(ksmConfigCommand, flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	config, err := ksm.Render(ksm.NewConfig())
+	if err != nil {
+		_, _ = fmt.Fprintln(stderr, err)
+		return 1
+	}
+	_, _ = stdout.Write(config)
+	return 0
+}
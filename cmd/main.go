@@ -3,20 +3,40 @@ package main
 
 import (
 	"crypto/tls"
+	"errors"
 	"flag"
+	"net/http"
+	"time"
 
 	"os"
 
+	"github.com/syndlex/openawareness-controller/internal/backup"
 	"github.com/syndlex/openawareness-controller/internal/clients"
+	"github.com/syndlex/openawareness-controller/internal/controller/utils"
+	"github.com/syndlex/openawareness-controller/internal/debugapi"
+	"github.com/syndlex/openawareness-controller/internal/gc"
+	"github.com/syndlex/openawareness-controller/internal/logging"
+	"github.com/syndlex/openawareness-controller/internal/metrics"
+	"github.com/syndlex/openawareness-controller/internal/mimir"
+	"github.com/syndlex/openawareness-controller/internal/notifications"
+	"github.com/syndlex/openawareness-controller/internal/operatorconfig"
+	"github.com/syndlex/openawareness-controller/internal/parity"
+	"github.com/syndlex/openawareness-controller/internal/policy"
+	"github.com/syndlex/openawareness-controller/internal/templatesource"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/cluster"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/filters"
@@ -35,8 +55,20 @@ import (
 var (
 	scheme   = runtime.NewScheme()
 	setupLog = ctrl.Log.WithName("setup")
+	// version is the controller version set at build time with -ldflags "-X main.version=<version>".
+	// Synced resources are only stamped with the version if set.
+	version = ""
 )
 
+// clientOptions returns the options of the clients of the manager and the hub cluster.
+// Secrets and ConfigMaps are read from the API server, their watches only cache metadata,
+// so the controller does not keep the data of every Secret in the cluster in memory.
+func clientOptions() client.Options {
+	return client.Options{Cache: &client.CacheOptions{
+		DisableFor: []client.Object{&corev1.Secret{}, &corev1.ConfigMap{}},
+	}}
+}
+
 func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 
@@ -45,11 +77,70 @@ func init() {
 }
 
 func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case renderCommand:
+			os.Exit(runRender(os.Args[2:], os.Stdout, os.Stderr))
+		case validateRulesCommand:
+			os.Exit(runValidateRules(os.Args[2:], os.Stdout, os.Stderr))
+		case restoreCommand:
+			os.Exit(runRestore(os.Args[2:], os.Stdout, os.Stderr))
+		case ksmConfigCommand:
+			os.Exit(runKSMConfig(os.Args[2:], os.Stdout, os.Stderr))
+		case eventCatalogCommand:
+			os.Exit(runEventCatalog(os.Args[2:], os.Stdout, os.Stderr))
+		}
+	}
+
 	var metricsAddr string
 	var enableLeaderElection bool
 	var probeAddr string
 	var secureMetrics bool
 	var enableHTTP2 bool
+	var gcInterval time.Duration
+	var gcDryRun bool
+	var gcAdoptUnlabeled bool
+	var verifyRuleActivation bool
+	var globalValuesFrom string
+	var clusterName string
+	var installationName string
+	var alertmanagerPolicyMode string
+	var alertmanagerMinRepeatInterval time.Duration
+	var defaultReceiverMode string
+	var tenantQuota policy.TenantQuota
+	var defaultReceiverWebhookURL string
+	var rulePolicyMode string
+	var rulePolicyRequiredLabels string
+	var rulePolicyRequiredAnnotations string
+	var enableDebugAPI bool
+	var debugAPIAddr string
+	var syncTimeout time.Duration
+	var shutdownGracePeriod time.Duration
+	var eventAggregationInterval time.Duration
+	var hubKubeconfig string
+	var hubContext string
+	var resyncRate int
+	var mimirTransport mimir.TransportConfig
+	var mimirCircuitBreaker mimir.CircuitBreakerConfig
+	var mimirRequestTapeSize int
+	var prometheusRuleWorkers int
+	var alertTenantWorkers int
+	var clientConfigWorkers int
+	var clientHealthCheckInterval time.Duration
+	var ruleExtraLabels string
+	var ruleNamespaceLabels string
+	var detectRuleConflicts bool
+	var detectRuleDrift bool
+	var simulateRules bool
+	var parityPrometheusURL string
+	var notificationPollInterval time.Duration
+	var notificationFailureThreshold float64
+	var backupNamespace string
+	var destructiveCleanup bool
+	var readOnly bool
+	var templateSourceRefreshInterval time.Duration
+	var successLogInterval time.Duration
+	var tenantBootstrapConfig string
 	var tlsOpts []func(*tls.Config)
 	flag.StringVar(&metricsAddr, "metrics-bind-address", "0", "The address the metrics endpoint binds to. "+
 		"Use :8443 for HTTPS or :8080 for HTTP, or leave as 0 to disable the metrics service.")
@@ -61,6 +152,136 @@ func main() {
 		"If set, the metrics endpoint is served securely via HTTPS. Use --metrics-secure=false to use HTTP instead.")
 	flag.BoolVar(&enableHTTP2, "enable-http2", false,
 		"If set, HTTP/2 will be enabled for the metrics and webhook servers")
+	flag.DurationVar(&gcInterval, "gc-interval", 0,
+		"Interval of the garbage collection of orphaned Mimir rule groups. Use 0 to disable.")
+	flag.BoolVar(&gcDryRun, "gc-dry-run", false,
+		"If set, orphaned Mimir rule groups are only reported and not deleted.")
+	flag.BoolVar(&gcAdoptUnlabeled, "gc-adopt-unlabeled", false,
+		"If set, garbage collection also deletes orphaned rule groups without instance label, pushed before "+
+			"--cluster-name or --installation-name were set. Only safe if no other installation pushes to the Mimir.")
+	flag.BoolVar(&verifyRuleActivation, "verify-rule-activation", false,
+		"If set, the ruler state is queried after each PrometheusRule sync to verify the groups are evaluated.")
+	flag.StringVar(&globalValuesFrom, "global-values-from", "",
+		"ConfigMap in the form namespace/name whose keys are available in every template as [[ .Global.KEY ]].")
+	flag.StringVar(&clusterName, "cluster-name", "",
+		"Identifier of this cluster, available in every template as [[ .Meta.Cluster ]].")
+	flag.StringVar(&installationName, "installation-name", "",
+		"Name of this operator installation. Together with --cluster-name it identifies the installation "+
+			"in Mimir requests and ownership markers, so installations pushing to the same Mimir can be told apart.")
+	flag.StringVar(&alertmanagerPolicyMode, "alertmanager-policy-mode", "",
+		"Enforce the Alertmanager configuration policy: \"warn\" reports violations, \"block\" also stops the sync. "+
+			"Disabled if empty.")
+	flag.DurationVar(&alertmanagerMinRepeatInterval, "alertmanager-policy-min-repeat-interval",
+		policy.DefaultMinRepeatInterval, "Smallest repeat_interval allowed by the Alertmanager configuration policy.")
+	flag.IntVar(&tenantQuota.MaxPerNamespace, "max-tenants-per-namespace", 0,
+		"Number of MimirAlertTenants allowed per namespace, newer ones are not synced. Unlimited if zero.")
+	flag.IntVar(&tenantQuota.MaxPerTenant, "max-tenants-per-mimir-tenant", 0,
+		"Number of MimirAlertTenants allowed to target the same Mimir tenant, newer ones are not synced. "+
+			"Unlimited if zero.")
+	flag.IntVar(&tenantQuota.MaxConfigBytes, "max-alertmanager-config-bytes", 0,
+		"Size allowed for a rendered Alertmanager configuration including its template files. Unlimited if zero.")
+	flag.StringVar(&defaultReceiverMode, "ensure-default-receiver", "",
+		"Ensure the top-level route of rendered Alertmanager configurations has a defined receiver: "+
+			"\"fail\" rejects the configuration, \"inject\" adds a catch-all webhook receiver. Disabled if empty.")
+	flag.StringVar(&defaultReceiverWebhookURL, "default-receiver-webhook-url", "",
+		"URL of the catch-all webhook receiver injected with --ensure-default-receiver=inject.")
+	flag.StringVar(&rulePolicyMode, "rule-policy-mode", "",
+		"Enforce the PrometheusRule policy: \"warn\" reports violations as events, \"block\" also stops the push. "+
+			"Disabled if empty.")
+	flag.StringVar(&rulePolicyRequiredLabels, "rule-policy-required-labels", policy.DefaultRequiredLabels,
+		"Comma-separated labels every alerting rule must have when the rule policy is enabled.")
+	flag.StringVar(&rulePolicyRequiredAnnotations, "rule-policy-required-annotations", policy.DefaultRequiredAnnotations,
+		"Comma-separated annotations every alerting rule must have when the rule policy is enabled.")
+	flag.BoolVar(&enableDebugAPI, "enable-debug-api", false,
+		"If set, the desired rendered state of every tenant is served at /tenants/<tenant>/alertmanager "+
+			"and /tenants/<tenant>/rules for authorized users.")
+	flag.StringVar(&debugAPIAddr, "debug-api-bind-address", debugapi.DefaultBindAddress,
+		"The address the debug API binds to.")
+	flag.DurationVar(&syncTimeout, "sync-timeout", utils.DefaultSyncTimeout,
+		"Default timeout of the Mimir API operations of a single reconciliation. Use 0 to disable.")
+	flag.DurationVar(&shutdownGracePeriod, "shutdown-grace-period", utils.DefaultShutdownGracePeriod,
+		"Time reconciliations in flight may finish their pushes after a shutdown signal. Resources still "+
+			"syncing afterwards are marked Pending and synced first on start. Use 0 to cancel them immediately.")
+	flag.DurationVar(&eventAggregationInterval, "event-aggregation-interval", utils.DefaultEventAggregationInterval,
+		"Interval in which repeated events of a resource with the same reason are collapsed into one event "+
+			"with a count. Use 0 to disable.")
+	flag.StringVar(&hubKubeconfig, "hub-kubeconfig", "",
+		"Kubeconfig of a hub cluster PrometheusRules and MimirAlertTenants are read from. "+
+			"ClientConfigs are still read from the local cluster. Disabled if empty.")
+	flag.StringVar(&hubContext, "hub-context", "",
+		"Context of --hub-kubeconfig to use. Defaults to its current context.")
+	flag.IntVar(&resyncRate, "resync-rate", utils.DefaultResyncRate,
+		"Number of resources per minute re-pushed after an upgrade, for resources last synced by another "+
+			"controller version. Use 0 to re-push them without pacing.")
+	flag.IntVar(&mimirTransport.MaxIdleConnsPerHost, "mimir-max-idle-conns-per-host", mimir.DefaultMaxIdleConnsPerHost,
+		"Number of idle connections kept open per Mimir instance and reused by later pushes.")
+	flag.DurationVar(&mimirTransport.IdleConnTimeout, "mimir-idle-conn-timeout", mimir.DefaultIdleConnTimeout,
+		"Time an idle connection to a Mimir instance is kept open.")
+	flag.IntVar(&mimirTransport.MaxConcurrentRequests, "mimir-max-concurrent-requests", 0,
+		"Number of requests in flight per Mimir instance, granted round-robin across tenants so that bulk "+
+			"imports of a large tenant do not delay the syncs of small tenants. Use 0 for no limit.")
+	flag.BoolVar(&mimirTransport.ForceAttemptHTTP2, "mimir-force-http2", false,
+		"If set, HTTP/2 is negotiated with Mimir instances served over TLS, multiplexing concurrent pushes "+
+			"over one connection.")
+	flag.IntVar(&mimirCircuitBreaker.Threshold, "mimir-circuit-breaker-threshold", mimir.DefaultCircuitBreakerThreshold,
+		"Number of consecutive 5xx responses of a Mimir instance after which requests to it are paused for "+
+			"the cool-down and its ClientConfig is Degraded. Use 0 to disable.")
+	flag.DurationVar(&mimirCircuitBreaker.CoolDown, "mimir-circuit-breaker-cooldown", mimir.DefaultCircuitBreakerCoolDown,
+		"Time requests to a Mimir instance are paused once its circuit breaker opened.")
+	flag.IntVar(&mimirRequestTapeSize, "mimir-request-tape-size", 0,
+		"Number of recent Mimir API requests and responses kept in memory, with credentials redacted, and "+
+			"served at /mimir/requests by the debug API. Use 0 to disable.")
+	flag.IntVar(&prometheusRuleWorkers, "prometheusrule-workers", utils.DefaultPrometheusRuleWorkers,
+		"Number of PrometheusRules reconciled in parallel.")
+	flag.IntVar(&alertTenantWorkers, "alerttenant-workers", utils.DefaultAlertTenantWorkers,
+		"Number of MimirAlertTenants reconciled in parallel.")
+	flag.IntVar(&clientConfigWorkers, "clientconfig-workers", utils.DefaultClientConfigWorkers,
+		"Number of ClientConfigs reconciled in parallel.")
+	flag.DurationVar(&clientHealthCheckInterval, "client-health-check-interval", 5*time.Minute,
+		"Interval of the health check of connected ClientConfigs, updating their Ready and connectivity conditions. "+
+			"Use 0 to check only when a ClientConfig changes.")
+	flag.StringVar(&ruleExtraLabels, "rule-extra-labels", "",
+		"Comma-separated key=value labels added to every alerting rule, e.g. cluster=prod,region=eu. "+
+			"Labels set on a rule or its group take precedence.")
+	flag.StringVar(&ruleNamespaceLabels, "rule-namespace-labels", "",
+		"Comma-separated labels of the namespace of a PrometheusRule added to every of its alerting rules.")
+	flag.BoolVar(&detectRuleConflicts, "detect-rule-conflicts", false,
+		"If set, recording and alerting rule names defined by several resources in the same tenant are reported "+
+			"as DuplicateRuleName events after each PrometheusRule sync.")
+	flag.BoolVar(&detectRuleDrift, "detect-rule-drift", false,
+		"If set, every rule group is read from Mimir before a PrometheusRule sync, groups modified outside the "+
+			"operator are reported as RuleGroupModified events and overwritten.")
+	flag.BoolVar(&simulateRules, "simulate-rules", false,
+		"If set, the expressions of alerting rules are run against the data of their tenant before they are "+
+			"pushed, reporting failing expressions and selectors without series as events.")
+	flag.StringVar(&parityPrometheusURL, "parity-prometheus-url", "",
+		"URL of the in-cluster Prometheus evaluating PrometheusRules with the openawareness.io/dual-write "+
+			"annotation, e.g. http://prometheus-operated.monitoring:9090. The series their rules produce in "+
+			"Prometheus and Mimir are compared and reported in the EvaluationParity condition.")
+	flag.DurationVar(&notificationPollInterval, "notification-poll-interval", 0,
+		"Interval in which the failed notification counters of the Alertmanager of every MimirAlertTenant "+
+			"are read and reported as NotificationsFailed events. Use 0 to disable.")
+	flag.Float64Var(&notificationFailureThreshold, "notification-failure-threshold", notifications.DefaultThreshold,
+		"Number of failed notifications of an integration between two polls reported as event.")
+	flag.BoolVar(&destructiveCleanup, "enable-destructive-cleanup", false,
+		"If set, the Alertmanager configuration of a deleted MimirAlertTenant is deleted from Mimir without the "+
+			"openawareness.io/confirm-delete annotation. Otherwise it is retained and a warning event is recorded.")
+	flag.BoolVar(&readOnly, "read-only", false,
+		"If set, nothing is pushed to or deleted from Mimir and no finalizers are added. PrometheusRules and "+
+			"MimirAlertTenants are rendered, validated and diffed against Mimir, reporting the outcome in the "+
+			"WouldSync condition. Garbage collection only reports orphaned namespaces.")
+	flag.StringVar(&backupNamespace, "backup-namespace", "",
+		"Namespace of the Secrets keeping the last pushed Alertmanager configuration and rule groups of every "+
+			"tenant, restored with the openawareness.io/restore-backup annotation on a ClientConfig. Disabled if empty.")
+	flag.DurationVar(&templateSourceRefreshInterval, "template-source-refresh-interval",
+		templatesource.DefaultRefreshInterval,
+		"Interval in which the template files of the templateSource of MimirAlertTenants are fetched again.")
+	flag.StringVar(&tenantBootstrapConfig, "tenant-bootstrap-config", "",
+		"Path of an Alertmanager configuration template pushed to the tenants of TenantMappings that have no "+
+			"configuration, so they never run on the fallback configuration of Mimir. Disabled if empty.")
+	flag.DurationVar(&successLogInterval, "success-log-interval", 0,
+		"Minimum interval between two logs of the same success, e.g. a synced resource or a request to Mimir. "+
+			"Logs sampled out are written at verbosity 1. Disabled if 0.")
 	opts := zap.Options{
 		Development: true,
 	}
@@ -68,6 +289,7 @@ func main() {
 	flag.Parse()
 
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
+	logging.SetSuccessLogInterval(successLogInterval)
 
 	// if the enable-http2 flag is false (the default), http/2 should be disabled
 	// due to its vulnerabilities. More specifically, disabling http/2 will
@@ -102,6 +324,8 @@ func main() {
 		// unauthorized access to sensitive metrics data. Consider replacing with CertDir, CertName, and KeyName
 		// to provide certificates, ensuring the server communicates using trusted and secure certificates.
 		TLSOpts: tlsOpts,
+		// Exemplars of the reconcile duration are only exposed in the OpenMetrics format
+		ExtraHandlers: map[string]http.Handler{metrics.OpenMetricsPath: metrics.Handler()},
 	}
 
 	if secureMetrics {
@@ -112,13 +336,22 @@ func main() {
 		metricsServerOptions.FilterProvider = filters.WithAuthenticationAndAuthorization
 	}
 
-	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
+	// A read-only controller is staged next to the one syncing, it must not take over its lease
+	leaderElectionID := "8a6b7222.syndlex"
+	if readOnly {
+		leaderElectionID = "8a6b7222-read-only.syndlex"
+	}
+	managerOptions := ctrl.Options{
 		Scheme:                 scheme,
+		Client:                 clientOptions(),
 		Metrics:                metricsServerOptions,
 		WebhookServer:          webhookServer,
 		HealthProbeBindAddress: probeAddr,
 		LeaderElection:         enableLeaderElection,
-		LeaderElectionID:       "8a6b7222.syndlex",
+		LeaderElectionID:       leaderElectionID,
+		// The controllers drain their reconciliations for the shutdown grace period, then mark
+		// the interrupted resources
+		GracefulShutdownTimeout: ptr.To(max(shutdownGracePeriod, 0) + utils.ShutdownMarkTimeout),
 		// LeaderElectionReleaseOnCancel defines if the leader should step down voluntarily
 		// when the Manager ends. This requires the binary to immediately end when the
 		// Manager is stopped, otherwise, this setting is unsafe. Setting this significantly
@@ -130,7 +363,13 @@ func main() {
 		// if you are doing or is intended to do any operation such as perform cleanups
 		// after the manager stops then its usage might be unsafe.
 		// LeaderElectionReleaseOnCancel: true,
-	})
+	}
+	if eventAggregationInterval > 0 {
+		// The manager lives as long as the process, so the broadcaster cannot leak
+		managerOptions.EventBroadcaster = utils.NewEventBroadcaster(eventAggregationInterval) //nolint:staticcheck
+	}
+
+	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), managerOptions)
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
 		os.Exit(1)
@@ -142,13 +381,148 @@ func main() {
 		os.Exit(1)
 	}
 
+	// PrometheusRules and MimirAlertTenants are read from the hub cluster if configured
+	resources := cluster.Cluster(mgr)
+	resourceClient := mgr.GetClient()
+	var hubCluster cluster.Cluster
+	if hubKubeconfig != "" {
+		hubCluster, err = newHubCluster(hubKubeconfig, hubContext, scheme, eventAggregationInterval)
+		if err != nil {
+			setupLog.Error(err, "unable to connect to hub cluster")
+			os.Exit(1)
+		}
+		if err := mgr.Add(hubCluster); err != nil {
+			setupLog.Error(err, "unable to set up hub cluster")
+			os.Exit(1)
+		}
+		resources = hubCluster
+		resourceClient = utils.NewHubClient(hubCluster.GetClient(), mgr.GetClient())
+		setupLog.Info("Reading PrometheusRules and MimirAlertTenants from hub cluster",
+			"kubeconfig", hubKubeconfig, "context", hubContext)
+	}
+
+	identity := utils.InstanceIdentity(clusterName, installationName)
 	clientCache := clients.NewRulerClientCache()
+	clientCache.Identity = identity
+	clientCache.Recorder = mgr.GetEventRecorderFor("clientconfig-controller")
+	clientCache.Transport = mimirTransport
+	clientCache.CircuitBreaker = mimirCircuitBreaker
+	circuitBreakerChanged := make(chan event.GenericEvent, 100)
+	clientCache.CircuitBreakerChanged = circuitBreakerChanged
+	requestTape := mimir.NewTape(mimirRequestTapeSize)
+	clientCache.Tape = requestTape
+	if requestTape != nil && !enableDebugAPI {
+		setupLog.Info("Mimir requests are recorded but not served, set --enable-debug-api to inspect them")
+	}
+	// CA ConfigMaps live next to the ClientConfigs in the manager's cluster
+	clientCache.Reader = mgr.GetClient()
+
+	var globalValues *utils.GlobalValues
+	if globalValuesFrom != "" {
+		configMap, err := utils.ParseNamespacedName(globalValuesFrom)
+		if err != nil {
+			setupLog.Error(err, "invalid --global-values-from")
+			os.Exit(1)
+		}
+		globalValues = &utils.GlobalValues{Reader: mgr.GetClient(), ConfigMap: configMap}
+	}
+
+	amPolicyMode, err := policy.ParseMode(alertmanagerPolicyMode)
+	if err != nil {
+		setupLog.Error(err, "invalid --alertmanager-policy-mode")
+		os.Exit(1)
+	}
+	alertmanagerPolicy := &policy.AlertmanagerPolicy{
+		Mode:              amPolicyMode,
+		MinRepeatInterval: alertmanagerMinRepeatInterval,
+	}
+	receiverMode, err := utils.ParseDefaultReceiverMode(defaultReceiverMode)
+	if err != nil {
+		setupLog.Error(err, "invalid --ensure-default-receiver")
+		os.Exit(1)
+	}
+	if receiverMode == utils.DefaultReceiverInject && defaultReceiverWebhookURL == "" {
+		setupLog.Error(errors.New("--default-receiver-webhook-url is required"),
+			"invalid --ensure-default-receiver")
+		os.Exit(1)
+	}
+	defaultReceiver := &utils.DefaultReceiver{
+		Mode:       receiverMode,
+		WebhookURL: defaultReceiverWebhookURL,
+	}
+	ruleMode, err := policy.ParseMode(rulePolicyMode)
+	if err != nil {
+		setupLog.Error(err, "invalid --rule-policy-mode")
+		os.Exit(1)
+	}
+	rulePolicy := &policy.RulePolicy{
+		Mode:                ruleMode,
+		RequiredLabels:      policy.ParseList(rulePolicyRequiredLabels),
+		RequiredAnnotations: policy.ParseList(rulePolicyRequiredAnnotations),
+	}
+
+	// Dual-written rules report ParityUnknown without a Prometheus to compare with
+	var parityPrometheus parity.SeriesCounter
+	if parityPrometheusURL != "" {
+		parityPrometheus, err = parity.NewPrometheusClient(parityPrometheusURL)
+		if err != nil {
+			setupLog.Error(err, "invalid --parity-prometheus-url")
+			os.Exit(1)
+		}
+	}
+
+	staticLabels, err := utils.ParseLabels(ruleExtraLabels)
+	if err != nil {
+		setupLog.Error(err, "invalid --rule-extra-labels")
+		os.Exit(1)
+	}
+	extraLabels := &utils.ExtraLabels{
+		Static:          staticLabels,
+		NamespaceLabels: policy.ParseList(ruleNamespaceLabels),
+		Reader:          resourceClient,
+	}
+
+	// Backups are kept in the manager's cluster, next to the ClientConfigs
+	var backupStore *backup.Store
+	if backupNamespace != "" {
+		backupStore = &backup.Store{Client: mgr.GetClient(), Reader: mgr.GetAPIReader(), Namespace: backupNamespace}
+	}
+
+	// Tenants sharing a template source fetch it once per refresh interval
+	templateSources := &templatesource.Fetcher{
+		Reader:          resourceClient,
+		RefreshInterval: templateSourceRefreshInterval,
+	}
+
+	// Both controllers share the pace of re-pushes after an upgrade
+	resyncPacer := utils.NewResyncPacer(version, resyncRate)
+
+	// The OperatorConfig lives in the manager's cluster and overrides the flags at runtime
+	operatorConfig := &operatorconfig.Source{Reader: mgr.GetClient()}
 
 	if err = (&monitoringcoreoscomcontroller.PrometheusRulesReconciler{
 		RulerClients: clientCache,
-		Client:       mgr.GetClient(),
+		Client:       resourceClient,
 		Scheme:       mgr.GetScheme(),
-		Recorder:     mgr.GetEventRecorderFor("prometheusrules-controller"),
+		Recorder:     resources.GetEventRecorderFor("prometheusrules-controller"),
+
+		VerifyActivation: verifyRuleActivation,
+		RulePolicy:       rulePolicy,
+		SyncTimeout:      syncTimeout,
+		ShutdownGrace:    shutdownGracePeriod,
+		ResyncPacer:      resyncPacer,
+		ResourceCluster:  hubCluster,
+
+		MaxConcurrentReconciles: prometheusRuleWorkers,
+		ExtraLabels:             extraLabels,
+		DetectConflicts:         detectRuleConflicts,
+		DetectDrift:             detectRuleDrift,
+		Backup:                  backupStore,
+		SimulateRules:           simulateRules,
+		ParityPrometheus:        parityPrometheus,
+		OperatorConfig:          operatorConfig,
+		ReadOnly:                readOnly,
+		Identity:                identity,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "PrometheusRules")
 		os.Exit(1)
@@ -157,20 +531,163 @@ func main() {
 		RulerClients: clientCache,
 		Client:       mgr.GetClient(),
 		Scheme:       mgr.GetScheme(),
+		Recorder:     mgr.GetEventRecorderFor("clientconfig-controller"),
+
+		MaxConcurrentReconciles: clientConfigWorkers,
+		Backup:                  backupStore,
+		CircuitBreakerChanged:   circuitBreakerChanged,
+		ReadOnly:                readOnly,
+		HealthCheckInterval:     clientHealthCheckInterval,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "ClientConfig")
 		os.Exit(1)
 	}
 	if err = (&openawarenesscontroller.MimirAlertTenantReconciler{
 		RulerClients: clientCache,
-		Client:       mgr.GetClient(),
+		Client:       resourceClient,
 		Scheme:       mgr.GetScheme(),
+		Recorder:     resources.GetEventRecorderFor("mimiralerttenant-controller"),
+		GlobalValues: globalValues,
+		ClusterName:  clusterName,
+		Identity:     identity,
+
+		AlertmanagerPolicy: alertmanagerPolicy,
+		DefaultReceiver:    defaultReceiver,
+		Quota:              &tenantQuota,
+		SyncTimeout:        syncTimeout,
+		ShutdownGrace:      shutdownGracePeriod,
+		ResyncPacer:        resyncPacer,
+		ResourceCluster:    hubCluster,
+
+		MaxConcurrentReconciles: alertTenantWorkers,
+		Backup:                  backupStore,
+
+		TemplateSources:               templateSources,
+		TemplateSourceRefreshInterval: templateSourceRefreshInterval,
+		DestructiveCleanup:            destructiveCleanup,
+		OperatorConfig:                operatorConfig,
+		ReadOnly:                      readOnly,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "MimirAlertTenant")
 		os.Exit(1)
 	}
+	if err = (&openawarenesscontroller.SLOReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "SLO")
+		os.Exit(1)
+	}
+	if err = (&openawarenesscontroller.RecordingRuleBundleReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		ReadOnly: readOnly,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "RecordingRuleBundle")
+		os.Exit(1)
+	}
+	if err = (&openawarenesscontroller.RuleTemplateInstanceReconciler{
+		Client:       mgr.GetClient(),
+		Scheme:       mgr.GetScheme(),
+		GlobalValues: globalValues,
+		ClusterName:  clusterName,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "RuleTemplateInstance")
+		os.Exit(1)
+	}
+	if err = (&openawarenesscontroller.OperatorConfigReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "OperatorConfig")
+		os.Exit(1)
+	}
+	if err = (&openawarenesscontroller.SnapshotReconciler{
+		Client:       mgr.GetClient(),
+		Scheme:       mgr.GetScheme(),
+		RulerClients: clientCache,
+		Recorder:     mgr.GetEventRecorderFor("snapshot-controller"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "Snapshot")
+		os.Exit(1)
+	}
+	if tenantBootstrapConfig != "" {
+		template, err := os.ReadFile(tenantBootstrapConfig)
+		if err != nil {
+			setupLog.Error(err, "unable to read --tenant-bootstrap-config")
+			os.Exit(1)
+		}
+		if _, err := openawarenesscontroller.RenderTenantBootstrapConfig(string(template), utils.TemplateBuiltins{
+			Meta: utils.TemplateMetadata{Name: "bootstrap", Tenant: "bootstrap", ClientName: "bootstrap"},
+		}); err != nil {
+			setupLog.Error(err, "invalid --tenant-bootstrap-config")
+			os.Exit(1)
+		}
+		if err = (&openawarenesscontroller.TenantBootstrapReconciler{
+			Client:          mgr.GetClient(),
+			RulerClients:    clientCache,
+			Recorder:        mgr.GetEventRecorderFor("tenantbootstrap-controller"),
+			Template:        string(template),
+			GlobalValues:    globalValues,
+			ClusterName:     clusterName,
+			Identity:        identity,
+			ResourceCluster: hubCluster,
+			ReadOnly:        readOnly,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "TenantBootstrap")
+			os.Exit(1)
+		}
+	}
 	// +kubebuilder:scaffold:builder
 
+	if gcInterval > 0 {
+		if err := mgr.Add(&gc.Sweeper{
+			Client:         resourceClient,
+			RulerClients:   clientCache,
+			Interval:       gcInterval,
+			DryRun:         gcDryRun || readOnly,
+			Identity:       identity,
+			AdoptUnlabeled: gcAdoptUnlabeled,
+		}); err != nil {
+			setupLog.Error(err, "unable to set up rule namespace garbage collection")
+			os.Exit(1)
+		}
+	}
+
+	if notificationPollInterval > 0 {
+		if err := mgr.Add(&notifications.Poller{
+			Client:       resourceClient,
+			RulerClients: clientCache,
+			Recorder:     resources.GetEventRecorderFor("mimiralerttenant-notifications"),
+			Interval:     notificationPollInterval,
+			Threshold:    notificationFailureThreshold,
+		}); err != nil {
+			setupLog.Error(err, "unable to set up notification failure polling")
+			os.Exit(1)
+		}
+	}
+
+	if enableDebugAPI {
+		debugServer, err := debugapi.NewServer(mgr, debugAPIAddr, (&debugapi.Handler{
+			Client:       resourceClient,
+			GlobalValues: globalValues,
+			ClusterName:  clusterName,
+			ExtraLabels:  extraLabels,
+
+			TemplateSources: templateSources,
+			Tape:            requestTape,
+			ClientCache:     clientCache,
+		}).Routes())
+		if err != nil {
+			setupLog.Error(err, "unable to set up debug API")
+			os.Exit(1)
+		}
+		if err := mgr.Add(debugServer); err != nil {
+			setupLog.Error(err, "unable to set up debug API")
+			os.Exit(1)
+		}
+	}
+
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
 		setupLog.Error(err, "unable to set up health check")
 		os.Exit(1)
@@ -180,7 +697,7 @@ func main() {
 		os.Exit(1)
 	}
 
-	setupLog.Info("starting manager")
+	setupLog.Info("starting manager", "version", version)
 	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
 		setupLog.Error(err, "problem running manager")
 		os.Exit(1)
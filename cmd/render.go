@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/syndlex/openawareness-controller/internal/render"
+)
+
+// renderCommand is the subcommand rendering a MimirAlertTenant from local files
+const renderCommand = "render"
+
+// fileList collects the values of a repeatable file flag
+type fileList []string
+
+func (f *fileList) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *fileList) Set(value string) error {
+	*f = append(*f, value)
+	return nil
+}
+
+// runRender implements `manager render -f tenant.yaml --values-from cm.yaml`: it renders a
+// MimirAlertTenant with the same reference resolution and template pipeline as the controller
+// and prints the Alertmanager payload sent to Mimir.
+// Returns the exit code of the command.
+func runRender(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet(renderCommand, flag.ContinueOnError)
+	flags.SetOutput(stderr)
+
+	var tenantFile, globalValuesFile, clusterName string
+	var valuesFiles fileList
+	flags.StringVar(&tenantFile, "f", "", "File containing the MimirAlertTenant to render.")
+	flags.Var(&valuesFiles, "values-from",
+		"File containing ConfigMaps or Secrets referenced by the tenant, MimirAlertTenants it extends "+
+			"or MimirAlertRoutes, MimirAlertGlobals, MimirMuteTimings and MimirInhibitRules contributing to it. Can be repeated.")
+	flags.StringVar(&globalValuesFile, "global-values-from", "",
+		"File containing the ConfigMap whose keys are available as [[ .Global.KEY ]].")
+	flags.StringVar(&clusterName, "cluster-name", "", "Identifier of the cluster, available as [[ .Meta.Cluster ]].")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if tenantFile == "" {
+		_, _ = fmt.Fprintln(stderr, "-f is required")
+		flags.Usage()
+		return 2
+	}
+
+	opts := render.Options{ClusterName: clusterName}
+	var err error
+	if opts.Tenant, err = os.ReadFile(tenantFile); err != nil {
+		_, _ = fmt.Fprintln(stderr, err)
+		return 1
+	}
+	for _, file := range valuesFiles {
+		values, err := os.ReadFile(file)
+		if err != nil {
+			_, _ = fmt.Fprintln(stderr, err)
+			return 1
+		}
+		opts.Values = append(opts.Values, values)
+	}
+	if globalValuesFile != "" {
+		if opts.GlobalValues, err = os.ReadFile(globalValuesFile); err != nil {
+			_, _ = fmt.Fprintln(stderr, err)
+			return 1
+		}
+	}
+
+	payload, err := render.AlertmanagerPayload(context.Background(), opts)
+	if err != nil {
+		_, _ = fmt.Fprintf(stderr, "failed to render %s: %v\n", tenantFile, err)
+		return 1
+	}
+	_, _ = stdout.Write(payload)
+	return 0
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os/signal"
+	"syscall"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"k8s.io/client-go/tools/clientcmd"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	openawarenessv1beta1 "github.com/syndlex/openawareness-controller/api/openawareness/v1beta1"
+	"github.com/syndlex/openawareness-controller/internal/clients"
+	"github.com/syndlex/openawareness-controller/internal/controller/utils"
+	"github.com/syndlex/openawareness-controller/internal/policy"
+	"github.com/syndlex/openawareness-controller/internal/restore"
+	"github.com/syndlex/openawareness-controller/internal/templatesource"
+)
+
+// restoreCommand is the subcommand pushing the desired state of a ClientConfig again
+const restoreCommand = "restore"
+
+// runRestore implements `manager restore --client <name> [--tenant <id>]`: it reads the
+// resources synced through the ClientConfig from the cluster and pushes their state to Mimir
+// again, tenant configurations first, then rules, verifying every push.
+// Returns the exit code of the command.
+func runRestore(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet(restoreCommand, flag.ContinueOnError)
+	flags.SetOutput(stderr)
+
+	var clientName, clientNamespace, tenantID, kubeconfig, kubeContext string
+	var globalValuesFrom, clusterName, ruleExtraLabels, ruleNamespaceLabels string
+	restorer := &restore.Restorer{Out: stdout}
+	flags.StringVar(&clientName, "client", "", "Name of the ClientConfig whose resources are restored.")
+	flags.StringVar(&clientNamespace, "namespace", "",
+		"Namespace of the ClientConfig, only needed if several ClientConfigs share the name.")
+	flags.StringVar(&tenantID, "tenant", "", "Restore only this tenant. All tenants of the client if empty.")
+	flags.StringVar(&kubeconfig, "kubeconfig", "", "Path to the kubeconfig file. The default loading rules if empty.")
+	flags.StringVar(&kubeContext, "context", "", "Context of the kubeconfig to use. The current context if empty.")
+	flags.StringVar(&globalValuesFrom, "global-values-from", "",
+		"ConfigMap in the form namespace/name whose keys are available as [[ .Global.KEY ]].")
+	flags.StringVar(&clusterName, "cluster-name", "", "Identifier of the cluster, available as [[ .Meta.Cluster ]].")
+	flags.StringVar(&ruleExtraLabels, "rule-extra-labels", "",
+		"Comma-separated key=value labels added to every alerting rule.")
+	flags.StringVar(&ruleNamespaceLabels, "rule-namespace-labels", "",
+		"Comma-separated labels of the namespace of a PrometheusRule added to every of its alerting rules.")
+	flags.DurationVar(&restorer.VerifyTimeout, "verify-timeout", restore.DefaultVerifyTimeout,
+		"Time to wait until each pushed configuration or rule group is returned by Mimir.")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if clientName == "" {
+		_, _ = fmt.Fprintln(stderr, "--client is required")
+		flags.Usage()
+		return 2
+	}
+	staticLabels, err := utils.ParseLabels(ruleExtraLabels)
+	if err != nil {
+		_, _ = fmt.Fprintf(stderr, "invalid --rule-extra-labels: %v\n", err)
+		return 2
+	}
+
+	config, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+		&clientcmd.ClientConfigLoadingRules{ExplicitPath: kubeconfig},
+		&clientcmd.ConfigOverrides{CurrentContext: kubeContext},
+	).ClientConfig()
+	if err != nil {
+		_, _ = fmt.Fprintf(stderr, "loading kubeconfig: %v\n", err)
+		return 1
+	}
+	// The manager registers PrometheusRules only at startup, after the subcommands
+	if err := monitoringv1.AddToScheme(scheme); err != nil {
+		_, _ = fmt.Fprintln(stderr, err)
+		return 1
+	}
+	reader, err := client.New(config, client.Options{Scheme: scheme})
+	if err != nil {
+		_, _ = fmt.Fprintf(stderr, "creating Kubernetes client: %v\n", err)
+		return 1
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	clientConfig, err := findClientConfig(ctx, reader, clientName, clientNamespace)
+	if err != nil {
+		_, _ = fmt.Fprintln(stderr, err)
+		return 1
+	}
+	if clientConfig.Spec.Type == openawarenessv1beta1.Prometheus {
+		_, _ = fmt.Fprintf(stderr, "ClientConfig %s is of type Prometheus, only Mimir can be restored\n", clientName)
+		return 1
+	}
+	clientCache := clients.NewRulerClientCache()
+	clientCache.Reader = reader
+	remote, err := clientCache.GetOrCreateMimirClient(ctx, clientConfig)
+	if err != nil {
+		_, _ = fmt.Fprintf(stderr, "connecting to ClientConfig %s: %v\n", clientName, err)
+		return 1
+	}
+
+	if globalValuesFrom != "" {
+		configMap, err := utils.ParseNamespacedName(globalValuesFrom)
+		if err != nil {
+			_, _ = fmt.Fprintf(stderr, "invalid --global-values-from: %v\n", err)
+			return 2
+		}
+		restorer.GlobalValues = &utils.GlobalValues{Reader: reader, ConfigMap: configMap}
+	}
+	restorer.Reader = reader
+	restorer.TemplateSources = &templatesource.Fetcher{Reader: reader}
+	restorer.Remote = remote
+	restorer.ClientName = clientName
+	restorer.TenantID = tenantID
+	restorer.ClusterName = clusterName
+	restorer.ExtraLabels = &utils.ExtraLabels{
+		Static:          staticLabels,
+		NamespaceLabels: policy.ParseList(ruleNamespaceLabels),
+		Reader:          reader,
+	}
+
+	summary, err := restorer.Run(ctx, ctrl.Log.WithName(restoreCommand))
+	_, _ = fmt.Fprintf(stdout, "restored %d Alertmanager configurations and %d rule groups of %s\n",
+		summary.AlertmanagerConfigs, summary.RuleGroups, restorer)
+	if err != nil {
+		_, _ = fmt.Fprintln(stderr, err)
+		return 1
+	}
+	return 0
+}
+
+// findClientConfig gets the ClientConfig with the name, in the namespace if not empty.
+// Returns an error if none or several match.
+func findClientConfig(
+	ctx context.Context,
+	reader client.Reader,
+	name, namespace string,
+) (*openawarenessv1beta1.ClientConfig, error) {
+	list := &openawarenessv1beta1.ClientConfigList{}
+	if err := reader.List(ctx, list, client.InNamespace(namespace)); err != nil {
+		return nil, fmt.Errorf("listing ClientConfigs: %w", err)
+	}
+	var found *openawarenessv1beta1.ClientConfig
+	for i := range list.Items {
+		if list.Items[i].Name != name {
+			continue
+		}
+		if found != nil {
+			return nil, errors.New("several ClientConfigs are named " + name + ", use --namespace")
+		}
+		found = &list.Items[i]
+	}
+	if found == nil {
+		return nil, fmt.Errorf("ClientConfig %s not found", name)
+	}
+	return found, nil
+}
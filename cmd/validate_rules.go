@@ -0,0 +1,54 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/syndlex/openawareness-controller/internal/render"
+)
+
+// validateRulesCommand is the subcommand validating PrometheusRule manifests offline
+const validateRulesCommand = "validate-rules"
+
+// runValidateRules implements `manager validate-rules -f rules.yaml`: it runs the conversion
+// and validation performed at reconcile time against local PrometheusRule manifests and
+// prints every problem found.
+// Returns the exit code of the command, non-zero if any problem was found.
+func runValidateRules(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet(validateRulesCommand, flag.ContinueOnError)
+	flags.SetOutput(stderr)
+
+	var files fileList
+	flags.Var(&files, "f", "File containing PrometheusRules to validate. Can be repeated.")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if len(files) == 0 {
+		_, _ = fmt.Fprintln(stderr, "-f is required")
+		flags.Usage()
+		return 2
+	}
+
+	exitCode := 0
+	for _, file := range files {
+		manifest, err := os.ReadFile(file)
+		if err != nil {
+			_, _ = fmt.Fprintln(stderr, err)
+			exitCode = 1
+			continue
+		}
+		problems, err := render.ValidateRules(manifest)
+		if err != nil {
+			_, _ = fmt.Fprintf(stderr, "%s: %v\n", file, err)
+			exitCode = 1
+			continue
+		}
+		for _, problem := range problems {
+			_, _ = fmt.Fprintf(stdout, "%s: %s\n", file, problem)
+			exitCode = 1
+		}
+	}
+	return exitCode
+}
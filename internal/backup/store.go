@@ -0,0 +1,314 @@
+// Package backup keeps the last successfully pushed state of every Mimir tenant in
+// controller-owned Secrets, so it can be pushed again after Mimir lost its data.
+package backup
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/prometheus/prometheus/model/rulefmt"
+	"gopkg.in/yaml.v3"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	openawarenessv1beta1 "github.com/syndlex/openawareness-controller/api/openawareness/v1beta1"
+	"github.com/syndlex/openawareness-controller/internal/clients"
+	"github.com/syndlex/openawareness-controller/internal/controller/utils"
+)
+
+const (
+	// BackupLabel marks the Secrets holding backups
+	BackupLabel = "openawareness.io/backup"
+	// ClientAnnotation records the ClientConfig of a backup Secret as namespace/name
+	ClientAnnotation = "openawareness.io/backup-client"
+
+	// alertmanagerKey is the Secret key of the Alertmanager configuration of the tenant
+	alertmanagerKey = "alertmanager.yaml.gz"
+	// rulesKeyPrefix prefixes the Secret keys of the rule groups of a PrometheusRule,
+	// followed by its namespace and name
+	rulesKeyPrefix = "rules."
+	// keySuffix ends the Secret keys of rule groups
+	keySuffix = ".yaml.gz"
+)
+
+// alertmanagerBackup is the stored Alertmanager configuration of a tenant
+type alertmanagerBackup struct {
+	Config    string            `yaml:"config"`
+	Templates map[string]string `yaml:"templates,omitempty"`
+}
+
+// rulesBackup is the stored rule groups of a PrometheusRule
+type rulesBackup struct {
+	// Namespace is the Mimir rule namespace the groups were pushed to
+	Namespace string              `yaml:"namespace"`
+	Groups    []rulefmt.RuleGroup `yaml:"groups"`
+}
+
+// Store writes the last successfully pushed Alertmanager configuration and rule groups of
+// every Mimir tenant to a Secret in Namespace, one Secret per ClientConfig and tenant, and
+// pushes them again on Restore. Entries are removed when the controller deletes the remote
+// state, so the backup outlives resources whose remote state was kept.
+// A nil Store keeps no backups.
+type Store struct {
+	// Client reads and writes the backup Secrets
+	Client client.Client
+	// Reader reads the backup Secrets before they are updated, bypassing the cache which may
+	// not hold the latest write yet. Client is used if nil.
+	Reader client.Reader
+	// Namespace is the namespace of the backup Secrets
+	Namespace string
+
+	// mu serializes the updates of the parallel reconcile workers
+	mu sync.Mutex
+}
+
+// Summary counts the state pushed by Restore.
+type Summary struct {
+	// Tenants is the number of tenants restored
+	Tenants int
+	// AlertmanagerConfigs is the number of Alertmanager configurations pushed
+	AlertmanagerConfigs int
+	// RuleGroups is the number of rule groups pushed
+	RuleGroups int
+}
+
+// SaveAlertmanagerConfig stores the Alertmanager configuration and template files pushed
+// for a tenant.
+func (s *Store) SaveAlertmanagerConfig(
+	ctx context.Context,
+	clientConfig *openawarenessv1beta1.ClientConfig,
+	tenantID, config string,
+	templates map[string]string,
+) error {
+	if s == nil {
+		return nil
+	}
+	data, err := encode(alertmanagerBackup{Config: config, Templates: templates})
+	if err != nil {
+		return err
+	}
+	return s.update(ctx, clientConfig, tenantID, func(secret *corev1.Secret) {
+		secret.Data[alertmanagerKey] = data
+	})
+}
+
+// RemoveAlertmanagerConfig removes the Alertmanager configuration of a tenant.
+func (s *Store) RemoveAlertmanagerConfig(
+	ctx context.Context,
+	clientConfig *openawarenessv1beta1.ClientConfig,
+	tenantID string,
+) error {
+	if s == nil {
+		return nil
+	}
+	return s.update(ctx, clientConfig, tenantID, func(secret *corev1.Secret) {
+		delete(secret.Data, alertmanagerKey)
+	})
+}
+
+// SaveRuleGroups stores the rule groups of the owner PrometheusRule pushed to the Mimir rule
+// namespace of a tenant, replacing the groups stored before.
+func (s *Store) SaveRuleGroups(
+	ctx context.Context,
+	clientConfig *openawarenessv1beta1.ClientConfig,
+	tenantID string,
+	owner types.NamespacedName,
+	namespace string,
+	groups []rulefmt.RuleGroup,
+) error {
+	if s == nil {
+		return nil
+	}
+	data, err := encode(rulesBackup{Namespace: namespace, Groups: groups})
+	if err != nil {
+		return err
+	}
+	return s.update(ctx, clientConfig, tenantID, func(secret *corev1.Secret) {
+		secret.Data[rulesKey(owner)] = data
+	})
+}
+
+// RemoveRuleGroups removes the rule groups of the owner PrometheusRule from a tenant.
+func (s *Store) RemoveRuleGroups(
+	ctx context.Context,
+	clientConfig *openawarenessv1beta1.ClientConfig,
+	tenantID string,
+	owner types.NamespacedName,
+) error {
+	if s == nil {
+		return nil
+	}
+	return s.update(ctx, clientConfig, tenantID, func(secret *corev1.Secret) {
+		delete(secret.Data, rulesKey(owner))
+	})
+}
+
+// Restore pushes the stored state of every tenant of the ClientConfig through remote: the
+// Alertmanager configuration of a tenant first, then its rule groups.
+// Returns what was pushed, and the first error, which stops the restore.
+func (s *Store) Restore(
+	ctx context.Context,
+	clientConfig *openawarenessv1beta1.ClientConfig,
+	remote clients.AwarenessClient,
+) (Summary, error) {
+	var summary Summary
+	if s == nil {
+		return summary, nil
+	}
+	secrets := &corev1.SecretList{}
+	if err := s.Client.List(ctx, secrets,
+		client.InNamespace(s.Namespace),
+		client.MatchingLabels{BackupLabel: "true"},
+	); err != nil {
+		return summary, fmt.Errorf("listing backup Secrets: %w", err)
+	}
+
+	for _, secret := range secrets.Items {
+		if secret.Annotations[ClientAnnotation] != utils.OwnerReference(clientConfig) {
+			continue
+		}
+		tenantID := secret.Annotations[utils.MimirTenantAnnotation]
+		if data, ok := secret.Data[alertmanagerKey]; ok {
+			var am alertmanagerBackup
+			if err := decode(data, &am); err != nil {
+				return summary, fmt.Errorf("reading Alertmanager configuration of tenant %s: %w", tenantID, err)
+			}
+			if err := remote.CreateAlertmanagerConfig(ctx, am.Config, am.Templates, tenantID); err != nil {
+				return summary, fmt.Errorf("restoring Alertmanager configuration of tenant %s: %w", tenantID, err)
+			}
+			summary.AlertmanagerConfigs++
+		}
+		for _, key := range slices.Sorted(maps.Keys(secret.Data)) {
+			if !strings.HasPrefix(key, rulesKeyPrefix) {
+				continue
+			}
+			var rules rulesBackup
+			if err := decode(secret.Data[key], &rules); err != nil {
+				return summary, fmt.Errorf("reading %s of tenant %s: %w", key, tenantID, err)
+			}
+			for _, group := range rules.Groups {
+				if err := remote.CreateRuleGroup(ctx, rules.Namespace, group, tenantID); err != nil {
+					return summary, fmt.Errorf("restoring rule group %s in namespace %s of tenant %s: %w",
+						group.Name, rules.Namespace, tenantID, err)
+				}
+				summary.RuleGroups++
+			}
+		}
+		summary.Tenants++
+	}
+	return summary, nil
+}
+
+// update applies change to the backup Secret of a tenant, creating it if needed, and
+// deletes the Secret once it holds no entries. Conflicting writes are retried, as are
+// creations of a Secret that was created since it was read.
+func (s *Store) update(
+	ctx context.Context,
+	clientConfig *openawarenessv1beta1.ClientConfig,
+	tenantID string,
+	change func(secret *corev1.Secret),
+) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := types.NamespacedName{Namespace: s.Namespace, Name: SecretName(clientConfig, tenantID)}
+	reader := s.Reader
+	if reader == nil {
+		reader = s.Client
+	}
+	return retry.OnError(retry.DefaultRetry, isStale, func() error {
+		secret := &corev1.Secret{}
+		err := reader.Get(ctx, key, secret)
+		exists := err == nil
+		if apierrors.IsNotFound(err) {
+			secret = &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      key.Name,
+					Namespace: key.Namespace,
+					Labels:    map[string]string{BackupLabel: "true"},
+					Annotations: map[string]string{
+						ClientAnnotation:            utils.OwnerReference(clientConfig),
+						utils.MimirTenantAnnotation: tenantID,
+					},
+				},
+				Type: corev1.SecretTypeOpaque,
+			}
+		} else if err != nil {
+			return fmt.Errorf("getting backup Secret %s: %w", key, err)
+		}
+		if secret.Data == nil {
+			secret.Data = map[string][]byte{}
+		}
+		change(secret)
+
+		switch {
+		case !exists && len(secret.Data) == 0:
+			return nil
+		case !exists:
+			return s.Client.Create(ctx, secret)
+		case len(secret.Data) == 0:
+			return client.IgnoreNotFound(s.Client.Delete(ctx, secret))
+		default:
+			return s.Client.Update(ctx, secret)
+		}
+	})
+}
+
+// isStale reports whether err is caused by a write based on an outdated read.
+func isStale(err error) bool {
+	return apierrors.IsConflict(err) || apierrors.IsAlreadyExists(err)
+}
+
+// SecretName returns the name of the backup Secret of a ClientConfig and tenant.
+func SecretName(clientConfig *openawarenessv1beta1.ClientConfig, tenantID string) string {
+	hash := fnv.New64a()
+	_, _ = hash.Write([]byte(utils.OwnerReference(clientConfig) + "/" + tenantID))
+	return fmt.Sprintf("openawareness-backup-%x", hash.Sum64())
+}
+
+// rulesKey returns the Secret key of the rule groups of a PrometheusRule.
+func rulesKey(owner types.NamespacedName) string {
+	return rulesKeyPrefix + owner.Namespace + "." + owner.Name + keySuffix
+}
+
+// encode returns value as gzip compressed YAML.
+func encode(value any) ([]byte, error) {
+	raw, err := yaml.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	if _, err := writer.Write(raw); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// decode reads gzip compressed YAML into value.
+func decode(data []byte, value any) error {
+	reader, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	raw, err := io.ReadAll(reader)
+	if err != nil {
+		return err
+	}
+	return yaml.Unmarshal(raw, value)
+}
@@ -0,0 +1,126 @@
+package backup
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/prometheus/model/rulefmt"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	openawarenessv1beta1 "github.com/syndlex/openawareness-controller/api/openawareness/v1beta1"
+	"github.com/syndlex/openawareness-controller/internal/clients"
+)
+
+func TestStoreRestore(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatalf("adding scheme: %v", err)
+	}
+	store := &Store{Client: fake.NewClientBuilder().WithScheme(scheme).Build(), Namespace: "backup"}
+	mimir := &openawarenessv1beta1.ClientConfig{ObjectMeta: metav1.ObjectMeta{Name: "mimir", Namespace: "team"}}
+	other := &openawarenessv1beta1.ClientConfig{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "team"}}
+	ctx := context.Background()
+
+	groups := []rulefmt.RuleGroup{{Name: "a"}, {Name: "b"}}
+	checkout := types.NamespacedName{Namespace: "shop", Name: "checkout"}
+	if err := store.SaveAlertmanagerConfig(ctx, mimir, "team-a", "route: {}", map[string]string{"t.tmpl": "x"}); err != nil {
+		t.Fatalf("SaveAlertmanagerConfig() error = %v", err)
+	}
+	if err := store.SaveRuleGroups(ctx, mimir, "team-a", checkout, "shop", groups); err != nil {
+		t.Fatalf("SaveRuleGroups() error = %v", err)
+	}
+	if err := store.SaveRuleGroups(ctx, mimir, "team-a", types.NamespacedName{Namespace: "shop", Name: "cart"},
+		"shop-alerts", groups[:1]); err != nil {
+		t.Fatalf("SaveRuleGroups() error = %v", err)
+	}
+	if err := store.SaveRuleGroups(ctx, mimir, "team-b", checkout, "shop", groups); err != nil {
+		t.Fatalf("SaveRuleGroups() error = %v", err)
+	}
+	if err := store.SaveRuleGroups(ctx, other, "team-a", checkout, "shop", groups); err != nil {
+		t.Fatalf("SaveRuleGroups() error = %v", err)
+	}
+	// Deleted rules are no longer restored, emptied Secrets are deleted
+	if err := store.RemoveRuleGroups(ctx, mimir, "team-b", checkout); err != nil {
+		t.Fatalf("RemoveRuleGroups() error = %v", err)
+	}
+	secret := &corev1.Secret{}
+	err := store.Client.Get(ctx, types.NamespacedName{Namespace: "backup", Name: SecretName(mimir, "team-b")}, secret)
+	if err == nil {
+		t.Error("expected the emptied backup Secret of team-b to be deleted")
+	}
+
+	remote := clients.NewMockAwarenessClient()
+	summary, err := store.Restore(ctx, mimir, remote)
+	if err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	want := Summary{Tenants: 1, AlertmanagerConfigs: 1, RuleGroups: 3}
+	if summary != want {
+		t.Errorf("expected %+v restored, got %+v", want, summary)
+	}
+	if config, _, _ := remote.GetAlertmanagerConfig(ctx, "team-a"); config != "route: {}" {
+		t.Errorf("expected the Alertmanager configuration of team-a to be restored, got %q", config)
+	}
+	if group, _ := remote.GetRuleGroup(ctx, "shop-alerts", "a", "team-a"); group == nil {
+		t.Error("expected the rule groups of cart to be restored to their rule namespace")
+	}
+
+	var nilStore *Store
+	if err := nilStore.SaveRuleGroups(ctx, mimir, "team-a", checkout, "shop", groups); err != nil {
+		t.Errorf("expected a nil Store to keep no backup, got %v", err)
+	}
+}
+
+// staleReader misses the Secrets for the first reads, like a cache that has not seen a
+// creation yet
+type staleReader struct {
+	client.Reader
+	misses int
+}
+
+func (r *staleReader) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	if r.misses > 0 {
+		r.misses--
+		return apierrors.NewNotFound(corev1.Resource("secrets"), key.Name)
+	}
+	return r.Reader.Get(ctx, key, obj, opts...)
+}
+
+func TestStoreStaleRead(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatalf("adding scheme: %v", err)
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).Build()
+	store := &Store{Client: c, Namespace: "backup"}
+	mimir := &openawarenessv1beta1.ClientConfig{ObjectMeta: metav1.ObjectMeta{Name: "mimir", Namespace: "team"}}
+	ctx := context.Background()
+
+	groups := []rulefmt.RuleGroup{{Name: "a"}}
+	checkout := types.NamespacedName{Namespace: "shop", Name: "checkout"}
+	cart := types.NamespacedName{Namespace: "shop", Name: "cart"}
+	if err := store.SaveRuleGroups(ctx, mimir, "team-a", checkout, "shop", groups); err != nil {
+		t.Fatalf("SaveRuleGroups() error = %v", err)
+	}
+
+	// The Secret created before is missed, its creation fails and is retried
+	store.Reader = &staleReader{Reader: c, misses: 1}
+	if err := store.SaveRuleGroups(ctx, mimir, "team-a", cart, "shop", groups); err != nil {
+		t.Fatalf("SaveRuleGroups() error = %v", err)
+	}
+	secret := &corev1.Secret{}
+	if err := c.Get(ctx, types.NamespacedName{Namespace: "backup", Name: SecretName(mimir, "team-a")}, secret); err != nil {
+		t.Fatalf("getting backup Secret: %v", err)
+	}
+	for _, owner := range []types.NamespacedName{checkout, cart} {
+		if _, ok := secret.Data[rulesKey(owner)]; !ok {
+			t.Errorf("expected the rule groups of %s to be stored", owner)
+		}
+	}
+}
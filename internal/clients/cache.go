@@ -5,25 +5,44 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
 
 	"github.com/grafana/dskit/crypto/tls"
 	"github.com/prometheus/prometheus/model/rulefmt"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/tools/record"
+	k8sClient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	openawarenessv1beta1 "github.com/syndlex/openawareness-controller/api/openawareness/v1beta1"
+	"github.com/syndlex/openawareness-controller/internal/events"
+	"github.com/syndlex/openawareness-controller/internal/metrics"
 	"github.com/syndlex/openawareness-controller/internal/mimir"
 )
 
 // RulerClientCacheInterface defines the interface for managing ruler clients.
 // It provides methods to add, remove, and retrieve clients for both Mimir and Prometheus.
 type RulerClientCacheInterface interface {
-	AddMimirClient(ctx context.Context, address string, name string) error
+	AddMimirClient(ctx context.Context, clientConfig *openawarenessv1beta1.ClientConfig) error
 	AddPromClient(ctx context.Context, address string, name string) error
-	RemoveClient(name string)
-	GetOrCreateMimirClient(
-		ctx context.Context,
-		address string,
-		clientName string,
-	) (AwarenessClient, error)
+	RemoveClient(namespace, name string)
+	GetOrCreateMimirClient(ctx context.Context, clientConfig *openawarenessv1beta1.ClientConfig) (AwarenessClient, error)
+	CheckHealth(ctx context.Context, clientConfig *openawarenessv1beta1.ClientConfig) error
+}
+
+// HealthChecker defines the health check of the connectivity of a client to its endpoint.
+type HealthChecker interface {
+	HealthCheck(ctx context.Context) error
 }
 
+// Ensure the Mimir client checks its health
+var _ HealthChecker = (*mimir.Client)(nil)
+
 // AwarenessClient defines the interface for interacting with rule and alert APIs.
 // It abstracts the operations for both Mimir and Prometheus clients.
 // All methods accept a tenantID parameter for multi-tenant isolation.
@@ -32,17 +51,121 @@ type AwarenessClient interface {
 	DeleteRuleGroup(ctx context.Context, namespace, groupName string, tenantID string) error
 	GetRuleGroup(ctx context.Context, namespace, groupName string, tenantID string) (*rulefmt.RuleGroup, error)
 	ListRules(ctx context.Context, namespace string, tenantID string) (map[string][]rulefmt.RuleGroup, error)
+	ListRuleNamespaces(ctx context.Context, tenantID string) ([]string, error)
+	WalkRules(ctx context.Context, tenantID string, fn func(namespace string, groups []rulefmt.RuleGroup) error) error
 	DeleteNamespace(ctx context.Context, namespace string, tenantID string) error
 	CreateAlertmanagerConfig(ctx context.Context, cfg string, templates map[string]string, tenantID string) error
 	DeleteAlermanagerConfig(ctx context.Context, tenantID string) error
 	GetAlertmanagerConfig(ctx context.Context, tenantID string) (string, map[string]string, error)
 	GetAlertmanagerStatus(ctx context.Context, tenantID string) (string, error)
+	GetNotificationFailures(ctx context.Context, tenantID string) (map[string]float64, error)
+}
+
+// QueryClient defines read-only access to the Mimir query API.
+// Reads may span several tenants at once through Mimir tenant federation.
+type QueryClient interface {
+	Query(ctx context.Context, query string, tenantIDs []string) (*http.Response, error)
+	QueryHasSeries(ctx context.Context, query string, tenantIDs []string) (bool, error)
+	ActiveRuleGroups(ctx context.Context, tenantID string) ([]mimir.RuleGroupState, error)
+}
+
+// Ensure the Mimir client provides read access
+var _ QueryClient = (*mimir.Client)(nil)
+
+// FederatedRuleClient defines the push of rule groups evaluated against the data of other
+// tenants through Mimir tenant federation for rules.
+type FederatedRuleClient interface {
+	CreateFederatedRuleGroup(
+		ctx context.Context, namespace string, rg rulefmt.RuleGroup, sourceTenants []string, tenantID string,
+	) error
+}
+
+// Ensure the Mimir client pushes federated rule groups
+var _ FederatedRuleClient = (*mimir.Client)(nil)
+
+// TenantLister defines access to the tenants of Mimir through its admin API.
+type TenantLister interface {
+	ListTenants(ctx context.Context) ([]string, error)
+}
+
+// Ensure the Mimir client lists tenants
+var _ TenantLister = (*mimir.Client)(nil)
+
+// CircuitBreakerClient defines access to the circuit breaker refusing requests after
+// consecutive server errors.
+type CircuitBreakerClient interface {
+	CircuitOpenUntil() time.Time
 }
 
+// Ensure the Mimir client reports its circuit breaker
+var _ CircuitBreakerClient = (*mimir.Client)(nil)
+
 // RulerClientCache implements RulerClientCacheInterface and manages a cache of ruler clients.
-// It stores clients in a map keyed by client name - one client per Mimir instance handles all tenants.
+// It stores clients in a map keyed by the namespace and name of their ClientConfig - one client per
+// Mimir instance handles all tenants.
+// It is safe for concurrent use by parallel reconcile workers.
 type RulerClientCache struct {
+	mu      sync.RWMutex
 	clients map[string]AwarenessClient
+	// Recorder emits events on ClientConfigs, e.g. when a client certificate is reloaded.
+	// Events are not emitted if nil.
+	Recorder record.EventRecorder
+	// Transport tunes the connection pooling of the created Mimir clients
+	Transport mimir.TransportConfig
+	// CircuitBreaker configures the circuit breaker of each created Mimir client, its state
+	// is exposed as metric by ClientConfig namespace and name
+	CircuitBreaker mimir.CircuitBreakerConfig
+	// CircuitBreakerChanged receives the ClientConfigs whose circuit breaker opened or closed,
+	// dropped if the channel is full. Not sent if nil.
+	CircuitBreakerChanged chan<- event.GenericEvent
+	// Tape records the requests of the created Mimir clients, disabled if nil
+	Tape *mimir.Tape
+	// Reader reads the CA ConfigMaps and HMAC key Secrets of ClientConfigs, ClientConfigs
+	// referencing one fail to connect if nil
+	Reader k8sClient.Reader
+	// caBundles holds the CA bundle read from the ConfigMap of each client when it was created
+	caBundles map[string]string
+	// hmacKeys holds the HMAC key read from the Secret of each client when it was created
+	hmacKeys map[string]string
+	// generations holds the generation of the ClientConfig each client was created for
+	generations map[string]int64
+	// infos describes each cached client, see Clients
+	infos map[string]ClientInfo
+	// creating serializes the creation of the client of each key, so parallel workers missing
+	// the cache create it once instead of closing each other's client
+	creating map[string]*sync.Mutex
+	// Identity is sent in mimir.InstanceHeader on every request, no header is sent if empty
+	Identity string
+}
+
+// instanceHeaders returns the extra headers identifying this installation, nil without Identity.
+func (e *RulerClientCache) instanceHeaders() map[string]string {
+	if e.Identity == "" {
+		return nil
+	}
+	return map[string]string{mimir.InstanceHeader: e.Identity}
+}
+
+// ClientInfo describes a client of the RulerClientCache. Clients are cached by ClientConfig
+// name, each serving all tenants of its ClientConfig through the X-Scope-OrgID header.
+type ClientInfo struct {
+	// Name is the name of the ClientConfig
+	Name string `json:"name" yaml:"name"`
+	// Namespace is the namespace of the ClientConfig the client was created for
+	Namespace string `json:"namespace" yaml:"namespace"`
+	// Address is the address of the endpoint
+	Address string `json:"address" yaml:"address"`
+	// Proxy is the URL of the proxy the endpoint is reached through, empty if none
+	Proxy string `json:"proxy,omitempty" yaml:"proxy,omitempty"`
+	// Created is the time the client was created
+	Created time.Time `json:"created" yaml:"created"`
+	// LastHealthCheck is the time of the last health check of the client
+	LastHealthCheck time.Time `json:"lastHealthCheck" yaml:"lastHealthCheck"`
+	// HealthCheckError is the error of the last health check, empty if it succeeded
+	HealthCheckError string `json:"healthCheckError,omitempty" yaml:"healthCheckError,omitempty"`
+	// CircuitOpenUntil is the time until which the circuit breaker of the client refuses
+	// requests, nil if it is closed
+	CircuitOpenUntil *time.Time `json:"circuitOpenUntil,omitempty" yaml:"circuitOpenUntil,omitempty"`
 }
 
 // Ensure RulerClientCache implements RulerClientCacheInterface
@@ -51,7 +174,12 @@ var _ RulerClientCacheInterface = (*RulerClientCache)(nil)
 // NewRulerClientCache creates and returns a new RulerClientCache instance.
 func NewRulerClientCache() *RulerClientCache {
 	return &RulerClientCache{
-		clients: map[string]AwarenessClient{},
+		clients:     map[string]AwarenessClient{},
+		caBundles:   map[string]string{},
+		hmacKeys:    map[string]string{},
+		generations: map[string]int64{},
+		infos:       map[string]ClientInfo{},
+		creating:    map[string]*sync.Mutex{},
 	}
 }
 
@@ -60,17 +188,51 @@ func NewRulerClientCache() *RulerClientCache {
 // The client is created without a tenant ID - tenant isolation is achieved
 // via the X-Scope-OrgID header on each request (passed via tenantID parameter).
 // Returns an error if client creation or health check fails.
-func (e *RulerClientCache) AddMimirClient(ctx context.Context, address string, name string) error {
+func (e *RulerClientCache) AddMimirClient(ctx context.Context, clientConfig *openawarenessv1beta1.ClientConfig) error {
+	lock := e.creationLock(cacheKey(clientConfig.Namespace, clientConfig.Name))
+	lock.Lock()
+	defer lock.Unlock()
+	return e.addMimirClient(ctx, clientConfig)
+}
+
+// addMimirClient creates a Mimir client and adds it to the cache, see AddMimirClient. The
+// caller must hold the creationLock of the client.
+func (e *RulerClientCache) addMimirClient(ctx context.Context, clientConfig *openawarenessv1beta1.ClientConfig) error {
+	spec := clientConfig.Spec
+	caBundle, err := e.caBundle(ctx, clientConfig)
+	if err != nil {
+		return err
+	}
+	hmacKey, err := e.hmacKey(ctx, clientConfig)
+	if err != nil {
+		return err
+	}
+	middlewares, err := requestMiddlewares(spec.Auth, hmacKey)
+	if err != nil {
+		return err
+	}
+	circuitBreaker := e.CircuitBreaker
+	circuitBreaker.OnChange = e.circuitBreakerChanged(clientConfig)
 	// Create client without tenant ID - tenant will be passed per-request via tenantID parameter
 	client, err := mimir.New(ctx, mimir.Config{
-		User:            "",
-		Key:             "",
-		Address:         address,
-		TLS:             tls.ClientConfig{},
-		UseLegacyRoutes: false,
-		MimirHTTPPrefix: "",
-		AuthToken:       "",
-		ExtraHeaders:    nil,
+		User:                "",
+		Key:                 "",
+		Address:             spec.Address,
+		TLS:                 tlsConfig(spec.TLS),
+		UseLegacyRoutes:     false,
+		MimirHTTPPrefix:     "",
+		AuthToken:           "",
+		ExtraHeaders:        e.instanceHeaders(),
+		TokenExchange:       tokenExchangeConfig(spec.Auth),
+		OnCertificateReload: e.certificateReloaded(clientConfig),
+		Transport:           e.Transport,
+		GzipRequests:        spec.Compression == openawarenessv1beta1.CompressionGzip,
+		CABundle:            []byte(caBundle),
+		Tape:                e.Tape,
+		PathPrefix:          spec.PathPrefix,
+		CircuitBreaker:      circuitBreaker,
+		Middlewares:         middlewares,
+		ProxyURL:            proxyURL(spec.Proxy),
 	})
 	if err != nil {
 		return fmt.Errorf("creating Mimir client: %w", err)
@@ -78,42 +240,344 @@ func (e *RulerClientCache) AddMimirClient(ctx context.Context, address string, n
 
 	// Perform health check to verify connectivity
 	if err := client.HealthCheck(ctx); err != nil {
+		client.Close()
+		e.healthChecked(cacheKey(clientConfig.Namespace, clientConfig.Name), err)
 		return fmt.Errorf("health check failed: %w", err)
 	}
 
-	e.clients[name] = client
+	now := time.Now()
+	key := cacheKey(clientConfig.Namespace, clientConfig.Name)
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.removeClient(key)
+	e.clients[key] = client
+	e.caBundles[key] = caBundle
+	e.hmacKeys[key] = hmacKey
+	e.generations[key] = clientConfig.Generation
+	e.infos[key] = ClientInfo{
+		Name:            clientConfig.Name,
+		Namespace:       clientConfig.Namespace,
+		Address:         spec.Address,
+		Proxy:           proxyURL(spec.Proxy),
+		Created:         now,
+		LastHealthCheck: now,
+	}
+	metrics.SetCachedClient(clientConfig.Namespace, clientConfig.Name, now, now, true)
 	return nil
 }
 
+// healthChecked records the health check of the cached client of key, err is nil if it
+// succeeded. A failed check of a client created again for a cached client is recorded for
+// the cached client, which stays cached.
+func (e *RulerClientCache) healthChecked(key string, err error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	info, ok := e.infos[key]
+	if !ok {
+		return
+	}
+	info.LastHealthCheck = time.Now()
+	info.HealthCheckError = ""
+	if err != nil {
+		info.HealthCheckError = err.Error()
+	}
+	e.infos[key] = info
+	metrics.SetCachedClient(info.Namespace, info.Name, info.Created, info.LastHealthCheck, err == nil)
+}
+
+// Clients describes the cached clients, sorted by namespace and name.
+func (e *RulerClientCache) Clients() []ClientInfo {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	infos := make([]ClientInfo, 0, len(e.infos))
+	for _, key := range slices.Sorted(maps.Keys(e.infos)) {
+		info := e.infos[key]
+		if breaker, ok := e.clients[key].(CircuitBreakerClient); ok {
+			if openUntil := breaker.CircuitOpenUntil(); !openUntil.IsZero() {
+				info.CircuitOpenUntil = &openUntil
+			}
+		}
+		infos = append(infos, info)
+	}
+	return infos
+}
+
 // GetOrCreateMimirClient gets an existing client or creates a new one.
-// The cache key is simply the clientName - one client handles all tenants for that Mimir instance.
+// Clients are cached by the namespace and name of their ClientConfig - one client handles all
+// tenants for that Mimir instance, same-named ClientConfigs of other namespaces have their own.
 // Tenant isolation is achieved via the X-Scope-OrgID header on each request (namespace parameter).
+// A cached client is created again, closing the old one, if the spec of its ClientConfig, the
+// CA bundle of its ConfigMap or its HMAC key changed. Concurrent callers missing the cache for
+// the same ClientConfig create a single client.
 // Returns the cached or newly created client, or an error if creation fails.
 func (e *RulerClientCache) GetOrCreateMimirClient(
 	ctx context.Context,
-	address string,
-	clientName string,
+	clientConfig *openawarenessv1beta1.ClientConfig,
 ) (AwarenessClient, error) {
-	// Check if client already exists using simple client name
-	if client, exists := e.clients[clientName]; exists {
-		return client, nil
+	key := cacheKey(clientConfig.Namespace, clientConfig.Name)
+	if client, err := e.currentClient(ctx, key, clientConfig); client != nil || err != nil {
+		return client, err
+	}
+
+	lock := e.creationLock(key)
+	lock.Lock()
+	defer lock.Unlock()
+	// Another worker may have created the client while this one waited
+	if client, err := e.currentClient(ctx, key, clientConfig); client != nil || err != nil {
+		return client, err
 	}
 
 	// Create new client without tenant ID - tenant passed per-request
-	if err := e.AddMimirClient(ctx, address, clientName); err != nil {
+	if err := e.addMimirClient(ctx, clientConfig); err != nil {
 		return nil, fmt.Errorf("creating Mimir client: %w", err)
 	}
 
-	return e.clients[clientName], nil
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.clients[key], nil
+}
+
+// currentClient returns the cached client of key if it was created for the current spec,
+// CA bundle and HMAC key of clientConfig, nil if it has to be created.
+func (e *RulerClientCache) currentClient(
+	ctx context.Context,
+	key string,
+	clientConfig *openawarenessv1beta1.ClientConfig,
+) (AwarenessClient, error) {
+	e.mu.RLock()
+	client, exists := e.clients[key]
+	cachedBundle := e.caBundles[key]
+	cachedKey := e.hmacKeys[key]
+	cachedGeneration := e.generations[key]
+	e.mu.RUnlock()
+	// The generation changes with every change of the spec, e.g. address, TLS or proxy
+	if !exists || cachedGeneration != clientConfig.Generation {
+		return nil, nil
+	}
+	caBundle, err := e.caBundle(ctx, clientConfig)
+	if err != nil {
+		return nil, err
+	}
+	hmacKey, err := e.hmacKey(ctx, clientConfig)
+	if err != nil {
+		return nil, err
+	}
+	if caBundle != cachedBundle || hmacKey != cachedKey {
+		return nil, nil
+	}
+	return client, nil
+}
+
+// creationLock returns the lock serializing the creation of the client of key.
+func (e *RulerClientCache) creationLock(key string) *sync.Mutex {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.creating == nil {
+		e.creating = map[string]*sync.Mutex{}
+	}
+	lock, ok := e.creating[key]
+	if !ok {
+		lock = &sync.Mutex{}
+		e.creating[key] = lock
+	}
+	return lock
+}
+
+// CheckHealth runs the health check of the cached client of a ClientConfig and records it in
+// the ClientInfo and metrics of the client, which stays cached if it fails. Returns an error if
+// no client is cached or it cannot check its health.
+func (e *RulerClientCache) CheckHealth(ctx context.Context, clientConfig *openawarenessv1beta1.ClientConfig) error {
+	key := cacheKey(clientConfig.Namespace, clientConfig.Name)
+	e.mu.RLock()
+	client := e.clients[key]
+	e.mu.RUnlock()
+	checker, ok := client.(HealthChecker)
+	if !ok {
+		return fmt.Errorf("no client with health check cached for ClientConfig %s/%s",
+			clientConfig.Namespace, clientConfig.Name)
+	}
+	err := checker.HealthCheck(ctx)
+	e.healthChecked(key, err)
+	if err != nil {
+		return fmt.Errorf("health check failed: %w", err)
+	}
+	return nil
+}
+
+// cacheKey returns the key of the client of the ClientConfig with the given namespace and name.
+func cacheKey(namespace, name string) string {
+	return namespace + "/" + name
+}
+
+// tlsConfig returns the TLS configuration of a ClientConfig.
+func tlsConfig(clientTLS *openawarenessv1beta1.ClientTLS) tls.ClientConfig {
+	if clientTLS == nil {
+		return tls.ClientConfig{}
+	}
+	return tls.ClientConfig{
+		CAPath:             clientTLS.CAPath,
+		CertPath:           clientTLS.CertPath,
+		KeyPath:            clientTLS.KeyPath,
+		ServerName:         clientTLS.ServerName,
+		InsecureSkipVerify: clientTLS.InsecureSkipVerify,
+	}
+}
+
+// certificateReloaded returns the function emitting a Reloaded event on the ClientConfig
+// when its client certificate is reloaded.
+func (e *RulerClientCache) certificateReloaded(clientConfig *openawarenessv1beta1.ClientConfig) func() {
+	if e.Recorder == nil || clientConfig.Spec.TLS == nil {
+		return nil
+	}
+	// The callback runs long after the reconcile that created the client
+	clientConfig = clientConfig.DeepCopy()
+	return func() {
+		events.Eventf(e.Recorder, clientConfig, events.Reloaded,
+			"Reloaded TLS client certificate from %s", clientConfig.Spec.TLS.CertPath)
+	}
+}
+
+// circuitBreakerChanged returns the function recording the state of the circuit breaker of
+// the client of a ClientConfig as metric and sending the ClientConfig to CircuitBreakerChanged.
+func (e *RulerClientCache) circuitBreakerChanged(clientConfig *openawarenessv1beta1.ClientConfig) func(open bool) {
+	// The callback runs long after the reconcile that created the client
+	clientConfig = clientConfig.DeepCopy()
+	return func(open bool) {
+		metrics.SetCircuitBreakerOpen(clientConfig.Namespace, clientConfig.Name, open)
+		if e.CircuitBreakerChanged == nil {
+			return
+		}
+		select {
+		case e.CircuitBreakerChanged <- event.GenericEvent{Object: clientConfig}:
+		default:
+		}
+	}
+}
+
+// proxyURL returns the URL of the proxy of a ClientConfig, empty if it uses none.
+func proxyURL(proxy *openawarenessv1beta1.ClientProxy) string {
+	if proxy == nil {
+		return ""
+	}
+	return proxy.URL
+}
+
+// tokenExchangeConfig returns the token exchange configuration of a ClientConfig,
+// or nil if it does not use workload identity.
+func tokenExchangeConfig(auth *openawarenessv1beta1.ClientAuth) *mimir.TokenExchangeConfig {
+	if auth == nil || auth.WorkloadIdentity == nil {
+		return nil
+	}
+	tokenPath := auth.WorkloadIdentity.TokenPath
+	if tokenPath == "" {
+		tokenPath = openawarenessv1beta1.DefaultServiceAccountTokenPath
+	}
+	return &mimir.TokenExchangeConfig{
+		TokenURL:         auth.WorkloadIdentity.TokenURL,
+		Audience:         auth.WorkloadIdentity.Audience,
+		SubjectTokenPath: tokenPath,
+	}
 }
 
-// RemoveClient removes a client from the cache by name.
+// RemoveClient removes the client of the ClientConfig with the given namespace and name from the cache.
 // This is typically called when a ClientConfig is deleted.
-func (e *RulerClientCache) RemoveClient(name string) {
-	if e.clients[name] == nil {
+func (e *RulerClientCache) RemoveClient(namespace, name string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.removeClient(cacheKey(namespace, name))
+}
+
+// removeClient closes and removes a client and its metrics, the caller must hold the lock.
+func (e *RulerClientCache) removeClient(key string) {
+	if e.clients[key] == nil {
 		return
 	}
-	delete(e.clients, name)
+	if client, ok := e.clients[key].(*mimir.Client); ok {
+		client.Close()
+	}
+	info := e.infos[key]
+	delete(e.clients, key)
+	delete(e.caBundles, key)
+	delete(e.hmacKeys, key)
+	delete(e.generations, key)
+	delete(e.infos, key)
+	metrics.DeleteCachedClient(info.Namespace, info.Name)
+	metrics.DeleteCircuitBreaker(info.Namespace, info.Name)
+}
+
+// caBundle reads the CA bundle of the caConfigMapRef of a ClientConfig, empty if none is
+// referenced or an optional ConfigMap or key is missing.
+func (e *RulerClientCache) caBundle(
+	ctx context.Context,
+	clientConfig *openawarenessv1beta1.ClientConfig,
+) (string, error) {
+	if clientConfig.Spec.TLS == nil || clientConfig.Spec.TLS.CAConfigMapRef == nil {
+		return "", nil
+	}
+	ref := clientConfig.Spec.TLS.CAConfigMapRef
+	if e.Reader == nil {
+		return "", fmt.Errorf("loading TLS CA bundle from ConfigMap %s: no reader configured", ref.Name)
+	}
+	optional := ref.Optional != nil && *ref.Optional
+	configMap := &corev1.ConfigMap{}
+	err := e.Reader.Get(ctx, k8sClient.ObjectKey{Namespace: clientConfig.Namespace, Name: ref.Name}, configMap)
+	if apierrors.IsNotFound(err) && optional {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("loading TLS CA bundle from ConfigMap %s: %w", ref.Name, err)
+	}
+	bundle, ok := configMap.Data[ref.Key]
+	if !ok && !optional {
+		return "", fmt.Errorf("loading TLS CA bundle: key %s not found in ConfigMap %s", ref.Key, ref.Name)
+	}
+	return bundle, nil
+}
+
+// hmacKey reads the HMAC key of the auth.hmac.secretRef of a ClientConfig, empty if none is
+// referenced or an optional Secret or key is missing.
+func (e *RulerClientCache) hmacKey(
+	ctx context.Context,
+	clientConfig *openawarenessv1beta1.ClientConfig,
+) (string, error) {
+	if clientConfig.Spec.Auth == nil || clientConfig.Spec.Auth.HMAC == nil {
+		return "", nil
+	}
+	ref := clientConfig.Spec.Auth.HMAC.SecretRef
+	if e.Reader == nil {
+		return "", fmt.Errorf("loading HMAC key from Secret %s: no reader configured", ref.Name)
+	}
+	optional := ref.Optional != nil && *ref.Optional
+	secret := &corev1.Secret{}
+	err := e.Reader.Get(ctx, k8sClient.ObjectKey{Namespace: clientConfig.Namespace, Name: ref.Name}, secret)
+	if apierrors.IsNotFound(err) && optional {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("loading HMAC key from Secret %s: %w", ref.Name, err)
+	}
+	key, ok := secret.Data[ref.Key]
+	if !ok && !optional {
+		return "", fmt.Errorf("loading HMAC key: key %s not found in Secret %s", ref.Key, ref.Name)
+	}
+	return string(key), nil
+}
+
+// requestMiddlewares returns the middlewares of the requests of a ClientConfig: the HMAC
+// signer if auth.hmac is set and its key is not empty.
+func requestMiddlewares(auth *openawarenessv1beta1.ClientAuth, hmacKey string) ([]mimir.RequestMiddleware, error) {
+	if auth == nil || auth.HMAC == nil || hmacKey == "" {
+		return nil, nil
+	}
+	signer, err := mimir.HMACSigner(mimir.HMACConfig{
+		Key:       []byte(hmacKey),
+		Header:    auth.HMAC.Header,
+		Algorithm: string(auth.HMAC.Algorithm),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("configuring HMAC signing: %w", err)
+	}
+	return []mimir.RequestMiddleware{signer}, nil
 }
 
 // AddPromClient would create a Prometheus client and add it to the cache.
@@ -0,0 +1,311 @@
+package clients
+
+import (
+	"context"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	openawarenessv1beta1 "github.com/syndlex/openawareness-controller/api/openawareness/v1beta1"
+)
+
+// mimirServer serves every request with its status, 200 unless changed.
+type mimirServer struct {
+	*httptest.Server
+	status   atomic.Int32
+	requests atomic.Int32
+}
+
+func newMimirServer(t *testing.T) *mimirServer {
+	t.Helper()
+	server := &mimirServer{}
+	server.status.Store(http.StatusOK)
+	server.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		server.requests.Add(1)
+		w.WriteHeader(int(server.status.Load()))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func mimirClientConfig(namespace, name, address string) *openawarenessv1beta1.ClientConfig {
+	return &openawarenessv1beta1.ClientConfig{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name, Generation: 1},
+		Spec: openawarenessv1beta1.ClientConfigSpec{
+			Address: address,
+			Type:    openawarenessv1beta1.Mimir,
+		},
+	}
+}
+
+func TestCheckHealth(t *testing.T) {
+	server := newMimirServer(t)
+	cache := NewRulerClientCache()
+	clientConfig := mimirClientConfig("monitoring", "mimir", server.URL)
+	ctx := context.Background()
+
+	if err := cache.CheckHealth(ctx, clientConfig); err == nil {
+		t.Error("expected an error without cached client")
+	}
+	if _, err := cache.GetOrCreateMimirClient(ctx, clientConfig); err != nil {
+		t.Fatalf("GetOrCreateMimirClient() error = %v", err)
+	}
+	created := cache.Clients()[0].LastHealthCheck
+
+	time.Sleep(10 * time.Millisecond)
+	server.status.Store(http.StatusServiceUnavailable)
+	if err := cache.CheckHealth(ctx, clientConfig); err == nil {
+		t.Fatal("expected the health check to fail")
+	}
+	info := cache.Clients()[0]
+	if !info.LastHealthCheck.After(created) {
+		t.Errorf("expected the failed health check to be recorded, last health check %v", info.LastHealthCheck)
+	}
+	if info.HealthCheckError == "" {
+		t.Error("expected the error of the failed health check")
+	}
+
+	failed := info.LastHealthCheck
+	time.Sleep(10 * time.Millisecond)
+	server.status.Store(http.StatusOK)
+	if err := cache.CheckHealth(ctx, clientConfig); err != nil {
+		t.Fatalf("CheckHealth() error = %v", err)
+	}
+	info = cache.Clients()[0]
+	if !info.LastHealthCheck.After(failed) {
+		t.Errorf("expected the successful health check to be recorded, last health check %v", info.LastHealthCheck)
+	}
+	if info.HealthCheckError != "" {
+		t.Errorf("expected the error to be cleared, got %q", info.HealthCheckError)
+	}
+}
+
+func TestGetOrCreateMimirClientReusesClient(t *testing.T) {
+	server := newMimirServer(t)
+	cache := NewRulerClientCache()
+	clientConfig := mimirClientConfig("monitoring", "mimir", server.URL)
+	ctx := context.Background()
+
+	first, err := cache.GetOrCreateMimirClient(ctx, clientConfig)
+	if err != nil {
+		t.Fatalf("GetOrCreateMimirClient() error = %v", err)
+	}
+	second, err := cache.GetOrCreateMimirClient(ctx, clientConfig.DeepCopy())
+	if err != nil {
+		t.Fatalf("GetOrCreateMimirClient() error = %v", err)
+	}
+	if first != second {
+		t.Error("expected the cached client to be reused for an unchanged ClientConfig")
+	}
+	if got := server.requests.Load(); got != 1 {
+		t.Errorf("expected a single health check, got %d requests", got)
+	}
+}
+
+func TestGetOrCreateMimirClientRebuildsOnSpecChange(t *testing.T) {
+	old, moved := newMimirServer(t), newMimirServer(t)
+	cache := NewRulerClientCache()
+	clientConfig := mimirClientConfig("monitoring", "mimir", old.URL)
+	ctx := context.Background()
+
+	first, err := cache.GetOrCreateMimirClient(ctx, clientConfig)
+	if err != nil {
+		t.Fatalf("GetOrCreateMimirClient() error = %v", err)
+	}
+	changed := clientConfig.DeepCopy()
+	changed.Spec.Address = moved.URL
+	changed.Generation++
+	second, err := cache.GetOrCreateMimirClient(ctx, changed)
+	if err != nil {
+		t.Fatalf("GetOrCreateMimirClient() error = %v", err)
+	}
+	if first == second {
+		t.Fatal("expected the client to be created again after a spec change")
+	}
+	infos := cache.Clients()
+	if len(infos) != 1 || infos[0].Address != moved.URL {
+		t.Errorf("expected a single client for %s, got %+v", moved.URL, infos)
+	}
+	if got := moved.requests.Load(); got != 1 {
+		t.Errorf("expected the new address to be health checked, got %d requests", got)
+	}
+}
+
+func TestGetOrCreateMimirClientRebuildsOnReferencedDataChange(t *testing.T) {
+	server := newMimirServer(t)
+	clientConfig := mimirClientConfig("monitoring", "mimir", server.URL)
+	clientConfig.Spec.Auth = &openawarenessv1beta1.ClientAuth{HMAC: &openawarenessv1beta1.HMACAuth{
+		SecretRef: corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "signing"}, Key: "key"},
+	}}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "monitoring", Name: "signing"},
+		Data:       map[string][]byte{"key": []byte("first")},
+	}
+	reader := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(secret).Build()
+	cache := NewRulerClientCache()
+	cache.Reader = reader
+	ctx := context.Background()
+
+	first, err := cache.GetOrCreateMimirClient(ctx, clientConfig)
+	if err != nil {
+		t.Fatalf("GetOrCreateMimirClient() error = %v", err)
+	}
+	if again, _ := cache.GetOrCreateMimirClient(ctx, clientConfig); again != first {
+		t.Error("expected the cached client to be reused while the key is unchanged")
+	}
+
+	secret.Data["key"] = []byte("rotated")
+	if err := reader.Update(ctx, secret); err != nil {
+		t.Fatalf("updating Secret: %v", err)
+	}
+	second, err := cache.GetOrCreateMimirClient(ctx, clientConfig)
+	if err != nil {
+		t.Fatalf("GetOrCreateMimirClient() error = %v", err)
+	}
+	if first == second {
+		t.Error("expected the client to be created again after the HMAC key changed")
+	}
+}
+
+func TestGetOrCreateMimirClientRebuildsOnCABundleChange(t *testing.T) {
+	tlsServer := httptest.NewTLSServer(http.NotFoundHandler())
+	t.Cleanup(tlsServer.Close)
+	certificate := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: tlsServer.Certificate().Raw}))
+	mimir := newMimirServer(t)
+	clientConfig := mimirClientConfig("monitoring", "mimir", mimir.URL)
+	clientConfig.Spec.TLS = &openawarenessv1beta1.ClientTLS{CAConfigMapRef: &corev1.ConfigMapKeySelector{
+		LocalObjectReference: corev1.LocalObjectReference{Name: "ca"}, Key: "ca.crt",
+	}}
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "monitoring", Name: "ca"},
+		Data:       map[string]string{"ca.crt": certificate},
+	}
+	reader := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(configMap).Build()
+	cache := NewRulerClientCache()
+	cache.Reader = reader
+	ctx := context.Background()
+
+	cached, err := cache.GetOrCreateMimirClient(ctx, clientConfig)
+	if err != nil {
+		t.Fatalf("GetOrCreateMimirClient() error = %v", err)
+	}
+	// httptest servers share their certificate, a bundle listing it twice differs all the same
+	configMap.Data["ca.crt"] = certificate + certificate
+	if err := reader.Update(ctx, configMap); err != nil {
+		t.Fatalf("updating ConfigMap: %v", err)
+	}
+	rebuilt, err := cache.GetOrCreateMimirClient(ctx, clientConfig)
+	if err != nil {
+		t.Fatalf("GetOrCreateMimirClient() error = %v", err)
+	}
+	if cached == rebuilt {
+		t.Error("expected the client to be created again after the CA bundle changed")
+	}
+}
+
+func TestGetOrCreateMimirClientKeyedByNamespaceAndName(t *testing.T) {
+	teamA, teamB := newMimirServer(t), newMimirServer(t)
+	cache := NewRulerClientCache()
+	ctx := context.Background()
+
+	a, err := cache.GetOrCreateMimirClient(ctx, mimirClientConfig("team-a", "mimir", teamA.URL))
+	if err != nil {
+		t.Fatalf("GetOrCreateMimirClient() error = %v", err)
+	}
+	b, err := cache.GetOrCreateMimirClient(ctx, mimirClientConfig("team-b", "mimir", teamB.URL))
+	if err != nil {
+		t.Fatalf("GetOrCreateMimirClient() error = %v", err)
+	}
+	if a == b {
+		t.Fatal("expected same-named ClientConfigs of different namespaces to have their own client")
+	}
+	infos := cache.Clients()
+	if len(infos) != 2 || infos[0].Namespace != "team-a" || infos[1].Namespace != "team-b" {
+		t.Fatalf("expected a client per namespace sorted by namespace, got %+v", infos)
+	}
+
+	cache.RemoveClient("team-a", "mimir")
+	if infos := cache.Clients(); len(infos) != 1 || infos[0].Namespace != "team-b" || infos[0].Address != teamB.URL {
+		t.Errorf("expected removing a client to keep the same-named client of team-b, got %+v", infos)
+	}
+}
+
+func TestGetOrCreateMimirClientCreatesOnceConcurrently(t *testing.T) {
+	server := newMimirServer(t)
+	cache := NewRulerClientCache()
+	clientConfig := mimirClientConfig("monitoring", "mimir", server.URL)
+	ctx := context.Background()
+
+	created := make([]AwarenessClient, 10)
+	var wg sync.WaitGroup
+	for i := range created {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			client, err := cache.GetOrCreateMimirClient(ctx, clientConfig.DeepCopy())
+			if err != nil {
+				t.Errorf("GetOrCreateMimirClient() error = %v", err)
+			}
+			created[i] = client
+		}()
+	}
+	wg.Wait()
+
+	for _, client := range created {
+		if client != created[0] {
+			t.Fatal("expected all callers to get the same client")
+		}
+	}
+	if got := server.requests.Load(); got != 1 {
+		t.Errorf("expected the client to be created once, got %d health checks", got)
+	}
+}
+
+func TestRulerClientCacheConcurrentAccess(t *testing.T) {
+	server := newMimirServer(t)
+	cache := NewRulerClientCache()
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	for i := range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			clientConfig := mimirClientConfig(fmt.Sprintf("team-%d", i%4), "mimir", server.URL)
+			// Every fifth caller has another generation, forcing rebuilds racing with the others
+			clientConfig.Generation = int64(1 + i%5/4)
+			for range 5 {
+				if _, err := cache.GetOrCreateMimirClient(ctx, clientConfig); err != nil {
+					t.Errorf("GetOrCreateMimirClient() error = %v", err)
+					return
+				}
+				_ = cache.CheckHealth(ctx, clientConfig)
+				_ = cache.Clients()
+				if i%4 == 3 {
+					cache.RemoveClient(clientConfig.Namespace, clientConfig.Name)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	cached := map[string]bool{}
+	for _, info := range cache.Clients() {
+		cached[info.Namespace] = true
+	}
+	for _, namespace := range []string{"team-0", "team-1", "team-2"} {
+		if !cached[namespace] {
+			t.Errorf("expected a client for %s, got %+v", namespace, cache.Clients())
+		}
+	}
+}
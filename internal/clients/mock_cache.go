@@ -4,15 +4,23 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"maps"
+	"net"
 	"net/url"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/prometheus/prometheus/model/rulefmt"
+
+	openawarenessv1beta1 "github.com/syndlex/openawareness-controller/api/openawareness/v1beta1"
 )
 
 // MockRulerClientCache is a mock implementation of RulerClientCache for testing
 type MockRulerClientCache struct {
 	clients map[string]AwarenessClient
+	// healthCheckError is returned by CheckHealth
+	healthCheckError error
 }
 
 // Ensure MockRulerClientCache implements RulerClientCacheInterface
@@ -26,7 +34,8 @@ func NewMockRulerClientCache() *MockRulerClientCache {
 }
 
 // AddMimirClient simulates adding a Mimir client with validation
-func (m *MockRulerClientCache) AddMimirClient(_ context.Context, address string, name string) error {
+func (m *MockRulerClientCache) AddMimirClient(_ context.Context, clientConfig *openawarenessv1beta1.ClientConfig) error {
+	address := clientConfig.Spec.Address
 	// Validate URL format
 	parsedURL, err := url.Parse(address)
 	if err != nil {
@@ -40,11 +49,12 @@ func (m *MockRulerClientCache) AddMimirClient(_ context.Context, address string,
 
 	// Simulate DNS resolution failure for specific test hosts
 	if strings.Contains(address, "unreachable-host") {
-		return fmt.Errorf("dial tcp: lookup unreachable-host-12345.local: no such host")
+		return fmt.Errorf("dial tcp: %w",
+			&net.DNSError{Err: "no such host", Name: "unreachable-host-12345.local", IsNotFound: true})
 	}
 
 	// Simulate successful connection for valid URLs
-	m.clients[name] = &MockAwarenessClient{}
+	m.clients[clientConfig.Name] = &MockAwarenessClient{}
 	return nil
 }
 
@@ -52,20 +62,19 @@ func (m *MockRulerClientCache) AddMimirClient(_ context.Context, address string,
 // One client handles all tenants for that Mimir instance via X-Scope-OrgID header.
 func (m *MockRulerClientCache) GetOrCreateMimirClient(
 	ctx context.Context,
-	address string,
-	clientName string,
+	clientConfig *openawarenessv1beta1.ClientConfig,
 ) (AwarenessClient, error) {
 	// Check if client already exists using simple client name
-	if client, exists := m.clients[clientName]; exists {
+	if client, exists := m.clients[clientConfig.Name]; exists {
 		return client, nil
 	}
 
 	// Create new client
-	if err := m.AddMimirClient(ctx, address, clientName); err != nil {
+	if err := m.AddMimirClient(ctx, clientConfig); err != nil {
 		return nil, fmt.Errorf("creating Mimir client: %w", err)
 	}
 
-	return m.clients[clientName], nil
+	return m.clients[clientConfig.Name], nil
 }
 
 // AddPromClient simulates adding a Prometheus client
@@ -73,14 +82,27 @@ func (m *MockRulerClientCache) AddPromClient(_ context.Context, _ string, _ stri
 	return errors.New("prometheus client not yet implemented")
 }
 
-// RemoveClient removes a client from the cache
-func (m *MockRulerClientCache) RemoveClient(name string) {
+// RemoveClient removes a client from the cache, the mock keys clients by name only
+func (m *MockRulerClientCache) RemoveClient(_ string, name string) {
 	if m.clients[name] == nil {
 		return
 	}
 	delete(m.clients, name)
 }
 
+// CheckHealth simulates the health check of a cached client, see SetHealthCheckError
+func (m *MockRulerClientCache) CheckHealth(_ context.Context, clientConfig *openawarenessv1beta1.ClientConfig) error {
+	if m.clients[clientConfig.Name] == nil {
+		return fmt.Errorf("no client cached for ClientConfig %s", clientConfig.Name)
+	}
+	return m.healthCheckError
+}
+
+// SetHealthCheckError sets the error returned by CheckHealth for testing
+func (m *MockRulerClientCache) SetHealthCheckError(err error) {
+	m.healthCheckError = err
+}
+
 // SetClient manually sets a client in the cache for testing
 func (m *MockRulerClientCache) SetClient(name string, client AwarenessClient) {
 	m.clients[name] = client
@@ -92,6 +114,20 @@ type MockAwarenessClient struct {
 	deleteRuleGroupError   error
 	createAlertConfigError error
 	deleteAlertConfigError error
+	// alertConfigs holds the pushed Alertmanager configurations by tenant
+	alertConfigs map[string]string
+	// ruleGroups holds the pushed rule groups by tenant, namespace and name
+	ruleGroups map[string]rulefmt.RuleGroup
+	// sourceTenants holds the source tenants of the pushed federated rule groups by tenant,
+	// namespace and name
+	sourceTenants map[string][]string
+	// notificationFailures holds the failed notifications by integration by tenant
+	notificationFailures map[string]map[string]float64
+	// tenants are the tenants returned by ListTenants, tenantsError its error
+	tenants      []string
+	tenantsError error
+	// circuitOpenUntil is returned by CircuitOpenUntil
+	circuitOpenUntil time.Time
 }
 
 // NewMockAwarenessClient creates a new mock awareness client
@@ -119,59 +155,171 @@ func (m *MockAwarenessClient) SetDeleteAlertConfigError(err error) {
 	m.deleteAlertConfigError = err
 }
 
+// SetNotificationFailures sets the failed notifications by integration returned for a tenant
+func (m *MockAwarenessClient) SetNotificationFailures(tenantID string, failures map[string]float64) {
+	if m.notificationFailures == nil {
+		m.notificationFailures = map[string]map[string]float64{}
+	}
+	m.notificationFailures[tenantID] = failures
+}
+
+// SetTenants sets the tenants and the error returned by ListTenants
+func (m *MockAwarenessClient) SetTenants(tenants []string, err error) {
+	m.tenants, m.tenantsError = tenants, err
+}
+
+// SetCircuitOpenUntil sets the time until which the circuit breaker of the mock client is open
+func (m *MockAwarenessClient) SetCircuitOpenUntil(until time.Time) {
+	m.circuitOpenUntil = until
+}
+
 // CreateRuleGroup creates or updates a rule group in the mock client.
-func (m *MockAwarenessClient) CreateRuleGroup(_ context.Context, _ string, _ rulefmt.RuleGroup, _ string) error {
+func (m *MockAwarenessClient) CreateRuleGroup(
+	_ context.Context,
+	namespace string,
+	group rulefmt.RuleGroup,
+	tenantID string,
+) error {
 	if m.createRuleGroupError != nil {
 		return m.createRuleGroupError
 	}
+	if m.ruleGroups == nil {
+		m.ruleGroups = map[string]rulefmt.RuleGroup{}
+	}
+	m.ruleGroups[ruleGroupKey(tenantID, namespace, group.Name)] = group
+	delete(m.sourceTenants, ruleGroupKey(tenantID, namespace, group.Name))
+	return nil
+}
+
+// CreateFederatedRuleGroup creates or updates a rule group with source tenants in the mock client.
+func (m *MockAwarenessClient) CreateFederatedRuleGroup(
+	ctx context.Context,
+	namespace string,
+	group rulefmt.RuleGroup,
+	sourceTenants []string,
+	tenantID string,
+) error {
+	if err := m.CreateRuleGroup(ctx, namespace, group, tenantID); err != nil {
+		return err
+	}
+	if m.sourceTenants == nil {
+		m.sourceTenants = map[string][]string{}
+	}
+	m.sourceTenants[ruleGroupKey(tenantID, namespace, group.Name)] = sourceTenants
 	return nil
 }
 
+// SourceTenants returns the source tenants a rule group was last pushed with to the mock client.
+func (m *MockAwarenessClient) SourceTenants(tenantID, namespace, groupName string) []string {
+	return m.sourceTenants[ruleGroupKey(tenantID, namespace, groupName)]
+}
+
+// ruleGroupKey identifies a rule group pushed to the mock client
+func ruleGroupKey(tenantID, namespace, groupName string) string {
+	return tenantID + "/" + namespace + "/" + groupName
+}
+
 // DeleteRuleGroup deletes a rule group from the mock client.
-func (m *MockAwarenessClient) DeleteRuleGroup(_ context.Context, _, _ string, _ string) error {
+func (m *MockAwarenessClient) DeleteRuleGroup(_ context.Context, namespace, groupName string, tenantID string) error {
 	if m.deleteRuleGroupError != nil {
 		return m.deleteRuleGroupError
 	}
+	delete(m.ruleGroups, ruleGroupKey(tenantID, namespace, groupName))
 	return nil
 }
 
-// GetRuleGroup retrieves a rule group from the mock client.
-func (m *MockAwarenessClient) GetRuleGroup(_ context.Context, _, _ string, _ string) (*rulefmt.RuleGroup, error) {
-	return nil, nil
+// GetRuleGroup retrieves a rule group pushed to the mock client, nil if none was pushed.
+func (m *MockAwarenessClient) GetRuleGroup(
+	_ context.Context,
+	namespace, groupName string,
+	tenantID string,
+) (*rulefmt.RuleGroup, error) {
+	group, ok := m.ruleGroups[ruleGroupKey(tenantID, namespace, groupName)]
+	if !ok {
+		return nil, nil
+	}
+	return &group, nil
+}
+
+// ListRules lists the rule groups pushed to a namespace of the mock client, sorted by name.
+func (m *MockAwarenessClient) ListRules(
+	_ context.Context,
+	namespace string,
+	tenantID string,
+) (map[string][]rulefmt.RuleGroup, error) {
+	var groups []rulefmt.RuleGroup
+	for _, key := range slices.Sorted(maps.Keys(m.ruleGroups)) {
+		if strings.HasPrefix(key, ruleGroupKey(tenantID, namespace, "")) {
+			groups = append(groups, m.ruleGroups[key])
+		}
+	}
+	if len(groups) == 0 {
+		return nil, nil
+	}
+	return map[string][]rulefmt.RuleGroup{namespace: groups}, nil
 }
 
-// ListRules lists all rules in a namespace from the mock client.
-func (m *MockAwarenessClient) ListRules(_ context.Context, _ string, _ string) (map[string][]rulefmt.RuleGroup, error) {
+// ListRuleNamespaces lists the rule namespaces of a tenant from the mock client.
+func (m *MockAwarenessClient) ListRuleNamespaces(_ context.Context, _ string) ([]string, error) {
 	return nil, nil
 }
 
+// WalkRules walks the rule namespaces of a tenant from the mock client.
+func (m *MockAwarenessClient) WalkRules(
+	_ context.Context,
+	_ string,
+	_ func(namespace string, groups []rulefmt.RuleGroup) error,
+) error {
+	return nil
+}
+
 // DeleteNamespace deletes a namespace from the mock client.
 func (m *MockAwarenessClient) DeleteNamespace(_ context.Context, _ string, _ string) error {
 	return nil
 }
 
 // CreateAlertmanagerConfig creates or updates an Alertmanager configuration in the mock client.
-func (m *MockAwarenessClient) CreateAlertmanagerConfig(_ context.Context, _ string, _ map[string]string, _ string) error {
+func (m *MockAwarenessClient) CreateAlertmanagerConfig(_ context.Context, cfg string, _ map[string]string, tenantID string) error {
 	if m.createAlertConfigError != nil {
 		return m.createAlertConfigError
 	}
+	if m.alertConfigs == nil {
+		m.alertConfigs = map[string]string{}
+	}
+	m.alertConfigs[tenantID] = cfg
 	return nil
 }
 
 // DeleteAlermanagerConfig deletes the Alertmanager configuration from the mock client.
-func (m *MockAwarenessClient) DeleteAlermanagerConfig(_ context.Context, _ string) error {
+func (m *MockAwarenessClient) DeleteAlermanagerConfig(_ context.Context, tenantID string) error {
 	if m.deleteAlertConfigError != nil {
 		return m.deleteAlertConfigError
 	}
+	delete(m.alertConfigs, tenantID)
 	return nil
 }
 
-// GetAlertmanagerConfig retrieves the Alertmanager configuration from the mock client.
-func (m *MockAwarenessClient) GetAlertmanagerConfig(_ context.Context, _ string) (string, map[string]string, error) {
-	return "", nil, nil
+// GetAlertmanagerConfig retrieves the last Alertmanager configuration pushed to the mock client.
+func (m *MockAwarenessClient) GetAlertmanagerConfig(_ context.Context, tenantID string) (string, map[string]string, error) {
+	return m.alertConfigs[tenantID], nil, nil
 }
 
 // GetAlertmanagerStatus retrieves the Alertmanager status from the mock client.
 func (m *MockAwarenessClient) GetAlertmanagerStatus(_ context.Context, _ string) (string, error) {
 	return "", nil
 }
+
+// GetNotificationFailures retrieves the failed notifications set for a tenant of the mock client.
+func (m *MockAwarenessClient) GetNotificationFailures(_ context.Context, tenantID string) (map[string]float64, error) {
+	return m.notificationFailures[tenantID], nil
+}
+
+// ListTenants returns the tenants set on the mock client.
+func (m *MockAwarenessClient) ListTenants(_ context.Context) ([]string, error) {
+	return m.tenants, m.tenantsError
+}
+
+// CircuitOpenUntil returns the time set on the mock client, zero unless set.
+func (m *MockAwarenessClient) CircuitOpenUntil() time.Time {
+	return m.circuitOpenUntil
+}
@@ -0,0 +1,108 @@
+// Package conflicts detects recording and alerting rules defined by several resources in the same tenant.
+package conflicts
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/prometheus/prometheus/model/rulefmt"
+)
+
+// Kind is the kind of a rule name.
+type Kind string
+
+const (
+	// KindRecord names a recording rule, duplicates overwrite each other's series in queries
+	KindRecord Kind = "recording rule"
+	// KindAlert names an alerting rule, duplicates make alerts ambiguous to route and silence
+	KindAlert Kind = "alerting rule"
+)
+
+// Conflict is a rule name of a resource that is also defined by other owners in the tenant.
+type Conflict struct {
+	Kind Kind
+	Name string
+	// Owners are the other owners defining the name, sorted
+	Owners []string
+}
+
+// String describes the conflict for events.
+func (c Conflict) String() string {
+	return fmt.Sprintf("%s %s is also defined by %s", c.Kind, c.Name, strings.Join(c.Owners, ", "))
+}
+
+// key identifies a rule name within a tenant.
+type key struct {
+	tenantID string
+	kind     Kind
+	name     string
+}
+
+// Registry records which owners define which rule names per tenant.
+// Owners are the provenance of the rules, e.g. "<namespace>/<name>" of a PrometheusRule.
+// The zero value is not usable, see NewRegistry.
+type Registry struct {
+	owners map[key]map[string]struct{}
+}
+
+// NewRegistry returns an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{owners: map[key]map[string]struct{}{}}
+}
+
+// Add records the rule names of the groups as defined by owner in the tenant.
+// Adding the same name for the same owner again has no effect.
+func (r *Registry) Add(tenantID, owner string, groups []rulefmt.RuleGroup) {
+	for _, group := range groups {
+		for _, rule := range group.Rules {
+			k := ruleKey(tenantID, rule)
+			if r.owners[k] == nil {
+				r.owners[k] = map[string]struct{}{}
+			}
+			r.owners[k][owner] = struct{}{}
+		}
+	}
+}
+
+// Conflicts returns the rule names of the groups that other owners also define in the
+// tenant, sorted by kind and name.
+func (r *Registry) Conflicts(tenantID, owner string, groups []rulefmt.RuleGroup) []Conflict {
+	seen := map[key]struct{}{}
+	var conflicts []Conflict
+	for _, group := range groups {
+		for _, rule := range group.Rules {
+			k := ruleKey(tenantID, rule)
+			if _, done := seen[k]; done {
+				continue
+			}
+			seen[k] = struct{}{}
+
+			var others []string
+			for other := range r.owners[k] {
+				if other != owner {
+					others = append(others, other)
+				}
+			}
+			if len(others) == 0 {
+				continue
+			}
+			sort.Strings(others)
+			conflicts = append(conflicts, Conflict{Kind: k.kind, Name: k.name, Owners: others})
+		}
+	}
+	sort.Slice(conflicts, func(i, j int) bool {
+		if conflicts[i].Kind != conflicts[j].Kind {
+			return conflicts[i].Kind > conflicts[j].Kind
+		}
+		return conflicts[i].Name < conflicts[j].Name
+	})
+	return conflicts
+}
+
+func ruleKey(tenantID string, rule rulefmt.Rule) key {
+	if rule.Record != "" {
+		return key{tenantID: tenantID, kind: KindRecord, name: rule.Record}
+	}
+	return key{tenantID: tenantID, kind: KindAlert, name: rule.Alert}
+}
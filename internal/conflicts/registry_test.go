@@ -0,0 +1,38 @@
+package conflicts
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/prometheus/prometheus/model/rulefmt"
+)
+
+func TestConflicts(t *testing.T) {
+	groupsA := []rulefmt.RuleGroup{{Name: "a", Rules: []rulefmt.Rule{
+		{Record: "job:up:sum"}, {Alert: "InstanceDown"}, {Alert: "InstanceDown"}, {Record: "job:up:max"},
+	}}}
+	groupsB := []rulefmt.RuleGroup{{Name: "b", Rules: []rulefmt.Rule{{Record: "job:up:sum"}, {Alert: "InstanceDown"}}}}
+
+	registry := NewRegistry()
+	registry.Add("tenant-a", "team-a/rules", groupsA)
+	registry.Add("tenant-a", "team-b/rules", groupsB)
+	registry.Add("tenant-a", "team-c/rules", groupsB)
+	// Names in other tenants do not conflict
+	registry.Add("tenant-b", "team-d/rules", groupsA)
+
+	got := registry.Conflicts("tenant-a", "team-a/rules", groupsA)
+	want := []Conflict{
+		{Kind: KindRecord, Name: "job:up:sum", Owners: []string{"team-b/rules", "team-c/rules"}},
+		{Kind: KindAlert, Name: "InstanceDown", Owners: []string{"team-b/rules", "team-c/rules"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Conflicts() = %+v, want %+v", got, want)
+	}
+	if got[0].String() != "recording rule job:up:sum is also defined by team-b/rules, team-c/rules" {
+		t.Errorf("unexpected description %q", got[0].String())
+	}
+
+	if got := registry.Conflicts("tenant-b", "team-d/rules", groupsA); len(got) != 0 {
+		t.Errorf("expected no conflicts for the only owner, got %+v", got)
+	}
+}
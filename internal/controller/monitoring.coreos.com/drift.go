@@ -0,0 +1,116 @@
+package monitoringcoreoscom
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"strings"
+
+	"github.com/go-logr/logr"
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"github.com/prometheus/prometheus/model/rulefmt"
+	openawarenessv1beta1 "github.com/syndlex/openawareness-controller/api/openawareness/v1beta1"
+	"github.com/syndlex/openawareness-controller/internal/clients"
+	"github.com/syndlex/openawareness-controller/internal/controller/utils"
+	"github.com/syndlex/openawareness-controller/internal/events"
+	"github.com/syndlex/openawareness-controller/internal/logging"
+	"github.com/syndlex/openawareness-controller/internal/mimir"
+)
+
+// reportDrift reads the rule group from Mimir and compares it with the group pushed before,
+// whose checksum is recorded. A group deleted or modified outside the operator is reported as
+// RuleGroupModified warning event listing how it differs from desired, which overwrites it.
+// Returns whether the group drifted, so it is pushed again even if desired is unchanged.
+// Groups without recorded checksum are not checked, nothing was pushed to compare with; read
+// errors are logged and count as no drift. Mimir does not return the source tenants of groups,
+// the remote group is compared as if pushed with sourceTenants.
+func (r *PrometheusRulesReconciler) reportDrift(
+	ctx context.Context,
+	logger logr.Logger,
+	rule *monitoringv1.PrometheusRule,
+	clientConfig *openawarenessv1beta1.ClientConfig,
+	awarenessClient clients.AwarenessClient,
+	namespace, tenantID string,
+	desired rulefmt.RuleGroup,
+	sourceTenants []string,
+	recorded string,
+) bool {
+	if recorded == "" {
+		return false
+	}
+	remote, err := awarenessClient.GetRuleGroup(ctx, namespace, desired.Name, tenantID)
+	if err != nil && !errors.Is(err, mimir.ErrResourceNotFound) {
+		logger.Error(err, "Failed to read rule group for drift detection",
+			"group", desired.Name,
+			"rulesNamespace", namespace,
+			logging.KeyTenant, tenantID)
+		return false
+	}
+	if remote == nil {
+		events.Eventf(r.Recorder, rule, events.RuleGroupModified,
+			"Rule group %s in namespace %s of tenant %s was deleted outside the operator, pushing it again",
+			desired.Name, namespace, tenantID)
+		return true
+	}
+
+	checksum, err := utils.RuleGroupChecksum(clientConfig, tenantID, *remote, sourceTenants...)
+	if err != nil || checksum == recorded {
+		return false
+	}
+	modifiedBy := "outside the operator"
+	if instance := utils.InstanceOf(*remote); instance != "" && instance != r.Identity {
+		modifiedBy = "by installation " + instance
+	}
+	events.Eventf(r.Recorder, rule, events.RuleGroupModified,
+		"Rule group %s in namespace %s of tenant %s was modified %s, overwriting it: %s",
+		desired.Name, namespace, tenantID, modifiedBy, strings.Join(ruleGroupChanges(*remote, desired), ", "))
+	return true
+}
+
+// ruleGroupChanges describes how the remote rule group differs from desired: its interval,
+// query offset, limit and labels, its rule count, and the number of rules with a changed
+// expression or other changed fields, compared by position.
+func ruleGroupChanges(remote, desired rulefmt.RuleGroup) []string {
+	var changes []string
+	if remote.Interval != desired.Interval {
+		changes = append(changes, fmt.Sprintf("interval %s in Mimir, %s desired", remote.Interval, desired.Interval))
+	}
+	if (remote.QueryOffset == nil) != (desired.QueryOffset == nil) ||
+		(remote.QueryOffset != nil && *remote.QueryOffset != *desired.QueryOffset) {
+		changes = append(changes, "query offset changed")
+	}
+	if remote.Limit != desired.Limit {
+		changes = append(changes, fmt.Sprintf("limit %d in Mimir, %d desired", remote.Limit, desired.Limit))
+	}
+	if !maps.Equal(remote.Labels, desired.Labels) {
+		changes = append(changes, "group labels changed")
+	}
+	if len(remote.Rules) != len(desired.Rules) {
+		changes = append(changes, fmt.Sprintf("%d rule(s) in Mimir, %d desired", len(remote.Rules), len(desired.Rules)))
+	}
+
+	expressions, other := 0, 0
+	for i := range min(len(remote.Rules), len(desired.Rules)) {
+		remoteRule, desiredRule := remote.Rules[i], desired.Rules[i]
+		switch {
+		case remoteRule.Expr != desiredRule.Expr:
+			expressions++
+		case remoteRule.Record != desiredRule.Record || remoteRule.Alert != desiredRule.Alert ||
+			remoteRule.For != desiredRule.For || remoteRule.KeepFiringFor != desiredRule.KeepFiringFor ||
+			!maps.Equal(remoteRule.Labels, desiredRule.Labels) ||
+			!maps.Equal(remoteRule.Annotations, desiredRule.Annotations):
+			other++
+		}
+	}
+	if expressions > 0 {
+		changes = append(changes, fmt.Sprintf("%d changed expression(s)", expressions))
+	}
+	if other > 0 {
+		changes = append(changes, fmt.Sprintf("%d rule(s) with changed names, durations, labels or annotations", other))
+	}
+	if len(changes) == 0 {
+		changes = append(changes, "content changed")
+	}
+	return changes
+}
@@ -0,0 +1,61 @@
+package monitoringcoreoscom
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/rulefmt"
+	openawarenessv1beta1 "github.com/syndlex/openawareness-controller/api/openawareness/v1beta1"
+)
+
+// errRuleIntervalTooShort reports rule groups evaluated more often than the minimum interval of
+// their ClientConfig allows.
+var errRuleIntervalTooShort = errors.New("rule group interval below the minimum")
+
+// shortIntervalGroups returns the names of the groups evaluated more often than minimum. Groups
+// without interval use the default of the ruler and are never returned.
+func shortIntervalGroups(groups []rulefmt.RuleGroup, minimum time.Duration) []string {
+	var short []string
+	for _, group := range groups {
+		if group.Interval > 0 && time.Duration(group.Interval) < minimum {
+			short = append(short, group.Name)
+		}
+	}
+	return short
+}
+
+// clampRuleIntervals raises the interval of the groups evaluated more often than the minimum of
+// the MinRuleInterval policy of clientConfig to the minimum, unless the policy rejects them.
+// Returns the names of the clamped groups, nil if clientConfig has no policy.
+func clampRuleIntervals(clientConfig *openawarenessv1beta1.ClientConfig, groups []rulefmt.RuleGroup) []string {
+	if clientConfig == nil || clientConfig.Spec.MinRuleInterval == nil ||
+		clientConfig.Spec.MinRuleInterval.Action == openawarenessv1beta1.RuleIntervalReject {
+		return nil
+	}
+	minimum := clientConfig.Spec.MinRuleInterval.Minimum.Duration
+	clamped := shortIntervalGroups(groups, minimum)
+	for i := range groups {
+		if groups[i].Interval > 0 && time.Duration(groups[i].Interval) < minimum {
+			groups[i].Interval = model.Duration(minimum)
+		}
+	}
+	return clamped
+}
+
+// checkRuleIntervals returns an error wrapping errRuleIntervalTooShort naming the groups
+// evaluated more often than the MinRuleInterval policy of clientConfig allows, nil if
+// clientConfig has no policy. Groups clamped by clampRuleIntervals pass.
+func checkRuleIntervals(clientConfig *openawarenessv1beta1.ClientConfig, groups []rulefmt.RuleGroup) error {
+	if clientConfig == nil || clientConfig.Spec.MinRuleInterval == nil {
+		return nil
+	}
+	minimum := clientConfig.Spec.MinRuleInterval.Minimum.Duration
+	if short := shortIntervalGroups(groups, minimum); len(short) > 0 {
+		return fmt.Errorf("%w %s of ClientConfig %s: %s", errRuleIntervalTooShort,
+			model.Duration(minimum), clientConfig.Name, strings.Join(short, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package monitoringcoreoscom
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"github.com/prometheus/prometheus/model/rulefmt"
+	openawarenessv1beta1 "github.com/syndlex/openawareness-controller/api/openawareness/v1beta1"
+	"github.com/syndlex/openawareness-controller/internal/clients"
+	"github.com/syndlex/openawareness-controller/internal/controller/utils"
+	"github.com/syndlex/openawareness-controller/internal/events"
+	"github.com/syndlex/openawareness-controller/internal/parity"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// parityRecheckInterval is the requeue delay between parity checks of dual-written rules
+const parityRecheckInterval = 5 * time.Minute
+
+// maxParityMismatches bounds the mismatching rules listed in the PrometheusRuleSync status
+const maxParityMismatches = 20
+
+// reportParity compares the series produced by the pushed groups in the in-cluster Prometheus
+// and in their tenants of Mimir for PrometheusRules with the DualWriteAnnotation, and records the
+// result in the Parity status and EvaluationParity condition of the PrometheusRuleSync. Rules
+// producing a different number of series are reported as EvaluationParityMismatch warning event.
+// The check must not fail the sync, errors are reported as ParityUnknown. It is skipped if the
+// client does not support queries, reportSyncStatus clears the parity of other rules.
+// Returns a result rechecking the parity after parityRecheckInterval.
+func (s *prometheusRuleSync) reportParity(
+	ctx context.Context,
+	state *utils.SyncState[*monitoringv1.PrometheusRule],
+	awarenessClient clients.AwarenessClient,
+	groups []rulefmt.RuleGroup,
+) ctrl.Result {
+	logger := log.FromContext(ctx)
+	rule := state.Object
+	mimirCounter, ok := awarenessClient.(parity.SeriesCounter)
+	if !ok {
+		logger.V(1).Info("Client does not support queries, skipping parity check")
+		return ctrl.Result{}
+	}
+
+	ruleSync, err := s.ruleSync(ctx, rule)
+	if err != nil {
+		logger.Error(err, "Failed to create PrometheusRuleSync")
+		return ctrl.Result{RequeueAfter: parityRecheckInterval}
+	}
+	original := ruleSync.DeepCopy()
+	s.r.checkParity(state.SyncContext, rule, ruleSync, mimirCounter,
+		PartitionRuleGroups(rule, groups, state.ClientConfig), utils.TenantIDs(rule, state.ClientConfig))
+	if patchErr := utils.PatchStatus(ctx, s.r.Client, ruleSync, original); patchErr != nil {
+		logger.Error(patchErr, "Failed to update PrometheusRuleSync status")
+	}
+	return ctrl.Result{RequeueAfter: parityRecheckInterval}
+}
+
+// checkParity counts the series of the partitioned groups in every tenant and records the result
+// in the status of ruleSync.
+func (r *PrometheusRulesReconciler) checkParity(
+	ctx context.Context,
+	rule *monitoringv1.PrometheusRule,
+	ruleSync *openawarenessv1beta1.PrometheusRuleSync,
+	mimirCounter parity.SeriesCounter,
+	partitions map[string][]rulefmt.RuleGroup,
+	tenantIDs []string,
+) {
+	condition := metav1.Condition{
+		Type:               openawarenessv1beta1.ConditionTypeEvaluationParity,
+		ObservedGeneration: rule.Generation,
+	}
+	result := &openawarenessv1beta1.EvaluationParity{CheckedAt: metav1.Now()}
+	var err error
+	if r.ParityPrometheus == nil {
+		err = parity.ErrNoPrometheus
+	}
+	for _, tenantID := range tenantIDs {
+		if err != nil {
+			break
+		}
+		var rules []parity.Rule
+		rules, err = parity.Compare(ctx, r.ParityPrometheus, mimirCounter, tenantID, partitions[tenantID])
+		for _, compared := range rules {
+			result.RulesCompared++
+			if compared.Matches() {
+				result.RulesMatching++
+				continue
+			}
+			if len(result.Mismatches) < maxParityMismatches {
+				result.Mismatches = append(result.Mismatches, openawarenessv1beta1.RuleParity{
+					Tenant:           tenantID,
+					Group:            compared.Group,
+					Rule:             compared.Name,
+					PrometheusSeries: compared.Prometheus,
+					MimirSeries:      compared.Mimir,
+				})
+			}
+		}
+	}
+
+	mismatches := result.RulesCompared - result.RulesMatching
+	switch {
+	case err != nil:
+		log.FromContext(ctx).Error(err, "Failed to check evaluation parity")
+		condition.Status, condition.Reason = metav1.ConditionUnknown, openawarenessv1beta1.ReasonParityUnknown
+		condition.Message = utils.StatusMessage(err)
+	case mismatches > 0:
+		condition.Status, condition.Reason = metav1.ConditionFalse, openawarenessv1beta1.ReasonParityMismatch
+		condition.Message = fmt.Sprintf("%d of %d rule(s) produce a different number of series in Mimir and Prometheus",
+			mismatches, result.RulesCompared)
+		first := result.Mismatches[0]
+		events.Eventf(r.Recorder, rule, events.EvaluationParityMismatch,
+			"%s, e.g. %s in group %s of tenant %s: %d series in Prometheus, %d in Mimir",
+			condition.Message, first.Rule, first.Group, first.Tenant, first.PrometheusSeries, first.MimirSeries)
+	default:
+		condition.Status, condition.Reason = metav1.ConditionTrue, openawarenessv1beta1.ReasonParityMatched
+		condition.Message = fmt.Sprintf("All %d rule(s) produce the same number of series in Mimir and Prometheus",
+			result.RulesCompared)
+	}
+	ruleSync.Status.Parity = result
+	utils.SetCondition(&ruleSync.Status.Conditions, condition)
+}
@@ -3,41 +3,123 @@ package monitoringcoreoscom
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"maps"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/go-logr/logr"
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"github.com/prometheus/common/model"
 	"github.com/prometheus/prometheus/model/rulefmt"
+	"github.com/prometheus/prometheus/promql/parser"
 	openawarenessv1beta1 "github.com/syndlex/openawareness-controller/api/openawareness/v1beta1"
+	"github.com/syndlex/openawareness-controller/internal/backup"
 	"github.com/syndlex/openawareness-controller/internal/clients"
+	"github.com/syndlex/openawareness-controller/internal/conflicts"
 	"github.com/syndlex/openawareness-controller/internal/controller/utils"
-	corev1 "k8s.io/api/core/v1"
+	"github.com/syndlex/openawareness-controller/internal/events"
+	"github.com/syndlex/openawareness-controller/internal/logging"
+	"github.com/syndlex/openawareness-controller/internal/metrics"
+	"github.com/syndlex/openawareness-controller/internal/mimir"
+	"github.com/syndlex/openawareness-controller/internal/operatorconfig"
+	"github.com/syndlex/openawareness-controller/internal/parity"
+	"github.com/syndlex/openawareness-controller/internal/policy"
+	"gopkg.in/yaml.v3"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/cluster"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 )
 
 // PrometheusRulesReconciler reconciles a PrometheusRules object
 type PrometheusRulesReconciler struct {
-	RulerClients *clients.RulerClientCache
+	RulerClients clients.RulerClientCacheInterface
 	client.Client
 	Scheme   *runtime.Scheme
 	Recorder record.EventRecorder
+	// VerifyActivation enables checking the ruler's runtime state after each sync
+	VerifyActivation bool
+	// RulePolicy checks alerting rules before they are pushed, nil if not configured
+	RulePolicy *policy.RulePolicy
+	// SyncTimeout bounds the Mimir API operations of a reconciliation unless the rule
+	// sets the sync-timeout annotation, zero disables the timeout
+	SyncTimeout time.Duration
+	// ShutdownGrace is the time a reconciliation in flight may finish after the manager stops,
+	// interrupted rules are retried first on start, see utils.SyncReconciler
+	ShutdownGrace time.Duration
+	// ResyncPacer paces the re-push of rules after controller upgrades, see utils.ResyncPacer
+	ResyncPacer *utils.ResyncPacer
+	// ResourceCluster is the hub cluster PrometheusRules are read from, the manager's
+	// cluster if nil. Client must operate on the same cluster, see utils.NewHubClient.
+	ResourceCluster cluster.Cluster
+	// MaxConcurrentReconciles is the number of PrometheusRules reconciled in parallel, 1 if zero
+	MaxConcurrentReconciles int
+	// ExtraLabels are added to every alerting rule, only the extra-labels annotation applies if nil
+	ExtraLabels *utils.ExtraLabels
+	// DetectConflicts reports recording and alerting rule names defined by several resources
+	// in the same tenant after each sync
+	DetectConflicts bool
+	// DetectDrift reads every rule group from Mimir before it is pushed, reporting and repairing
+	// groups modified outside the operator, see reportDrift
+	DetectDrift bool
+	// Backup keeps the last pushed rule groups of every rule, no backup is kept if nil
+	Backup *backup.Store
+	// SimulateRules runs the expressions of alerting rules against the data of their tenant
+	// before they are pushed, see simulateRuleGroup
+	SimulateRules bool
+	// OperatorConfig overrides RulePolicy, SyncTimeout, the synced rules and their retry and
+	// resync at runtime, they are used as configured if nil
+	OperatorConfig *operatorconfig.Source
+	// ParityPrometheus counts the series produced by the rules of PrometheusRules with the
+	// DualWriteAnnotation in the in-cluster Prometheus, see reportParity
+	ParityPrometheus parity.SeriesCounter
+	// ReadOnly diffs the rule groups against Mimir instead of pushing them and adds no
+	// finalizers, see utils.SyncReconciler
+	ReadOnly bool
+	// Identity names this installation in the instance label of every pushed rule,
+	// see utils.InstanceIdentity
+	Identity string
+
+	// resyncs pushes unchanged rule groups again once the resync interval elapsed
+	resyncs utils.ResyncTracker
 }
 
+const (
+	// activationRecheckInterval is the first requeue delay while pushed rule groups are not yet active
+	activationRecheckInterval = 30 * time.Second
+	// maxActivationRecheckInterval caps the requeue delay of rule groups that stay inactive
+	maxActivationRecheckInterval = 10 * time.Minute
+)
+
 // +kubebuilder:rbac:groups=monitoring.coreos.com,resources=prometheusrules,verbs=get;list;watch;create;update;patch;delete
 //nolint:lll
 // +kubebuilder:rbac:groups=monitoring.coreos.com,resources=prometheusrules/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=monitoring.coreos.com,resources=prometheusrules/finalizers,verbs=update
 // +kubebuilder:rbac:groups=openawareness.syndlex,resources=clientconfigs,verbs=get;list;watch
+// +kubebuilder:rbac:groups=openawareness.syndlex,resources=tenantmappings,verbs=get;list;watch
+// +kubebuilder:rbac:groups=openawareness.syndlex,resources=operatorconfigs,verbs=get;list;watch
+// +kubebuilder:rbac:groups=openawareness.syndlex,resources=prometheusrulesyncs,verbs=get;list;watch;create;update;patch
+// +kubebuilder:rbac:groups=openawareness.syndlex,resources=prometheusrulesyncs/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+// +kubebuilder:rbac:groups=core,resources=namespaces,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch
 
 // Reconcile reconciles the PrometheusRule resource by syncing rule groups
 // to the configured Mimir instance. It handles the full lifecycle including creation,
@@ -46,180 +128,1514 @@ type PrometheusRulesReconciler struct {
 // Note: Status management is not implemented for PrometheusRule resources because
 // the prometheus-operator v0.88.1 ConfigResourceStatus type does not include a
 // Conditions field. Status updates are only supported for custom CRDs (ClientConfig
-// and MimirAlertTenant) that define their own status structures.
+// and MimirAlertTenant) that define their own status structures. Outcomes, including
+// the paused state, are therefore surfaced as events, and the group counts of each sync
+// are summarized in the status of a companion PrometheusRuleSync, see reportSyncStatus.
 //
-// The reconciliation process:
+// The reconciliation follows utils.SyncReconciler with prometheusRuleSync as adapter:
 // 1. Fetches the PrometheusRule resource
-// 2. Retrieves the Mimir client from annotations
+// 2. Resolves the referenced ClientConfig, skips syncing while it is disconnected and
+// retrieves the Mimir client for it
 // 3. Adds finalizer for cleanup on deletion
-// 4. Converts and pushes rule groups to Mimir API
-// 5. On deletion, removes rule groups from Mimir and cleans up finalizer
+// 4. Converts rule groups, checks alerting rules against the rule policy, which may block
+// the push, and validates the groups
+// 5. Labels every rule with its owner and pushes the rule groups to Mimir API
+// 6. Optionally verifies that the ruler evaluates the pushed groups
+// 7. On deletion, removes rule groups from Mimir and cleans up finalizer
 //
 // For more details, check Reconcile and its Result here:
 // - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.19.0/pkg/reconcile
 func (r *PrometheusRulesReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
-	logger := log.FromContext(ctx)
+	settings := r.OperatorConfig.Apply(ctx, operatorconfig.Settings{
+		SyncTimeout: r.SyncTimeout,
+		RulePolicy:  r.RulePolicy,
+	})
+	reconciler := &utils.SyncReconciler[*monitoringv1.PrometheusRule, []rulefmt.RuleGroup]{
+		Client:    r.Client,
+		Adapter:   &prometheusRuleSync{r: r, settings: settings},
+		Kind:      metrics.KindPrometheusRule,
+		Finalizer: utils.FinalizerAnnotation,
+		Pacer:     r.ResyncPacer,
+		// Rules never synced to a client do not block their deletion
+		ResolveBeforeFinalizer: true,
+		Selector:               settings.RuleSelector,
+		ResyncInterval:         settings.ResyncInterval,
+		Resyncs:                &r.resyncs,
+		ShutdownGrace:          r.ShutdownGrace,
+		ReadOnly:               r.ReadOnly,
+	}
+	return reconciler.Reconcile(ctx, req)
+}
+
+// errRulePolicyBlocked reports rule groups not pushed because of blocking rule policy violations.
+var errRulePolicyBlocked = errors.New("rule groups violate the rule policy")
+
+// errExtraLabels reports extra labels that could not be determined.
+var errExtraLabels = errors.New("unable to determine extra labels")
 
-	rule := &monitoringv1.PrometheusRule{}
-	if err := r.Get(ctx, req.NamespacedName, rule); err != nil {
-		return ctrl.Result{}, client.IgnoreNotFound(err)
+// clientError reports why the Mimir client of a PrometheusRule could not be resolved.
+type clientError struct {
+	// reason is the reason of the warning event
+	reason events.Reason
+	// requeueAfter is the delay before the client is resolved again
+	requeueAfter time.Duration
+	err          error
+}
+
+func (e *clientError) Error() string {
+	return e.err.Error()
+}
+
+func (e *clientError) Unwrap() error {
+	return e.err
+}
+
+// prometheusRuleSync adapts PrometheusRules to utils.SyncReconciler. PrometheusRules have no
+// conditions, so Report emits events.
+type prometheusRuleSync struct {
+	r *PrometheusRulesReconciler
+	// settings are the settings of the reconciler with the OperatorConfig applied
+	settings operatorconfig.Settings
+	// skipped is the number of rule groups Push did not push because they are unchanged
+	skipped int
+	// desired is the number of rule groups of all tenants, synced the number of groups Push
+	// pushed or skipped, see reportSyncStatus
+	desired, synced int
+	// failure is the first rule group Push failed to push
+	failure error
+	// failed are the rule groups Push failed to push in the form tenant/group
+	failed []string
+}
+
+// maxFailedGroups bounds the failed rule groups listed in the PrometheusRuleSync status
+const maxFailedGroups = 20
+
+// maxRewriteDiffLength bounds the expression diff in ExpressionRewritePreview events
+const maxRewriteDiffLength = 1024
+
+// NewObject returns an empty PrometheusRule.
+func (s *prometheusRuleSync) NewObject() *monitoringv1.PrometheusRule {
+	return &monitoringv1.PrometheusRule{}
+}
+
+// SyncTimeout returns the timeout of the Mimir API operations for the rule. An invalid
+// sync-timeout annotation is reported as event and the default timeout is used.
+func (s *prometheusRuleSync) SyncTimeout(rule *monitoringv1.PrometheusRule) time.Duration {
+	timeout, err := utils.SyncTimeout(rule, s.settings.SyncTimeout)
+	if err != nil {
+		events.Event(s.r.Recorder, rule, events.InvalidSyncTimeout, err.Error())
 	}
-	logger.Info("Found Rule", "name", rule.Name, "namespace", rule.Namespace)
+	return timeout
+}
 
-	alertManagerClient, err := r.clientFromAnnotation(ctx, logger, rule)
+// Resolve returns the Mimir client of the ClientConfig referenced by the rule.
+// Returns a clientError if the ClientConfig is missing, disconnected or its client cannot be created,
+// or while the circuit breaker of the client is open.
+func (s *prometheusRuleSync) Resolve(
+	ctx context.Context,
+	state *utils.SyncState[*monitoringv1.PrometheusRule],
+) (clients.AwarenessClient, error) {
+	logger := log.FromContext(ctx)
+	rule := state.Object
+
+	clientConfig, err := utils.GetClientConfig(ctx, s.r.Client, rule)
 	if err != nil {
-		r.Recorder.Event(rule, corev1.EventTypeWarning, "ClientNotFound",
-			fmt.Sprintf("No client configuration found: %v", err))
 		logger.Info(
 			"Client not found, will retry in 5 seconds. Please create a new "+openawarenessv1beta1.GroupVersion.Group+" ClientConfig",
-			"name", rule.Name,
-			"namespace", rule.Namespace,
 			"error", err.Error(),
 		)
-		// Requeue to retry when client becomes available
-		return ctrl.Result{RequeueAfter: time.Second * 5}, nil
+		return nil, &clientError{
+			reason:       events.ClientNotFound,
+			requeueAfter: time.Second * 5,
+			err:          fmt.Errorf("no client configuration found: %w", err),
+		}
+	}
+
+	state.ClientConfig = clientConfig
+	tenantID := s.r.getNamespaceFromAnnotations(logger, rule, clientConfig)
+	state.Reconciliation.SetTarget(tenantID, clientConfig.Name)
+
+	// Skip push attempts while the ClientConfig reports a broken connection.
+	// The ClientConfig watch re-queues this rule once the connection recovers.
+	if clientConfig.Status.ConnectionStatus == openawarenessv1beta1.ConnectionStatusDisconnected {
+		logger.Info("ClientConfig is disconnected, skipping sync",
+			logging.KeyClient, clientConfig.Name,
+			"connectionStatus", clientConfig.Status.ConnectionStatus,
+			"clientError", clientConfig.Status.ErrorMessage,
+		)
+		return nil, &clientError{
+			reason:       events.ClientDisconnected,
+			requeueAfter: time.Minute,
+			err: fmt.Errorf("ClientConfig %s is %s: %s", clientConfig.Name, clientConfig.Status.ConnectionStatus,
+				clientConfig.Status.ErrorMessage),
+		}
+	}
+
+	alertManagerClient, err := s.r.clientFromConfig(ctx, logger, rule, clientConfig)
+	if err != nil {
+		return nil, &clientError{
+			reason:       events.ClientUnavailable,
+			requeueAfter: time.Second * 5,
+			err: fmt.Errorf("unable to create client for ClientConfig %s (status %q): %w",
+				clientConfig.Name, clientConfig.Status.ConnectionStatus, err),
+		}
 	}
 
-	tenantID := r.getNamespaceFromAnnotations(logger, rule)
+	// Mimir is not contacted while it fails, the rule is pushed once the cool-down ends
+	if breaker, ok := alertManagerClient.(clients.CircuitBreakerClient); ok {
+		if openUntil := breaker.CircuitOpenUntil(); !openUntil.IsZero() {
+			return nil, &clientError{
+				reason:       events.CircuitOpen,
+				requeueAfter: time.Until(openUntil),
+				err: fmt.Errorf("requests of ClientConfig %s are paused until %s after consecutive server errors",
+					clientConfig.Name, openUntil.UTC().Format(time.RFC3339)),
+			}
+		}
+	}
+	return alertManagerClient, nil
+}
+
+// Render renders the templates of the rule if it opts in to templating, see
+// ResolveRuleTemplates, converts its rule groups to Mimir rule groups and adds the extra
+// labels to its alerting rules. Intervals below the minimum of the ClientConfig are clamped
+// unless it rejects them, see clampRuleIntervals.
+func (s *prometheusRuleSync) Render(
+	ctx context.Context,
+	state *utils.SyncState[*monitoringv1.PrometheusRule],
+) ([]rulefmt.RuleGroup, error) {
+	rule, err := ResolveRuleTemplates(ctx, s.r.Client, state.Object)
+	if err != nil {
+		return nil, err
+	}
+	groups, err := DesiredRuleGroups(rule)
+	if err != nil {
+		return nil, err
+	}
+	utils.SetInstanceLabel(groups, s.r.Identity)
+	if rule.Annotations[utils.InjectMatchersDryRunAnnotation] == "true" {
+		rewrites, err := rewriteExpressions(rule, groups)
+		if err != nil {
+			return nil, err
+		}
+		if len(rewrites) > 0 {
+			events.Eventf(s.r.Recorder, state.Object, events.ExpressionRewritePreview,
+				"Injecting the matchers of %s would rewrite %d expression(s):\n%s",
+				utils.InjectMatchersAnnotation, len(rewrites), utils.RewriteDiff(rewrites, maxRewriteDiffLength))
+		}
+	}
+	labels, err := s.r.ExtraLabels.For(ctx, state.Object)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errExtraLabels, err)
+	}
+	utils.InjectLabels(groups, labels)
+	if clamped := clampRuleIntervals(state.ClientConfig, groups); len(clamped) > 0 {
+		events.Eventf(s.r.Recorder, state.Object, events.RuleIntervalClamped,
+			"Raised the interval of rule group(s) %s to the minimum %s of ClientConfig %s",
+			strings.Join(clamped, ", "), state.ClientConfig.Spec.MinRuleInterval.Minimum.Duration,
+			state.ClientConfig.Name)
+	}
+	return groups, nil
+}
+
+// Validate checks the alerting rules against the rule policy, the intervals against the
+// minimum of the ClientConfig and validates the rule groups. Blocking policy violations are
+// reported as errRulePolicyBlocked, rejected intervals as errRuleIntervalTooShort.
+func (s *prometheusRuleSync) Validate(
+	ctx context.Context,
+	state *utils.SyncState[*monitoringv1.PrometheusRule],
+	groups []rulefmt.RuleGroup,
+) error {
+	if !s.r.checkRulePolicy(log.FromContext(ctx), state.Object, s.settings.RulePolicy) {
+		return errRulePolicyBlocked
+	}
+	if err := checkRuleIntervals(state.ClientConfig, groups); err != nil {
+		return err
+	}
+	return errors.Join(ValidateRuleGroups(groups)...)
+}
 
-	if rule.DeletionTimestamp.IsZero() {
-		// Register finalizer
-		if !controllerutil.ContainsFinalizer(rule, utils.FinalizerAnnotation) {
-			controllerutil.AddFinalizer(rule, utils.FinalizerAnnotation)
-			if err := r.Update(ctx, rule); err != nil {
-				return ctrl.Result{}, err
+// Push creates or updates the rule groups in Mimir, each partition in its tenant, in the rule
+// namespace of utils.RulesNamespace, see PartitionRuleGroups. Groups whose checksum matches the
+// GroupChecksumsAnnotation are skipped, unless the resync interval elapsed, see
+// utils.SyncState.Resync, and changed groups are simulated first if SimulateRules is set. A group
+// that fails to push does not stop the other groups, all failures are returned joined. Groups
+// pushed before but no longer part of the rule, e.g. removed or renamed ones, are pruned, see
+// pruneRuleGroups. The checksums of the synced groups are recorded, failed groups keep their
+// previous checksum so they are pushed again. Nothing is pushed if the ClientConfig does not
+// allow one of the tenants or source tenants, utils.ErrTenantNotAllowed is returned instead.
+// Groups with source tenants are evaluated against them, see PushRuleGroup. With DetectDrift,
+// groups modified in Mimir since they were pushed are reported and pushed again, see reportDrift.
+//
+// When the rule namespace changed, all groups are pushed to the new namespace and removed from the
+// one they were synced to once all pushes succeeded, see utils.SyncedRulesNamespace.
+func (s *prometheusRuleSync) Push(
+	ctx context.Context,
+	state *utils.SyncState[*monitoringv1.PrometheusRule],
+	alertManagerClient clients.AwarenessClient,
+	groups []rulefmt.RuleGroup,
+) error {
+	rule := state.Object
+	partitions := PartitionRuleGroups(rule, groups, state.ClientConfig)
+	s.desired = countGroups(rule, state.ClientConfig, partitions)
+	// Validated by DesiredRuleGroups
+	sourceTenants, _ := utils.SourceTenants(rule, state.ClientConfig)
+	allowed := utils.TenantIDs(rule, state.ClientConfig)
+	for _, tenantIDs := range sourceTenants {
+		allowed = append(allowed, tenantIDs...)
+	}
+	if err := utils.CheckTenantsAllowed(state.ClientConfig, allowed...); err != nil {
+		return err
+	}
+	namespace := utils.RulesNamespace(rule)
+	moved := utils.SyncedRulesNamespace(rule) != namespace
+	recorded := utils.GroupChecksumsOf(rule)
+	// The recorded groups were pushed to another namespace, none of them is unchanged
+	unchanged := recorded
+	if moved {
+		unchanged = nil
+	}
+	checksums := utils.GroupChecksums{}
+	var failures []error
+	fail := func(tenantID, group string, err error) {
+		failures = append(failures,
+			fmt.Errorf("rule group %s in namespace %s for tenant %s: %w", group, namespace, tenantID, err))
+		s.failed = append(s.failed, tenantID+"/"+group)
+		// The previous checksum keeps the group pushed before prunable and differs from the new one
+		if previous, ok := recorded[tenantID][group]; ok {
+			checksums[tenantID][group] = previous
+		}
+	}
+	for _, tenantID := range utils.TenantIDs(rule, state.ClientConfig) {
+		// Tenants without groups are recorded too, so that their groups are pruned by the checksums
+		checksums[tenantID] = map[string]string{}
+		for _, group := range partitions[tenantID] {
+			sources := sourceTenants[group.Name]
+			checksum, err := utils.RuleGroupChecksum(state.ClientConfig, tenantID, group, sources...)
+			if err != nil {
+				fail(tenantID, group.Name, fmt.Errorf("computing checksum: %w", err))
+				continue
+			}
+			skip := !state.Resync && unchanged.Unchanged(tenantID, group.Name, checksum)
+			if s.r.DetectDrift && s.r.reportDrift(ctx, log.FromContext(ctx), rule, state.ClientConfig,
+				alertManagerClient, namespace, tenantID, group, sources, unchanged[tenantID][group.Name]) {
+				skip = false
+			}
+			if skip {
+				s.skipped++
+			} else {
+				if s.r.SimulateRules {
+					s.r.simulateRuleGroup(ctx, rule, alertManagerClient, group, tenantID)
+				}
+				if err := PushRuleGroup(ctx, alertManagerClient, namespace, group, sources, tenantID); err != nil {
+					fail(tenantID, group.Name, err)
+					continue
+				}
 			}
+			s.synced++
+			checksums[tenantID][group.Name] = checksum
+		}
+	}
+	if len(failures) > 0 {
+		s.failure = failures[0]
+	}
+	// The groups stay in the namespace they were synced to until all are pushed to the new one
+	if !moved || len(failures) == 0 {
+		if err := s.r.pruneRuleGroups(ctx, rule, state.ClientConfig, alertManagerClient, recorded, partitions); err != nil {
+			return errors.Join(append(failures, err)...)
 		}
-		groups := convert(rule.Spec.Groups)
-		for _, group := range groups {
-			err := alertManagerClient.CreateRuleGroup(ctx, rule.Namespace, group, tenantID)
+	}
+	if err := utils.SetGroupChecksums(ctx, s.r.Client, rule, checksums); err != nil {
+		return errors.Join(append(failures, fmt.Errorf("recording the rule group checksums: %w", err))...)
+	}
+	if len(failures) > 0 {
+		return errors.Join(failures...)
+	}
+	if err := utils.SetSyncedRulesNamespace(ctx, s.r.Client, rule, namespace); err != nil {
+		return fmt.Errorf("recording the rule namespace: %w", err)
+	}
+	s.r.updateBackup(ctx, rule, func(clientConfig *openawarenessv1beta1.ClientConfig) error {
+		owner := types.NamespacedName{Namespace: rule.Namespace, Name: rule.Name}
+		var errs []error
+		for _, tenantID := range utils.TenantIDs(rule, clientConfig) {
+			if len(partitions[tenantID]) == 0 {
+				errs = append(errs, s.r.Backup.RemoveRuleGroups(ctx, clientConfig, tenantID, owner))
+				continue
+			}
+			errs = append(errs, s.r.Backup.SaveRuleGroups(ctx, clientConfig, tenantID, owner, namespace,
+				partitions[tenantID]))
+		}
+		return errors.Join(errs...)
+	})
+	return nil
+}
+
+// Diff summarizes how pushing the rule groups would change Mimir: the groups of every tenant that
+// would be created, the groups that would be updated with how they differ, compared by checksum
+// like reportDrift, and the groups owned by the rule that would be pruned from the rule
+// namespace. Nothing is changed in Mimir. Nothing is read if the ClientConfig does not allow one
+// of the tenants or source tenants, utils.ErrTenantNotAllowed is returned instead.
+func (s *prometheusRuleSync) Diff(
+	ctx context.Context,
+	state *utils.SyncState[*monitoringv1.PrometheusRule],
+	alertManagerClient clients.AwarenessClient,
+	groups []rulefmt.RuleGroup,
+) (string, error) {
+	rule := state.Object
+	partitions := PartitionRuleGroups(rule, groups, state.ClientConfig)
+	// Validated by DesiredRuleGroups
+	sourceTenants, _ := utils.SourceTenants(rule, state.ClientConfig)
+	allowed := utils.TenantIDs(rule, state.ClientConfig)
+	for _, tenantIDs := range sourceTenants {
+		allowed = append(allowed, tenantIDs...)
+	}
+	if err := utils.CheckTenantsAllowed(state.ClientConfig, allowed...); err != nil {
+		return "", err
+	}
+
+	namespace := utils.RulesNamespace(rule)
+	var changes []string
+	for _, tenantID := range utils.TenantIDs(rule, state.ClientConfig) {
+		remote, err := alertManagerClient.ListRules(ctx, namespace, tenantID)
+		if err != nil && !errors.Is(err, mimir.ErrResourceNotFound) {
+			return "", fmt.Errorf("listing rule groups of namespace %s for tenant %s: %w", namespace, tenantID, err)
+		}
+		for _, group := range partitions[tenantID] {
+			index := slices.IndexFunc(remote[namespace], func(g rulefmt.RuleGroup) bool { return g.Name == group.Name })
+			if index < 0 {
+				changes = append(changes, fmt.Sprintf("create %s/%s", tenantID, group.Name))
+				continue
+			}
+			current := remote[namespace][index]
+			sources := sourceTenants[group.Name]
+			desired, err := utils.RuleGroupChecksum(state.ClientConfig, tenantID, group, sources...)
 			if err != nil {
-				r.Recorder.Eventf(rule, corev1.EventTypeWarning, "RuleGroupCreateFailed",
-					"Failed to create rule group %s in namespace %s for tenant %s: %v", group.Name, rule.Namespace, tenantID, err)
-				logger.Error(err, "Failed to create rule group", "group", group.Name, "namespace", rule.Namespace, "tenantID", tenantID)
-				return ctrl.Result{}, err
+				return "", err
 			}
+			if actual, err := utils.RuleGroupChecksum(state.ClientConfig, tenantID, current, sources...); err != nil ||
+				actual != desired {
+				changes = append(changes, fmt.Sprintf("update %s/%s: %s",
+					tenantID, group.Name, strings.Join(ruleGroupChanges(current, group), ", ")))
+			}
+		}
+		for _, name := range ownedRemovedGroups(remote[namespace], utils.OwnerReference(rule), partitions[tenantID]) {
+			changes = append(changes, fmt.Sprintf("delete %s/%s", tenantID, name))
+		}
+	}
+	return strings.Join(changes, "\n"), nil
+}
+
+// Delete removes the rule groups of the rule from Mimir, from every tenant they were
+// partitioned to, including groups recorded in the GroupChecksumsAnnotation that were renamed
+// or moved to another tenant since the last sync, and groups left in the rule namespace they
+// were synced to before the rule namespace changed. Tenants the ClientConfig does not allow are
+// skipped, nothing was pushed to them.
+// A group that fails to delete does not stop the other groups, all failures are returned joined,
+// which keeps the finalizer so the deletion is retried.
+func (s *prometheusRuleSync) Delete(
+	ctx context.Context,
+	state *utils.SyncState[*monitoringv1.PrometheusRule],
+	alertManagerClient clients.AwarenessClient,
+) error {
+	rule := state.Object
+	partitions := PartitionRuleGroups(rule, ruleKinds(rule.Spec.Groups), state.ClientConfig)
+	recorded := utils.GroupChecksumsOf(rule)
+	namespace, syncedNamespace := utils.RulesNamespace(rule), utils.SyncedRulesNamespace(rule)
+	var failures []error
+	for _, tenantID := range syncedTenants(rule, state.ClientConfig, recorded) {
+		if !state.ClientConfig.TenantAllowed(tenantID) {
+			continue
+		}
+		for _, group := range partitions[tenantID] {
+			if err := alertManagerClient.DeleteRuleGroup(ctx, namespace, group.Name, tenantID); err != nil {
+				failures = append(failures,
+					fmt.Errorf("rule group %s from namespace %s for tenant %s: %w", group.Name, namespace, tenantID, err))
+			}
+		}
+		_, err := deleteRuleGroups(ctx, alertManagerClient, namespace, tenantID,
+			recorded.Removed(tenantID, partitions[tenantID]))
+		failures = append(failures, err)
+		if syncedNamespace != namespace {
+			_, err := deleteRuleGroups(ctx, alertManagerClient, syncedNamespace, tenantID, recorded.Removed(tenantID, nil))
+			failures = append(failures, err)
+		}
+	}
+	if err := errors.Join(failures...); err != nil {
+		return err
+	}
+	s.r.updateBackup(ctx, rule, func(clientConfig *openawarenessv1beta1.ClientConfig) error {
+		owner := types.NamespacedName{Namespace: rule.Namespace, Name: rule.Name}
+		var errs []error
+		for _, tenantID := range utils.TenantIDs(rule, clientConfig) {
+			errs = append(errs, s.r.Backup.RemoveRuleGroups(ctx, clientConfig, tenantID, owner))
+		}
+		return errors.Join(errs...)
+	})
+	return nil
+}
+
+// Report emits the outcome as event and summarizes it in the PrometheusRuleSync of the rule,
+// see reportSyncStatus. Client failures are retried after the delay of the clientError,
+// invalid or blocked rule groups wait for spec changes, push and deletion failures and syncs
+// interrupted by a shutdown are returned for retry. In read-only mode only the WouldSync
+// condition of the PrometheusRuleSync is set, see reportWouldSync.
+func (s *prometheusRuleSync) Report(
+	ctx context.Context,
+	state *utils.SyncState[*monitoringv1.PrometheusRule],
+	outcome utils.SyncOutcome[[]rulefmt.RuleGroup],
+) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+	rule := state.Object
+	recorder := s.r.Recorder
+
+	// Read-only controllers only record whether the sync would succeed
+	if state.ReadOnly && outcome.Stage != utils.SyncStagePaused {
+		s.reportWouldSync(ctx, state, outcome)
+		return utils.ReadOnlyResult(outcome.Stage, outcome.Err)
+	}
+
+	// Interrupted deletions keep the finalizer like other failed deletions
+	if errors.Is(outcome.Err, utils.ErrSyncInterrupted) && outcome.Stage != utils.SyncStageDelete {
+		events.Event(recorder, rule, events.Interrupted,
+			"Sync was interrupted by a controller shutdown, it is retried on start")
+		s.reportSyncStatus(ctx, state, openawarenessv1beta1.ReasonInterrupted, outcome.Err)
+		return ctrl.Result{}, outcome.Err
+	}
+
+	switch outcome.Stage {
+	case utils.SyncStageResolve:
+		reason, requeueAfter := events.ClientUnavailable, time.Second*5
+		var clientErr *clientError
+		if errors.As(outcome.Err, &clientErr) {
+			reason, requeueAfter = clientErr.reason, clientErr.requeueAfter
+		}
+		events.Event(recorder, rule, reason, capitalize(outcome.Err.Error()))
+		s.r.reportSyncTimeout(rule, outcome.Err, state.Timeout)
+		s.reportSyncStatus(ctx, state, string(reason), outcome.Err)
+		// Requeue to retry when client becomes available
+		return ctrl.Result{RequeueAfter: requeueAfter}, nil
+	case utils.SyncStageRender:
+		events.Eventf(recorder, rule, events.InvalidRuleGroups,
+			"Failed to convert rule groups: %v", outcome.Err)
+		logger.Error(outcome.Err, "Failed to convert rule groups")
+		s.reportSyncStatus(ctx, state, openawarenessv1beta1.ReasonInvalidRuleGroups, outcome.Err)
+		// The namespace may not be readable yet, namespace label changes are not watched.
+		// Missing template data is picked up by the ConfigMap and Secret watches, errors
+		// reading it are retried.
+		if errors.Is(outcome.Err, errExtraLabels) || isTemplateReadError(outcome.Err) {
+			return ctrl.Result{}, outcome.Err
+		}
+		// Spec changes trigger a new reconciliation, retrying does not help
+		return ctrl.Result{}, nil
+	case utils.SyncStageValidate:
+		switch {
+		case errors.Is(outcome.Err, errRulePolicyBlocked):
+			s.reportSyncStatus(ctx, state, openawarenessv1beta1.ReasonRuleGroupsBlocked, outcome.Err)
+		case errors.Is(outcome.Err, errRuleIntervalTooShort):
+			events.Event(recorder, rule, events.RuleIntervalTooShort,
+				"Rule groups are not synced: "+outcome.Err.Error())
+			logger.Info("Rule group intervals are below the minimum of the ClientConfig, refusing the push",
+				"error", outcome.Err.Error())
+			s.reportSyncStatus(ctx, state, openawarenessv1beta1.ReasonRuleIntervalTooShort, outcome.Err)
+		default:
+			events.Eventf(recorder, rule, events.InvalidRuleGroups,
+				"Rule groups are invalid: %v", outcome.Err)
+			logger.Error(outcome.Err, "Invalid rule groups")
+			s.reportSyncStatus(ctx, state, openawarenessv1beta1.ReasonInvalidRuleGroups, outcome.Err)
+		}
+		// Spec changes trigger a new reconciliation, retrying does not help
+		return ctrl.Result{}, nil
+	case utils.SyncStagePaused:
+		events.Event(recorder, rule, events.SyncPaused,
+			"Remote changes are paused via the "+utils.PausedAnnotation+" annotation")
+		return ctrl.Result{}, nil
+	case utils.SyncStagePush:
+		if errors.Is(outcome.Err, utils.ErrTenantNotAllowed) {
+			events.Event(recorder, rule, events.TenantNotAllowed,
+				"Rule groups are not synced: "+outcome.Err.Error())
+			logger.Info("Tenant is not allowed by the ClientConfig, refusing the push",
+				"error", outcome.Err.Error())
+			s.reportSyncStatus(ctx, state, openawarenessv1beta1.ReasonTenantNotAllowed, outcome.Err)
+			// ClientConfig and annotation changes trigger a new reconciliation, retrying does not help
+			return ctrl.Result{}, nil
+		}
+		if failed := s.desired - s.synced; failed > 0 {
+			events.Eventf(recorder, rule, events.RuleGroupCreateFailed,
+				"Failed to create %d of %d rule group(s): %v", failed, s.desired, outcome.Err)
+		} else {
+			events.Eventf(recorder, rule, events.RuleGroupCreateFailed, "Failed to create %v", outcome.Err)
+		}
+		s.r.reportSyncTimeout(rule, outcome.Err, state.Timeout)
+		logger.Error(outcome.Err, "Failed to create rule group",
+			"desiredCount", s.desired, "syncedCount", s.synced)
+		failure := s.failure
+		if failure == nil {
+			failure = outcome.Err
 		}
+		reason, _ := utils.CategorizeError(failure)
+		s.reportSyncStatus(ctx, state, reason, failure)
+		return ctrl.Result{}, outcome.Err
+	case utils.SyncStageDelete:
+		if outcome.Err != nil {
+			events.Eventf(recorder, rule, events.RuleGroupDeleteFailed, "Failed to delete %v", outcome.Err)
+			s.r.reportSyncTimeout(rule, outcome.Err, state.Timeout)
+			logger.Error(outcome.Err, "Failed to delete rule group")
+			return ctrl.Result{}, outcome.Err
+		}
+		events.Event(recorder, rule, events.RuleGroupsDeleted,
+			"Successfully deleted all rule groups from Mimir")
+		return ctrl.Result{}, nil
+	}
 
-		r.Recorder.Eventf(rule, corev1.EventTypeNormal, "RuleGroupsSynced",
+	groups := outcome.Payload
+	s.reportSyncStatus(ctx, state, "", nil)
+	if s.skipped > 0 {
+		events.Eventf(recorder, rule, events.RuleGroupsSynced,
+			"Successfully synced %d rule group(s) to Mimir, skipped %d unchanged group(s)", len(groups), s.skipped)
+	} else {
+		events.Eventf(recorder, rule, events.RuleGroupsSynced,
 			"Successfully synced %d rule group(s) to Mimir", len(groups))
-		logger.Info("Successfully synced all rule groups",
-			"name", rule.Name,
-			"namespace", rule.Namespace,
-			"groupCount", len(groups))
+	}
+	logging.SuccessInfo(logger, utils.OwnerReference(rule), "Successfully synced all rule groups",
+		"groupCount", len(groups),
+		"skippedCount", s.skipped)
+
+	if s.r.DetectConflicts {
+		s.r.reportConflicts(state.SyncContext, logger, rule, state.ClientConfig, outcome.Remote, groups)
+	}
 
+	var result ctrl.Result
+	if utils.DualWriteEnabled(rule) {
+		result = s.reportParity(ctx, state, outcome.Remote, groups)
+	}
+
+	if s.r.VerifyActivation {
+		if activation := s.reportActivation(ctx, state, outcome.Remote, groups); !activation.IsZero() {
+			return activation, nil
+		}
+	}
+	return result, nil
+}
+
+// reportSyncStatus summarizes the sync in the status of the PrometheusRuleSync of the rule, which
+// is created owned by the rule if missing. reason and err describe the earliest failure, err is
+// nil if the sync succeeded. Without a push, e.g. for invalid rule groups, all rule groups of the
+// rule count as failed. The PartiallySynced condition is true while some groups synced and
+// others failed, which are listed. After a successful sync the tenants and the ClientConfig
+// the groups were synced to are recorded, failed syncs keep those of the last successful one.
+// The summary must not fail the sync, errors are logged.
+func (s *prometheusRuleSync) reportSyncStatus(
+	ctx context.Context,
+	state *utils.SyncState[*monitoringv1.PrometheusRule],
+	reason string,
+	err error,
+) {
+	logger := log.FromContext(ctx)
+	rule := state.Object
+	desired := s.desired
+	if desired == 0 {
+		desired = countGroups(rule, state.ClientConfig,
+			PartitionRuleGroups(rule, ruleKinds(rule.Spec.Groups), state.ClientConfig))
+	}
+
+	ruleSync, createErr := s.ruleSync(ctx, rule)
+	if createErr != nil {
+		logger.Error(createErr, "Failed to create PrometheusRuleSync")
+		return
+	}
+
+	original := ruleSync.DeepCopy()
+	ruleSync.Status.ObservedGeneration = rule.Generation
+	ruleSync.Status.GroupsDesired = int32(desired)
+	ruleSync.Status.GroupsSynced = int32(s.synced)
+	ruleSync.Status.GroupsFailed = int32(desired - s.synced)
+	ruleSync.Status.FailedGroups = s.failed[:min(len(s.failed), maxFailedGroups)]
+	ruleSync.Status.FailureReason, ruleSync.Status.FailureMessage = "", ""
+	condition := metav1.Condition{
+		Type:               openawarenessv1beta1.ConditionTypeSynced,
+		Status:             metav1.ConditionTrue,
+		Reason:             openawarenessv1beta1.ReasonSynced,
+		Message:            fmt.Sprintf("%d of %d rule group(s) synced to Mimir", s.synced, desired),
+		ObservedGeneration: rule.Generation,
+	}
+	if err != nil {
+		ruleSync.Status.FailureReason, ruleSync.Status.FailureMessage = reason, utils.StatusMessage(err)
+		condition.Status, condition.Reason = metav1.ConditionFalse, reason
 	} else {
-		for _, group := range rule.Spec.Groups {
-			err := alertManagerClient.DeleteRuleGroup(ctx, rule.Namespace, group.Name, tenantID)
+		ruleSync.Status.ResolvedTenants = utils.TenantIDs(rule, state.ClientConfig)
+		ruleSync.Status.ResolvedEndpoint, ruleSync.Status.ClientConfigGeneration = "", 0
+		if state.ClientConfig != nil {
+			ruleSync.Status.ResolvedEndpoint = state.ClientConfig.Endpoint()
+			ruleSync.Status.ClientConfigGeneration = state.ClientConfig.Generation
+		}
+	}
+	utils.SetCondition(&ruleSync.Status.Conditions, condition)
+	partial := metav1.Condition{
+		Type:               openawarenessv1beta1.ConditionTypePartiallySynced,
+		Status:             metav1.ConditionFalse,
+		Reason:             condition.Reason,
+		Message:            condition.Message,
+		ObservedGeneration: rule.Generation,
+	}
+	if s.synced > 0 && len(s.failed) > 0 {
+		partial.Status, partial.Reason = metav1.ConditionTrue, openawarenessv1beta1.ReasonGroupsFailed
+		partial.Message = fmt.Sprintf("%d of %d rule group(s) synced to Mimir, failed: %s",
+			s.synced, desired, strings.Join(ruleSync.Status.FailedGroups, ", "))
+		if len(s.failed) > maxFailedGroups {
+			partial.Message += fmt.Sprintf(" and %d more", len(s.failed)-maxFailedGroups)
+		}
+	}
+	utils.SetCondition(&ruleSync.Status.Conditions, partial)
+	if !utils.DualWriteEnabled(rule) {
+		ruleSync.Status.Parity = nil
+		meta.RemoveStatusCondition(&ruleSync.Status.Conditions, openawarenessv1beta1.ConditionTypeEvaluationParity)
+	}
+	if !s.r.VerifyActivation {
+		ruleSync.Status.LastEvaluation = nil
+		meta.RemoveStatusCondition(&ruleSync.Status.Conditions, openawarenessv1beta1.ConditionTypeActive)
+	}
+	if equality.Semantic.DeepEqual(original.Status, ruleSync.Status) {
+		return
+	}
+	if patchErr := utils.PatchStatus(ctx, s.r.Client, ruleSync, original); patchErr != nil {
+		logger.Error(patchErr, "Failed to update PrometheusRuleSync status")
+	}
+}
+
+// reportWouldSync records in the WouldSync condition of the PrometheusRuleSync of the rule
+// whether a sync would succeed, see utils.SetWouldSyncCondition. The counts of synced and failed
+// groups are kept. The summary must not fail the sync, errors are logged.
+func (s *prometheusRuleSync) reportWouldSync(
+	ctx context.Context,
+	state *utils.SyncState[*monitoringv1.PrometheusRule],
+	outcome utils.SyncOutcome[[]rulefmt.RuleGroup],
+) {
+	logger := log.FromContext(ctx)
+	rule := state.Object
+	ruleSync, err := s.ruleSync(ctx, rule)
+	if err != nil {
+		logger.Error(err, "Failed to create PrometheusRuleSync")
+		return
+	}
+
+	original := ruleSync.DeepCopy()
+	ruleSync.Status.ObservedGeneration = rule.Generation
+	utils.SetWouldSyncCondition(&ruleSync.Status.Conditions, rule.Generation, outcome.Stage, outcome.Diff, outcome.Err)
+	if equality.Semantic.DeepEqual(original.Status, ruleSync.Status) {
+		return
+	}
+	if patchErr := utils.PatchStatus(ctx, s.r.Client, ruleSync, original); patchErr != nil {
+		logger.Error(patchErr, "Failed to update PrometheusRuleSync status")
+	}
+}
+
+// ruleSync returns the PrometheusRuleSync of the rule, created owned by the rule if missing.
+func (s *prometheusRuleSync) ruleSync(
+	ctx context.Context,
+	rule *monitoringv1.PrometheusRule,
+) (*openawarenessv1beta1.PrometheusRuleSync, error) {
+	ruleSync := &openawarenessv1beta1.PrometheusRuleSync{
+		ObjectMeta: metav1.ObjectMeta{Name: rule.Name, Namespace: rule.Namespace},
+	}
+	_, err := controllerutil.CreateOrUpdate(ctx, s.r.Client, ruleSync, func() error {
+		return controllerutil.SetControllerReference(rule, ruleSync, s.r.Scheme)
+	})
+	return ruleSync, err
+}
+
+// countGroups returns the number of rule groups partitioned to the tenants of the rule.
+func countGroups(
+	rule *monitoringv1.PrometheusRule,
+	clientConfig *openawarenessv1beta1.ClientConfig,
+	partitions map[string][]rulefmt.RuleGroup,
+) int {
+	count := 0
+	for _, tenantID := range utils.TenantIDs(rule, clientConfig) {
+		count += len(partitions[tenantID])
+	}
+	return count
+}
+
+// pruneRuleGroups deletes the rule groups pushed for the rule before that are not in partitions
+// anymore, because they were removed from the rule or renamed, or because the rule no longer
+// pushes to their tenant, and reports them as a RuleGroupsPruned event. The groups pushed before
+// are the groups recorded in the GroupChecksumsAnnotation. For tenants without recorded groups,
+// e.g. when the annotation was removed, the groups of the rule namespace in Mimir whose rules all
+// carry the owner label of the rule are used instead. Tenants the ClientConfig does not allow
+// are skipped, nothing was pushed to them. The groups are pruned from the rule namespace they
+// were synced to, all of them if the rule namespace changed since, see utils.SyncedRulesNamespace.
+// A group or tenant failing does not stop the others, the deleted groups are reported and all
+// failures returned joined.
+func (r *PrometheusRulesReconciler) pruneRuleGroups(
+	ctx context.Context,
+	rule *monitoringv1.PrometheusRule,
+	clientConfig *openawarenessv1beta1.ClientConfig,
+	alertManagerClient clients.AwarenessClient,
+	recorded utils.GroupChecksums,
+	partitions map[string][]rulefmt.RuleGroup,
+) error {
+	namespace := utils.SyncedRulesNamespace(rule)
+	moved := namespace != utils.RulesNamespace(rule)
+	var pruned []string
+	var failures []error
+	for _, tenantID := range syncedTenants(rule, clientConfig, recorded) {
+		if !clientConfig.TenantAllowed(tenantID) {
+			continue
+		}
+		desired := partitions[tenantID]
+		if moved {
+			desired = nil
+		}
+		removed := recorded.Removed(tenantID, desired)
+		if _, ok := recorded[tenantID]; !ok {
+			remote, err := alertManagerClient.ListRules(ctx, namespace, tenantID)
+			if err != nil && !errors.Is(err, mimir.ErrResourceNotFound) {
+				failures = append(failures,
+					fmt.Errorf("listing rule groups of namespace %s for tenant %s: %w", namespace, tenantID, err))
+				continue
+			}
+			removed = ownedRemovedGroups(remote[namespace], utils.OwnerReference(rule), desired)
+		}
+		deleted, err := deleteRuleGroups(ctx, alertManagerClient, namespace, tenantID, removed)
+		failures = append(failures, err)
+		for _, name := range deleted {
+			pruned = append(pruned, tenantID+"/"+name)
+		}
+	}
+
+	if len(pruned) > 0 {
+		events.Eventf(r.Recorder, rule, events.RuleGroupsPruned,
+			"Deleted %d rule group(s) no longer part of the PrometheusRule from Mimir: %s",
+			len(pruned), strings.Join(pruned, ", "))
+	}
+	return errors.Join(failures...)
+}
+
+// syncedTenants returns the tenants of the rule followed by the other tenants recorded in its
+// GroupChecksumsAnnotation, which the rule pushed to before its tenants changed.
+func syncedTenants(
+	rule *monitoringv1.PrometheusRule,
+	clientConfig *openawarenessv1beta1.ClientConfig,
+	recorded utils.GroupChecksums,
+) []string {
+	tenantIDs := utils.TenantIDs(rule, clientConfig)
+	for _, tenantID := range slices.Sorted(maps.Keys(recorded)) {
+		if !slices.Contains(tenantIDs, tenantID) {
+			tenantIDs = append(tenantIDs, tenantID)
+		}
+	}
+	return tenantIDs
+}
+
+// ownedRemovedGroups returns the names of the groups whose rules all carry the owner label of
+// owner and that are not in desired, sorted by name.
+func ownedRemovedGroups(groups []rulefmt.RuleGroup, owner string, desired []rulefmt.RuleGroup) []string {
+	var removed []string
+	for _, group := range groups {
+		owned := len(group.Rules) > 0 && !slices.ContainsFunc(group.Rules, func(r rulefmt.Rule) bool {
+			return r.Labels[utils.OwnerLabel] != owner
+		})
+		if owned && !slices.ContainsFunc(desired, func(d rulefmt.RuleGroup) bool { return d.Name == group.Name }) {
+			removed = append(removed, group.Name)
+		}
+	}
+	slices.Sort(removed)
+	return removed
+}
+
+// PushRuleGroup creates or updates the rule group of the tenant in the Mimir namespace, evaluated
+// against sourceTenants if any. Returns an error if the client cannot push groups with source
+// tenants, see clients.FederatedRuleClient.
+func PushRuleGroup(
+	ctx context.Context,
+	alertManagerClient clients.AwarenessClient,
+	namespace string,
+	group rulefmt.RuleGroup,
+	sourceTenants []string,
+	tenantID string,
+) error {
+	if len(sourceTenants) == 0 {
+		return alertManagerClient.CreateRuleGroup(ctx, namespace, group, tenantID)
+	}
+	federatedClient, ok := alertManagerClient.(clients.FederatedRuleClient)
+	if !ok {
+		return fmt.Errorf("client does not support the source tenants of %s", utils.SourceTenantsAnnotation)
+	}
+	return federatedClient.CreateFederatedRuleGroup(ctx, namespace, group, sourceTenants, tenantID)
+}
+
+// deleteRuleGroups deletes the named rule groups of the tenant from the Mimir namespace.
+// Groups that no longer exist are ignored. A group that fails to delete does not stop the other
+// groups. Returns the names of the deleted groups and all failures joined.
+func deleteRuleGroups(
+	ctx context.Context,
+	alertManagerClient clients.AwarenessClient,
+	namespace, tenantID string,
+	names []string,
+) ([]string, error) {
+	var deleted []string
+	var failures []error
+	for _, name := range names {
+		err := alertManagerClient.DeleteRuleGroup(ctx, namespace, name, tenantID)
+		if err != nil && !errors.Is(err, mimir.ErrResourceNotFound) {
+			failures = append(failures,
+				fmt.Errorf("rule group %s from namespace %s for tenant %s: %w", name, namespace, tenantID, err))
+			continue
+		}
+		deleted = append(deleted, name)
+	}
+	return deleted, errors.Join(failures...)
+}
+
+// capitalize upper-cases the first letter of an error message for use as event message.
+func capitalize(message string) string {
+	if message == "" {
+		return message
+	}
+	return strings.ToUpper(message[:1]) + message[1:]
+}
+
+// updateBackup applies update to the backup of the rule with its ClientConfig.
+// The backup must not fail the sync, errors are logged.
+func (r *PrometheusRulesReconciler) updateBackup(
+	ctx context.Context,
+	rule *monitoringv1.PrometheusRule,
+	update func(clientConfig *openawarenessv1beta1.ClientConfig) error,
+) {
+	if r.Backup == nil {
+		return
+	}
+	clientConfig, err := utils.GetClientConfig(ctx, r.Client, rule)
+	if err == nil {
+		err = update(clientConfig)
+	}
+	if err != nil {
+		log.FromContext(ctx).Error(err, "Failed to update the backup of the rule groups")
+	}
+}
+
+// reportSyncTimeout emits a TimeoutError event if err was caused by the sync timeout.
+func (r *PrometheusRulesReconciler) reportSyncTimeout(rule *monitoringv1.PrometheusRule, err error, timeout time.Duration) {
+	if !errors.Is(err, context.DeadlineExceeded) {
+		return
+	}
+	events.Eventf(r.Recorder, rule, events.TimeoutError,
+		"Mimir API operations did not complete within the sync timeout of %s", timeout)
+}
+
+// checkRulePolicy reports every finding of rulePolicy as a RulePolicyViolation event.
+// Returns false if the findings block the push.
+func (r *PrometheusRulesReconciler) checkRulePolicy(
+	logger logr.Logger,
+	rule *monitoringv1.PrometheusRule,
+	rulePolicy *policy.RulePolicy,
+) bool {
+	findings := rulePolicy.Check(rule.Spec.Groups)
+	if len(findings) == 0 {
+		return true
+	}
+
+	for _, finding := range findings {
+		events.Event(r.Recorder, rule, events.RulePolicyViolation, finding.String())
+	}
+	logger.Info("PrometheusRule violates the rule policy",
+		"findings", len(findings))
+
+	if !rulePolicy.Blocking() {
+		return true
+	}
+	events.Eventf(r.Recorder, rule, events.RuleGroupsBlocked,
+		"Rule groups are not synced because of %d rule policy violation(s)", len(findings))
+	return false
+}
+
+// reportConflicts emits a DuplicateRuleName warning event for every recording or alerting
+// rule name of the rule that another resource defines in the same tenant. Other resources
+// are the PrometheusRules synced through the same ClientConfig and the rules stored in
+// Mimir, identified by their owner label or, without it, by their rule namespace.
+// The tenants are resolved through clientConfig, which all compared rules are synced with.
+// Failures are logged, the sync already succeeded.
+func (r *PrometheusRulesReconciler) reportConflicts(
+	ctx context.Context,
+	logger logr.Logger,
+	rule *monitoringv1.PrometheusRule,
+	clientConfig *openawarenessv1beta1.ClientConfig,
+	awarenessClient clients.AwarenessClient,
+	groups []rulefmt.RuleGroup,
+) {
+	clientConfigs := &openawarenessv1beta1.ClientConfigList{}
+	if err := r.List(ctx, clientConfigs); err != nil {
+		logger.Error(err, "Failed to list ClientConfigs for conflict detection")
+		return
+	}
+	mappings, err := utils.ListTenantMappings(ctx, r.Client)
+	if err != nil {
+		logger.Error(err, "Failed to list TenantMappings for conflict detection")
+		return
+	}
+	rules := &monitoringv1.PrometheusRuleList{}
+	if err := r.List(ctx, rules); err != nil {
+		logger.Error(err, "Failed to list PrometheusRules for conflict detection")
+		return
+	}
+
+	registry := conflicts.NewRegistry()
+	clientName := utils.ClientNameFor(rule, clientConfigs.Items, mappings)
+	for i := range rules.Items {
+		other := &rules.Items[i]
+		if !other.DeletionTimestamp.IsZero() || utils.ClientNameFor(other, clientConfigs.Items, mappings) != clientName {
+			continue
+		}
+		otherGroups, err := DesiredRuleGroups(other)
+		if err != nil {
+			continue
+		}
+		for tenantID, partition := range PartitionRuleGroups(other, otherGroups, clientConfig) {
+			registry.Add(tenantID, utils.OwnerReference(other), partition)
+		}
+	}
+
+	owner := utils.OwnerReference(rule)
+	partitions := PartitionRuleGroups(rule, groups, clientConfig)
+	for _, tenantID := range utils.TenantIDs(rule, clientConfig) {
+		err := awarenessClient.WalkRules(ctx, tenantID, func(namespace string, remote []rulefmt.RuleGroup) error {
+			for _, group := range remote {
+				for _, remoteRule := range group.Rules {
+					remoteOwner := remoteRule.Labels[utils.OwnerLabel]
+					if remoteOwner == "" {
+						remoteOwner = "Mimir rule namespace " + namespace
+					}
+					registry.Add(tenantID, remoteOwner, []rulefmt.RuleGroup{{Rules: []rulefmt.Rule{remoteRule}}})
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			logger.Error(err, "Failed to list rules for conflict detection", logging.KeyTenant, tenantID)
+			continue
+		}
+
+		for _, conflict := range registry.Conflicts(tenantID, owner, partitions[tenantID]) {
+			events.Eventf(r.Recorder, rule, events.DuplicateRuleName,
+				"%s in tenant %s", capitalize(conflict.String()), tenantID)
+		}
+	}
+}
+
+// reportActivation checks the ruler's runtime state for the pushed groups in every tenant and
+// records it in the Active condition and LastEvaluation status of the PrometheusRuleSync of the
+// rule. The state is reported as event when the condition changes, see activationEvent. The
+// check must not fail the sync, errors are reported as ActivationUnknown. It is skipped if the
+// client does not support reading the ruler state.
+// Returns a result requeueing the rule while its groups are not active yet.
+func (s *prometheusRuleSync) reportActivation(
+	ctx context.Context,
+	state *utils.SyncState[*monitoringv1.PrometheusRule],
+	awarenessClient clients.AwarenessClient,
+	groups []rulefmt.RuleGroup,
+) ctrl.Result {
+	logger := log.FromContext(ctx)
+	rule := state.Object
+	queryClient, ok := awarenessClient.(clients.QueryClient)
+	if !ok {
+		logger.V(1).Info("Client does not support reading ruler state, skipping activation check")
+		return ctrl.Result{}
+	}
+
+	ruleSync, err := s.ruleSync(ctx, rule)
+	if err != nil {
+		logger.Error(err, "Failed to create PrometheusRuleSync")
+		return ctrl.Result{RequeueAfter: activationRecheckInterval}
+	}
+	original := ruleSync.DeepCopy()
+	condition, lastEvaluation := s.r.verifyActivation(state.SyncContext, logger, rule, queryClient,
+		PartitionRuleGroups(rule, groups, state.ClientConfig), utils.TenantIDs(rule, state.ClientConfig))
+	if previous := meta.FindStatusCondition(ruleSync.Status.Conditions, condition.Type); previous == nil ||
+		previous.Status != condition.Status || previous.Reason != condition.Reason {
+		s.r.activationEvent(rule, condition)
+	}
+	ruleSync.Status.LastEvaluation = nil
+	if !lastEvaluation.IsZero() {
+		ruleSync.Status.LastEvaluation = &metav1.Time{Time: lastEvaluation}
+	}
+	utils.SetCondition(&ruleSync.Status.Conditions, condition)
+	if !equality.Semantic.DeepEqual(original.Status, ruleSync.Status) {
+		if patchErr := utils.PatchStatus(ctx, s.r.Client, ruleSync, original); patchErr != nil {
+			logger.Error(patchErr, "Failed to update PrometheusRuleSync status")
+		}
+	}
+	if condition.Status != metav1.ConditionTrue {
+		since := meta.FindStatusCondition(ruleSync.Status.Conditions, condition.Type).LastTransitionTime.Time
+		return ctrl.Result{RequeueAfter: activationRecheckDelay(since, time.Now())}
+	}
+	return ctrl.Result{}
+}
+
+// activationRecheckDelay returns the requeue delay of rule groups not active since inactiveSince:
+// the time they are inactive already, so the delay doubles with every recheck, at least
+// activationRecheckInterval and at most maxActivationRecheckInterval.
+func activationRecheckDelay(inactiveSince, now time.Time) time.Duration {
+	return min(max(now.Sub(inactiveSince), activationRecheckInterval), maxActivationRecheckInterval)
+}
+
+// verifyActivation checks the ruler's runtime state for the partitioned groups in every tenant.
+// Returns the Active condition and, if all groups are evaluated, the oldest last evaluation time
+// over all tenants.
+func (r *PrometheusRulesReconciler) verifyActivation(
+	ctx context.Context,
+	logger logr.Logger,
+	rule *monitoringv1.PrometheusRule,
+	queryClient clients.QueryClient,
+	partitions map[string][]rulefmt.RuleGroup,
+	tenantIDs []string,
+) (metav1.Condition, time.Time) {
+	condition := metav1.Condition{
+		Type:               openawarenessv1beta1.ConditionTypeActive,
+		ObservedGeneration: rule.Generation,
+	}
+	var oldest time.Time
+	var problems []string
+	groups := 0
+	for _, tenantID := range tenantIDs {
+		states, err := queryClient.ActiveRuleGroups(ctx, tenantID)
+		if err != nil {
+			logger.Error(err, "Failed to read ruler state", logging.KeyTenant, tenantID)
+			condition.Status, condition.Reason = metav1.ConditionUnknown, openawarenessv1beta1.ReasonActivationUnknown
+			condition.Message = utils.StatusMessage(
+				fmt.Errorf("unable to read ruler state for tenant %s: %w", tenantID, err))
+			return condition, time.Time{}
+		}
+		lastEvaluation, tenantProblems := checkActivation(states, utils.RulesNamespace(rule), partitions[tenantID])
+		problems = append(problems, tenantProblems...)
+		groups += len(partitions[tenantID])
+		if !lastEvaluation.IsZero() && (oldest.IsZero() || lastEvaluation.Before(oldest)) {
+			oldest = lastEvaluation
+		}
+	}
+
+	if len(problems) > 0 {
+		logger.Info("Rule groups not active yet", "problems", problems)
+		condition.Status, condition.Reason = metav1.ConditionFalse, openawarenessv1beta1.ReasonRuleGroupsInactive
+		condition.Message = utils.StatusMessage(fmt.Errorf("%d rule group(s) not active yet: %s",
+			len(problems), strings.Join(problems, "; ")))
+		return condition, time.Time{}
+	}
+	condition.Status, condition.Reason = metav1.ConditionTrue, openawarenessv1beta1.ReasonRuleGroupsActive
+	condition.Message = fmt.Sprintf("All %d rule group(s) are evaluated by the ruler", groups)
+	return condition, oldest
+}
+
+// activationEvent reports the Active condition of the rule as RuleGroupsActive,
+// RuleGroupsInactive or RuleGroupsActivationUnknown event.
+func (r *PrometheusRulesReconciler) activationEvent(rule *monitoringv1.PrometheusRule, condition metav1.Condition) {
+	switch condition.Status {
+	case metav1.ConditionTrue:
+		events.Event(r.Recorder, rule, events.RuleGroupsActive, condition.Message)
+	case metav1.ConditionFalse:
+		events.Event(r.Recorder, rule, events.RuleGroupsInactive, condition.Message)
+	default:
+		events.Event(r.Recorder, rule, events.RuleGroupsActivationUnknown, condition.Message)
+	}
+}
+
+// checkActivation compares the pushed groups against the ruler state of the given namespace.
+// Returns the oldest last evaluation time across all groups and a description of every
+// group that is missing, has not been evaluated yet, or contains unhealthy rules.
+func checkActivation(states []mimir.RuleGroupState, namespace string, groups []rulefmt.RuleGroup) (time.Time, []string) {
+	byName := make(map[string]mimir.RuleGroupState, len(states))
+	for _, state := range states {
+		if state.File == namespace {
+			byName[state.Name] = state
+		}
+	}
+
+	var oldest time.Time
+	var problems []string
+	for _, group := range groups {
+		state, exists := byName[group.Name]
+		if !exists {
+			problems = append(problems, fmt.Sprintf("group %s is not loaded by the ruler", group.Name))
+			continue
+		}
+		if state.LastEvaluation.IsZero() {
+			problems = append(problems, fmt.Sprintf("group %s has not been evaluated yet", group.Name))
+			continue
+		}
+		for _, ruleState := range state.Rules {
+			if ruleState.Health == "err" {
+				problems = append(problems, fmt.Sprintf("rule %s in group %s is unhealthy: %s",
+					ruleState.Name, group.Name, ruleState.LastError))
+			}
+		}
+		if oldest.IsZero() || state.LastEvaluation.Before(oldest) {
+			oldest = state.LastEvaluation
+		}
+	}
+
+	return oldest, problems
+}
+
+// absentFunctions select series expected not to exist, their selectors are not simulated
+var absentFunctions = map[string]bool{"absent": true, "absent_over_time": true}
+
+// simulateRuleGroup runs the expression of every alerting rule of a group about to be pushed
+// against the data of the tenant through the query API, asking for a single series, and reports
+// rules whose expression fails to execute as RuleSimulationFailed events and rules selecting
+// metrics without series as MissingSeriesWarning events. The simulation never blocks the push;
+// it is skipped if the client does not support queries.
+func (r *PrometheusRulesReconciler) simulateRuleGroup(
+	ctx context.Context,
+	rule *monitoringv1.PrometheusRule,
+	awarenessClient clients.AwarenessClient,
+	group rulefmt.RuleGroup,
+	tenantID string,
+) {
+	logger := log.FromContext(ctx)
+	queryClient, ok := awarenessClient.(clients.QueryClient)
+	if !ok {
+		logger.V(1).Info("Client does not support queries, skipping rule simulation")
+		return
+	}
+
+	recorded := recordedMetrics(rule)
+	tenantIDs := []string{tenantID}
+	for _, groupRule := range group.Rules {
+		if groupRule.Alert == "" {
+			continue
+		}
+		if _, err := queryClient.QueryHasSeries(ctx, groupRule.Expr, tenantIDs); err != nil {
+			events.Eventf(r.Recorder, rule, events.RuleSimulationFailed,
+				"Alerting rule %s in group %s cannot be evaluated in tenant %s: %v", groupRule.Alert, group.Name, tenantID, err)
+			continue
+		}
+
+		var missing []string
+		for _, selector := range seriesSelectors(groupRule.Expr, recorded) {
+			exists, err := queryClient.QueryHasSeries(ctx, selector, tenantIDs)
 			if err != nil {
-				r.Recorder.Eventf(rule, corev1.EventTypeWarning, "RuleGroupDeleteFailed",
-					"Failed to delete rule group %s from namespace %s for tenant %s: %v", group.Name, rule.Namespace, tenantID, err)
-				logger.Error(err, "Failed to delete rule group", "group", group.Name, "namespace", rule.Namespace, "tenantID", tenantID)
-				return ctrl.Result{}, err
+				logger.Error(err, "Failed to simulate selector", "selector", selector, logging.KeyTenant, tenantID)
+				continue
+			}
+			if !exists {
+				missing = append(missing, selector)
 			}
 		}
+		if len(missing) > 0 {
+			events.Eventf(r.Recorder, rule, events.MissingSeriesWarning,
+				"Alerting rule %s in group %s selects no series in tenant %s: %s",
+				groupRule.Alert, group.Name, tenantID, strings.Join(missing, ", "))
+		}
+	}
+}
 
-		r.Recorder.Event(rule, corev1.EventTypeNormal, "RuleGroupsDeleted",
-			"Successfully deleted all rule groups from Mimir")
+// seriesSelectors returns the distinct series selectors of expr without offset and @ modifiers,
+// leaving out selectors inside absent functions and selectors of metrics in recorded, which are
+// only written once the recording rules are evaluated. Returns nil if expr cannot be parsed.
+func seriesSelectors(expr string, recorded map[string]bool) []string {
+	parsed, err := parser.ParseExpr(expr)
+	if err != nil {
+		return nil
+	}
+	var selectors []string
+	parser.Inspect(parsed, func(node parser.Node, path []parser.Node) error {
+		vectorSelector, ok := node.(*parser.VectorSelector)
+		if !ok || recorded[vectorSelector.Name] {
+			return nil
+		}
+		for _, ancestor := range path {
+			if call, ok := ancestor.(*parser.Call); ok && absentFunctions[call.Func.Name] {
+				return nil
+			}
+		}
+		selector := (&parser.VectorSelector{
+			Name:          vectorSelector.Name,
+			LabelMatchers: vectorSelector.LabelMatchers,
+		}).String()
+		if !slices.Contains(selectors, selector) {
+			selectors = append(selectors, selector)
+		}
+		return nil
+	})
+	return selectors
+}
+
+// recordedMetrics returns the names of the metrics recorded by the rule.
+func recordedMetrics(rule *monitoringv1.PrometheusRule) map[string]bool {
+	recorded := map[string]bool{}
+	for _, group := range rule.Spec.Groups {
+		for _, r := range group.Rules {
+			if r.Record != "" {
+				recorded[r.Record] = true
+			}
+		}
+	}
+	return recorded
+}
+
+// DesiredRuleGroups returns the rule groups pushed to Mimir for a PrometheusRule:
+// its groups in rulefmt format with every rule labelled with its owner. Groups without interval
+// or query offset get the ones of the EvaluationIntervalAnnotation and QueryOffsetAnnotation.
+// Returns an error if the groups cannot be converted, an annotation is not a valid duration or
+// the GroupTenantsAnnotation or SourceTenantsAnnotation is invalid. The matchers of the
+// InjectMatchersAnnotation are added to the expressions unless the InjectMatchersDryRunAnnotation
+// is set, see rewriteExpressions.
+func DesiredRuleGroups(rule *monitoringv1.PrometheusRule) ([]rulefmt.RuleGroup, error) {
+	groups, err := convert(rule.Spec.Groups)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := rewriteExpressions(rule, groups); err != nil {
+		return nil, err
+	}
+	if _, err := utils.GroupTenants(rule, nil); err != nil {
+		return nil, err
+	}
+	if _, err := utils.SourceTenants(rule, nil); err != nil {
+		return nil, err
+	}
+	if err := applyGroupDefaults(rule, groups); err != nil {
+		return nil, err
+	}
+	utils.SetOwnerLabel(groups, utils.OwnerReference(rule))
+	return groups, nil
+}
 
-		// The object is being deleted check for finalizer
-		if controllerutil.ContainsFinalizer(rule, utils.FinalizerAnnotation) {
-			controllerutil.RemoveFinalizer(rule, utils.FinalizerAnnotation)
-			if err := r.Update(ctx, rule); err != nil {
-				return ctrl.Result{}, err
+// rewriteExpressions adds the label matchers of the InjectMatchersAnnotation of the rule to the
+// vector selectors of the expressions of the groups, e.g. to keep recording rules imported from a
+// per-cluster Prometheus from aggregating the series of all clusters of a tenant. The groups are
+// left unchanged while the InjectMatchersDryRunAnnotation is set to "true".
+// Returns the rewritten expressions, and an error if the annotation or an expression is invalid.
+func rewriteExpressions(rule *monitoringv1.PrometheusRule, groups []rulefmt.RuleGroup) ([]utils.ExpressionRewrite, error) {
+	value, ok := rule.Annotations[utils.InjectMatchersAnnotation]
+	if !ok {
+		return nil, nil
+	}
+	matchers, err := utils.ParseLabels(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s annotation %q: %w", utils.InjectMatchersAnnotation, value, err)
+	}
+	dryRun := rule.Annotations[utils.InjectMatchersDryRunAnnotation] == "true"
+	return utils.RewriteExpressions(groups, matchers, dryRun)
+}
+
+// applyGroupDefaults sets the interval of the EvaluationIntervalAnnotation and the query offset
+// of the QueryOffsetAnnotation of the rule on the groups that do not set their own, so rules
+// relying on the global defaults of Prometheus keep them in Mimir.
+// Returns an error if an annotation is not a duration or the interval is zero.
+func applyGroupDefaults(rule *monitoringv1.PrometheusRule, groups []rulefmt.RuleGroup) error {
+	if value, ok := rule.Annotations[utils.EvaluationIntervalAnnotation]; ok {
+		interval, err := model.ParseDuration(value)
+		if err != nil || interval <= 0 {
+			return fmt.Errorf("invalid %s annotation %q, expected a positive duration such as 30s",
+				utils.EvaluationIntervalAnnotation, value)
+		}
+		for i := range groups {
+			if groups[i].Interval == 0 {
+				groups[i].Interval = interval
 			}
-			logger.Info("PrometheusRule was deleted", "name", rule.Name, "namespace", rule.Namespace)
 		}
 	}
-	return ctrl.Result{}, nil
+	if value, ok := rule.Annotations[utils.QueryOffsetAnnotation]; ok {
+		queryOffset, err := model.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("invalid %s annotation %q, expected a duration such as 1m",
+				utils.QueryOffsetAnnotation, value)
+		}
+		for i := range groups {
+			if groups[i].QueryOffset == nil {
+				groups[i].QueryOffset = &queryOffset
+			}
+		}
+	}
+	return nil
+}
+
+// PartitionRuleGroups splits the rule groups of a PrometheusRule by the tenant they are
+// pushed to. Recording rules go to utils.RecordingTenantID and alerting rules to
+// utils.AlertingTenantID; a group mixing both is split into two groups of the same name,
+// one per tenant. Groups without rules go to the alerting tenant. Groups named in the
+// GroupTenantsAnnotation go to their tenant whole, see utils.GroupTenants.
+// Without these annotations all groups go to the rule's tenant unchanged. Tenants are resolved
+// through clientConfig, which may be nil.
+func PartitionRuleGroups(
+	rule *monitoringv1.PrometheusRule,
+	groups []rulefmt.RuleGroup,
+	clientConfig *openawarenessv1beta1.ClientConfig,
+) map[string][]rulefmt.RuleGroup {
+	recordingTenant, alertingTenant := utils.RecordingTenantID(rule, clientConfig), utils.AlertingTenantID(rule, clientConfig)
+	// An invalid annotation is reported by DesiredRuleGroups
+	groupTenants, _ := utils.GroupTenants(rule, clientConfig)
+	partitions := map[string][]rulefmt.RuleGroup{}
+	if recordingTenant == alertingTenant && len(groupTenants) == 0 {
+		partitions[recordingTenant] = groups
+		return partitions
+	}
+
+	for _, group := range groups {
+		if tenantID, ok := groupTenants[group.Name]; ok {
+			partitions[tenantID] = append(partitions[tenantID], group)
+			continue
+		}
+		if recordingTenant == alertingTenant {
+			partitions[recordingTenant] = append(partitions[recordingTenant], group)
+			continue
+		}
+		var recording, alerting []rulefmt.Rule
+		for _, r := range group.Rules {
+			if r.Record != "" {
+				recording = append(recording, r)
+			} else {
+				alerting = append(alerting, r)
+			}
+		}
+		if len(recording) > 0 {
+			recordingGroup := group
+			recordingGroup.Rules = recording
+			partitions[recordingTenant] = append(partitions[recordingTenant], recordingGroup)
+		}
+		if len(alerting) > 0 || len(recording) == 0 {
+			alertingGroup := group
+			alertingGroup.Rules = alerting
+			partitions[alertingTenant] = append(partitions[alertingTenant], alertingGroup)
+		}
+	}
+	return partitions
+}
+
+// ruleKinds returns the groups with only the names of their rules, which is enough to
+// partition them without converting durations.
+func ruleKinds(groups []monitoringv1.RuleGroup) []rulefmt.RuleGroup {
+	kinds := make([]rulefmt.RuleGroup, 0, len(groups))
+	for _, group := range groups {
+		rules := make([]rulefmt.Rule, 0, len(group.Rules))
+		for _, rule := range group.Rules {
+			rules = append(rules, rulefmt.Rule{Record: rule.Record, Alert: rule.Alert})
+		}
+		kinds = append(kinds, rulefmt.RuleGroup{Name: group.Name, Rules: rules})
+	}
+	return kinds
+}
+
+// ValidateRuleGroups validates converted rule groups like Prometheus validates rule files,
+// including the PromQL expressions and the templates of labels and annotations.
+// Returns all problems found, nil if the groups are valid.
+func ValidateRuleGroups(groups []rulefmt.RuleGroup) []error {
+	content, err := yaml.Marshal(&rulefmt.RuleGroups{Groups: groups})
+	if err != nil {
+		return []error{err}
+	}
+	_, errs := rulefmt.Parse(content, false, model.UTF8Validation)
+	return errs
 }
 
 // convert transforms PrometheusRule RuleGroups to Mimir's rulefmt.RuleGroup format.
-// It processes each rule group and converts individual rules to the appropriate format.
-func convert(groups []monitoringv1.RuleGroup) []rulefmt.RuleGroup {
+// It maps every field rulefmt supports, including the group interval, query offset, limit
+// and labels and the rule for and keep_firing_for durations. PartialResponseStrategy is
+// specific to Thanos and not supported by Mimir, so it is dropped.
+// Returns an error if a duration cannot be parsed.
+func convert(groups []monitoringv1.RuleGroup) ([]rulefmt.RuleGroup, error) {
 	returnGroups := make([]rulefmt.RuleGroup, 0, len(groups))
 	for _, group := range groups {
 		returnRules := make([]rulefmt.Rule, 0, len(group.Rules))
 		for _, rule := range group.Rules {
-			returnRules = append(returnRules, newRule(rule))
+			returnRule, err := newRule(rule)
+			if err != nil {
+				return nil, fmt.Errorf("rule group %s: %w", group.Name, err)
+			}
+			returnRules = append(returnRules, returnRule)
 		}
-		returnGroups = append(returnGroups, rulefmt.RuleGroup{
-			Name: group.Name,
-			//Interval: group.Interval, todo
-			Rules: returnRules,
-		})
-	}
 
-	return returnGroups
+		returnGroup := rulefmt.RuleGroup{
+			Name:   group.Name,
+			Rules:  returnRules,
+			Labels: group.Labels,
+		}
+		if group.Interval != nil {
+			interval, err := parseDuration(string(*group.Interval))
+			if err != nil {
+				return nil, fmt.Errorf("rule group %s: invalid interval: %w", group.Name, err)
+			}
+			returnGroup.Interval = interval
+		}
+		if group.QueryOffset != nil {
+			queryOffset, err := parseDuration(string(*group.QueryOffset))
+			if err != nil {
+				return nil, fmt.Errorf("rule group %s: invalid query_offset: %w", group.Name, err)
+			}
+			returnGroup.QueryOffset = &queryOffset
+		}
+		if group.Limit != nil {
+			returnGroup.Limit = *group.Limit
+		}
+		returnGroups = append(returnGroups, returnGroup)
+	}
 
+	return returnGroups, nil
 }
 
 // newRule converts a single PrometheusRule to a rulefmt.Rule.
 // It handles both alert rules (with Alert field) and recording rules (with Record field).
-func newRule(rule monitoringv1.Rule) rulefmt.Rule {
-	return rulefmt.Rule{
-		Record:        rule.Record,
-		Alert:         rule.Alert,
-		Expr:          rule.Expr.String(),
-		For:           0,
-		KeepFiringFor: 0,
-		Labels:        rule.Labels,
-		Annotations:   rule.Annotations,
-	}
-}
-
-// clientFromAnnotation retrieves the appropriate Mimir client for the given PrometheusRule.
-// It extracts the client name and tenant ID from the resource's annotations and returns the cached client.
-// Returns an error if the annotation is missing or if the client is not found in the cache.
-func (r *PrometheusRulesReconciler) clientFromAnnotation(
-	ctx context.Context,
-	logger logr.Logger,
-	rule *monitoringv1.PrometheusRule,
-) (clients.AwarenessClient, error) {
-	if rule.Annotations == nil {
-		logger.Info(
-			"PrometheusRule is missing client annotation",
-			"annotation", utils.ClientNameAnnotation,
-			"name", rule.Name,
-			"namespace", rule.Namespace,
-		)
-		return nil, fmt.Errorf(
-			"annotation %s is missing for PrometheusRule %s/%s",
-			utils.ClientNameAnnotation,
-			rule.Namespace,
-			rule.Name,
-		)
+// Returns an error if a duration cannot be parsed.
+func newRule(rule monitoringv1.Rule) (rulefmt.Rule, error) {
+	returnRule := rulefmt.Rule{
+		Record:      rule.Record,
+		Alert:       rule.Alert,
+		Expr:        rule.Expr.String(),
+		Labels:      rule.Labels,
+		Annotations: rule.Annotations,
+	}
+	if rule.For != nil {
+		forDuration, err := parseDuration(string(*rule.For))
+		if err != nil {
+			return rulefmt.Rule{}, fmt.Errorf("rule %s: invalid for: %w", ruleName(rule), err)
+		}
+		returnRule.For = forDuration
+	}
+	if rule.KeepFiringFor != nil {
+		keepFiringFor, err := parseDuration(string(*rule.KeepFiringFor))
+		if err != nil {
+			return rulefmt.Rule{}, fmt.Errorf("rule %s: invalid keep_firing_for: %w", ruleName(rule), err)
+		}
+		returnRule.KeepFiringFor = keepFiringFor
 	}
+	return returnRule, nil
+}
 
-	clientName := rule.Annotations[utils.ClientNameAnnotation]
-	if clientName == "" {
-		logger.Info(
-			"PrometheusRule client annotation is empty",
-			"annotation", utils.ClientNameAnnotation,
-			"name", rule.Name,
-			"namespace", rule.Namespace,
-		)
-		return nil, fmt.Errorf(
-			"annotation %s is empty for PrometheusRule %s/%s",
-			utils.ClientNameAnnotation,
-			rule.Namespace,
-			rule.Name,
-		)
+// parseDuration parses a Prometheus duration, treating an empty string as zero.
+func parseDuration(value string) (model.Duration, error) {
+	if value == "" {
+		return 0, nil
 	}
+	return model.ParseDuration(value)
+}
+
+// ruleName returns the alert or record name of a rule.
+func ruleName(rule monitoringv1.Rule) string {
+	if rule.Alert != "" {
+		return rule.Alert
+	}
+	return rule.Record
+}
 
-	// Get or create client - uses simple cache key (clientName only)
-	// via the namespace parameter in Mimir client methods
-	alertManagerClient, err := r.RulerClients.GetOrCreateMimirClient(ctx, "", clientName)
+// clientFromConfig gets or creates the Mimir client for the ClientConfig referenced by the
+// PrometheusRule, so it does not depend on the ClientConfig controller having populated the cache first.
+// Returns an error if the client cannot be created.
+func (r *PrometheusRulesReconciler) clientFromConfig(
+	ctx context.Context,
+	logger logr.Logger,
+	rule *monitoringv1.PrometheusRule,
+	clientConfig *openawarenessv1beta1.ClientConfig,
+) (clients.AwarenessClient, error) {
+	alertManagerClient, err := utils.ClientForConfig(ctx, r.RulerClients, clientConfig)
 	if err != nil {
 		logger.Info(
-			"Client does not exist in cache",
-			"clientName", clientName,
-			"name", rule.Name,
-			"namespace", rule.Namespace,
+			"Unable to create client for PrometheusRule",
+			logging.KeyClient, clientConfig.Name,
+			"connectionStatus", clientConfig.Status.ConnectionStatus,
+			"error", err.Error(),
 		)
 		return nil, fmt.Errorf(
 			"getting client %s for PrometheusRule %s/%s: %w",
-			clientName,
+			clientConfig.Name,
 			rule.Namespace,
 			rule.Name,
 			err,
@@ -229,76 +1645,238 @@ func (r *PrometheusRulesReconciler) clientFromAnnotation(
 }
 
 // getNamespaceFromAnnotations extracts the Mimir tenant namespace from the PrometheusRule annotations.
-// Returns the tenant ID from the annotation, or the default tenant ID if the annotation is not set.
+// Returns the tenant ID from the annotation resolved through the tenant aliases of the ClientConfig,
+// or its default tenant if the annotation is not set, see utils.GetTenantID.
 func (r *PrometheusRulesReconciler) getNamespaceFromAnnotations(
 	logger logr.Logger,
 	rule *monitoringv1.PrometheusRule,
+	clientConfig *openawarenessv1beta1.ClientConfig,
 ) string {
-	mimirNamespace := rule.Annotations[utils.MimirTenantAnnotation]
-	if mimirNamespace == "" {
+	tenantID := utils.GetTenantID(rule, clientConfig)
+	if rule.Annotations[utils.MimirTenantAnnotation] == "" {
 		logger.V(1).Info(
 			"Using default tenant ID because annotation is missing",
 			"annotation", utils.MimirTenantAnnotation,
-			"defaultTenant", utils.DefaultTenantID,
-			"name", rule.Name,
-			"namespace", rule.Namespace,
+			"defaultTenant", tenantID,
 		)
-		return utils.DefaultTenantID
 	}
-	return mimirNamespace
+	return tenantID
 }
 
 // SetupWithManager sets up the controller with the Manager.
+// It registers a field index on the client-name annotation so ClientConfig events
+// can be mapped to the referencing PrometheusRules without listing all of them.
+// A second index on the SecretDataRefsAnnotation maps changes of the ConfigMaps and Secrets
+// providing template data to the PrometheusRules rendered with it.
+// Changes of the OperatorConfig requeue all PrometheusRules.
+// PrometheusRules, ConfigMaps and Secrets are watched in the ResourceCluster, ClientConfigs and
+// the OperatorConfig in the manager's cluster.
 func (r *PrometheusRulesReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	resources := cluster.Cluster(mgr)
+	if r.ResourceCluster != nil {
+		resources = r.ResourceCluster
+	}
+
+	if err := resources.GetFieldIndexer().IndexField(
+		context.Background(),
+		&monitoringv1.PrometheusRule{},
+		utils.ClientNameIndexKey,
+		utils.ClientNameIndexer,
+	); err != nil {
+		return fmt.Errorf("indexing PrometheusRules by client name: %w", err)
+	}
+	if err := resources.GetFieldIndexer().IndexField(
+		context.Background(),
+		&monitoringv1.PrometheusRule{},
+		utils.SecretDataRefIndexKey,
+		utils.SecretDataRefIndexer,
+	); err != nil {
+		return fmt.Errorf("indexing PrometheusRules by template data: %w", err)
+	}
+
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&monitoringv1.PrometheusRule{}).
+		Named("prometheusrule").
+		WatchesRawSource(source.Kind(resources.GetCache(), &monitoringv1.PrometheusRule{},
+			utils.EnqueueByPriority[*monitoringv1.PrometheusRule]())).
+		// Only the metadata of ConfigMaps and Secrets is cached, their data is read when rendering
+		WatchesRawSource(source.Kind(resources.GetCache(), utils.MetadataOf("ConfigMap"),
+			handler.TypedEnqueueRequestsFromMapFunc(findPrometheusRulesForTemplateData(r, "ConfigMap")))).
+		WatchesRawSource(source.Kind(resources.GetCache(), utils.MetadataOf("Secret"),
+			handler.TypedEnqueueRequestsFromMapFunc(findPrometheusRulesForTemplateData(r, "Secret")))).
+		WithOptions(controller.Options{
+			NewQueue:                metrics.NewQueue(metrics.KindPrometheusRule),
+			RateLimiter:             r.OperatorConfig.RateLimiter(operatorconfig.DefaultRetry),
+			MaxConcurrentReconciles: r.MaxConcurrentReconciles,
+			LogConstructor:          logging.LogConstructor(mgr.GetLogger(), "prometheusrule"),
+		}).
 		Watches(
 			&openawarenessv1beta1.ClientConfig{},
 			handler.EnqueueRequestsFromMapFunc(r.findPrometheusRulesForClient),
+			builder.WithPredicates(utils.ClientConfigChangedPredicate()),
 		).
-		Complete(r)
+		Watches(
+			&openawarenessv1beta1.TenantMapping{},
+			handler.EnqueueRequestsFromMapFunc(r.findPrometheusRulesForTenantMapping),
+		).
+		Watches(
+			&openawarenessv1beta1.OperatorConfig{},
+			handler.EnqueueRequestsFromMapFunc(r.findPrometheusRulesForOperatorConfig),
+			// Status updates of the OperatorConfig change no setting
+			builder.WithPredicates(predicate.GenerationChangedPredicate{}),
+		).
+		Complete(logging.TrackAttempts(r))
+}
+
+// findPrometheusRulesForOperatorConfig maps changes of the OperatorConfig to reconciliation
+// requests of all PrometheusRules, so changed settings apply without a restart.
+func (r *PrometheusRulesReconciler) findPrometheusRulesForOperatorConfig(
+	ctx context.Context,
+	obj client.Object,
+) []reconcile.Request {
+	if obj.GetName() != openawarenessv1beta1.OperatorConfigName {
+		return nil
+	}
+	logger := log.FromContext(ctx)
+
+	rulesList := &monitoringv1.PrometheusRuleList{}
+	if err := r.List(ctx, rulesList); err != nil {
+		logger.Error(err, "Failed to list PrometheusRules for OperatorConfig watch")
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(rulesList.Items))
+	for _, rule := range rulesList.Items {
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Name: rule.Name, Namespace: rule.Namespace},
+		})
+	}
+
+	logger.V(1).Info("Queueing all PrometheusRules due to OperatorConfig change", "count", len(requests))
+
+	return requests
+}
+
+// findPrometheusRulesForTemplateData returns a function mapping changes of a ConfigMap or Secret
+// of the given kind to reconciliation requests for the PrometheusRules rendered with its data.
+func findPrometheusRulesForTemplateData(
+	r *PrometheusRulesReconciler,
+	kind string,
+) func(context.Context, *metav1.PartialObjectMetadata) []reconcile.Request {
+	return func(ctx context.Context, obj *metav1.PartialObjectMetadata) []reconcile.Request {
+		logger := log.FromContext(ctx)
+
+		rulesList := &monitoringv1.PrometheusRuleList{}
+		if err := r.List(ctx, rulesList,
+			client.InNamespace(obj.GetNamespace()),
+			client.MatchingFields{utils.SecretDataRefIndexKey: kind + "/" + obj.GetName()},
+		); err != nil {
+			logger.Error(err, "Failed to list PrometheusRules reading template data",
+				logging.KeyResource, kind+"/"+obj.GetName(), logging.KeyNamespace, obj.GetNamespace())
+			return nil
+		}
+
+		requests := make([]reconcile.Request, 0, len(rulesList.Items))
+		for _, rule := range rulesList.Items {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: rule.Name, Namespace: rule.Namespace},
+			})
+		}
+		return requests
+	}
 }
 
 // findPrometheusRulesForClient maps ClientConfig changes to PrometheusRule reconciliation requests.
 // When a ClientConfig is created, updated, or deleted, this function finds all PrometheusRules
 // that reference it and triggers their reconciliation.
-func (r *PrometheusRulesReconciler) findPrometheusRulesForClient(ctx context.Context, client client.Object) []reconcile.Request {
+func (r *PrometheusRulesReconciler) findPrometheusRulesForClient(ctx context.Context, obj client.Object) []reconcile.Request {
 	logger := log.FromContext(ctx)
 
-	clientConfig, ok := client.(*openawarenessv1beta1.ClientConfig)
+	clientConfig, ok := obj.(*openawarenessv1beta1.ClientConfig)
 	if !ok {
-		logger.Error(fmt.Errorf("expected ClientConfig but got %T", client), "Unexpected object type in watch handler")
+		logger.Error(fmt.Errorf("expected ClientConfig but got %T", obj), "Unexpected object type in watch handler")
 		return nil
 	}
 
-	// List all PrometheusRules
+	// List only the PrometheusRules referencing this ClientConfig via the client-name index,
+	// by name in any namespace or by namespace/name
+	rulesList := &monitoringv1.PrometheusRuleList{}
+	for _, value := range utils.ClientNameIndexValues(clientConfig) {
+		referencing := &monitoringv1.PrometheusRuleList{}
+		if err := r.List(ctx, referencing, client.MatchingFields{utils.ClientNameIndexKey: value}); err != nil {
+			logger.Error(err, "Failed to list PrometheusRules for ClientConfig watch")
+			return nil
+		}
+		rulesList.Items = append(rulesList.Items, referencing.Items...)
+	}
+
+	// A default ClientConfig, or one referenced by a TenantMapping in any namespace, is also
+	// used by the PrometheusRules without client-name annotation
+	referenced, err := utils.ReferencedByTenantMapping(ctx, r.Client, clientConfig)
+	if err != nil {
+		logger.Error(err, "Failed to list TenantMappings for ClientConfig watch")
+		return nil
+	}
+	if referenced || clientConfig.Spec.Default {
+		opts := utils.DefaultClientListOptions(clientConfig)
+		if referenced {
+			opts = []client.ListOption{client.MatchingFields{utils.ClientNameIndexKey: utils.DefaultClientIndexValue}}
+		}
+		defaultRules := &monitoringv1.PrometheusRuleList{}
+		if err := r.List(ctx, defaultRules, opts...); err != nil {
+			logger.Error(err, "Failed to list PrometheusRules using the default ClientConfig")
+			return nil
+		}
+		rulesList.Items = append(rulesList.Items, defaultRules.Items...)
+	}
+
+	requests := make([]reconcile.Request, 0, len(rulesList.Items))
+	for _, rule := range rulesList.Items {
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Name:      rule.Name,
+				Namespace: rule.Namespace,
+			},
+		})
+		logger.V(1).Info("Queueing PrometheusRule reconciliation due to ClientConfig change",
+			"prometheusRule", utils.OwnerReference(&rule),
+			logging.KeyClient, clientConfig.Name)
+	}
+
+	logger.V(1).Info("Found PrometheusRules referencing ClientConfig",
+		logging.KeyClient, clientConfig.Name,
+		"count", len(requests))
+
+	return requests
+}
+
+// findPrometheusRulesForTenantMapping maps TenantMapping changes to reconciliation requests of
+// the PrometheusRules lacking the client-name or tenant annotation, which a TenantMapping may
+// apply to. Changes of namespace labels are not watched.
+func (r *PrometheusRulesReconciler) findPrometheusRulesForTenantMapping(
+	ctx context.Context,
+	obj client.Object,
+) []reconcile.Request {
+	logger := log.FromContext(ctx)
+
 	rulesList := &monitoringv1.PrometheusRuleList{}
 	if err := r.List(ctx, rulesList); err != nil {
-		logger.Error(err, "Failed to list PrometheusRules for ClientConfig watch")
+		logger.Error(err, "Failed to list PrometheusRules for TenantMapping watch")
 		return nil
 	}
 
 	var requests []reconcile.Request
-	for _, rule := range rulesList.Items {
-		// Check if this rule references the ClientConfig
-		if rule.Annotations != nil {
-			if clientName, exists := rule.Annotations[utils.ClientNameAnnotation]; exists && clientName == clientConfig.Name {
-				requests = append(requests, reconcile.Request{
-					NamespacedName: types.NamespacedName{
-						Name:      rule.Name,
-						Namespace: rule.Namespace,
-					},
-				})
-				logger.V(1).Info("Queueing PrometheusRule reconciliation due to ClientConfig change",
-					"prometheusRule", rule.Name,
-					"namespace", rule.Namespace,
-					"clientConfig", clientConfig.Name)
-			}
+	for i := range rulesList.Items {
+		rule := &rulesList.Items[i]
+		if !utils.UsesTenantMapping(rule) {
+			continue
 		}
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Name: rule.Name, Namespace: rule.Namespace},
+		})
 	}
 
-	logger.V(1).Info("Found PrometheusRules referencing ClientConfig",
-		"clientConfig", clientConfig.Name,
+	logger.V(1).Info("Found PrometheusRules a TenantMapping may apply to",
+		"tenantMapping", obj.GetName(),
 		"count", len(requests))
 
 	return requests
@@ -2,17 +2,29 @@ package monitoringcoreoscom
 
 import (
 	"context"
+	"errors"
+	"net/http"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/rulefmt"
+	openawarenessv1beta1 "github.com/syndlex/openawareness-controller/api/openawareness/v1beta1"
 	"github.com/syndlex/openawareness-controller/internal/clients"
 	"github.com/syndlex/openawareness-controller/internal/controller/utils"
+	"github.com/syndlex/openawareness-controller/internal/mimir"
+	"github.com/syndlex/openawareness-controller/internal/policy"
+	"gopkg.in/yaml.v3"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 var _ = Describe("PrometheusRules Controller", func() {
@@ -77,6 +89,14 @@ var _ = Describe("PrometheusRules Controller", func() {
 		}
 	})
 
+	AfterEach(func() {
+		// envtest runs no garbage collector that deletes the PrometheusRuleSync with its rule
+		ruleSync := &openawarenessv1beta1.PrometheusRuleSync{
+			ObjectMeta: metav1.ObjectMeta{Name: ruleName, Namespace: ruleNamespace},
+		}
+		Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, ruleSync))).To(Succeed())
+	})
+
 	Context("When reconciling a PrometheusRule", func() {
 		It("should emit warning event when client annotation is missing", func() {
 			// Create rule without client annotation
@@ -132,6 +152,47 @@ var _ = Describe("PrometheusRules Controller", func() {
 			Expect(k8sClient.Delete(ctx, ruleWithoutTenant)).To(Succeed())
 		})
 
+		It("should emit warning event with connection details when ClientConfig is disconnected", func() {
+			clientConfig := &openawarenessv1beta1.ClientConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      clientName,
+					Namespace: ruleNamespace,
+				},
+				Spec: openawarenessv1beta1.ClientConfigSpec{
+					Address: "http://localhost:9009",
+					Type:    openawarenessv1beta1.Mimir,
+				},
+			}
+			Expect(k8sClient.Create(ctx, clientConfig)).To(Succeed())
+			clientConfig.Status.ConnectionStatus = openawarenessv1beta1.ConnectionStatusDisconnected
+			clientConfig.Status.ErrorMessage = "connection refused"
+			Expect(k8sClient.Status().Update(ctx, clientConfig)).To(Succeed())
+
+			Expect(k8sClient.Create(ctx, prometheusRule)).To(Succeed())
+
+			// Reconcile
+			result, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).NotTo(BeZero())
+
+			// Verify the event carries the ClientConfig status
+			Eventually(fakeRecorder.Events).Should(Receive(SatisfyAll(
+				ContainSubstring("ClientDisconnected"),
+				ContainSubstring("connection refused"),
+			)))
+
+			// Verify the sync is summarized in the PrometheusRuleSync
+			ruleSync := &openawarenessv1beta1.PrometheusRuleSync{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, ruleSync)).To(Succeed())
+			Expect(ruleSync.Status.GroupsDesired).To(BeEquivalentTo(1))
+			Expect(ruleSync.Status.GroupsFailed).To(BeEquivalentTo(1))
+			Expect(ruleSync.Status.FailureReason).To(Equal("ClientDisconnected"))
+
+			// Cleanup
+			Expect(k8sClient.Delete(ctx, prometheusRule)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, clientConfig)).To(Succeed())
+		})
+
 		It("should add finalizer to PrometheusRule", func() {
 			Skip("Integration test - requires working Mimir client")
 		})
@@ -153,6 +214,670 @@ var _ = Describe("PrometheusRules Controller", func() {
 		})
 	})
 
+	Context("When verifying rule group activation", func() {
+		evaluatedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+		pushed := []rulefmt.RuleGroup{{Name: "alerts"}, {Name: "recordings"}}
+
+		It("should report active groups with the oldest evaluation time", func() {
+			states := []mimir.RuleGroupState{
+				{Name: "alerts", File: ruleNamespace, LastEvaluation: evaluatedAt,
+					Rules: []mimir.RuleState{{Name: "Alert1", Health: "ok"}}},
+				{Name: "recordings", File: ruleNamespace, LastEvaluation: evaluatedAt.Add(time.Minute)},
+			}
+
+			lastEvaluation, problems := checkActivation(states, ruleNamespace, pushed)
+
+			Expect(problems).To(BeEmpty())
+			Expect(lastEvaluation).To(Equal(evaluatedAt))
+		})
+
+		It("should report missing, unevaluated and unhealthy groups", func() {
+			states := []mimir.RuleGroupState{
+				{Name: "alerts", File: ruleNamespace, LastEvaluation: evaluatedAt,
+					Rules: []mimir.RuleState{{Name: "Alert1", Health: "err", LastError: "bad query"}}},
+				{Name: "recordings", File: "other-namespace", LastEvaluation: evaluatedAt},
+			}
+
+			_, problems := checkActivation(states, ruleNamespace, pushed)
+
+			Expect(problems).To(HaveLen(2))
+			Expect(problems[0]).To(ContainSubstring("bad query"))
+			Expect(problems[1]).To(ContainSubstring("not loaded"))
+
+			states[1].File = ruleNamespace
+			states[1].LastEvaluation = time.Time{}
+			_, problems = checkActivation(states, ruleNamespace, pushed)
+			Expect(problems[1]).To(ContainSubstring("not been evaluated"))
+		})
+
+		It("should record the Active condition and report only its changes", func() {
+			rule := prometheusRule.DeepCopy()
+			Expect(k8sClient.Create(ctx, rule)).To(Succeed())
+			DeferCleanup(func() { Expect(k8sClient.Delete(ctx, rule)).To(Succeed()) })
+			groups, err := DesiredRuleGroups(rule)
+			Expect(err).NotTo(HaveOccurred())
+			state := &utils.SyncState[*monitoringv1.PrometheusRule]{
+				Object:       rule,
+				ClientConfig: &openawarenessv1beta1.ClientConfig{},
+				SyncContext:  ctx,
+			}
+			reconciler.VerifyActivation = true
+			ruler := &activationClient{
+				simulationClient: &simulationClient{MockAwarenessClient: clients.NewMockAwarenessClient()},
+			}
+			sync := &prometheusRuleSync{r: reconciler}
+			ruleSync := &openawarenessv1beta1.PrometheusRuleSync{}
+
+			By("reporting groups not loaded by the ruler yet")
+			Expect(sync.reportActivation(ctx, state, ruler, groups)).To(Equal(
+				ctrl.Result{RequeueAfter: activationRecheckInterval}))
+			Expect(k8sClient.Get(ctx, typeNamespacedName, ruleSync)).To(Succeed())
+			active := meta.FindStatusCondition(ruleSync.Status.Conditions, openawarenessv1beta1.ConditionTypeActive)
+			Expect(active).NotTo(BeNil())
+			Expect(active.Status).To(Equal(metav1.ConditionFalse))
+			Expect(active.Reason).To(Equal(openawarenessv1beta1.ReasonRuleGroupsInactive))
+			Expect(ruleSync.Status.LastEvaluation).To(BeNil())
+			Expect(fakeRecorder.Events).To(Receive(ContainSubstring("RuleGroupsInactive")))
+
+			By("reporting active groups with their last evaluation once")
+			ruler.states = []mimir.RuleGroupState{{Name: "test-group", File: ruleNamespace, LastEvaluation: evaluatedAt}}
+			Expect(sync.reportActivation(ctx, state, ruler, groups)).To(Equal(ctrl.Result{}))
+			Expect(k8sClient.Get(ctx, typeNamespacedName, ruleSync)).To(Succeed())
+			Expect(meta.IsStatusConditionTrue(ruleSync.Status.Conditions,
+				openawarenessv1beta1.ConditionTypeActive)).To(BeTrue())
+			Expect(ruleSync.Status.LastEvaluation).NotTo(BeNil())
+			Expect(ruleSync.Status.LastEvaluation.Time.Equal(evaluatedAt)).To(BeTrue())
+			Expect(fakeRecorder.Events).To(Receive(ContainSubstring("RuleGroupsActive")))
+
+			sync.reportActivation(ctx, state, ruler, groups)
+			Expect(fakeRecorder.Events).NotTo(Receive())
+
+			By("reporting an unreadable ruler state as unknown")
+			ruler.err = errors.New("connection refused")
+			sync.reportActivation(ctx, state, ruler, groups)
+			Expect(k8sClient.Get(ctx, typeNamespacedName, ruleSync)).To(Succeed())
+			Expect(meta.FindStatusCondition(ruleSync.Status.Conditions,
+				openawarenessv1beta1.ConditionTypeActive).Reason).To(Equal(openawarenessv1beta1.ReasonActivationUnknown))
+			Expect(fakeRecorder.Events).To(Receive(ContainSubstring("RuleGroupsActivationUnknown")))
+
+			By("clearing the condition once activation checks are disabled")
+			reconciler.VerifyActivation = false
+			sync.reportSyncStatus(ctx, state, "", nil)
+			Expect(k8sClient.Get(ctx, typeNamespacedName, ruleSync)).To(Succeed())
+			Expect(meta.FindStatusCondition(ruleSync.Status.Conditions,
+				openawarenessv1beta1.ConditionTypeActive)).To(BeNil())
+		})
+
+		It("should back off the recheck of groups staying inactive", func() {
+			now := time.Now()
+			Expect(activationRecheckDelay(now, now)).To(Equal(activationRecheckInterval))
+			Expect(activationRecheckDelay(now.Add(-2*time.Minute), now)).To(Equal(2 * time.Minute))
+			Expect(activationRecheckDelay(now.Add(-time.Hour), now)).To(Equal(maxActivationRecheckInterval))
+		})
+	})
+
+	Context("When pruning rule groups", func() {
+		owned := func(name string) rulefmt.RuleGroup {
+			return rulefmt.RuleGroup{Name: name, Rules: []rulefmt.Rule{{
+				Alert:  name,
+				Expr:   "up == 0",
+				Labels: map[string]string{utils.OwnerLabel: utils.OwnerReference(prometheusRule)},
+			}}}
+		}
+		clientConfig := &openawarenessv1beta1.ClientConfig{}
+
+		It("should delete recorded groups removed from the rule or its tenants", func() {
+			mockClient := clients.NewMockAwarenessClient()
+			for tenant, names := range map[string][]string{tenantID: {"kept", "removed"}, "old-tenant": {"moved"}} {
+				for _, name := range names {
+					Expect(mockClient.CreateRuleGroup(ctx, ruleNamespace, owned(name), tenant)).To(Succeed())
+				}
+			}
+			recorded := utils.GroupChecksums{
+				tenantID:     {"kept": "1", "removed": "2"},
+				"old-tenant": {"moved": "3"},
+			}
+			partitions := map[string][]rulefmt.RuleGroup{tenantID: {owned("kept")}}
+
+			Expect(reconciler.pruneRuleGroups(ctx, prometheusRule, clientConfig, mockClient, recorded, partitions)).
+				To(Succeed())
+
+			Expect(mockClient.GetRuleGroup(ctx, ruleNamespace, "kept", tenantID)).NotTo(BeNil())
+			Expect(mockClient.GetRuleGroup(ctx, ruleNamespace, "removed", tenantID)).To(BeNil())
+			Expect(mockClient.GetRuleGroup(ctx, ruleNamespace, "moved", "old-tenant")).To(BeNil())
+			Expect(fakeRecorder.Events).To(Receive(SatisfyAll(
+				ContainSubstring("RuleGroupsPruned"),
+				ContainSubstring("Deleted 2 rule group(s)"),
+				ContainSubstring(tenantID+"/removed, old-tenant/moved"),
+			)))
+		})
+
+		It("should delete owned groups from Mimir without recorded groups", func() {
+			mockClient := clients.NewMockAwarenessClient()
+			foreign := rulefmt.RuleGroup{Name: "foreign", Rules: []rulefmt.Rule{{Alert: "Foreign", Expr: "up == 0"}}}
+			for _, group := range []rulefmt.RuleGroup{owned("kept"), owned("stale"), foreign} {
+				Expect(mockClient.CreateRuleGroup(ctx, ruleNamespace, group, tenantID)).To(Succeed())
+			}
+			partitions := map[string][]rulefmt.RuleGroup{tenantID: {owned("kept")}}
+
+			Expect(reconciler.pruneRuleGroups(ctx, prometheusRule, clientConfig, mockClient, nil, partitions)).
+				To(Succeed())
+
+			Expect(mockClient.GetRuleGroup(ctx, ruleNamespace, "stale", tenantID)).To(BeNil())
+			Expect(mockClient.GetRuleGroup(ctx, ruleNamespace, "kept", tenantID)).NotTo(BeNil())
+			Expect(mockClient.GetRuleGroup(ctx, ruleNamespace, "foreign", tenantID)).NotTo(BeNil())
+			Expect(fakeRecorder.Events).To(Receive(ContainSubstring(tenantID + "/stale")))
+			Expect(fakeRecorder.Events).To(BeEmpty())
+		})
+
+		It("should delete the other groups when a group fails to delete", func() {
+			mockClient := &failingGroupClient{
+				MockAwarenessClient: clients.NewMockAwarenessClient(),
+				failing:             map[string]bool{"broken": true},
+			}
+			for _, name := range []string{"broken", "removed", "test-group"} {
+				Expect(mockClient.CreateRuleGroup(ctx, ruleNamespace, owned(name), tenantID)).To(Succeed())
+			}
+			recorded := utils.GroupChecksums{tenantID: {"broken": "1", "removed": "2", "test-group": "3"}}
+
+			By("Pruning the groups removed from the rule")
+			err := reconciler.pruneRuleGroups(ctx, prometheusRule, clientConfig, mockClient, recorded,
+				map[string][]rulefmt.RuleGroup{tenantID: {owned("test-group")}})
+			Expect(err).To(MatchError(ContainSubstring("rule group broken")))
+			Expect(mockClient.GetRuleGroup(ctx, ruleNamespace, "removed", tenantID)).To(BeNil())
+			Expect(fakeRecorder.Events).To(Receive(SatisfyAll(
+				ContainSubstring("Deleted 1 rule group(s)"),
+				ContainSubstring(tenantID+"/removed"),
+			)))
+
+			By("Deleting all groups of the rule")
+			rule := prometheusRule.DeepCopy()
+			rule.Annotations[utils.GroupChecksumsAnnotation] = `{"` + tenantID + `":{"broken":"1","test-group":"3"}}`
+			state := &utils.SyncState[*monitoringv1.PrometheusRule]{Object: rule, ClientConfig: clientConfig}
+			err = (&prometheusRuleSync{r: reconciler}).Delete(ctx, state, mockClient)
+			Expect(err).To(MatchError(ContainSubstring("rule group broken")))
+			Expect(mockClient.GetRuleGroup(ctx, ruleNamespace, "test-group", tenantID)).To(BeNil())
+			Expect(mockClient.GetRuleGroup(ctx, ruleNamespace, "broken", tenantID)).NotTo(BeNil())
+		})
+
+		It("should delete all recorded groups from the rule namespace synced before", func() {
+			rule := prometheusRule.DeepCopy()
+			rule.Annotations = map[string]string{
+				utils.RulesNamespaceAnnotation:       "alerts",
+				utils.SyncedRulesNamespaceAnnotation: "legacy",
+			}
+			mockClient := clients.NewMockAwarenessClient()
+			Expect(mockClient.CreateRuleGroup(ctx, "legacy", owned("kept"), tenantID)).To(Succeed())
+			Expect(mockClient.CreateRuleGroup(ctx, "alerts", owned("kept"), tenantID)).To(Succeed())
+			recorded := utils.GroupChecksums{tenantID: {"kept": "1"}}
+			partitions := map[string][]rulefmt.RuleGroup{tenantID: {owned("kept")}}
+
+			Expect(reconciler.pruneRuleGroups(ctx, rule, clientConfig, mockClient, recorded, partitions)).
+				To(Succeed())
+
+			Expect(mockClient.GetRuleGroup(ctx, "legacy", "kept", tenantID)).To(BeNil())
+			Expect(mockClient.GetRuleGroup(ctx, "alerts", "kept", tenantID)).NotTo(BeNil())
+			Expect(fakeRecorder.Events).To(Receive(ContainSubstring(tenantID + "/kept")))
+		})
+	})
+
+	Context("When detecting drift", func() {
+		clientConfig := &openawarenessv1beta1.ClientConfig{}
+		pushed := rulefmt.RuleGroup{Name: "drift", Interval: model.Duration(time.Minute), Rules: []rulefmt.Rule{
+			{Alert: "Down", Expr: "up == 0"},
+			{Record: "job:up:sum", Expr: "sum by (job) (up)"},
+		}}
+		var checksum string
+
+		BeforeEach(func() {
+			var err error
+			checksum, err = utils.RuleGroupChecksum(clientConfig, tenantID, pushed)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should not report groups unchanged in Mimir", func() {
+			mockClient := clients.NewMockAwarenessClient()
+			Expect(mockClient.CreateRuleGroup(ctx, ruleNamespace, pushed, tenantID)).To(Succeed())
+
+			Expect(reconciler.reportDrift(ctx, GinkgoLogr, prometheusRule, clientConfig, mockClient,
+				ruleNamespace, tenantID, pushed, nil, checksum)).To(BeFalse())
+			Expect(fakeRecorder.Events).To(BeEmpty())
+		})
+
+		It("should not report groups unchanged in Mimir pushed with source tenants", func() {
+			sources := []string{"team-a", "team-b"}
+			federated, err := utils.RuleGroupChecksum(clientConfig, tenantID, pushed, sources...)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(federated).NotTo(Equal(checksum))
+			mockClient := clients.NewMockAwarenessClient()
+			Expect(PushRuleGroup(ctx, mockClient, ruleNamespace, pushed, sources, tenantID)).To(Succeed())
+			Expect(mockClient.SourceTenants(tenantID, ruleNamespace, pushed.Name)).To(Equal(sources))
+
+			Expect(reconciler.reportDrift(ctx, GinkgoLogr, prometheusRule, clientConfig, mockClient,
+				ruleNamespace, tenantID, pushed, sources, federated)).To(BeFalse())
+			Expect(fakeRecorder.Events).To(BeEmpty())
+		})
+
+		It("should report the differing fields of groups modified in Mimir", func() {
+			modified := pushed
+			modified.Interval = model.Duration(5 * time.Minute)
+			modified.Rules = []rulefmt.Rule{{Alert: "Down", Expr: "up == 1"}}
+			mockClient := clients.NewMockAwarenessClient()
+			Expect(mockClient.CreateRuleGroup(ctx, ruleNamespace, modified, tenantID)).To(Succeed())
+
+			Expect(reconciler.reportDrift(ctx, GinkgoLogr, prometheusRule, clientConfig, mockClient,
+				ruleNamespace, tenantID, pushed, nil, checksum)).To(BeTrue())
+			Expect(fakeRecorder.Events).To(Receive(SatisfyAll(
+				ContainSubstring("RuleGroupModified"),
+				ContainSubstring("interval 5m in Mimir, 1m desired"),
+				ContainSubstring("1 rule(s) in Mimir, 2 desired"),
+				ContainSubstring("1 changed expression(s)"),
+			)))
+		})
+
+		It("should report groups deleted in Mimir", func() {
+			Expect(reconciler.reportDrift(ctx, GinkgoLogr, prometheusRule, clientConfig, clients.NewMockAwarenessClient(),
+				ruleNamespace, tenantID, pushed, nil, checksum)).To(BeTrue())
+			Expect(fakeRecorder.Events).To(Receive(ContainSubstring("was deleted outside the operator")))
+		})
+
+		It("should not check groups without recorded checksum", func() {
+			Expect(reconciler.reportDrift(ctx, GinkgoLogr, prometheusRule, clientConfig, clients.NewMockAwarenessClient(),
+				ruleNamespace, tenantID, pushed, nil, "")).To(BeFalse())
+			Expect(fakeRecorder.Events).To(BeEmpty())
+		})
+	})
+
+	Context("When summarizing the sync of rule groups", func() {
+		It("should count synced and failed groups in the PrometheusRuleSync", func() {
+			rule := prometheusRule.DeepCopy()
+			rule.Spec.Groups = append(rule.Spec.Groups, monitoringv1.RuleGroup{
+				Name:  "broken-group",
+				Rules: []monitoringv1.Rule{{Alert: "Broken", Expr: intstr.FromString("up == 0")}},
+			})
+			Expect(k8sClient.Create(ctx, rule)).To(Succeed())
+			DeferCleanup(func() { Expect(k8sClient.Delete(ctx, rule)).To(Succeed()) })
+			groups, err := DesiredRuleGroups(rule)
+			Expect(err).NotTo(HaveOccurred())
+			mockClient := &failingGroupClient{
+				MockAwarenessClient: clients.NewMockAwarenessClient(),
+				failing:             map[string]bool{"broken-group": true},
+			}
+			state := &utils.SyncState[*monitoringv1.PrometheusRule]{
+				Object: rule,
+				ClientConfig: &openawarenessv1beta1.ClientConfig{
+					ObjectMeta: metav1.ObjectMeta{Generation: 3},
+					Spec:       openawarenessv1beta1.ClientConfigSpec{Address: "http://mimir:9009/", PathPrefix: "/mimir"},
+				},
+			}
+
+			sync := &prometheusRuleSync{r: reconciler}
+			err = sync.Push(ctx, state, mockClient, groups)
+			Expect(err).To(MatchError(ContainSubstring("broken-group")))
+			Expect(mockClient.GetRuleGroup(ctx, ruleNamespace, "test-group", tenantID)).NotTo(BeNil())
+			Expect(utils.GroupChecksumsOf(rule)[tenantID]).To(HaveKey("test-group"))
+			Expect(utils.GroupChecksumsOf(rule)[tenantID]).NotTo(HaveKey("broken-group"))
+			_, err = sync.Report(ctx, state, utils.SyncOutcome[[]rulefmt.RuleGroup]{Stage: utils.SyncStagePush, Err: err})
+			Expect(err).To(HaveOccurred())
+			Expect(fakeRecorder.Events).To(Receive(ContainSubstring("Failed to create 1 of 2 rule group(s)")))
+
+			ruleSync := &openawarenessv1beta1.PrometheusRuleSync{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, ruleSync)).To(Succeed())
+			Expect(ruleSync.OwnerReferences).To(ConsistOf(HaveField("UID", rule.UID)))
+			Expect(ruleSync.Status.GroupsDesired).To(BeEquivalentTo(2))
+			Expect(ruleSync.Status.GroupsSynced).To(BeEquivalentTo(1))
+			Expect(ruleSync.Status.GroupsFailed).To(BeEquivalentTo(1))
+			Expect(ruleSync.Status.FailureReason).To(Equal(openawarenessv1beta1.ReasonNetworkError))
+			Expect(ruleSync.Status.FailureMessage).To(ContainSubstring("broken-group"))
+			Expect(ruleSync.Status.FailedGroups).To(Equal([]string{tenantID + "/broken-group"}))
+			Expect(ruleSync.Status.ResolvedTenants).To(BeEmpty())
+			Expect(ruleSync.Status.Conditions).To(ConsistOf(
+				SatisfyAll(
+					HaveField("Type", openawarenessv1beta1.ConditionTypeSynced),
+					HaveField("Status", metav1.ConditionFalse),
+				),
+				SatisfyAll(
+					HaveField("Type", openawarenessv1beta1.ConditionTypePartiallySynced),
+					HaveField("Status", metav1.ConditionTrue),
+					HaveField("Reason", openawarenessv1beta1.ReasonGroupsFailed),
+					HaveField("Message", ContainSubstring(tenantID+"/broken-group")),
+				),
+			))
+
+			// Only the failed group is pushed again once it succeeds
+			mockClient.failing = nil
+			sync = &prometheusRuleSync{r: reconciler}
+			Expect(sync.Push(ctx, state, mockClient, groups)).To(Succeed())
+			Expect(sync.skipped).To(Equal(1))
+			_, err = sync.Report(ctx, state, utils.SyncOutcome[[]rulefmt.RuleGroup]{
+				Stage: utils.SyncStageSynced, Payload: groups,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, ruleSync)).To(Succeed())
+			Expect(ruleSync.Status.GroupsSynced).To(BeEquivalentTo(2))
+			Expect(ruleSync.Status.GroupsFailed).To(BeZero())
+			Expect(ruleSync.Status.FailureReason).To(BeEmpty())
+			Expect(ruleSync.Status.FailedGroups).To(BeEmpty())
+			Expect(ruleSync.Status.ResolvedTenants).To(Equal([]string{tenantID}))
+			Expect(ruleSync.Status.ResolvedEndpoint).To(Equal("http://mimir:9009/mimir"))
+			Expect(ruleSync.Status.ClientConfigGeneration).To(BeEquivalentTo(3))
+			Expect(meta.IsStatusConditionTrue(ruleSync.Status.Conditions, openawarenessv1beta1.ConditionTypeSynced)).
+				To(BeTrue())
+			Expect(meta.IsStatusConditionFalse(ruleSync.Status.Conditions,
+				openawarenessv1beta1.ConditionTypePartiallySynced)).To(BeTrue())
+		})
+
+		It("should report the evaluation parity of dual-written rules", func() {
+			rule := prometheusRule.DeepCopy()
+			rule.Annotations[utils.DualWriteAnnotation] = "true"
+			Expect(k8sClient.Create(ctx, rule)).To(Succeed())
+			DeferCleanup(func() { Expect(k8sClient.Delete(ctx, rule)).To(Succeed()) })
+			groups, err := DesiredRuleGroups(rule)
+			Expect(err).NotTo(HaveOccurred())
+			state := &utils.SyncState[*monitoringv1.PrometheusRule]{
+				Object:       rule,
+				ClientConfig: &openawarenessv1beta1.ClientConfig{},
+				SyncContext:  ctx,
+			}
+			alerts := `ALERTS{alertname="TestAlert"}`
+			reconciler.ParityPrometheus = seriesCounts{alerts: 2}
+			mimirClient := &seriesCountingClient{
+				MockAwarenessClient: clients.NewMockAwarenessClient(),
+				counts:              seriesCounts{alerts: 1},
+			}
+			sync := &prometheusRuleSync{r: reconciler}
+			ruleSync := &openawarenessv1beta1.PrometheusRuleSync{}
+
+			By("reporting rules producing a different number of series")
+			Expect(sync.reportParity(ctx, state, mimirClient, groups)).To(Equal(
+				ctrl.Result{RequeueAfter: parityRecheckInterval}))
+			Expect(k8sClient.Get(ctx, typeNamespacedName, ruleSync)).To(Succeed())
+			Expect(ruleSync.Status.Parity).NotTo(BeNil())
+			Expect(ruleSync.Status.Parity.RulesCompared).To(BeEquivalentTo(1))
+			Expect(ruleSync.Status.Parity.Mismatches).To(ConsistOf(openawarenessv1beta1.RuleParity{
+				Tenant: tenantID, Group: "test-group", Rule: "TestAlert", PrometheusSeries: 2, MimirSeries: 1,
+			}))
+			parity := meta.FindStatusCondition(ruleSync.Status.Conditions,
+				openawarenessv1beta1.ConditionTypeEvaluationParity)
+			Expect(parity).NotTo(BeNil())
+			Expect(parity.Reason).To(Equal(openawarenessv1beta1.ReasonParityMismatch))
+			Expect(fakeRecorder.Events).To(Receive(ContainSubstring("EvaluationParityMismatch")))
+
+			By("reporting parity once both produce the same series")
+			mimirClient.counts[alerts] = 2
+			sync.reportParity(ctx, state, mimirClient, groups)
+			Expect(k8sClient.Get(ctx, typeNamespacedName, ruleSync)).To(Succeed())
+			Expect(ruleSync.Status.Parity.RulesMatching).To(BeEquivalentTo(1))
+			Expect(ruleSync.Status.Parity.Mismatches).To(BeEmpty())
+			Expect(meta.IsStatusConditionTrue(ruleSync.Status.Conditions,
+				openawarenessv1beta1.ConditionTypeEvaluationParity)).To(BeTrue())
+
+			By("clearing the parity once the rule is no longer dual-written")
+			delete(rule.Annotations, utils.DualWriteAnnotation)
+			sync.reportSyncStatus(ctx, state, "", nil)
+			Expect(k8sClient.Get(ctx, typeNamespacedName, ruleSync)).To(Succeed())
+			Expect(ruleSync.Status.Parity).To(BeNil())
+			Expect(meta.FindStatusCondition(ruleSync.Status.Conditions,
+				openawarenessv1beta1.ConditionTypeEvaluationParity)).To(BeNil())
+		})
+
+		It("should clamp or reject rule group intervals below the minimum of the ClientConfig", func() {
+			rule := prometheusRule.DeepCopy()
+			interval := monitoringv1.Duration("1s")
+			rule.Spec.Groups[0].Interval = &interval
+			clientConfig := &openawarenessv1beta1.ClientConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-client"},
+				Spec: openawarenessv1beta1.ClientConfigSpec{
+					MinRuleInterval: &openawarenessv1beta1.RuleIntervalPolicy{
+						Minimum: metav1.Duration{Duration: 15 * time.Second},
+					},
+				},
+			}
+			state := &utils.SyncState[*monitoringv1.PrometheusRule]{Object: rule, ClientConfig: clientConfig}
+			sync := &prometheusRuleSync{r: reconciler}
+
+			By("clamping the interval by default")
+			groups, err := sync.Render(ctx, state)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(groups[0].Interval).To(Equal(model.Duration(15 * time.Second)))
+			Expect(sync.Validate(ctx, state, groups)).To(Succeed())
+			Expect(fakeRecorder.Events).To(Receive(SatisfyAll(
+				ContainSubstring("RuleIntervalClamped"), ContainSubstring("test-group"))))
+
+			By("rejecting the groups with the reject action")
+			clientConfig.Spec.MinRuleInterval.Action = openawarenessv1beta1.RuleIntervalReject
+			groups, err = sync.Render(ctx, state)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(groups[0].Interval).To(Equal(model.Duration(time.Second)))
+			err = sync.Validate(ctx, state, groups)
+			Expect(err).To(MatchError(errRuleIntervalTooShort))
+			Expect(err.Error()).To(ContainSubstring("15s of ClientConfig test-client: test-group"))
+
+			By("leaving groups at or above the minimum and without interval alone")
+			interval = "15s"
+			groups, err = sync.Render(ctx, state)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sync.Validate(ctx, state, groups)).To(Succeed())
+			rule.Spec.Groups[0].Interval = nil
+			groups, err = sync.Render(ctx, state)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sync.Validate(ctx, state, groups)).To(Succeed())
+			Expect(fakeRecorder.Events).To(BeEmpty())
+		})
+
+		It("should inject the matchers of the annotation or preview them in dry-run mode", func() {
+			rule := prometheusRule.DeepCopy()
+			rule.Annotations[utils.InjectMatchersAnnotation] = "cluster=eu-1"
+			state := &utils.SyncState[*monitoringv1.PrometheusRule]{Object: rule}
+			sync := &prometheusRuleSync{r: reconciler}
+
+			By("rewriting the expressions")
+			groups, err := sync.Render(ctx, state)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(groups[0].Rules[0].Expr).To(Equal(`up{cluster="eu-1"} == 0`))
+			Expect(fakeRecorder.Events).To(BeEmpty())
+
+			By("only previewing the rewrite in dry-run mode")
+			rule.Annotations[utils.InjectMatchersDryRunAnnotation] = "true"
+			groups, err = sync.Render(ctx, state)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(groups[0].Rules[0].Expr).To(Equal("up == 0"))
+			Expect(fakeRecorder.Events).To(Receive(SatisfyAll(
+				ContainSubstring("ExpressionRewritePreview"), ContainSubstring(`+ up{cluster="eu-1"} == 0`))))
+
+			By("rejecting invalid matchers")
+			rule.Annotations[utils.InjectMatchersAnnotation] = "cluster"
+			_, err = DesiredRuleGroups(rule)
+			Expect(err).To(MatchError(ContainSubstring(utils.InjectMatchersAnnotation)))
+		})
+
+		It("should diff the rule groups against Mimir in read-only mode", func() {
+			rule := prometheusRule.DeepCopy()
+			Expect(k8sClient.Create(ctx, rule)).To(Succeed())
+			DeferCleanup(func() { Expect(k8sClient.Delete(ctx, rule)).To(Succeed()) })
+			groups, err := DesiredRuleGroups(rule)
+			Expect(err).NotTo(HaveOccurred())
+			mockClient := clients.NewMockAwarenessClient()
+			state := &utils.SyncState[*monitoringv1.PrometheusRule]{
+				Object:       rule,
+				ClientConfig: &openawarenessv1beta1.ClientConfig{},
+				ReadOnly:     true,
+			}
+			sync := &prometheusRuleSync{r: reconciler}
+
+			By("reporting groups missing in Mimir as created")
+			diff, err := sync.Diff(ctx, state, mockClient, groups)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(diff).To(Equal("create " + tenantID + "/test-group"))
+			Expect(mockClient.GetRuleGroup(ctx, ruleNamespace, "test-group", tenantID)).To(BeNil())
+
+			By("reporting nothing once Mimir is in sync")
+			Expect(mockClient.CreateRuleGroup(ctx, ruleNamespace, groups[0], tenantID)).To(Succeed())
+			Expect(sync.Diff(ctx, state, mockClient, groups)).To(BeEmpty())
+
+			By("reporting changed and removed groups")
+			changed := groups[0]
+			changed.Rules = []rulefmt.Rule{changed.Rules[0]}
+			changed.Rules[0].Expr = "up == 1"
+			diff, err = sync.Diff(ctx, state, mockClient, []rulefmt.RuleGroup{changed})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(diff).To(Equal("update " + tenantID + "/test-group: 1 changed expression(s)"))
+			Expect(sync.Diff(ctx, state, mockClient, nil)).To(Equal("delete " + tenantID + "/test-group"))
+
+			By("recording the outcome in the WouldSync condition")
+			_, err = sync.Report(ctx, state, utils.SyncOutcome[[]rulefmt.RuleGroup]{
+				Stage: utils.SyncStageDiff, Payload: groups, Diff: diff,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			ruleSync := &openawarenessv1beta1.PrometheusRuleSync{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, ruleSync)).To(Succeed())
+			Expect(ruleSync.Status.Conditions).To(ContainElement(SatisfyAll(
+				HaveField("Type", openawarenessv1beta1.ConditionTypeWouldSync),
+				HaveField("Status", metav1.ConditionTrue),
+				HaveField("Reason", openawarenessv1beta1.ReasonWouldSync),
+				HaveField("Message", ContainSubstring(diff)),
+			)))
+			Expect(ruleSync.Status.Conditions).NotTo(ContainElement(
+				HaveField("Type", openawarenessv1beta1.ConditionTypeSynced)))
+		})
+	})
+
+	Context("When rendering rule templates", func() {
+		BeforeEach(func() {
+			prometheusRule.Annotations[utils.TemplateAnnotation] = "true"
+			prometheusRule.Annotations[utils.SecretDataRefsAnnotation] = "ConfigMap/test-thresholds"
+			prometheusRule.Spec.Groups[0].Labels = map[string]string{"team": "[[ .team ]]"}
+			prometheusRule.Spec.Groups[0].Rules[0].Expr = intstr.FromString("cpu_usage > [[ .cpuThreshold ]]")
+			prometheusRule.Spec.Groups[0].Rules[0].Annotations = map[string]string{
+				"summary": "{{ $value }} above [[ .cpuThreshold ]]",
+			}
+		})
+
+		It("should render expressions, labels and annotations with ConfigMap data", func() {
+			configMap := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-thresholds", Namespace: ruleNamespace},
+				Data:       map[string]string{"cpuThreshold": "0.9", "team": "payments"},
+			}
+			Expect(k8sClient.Create(ctx, configMap)).To(Succeed())
+			DeferCleanup(func() { Expect(k8sClient.Delete(ctx, configMap)).To(Succeed()) })
+
+			sync := &prometheusRuleSync{r: reconciler}
+			groups, err := sync.Render(ctx, &utils.SyncState[*monitoringv1.PrometheusRule]{Object: prometheusRule})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(groups[0].Labels).To(HaveKeyWithValue("team", "payments"))
+			Expect(groups[0].Rules[0].Expr).To(Equal("cpu_usage > 0.9"))
+			Expect(groups[0].Rules[0].Annotations).To(HaveKeyWithValue("summary", "{{ $value }} above 0.9"))
+			Expect(prometheusRule.Spec.Groups[0].Rules[0].Expr.StrVal).To(ContainSubstring("[["),
+				"the rule itself must not be modified")
+		})
+
+		It("should leave rules not opting in to templating unchanged", func() {
+			prometheusRule.Annotations[utils.TemplateAnnotation] = "false"
+			rendered, err := RenderRuleTemplates(prometheusRule, map[string]string{"cpuThreshold": "0.9"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(rendered).To(BeIdenticalTo(prometheusRule))
+		})
+
+		It("should wait for missing template data without retrying", func() {
+			sync := &prometheusRuleSync{r: reconciler}
+			state := &utils.SyncState[*monitoringv1.PrometheusRule]{Object: prometheusRule}
+			_, err := sync.Render(ctx, state)
+			Expect(err).To(MatchError(errTemplateData))
+
+			result, err := sync.Report(ctx, state, utils.SyncOutcome[[]rulefmt.RuleGroup]{
+				Stage: utils.SyncStageRender, Err: err,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeZero())
+			Expect(fakeRecorder.Events).To(Receive(ContainSubstring("test-thresholds")))
+		})
+
+		It("should reject invalid template data references", func() {
+			prometheusRule.Annotations[utils.SecretDataRefsAnnotation] = "thresholds"
+			_, err := ResolveRuleTemplates(ctx, k8sClient, prometheusRule)
+			Expect(err).To(MatchError(ContainSubstring(utils.SecretDataRefsAnnotation)))
+		})
+	})
+
+	Context("When simulating alerting rules", func() {
+		group := rulefmt.RuleGroup{Name: "alerts", Rules: []rulefmt.Rule{
+			{Alert: "Healthy", Expr: `up{job="api"} == 0`},
+			{Alert: "Typo", Expr: `rate(http_requets_total{job="api"}[5m] offset 1h) > 1 and on() up`},
+			{Alert: "Absent", Expr: `absent(heartbeat_total)`},
+			{Alert: "Recorded", Expr: `job:errors:rate5m > 0.1`},
+			{Alert: "Broken", Expr: `too_many_samples`},
+			{Record: "job:errors:rate5m", Expr: `missing_total`},
+		}}
+
+		It("should report failing expressions and selectors without series", func() {
+			prometheusRule.Spec.Groups = append(prometheusRule.Spec.Groups, monitoringv1.RuleGroup{
+				Name:  "recordings",
+				Rules: []monitoringv1.Rule{{Record: "job:errors:rate5m", Expr: intstr.FromString("missing_total")}},
+			})
+			client := &simulationClient{
+				MockAwarenessClient: clients.NewMockAwarenessClient(),
+				series:              map[string]bool{`up{job="api"}`: true, "up": true},
+			}
+
+			reconciler.simulateRuleGroup(ctx, prometheusRule, client, group, tenantID)
+
+			Expect(fakeRecorder.Events).To(Receive(SatisfyAll(
+				ContainSubstring("MissingSeriesWarning"),
+				ContainSubstring("Alerting rule Typo in group alerts selects no series in tenant "+tenantID),
+				ContainSubstring(`http_requets_total{job="api"}`),
+				Not(ContainSubstring("offset")),
+			)))
+			Expect(fakeRecorder.Events).To(Receive(SatisfyAll(
+				ContainSubstring("RuleSimulationFailed"),
+				ContainSubstring("Alerting rule Broken"),
+				ContainSubstring("query exceeds the sample limit"),
+			)))
+			Expect(fakeRecorder.Events).To(BeEmpty())
+			Expect(client.queries).NotTo(ContainElement("missing_total"))
+		})
+
+		It("should skip the simulation for clients without query support", func() {
+			reconciler.simulateRuleGroup(ctx, prometheusRule, clients.NewMockAwarenessClient(), group, tenantID)
+
+			Expect(fakeRecorder.Events).To(BeEmpty())
+		})
+	})
+
+	Context("When checking the rule policy", func() {
+		newPolicy := func(mode policy.Mode) *policy.RulePolicy {
+			return &policy.RulePolicy{
+				Mode:                mode,
+				RequiredLabels:      []string{"severity"},
+				RequiredAnnotations: []string{"runbook_url"},
+			}
+		}
+
+		It("should allow the push when the policy is not configured", func() {
+			Expect(reconciler.checkRulePolicy(ctrl.Log, prometheusRule, reconciler.RulePolicy)).To(BeTrue())
+			Expect(fakeRecorder.Events).To(BeEmpty())
+		})
+
+		It("should emit per-rule warnings and allow the push in warn mode", func() {
+			reconciler.RulePolicy = newPolicy(policy.ModeWarn)
+
+			Expect(reconciler.checkRulePolicy(ctrl.Log, prometheusRule, reconciler.RulePolicy)).To(BeTrue())
+			Expect(fakeRecorder.Events).To(Receive(SatisfyAll(
+				ContainSubstring("RulePolicyViolation"),
+				ContainSubstring(`test-group/TestAlert: missing annotation "runbook_url"`),
+			)))
+			Expect(fakeRecorder.Events).To(BeEmpty())
+		})
+
+		It("should block the push in block mode", func() {
+			reconciler.RulePolicy = newPolicy(policy.ModeBlock)
+
+			Expect(reconciler.checkRulePolicy(ctrl.Log, prometheusRule, reconciler.RulePolicy)).To(BeFalse())
+			Expect(fakeRecorder.Events).To(Receive(ContainSubstring("RulePolicyViolation")))
+			Expect(fakeRecorder.Events).To(Receive(ContainSubstring("RuleGroupsBlocked")))
+		})
+	})
+
 	Context("When converting rule groups", func() {
 		It("should convert PrometheusRule groups to Mimir format", func() {
 			groups := []monitoringv1.RuleGroup{
@@ -177,7 +902,8 @@ var _ = Describe("PrometheusRules Controller", func() {
 				},
 			}
 
-			converted := convert(groups)
+			converted, err := convert(groups)
+			Expect(err).NotTo(HaveOccurred())
 
 			Expect(converted).To(HaveLen(1))
 			Expect(converted[0].Name).To(Equal("test-group-1"))
@@ -203,11 +929,259 @@ var _ = Describe("PrometheusRules Controller", func() {
 				},
 			}
 
-			converted := convert(groups)
+			converted, err := convert(groups)
+			Expect(err).NotTo(HaveOccurred())
 
 			Expect(converted).To(HaveLen(2))
 			Expect(converted[0].Name).To(Equal("alerts"))
 			Expect(converted[1].Name).To(Equal("recordings"))
 		})
+
+		It("should map all fields supported by rulefmt and round-trip them", func() {
+			interval := monitoringv1.Duration("2m")
+			queryOffset := monitoringv1.Duration("30s")
+			forDuration := monitoringv1.Duration("5m")
+			keepFiringFor := monitoringv1.NonEmptyDuration("10m")
+			limit := 25
+			groups := []monitoringv1.RuleGroup{
+				{
+					Name:                    "alerts",
+					Labels:                  map[string]string{"team": "payments"},
+					Interval:                &interval,
+					QueryOffset:             &queryOffset,
+					Limit:                   &limit,
+					PartialResponseStrategy: "warn",
+					Rules: []monitoringv1.Rule{
+						{
+							Alert:         "Alert1",
+							Expr:          intstr.FromString("up == 0"),
+							For:           &forDuration,
+							KeepFiringFor: &keepFiringFor,
+							Labels:        map[string]string{"severity": "critical"},
+							Annotations:   map[string]string{"summary": "Instance is down"},
+						},
+					},
+				},
+			}
+
+			converted, err := convert(groups)
+			Expect(err).NotTo(HaveOccurred())
+
+			group := converted[0]
+			Expect(group.Labels).To(Equal(map[string]string{"team": "payments"}))
+			Expect(group.Interval).To(Equal(model.Duration(2 * time.Minute)))
+			Expect(group.QueryOffset).To(HaveValue(Equal(model.Duration(30 * time.Second))))
+			Expect(group.Limit).To(Equal(25))
+			Expect(group.Rules[0].For).To(Equal(model.Duration(5 * time.Minute)))
+			Expect(group.Rules[0].KeepFiringFor).To(Equal(model.Duration(10 * time.Minute)))
+
+			By("Round-tripping the converted group through the YAML sent to Mimir")
+			payload, err := yaml.Marshal(group)
+			Expect(err).NotTo(HaveOccurred())
+			var parsed rulefmt.RuleGroup
+			Expect(yaml.Unmarshal(payload, &parsed)).To(Succeed())
+			Expect(parsed).To(Equal(group))
+		})
+
+		It("should validate converted rule groups", func() {
+			valid, err := convert(prometheusRule.Spec.Groups)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ValidateRuleGroups(valid)).To(BeEmpty())
+
+			invalid, err := convert([]monitoringv1.RuleGroup{
+				{
+					Name: "alerts",
+					Rules: []monitoringv1.Rule{
+						{Alert: "Alert1", Expr: intstr.FromString("sum(up")},
+					},
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			errs := ValidateRuleGroups(invalid)
+			Expect(errs).To(HaveLen(1))
+			Expect(errs[0].Error()).To(ContainSubstring("Alert1"))
+		})
+
+		It("should apply the evaluation interval and query offset annotations to groups without one", func() {
+			interval := monitoringv1.Duration("5m")
+			prometheusRule.Spec.Groups = append(prometheusRule.Spec.Groups, monitoringv1.RuleGroup{
+				Name:     "own-interval",
+				Interval: &interval,
+				Rules:    []monitoringv1.Rule{{Alert: "Alert2", Expr: intstr.FromString("up == 0")}},
+			})
+			prometheusRule.Annotations[utils.EvaluationIntervalAnnotation] = "30s"
+			prometheusRule.Annotations[utils.QueryOffsetAnnotation] = "1m"
+
+			groups, err := DesiredRuleGroups(prometheusRule)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(groups[0].Interval).To(Equal(model.Duration(30 * time.Second)))
+			Expect(groups[1].Interval).To(Equal(model.Duration(5 * time.Minute)))
+			Expect(groups[0].QueryOffset).To(HaveValue(Equal(model.Duration(time.Minute))))
+
+			By("Rejecting invalid durations")
+			prometheusRule.Annotations[utils.EvaluationIntervalAnnotation] = "0s"
+			_, err = DesiredRuleGroups(prometheusRule)
+			Expect(err).To(MatchError(ContainSubstring(utils.EvaluationIntervalAnnotation)))
+			prometheusRule.Annotations[utils.EvaluationIntervalAnnotation] = "30s"
+			prometheusRule.Annotations[utils.QueryOffsetAnnotation] = "soon"
+			_, err = DesiredRuleGroups(prometheusRule)
+			Expect(err).To(MatchError(ContainSubstring(utils.QueryOffsetAnnotation)))
+		})
+
+		It("should partition recording and alerting rules by tenant", func() {
+			groups := []rulefmt.RuleGroup{
+				{Name: "mixed", Rules: []rulefmt.Rule{{Record: "job:up:sum"}, {Alert: "Alert1"}}},
+				{Name: "recordings", Rules: []rulefmt.Rule{{Record: "job:up:max"}}},
+				{Name: "empty"},
+			}
+			rule := &monitoringv1.PrometheusRule{ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{utils.MimirTenantAnnotation: "team"},
+			}}
+
+			By("Keeping all groups in the rule's tenant without annotations")
+			Expect(PartitionRuleGroups(rule, groups, nil)).To(Equal(map[string][]rulefmt.RuleGroup{"team": groups}))
+
+			By("Splitting mixed groups between the recording and alerting tenant")
+			rule.Annotations[utils.RecordingTenantAnnotation] = "recording"
+			partitions := PartitionRuleGroups(rule, groups, nil)
+			Expect(partitions).To(HaveLen(2))
+			Expect(partitions["recording"]).To(Equal([]rulefmt.RuleGroup{
+				{Name: "mixed", Rules: []rulefmt.Rule{{Record: "job:up:sum"}}},
+				{Name: "recordings", Rules: []rulefmt.Rule{{Record: "job:up:max"}}},
+			}))
+			Expect(partitions["team"]).To(Equal([]rulefmt.RuleGroup{
+				{Name: "mixed", Rules: []rulefmt.Rule{{Alert: "Alert1"}}},
+				{Name: "empty"},
+			}))
+			Expect(utils.TenantIDs(rule, nil)).To(Equal([]string{"recording", "team"}))
+
+			By("Resolving the tenants through the tenant aliases of the ClientConfig")
+			clientConfig := &openawarenessv1beta1.ClientConfig{Spec: openawarenessv1beta1.ClientConfigSpec{
+				TenantAliases: map[string]string{"recording": "org-1"},
+			}}
+			partitions = PartitionRuleGroups(rule, groups, clientConfig)
+			Expect(partitions).To(HaveKey("org-1"))
+			Expect(partitions).To(HaveKey("team"))
+			Expect(utils.TenantIDs(rule, clientConfig)).To(Equal([]string{"org-1", "team"}))
+
+			By("Routing groups of the group tenant map to their tenant whole")
+			rule.Annotations[utils.GroupTenantsAnnotation] = `{"mixed":"billing"}`
+			partitions = PartitionRuleGroups(rule, groups, nil)
+			Expect(partitions["billing"]).To(Equal([]rulefmt.RuleGroup{groups[0]}))
+			Expect(partitions["recording"]).To(Equal([]rulefmt.RuleGroup{groups[1]}))
+			Expect(partitions["team"]).To(Equal([]rulefmt.RuleGroup{groups[2]}))
+			Expect(utils.TenantIDs(rule, nil)).To(Equal([]string{"recording", "team", "billing"}))
+
+			delete(rule.Annotations, utils.RecordingTenantAnnotation)
+			partitions = PartitionRuleGroups(rule, groups, nil)
+			Expect(partitions["billing"]).To(Equal([]rulefmt.RuleGroup{groups[0]}))
+			Expect(partitions["team"]).To(Equal([]rulefmt.RuleGroup{groups[1], groups[2]}))
+
+			By("Rejecting an invalid source tenant map")
+			delete(rule.Annotations, utils.GroupTenantsAnnotation)
+			rule.Annotations[utils.SourceTenantsAnnotation] = `{"mixed":[]}`
+			_, err := DesiredRuleGroups(rule)
+			Expect(err).To(MatchError(ContainSubstring(utils.SourceTenantsAnnotation)))
+			delete(rule.Annotations, utils.SourceTenantsAnnotation)
+
+			By("Rejecting an invalid group tenant map")
+			rule.Annotations[utils.GroupTenantsAnnotation] = "mixed=billing"
+			_, err = DesiredRuleGroups(rule)
+			Expect(err).To(MatchError(ContainSubstring(utils.GroupTenantsAnnotation)))
+		})
+
+		It("should reject invalid durations", func() {
+			invalid := monitoringv1.Duration("soon")
+			groups := []monitoringv1.RuleGroup{
+				{
+					Name: "alerts",
+					Rules: []monitoringv1.Rule{
+						{Alert: "Alert1", Expr: intstr.FromString("up == 0"), For: &invalid},
+					},
+				},
+			}
+
+			_, err := convert(groups)
+
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Alert1"))
+		})
 	})
 })
+
+// simulationClient answers the queries of rule simulations: queries in series have series,
+// too_many_samples fails and any other query succeeds without series.
+type simulationClient struct {
+	*clients.MockAwarenessClient
+	series  map[string]bool
+	queries []string
+}
+
+func (c *simulationClient) Query(context.Context, string, []string) (*http.Response, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (c *simulationClient) QueryHasSeries(_ context.Context, query string, _ []string) (bool, error) {
+	c.queries = append(c.queries, query)
+	if query == "too_many_samples" {
+		return false, errors.New("query exceeds the sample limit")
+	}
+	return c.series[query], nil
+}
+
+func (c *simulationClient) ActiveRuleGroups(context.Context, string) ([]mimir.RuleGroupState, error) {
+	return nil, nil
+}
+
+// activationClient answers ActiveRuleGroups with states, or fails with err if set.
+type activationClient struct {
+	*simulationClient
+	states []mimir.RuleGroupState
+	err    error
+}
+
+func (c *activationClient) ActiveRuleGroups(context.Context, string) ([]mimir.RuleGroupState, error) {
+	return c.states, c.err
+}
+
+// failingGroupClient fails to create the rule groups named in failing.
+type failingGroupClient struct {
+	*clients.MockAwarenessClient
+	failing map[string]bool
+}
+
+func (c *failingGroupClient) CreateRuleGroup(
+	ctx context.Context,
+	namespace string,
+	group rulefmt.RuleGroup,
+	tenantID string,
+) error {
+	if c.failing[group.Name] {
+		return errors.New("dial tcp: connection refused")
+	}
+	return c.MockAwarenessClient.CreateRuleGroup(ctx, namespace, group, tenantID)
+}
+
+func (c *failingGroupClient) DeleteRuleGroup(ctx context.Context, namespace, groupName string, tenantID string) error {
+	if c.failing[groupName] {
+		return errors.New("dial tcp: connection refused")
+	}
+	return c.MockAwarenessClient.DeleteRuleGroup(ctx, namespace, groupName, tenantID)
+}
+
+// seriesCounts counts the series of a selector from a fixed map.
+type seriesCounts map[string]int64
+
+func (c seriesCounts) CountSeries(_ context.Context, query string, _ []string) (int64, error) {
+	return c[query], nil
+}
+
+// seriesCountingClient counts series in Mimir from a fixed map.
+type seriesCountingClient struct {
+	*clients.MockAwarenessClient
+	counts seriesCounts
+}
+
+func (c *seriesCountingClient) CountSeries(ctx context.Context, query string, tenantIDs []string) (int64, error) {
+	return c.counts.CountSeries(ctx, query, tenantIDs)
+}
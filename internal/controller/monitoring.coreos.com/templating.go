@@ -0,0 +1,101 @@
+package monitoringcoreoscom
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	openawarenessv1beta1 "github.com/syndlex/openawareness-controller/api/openawareness/v1beta1"
+	"github.com/syndlex/openawareness-controller/internal/controller/utils"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// errTemplateData reports template variables of a PrometheusRule that could not be read.
+var errTemplateData = errors.New("unable to read template data")
+
+// ResolveRuleTemplates returns the rule with its templates rendered with the data of the
+// ConfigMaps and Secrets of its SecretDataRefsAnnotation, see RenderRuleTemplates. Rules not
+// opting in with the TemplateAnnotation are returned unchanged.
+// Returns an error wrapping errTemplateData if the data cannot be read, or the error of
+// RenderRuleTemplates.
+func ResolveRuleTemplates(
+	ctx context.Context,
+	reader client.Reader,
+	rule *monitoringv1.PrometheusRule,
+) (*monitoringv1.PrometheusRule, error) {
+	if !utils.TemplatingEnabled(rule) {
+		return rule, nil
+	}
+	refs, err := utils.SecretDataRefs(rule)
+	if err != nil {
+		return nil, err
+	}
+	data, _, err := utils.GetSecretData(ctx, reader, log.FromContext(ctx), rule.Namespace, refs,
+		openawarenessv1beta1.ReferenceMergeOverrideSilently)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errTemplateData, err)
+	}
+	return RenderRuleTemplates(rule, data)
+}
+
+// RenderRuleTemplates returns a copy of the rule with the expressions, labels and annotations of
+// its rules and the labels of its groups rendered with utils.RenderTemplate, so thresholds can be
+// kept in ConfigMaps, e.g. expr: cpu_usage > [[ .cpuThreshold ]]. The [[ ]] delimiters leave the
+// {{ }} templates Prometheus renders in labels and annotations untouched.
+// Rules not opting in with the TemplateAnnotation are returned unchanged.
+// Returns an error naming the group and rule of the first template that fails to render.
+func RenderRuleTemplates(rule *monitoringv1.PrometheusRule, data map[string]string) (*monitoringv1.PrometheusRule, error) {
+	if !utils.TemplatingEnabled(rule) {
+		return rule, nil
+	}
+	rendered := rule.DeepCopy()
+	for i := range rendered.Spec.Groups {
+		group := &rendered.Spec.Groups[i]
+		if err := renderValues(group.Labels, data); err != nil {
+			return nil, fmt.Errorf("group %s: labels: %w", group.Name, err)
+		}
+		for j := range group.Rules {
+			r := &group.Rules[j]
+			name := r.Alert
+			if name == "" {
+				name = r.Record
+			}
+			if r.Expr.Type == intstr.String {
+				expr, err := utils.RenderTemplate(r.Expr.StrVal, data)
+				if err != nil {
+					return nil, fmt.Errorf("group %s: rule %s: expr: %w", group.Name, name, err)
+				}
+				r.Expr = intstr.FromString(expr)
+			}
+			if err := renderValues(r.Labels, data); err != nil {
+				return nil, fmt.Errorf("group %s: rule %s: labels: %w", group.Name, name, err)
+			}
+			if err := renderValues(r.Annotations, data); err != nil {
+				return nil, fmt.Errorf("group %s: rule %s: annotations: %w", group.Name, name, err)
+			}
+		}
+	}
+	return rendered, nil
+}
+
+// renderValues renders the values of values in place.
+func renderValues(values map[string]string, data map[string]string) error {
+	for key, value := range values {
+		rendered, err := utils.RenderTemplate(value, data)
+		if err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+		values[key] = rendered
+	}
+	return nil
+}
+
+// isTemplateReadError reports whether err is a failure to read template data other than a
+// missing ConfigMap or Secret, whose creation triggers a new reconciliation.
+func isTemplateReadError(err error) bool {
+	return errors.Is(err, errTemplateData) && !apierrors.IsNotFound(err)
+}
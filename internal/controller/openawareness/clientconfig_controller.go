@@ -3,30 +3,69 @@ package openawareness
 
 import (
 	"context"
+	"fmt"
+	"strings"
 	"time"
 
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	k8sClient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	openawarenessv1beta1 "github.com/syndlex/openawareness-controller/api/openawareness/v1beta1"
+	"github.com/syndlex/openawareness-controller/internal/backup"
 	"github.com/syndlex/openawareness-controller/internal/clients"
 	"github.com/syndlex/openawareness-controller/internal/controller/utils"
+	"github.com/syndlex/openawareness-controller/internal/events"
+	"github.com/syndlex/openawareness-controller/internal/logging"
 )
 
+// tenantSampleSize is the number of tenant IDs reported in the status of a ClientConfig listing tenants
+const tenantSampleSize = 10
+
 // ClientConfigReconciler reconciles a ClientConfig object
 type ClientConfigReconciler struct {
 	k8sClient.Client
 	RulerClients clients.RulerClientCacheInterface
 	Scheme       *runtime.Scheme
+	// Recorder emits events on ClientConfigs, e.g. when a backup was restored.
+	// Events are not emitted if nil.
+	Recorder record.EventRecorder
+	// MaxConcurrentReconciles is the number of ClientConfigs reconciled in parallel, 1 if zero
+	MaxConcurrentReconciles int
+	// Backup holds the last pushed state of the tenants restored through the
+	// restore-backup annotation, the annotation is ignored if nil
+	Backup *backup.Store
+	// CircuitBreakerChanged re-queues the ClientConfigs whose circuit breaker opened or closed,
+	// to update their Degraded condition. Not watched if nil.
+	CircuitBreakerChanged <-chan event.GenericEvent
+	// ReadOnly adds no finalizers and ignores the restore-backup annotation, the clients of
+	// deleted ClientConfigs are removed from the cache once they are gone
+	ReadOnly bool
+	// HealthCheckInterval is the time after which a connected ClientConfig is reconciled again
+	// to check its health, its connectivity conditions are only updated on changes if zero
+	HealthCheckInterval time.Duration
 }
 
 //nolint:lll
 // +kubebuilder:rbac:groups=openawareness.syndlex,resources=clientconfigs,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=openawareness.syndlex,resources=clientconfigs/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=openawareness.syndlex,resources=clientconfigs/finalizers,verbs=update
+// +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -42,22 +81,31 @@ func (r *ClientConfigReconciler) Reconcile(ctx context.Context, req ctrl.Request
 	clientConfig := &openawarenessv1beta1.ClientConfig{}
 	if err := r.Get(ctx, req.NamespacedName, clientConfig); err != nil {
 		logger.Info("unable to get clientConfig")
+		if r.ReadOnly && apierrors.IsNotFound(err) {
+			r.RulerClients.RemoveClient(req.Namespace, req.Name)
+		}
 		return ctrl.Result{}, k8sClient.IgnoreNotFound(err)
 	}
 
-	logger.Info("Found new Client Config", "name", clientConfig.Name, "namespace", clientConfig.Namespace)
+	logging.SuccessInfo(logger, req.String(), "Found new Client Config")
+	// Status changes are patched against the ClientConfig as read
+	original := clientConfig.DeepCopy()
 
-	// Handle finalizer lifecycle
-	//nolint:lll
-	isDeleting, err := utils.HandleFinalizer(ctx, r.Client, clientConfig, utils.FinalizerAnnotation, func(_ context.Context) error {
-		// Cleanup: remove client from cache
-		logger.Info("Removing client from cache", "name", clientConfig.Name, "namespace", clientConfig.Namespace)
-		r.RulerClients.RemoveClient(clientConfig.Name)
-		return nil
-	})
+	// Handle finalizer lifecycle, read-only controllers leave the finalizers to another controller
+	var err error
+	isDeleting := !clientConfig.DeletionTimestamp.IsZero()
+	if !r.ReadOnly {
+		//nolint:lll
+		isDeleting, err = utils.HandleFinalizer(ctx, r.Client, clientConfig, utils.FinalizerAnnotation, func(_ context.Context) error {
+			// Cleanup: remove client from cache
+			logger.Info("Removing client from cache")
+			r.RulerClients.RemoveClient(clientConfig.Namespace, clientConfig.Name)
+			return nil
+		})
+	}
 
 	if err != nil {
-		logger.Error(err, "Failed to handle finalizer", "name", clientConfig.Name, "namespace", clientConfig.Namespace)
+		logger.Error(err, "Failed to handle finalizer")
 		return ctrl.Result{}, err
 	}
 
@@ -66,16 +114,39 @@ func (r *ClientConfigReconciler) Reconcile(ctx context.Context, req ctrl.Request
 		return ctrl.Result{}, nil
 	}
 
+	if err := r.setDefaultConflictCondition(ctx, clientConfig); err != nil {
+		logger.Error(err, "Failed to check for conflicting default ClientConfigs")
+		return ctrl.Result{}, err
+	}
+	setInsecureTLSCondition(clientConfig)
+
+	// Paused ClientConfigs keep their cached client but do not contact the endpoint
+	if utils.IsPaused(clientConfig) {
+		logger.Info("ClientConfig is paused, skipping connection check")
+		utils.SetPausedCondition(&clientConfig.Status.Conditions, true, clientConfig.Generation)
+		clientConfig.Status.ObservedGeneration = clientConfig.Generation
+		if statusErr := utils.PatchStatus(ctx, r.Client, clientConfig, original); statusErr != nil {
+			logger.Error(statusErr, "Failed to update status")
+			return ctrl.Result{}, statusErr
+		}
+		return ctrl.Result{}, nil
+	}
+
 	// Normal reconciliation: resource is NOT being deleted
 	{
 		// Attempt to create and validate client connection
 		spec := clientConfig.Spec
 
+		var awarenessClient clients.AwarenessClient
 		switch spec.Type {
 		case openawarenessv1beta1.Mimir:
 			// Create client without tenant ID - tenant is passed per-request via namespace parameter
 			// in Mimir client methods (e.g., CreateRuleGroup, DeleteRuleGroup)
-			_, err = r.RulerClients.GetOrCreateMimirClient(ctx, spec.Address, clientConfig.Name)
+			awarenessClient, err = r.RulerClients.GetOrCreateMimirClient(ctx, clientConfig)
+			// A cached client is returned without check, the endpoint may have gone down since
+			if err == nil {
+				err = r.RulerClients.CheckHealth(ctx, clientConfig)
+			}
 		case openawarenessv1beta1.Prometheus:
 			// Prometheus client support - currently not implemented
 			err = r.RulerClients.AddPromClient(ctx, spec.Address, clientConfig.Name)
@@ -84,11 +155,9 @@ func (r *ClientConfigReconciler) Reconcile(ctx context.Context, req ctrl.Request
 		// Update status based on connection result
 		if err != nil {
 			logger.Error(err, "Failed to add client",
-				"name", clientConfig.Name,
-				"namespace", clientConfig.Namespace,
 				"type", spec.Type)
 			reason, message := utils.CategorizeError(err)
-			if statusErr := r.updateStatus(ctx, clientConfig,
+			if statusErr := r.updateStatus(ctx, clientConfig, original,
 				openawarenessv1beta1.ConnectionStatusDisconnected,
 				metav1.ConditionFalse,
 				reason,
@@ -101,13 +170,17 @@ func (r *ClientConfigReconciler) Reconcile(ctx context.Context, req ctrl.Request
 			return ctrl.Result{RequeueAfter: time.Minute * 1}, nil
 		}
 
-		logger.Info("Added new Client Config",
-			"name", clientConfig.Name,
-			"namespace", clientConfig.Namespace,
+		logging.SuccessInfo(logger, utils.OwnerReference(clientConfig), "Added new Client Config",
 			"type", spec.Type)
 
+		// The tenants are reported with the connection status, their listing does not affect readiness
+		r.setTenantsStatus(ctx, clientConfig, awarenessClient)
+		// The query path is reported separately from the ruler, it may be routed differently
+		setQueryReachableCondition(ctx, clientConfig, awarenessClient)
+		openUntil := setDegradedCondition(clientConfig, awarenessClient)
+
 		// Update status to connected
-		if statusErr := r.updateStatus(ctx, clientConfig,
+		if statusErr := r.updateStatus(ctx, clientConfig, original,
 			openawarenessv1beta1.ConnectionStatusConnected,
 			metav1.ConditionTrue,
 			openawarenessv1beta1.ReasonConnected,
@@ -116,23 +189,194 @@ func (r *ClientConfigReconciler) Reconcile(ctx context.Context, req ctrl.Request
 			logger.Error(statusErr, "Failed to update status")
 			return ctrl.Result{}, statusErr
 		}
+
+		// Requests are refused until the cool-down of the circuit breaker ends
+		if !openUntil.IsZero() {
+			return ctrl.Result{RequeueAfter: time.Until(openUntil)}, nil
+		}
+
+		// The state of the tenants is restored once the endpoint is reachable
+		if err := r.restoreBackup(ctx, clientConfig); err != nil {
+			return ctrl.Result{}, err
+		}
 	} // End of normal reconciliation scope
 
-	return ctrl.Result{}, nil
+	// The health is checked again periodically, the endpoint may go down without any change
+	return ctrl.Result{RequeueAfter: r.HealthCheckInterval}, nil
+}
+
+// restoreBackup pushes the backed up state of all tenants of the ClientConfig again if it
+// carries the restore-backup annotation, and removes the annotation once restored.
+// The outcome is reported as event. Returns the restore error for retry. Read-only
+// controllers never restore.
+func (r *ClientConfigReconciler) restoreBackup(ctx context.Context, clientConfig *openawarenessv1beta1.ClientConfig) error {
+	if r.Backup == nil || r.ReadOnly || clientConfig.Spec.Type != openawarenessv1beta1.Mimir ||
+		clientConfig.Annotations[utils.RestoreBackupAnnotation] != "true" {
+		return nil
+	}
+	logger := log.FromContext(ctx)
+
+	remote, err := r.RulerClients.GetOrCreateMimirClient(ctx, clientConfig)
+	if err != nil {
+		return err
+	}
+	summary, err := r.Backup.Restore(ctx, clientConfig, remote)
+	if err != nil {
+		logger.Error(err, "Failed to restore backup")
+		events.Event(r.Recorder, clientConfig, events.BackupRestoreFailed, err.Error())
+		return err
+	}
+
+	logger.Info("Restored backup",
+		"tenants", summary.Tenants,
+		"alertmanagerConfigs", summary.AlertmanagerConfigs,
+		"ruleGroups", summary.RuleGroups)
+	events.Eventf(r.Recorder, clientConfig, events.BackupRestored,
+		"Restored %d Alertmanager configurations and %d rule groups of %d tenants",
+		summary.AlertmanagerConfigs, summary.RuleGroups, summary.Tenants)
+
+	patch := k8sClient.MergeFrom(clientConfig.DeepCopy())
+	delete(clientConfig.Annotations, utils.RestoreBackupAnnotation)
+	return r.Patch(ctx, clientConfig, patch)
+}
+
+// setTenantsStatus lists the tenants of the Mimir instance through awarenessClient and sets
+// status.tenants and the TenantsListed condition if spec.listTenants is enabled, and removes
+// them otherwise. A failed listing keeps the last listed tenants.
+func (r *ClientConfigReconciler) setTenantsStatus(
+	ctx context.Context,
+	clientConfig *openawarenessv1beta1.ClientConfig,
+	awarenessClient clients.AwarenessClient,
+) {
+	if !clientConfig.Spec.ListTenants {
+		clientConfig.Status.Tenants = nil
+		meta.RemoveStatusCondition(&clientConfig.Status.Conditions, openawarenessv1beta1.ConditionTypeTenantsListed)
+		return
+	}
+	condition := metav1.Condition{
+		Type:               openawarenessv1beta1.ConditionTypeTenantsListed,
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: clientConfig.Generation,
+	}
+	defer func() { meta.SetStatusCondition(&clientConfig.Status.Conditions, condition) }()
+
+	lister, ok := awarenessClient.(clients.TenantLister)
+	if !ok {
+		condition.Reason = openawarenessv1beta1.ReasonTenantListingUnsupported
+		condition.Message = fmt.Sprintf("Clients of type %s cannot list tenants", clientConfig.Spec.Type)
+		return
+	}
+	tenants, err := lister.ListTenants(ctx)
+	if err != nil {
+		log.FromContext(ctx).Error(err, "Failed to list tenants")
+		condition.Reason, condition.Message = utils.CategorizeError(err)
+		return
+	}
+
+	now := metav1.Now()
+	clientConfig.Status.Tenants = &openawarenessv1beta1.TenantListStatus{
+		Count:        int32(len(tenants)),
+		Sample:       tenants[:min(len(tenants), tenantSampleSize)],
+		LastListTime: &now,
+	}
+	condition.Status = metav1.ConditionTrue
+	condition.Reason = openawarenessv1beta1.ReasonTenantsListed
+	condition.Message = fmt.Sprintf("Listed %d tenants through the admin API", len(tenants))
+}
+
+// setQueryReachableCondition runs an `up` query for the default tenant through awarenessClient
+// and sets the QueryReachable condition if spec.verifyQuery is enabled, and removes it otherwise.
+// The status is persisted by the caller.
+func setQueryReachableCondition(
+	ctx context.Context,
+	clientConfig *openawarenessv1beta1.ClientConfig,
+	awarenessClient clients.AwarenessClient,
+) {
+	if !clientConfig.Spec.VerifyQuery {
+		meta.RemoveStatusCondition(&clientConfig.Status.Conditions, openawarenessv1beta1.ConditionTypeQueryReachable)
+		return
+	}
+	condition := metav1.Condition{
+		Type:               openawarenessv1beta1.ConditionTypeQueryReachable,
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: clientConfig.Generation,
+	}
+	defer func() { meta.SetStatusCondition(&clientConfig.Status.Conditions, condition) }()
+
+	queryClient, ok := awarenessClient.(clients.QueryClient)
+	if !ok {
+		condition.Reason = openawarenessv1beta1.ReasonQueryUnsupported
+		condition.Message = fmt.Sprintf("Clients of type %s cannot run queries", clientConfig.Spec.Type)
+		return
+	}
+	tenantID := clientConfig.ResolveTenant("")
+	if tenantID == "" {
+		tenantID = utils.DefaultTenantID
+	}
+	res, err := queryClient.Query(ctx, "up", []string{tenantID})
+	if err != nil {
+		log.FromContext(ctx).Error(err, "Failed to query tenant",
+			logging.KeyTenant, tenantID)
+		condition.Reason, condition.Message = utils.CategorizeError(err)
+		return
+	}
+	_ = res.Body.Close()
+
+	condition.Status = metav1.ConditionTrue
+	condition.Reason = openawarenessv1beta1.ReasonQueryReachable
+	condition.Message = fmt.Sprintf("Queried tenant %s through the query API", tenantID)
+}
+
+// setDegradedCondition sets the Degraded condition from the circuit breaker of awarenessClient,
+// and removes it for clients without one. The status is persisted by the caller.
+// Returns the time until which the circuit breaker refuses requests, zero if it is closed.
+func setDegradedCondition(
+	clientConfig *openawarenessv1beta1.ClientConfig,
+	awarenessClient clients.AwarenessClient,
+) time.Time {
+	breaker, ok := awarenessClient.(clients.CircuitBreakerClient)
+	if !ok {
+		meta.RemoveStatusCondition(&clientConfig.Status.Conditions, openawarenessv1beta1.ConditionTypeDegraded)
+		return time.Time{}
+	}
+	condition := metav1.Condition{
+		Type:               openawarenessv1beta1.ConditionTypeDegraded,
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: clientConfig.Generation,
+		Reason:             openawarenessv1beta1.ReasonCircuitClosed,
+		Message:            "Requests are sent to the endpoint",
+	}
+	openUntil := breaker.CircuitOpenUntil()
+	if !openUntil.IsZero() {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = openawarenessv1beta1.ReasonCircuitOpen
+		condition.Message = fmt.Sprintf("Requests are paused until %s after consecutive server errors",
+			openUntil.UTC().Format(time.RFC3339))
+	}
+	meta.SetStatusCondition(&clientConfig.Status.Conditions, condition)
+	return openUntil
 }
 
 // updateStatus updates the ClientConfig status with the given connection state and condition.
 // It consolidates all status update logic into a single method to reduce code duplication
 // and ensure consistent status handling across all reconciliation paths.
+// The status is only written if it changed, so periodic health checks of an unchanged
+// endpoint do not trigger the controllers watching ClientConfigs.
 func (r *ClientConfigReconciler) updateStatus(ctx context.Context,
-	clientConfig *openawarenessv1beta1.ClientConfig,
+	clientConfig, original *openawarenessv1beta1.ClientConfig,
 	connectionStatus openawarenessv1beta1.ConnectionStatus,
 	conditionStatus metav1.ConditionStatus,
 	reason, message string,
 	err error) error {
 
-	now := metav1.Now()
+	// The connection time is kept while the connection stays established
+	if connectionStatus == openawarenessv1beta1.ConnectionStatusConnected &&
+		(original.Status.ConnectionStatus != connectionStatus || clientConfig.Status.LastConnectionTime == nil) {
+		now := metav1.Now()
+		clientConfig.Status.LastConnectionTime = &now
+	}
 
+	clientConfig.Status.ObservedGeneration = clientConfig.Generation
 	clientConfig.Status.ConnectionStatus = connectionStatus
 	if err != nil {
 		clientConfig.Status.ErrorMessage = err.Error()
@@ -140,27 +384,185 @@ func (r *ClientConfigReconciler) updateStatus(ctx context.Context,
 		clientConfig.Status.ErrorMessage = ""
 	}
 
-	if connectionStatus == openawarenessv1beta1.ConnectionStatusConnected {
-		clientConfig.Status.LastConnectionTime = &now
-	}
-
 	condition := metav1.Condition{
 		Type:               openawarenessv1beta1.ConditionTypeReady,
 		Status:             conditionStatus,
 		ObservedGeneration: clientConfig.Generation,
-		LastTransitionTime: now,
 		Reason:             reason,
 		Message:            message,
 	}
 
 	utils.SetCondition(&clientConfig.Status.Conditions, condition)
+	// The connectivity conditions show the layer a failed connection broke at
+	utils.SetConnectivityConditions(&clientConfig.Status.Conditions, err, clientConfig.Spec.Address, clientConfig.Generation)
+	utils.SetPausedCondition(&clientConfig.Status.Conditions, false, clientConfig.Generation)
+
+	if equality.Semantic.DeepEqual(clientConfig.Status, original.Status) {
+		return nil
+	}
+	return utils.PatchStatus(ctx, r.Client, clientConfig, original)
+}
+
+// setDefaultConflictCondition sets the DefaultConflict condition of a default ClientConfig,
+// which is true if other ClientConfigs are the default for the same scope, and removes it
+// from ClientConfigs that are not a default. The status is persisted by the caller.
+func (r *ClientConfigReconciler) setDefaultConflictCondition(
+	ctx context.Context,
+	clientConfig *openawarenessv1beta1.ClientConfig,
+) error {
+	if !clientConfig.Spec.Default {
+		meta.RemoveStatusCondition(&clientConfig.Status.Conditions, openawarenessv1beta1.ConditionTypeDefaultConflict)
+		return nil
+	}
+
+	clientConfigs := &openawarenessv1beta1.ClientConfigList{}
+	if err := r.List(ctx, clientConfigs); err != nil {
+		return err
+	}
+
+	condition := metav1.Condition{
+		Type:               openawarenessv1beta1.ConditionTypeDefaultConflict,
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: clientConfig.Generation,
+		Reason:             openawarenessv1beta1.ReasonUniqueDefault,
+		Message:            fmt.Sprintf("Default ClientConfig for scope %s", clientConfig.EffectiveDefaultScope()),
+	}
+	if conflicts := utils.ConflictingDefaults(clientConfig, clientConfigs.Items); len(conflicts) > 0 {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = openawarenessv1beta1.ReasonDefaultConflict
+		condition.Message = fmt.Sprintf("Also default for scope %s: %s; resources without %s annotation are not synced",
+			clientConfig.EffectiveDefaultScope(), strings.Join(conflicts, ", "), utils.ClientNameAnnotation)
+	}
+	meta.SetStatusCondition(&clientConfig.Status.Conditions, condition)
+	return nil
+}
 
-	return r.Status().Update(ctx, clientConfig)
+// setInsecureTLSCondition sets the InsecureTLS condition while the verification of the server
+// certificate is disabled, and removes it otherwise. The status is persisted by the caller.
+func setInsecureTLSCondition(clientConfig *openawarenessv1beta1.ClientConfig) {
+	if clientConfig.Spec.TLS == nil || !clientConfig.Spec.TLS.InsecureSkipVerify {
+		meta.RemoveStatusCondition(&clientConfig.Status.Conditions, openawarenessv1beta1.ConditionTypeInsecureTLS)
+		return
+	}
+	meta.SetStatusCondition(&clientConfig.Status.Conditions, metav1.Condition{
+		Type:               openawarenessv1beta1.ConditionTypeInsecureTLS,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: clientConfig.Generation,
+		Reason:             openawarenessv1beta1.ReasonInsecureSkipVerify,
+		Message:            "The server certificate is not verified, only use insecureSkipVerify in lab environments",
+	})
 }
 
 // SetupWithManager sets up the controller with the Manager.
+// Changes of a default ClientConfig re-queue the other defaults to update their conflict condition.
+// Changes of a CA ConfigMap or HMAC key Secret re-queue the ClientConfigs referencing it, so
+// their client is created again with the new CA bundle or key. ClientConfigs sent to CircuitBreakerChanged are re-queued
+// to update their Degraded condition.
 func (r *ClientConfigReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
+	if err := mgr.GetFieldIndexer().IndexField(
+		context.Background(),
+		&openawarenessv1beta1.ClientConfig{},
+		utils.CAConfigMapIndexKey,
+		utils.CAConfigMapIndexer,
+	); err != nil {
+		return fmt.Errorf("indexing ClientConfigs by CA ConfigMap: %w", err)
+	}
+	if err := mgr.GetFieldIndexer().IndexField(
+		context.Background(),
+		&openawarenessv1beta1.ClientConfig{},
+		utils.HMACSecretIndexKey,
+		utils.HMACSecretIndexer,
+	); err != nil {
+		return fmt.Errorf("indexing ClientConfigs by HMAC key Secret: %w", err)
+	}
+
+	blder := ctrl.NewControllerManagedBy(mgr).
 		For(&openawarenessv1beta1.ClientConfig{}).
-		Complete(r)
+		Watches(
+			&openawarenessv1beta1.ClientConfig{},
+			handler.EnqueueRequestsFromMapFunc(r.findOtherDefaults),
+		).
+		// Only the metadata of ConfigMaps and Secrets is cached, their data is read by the client cache
+		Watches(
+			&corev1.ConfigMap{},
+			handler.EnqueueRequestsFromMapFunc(r.findClientConfigsForCA),
+			builder.OnlyMetadata,
+		).
+		Watches(
+			&corev1.Secret{},
+			handler.EnqueueRequestsFromMapFunc(r.findClientConfigsForHMACKey),
+			builder.OnlyMetadata,
+		)
+	if r.CircuitBreakerChanged != nil {
+		blder = blder.WatchesRawSource(source.Channel(r.CircuitBreakerChanged, &handler.EnqueueRequestForObject{}))
+	}
+	return blder.
+		WithOptions(controller.Options{
+			MaxConcurrentReconciles: r.MaxConcurrentReconciles,
+			LogConstructor:          logging.LogConstructor(mgr.GetLogger(), "clientconfig"),
+		}).
+		Complete(logging.TrackAttempts(r))
+}
+
+// findOtherDefaults maps a change of a default ClientConfig to the other default ClientConfigs.
+func (r *ClientConfigReconciler) findOtherDefaults(ctx context.Context, obj k8sClient.Object) []reconcile.Request {
+	clientConfig, ok := obj.(*openawarenessv1beta1.ClientConfig)
+	if !ok || !clientConfig.Spec.Default {
+		return nil
+	}
+
+	clientConfigs := &openawarenessv1beta1.ClientConfigList{}
+	if err := r.List(ctx, clientConfigs); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to list ClientConfigs for default conflict detection")
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, other := range clientConfigs.Items {
+		if !other.Spec.Default || (other.Namespace == clientConfig.Namespace && other.Name == clientConfig.Name) {
+			continue
+		}
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Name: other.Name, Namespace: other.Namespace},
+		})
+	}
+	return requests
+}
+
+// findClientConfigsForCA maps a change of a ConfigMap to the ClientConfigs in its namespace
+// reading their CA bundle from it.
+func (r *ClientConfigReconciler) findClientConfigsForCA(ctx context.Context, obj k8sClient.Object) []reconcile.Request {
+	return r.findClientConfigsBy(ctx, obj, utils.CAConfigMapIndexKey)
+}
+
+// findClientConfigsForHMACKey maps a change of a Secret to the ClientConfigs in its namespace
+// reading their HMAC signing key from it.
+func (r *ClientConfigReconciler) findClientConfigsForHMACKey(ctx context.Context, obj k8sClient.Object) []reconcile.Request {
+	return r.findClientConfigsBy(ctx, obj, utils.HMACSecretIndexKey)
+}
+
+// findClientConfigsBy maps a change of obj to the ClientConfigs in its namespace referencing
+// it by the field index indexKey.
+func (r *ClientConfigReconciler) findClientConfigsBy(
+	ctx context.Context,
+	obj k8sClient.Object,
+	indexKey string,
+) []reconcile.Request {
+	clientConfigs := &openawarenessv1beta1.ClientConfigList{}
+	if err := r.List(ctx, clientConfigs,
+		k8sClient.InNamespace(obj.GetNamespace()),
+		k8sClient.MatchingFields{indexKey: obj.GetName()},
+	); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to list ClientConfigs referencing object",
+			"name", obj.GetName(), "index", indexKey)
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(clientConfigs.Items))
+	for _, clientConfig := range clientConfigs.Items {
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Name: clientConfig.Name, Namespace: clientConfig.Namespace},
+		})
+	}
+	return requests
 }
@@ -2,14 +2,18 @@ package openawareness
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/syndlex/openawareness-controller/internal/clients"
 	"github.com/syndlex/openawareness-controller/internal/controller/utils"
 	"github.com/syndlex/openawareness-controller/test/helper"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	openawarenessv1beta1 "github.com/syndlex/openawareness-controller/api/openawareness/v1beta1"
 )
@@ -89,6 +93,119 @@ var _ = Describe("ClientConfig Controller", func() {
 			})
 		})
 
+		Context("When creating a ClientConfig listing tenants", func() {
+			It("should report the listed tenants in status", func() {
+				By("Creating a ClientConfig with listTenants enabled")
+				clientConfig := &openawarenessv1beta1.ClientConfig{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      ClientConfigName,
+						Namespace: ClientConfigNamespace,
+					},
+					Spec: openawarenessv1beta1.ClientConfigSpec{
+						Address:     "http://localhost:9009",
+						Type:        openawarenessv1beta1.Mimir,
+						ListTenants: true,
+					},
+				}
+				Expect(testClient.Create(ctx, clientConfig)).To(Succeed())
+
+				By("Checking the TenantsListed condition and tenant summary")
+				Eventually(func() *metav1.Condition {
+					if err := testClient.Get(ctx, typeNamespacedName, clientConfig); err != nil {
+						return nil
+					}
+					return meta.FindStatusCondition(clientConfig.Status.Conditions,
+						openawarenessv1beta1.ConditionTypeTenantsListed)
+				}, timeout, interval).Should(And(
+					HaveField("Status", metav1.ConditionTrue),
+					HaveField("Reason", openawarenessv1beta1.ReasonTenantsListed),
+				))
+				Expect(clientConfig.Status.Tenants).NotTo(BeNil())
+				Expect(clientConfig.Status.Tenants.LastListTime).NotTo(BeNil())
+
+				By("Disabling listTenants")
+				clientConfig.Spec.ListTenants = false
+				Expect(testClient.Update(ctx, clientConfig)).To(Succeed())
+				Eventually(func() bool {
+					if err := testClient.Get(ctx, typeNamespacedName, clientConfig); err != nil {
+						return false
+					}
+					return clientConfig.Status.Tenants == nil && meta.FindStatusCondition(clientConfig.Status.Conditions,
+						openawarenessv1beta1.ConditionTypeTenantsListed) == nil
+				}, timeout, interval).Should(BeTrue())
+			})
+		})
+
+		Context("When creating a ClientConfig verifying the query path", func() {
+			It("should report the QueryReachable condition while enabled", func() {
+				By("Creating a ClientConfig with verifyQuery enabled")
+				clientConfig := &openawarenessv1beta1.ClientConfig{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      ClientConfigName,
+						Namespace: ClientConfigNamespace,
+					},
+					Spec: openawarenessv1beta1.ClientConfigSpec{
+						Address:     "http://localhost:9009",
+						Type:        openawarenessv1beta1.Mimir,
+						VerifyQuery: true,
+					},
+				}
+				Expect(testClient.Create(ctx, clientConfig)).To(Succeed())
+
+				By("Checking the mock client is reported as unable to query")
+				Eventually(func() *metav1.Condition {
+					if err := testClient.Get(ctx, typeNamespacedName, clientConfig); err != nil {
+						return nil
+					}
+					return meta.FindStatusCondition(clientConfig.Status.Conditions,
+						openawarenessv1beta1.ConditionTypeQueryReachable)
+				}, timeout, interval).Should(And(
+					HaveField("Status", metav1.ConditionFalse),
+					HaveField("Reason", openawarenessv1beta1.ReasonQueryUnsupported),
+				))
+				Expect(meta.IsStatusConditionTrue(clientConfig.Status.Conditions,
+					openawarenessv1beta1.ConditionTypeReady)).To(BeTrue())
+
+				By("Disabling verifyQuery")
+				clientConfig.Spec.VerifyQuery = false
+				Expect(testClient.Update(ctx, clientConfig)).To(Succeed())
+				Eventually(func() bool {
+					if err := testClient.Get(ctx, typeNamespacedName, clientConfig); err != nil {
+						return false
+					}
+					return meta.FindStatusCondition(clientConfig.Status.Conditions,
+						openawarenessv1beta1.ConditionTypeQueryReachable) == nil
+				}, timeout, interval).Should(BeTrue())
+			})
+		})
+
+		Context("When the circuit breaker of the client opens", func() {
+			It("should report the Degraded condition until the cool-down ends", func() {
+				clientConfig := &openawarenessv1beta1.ClientConfig{
+					ObjectMeta: metav1.ObjectMeta{Name: ClientConfigName, Namespace: ClientConfigNamespace},
+				}
+				mockClient := clients.NewMockAwarenessClient()
+
+				By("Checking a closed circuit breaker")
+				Expect(setDegradedCondition(clientConfig, mockClient).IsZero()).To(BeTrue())
+				Expect(meta.FindStatusCondition(clientConfig.Status.Conditions,
+					openawarenessv1beta1.ConditionTypeDegraded)).To(And(
+					HaveField("Status", metav1.ConditionFalse),
+					HaveField("Reason", openawarenessv1beta1.ReasonCircuitClosed),
+				))
+
+				By("Opening the circuit breaker")
+				openUntil := time.Now().Add(time.Minute)
+				mockClient.SetCircuitOpenUntil(openUntil)
+				Expect(setDegradedCondition(clientConfig, mockClient)).To(Equal(openUntil))
+				Expect(meta.FindStatusCondition(clientConfig.Status.Conditions,
+					openawarenessv1beta1.ConditionTypeDegraded)).To(And(
+					HaveField("Status", metav1.ConditionTrue),
+					HaveField("Reason", openawarenessv1beta1.ReasonCircuitOpen),
+				))
+			})
+		})
+
 		Context("When creating a ClientConfig with invalid URL", func() {
 			It("should update status with error condition", func() {
 				By("Creating a ClientConfig with invalid address")
@@ -118,6 +235,7 @@ var _ = Describe("ClientConfig Controller", func() {
 
 				By("Verifying ConnectionStatus is Disconnected")
 				Expect(clientConfig.Status.ConnectionStatus).To(Equal(openawarenessv1beta1.ConnectionStatusDisconnected))
+				Expect(clientConfig.Status.ObservedGeneration).To(Equal(clientConfig.Generation))
 
 				By("Verifying error condition exists")
 				conditions := clientConfig.Status.Conditions
@@ -127,6 +245,15 @@ var _ = Describe("ClientConfig Controller", func() {
 				Expect(readyCondition).NotTo(BeNil())
 				Expect(readyCondition.Status).To(Equal(metav1.ConditionFalse))
 				Expect(readyCondition.Reason).To(Equal(openawarenessv1beta1.ReasonInvalidURL))
+
+				By("Verifying the connectivity conditions name the invalid URL")
+				urlCondition := helper.FindCondition(conditions, openawarenessv1beta1.ConditionTypeURLValid)
+				Expect(urlCondition).NotTo(BeNil())
+				Expect(urlCondition.Status).To(Equal(metav1.ConditionFalse))
+				Expect(urlCondition.Reason).To(Equal(openawarenessv1beta1.ReasonInvalidURL))
+				apiCondition := helper.FindCondition(conditions, openawarenessv1beta1.ConditionTypeAPIReachable)
+				Expect(apiCondition).NotTo(BeNil())
+				Expect(apiCondition.Status).To(Equal(metav1.ConditionUnknown))
 			})
 		})
 
@@ -162,6 +289,48 @@ var _ = Describe("ClientConfig Controller", func() {
 
 				By("Verifying error message contains network error details")
 				Expect(clientConfig.Status.ErrorMessage).NotTo(BeEmpty())
+
+				By("Verifying the connectivity conditions name the unresolvable host")
+				Expect(meta.FindStatusCondition(clientConfig.Status.Conditions,
+					openawarenessv1beta1.ConditionTypeDNSResolvable)).To(And(
+					HaveField("Status", metav1.ConditionFalse),
+					HaveField("Reason", openawarenessv1beta1.ReasonDNSResolutionError),
+				))
+				Expect(meta.IsStatusConditionTrue(clientConfig.Status.Conditions,
+					openawarenessv1beta1.ConditionTypeURLValid)).To(BeTrue())
+			})
+		})
+
+		Context("When checking the health of a cached client", func() {
+			It("should check the endpoint on every reconcile and requeue", func() {
+				clientConfig := &openawarenessv1beta1.ClientConfig{
+					ObjectMeta: metav1.ObjectMeta{Name: ClientConfigName, Namespace: ClientConfigNamespace},
+					Spec: openawarenessv1beta1.ClientConfigSpec{
+						Address: "http://localhost:9009",
+						Type:    openawarenessv1beta1.Mimir,
+					},
+				}
+				Expect(testClient.Create(ctx, clientConfig)).To(Succeed())
+
+				cache := clients.NewMockRulerClientCache()
+				reconciler := &ClientConfigReconciler{
+					Client:              testClient,
+					RulerClients:        cache,
+					Scheme:              testClient.Scheme(),
+					HealthCheckInterval: 5 * time.Minute,
+				}
+				request := reconcile.Request{NamespacedName: typeNamespacedName}
+
+				By("Requeueing a healthy ClientConfig after the health check interval")
+				result, err := reconciler.Reconcile(ctx, request)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result.RequeueAfter).To(Equal(5 * time.Minute))
+
+				By("Failing the health check of the cached client")
+				cache.SetHealthCheckError(errors.New("mimir went down"))
+				result, err = reconciler.Reconcile(ctx, request)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result.RequeueAfter).To(Equal(time.Minute))
 			})
 		})
 
@@ -199,5 +368,54 @@ var _ = Describe("ClientConfig Controller", func() {
 				}, timeout, interval).Should(BeTrue())
 			})
 		})
+
+		Context("When two ClientConfigs are the default of a namespace", func() {
+			It("should report the conflict until one of them is removed", func() {
+				newDefault := func(name string) *openawarenessv1beta1.ClientConfig {
+					return &openawarenessv1beta1.ClientConfig{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      name,
+							Namespace: ClientConfigNamespace,
+						},
+						Spec: openawarenessv1beta1.ClientConfigSpec{
+							Address: "http://localhost:9009",
+							Type:    openawarenessv1beta1.Mimir,
+							Default: true,
+						},
+					}
+				}
+				conflictCondition := func(name string) func() *metav1.Condition {
+					return func() *metav1.Condition {
+						clientConfig := &openawarenessv1beta1.ClientConfig{}
+						key := types.NamespacedName{Name: name, Namespace: ClientConfigNamespace}
+						if err := testClient.Get(ctx, key, clientConfig); err != nil {
+							return nil
+						}
+						return meta.FindStatusCondition(clientConfig.Status.Conditions,
+							openawarenessv1beta1.ConditionTypeDefaultConflict)
+					}
+				}
+
+				By("Creating a first default ClientConfig")
+				Expect(testClient.Create(ctx, newDefault(ClientConfigName))).To(Succeed())
+				Eventually(conflictCondition(ClientConfigName), timeout, interval).Should(
+					HaveField("Reason", openawarenessv1beta1.ReasonUniqueDefault))
+
+				By("Creating a second default ClientConfig in the same namespace")
+				second := newDefault(ClientConfigName + "-second")
+				Expect(testClient.Create(ctx, second)).To(Succeed())
+				Eventually(conflictCondition(ClientConfigName), timeout, interval).Should(
+					HaveField("Status", metav1.ConditionTrue))
+				Eventually(conflictCondition(second.Name), timeout, interval).Should(And(
+					HaveField("Status", metav1.ConditionTrue),
+					HaveField("Message", ContainSubstring(ClientConfigNamespace+"/"+ClientConfigName)),
+				))
+
+				By("Deleting the second default ClientConfig")
+				Expect(testClient.Delete(ctx, second)).To(Succeed())
+				Eventually(conflictCondition(ClientConfigName), timeout, interval).Should(
+					HaveField("Status", metav1.ConditionFalse))
+			})
+		})
 	})
 })
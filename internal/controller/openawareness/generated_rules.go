@@ -0,0 +1,49 @@
+package openawareness
+
+import (
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	openawarenessv1beta1 "github.com/syndlex/openawareness-controller/api/openawareness/v1beta1"
+	"github.com/syndlex/openawareness-controller/internal/controller/utils"
+)
+
+// propagatedAnnotations are copied from a resource to the PrometheusRule generated from it
+// so the PrometheusRule controller syncs the rules to the same client and tenant.
+var propagatedAnnotations = []string{
+	utils.ClientNameAnnotation,
+	utils.MimirTenantAnnotation,
+	utils.PausedAnnotation,
+	utils.SyncTimeoutAnnotation,
+}
+
+// syncAnnotations copies the propagated annotations of the source to the PrometheusRule,
+// removing those no longer set on the source.
+func syncAnnotations(source metav1.Object, rule *monitoringv1.PrometheusRule) {
+	if rule.Annotations == nil {
+		rule.Annotations = map[string]string{}
+	}
+	for _, key := range propagatedAnnotations {
+		if value, ok := source.GetAnnotations()[key]; ok {
+			rule.Annotations[key] = value
+		} else {
+			delete(rule.Annotations, key)
+		}
+	}
+}
+
+// setReadyCondition sets the Ready condition for the given generation.
+func setReadyCondition(
+	conditions *[]metav1.Condition,
+	generation int64,
+	status metav1.ConditionStatus,
+	reason, message string,
+) {
+	utils.SetCondition(conditions, metav1.Condition{
+		Type:               openawarenessv1beta1.ConditionTypeReady,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: generation,
+	})
+}
@@ -2,17 +2,37 @@ package openawareness
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"maps"
+	"strings"
+	"time"
 
 	"github.com/go-logr/logr"
+	"github.com/syndlex/openawareness-controller/internal/backup"
 	"github.com/syndlex/openawareness-controller/internal/clients"
 	"github.com/syndlex/openawareness-controller/internal/controller/utils"
-	corev1 "k8s.io/api/core/v1"
+	"github.com/syndlex/openawareness-controller/internal/events"
+	"github.com/syndlex/openawareness-controller/internal/logging"
+	"github.com/syndlex/openawareness-controller/internal/metrics"
+	"github.com/syndlex/openawareness-controller/internal/mimir"
+	"github.com/syndlex/openawareness-controller/internal/operatorconfig"
+	"github.com/syndlex/openawareness-controller/internal/policy"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	k8sClient "sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/cluster"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	openawarenessv1beta1 "github.com/syndlex/openawareness-controller/api/openawareness/v1beta1"
 )
@@ -22,12 +42,72 @@ type MimirAlertTenantReconciler struct {
 	k8sClient.Client
 	RulerClients clients.RulerClientCacheInterface
 	Scheme       *runtime.Scheme
+	// Recorder emits events on MimirAlertTenants, e.g. the diff of a changed configuration.
+	// Events are not emitted if nil.
+	Recorder record.EventRecorder
+	// GlobalValues provides controller-wide template values, nil if not configured
+	GlobalValues *utils.GlobalValues
+	// ClusterName identifies the cluster in templates as [[ .Meta.Cluster ]]
+	ClusterName string
+	// Identity names this installation in the managed-by header of pushed configurations,
+	// see utils.InstanceIdentity
+	Identity string
+	// AlertmanagerPolicy checks rendered configurations, nil if not configured
+	AlertmanagerPolicy *policy.AlertmanagerPolicy
+	// Quota limits the number of MimirAlertTenants per namespace and Mimir tenant and the size
+	// of their configurations, nothing is limited if nil
+	Quota *policy.TenantQuota
+	// DefaultReceiver makes sure rendered configurations have a default receiver, configurations
+	// are not checked if nil
+	DefaultReceiver *utils.DefaultReceiver
+	// SyncTimeout bounds the Mimir API operations of a reconciliation unless the tenant
+	// sets spec.syncTimeout, zero disables the timeout
+	SyncTimeout time.Duration
+	// ShutdownGrace is the time a reconciliation in flight may finish after the manager stops,
+	// interrupted tenants are retried first on start, see utils.SyncReconciler
+	ShutdownGrace time.Duration
+	// ResyncPacer spreads the re-push of tenants synced by another controller version,
+	// tenants are not stamped with the version if nil
+	ResyncPacer *utils.ResyncPacer
+	// ResourceCluster is the hub cluster MimirAlertTenants are read from, the manager's
+	// cluster if nil. Client must operate on the same cluster, see utils.NewHubClient.
+	ResourceCluster cluster.Cluster
+	// MaxConcurrentReconciles is the number of MimirAlertTenants reconciled in parallel, 1 if zero
+	MaxConcurrentReconciles int
+	// Backup keeps the last pushed configuration of every tenant, no backup is kept if nil
+	Backup *backup.Store
+	// TemplateSources fetches the templateSources of tenants, tenants using one fail to render if nil
+	TemplateSources utils.TemplateSourceFetcher
+	// TemplateSourceRefreshInterval is the time after which tenants using a templateSource are
+	// synced again to pick up changed template files, they are not if zero
+	TemplateSourceRefreshInterval time.Duration
+	// DestructiveCleanup deletes the Alertmanager configuration of deleted tenants from Mimir
+	// without the ConfirmDeleteAnnotation, see mimirAlertTenantSync.Delete
+	DestructiveCleanup bool
+	// OperatorConfig overrides AlertmanagerPolicy, SyncTimeout and the retry and resync of
+	// tenants at runtime, they are used as configured if nil
+	OperatorConfig *operatorconfig.Source
+	// ReadOnly diffs the configurations against Mimir instead of pushing them and adds no
+	// finalizers, see utils.SyncReconciler
+	ReadOnly bool
 }
 
 //nolint:lll
 // +kubebuilder:rbac:groups=openawareness.syndlex,resources=mimiralerttenants,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=openawareness.syndlex,resources=mimiralerttenants/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=openawareness.syndlex,resources=mimiralerttenants/finalizers,verbs=update
+// +kubebuilder:rbac:groups=openawareness.syndlex,resources=mimiralertroutes,verbs=get;list;watch
+// +kubebuilder:rbac:groups=openawareness.syndlex,resources=mimiralertroutes/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=openawareness.syndlex,resources=mimiralertglobals,verbs=get;list;watch
+// +kubebuilder:rbac:groups=openawareness.syndlex,resources=mimiralertglobals/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=openawareness.syndlex,resources=mimirmutetimings,verbs=get;list;watch
+// +kubebuilder:rbac:groups=openawareness.syndlex,resources=mimirmutetimings/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=openawareness.syndlex,resources=mimirinhibitrules,verbs=get;list;watch
+// +kubebuilder:rbac:groups=openawareness.syndlex,resources=mimirinhibitrules/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=openawareness.syndlex,resources=clientconfigs,verbs=get;list;watch
+// +kubebuilder:rbac:groups=openawareness.syndlex,resources=tenantmappings,verbs=get;list;watch
+// +kubebuilder:rbac:groups=openawareness.syndlex,resources=operatorconfigs,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 // +kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch
 // +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch
 
@@ -35,317 +115,1249 @@ type MimirAlertTenantReconciler struct {
 // to the configured Mimir instance. It handles the full lifecycle including creation,
 // updates, and deletion of Alertmanager configurations with proper finalizer management.
 //
-// The reconciliation process:
+// The reconciliation follows utils.SyncReconciler with mimirAlertTenantSync as adapter:
 // 1. Fetches the MimirAlertTenant resource
 // 2. Adds finalizer for cleanup on deletion
-// 3. Retrieves the Mimir client from annotations
-// 4. Validates the Alertmanager configuration
-// 5. Pushes configuration to Mimir API
-// 6. Updates status to reflect sync state
-// 7. On deletion, removes configuration from Mimir and cleans up finalizer
+// 3. Renders the composed configuration and merges the routes, globals, mute timings and inhibition rules contributed to it
+// 4. Validates the Alertmanager configuration and checks the Alertmanager policy
+// 5. Retrieves the Mimir client from annotations
+// 6. Pushes configuration to Mimir API
+// 7. Updates status to reflect sync state
+// 8. On deletion, removes configuration from Mimir if confirmed and cleans up finalizer
 //
 // For more details, check Reconcile and its Result here:
 // - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.19.0/pkg/reconcile
 func (r *MimirAlertTenantReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	settings := r.OperatorConfig.Apply(ctx, operatorconfig.Settings{
+		SyncTimeout:        r.SyncTimeout,
+		AlertmanagerPolicy: r.AlertmanagerPolicy,
+	})
+	reconciler := &utils.SyncReconciler[*openawarenessv1beta1.MimirAlertTenant, renderedAlertmanagerConfig]{
+		Client:         r.Client,
+		Adapter:        &mimirAlertTenantSync{r: r, settings: settings},
+		Kind:           metrics.KindMimirAlertTenant,
+		Finalizer:      utils.FinalizerAnnotation,
+		Pacer:          r.ResyncPacer,
+		ResyncInterval: settings.ResyncInterval,
+		ShutdownGrace:  r.ShutdownGrace,
+		ReadOnly:       r.ReadOnly,
+	}
+	return reconciler.Reconcile(ctx, req)
+}
+
+const (
+	// configDiffEventLength bounds the configuration diff in ConfigurationChanged events
+	configDiffEventLength = 1024
+	// configDiffStatusLength bounds the configuration diff in status.lastConfigDiff
+	configDiffStatusLength = 4096
+)
+
+// errPolicyBlocked reports a configuration not pushed because of blocking policy violations.
+var errPolicyBlocked = errors.New("configuration violates the Alertmanager policy")
+
+// errFallbackConfig reports a pushed configuration that Mimir treats as blank, so it serves the
+// fallback configuration to the tenant.
+var errFallbackConfig = errors.New("mimir serves the fallback Alertmanager configuration")
+
+// renderedAlertmanagerConfig is the payload pushed for a MimirAlertTenant.
+type renderedAlertmanagerConfig struct {
+	config    string
+	templates map[string]string
+	// fetched is set if template files were fetched from a templateSource
+	fetched bool
+}
+
+// mimirAlertTenantSync adapts MimirAlertTenants to utils.SyncReconciler. Render and validation
+// failures set the condition describing them, Report writes the status.
+type mimirAlertTenantSync struct {
+	r *MimirAlertTenantReconciler
+	// settings are the settings of the reconciler with the OperatorConfig applied
+	settings operatorconfig.Settings
+}
+
+// NewObject returns an empty MimirAlertTenant.
+func (s *mimirAlertTenantSync) NewObject() *openawarenessv1beta1.MimirAlertTenant {
+	return &openawarenessv1beta1.MimirAlertTenant{}
+}
+
+// SyncTimeout returns the timeout of the Mimir API operations for the tenant.
+func (s *mimirAlertTenantSync) SyncTimeout(tenant *openawarenessv1beta1.MimirAlertTenant) time.Duration {
+	if tenant.Spec.SyncTimeout != nil {
+		return tenant.Spec.SyncTimeout.Duration
+	}
+	return s.settings.SyncTimeout
+}
+
+// Resolve returns the Mimir client of the tenant, see clientFromCrd.
+func (s *mimirAlertTenantSync) Resolve(
+	ctx context.Context,
+	state *utils.SyncState[*openawarenessv1beta1.MimirAlertTenant],
+) (clients.AwarenessClient, error) {
+	return s.r.clientFromCrd(ctx, log.FromContext(ctx), state)
+}
+
+// Render composes the configuration of the tenant and the tenants it extends, renders it with
+// the template data and merges the MimirAlertRoutes contributed to it.
+func (s *mimirAlertTenantSync) Render(
+	ctx context.Context,
+	state *utils.SyncState[*openawarenessv1beta1.MimirAlertTenant],
+) (renderedAlertmanagerConfig, error) {
 	logger := log.FromContext(ctx)
+	rule := state.Object
 
-	rule := &openawarenessv1beta1.MimirAlertTenant{}
-	if err := r.Get(ctx, req.NamespacedName, rule); err != nil {
-		return ctrl.Result{}, k8sClient.IgnoreNotFound(err)
+	// Global values are injected into every render as [[ .Global.NAME ]], resource
+	// metadata as [[ .Meta.FIELD ]]
+	globals, err := s.r.GlobalValues.Get(ctx)
+	if err != nil {
+		logger.Error(err, "Failed to get global template values")
+		rule.SetConfigInvalidCondition(openawarenessv1beta1.ReasonTemplateDataNotFound, err.Error())
+		return renderedAlertmanagerConfig{}, err
 	}
-	logger.Info("Found MimirAlertTenant", "name", rule.Name, "namespace", rule.Namespace)
 
-	if rule.DeletionTimestamp.IsZero() {
-		// Register finalizer first, before checking for client
-		if !controllerutil.ContainsFinalizer(rule, utils.FinalizerAnnotation) {
-			controllerutil.AddFinalizer(rule, utils.FinalizerAnnotation)
-			if err := r.Update(ctx, rule); err != nil {
-				return ctrl.Result{}, err
-			}
+	// Extended tenants are composed into the configuration of this tenant
+	chain, err := utils.ResolveExtends(ctx, s.r.Client, rule)
+	if err != nil {
+		logger.Error(err, "Failed to resolve extended MimirAlertTenants")
+		reason := openawarenessv1beta1.ReasonBaseNotFound
+		if errors.Is(err, utils.ErrExtendsCycle) {
+			reason = openawarenessv1beta1.ReasonExtendsCycle
 		}
+		rule.SetCompositionFailedCondition(reason, err.Error())
+		return renderedAlertmanagerConfig{}, err
+	}
+	rule.SetComposedCondition(utils.BaseNames(chain))
 
-		// Get the alertmanager client
-		alertManagerClient, err := r.clientFromCrd(ctx, logger, rule)
-		if err != nil {
-			logger.Error(err, "Failed to get Alertmanager client",
-				"name", rule.Name,
-				"namespace", rule.Namespace)
-			// Return error to trigger retry
-			return ctrl.Result{}, err
-		}
+	// Configurations kept in Secrets are read before rendering
+	chain, err = utils.ResolveConfigSources(ctx, s.r.Client, chain)
+	if err != nil {
+		logger.Error(err, "Failed to read alertmanagerConfigFrom")
+		rule.SetConfigInvalidCondition(openawarenessv1beta1.ReasonConfigSecretNotFound, err.Error())
+		return renderedAlertmanagerConfig{}, err
+	}
 
-		// Template rendering must happen BEFORE validation
-		// Get template data and render config if references are provided
-		var renderedConfig string
-		if len(rule.Spec.SecretDataReferences) > 0 {
-			templateData, err := r.getSecretData(ctx, logger, rule)
-			if err != nil {
-				logger.Error(err, "Failed to get template data",
-					"name", rule.Name,
-					"namespace", rule.Namespace)
-				rule.SetConfigInvalidCondition(openawarenessv1beta1.ReasonTemplateDataNotFound, err.Error())
-				if updateErr := r.Status().Update(ctx, rule); updateErr != nil {
-					logger.Error(updateErr, "Failed to update status")
-				}
-				return ctrl.Result{}, err
-			}
+	// Centrally versioned template files are fetched before the inline files override them
+	chain, err = utils.ResolveTemplateSources(ctx, s.r.TemplateSources, chain)
+	if err != nil {
+		logger.Error(err, "Failed to fetch templateSource")
+		rule.SetConfigInvalidCondition(openawarenessv1beta1.ReasonTemplateSourceFailed, err.Error())
+		return renderedAlertmanagerConfig{}, err
+	}
 
-			// Render the alertmanagerConfig with template data
-			renderedConfig, err = utils.RenderTemplate(rule.Spec.AlertmanagerConfig, templateData)
-			if err != nil {
-				logger.Error(err, "Failed to render template",
-					"name", rule.Name,
-					"namespace", rule.Namespace)
-				rule.SetConfigInvalidCondition(openawarenessv1beta1.ReasonInvalidTemplate, err.Error())
-				if updateErr := r.Status().Update(ctx, rule); updateErr != nil {
-					logger.Error(updateErr, "Failed to update status")
-				}
-				return ctrl.Result{}, err
-			}
+	// Template rendering must happen BEFORE validation
+	// Resource metadata is always available, so every config is rendered
+	templateData, conflicts, err := utils.GetSecretData(ctx, s.r.Client, logger, rule.Namespace,
+		utils.ComposedReferences(chain), rule.Spec.ReferenceMergeStrategy)
+	rule.Status.ReferenceConflicts = conflicts
+	if err != nil {
+		logger.Error(err, "Failed to get template data")
+		reason := openawarenessv1beta1.ReasonTemplateDataNotFound
+		if errors.Is(err, utils.ErrReferenceConflict) {
+			reason = openawarenessv1beta1.ReasonReferenceConflict
+		}
+		rule.SetConfigInvalidCondition(reason, err.Error())
+		return renderedAlertmanagerConfig{}, err
+	}
 
-			logger.V(1).Info("Template rendered successfully",
-				"name", rule.Name,
-				"templateVars", len(templateData))
+	// Render the alertmanagerConfig of every tenant in the chain with template data and compose them
+	builtins := utils.TemplateBuiltins{
+		Global: globals,
+		Meta:   utils.NewTemplateMetadata(rule, s.r.ClusterName),
+	}
+	renderedConfig, err := utils.RenderComposedConfig(chain, templateData, builtins)
+	if err == nil {
+		// Routing subtrees contributed by MimirAlertRoutes are merged into the composed config
+		renderedConfig, err = s.r.mergeRoutes(ctx, logger, rule, chain, renderedConfig, templateData, builtins)
+	}
+	if err == nil {
+		// The global section contributed by MimirAlertGlobals overrides the one of the config
+		renderedConfig, err = s.r.mergeAlertGlobals(ctx, logger, rule, chain, renderedConfig, templateData, builtins)
+	}
+	if err == nil {
+		// Time intervals contributed by MimirMuteTimings are appended to time_intervals
+		renderedConfig, err = s.r.mergeMuteTimings(ctx, logger, rule, chain, renderedConfig)
+	}
+	if err == nil {
+		// Inhibition rules contributed by MimirInhibitRules are appended to inhibit_rules
+		renderedConfig, err = s.r.mergeInhibitRules(ctx, logger, rule, chain, renderedConfig)
+	}
+	if err != nil {
+		logger.Error(err, "Failed to render template")
+		if errors.Is(err, utils.ErrComposition) {
+			rule.SetCompositionFailedCondition(openawarenessv1beta1.ReasonCompositionFailed, err.Error())
 		} else {
-			// No templating needed
-			renderedConfig = rule.ToConfigDTO()
-		}
-
-		// Validate the rendered Alertmanager configuration before sending to Mimir
-		// We need to create a temporary copy with the rendered config for validation
-		if err := rule.ValidateRenderedConfig(renderedConfig); err != nil {
-			logger.Error(err, "Invalid Alertmanager configuration after rendering",
-				"name", rule.Name,
-				"namespace", rule.Namespace)
-			rule.SetConfigInvalidCondition(openawarenessv1beta1.ReasonInvalidYAML, err.Error())
-			if updateErr := r.Status().Update(ctx, rule); updateErr != nil {
-				logger.Error(updateErr, "Failed to update status")
-				return ctrl.Result{}, updateErr
-			}
-			return ctrl.Result{}, err
+			rule.SetConfigInvalidCondition(openawarenessv1beta1.ReasonInvalidTemplate, err.Error())
+		}
+		return renderedAlertmanagerConfig{}, err
+	}
+
+	// Alerts matching no route must not be dropped silently
+	renderedConfig, injected, err := s.r.DefaultReceiver.Ensure(renderedConfig)
+	if err != nil {
+		logger.Error(err, "Rendered configuration has no default receiver")
+		reason := openawarenessv1beta1.ReasonInvalidYAML
+		if errors.Is(err, utils.ErrNoDefaultReceiver) {
+			reason = openawarenessv1beta1.ReasonNoDefaultReceiver
 		}
+		rule.SetConfigInvalidCondition(reason, err.Error())
+		return renderedAlertmanagerConfig{}, err
+	}
+	if injected {
+		events.Eventf(s.r.Recorder, rule, events.DefaultReceiverInjected,
+			"The top-level route has no resolvable receiver, alerts matching no route are sent to %s",
+			utils.DefaultReceiverName)
+	}
+
+	logger.V(1).Info("Template rendered successfully",
+		"templateVars", len(templateData))
+	return renderedAlertmanagerConfig{
+		config:    renderedConfig,
+		templates: utils.ComposedTemplateFiles(chain),
+		fetched:   utils.UsesTemplateSource(chain),
+	}, nil
+}
+
+// Validate checks the rendered configuration and its matchers, the quota of the tenant and
+// enforces the Alertmanager policy on it. Blocking policy violations are reported as
+// errPolicyBlocked, exceeded quotas as policy.ErrQuotaExceeded, matchers invalid for the
+// matcherSyntax of the tenant as utils.ErrInvalidMatcher.
+func (s *mimirAlertTenantSync) Validate(
+	ctx context.Context,
+	state *utils.SyncState[*openawarenessv1beta1.MimirAlertTenant],
+	rendered renderedAlertmanagerConfig,
+) error {
+	logger := log.FromContext(ctx)
+	rule := state.Object
+
+	if err := rule.ValidateRenderedConfig(rendered.config); err != nil {
+		logger.Error(err, "Invalid Alertmanager configuration after rendering")
+		rule.SetConfigInvalidCondition(openawarenessv1beta1.ReasonInvalidYAML, err.Error())
+		return err
+	}
 
-		templates := rule.ToTemplatesDTO()
+	// Matchers the Alertmanager of Mimir cannot parse would fail the push
+	if err := utils.ValidateMatchers(rendered.config, rule.Spec.MatcherSyntax); err != nil {
+		logger.Info("Alertmanager configuration has invalid matchers",
+			"matcherSyntax", rule.Spec.MatcherSyntax,
+			"error", err.Error())
+		rule.SetConfigInvalidCondition(openawarenessv1beta1.ReasonInvalidMatcher, err.Error())
+		return err
+	}
 
-		// Get tenant ID from annotations for the API call
-		tenantID := rule.GetAnnotations()[utils.MimirTenantAnnotation]
-		if tenantID == "" {
-			tenantID = utils.DefaultTenantID
+	if err := s.r.checkQuota(ctx, rule, rendered); err != nil {
+		if !errors.Is(err, policy.ErrQuotaExceeded) {
+			logger.Error(err, "Failed to check the quota")
+			return err
 		}
+		logger.Info("MimirAlertTenant exceeds its quota",
+			"error", err.Error())
+		rule.SetConfigInvalidCondition(openawarenessv1beta1.ReasonQuotaExceeded, err.Error())
+		events.Event(s.r.Recorder, rule, events.QuotaExceeded,
+			"Alertmanager configuration is not synced: "+err.Error())
+		return err
+	}
 
-		err = alertManagerClient.CreateAlertmanagerConfig(ctx, renderedConfig, templates, tenantID)
-		if err != nil {
-			logger.Error(err, "Failed to create Alertmanager configuration",
-				"name", rule.Name,
-				"namespace", rule.Namespace,
-				"tenantID", tenantID)
+	amPolicy := s.settings.AlertmanagerPolicy
+	if !amPolicy.Enabled() {
+		return nil
+	}
+	violations, err := amPolicy.Check(rendered.config)
+	if err != nil {
+		logger.Error(err, "Invalid Alertmanager configuration for policy check")
+		rule.SetConfigInvalidCondition(openawarenessv1beta1.ReasonInvalidYAML, err.Error())
+		return err
+	}
+	rule.SetPolicyViolationCondition(violations)
+	if len(violations) == 0 {
+		return nil
+	}
+	logger.Info("Alertmanager configuration violates policy",
+		"violations", violations)
+	if !amPolicy.Blocking() {
+		return nil
+	}
+	rule.SetConfigInvalidCondition(openawarenessv1beta1.ReasonPolicyViolation,
+		fmt.Sprintf("Policy violations: %s", strings.Join(violations, "; ")))
+	return errPolicyBlocked
+}
 
-			// Categorize the error and set appropriate status using shared utility
-			reason, _ := utils.CategorizeError(err)
-			rule.SetFailedCondition(reason, err.Error())
-			if updateErr := r.Status().Update(ctx, rule); updateErr != nil {
-				logger.Error(updateErr, "Failed to update status")
-			}
+// Push writes the rendered configuration with its template files to Mimir.
+// Changes against the previously pushed configuration are recorded as redacted diff in
+// status.lastConfigDiff and a ConfigurationChanged event.
+// The configuration is read back afterwards; if Mimir serves the fallback configuration to
+// the tenant because the pushed one is blank, errFallbackConfig is returned.
+// Tenants the ClientConfig does not allow are refused with utils.ErrTenantNotAllowed.
+func (s *mimirAlertTenantSync) Push(
+	ctx context.Context,
+	state *utils.SyncState[*openawarenessv1beta1.MimirAlertTenant],
+	alertManagerClient clients.AwarenessClient,
+	rendered renderedAlertmanagerConfig,
+) error {
+	logger := log.FromContext(ctx)
+	rule := state.Object
+	tenantID := tenantIDOf(rule, state.ClientConfig)
+	if err := utils.CheckTenantsAllowed(state.ClientConfig, tenantID); err != nil {
+		return err
+	}
+
+	previous, _, err := alertManagerClient.GetAlertmanagerConfig(ctx, tenantID)
+	if err != nil {
+		// The diff is informational, it must not block the push
+		logger.V(1).Info("Unable to read the pushed Alertmanager configuration, skipping diff",
+			logging.KeyTenant, tenantID,
+			"error", err.Error())
+		previous = ""
+	}
+
+	config := utils.AddManagedByHeader(rendered.config, "MimirAlertTenant", rule, s.r.Identity)
+	if err := alertManagerClient.CreateAlertmanagerConfig(ctx, config, rendered.templates, tenantID); err != nil {
+		return err
+	}
+	logging.SuccessInfo(logger, utils.OwnerReference(rule), "Successfully created Alertmanager configuration")
+	s.r.updateBackup(ctx, logger, rule, func(clientConfig *openawarenessv1beta1.ClientConfig) error {
+		return s.r.Backup.SaveAlertmanagerConfig(ctx, clientConfig, tenantID, config, rendered.templates)
+	})
+
+	if diff := utils.ConfigDiff(previous, config, configDiffStatusLength); diff != "" {
+		rule.Status.LastConfigDiff = diff
+		events.Event(s.r.Recorder, rule, events.ConfigurationChanged,
+			"Alertmanager configuration changed:\n"+utils.ConfigDiff(previous, config, configDiffEventLength))
+	}
+
+	stored, _, err := alertManagerClient.GetAlertmanagerConfig(ctx, tenantID)
+	if err != nil {
+		// The check is informational, the push itself succeeded
+		logger.V(1).Info("Unable to read back the pushed Alertmanager configuration, skipping fallback detection",
+			logging.KeyTenant, tenantID,
+			"error", err.Error())
+		return nil
+	}
+	if mimir.UsesFallbackConfig(stored) {
+		return errFallbackConfig
+	}
+	return nil
+}
+
+// Diff summarizes how pushing the rendered configuration would change the configuration of the
+// tenant in Mimir, as redacted diff and whether the template files change, empty if Mimir is in
+// sync. Tenants the ClientConfig does not allow are refused with utils.ErrTenantNotAllowed.
+func (s *mimirAlertTenantSync) Diff(
+	ctx context.Context,
+	state *utils.SyncState[*openawarenessv1beta1.MimirAlertTenant],
+	alertManagerClient clients.AwarenessClient,
+	rendered renderedAlertmanagerConfig,
+) (string, error) {
+	rule := state.Object
+	tenantID := tenantIDOf(rule, state.ClientConfig)
+	if err := utils.CheckTenantsAllowed(state.ClientConfig, tenantID); err != nil {
+		return "", err
+	}
+	previous, templates, err := alertManagerClient.GetAlertmanagerConfig(ctx, tenantID)
+	if err != nil {
+		return "", fmt.Errorf("reading the Alertmanager configuration of tenant %s: %w", tenantID, err)
+	}
+	if strings.TrimSpace(previous) == "" {
+		return fmt.Sprintf("the Alertmanager configuration of tenant %s would be created", tenantID), nil
+	}
+
+	config := utils.AddManagedByHeader(rendered.config, "MimirAlertTenant", rule, s.r.Identity)
+	var changes []string
+	if diff := utils.ConfigDiff(previous, config, configDiffStatusLength); diff != "" {
+		changes = append(changes, diff)
+	}
+	if !maps.Equal(templates, rendered.templates) && (len(templates) > 0 || len(rendered.templates) > 0) {
+		changes = append(changes, "template files changed")
+	}
+	return strings.Join(changes, "\n"), nil
+}
+
+// Delete removes the Alertmanager configuration of the tenant from Mimir.
+// The configuration is tenant-wide, so it is only deleted if the tenant has the
+// ConfirmDeleteAnnotation or DestructiveCleanup is set; otherwise it is retained in Mimir and a
+// RemoteDataRetained warning event is recorded.
+// Tenants the ClientConfig does not allow are left untouched, nothing was pushed to them.
+func (s *mimirAlertTenantSync) Delete(
+	ctx context.Context,
+	state *utils.SyncState[*openawarenessv1beta1.MimirAlertTenant],
+	alertManagerClient clients.AwarenessClient,
+) error {
+	rule := state.Object
+	if !state.ClientConfig.TenantAllowed(tenantIDOf(rule, state.ClientConfig)) {
+		log.FromContext(ctx).Info("Tenant is not allowed by the ClientConfig, skipping deletion from Mimir",
+			logging.KeyTenant, tenantIDOf(rule, state.ClientConfig))
+		return nil
+	}
+	if !s.r.DestructiveCleanup && rule.Annotations[utils.ConfirmDeleteAnnotation] != "true" {
+		log.FromContext(ctx).Info("Deletion is not confirmed, retaining the Alertmanager configuration in Mimir",
+			logging.KeyTenant, tenantIDOf(rule, state.ClientConfig),
+			"annotation", utils.ConfirmDeleteAnnotation)
+		events.Eventf(s.r.Recorder, rule, events.RemoteDataRetained,
+			"The Alertmanager configuration of tenant %s is retained in Mimir, set the %s: \"true\" annotation "+
+				"before deleting to remove it", tenantIDOf(rule, state.ClientConfig), utils.ConfirmDeleteAnnotation)
+		return nil
+	}
+	if err := alertManagerClient.DeleteAlermanagerConfig(ctx, tenantIDOf(rule, state.ClientConfig)); err != nil {
+		return err
+	}
+	logger := log.FromContext(ctx)
+	logger.Info("Successfully deleted Alertmanager configuration from Mimir",
+		logging.KeyTenant, tenantIDOf(rule, state.ClientConfig))
+	s.r.updateBackup(ctx, logger, rule, func(clientConfig *openawarenessv1beta1.ClientConfig) error {
+		return s.r.Backup.RemoveAlertmanagerConfig(ctx, clientConfig, tenantIDOf(rule, clientConfig))
+	})
+	return nil
+}
+
+// Report writes the outcome to the status of the tenant.
+// Deletion failures are logged but do not keep the finalizer, so the tenant is not stuck in
+// deletion; they may leave orphaned configuration in Mimir, which operators should clean up
+// manually. Client failures are retried without status update. Syncs and deletions interrupted
+// by a shutdown are retried, interrupted syncs are reported as Pending. In read-only mode only
+// the WouldSync condition is set, see utils.SetWouldSyncCondition.
+func (s *mimirAlertTenantSync) Report(
+	ctx context.Context,
+	state *utils.SyncState[*openawarenessv1beta1.MimirAlertTenant],
+	outcome utils.SyncOutcome[renderedAlertmanagerConfig],
+) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+	rule := state.Object
+
+	// Read-only controllers only record whether the sync would succeed
+	if state.ReadOnly && outcome.Stage != utils.SyncStagePaused {
+		utils.SetWouldSyncCondition(&rule.Status.Conditions, rule.Generation, outcome.Stage, outcome.Diff, outcome.Err)
+		rule.Status.ObservedGeneration = rule.Generation
+		if err := utils.PatchStatus(ctx, s.r.Client, rule, state.Original); err != nil {
+			logger.Error(err, "Failed to update status")
 			return ctrl.Result{}, err
 		}
+		return utils.ReadOnlyResult(outcome.Stage, outcome.Err)
+	}
 
-		logger.Info("Successfully created Alertmanager configuration",
-			"name", rule.Name,
-			"namespace", rule.Namespace)
+	if errors.Is(outcome.Err, utils.ErrSyncInterrupted) {
+		if outcome.Stage == utils.SyncStageDelete {
+			// Keep the finalizer so the configuration is deleted after the restart
+			return ctrl.Result{}, outcome.Err
+		}
+		rule.SetPendingCondition(openawarenessv1beta1.ReasonInterrupted, utils.StatusMessage(outcome.Err))
+		if err := utils.PatchStatus(ctx, s.r.Client, rule, state.Original); err != nil {
+			logger.Error(err, "Failed to update status")
+		}
+		return ctrl.Result{}, outcome.Err
+	}
 
-		// Update status to reflect successful sync
+	switch outcome.Stage {
+	case utils.SyncStageResolve:
+		logger.Error(outcome.Err, "Failed to get Alertmanager client")
+		// Return error to trigger retry
+		return ctrl.Result{}, outcome.Err
+	case utils.SyncStageDelete:
+		if outcome.Err == nil {
+			return ctrl.Result{}, nil
+		}
+		if outcome.Remote == nil {
+			logger.Error(outcome.Err, "Failed to get Alertmanager client for deletion - configuration may be orphaned in Mimir",
+				"warning", "Unable to cleanup Alertmanager configuration from Mimir API")
+		} else {
+			logger.Error(outcome.Err, "Failed to delete Alertmanager configuration - configuration may be orphaned in Mimir",
+				logging.KeyTenant, tenantIDOf(rule, state.ClientConfig),
+				"warning", "Alertmanager configuration may still exist in Mimir API")
+		}
+		return ctrl.Result{}, nil
+	case utils.SyncStagePush:
+		if errors.Is(outcome.Err, errFallbackConfig) {
+			logger.Info("Mimir serves the fallback Alertmanager configuration, the pushed configuration is blank",
+				logging.KeyTenant, tenantIDOf(rule, state.ClientConfig))
+			rule.SetFallbackCondition(tenantIDOf(rule, state.ClientConfig))
+			events.Event(s.r.Recorder, rule, events.FallbackConfig,
+				"Mimir serves the fallback Alertmanager configuration, the synced configuration is blank")
+			break
+		}
+		if errors.Is(outcome.Err, utils.ErrTenantNotAllowed) {
+			logger.Info("Tenant is not allowed by the ClientConfig, refusing the push",
+				logging.KeyTenant, tenantIDOf(rule, state.ClientConfig))
+			rule.SetFailedCondition(openawarenessv1beta1.ReasonTenantNotAllowed, outcome.Err.Error())
+			events.Event(s.r.Recorder, rule, events.TenantNotAllowed,
+				"Alertmanager configuration is not synced: "+outcome.Err.Error())
+			break
+		}
+		logger.Error(outcome.Err, "Failed to create Alertmanager configuration",
+			logging.KeyTenant, tenantIDOf(rule, state.ClientConfig))
+		// Categorize the error and set appropriate status using shared utility
+		reason, _ := utils.CategorizeError(outcome.Err)
+		rule.SetFailedCondition(reason, utils.StatusMessage(outcome.Err))
+	case utils.SyncStagePaused:
+		rule.SetPausedCondition()
+	case utils.SyncStageSynced:
 		rule.SetSyncedCondition()
-		if err := r.Status().Update(ctx, rule); err != nil {
-			logger.Error(err, "Failed to update status after successful sync")
-			return ctrl.Result{}, err
+		rule.SetResolvedTarget(tenantIDOf(rule, state.ClientConfig), state.ClientConfig)
+		utils.SetPausedCondition(&rule.Status.Conditions, false, rule.Generation)
+	}
+
+	// Render and validation failures have set their condition already
+	rule.Status.ObservedGeneration = rule.Generation
+	if err := utils.PatchStatus(ctx, s.r.Client, rule, state.Original); err != nil {
+		logger.Error(err, "Failed to update status")
+		return ctrl.Result{}, err
+	}
+	if errors.Is(outcome.Err, utils.ErrExtendsCycle) || errors.Is(outcome.Err, errPolicyBlocked) ||
+		errors.Is(outcome.Err, errFallbackConfig) || errors.Is(outcome.Err, utils.ErrTenantNotAllowed) ||
+		errors.Is(outcome.Err, utils.ErrInvalidMatcher) || errors.Is(outcome.Err, policy.ErrQuotaExceeded) {
+		// Spec and ClientConfig changes trigger a new reconciliation, retrying does not help.
+		// Deleting other tenants frees up the quota, see findTenantsExceedingQuota
+		return ctrl.Result{}, nil
+	}
+	if outcome.Stage == utils.SyncStageSynced && outcome.Payload.fetched {
+		// Changes of fetched template files are not watched, they are picked up periodically
+		return ctrl.Result{RequeueAfter: s.r.TemplateSourceRefreshInterval}, nil
+	}
+	return ctrl.Result{}, outcome.Err
+}
+
+// checkQuota checks the tenant against the Quota: the size of the rendered configuration and
+// the number of MimirAlertTenants in its namespace and targeting the same Mimir tenant through
+// the same ClientConfig, deleted tenants excluded. Validation runs before the ClientConfig is
+// resolved, so the ClientConfigs are resolved from the listed ones, for the tenants that may
+// target the Mimir tenant by the tenant index only; tenants without a ClientConfig are not
+// counted per Mimir tenant.
+// Returns an error wrapping policy.ErrQuotaExceeded if a quota is exceeded, or the error of
+// listing the resources.
+func (r *MimirAlertTenantReconciler) checkQuota(
+	ctx context.Context,
+	rule *openawarenessv1beta1.MimirAlertTenant,
+	rendered renderedAlertmanagerConfig,
+) error {
+	if err := r.Quota.CheckConfigSize(rendered.config, rendered.templates); err != nil {
+		return err
+	}
+	if !r.Quota.LimitsCount() {
+		return nil
+	}
+
+	tenantList := &openawarenessv1beta1.MimirAlertTenantList{}
+	if err := r.List(ctx, tenantList, k8sClient.InNamespace(rule.Namespace)); err != nil {
+		return fmt.Errorf("failed to list MimirAlertTenants: %w", err)
+	}
+	var inNamespace []metav1.Object
+	for i := range tenantList.Items {
+		other := &tenantList.Items[i]
+		if other.UID == rule.UID || other.DeletionTimestamp.IsZero() {
+			inNamespace = append(inNamespace, other)
 		}
+	}
+	if err := r.Quota.CheckNamespaceCount(rule, inNamespace); err != nil {
+		return err
+	}
+	if r.Quota.MaxPerTenant <= 0 {
+		return nil
+	}
 
-	} else {
-		// The object is being deleted
-		// Get the alertmanager client for cleanup
-		alertManagerClient, err := r.clientFromCrd(ctx, logger, rule)
-		if err != nil {
-			logger.Error(err, "Failed to get Alertmanager client for deletion - configuration may be orphaned in Mimir",
-				"name", rule.Name,
-				"namespace", rule.Namespace,
-				"warning", "Unable to cleanup Alertmanager configuration from Mimir API")
-			// If we can't get the client, we still need to remove the finalizer
-			// to allow deletion to proceed. This may leave orphaned configuration in Mimir.
-			// Operators should manually clean up if needed.
-			if controllerutil.ContainsFinalizer(rule, utils.FinalizerAnnotation) {
-				controllerutil.RemoveFinalizer(rule, utils.FinalizerAnnotation)
-				if err := r.Update(ctx, rule); err != nil {
-					return ctrl.Result{}, err
-				}
+	clientConfigs := &openawarenessv1beta1.ClientConfigList{}
+	if err := r.List(ctx, clientConfigs); err != nil {
+		return fmt.Errorf("failed to list ClientConfigs: %w", err)
+	}
+	mappings, err := utils.ListTenantMappings(ctx, r.Client)
+	if err != nil {
+		return fmt.Errorf("failed to list TenantMappings: %w", err)
+	}
+	clientConfig := utils.ClientConfigFor(rule, clientConfigs.Items, mappings)
+	if clientConfig == nil {
+		// The sync fails to resolve the ClientConfig later on
+		return nil
+	}
+	tenantID := tenantIDOf(rule, clientConfig)
+	var inTenant []metav1.Object
+	for _, value := range utils.TenantIndexValues(clientConfig, tenantID) {
+		candidates := &openawarenessv1beta1.MimirAlertTenantList{}
+		if err := r.List(ctx, candidates, k8sClient.MatchingFields{utils.TenantIndexKey: value}); err != nil {
+			return fmt.Errorf("failed to list MimirAlertTenants of tenant %s: %w", value, err)
+		}
+		for i := range candidates.Items {
+			other := &candidates.Items[i]
+			if other.UID != rule.UID && !other.DeletionTimestamp.IsZero() {
+				continue
+			}
+			otherConfig := utils.ClientConfigFor(other, clientConfigs.Items, mappings)
+			if otherConfig != nil && otherConfig.Name == clientConfig.Name &&
+				otherConfig.Namespace == clientConfig.Namespace && tenantIDOf(other, otherConfig) == tenantID {
+				inTenant = append(inTenant, other)
 			}
-			return ctrl.Result{}, nil
 		}
+	}
+	return r.Quota.CheckTenantCount(rule, tenantID, inTenant)
+}
 
-		// Get tenant ID from annotations for the API call
-		tenantID := rule.GetAnnotations()[utils.MimirTenantAnnotation]
-		if tenantID == "" {
-			tenantID = utils.DefaultTenantID
-		}
+// tenantIDOf returns the Mimir tenant of a MimirAlertTenant from its annotations, resolved
+// through its ClientConfig, see utils.GetTenantID.
+func tenantIDOf(rule *openawarenessv1beta1.MimirAlertTenant, clientConfig *openawarenessv1beta1.ClientConfig) string {
+	return utils.GetTenantID(rule, clientConfig)
+}
 
-		err = alertManagerClient.DeleteAlermanagerConfig(ctx, tenantID)
-		if err != nil {
-			logger.Error(err, "Failed to delete Alertmanager configuration - configuration may be orphaned in Mimir",
-				"name", rule.Name,
-				"namespace", rule.Namespace,
-				"tenantID", tenantID,
-				"warning", "Alertmanager configuration may still exist in Mimir API")
-			// Continue with finalizer removal even if deletion fails to prevent resource from being stuck.
-			// This may leave orphaned configuration in Mimir. Operators should manually clean up if needed.
+// mergeRoutes merges the MimirAlertRoutes contributed to the tenants of the chain into the
+// rendered configuration through utils.MergeRoutes. Routes referencing the tenant itself
+// report in their status whether they were merged or rejected; routes of base tenants
+// report for their own tenant.
+// Returns the merged configuration, or an error if the routes cannot be listed or the
+// configuration cannot be merged.
+func (r *MimirAlertTenantReconciler) mergeRoutes(
+	ctx context.Context,
+	logger logr.Logger,
+	tenant *openawarenessv1beta1.MimirAlertTenant,
+	chain []*openawarenessv1beta1.MimirAlertTenant,
+	config string,
+	data map[string]string,
+	builtins utils.TemplateBuiltins,
+) (string, error) {
+	routes, err := utils.ComposedRoutes(ctx, r.Client, chain)
+	if err != nil {
+		return "", err
+	}
+	merged, rejected, err := utils.MergeRoutes(config, routes, data, builtins)
+	if err != nil {
+		return "", err
+	}
+
+	for i := range routes {
+		route := &routes[i]
+		if route.Spec.Tenant.Name != tenant.Name {
+			continue
+		}
+		original := route.DeepCopy()
+		var changed bool
+		if rejectErr, ok := rejected[route.Name]; ok {
+			logger.Info("MimirAlertRoute rejected",
+				"route", route.Name,
+				"namespace", route.Namespace,
+				"error", rejectErr.Error())
+			reason := openawarenessv1beta1.ReasonInvalidRoute
+			if errors.Is(rejectErr, utils.ErrDuplicateReceiver) {
+				reason = openawarenessv1beta1.ReasonDuplicateReceiver
+			}
+			changed = route.SetRejectedCondition(reason, rejectErr.Error())
 		} else {
-			logger.Info("Successfully deleted Alertmanager configuration from Mimir",
-				"name", rule.Name,
-				"namespace", rule.Namespace,
-				"tenantID", tenantID)
+			changed = route.SetMergedCondition(tenant.Name)
+		}
+		if changed {
+			if err := utils.PatchStatus(ctx, r.Client, route, original); err != nil {
+				logger.Error(err, "Failed to update MimirAlertRoute status", "route", route.Name)
+			}
 		}
+	}
+
+	logger.V(1).Info("Merged MimirAlertRoutes",
+		"routes", len(routes),
+		"rejected", len(rejected))
+	return merged, nil
+}
 
-		// Remove finalizer
-		if controllerutil.ContainsFinalizer(rule, utils.FinalizerAnnotation) {
-			controllerutil.RemoveFinalizer(rule, utils.FinalizerAnnotation)
-			if err := r.Update(ctx, rule); err != nil {
-				return ctrl.Result{}, err
+// mergeAlertGlobals merges the MimirAlertGlobals contributed to the tenants of the chain into
+// the rendered configuration through utils.MergeAlertGlobals. MimirAlertGlobals referencing the
+// tenant itself report in their status whether they were merged or rejected.
+// Returns the merged configuration, or an error if the MimirAlertGlobals cannot be listed or
+// the configuration cannot be merged.
+func (r *MimirAlertTenantReconciler) mergeAlertGlobals(
+	ctx context.Context,
+	logger logr.Logger,
+	tenant *openawarenessv1beta1.MimirAlertTenant,
+	chain []*openawarenessv1beta1.MimirAlertTenant,
+	config string,
+	data map[string]string,
+	builtins utils.TemplateBuiltins,
+) (string, error) {
+	composed, err := utils.ComposedAlertGlobals(ctx, r.Client, chain)
+	if err != nil {
+		return "", err
+	}
+	merged, rejected, err := utils.MergeAlertGlobals(config, composed, data, builtins)
+	if err != nil {
+		return "", err
+	}
+
+	for i := range composed {
+		globals := &composed[i]
+		if globals.Spec.Tenant.Name != tenant.Name {
+			continue
+		}
+		original := globals.DeepCopy()
+		var changed bool
+		if rejectErr, ok := rejected[globals.Name]; ok {
+			logger.Info("MimirAlertGlobals rejected",
+				"globals", globals.Name,
+				"namespace", globals.Namespace,
+				"error", rejectErr.Error())
+			reason := openawarenessv1beta1.ReasonInvalidGlobals
+			if errors.Is(rejectErr, utils.ErrDuplicateGlobals) {
+				reason = openawarenessv1beta1.ReasonDuplicateGlobals
+			}
+			changed = globals.SetRejectedCondition(reason, rejectErr.Error())
+		} else {
+			changed = globals.SetMergedCondition(tenant.Name)
+		}
+		if changed {
+			if err := utils.PatchStatus(ctx, r.Client, globals, original); err != nil {
+				logger.Error(err, "Failed to update MimirAlertGlobals status", "globals", globals.Name)
 			}
-			logger.Info("MimirAlertTenant was deleted",
-				"name", rule.Name,
-				"namespace", rule.Namespace)
 		}
 	}
-	return ctrl.Result{}, nil
 
+	logger.V(1).Info("Merged MimirAlertGlobals",
+		"globals", len(composed),
+		"rejected", len(rejected))
+	return merged, nil
 }
 
-// clientFromCrd retrieves the appropriate Mimir client for the given MimirAlertTenant.
-// It extracts the client name and tenant ID from the resource's annotations,
-// fetches the ClientConfig, and returns a tenant-specific Mimir client.
-// Returns an error if annotations are missing or if the client cannot be created.
-func (r *MimirAlertTenantReconciler) clientFromCrd(
+// mergeMuteTimings merges the MimirMuteTimings contributed to the tenants of the chain into
+// the rendered configuration through utils.MergeMuteTimings. MimirMuteTimings referencing the
+// tenant itself report in their status whether they were merged or rejected.
+// Returns the merged configuration, or an error if the MimirMuteTimings cannot be listed or
+// the configuration cannot be merged.
+func (r *MimirAlertTenantReconciler) mergeMuteTimings(
 	ctx context.Context,
 	logger logr.Logger,
-	rule *openawarenessv1beta1.MimirAlertTenant,
-) (clients.AwarenessClient, error) {
-	if r.RulerClients == nil {
-		logger.Info("RulerClients cache is not initialized")
-		return nil, fmt.Errorf("ruler clients cache is nil for MimirAlertTenant %s/%s", rule.Namespace, rule.Name)
+	tenant *openawarenessv1beta1.MimirAlertTenant,
+	chain []*openawarenessv1beta1.MimirAlertTenant,
+	config string,
+) (string, error) {
+	composed, err := utils.ComposedMuteTimings(ctx, r.Client, chain)
+	if err != nil {
+		return "", err
 	}
+	merged, rejected, err := utils.MergeMuteTimings(config, composed)
+	if err != nil {
+		return "", err
+	}
+
+	for i := range composed {
+		timing := &composed[i]
+		if timing.Spec.Tenant.Name != tenant.Name {
+			continue
+		}
+		original := timing.DeepCopy()
+		var changed bool
+		if rejectErr, ok := rejected[timing.Name]; ok {
+			logger.Info("MimirMuteTiming rejected",
+				"muteTiming", timing.Name,
+				"namespace", timing.Namespace,
+				"error", rejectErr.Error())
+			reason := openawarenessv1beta1.ReasonInvalidMuteTiming
+			if errors.Is(rejectErr, utils.ErrDuplicateMuteTiming) {
+				reason = openawarenessv1beta1.ReasonDuplicateMuteTiming
+			}
+			changed = timing.SetRejectedCondition(reason, rejectErr.Error())
+		} else {
+			changed = timing.SetMergedCondition(tenant.Name)
+		}
+		if changed {
+			if err := utils.PatchStatus(ctx, r.Client, timing, original); err != nil {
+				logger.Error(err, "Failed to update MimirMuteTiming status", "muteTiming", timing.Name)
+			}
+		}
+	}
+
+	logger.V(1).Info("Merged MimirMuteTimings",
+		"muteTimings", len(composed),
+		"rejected", len(rejected))
+	return merged, nil
+}
 
-	// Extract and validate required annotations
-	annotations, err := utils.GetRequiredAnnotations(rule, utils.ClientNameAnnotation, utils.MimirTenantAnnotation)
+// mergeInhibitRules merges the MimirInhibitRules contributed to the tenants of the chain into
+// the rendered configuration through utils.MergeInhibitRules. MimirInhibitRules referencing the
+// tenant itself report in their status whether they were merged or rejected.
+// Returns the merged configuration, or an error if the MimirInhibitRules cannot be listed or
+// the configuration cannot be merged.
+func (r *MimirAlertTenantReconciler) mergeInhibitRules(
+	ctx context.Context,
+	logger logr.Logger,
+	tenant *openawarenessv1beta1.MimirAlertTenant,
+	chain []*openawarenessv1beta1.MimirAlertTenant,
+	config string,
+) (string, error) {
+	composed, err := utils.ComposedInhibitRules(ctx, r.Client, chain)
 	if err != nil {
-		logger.Info("MimirAlertTenant is missing required annotations", "name", rule.Name, "error", err.Error())
-		return nil, err
+		return "", err
+	}
+	merged, rejected, err := utils.MergeInhibitRules(config, composed)
+	if err != nil {
+		return "", err
 	}
 
-	clientName := annotations[utils.ClientNameAnnotation]
-	tenantID := annotations[utils.MimirTenantAnnotation]
+	for i := range composed {
+		inhibitRule := &composed[i]
+		if inhibitRule.Spec.Tenant.Name != tenant.Name {
+			continue
+		}
+		original := inhibitRule.DeepCopy()
+		var changed bool
+		if rejectErr, ok := rejected[inhibitRule.Name]; ok {
+			logger.Info("MimirInhibitRule rejected",
+				"inhibitRule", inhibitRule.Name,
+				"namespace", inhibitRule.Namespace,
+				"error", rejectErr.Error())
+			reason := openawarenessv1beta1.ReasonInvalidInhibitRule
+			if errors.Is(rejectErr, utils.ErrDuplicateInhibitRule) {
+				reason = openawarenessv1beta1.ReasonDuplicateInhibitRule
+			}
+			changed = inhibitRule.SetRejectedCondition(reason, rejectErr.Error())
+		} else {
+			changed = inhibitRule.SetMergedCondition(tenant.Name)
+		}
+		if changed {
+			if err := utils.PatchStatus(ctx, r.Client, inhibitRule, original); err != nil {
+				logger.Error(err, "Failed to update MimirInhibitRule status", "inhibitRule", inhibitRule.Name)
+			}
+		}
+	}
+
+	logger.V(1).Info("Merged MimirInhibitRules",
+		"inhibitRules", len(composed),
+		"rejected", len(rejected))
+	return merged, nil
+}
+
+// updateBackup applies update to the backup of the tenant with its ClientConfig.
+// The backup must not fail the sync, errors are logged.
+func (r *MimirAlertTenantReconciler) updateBackup(
+	ctx context.Context,
+	logger logr.Logger,
+	tenant *openawarenessv1beta1.MimirAlertTenant,
+	update func(clientConfig *openawarenessv1beta1.ClientConfig) error,
+) {
+	if r.Backup == nil {
+		return
+	}
+	clientConfig, err := utils.GetClientConfig(ctx, r.Client, tenant)
+	if err == nil {
+		err = update(clientConfig)
+	}
+	if err != nil {
+		logger.Error(err, "Failed to update the backup of the Alertmanager configuration")
+	}
+}
 
-	// Get the ClientConfig to retrieve the Mimir address
-	clientConfig := &openawarenessv1beta1.ClientConfig{}
-	if err := r.Get(ctx, k8sClient.ObjectKey{
-		Name:      clientName,
-		Namespace: rule.Namespace,
-	}, clientConfig); err != nil {
-		logger.Error(err, "Failed to get ClientConfig", "clientName", clientName)
-		return nil, fmt.Errorf("getting ClientConfig %s: %w", clientName, err)
+// clientFromCrd retrieves the appropriate Mimir client for the given MimirAlertTenant.
+// It resolves the referenced or default ClientConfig through utils.ResolveClient and records
+// it in the state. The tenant is the tenant annotation resolved through the ClientConfig, see
+// tenantIDOf. The tenant and resolved ClientConfig are recorded as target of the reconciliation.
+// Returns an error if the tenant is neither annotated nor defaulted by the ClientConfig, or if
+// the client cannot be created.
+func (r *MimirAlertTenantReconciler) clientFromCrd(
+	ctx context.Context,
+	logger logr.Logger,
+	state *utils.SyncState[*openawarenessv1beta1.MimirAlertTenant],
+) (clients.AwarenessClient, error) {
+	rule := state.Object
+	alertManagerClient, clientConfig, err := utils.ResolveClient(ctx, r.Client, r.RulerClients, rule)
+	state.ClientConfig = clientConfig
+	if rule.Annotations[utils.MimirTenantAnnotation] == "" && (clientConfig == nil || clientConfig.Spec.DefaultTenant == "") {
+		err = fmt.Errorf("required annotation '%s' is missing or empty for %s/%s and no default tenant is set",
+			utils.MimirTenantAnnotation, rule.Namespace, rule.Name)
+		logger.Info("MimirAlertTenant is missing required annotations", "error", err.Error())
+		return nil, err
 	}
 
-	// Get or create a client specific to this tenant
-	alertManagerClient, err := r.RulerClients.GetOrCreateMimirClient(
-		ctx,
-		clientConfig.Spec.Address,
-		clientName,
-	)
+	tenantID := tenantIDOf(rule, clientConfig)
+	if clientConfig != nil {
+		state.Reconciliation.SetTarget(tenantID, clientConfig.Name)
+	}
 	if err != nil {
 		logger.Error(err, "Failed to get or create Mimir client",
-			"clientName", clientName,
-			"tenantID", tenantID,
-			"address", clientConfig.Spec.Address)
+			logging.KeyClient, rule.Annotations[utils.ClientNameAnnotation],
+			logging.KeyTenant, tenantID)
 		return nil, err
 	}
 
-	logger.Info("Got Mimir client for tenant",
-		"clientName", clientName,
-		"tenantID", tenantID,
+	logging.SuccessInfo(logger, utils.OwnerReference(rule), "Got Mimir client for tenant",
+		logging.KeyClient, clientConfig.Name,
+		logging.KeyTenant, tenantID,
 		"address", clientConfig.Spec.Address)
 
 	return alertManagerClient, nil
 }
 
-// getSecretData fetches and merges data from all SecretDataReferences.
-// Returns a map of key-value pairs for templating.
-// Later references override earlier ones in case of key conflicts.
-// Returns error if a required (non-optional) reference is not found.
-func (r *MimirAlertTenantReconciler) getSecretData(
+// SetupWithManager sets up the controller with the Manager.
+// It registers a field index on the client-name annotation and watches ClientConfigs
+// so dependent MimirAlertTenants are reconciled as soon as their ClientConfig changes.
+// Tenants are indexed by the tenant they extend, so changes of a base tenant are
+// propagated to all tenants extending it. MimirAlertRoute, MimirAlertGlobals, MimirMuteTiming and
+// MimirInhibitRule changes are propagated to their tenant and the tenants extending it, as are
+// changes of the Secrets tenants read their alertmanagerConfig from.
+// Tenants are indexed by their Mimir tenant for the quota check, and deleted tenants requeue the
+// tenants exceeding the quota.
+// Changes of the OperatorConfig requeue all MimirAlertTenants.
+// MimirAlertTenants, the resources contributing to them and Secrets are watched in the
+// ResourceCluster, ClientConfigs and the OperatorConfig in the manager's cluster.
+func (r *MimirAlertTenantReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	resources := cluster.Cluster(mgr)
+	if r.ResourceCluster != nil {
+		resources = r.ResourceCluster
+	}
+
+	if err := resources.GetFieldIndexer().IndexField(
+		context.Background(),
+		&openawarenessv1beta1.MimirAlertTenant{},
+		utils.ClientNameIndexKey,
+		utils.ClientNameIndexer,
+	); err != nil {
+		return fmt.Errorf("indexing MimirAlertTenants by client name: %w", err)
+	}
+	if err := resources.GetFieldIndexer().IndexField(
+		context.Background(),
+		&openawarenessv1beta1.MimirAlertTenant{},
+		utils.ExtendsIndexKey,
+		utils.ExtendsIndexer,
+	); err != nil {
+		return fmt.Errorf("indexing MimirAlertTenants by extended tenant: %w", err)
+	}
+	if err := resources.GetFieldIndexer().IndexField(
+		context.Background(),
+		&openawarenessv1beta1.MimirAlertTenant{},
+		utils.ConfigSecretIndexKey,
+		utils.ConfigSecretIndexer,
+	); err != nil {
+		return fmt.Errorf("indexing MimirAlertTenants by configuration Secret: %w", err)
+	}
+	if err := resources.GetFieldIndexer().IndexField(
+		context.Background(),
+		&openawarenessv1beta1.MimirAlertTenant{},
+		utils.TenantIndexKey,
+		utils.TenantIndexer,
+	); err != nil {
+		return fmt.Errorf("indexing MimirAlertTenants by Mimir tenant: %w", err)
+	}
+	if err := resources.GetFieldIndexer().IndexField(
+		context.Background(),
+		&openawarenessv1beta1.MimirAlertTenant{},
+		utils.QuotaExceededIndexKey,
+		utils.QuotaExceededIndexer,
+	); err != nil {
+		return fmt.Errorf("indexing MimirAlertTenants exceeding the quota: %w", err)
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("mimiralerttenant").
+		WatchesRawSource(source.Kind(resources.GetCache(), &openawarenessv1beta1.MimirAlertTenant{},
+			utils.EnqueueByPriority[*openawarenessv1beta1.MimirAlertTenant]())).
+		WatchesRawSource(source.Kind(resources.GetCache(), &openawarenessv1beta1.MimirAlertTenant{},
+			handler.TypedEnqueueRequestsFromMapFunc(r.findTenantsExtending))).
+		WatchesRawSource(source.Kind(resources.GetCache(), &openawarenessv1beta1.MimirAlertTenant{},
+			handler.TypedEnqueueRequestsFromMapFunc(r.findTenantsExceedingQuota),
+			// Only deleting a tenant frees up the quota
+			predicate.TypedFuncs[*openawarenessv1beta1.MimirAlertTenant]{
+				CreateFunc: func(event.TypedCreateEvent[*openawarenessv1beta1.MimirAlertTenant]) bool { return false },
+				UpdateFunc: func(e event.TypedUpdateEvent[*openawarenessv1beta1.MimirAlertTenant]) bool {
+					return e.ObjectOld.DeletionTimestamp.IsZero() && !e.ObjectNew.DeletionTimestamp.IsZero()
+				},
+				GenericFunc: func(event.TypedGenericEvent[*openawarenessv1beta1.MimirAlertTenant]) bool {
+					return false
+				},
+			})).
+		WatchesRawSource(source.Kind(resources.GetCache(), &openawarenessv1beta1.MimirAlertRoute{},
+			handler.TypedEnqueueRequestsFromMapFunc(r.findTenantsForRoute),
+			// Status updates of routes are written by this controller
+			predicate.TypedGenerationChangedPredicate[*openawarenessv1beta1.MimirAlertRoute]{})).
+		WatchesRawSource(source.Kind(resources.GetCache(), &openawarenessv1beta1.MimirAlertGlobals{},
+			handler.TypedEnqueueRequestsFromMapFunc(r.findTenantsForAlertGlobals),
+			// Status updates of globals are written by this controller
+			predicate.TypedGenerationChangedPredicate[*openawarenessv1beta1.MimirAlertGlobals]{})).
+		WatchesRawSource(source.Kind(resources.GetCache(), &openawarenessv1beta1.MimirMuteTiming{},
+			handler.TypedEnqueueRequestsFromMapFunc(r.findTenantsForMuteTiming),
+			// Status updates of mute timings are written by this controller
+			predicate.TypedGenerationChangedPredicate[*openawarenessv1beta1.MimirMuteTiming]{})).
+		WatchesRawSource(source.Kind(resources.GetCache(), &openawarenessv1beta1.MimirInhibitRule{},
+			handler.TypedEnqueueRequestsFromMapFunc(r.findTenantsForInhibitRule),
+			// Status updates of inhibition rules are written by this controller
+			predicate.TypedGenerationChangedPredicate[*openawarenessv1beta1.MimirInhibitRule]{})).
+		// Only the metadata of Secrets is cached, their data is read when composing
+		WatchesRawSource(source.Kind(resources.GetCache(), utils.MetadataOf("Secret"),
+			handler.TypedEnqueueRequestsFromMapFunc(r.findTenantsForConfigSecret))).
+		WithOptions(controller.Options{
+			NewQueue:                metrics.NewQueue(metrics.KindMimirAlertTenant),
+			RateLimiter:             r.OperatorConfig.RateLimiter(operatorconfig.DefaultRetry),
+			MaxConcurrentReconciles: r.MaxConcurrentReconciles,
+			LogConstructor:          logging.LogConstructor(mgr.GetLogger(), "mimiralerttenant"),
+		}).
+		Watches(
+			&openawarenessv1beta1.ClientConfig{},
+			handler.EnqueueRequestsFromMapFunc(r.findAlertTenantsForClient),
+			builder.WithPredicates(utils.ClientConfigChangedPredicate()),
+		).
+		Watches(
+			&openawarenessv1beta1.TenantMapping{},
+			handler.EnqueueRequestsFromMapFunc(r.findAlertTenantsForTenantMapping),
+		).
+		Watches(
+			&openawarenessv1beta1.OperatorConfig{},
+			handler.EnqueueRequestsFromMapFunc(r.findAlertTenantsForOperatorConfig),
+			// Status updates of the OperatorConfig change no setting
+			builder.WithPredicates(predicate.GenerationChangedPredicate{}),
+		).
+		Complete(logging.TrackAttempts(r))
+}
+
+// findTenantsExceedingQuota maps the deletion of a MimirAlertTenant to reconciliation requests
+// of the tenants exceeding the quota, the oldest of which are within it once the deleted tenant
+// is no longer counted. Nothing is requeued if the quota does not limit the number of tenants.
+func (r *MimirAlertTenantReconciler) findTenantsExceedingQuota(
 	ctx context.Context,
-	logger logr.Logger,
-	tenant *openawarenessv1beta1.MimirAlertTenant,
-) (map[string]string, error) {
-	data := make(map[string]string)
-
-	for _, ref := range tenant.Spec.SecretDataReferences {
-		refData, err := r.fetchReferenceData(ctx, tenant.Namespace, ref)
-		if err != nil {
-			if ref.Optional {
-				logger.Info("Optional reference not found, skipping",
-					"kind", ref.Kind,
-					"name", ref.Name)
-				continue
-			}
-			return nil, fmt.Errorf("failed to get %s %s: %w", ref.Kind, ref.Name, err)
+	_ *openawarenessv1beta1.MimirAlertTenant,
+) []reconcile.Request {
+	if !r.Quota.LimitsCount() {
+		return nil
+	}
+	tenantList := &openawarenessv1beta1.MimirAlertTenantList{}
+	if err := r.List(ctx, tenantList, k8sClient.MatchingFields{utils.QuotaExceededIndexKey: "true"}); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to list MimirAlertTenants exceeding the quota")
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(tenantList.Items))
+	for _, tenant := range tenantList.Items {
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Name: tenant.Name, Namespace: tenant.Namespace},
+		})
+	}
+	return requests
+}
+
+// findAlertTenantsForOperatorConfig maps changes of the OperatorConfig to reconciliation requests
+// of all MimirAlertTenants, so changed settings apply without a restart.
+func (r *MimirAlertTenantReconciler) findAlertTenantsForOperatorConfig(
+	ctx context.Context,
+	obj k8sClient.Object,
+) []reconcile.Request {
+	if obj.GetName() != openawarenessv1beta1.OperatorConfigName {
+		return nil
+	}
+	logger := log.FromContext(ctx)
+
+	tenantList := &openawarenessv1beta1.MimirAlertTenantList{}
+	if err := r.List(ctx, tenantList); err != nil {
+		logger.Error(err, "Failed to list MimirAlertTenants for OperatorConfig watch")
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(tenantList.Items))
+	for _, tenant := range tenantList.Items {
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Name: tenant.Name, Namespace: tenant.Namespace},
+		})
+	}
+
+	logger.V(1).Info("Queueing all MimirAlertTenants due to OperatorConfig change", "count", len(requests))
+
+	return requests
+}
+
+// findAlertTenantsForClient maps ClientConfig changes to MimirAlertTenant reconciliation requests.
+// Tenants referencing it by name in any namespace are enqueued, as a name resolves across
+// namespaces if the tenant's namespace has no ClientConfig of that name, see
+// utils.GetClientConfig, and those referencing it by namespace/name, plus the tenants
+// without client-name annotation using it as default.
+func (r *MimirAlertTenantReconciler) findAlertTenantsForClient(ctx context.Context, obj k8sClient.Object) []reconcile.Request {
+	logger := log.FromContext(ctx)
+
+	clientConfig, ok := obj.(*openawarenessv1beta1.ClientConfig)
+	if !ok {
+		logger.Error(fmt.Errorf("expected ClientConfig but got %T", obj), "Unexpected object type in watch handler")
+		return nil
+	}
+
+	tenantList := &openawarenessv1beta1.MimirAlertTenantList{}
+	for _, value := range utils.ClientNameIndexValues(clientConfig) {
+		referencing := &openawarenessv1beta1.MimirAlertTenantList{}
+		if err := r.List(ctx, referencing, k8sClient.MatchingFields{utils.ClientNameIndexKey: value}); err != nil {
+			logger.Error(err, "Failed to list MimirAlertTenants for ClientConfig watch")
+			return nil
 		}
+		tenantList.Items = append(tenantList.Items, referencing.Items...)
+	}
 
-		// Merge data (later refs override earlier ones)
-		for k, v := range refData {
-			data[k] = v
+	// A default ClientConfig, or one referenced by a TenantMapping in any namespace, is also
+	// used by the tenants without client-name annotation
+	referenced, err := utils.ReferencedByTenantMapping(ctx, r.Client, clientConfig)
+	if err != nil {
+		logger.Error(err, "Failed to list TenantMappings for ClientConfig watch")
+		return nil
+	}
+	if referenced || clientConfig.Spec.Default {
+		opts := utils.DefaultClientListOptions(clientConfig)
+		if referenced {
+			opts = []k8sClient.ListOption{k8sClient.MatchingFields{utils.ClientNameIndexKey: utils.DefaultClientIndexValue}}
 		}
+		defaultTenants := &openawarenessv1beta1.MimirAlertTenantList{}
+		if err := r.List(ctx, defaultTenants, opts...); err != nil {
+			logger.Error(err, "Failed to list MimirAlertTenants using the default ClientConfig")
+			return nil
+		}
+		tenantList.Items = append(tenantList.Items, defaultTenants.Items...)
+	}
+
+	requests := make([]reconcile.Request, 0, len(tenantList.Items))
+	for _, tenant := range tenantList.Items {
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Name:      tenant.Name,
+				Namespace: tenant.Namespace,
+			},
+		})
+		logger.V(1).Info("Queueing MimirAlertTenant reconciliation due to ClientConfig change",
+			"mimirAlertTenant", utils.OwnerReference(&tenant),
+			logging.KeyClient, clientConfig.Name)
 	}
 
-	return data, nil
+	logger.V(1).Info("Found MimirAlertTenants referencing ClientConfig",
+		logging.KeyClient, clientConfig.Name,
+		"count", len(requests))
+
+	return requests
 }
 
-// fetchReferenceData retrieves data from a single ConfigMap or Secret
-func (r *MimirAlertTenantReconciler) fetchReferenceData(
+// findAlertTenantsForTenantMapping maps TenantMapping changes to reconciliation requests of the
+// MimirAlertTenants lacking the client-name or tenant annotation, which a TenantMapping may
+// apply to. Changes of namespace labels are not watched.
+func (r *MimirAlertTenantReconciler) findAlertTenantsForTenantMapping(
 	ctx context.Context,
-	namespace string,
-	ref openawarenessv1beta1.SecretDataReference,
-) (map[string]string, error) {
-	switch ref.Kind {
-	case "ConfigMap":
-		cm := &corev1.ConfigMap{}
-		if err := r.Get(ctx, k8sClient.ObjectKey{
-			Name:      ref.Name,
-			Namespace: namespace,
-		}, cm); err != nil {
-			return nil, err
-		}
-		return cm.Data, nil
+	obj k8sClient.Object,
+) []reconcile.Request {
+	logger := log.FromContext(ctx)
 
-	case "Secret":
-		secret := &corev1.Secret{}
-		if err := r.Get(ctx, k8sClient.ObjectKey{
-			Name:      ref.Name,
-			Namespace: namespace,
-		}, secret); err != nil {
-			return nil, err
+	tenantList := &openawarenessv1beta1.MimirAlertTenantList{}
+	if err := r.List(ctx, tenantList); err != nil {
+		logger.Error(err, "Failed to list MimirAlertTenants for TenantMapping watch")
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for i := range tenantList.Items {
+		tenant := &tenantList.Items[i]
+		if !utils.UsesTenantMapping(tenant) {
+			continue
 		}
-		// Convert []byte to string
-		data := make(map[string]string, len(secret.Data))
-		for k, v := range secret.Data {
-			data[k] = string(v)
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Name: tenant.Name, Namespace: tenant.Namespace},
+		})
+	}
+
+	logger.V(1).Info("Found MimirAlertTenants a TenantMapping may apply to",
+		"tenantMapping", obj.GetName(),
+		"count", len(requests))
+
+	return requests
+}
+
+// findTenantsExtending maps changes of a MimirAlertTenant to reconciliation requests for all
+// tenants extending it, directly or through other tenants.
+func (r *MimirAlertTenantReconciler) findTenantsExtending(
+	ctx context.Context,
+	base *openawarenessv1beta1.MimirAlertTenant,
+) []reconcile.Request {
+	logger := log.FromContext(ctx)
+
+	var requests []reconcile.Request
+	visited := map[string]bool{base.Name: true}
+	for pending := []string{base.Name}; len(pending) > 0; pending = pending[1:] {
+		tenantList := &openawarenessv1beta1.MimirAlertTenantList{}
+		if err := r.List(ctx, tenantList,
+			k8sClient.InNamespace(base.Namespace),
+			k8sClient.MatchingFields{utils.ExtendsIndexKey: pending[0]},
+		); err != nil {
+			logger.Error(err, "Failed to list MimirAlertTenants extending tenant", "mimirAlertTenant", pending[0])
+			return requests
 		}
-		return data, nil
 
-	default:
-		return nil, fmt.Errorf("unsupported reference kind: %s", ref.Kind)
+		for _, tenant := range tenantList.Items {
+			// A cycle is reported by the reconciliation of the tenants in it
+			if visited[tenant.Name] {
+				continue
+			}
+			visited[tenant.Name] = true
+			pending = append(pending, tenant.Name)
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: tenant.Name, Namespace: tenant.Namespace},
+			})
+		}
 	}
+
+	logger.V(1).Info("Found MimirAlertTenants extending tenant",
+		"mimirAlertTenant", utils.OwnerReference(base),
+		"count", len(requests))
+	return requests
 }
 
-// SetupWithManager sets up the controller with the Manager.
-func (r *MimirAlertTenantReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
-		For(&openawarenessv1beta1.MimirAlertTenant{}).
-		Complete(r)
+// findTenantsForRoute maps changes of a MimirAlertRoute to reconciliation requests for its
+// tenant and all tenants extending it.
+func (r *MimirAlertTenantReconciler) findTenantsForRoute(
+	ctx context.Context,
+	route *openawarenessv1beta1.MimirAlertRoute,
+) []reconcile.Request {
+	tenant := types.NamespacedName{Name: route.Spec.Tenant.Name, Namespace: route.Namespace}
+	requests := []reconcile.Request{{NamespacedName: tenant}}
+	return append(requests, r.findTenantsExtending(ctx, &openawarenessv1beta1.MimirAlertTenant{
+		ObjectMeta: metav1.ObjectMeta{Name: tenant.Name, Namespace: tenant.Namespace},
+	})...)
+}
+
+// findTenantsForAlertGlobals maps changes of MimirAlertGlobals to reconciliation requests for
+// their tenant and all tenants extending it.
+func (r *MimirAlertTenantReconciler) findTenantsForAlertGlobals(
+	ctx context.Context,
+	globals *openawarenessv1beta1.MimirAlertGlobals,
+) []reconcile.Request {
+	tenant := types.NamespacedName{Name: globals.Spec.Tenant.Name, Namespace: globals.Namespace}
+	requests := []reconcile.Request{{NamespacedName: tenant}}
+	return append(requests, r.findTenantsExtending(ctx, &openawarenessv1beta1.MimirAlertTenant{
+		ObjectMeta: metav1.ObjectMeta{Name: tenant.Name, Namespace: tenant.Namespace},
+	})...)
+}
+
+// findTenantsForMuteTiming maps changes of MimirMuteTimings to reconciliation requests for
+// their tenant and all tenants extending it.
+func (r *MimirAlertTenantReconciler) findTenantsForMuteTiming(
+	ctx context.Context,
+	timing *openawarenessv1beta1.MimirMuteTiming,
+) []reconcile.Request {
+	tenant := types.NamespacedName{Name: timing.Spec.Tenant.Name, Namespace: timing.Namespace}
+	requests := []reconcile.Request{{NamespacedName: tenant}}
+	return append(requests, r.findTenantsExtending(ctx, &openawarenessv1beta1.MimirAlertTenant{
+		ObjectMeta: metav1.ObjectMeta{Name: tenant.Name, Namespace: tenant.Namespace},
+	})...)
+}
+
+// findTenantsForInhibitRule maps changes of MimirInhibitRules to reconciliation requests for
+// their tenant and all tenants extending it.
+func (r *MimirAlertTenantReconciler) findTenantsForInhibitRule(
+	ctx context.Context,
+	inhibitRule *openawarenessv1beta1.MimirInhibitRule,
+) []reconcile.Request {
+	tenant := types.NamespacedName{Name: inhibitRule.Spec.Tenant.Name, Namespace: inhibitRule.Namespace}
+	requests := []reconcile.Request{{NamespacedName: tenant}}
+	return append(requests, r.findTenantsExtending(ctx, &openawarenessv1beta1.MimirAlertTenant{
+		ObjectMeta: metav1.ObjectMeta{Name: tenant.Name, Namespace: tenant.Namespace},
+	})...)
+}
+
+// findTenantsForConfigSecret maps changes of a Secret to reconciliation requests for the
+// tenants reading their alertmanagerConfig from it and all tenants extending them.
+func (r *MimirAlertTenantReconciler) findTenantsForConfigSecret(
+	ctx context.Context,
+	secret *metav1.PartialObjectMetadata,
+) []reconcile.Request {
+	logger := log.FromContext(ctx)
+
+	tenantList := &openawarenessv1beta1.MimirAlertTenantList{}
+	if err := r.List(ctx, tenantList,
+		k8sClient.InNamespace(secret.Namespace),
+		k8sClient.MatchingFields{utils.ConfigSecretIndexKey: secret.Name},
+	); err != nil {
+		logger.Error(err, "Failed to list MimirAlertTenants reading Secret", "secret", secret.Name)
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for i := range tenantList.Items {
+		tenant := &tenantList.Items[i]
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Name: tenant.Name, Namespace: tenant.Namespace},
+		})
+		requests = append(requests, r.findTenantsExtending(ctx, tenant)...)
+	}
+	return requests
 }
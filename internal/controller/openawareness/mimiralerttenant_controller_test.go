@@ -2,18 +2,24 @@ package openawareness
 
 import (
 	"context"
+	"strings"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"github.com/syndlex/openawareness-controller/test/helper"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	openawarenessv1beta1 "github.com/syndlex/openawareness-controller/api/openawareness/v1beta1"
+	"github.com/syndlex/openawareness-controller/internal/clients"
 	"github.com/syndlex/openawareness-controller/internal/controller/utils"
+	"github.com/syndlex/openawareness-controller/internal/policy"
+	oatesting "github.com/syndlex/openawareness-controller/pkg/testing"
 )
 
 var _ = Describe("MimirAlertTenant Controller", func() {
@@ -94,6 +100,363 @@ receivers:
 			Expect(resource.Finalizers).To(ContainElement(utils.FinalizerAnnotation))
 		})
 
+		It("should block configurations violating the Alertmanager policy", func() {
+			By("Reconciling with a blocking policy and without RulerClients")
+			controllerReconciler := &MimirAlertTenantReconciler{
+				Client: testClient,
+				Scheme: testClient.Scheme(),
+				AlertmanagerPolicy: &policy.AlertmanagerPolicy{
+					Mode:              policy.ModeBlock,
+					MinRepeatInterval: policy.DefaultMinRepeatInterval,
+				},
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			// The sync is stopped before the client lookup
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Checking the policy violation is reported")
+			resource := &openawarenessv1beta1.MimirAlertTenant{}
+			Expect(testClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Status.ConfigurationValidation).To(Equal(openawarenessv1beta1.ConfigValidationInvalid))
+
+			policyCondition := helper.FindCondition(resource.Status.Conditions,
+				openawarenessv1beta1.ConditionTypePolicyViolation)
+			Expect(policyCondition).NotTo(BeNil())
+			Expect(policyCondition.Status).To(Equal(metav1.ConditionTrue))
+			Expect(policyCondition.Message).To(ContainSubstring("severity"))
+
+			readyCondition := helper.FindCondition(resource.Status.Conditions, openawarenessv1beta1.ConditionTypeReady)
+			Expect(readyCondition).NotTo(BeNil())
+			Expect(readyCondition.Reason).To(Equal(openawarenessv1beta1.ReasonPolicyViolation))
+		})
+
+		It("should report tenants extending each other as cycle", func() {
+			By("Creating a tenant extending itself")
+			cyclic := &openawarenessv1beta1.MimirAlertTenant{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "cyclic-alert-tenant",
+					Namespace:   "default",
+					Annotations: map[string]string{utils.MimirTenantAnnotation: "test-tenant"},
+				},
+				Spec: openawarenessv1beta1.MimirAlertTenantSpec{
+					Extends: &openawarenessv1beta1.TenantReference{Name: "cyclic-alert-tenant"},
+				},
+			}
+			Expect(testClient.Create(ctx, cyclic)).To(Succeed())
+			DeferCleanup(func() {
+				Expect(testClient.Delete(ctx, cyclic)).To(Succeed())
+			})
+
+			controllerReconciler := &MimirAlertTenantReconciler{
+				Client: testClient,
+				Scheme: testClient.Scheme(),
+			}
+			cyclicName := types.NamespacedName{Name: cyclic.Name, Namespace: cyclic.Namespace}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: cyclicName})
+			// Retrying does not resolve a cycle
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Checking the cycle is reported")
+			resource := &openawarenessv1beta1.MimirAlertTenant{}
+			Expect(testClient.Get(ctx, cyclicName, resource)).To(Succeed())
+			composedCondition := helper.FindCondition(resource.Status.Conditions,
+				openawarenessv1beta1.ConditionTypeComposed)
+			Expect(composedCondition).NotTo(BeNil())
+			Expect(composedCondition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(composedCondition.Reason).To(Equal(openawarenessv1beta1.ReasonExtendsCycle))
+			Expect(resource.Status.ConfigurationValidation).To(Equal(openawarenessv1beta1.ConfigValidationInvalid))
+		})
+
+		It("should record the diff of a changed configuration", func() {
+			By("Creating the ClientConfig of the tenant")
+			clientConfig := &openawarenessv1beta1.ClientConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-client", Namespace: "default"},
+				Spec: openawarenessv1beta1.ClientConfigSpec{
+					Address: "http://localhost:9009",
+					Type:    openawarenessv1beta1.Mimir,
+				},
+			}
+			Expect(testClient.Create(ctx, clientConfig)).To(Succeed())
+			defer func() { Expect(testClient.Delete(ctx, clientConfig)).To(Succeed()) }()
+
+			recorder := record.NewFakeRecorder(10)
+			controllerReconciler := &MimirAlertTenantReconciler{
+				Client:       testClient,
+				Scheme:       testClient.Scheme(),
+				RulerClients: clients.NewMockRulerClientCache(),
+				Recorder:     recorder,
+			}
+			request := reconcile.Request{NamespacedName: typeNamespacedName}
+
+			By("Pushing the initial configuration without diff")
+			_, err := controllerReconciler.Reconcile(ctx, request)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(recorder.Events).To(BeEmpty())
+
+			By("Changing the receiver address")
+			resource := &openawarenessv1beta1.MimirAlertTenant{}
+			Expect(testClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			resource.Spec.AlertmanagerConfig = strings.Replace(resource.Spec.AlertmanagerConfig,
+				"team@example.org", "oncall@example.org", 1)
+			Expect(testClient.Update(ctx, resource)).To(Succeed())
+
+			_, err = controllerReconciler.Reconcile(ctx, request)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(recorder.Events).To(Receive(SatisfyAll(
+				ContainSubstring("ConfigurationChanged"),
+				ContainSubstring("+      - to: 'oncall@example.org'"),
+			)))
+
+			Expect(testClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Status.LastConfigDiff).To(ContainSubstring("-      - to: 'team@example.org'"))
+
+			By("Removing the configuration and the finalizer on deletion")
+			Expect(testClient.Delete(ctx, resource)).To(Succeed())
+			_, err = controllerReconciler.Reconcile(ctx, request)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should push, repair drift and delete against a fake Mimir", func() {
+			By("Creating a ClientConfig for an in-process fake Mimir")
+			mimirServer := oatesting.NewFakeMimir()
+			defer mimirServer.Close()
+			clientConfig := &openawarenessv1beta1.ClientConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-client", Namespace: "default"},
+				Spec: openawarenessv1beta1.ClientConfigSpec{
+					Address: mimirServer.URL,
+					Type:    openawarenessv1beta1.Mimir,
+				},
+			}
+			Expect(testClient.Create(ctx, clientConfig)).To(Succeed())
+			defer func() { Expect(testClient.Delete(ctx, clientConfig)).To(Succeed()) }()
+
+			controllerReconciler := &MimirAlertTenantReconciler{
+				Client:       testClient,
+				Scheme:       testClient.Scheme(),
+				RulerClients: clients.NewRulerClientCache(),
+			}
+			request := reconcile.Request{NamespacedName: typeNamespacedName}
+
+			By("Pushing the configuration")
+			_, err := controllerReconciler.Reconcile(ctx, request)
+			Expect(err).NotTo(HaveOccurred())
+			config, templates, ok := mimirServer.AlertmanagerConfig("test-tenant")
+			Expect(ok).To(BeTrue())
+			Expect(config).To(ContainSubstring("team@example.org"))
+			Expect(templates).To(HaveKey("default"))
+
+			By("Restoring a configuration changed outside of the controller")
+			mimirServer.SetAlertmanagerConfig("test-tenant", "route:\n  receiver: manual\n", nil)
+			_, err = controllerReconciler.Reconcile(ctx, request)
+			Expect(err).NotTo(HaveOccurred())
+			config, _, _ = mimirServer.AlertmanagerConfig("test-tenant")
+			Expect(config).To(ContainSubstring("team@example.org"))
+
+			By("Reporting server errors in the status")
+			mimirServer.FailWith(503, "ingester unavailable")
+			_, err = controllerReconciler.Reconcile(ctx, request)
+			Expect(err).To(HaveOccurred())
+			resource := &openawarenessv1beta1.MimirAlertTenant{}
+			Expect(testClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Status.SyncStatus).To(Equal(openawarenessv1beta1.SyncStatusFailed))
+			readyCondition := helper.FindCondition(resource.Status.Conditions, openawarenessv1beta1.ConditionTypeReady)
+			Expect(readyCondition).NotTo(BeNil())
+			Expect(readyCondition.Reason).To(Equal(openawarenessv1beta1.ReasonServerError))
+			Expect(readyCondition.Message).To(ContainSubstring("ingester unavailable"))
+			mimirServer.FailWith(0, "")
+
+			By("Removing the configuration on confirmed deletion")
+			Expect(testClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			if resource.Annotations == nil {
+				resource.Annotations = map[string]string{}
+			}
+			resource.Annotations[utils.ConfirmDeleteAnnotation] = "true"
+			Expect(testClient.Update(ctx, resource)).To(Succeed())
+			Expect(testClient.Delete(ctx, resource)).To(Succeed())
+			_, err = controllerReconciler.Reconcile(ctx, request)
+			Expect(err).NotTo(HaveOccurred())
+			_, _, ok = mimirServer.AlertmanagerConfig("test-tenant")
+			Expect(ok).To(BeFalse())
+		})
+
+		It("should retain the configuration of an unconfirmed deletion", func() {
+			By("Creating a ClientConfig for an in-process fake Mimir")
+			mimirServer := oatesting.NewFakeMimir()
+			defer mimirServer.Close()
+			clientConfig := &openawarenessv1beta1.ClientConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-client", Namespace: "default"},
+				Spec: openawarenessv1beta1.ClientConfigSpec{
+					Address: mimirServer.URL,
+					Type:    openawarenessv1beta1.Mimir,
+				},
+			}
+			Expect(testClient.Create(ctx, clientConfig)).To(Succeed())
+			defer func() { Expect(testClient.Delete(ctx, clientConfig)).To(Succeed()) }()
+
+			recorder := record.NewFakeRecorder(10)
+			controllerReconciler := &MimirAlertTenantReconciler{
+				Client:       testClient,
+				Scheme:       testClient.Scheme(),
+				RulerClients: clients.NewRulerClientCache(),
+				Recorder:     recorder,
+			}
+			request := reconcile.Request{NamespacedName: typeNamespacedName}
+			_, err := controllerReconciler.Reconcile(ctx, request)
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Deleting the tenant without confirmation")
+			resource := &openawarenessv1beta1.MimirAlertTenant{}
+			Expect(testClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(testClient.Delete(ctx, resource)).To(Succeed())
+			_, err = controllerReconciler.Reconcile(ctx, request)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, _, ok := mimirServer.AlertmanagerConfig("test-tenant")
+			Expect(ok).To(BeTrue())
+			Expect(recorder.Events).To(Receive(SatisfyAll(
+				ContainSubstring("RemoteDataRetained"),
+				ContainSubstring(utils.ConfirmDeleteAnnotation),
+			)))
+			Expect(errors.IsNotFound(testClient.Get(ctx, typeNamespacedName, resource))).To(BeTrue())
+		})
+
+		It("should refuse tenants not allowed by the ClientConfig", func() {
+			By("Creating a ClientConfig allowing another tenant only")
+			clientConfig := &openawarenessv1beta1.ClientConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-client", Namespace: "default"},
+				Spec: openawarenessv1beta1.ClientConfigSpec{
+					Address:        "http://localhost:9009",
+					Type:           openawarenessv1beta1.Mimir,
+					AllowedTenants: []string{"other-tenant"},
+				},
+			}
+			Expect(testClient.Create(ctx, clientConfig)).To(Succeed())
+			defer func() { Expect(testClient.Delete(ctx, clientConfig)).To(Succeed()) }()
+
+			mockClient := clients.NewMockAwarenessClient()
+			ruleClients := clients.NewMockRulerClientCache()
+			ruleClients.SetClient("test-client", mockClient)
+			controllerReconciler := &MimirAlertTenantReconciler{
+				Client:       testClient,
+				Scheme:       testClient.Scheme(),
+				RulerClients: ruleClients,
+			}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			// Retrying does not allow the tenant
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Checking the push is refused")
+			resource := &openawarenessv1beta1.MimirAlertTenant{}
+			Expect(testClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Status.SyncStatus).To(Equal(openawarenessv1beta1.SyncStatusFailed))
+			readyCondition := helper.FindCondition(resource.Status.Conditions, openawarenessv1beta1.ConditionTypeReady)
+			Expect(readyCondition).NotTo(BeNil())
+			Expect(readyCondition.Reason).To(Equal(openawarenessv1beta1.ReasonTenantNotAllowed))
+			Expect(readyCondition.Message).To(ContainSubstring("test-tenant"))
+			pushed, _, err := mockClient.GetAlertmanagerConfig(ctx, "test-tenant")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(pushed).To(BeEmpty())
+		})
+
+		It("should not sync tenants exceeding the quota", func() {
+			By("Creating a newer tenant in the same namespace")
+			newer := &openawarenessv1beta1.MimirAlertTenant{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-newer-tenant", Namespace: "default"},
+				Spec: openawarenessv1beta1.MimirAlertTenantSpec{
+					AlertmanagerConfig: "route:\n  receiver: default\nreceivers:\n  - name: default\n",
+				},
+			}
+			// Creation timestamps have a resolution of seconds
+			time.Sleep(time.Second)
+			Expect(testClient.Create(ctx, newer)).To(Succeed())
+			defer func() { Expect(testClient.Delete(ctx, newer)).To(Succeed()) }()
+
+			controllerReconciler := &MimirAlertTenantReconciler{
+				Client: testClient,
+				Scheme: testClient.Scheme(),
+				Quota:  &policy.TenantQuota{MaxPerNamespace: 1},
+			}
+			result, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: newer.Name, Namespace: newer.Namespace},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			// Deleting the older tenant requeues the newer one
+			Expect(result.RequeueAfter).To(BeZero())
+
+			By("Checking the newer tenant exceeds the namespace quota")
+			resource := &openawarenessv1beta1.MimirAlertTenant{}
+			Expect(testClient.Get(ctx, types.NamespacedName{Name: newer.Name, Namespace: newer.Namespace}, resource)).To(Succeed())
+			Expect(resource.Status.ConfigurationValidation).To(Equal(openawarenessv1beta1.ConfigValidationInvalid))
+			readyCondition := helper.FindCondition(resource.Status.Conditions, openawarenessv1beta1.ConditionTypeReady)
+			Expect(readyCondition).NotTo(BeNil())
+			Expect(readyCondition.Reason).To(Equal(openawarenessv1beta1.ReasonQuotaExceeded))
+			Expect(readyCondition.Message).To(ContainSubstring("namespace default has 2 MimirAlertTenants"))
+
+			By("Checking the configuration size quota")
+			controllerReconciler.Quota = &policy.TenantQuota{MaxConfigBytes: 16}
+			result, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeZero())
+			Expect(testClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			readyCondition = helper.FindCondition(resource.Status.Conditions, openawarenessv1beta1.ConditionTypeReady)
+			Expect(readyCondition).NotTo(BeNil())
+			Expect(readyCondition.Reason).To(Equal(openawarenessv1beta1.ReasonQuotaExceeded))
+			Expect(readyCondition.Message).To(ContainSubstring("at most 16 are allowed"))
+		})
+
+		It("should report merged and rejected MimirAlertRoutes", func() {
+			By("Creating a route and a route reusing the receiver of the tenant")
+			routes := []*openawarenessv1beta1.MimirAlertRoute{{
+				ObjectMeta: metav1.ObjectMeta{Name: "payments-route", Namespace: "default"},
+				Spec: openawarenessv1beta1.MimirAlertRouteSpec{
+					Tenant:    openawarenessv1beta1.TenantReference{Name: resourceName},
+					Matchers:  []string{`team="payments"`},
+					Route:     "receiver: payments",
+					Receivers: "- name: payments",
+				},
+			}, {
+				ObjectMeta: metav1.ObjectMeta{Name: "duplicate-route", Namespace: "default"},
+				Spec: openawarenessv1beta1.MimirAlertRouteSpec{
+					Tenant:    openawarenessv1beta1.TenantReference{Name: resourceName},
+					Matchers:  []string{`team="search"`},
+					Receivers: "- name: default",
+				},
+			}}
+			for _, route := range routes {
+				Expect(testClient.Create(ctx, route)).To(Succeed())
+				DeferCleanup(func() {
+					Expect(testClient.Delete(ctx, route)).To(Succeed())
+				})
+			}
+
+			controllerReconciler := &MimirAlertTenantReconciler{
+				Client: testClient,
+				Scheme: testClient.Scheme(),
+			}
+			// Routes are merged before the client lookup, which fails without RulerClients
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).To(HaveOccurred())
+
+			By("Checking the status of the routes")
+			merged := &openawarenessv1beta1.MimirAlertRoute{}
+			Expect(testClient.Get(ctx, types.NamespacedName{Name: "payments-route", Namespace: "default"},
+				merged)).To(Succeed())
+			mergedCondition := helper.FindCondition(merged.Status.Conditions, openawarenessv1beta1.ConditionTypeReady)
+			Expect(mergedCondition).NotTo(BeNil())
+			Expect(mergedCondition.Status).To(Equal(metav1.ConditionTrue))
+
+			rejected := &openawarenessv1beta1.MimirAlertRoute{}
+			Expect(testClient.Get(ctx, types.NamespacedName{Name: "duplicate-route", Namespace: "default"},
+				rejected)).To(Succeed())
+			rejectedCondition := helper.FindCondition(rejected.Status.Conditions, openawarenessv1beta1.ConditionTypeReady)
+			Expect(rejectedCondition).NotTo(BeNil())
+			Expect(rejectedCondition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(rejectedCondition.Reason).To(Equal(openawarenessv1beta1.ReasonDuplicateReceiver))
+		})
+
 		It("should successfully process the resource (verification test)", func() {
 			By("Getting the created resource")
 			resource := &openawarenessv1beta1.MimirAlertTenant{}
@@ -197,6 +560,76 @@ receivers:
 			Expect(syncedCondition.Status).To(Equal(metav1.ConditionTrue))
 		})
 
+		It("should diff the configuration against Mimir in read-only mode", func() {
+			resource := &openawarenessv1beta1.MimirAlertTenant{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "read-only-tenant",
+					Namespace:   "default",
+					Annotations: map[string]string{utils.MimirTenantAnnotation: "team-a"},
+				},
+			}
+			mockClient := clients.NewMockAwarenessClient()
+			state := &utils.SyncState[*openawarenessv1beta1.MimirAlertTenant]{
+				Object:       resource,
+				ClientConfig: &openawarenessv1beta1.ClientConfig{},
+				ReadOnly:     true,
+			}
+			sync := &mimirAlertTenantSync{r: &MimirAlertTenantReconciler{}}
+			rendered := renderedAlertmanagerConfig{config: "route:\n  receiver: default\n"}
+
+			Expect(sync.Diff(ctx, state, mockClient, rendered)).To(ContainSubstring("would be created"))
+			Expect(mockClient.CreateAlertmanagerConfig(ctx, "route:\n  receiver: other\n", nil, "team-a")).To(Succeed())
+			diff, err := sync.Diff(ctx, state, mockClient, rendered)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(diff).To(ContainSubstring("+  receiver: default"))
+			Expect(mockClient.CreateAlertmanagerConfig(ctx,
+				utils.AddManagedByHeader(rendered.config, "MimirAlertTenant", resource, ""), nil, "team-a")).To(Succeed())
+			Expect(sync.Diff(ctx, state, mockClient, rendered)).To(BeEmpty())
+		})
+
+		It("should record the resolved tenant and endpoint", func() {
+			resource := &openawarenessv1beta1.MimirAlertTenant{}
+			clientConfig := &openawarenessv1beta1.ClientConfig{
+				ObjectMeta: metav1.ObjectMeta{Generation: 2},
+				Spec: openawarenessv1beta1.ClientConfigSpec{
+					Address:    "https://mimir.example.org",
+					PathPrefix: "/prometheus/",
+				},
+			}
+
+			resource.SetResolvedTarget("team-a", clientConfig)
+			Expect(resource.Status.ResolvedTenant).To(Equal("team-a"))
+			Expect(resource.Status.ResolvedEndpoint).To(Equal("https://mimir.example.org/prometheus"))
+			Expect(resource.Status.ClientConfigGeneration).To(BeEquivalentTo(2))
+
+			By("Clearing the endpoint without a ClientConfig")
+			resource.SetResolvedTarget("team-a", nil)
+			Expect(resource.Status.ResolvedEndpoint).To(BeEmpty())
+			Expect(resource.Status.ClientConfigGeneration).To(BeZero())
+		})
+
+		It("should set fallback condition correctly", func() {
+			resource := &openawarenessv1beta1.MimirAlertTenant{}
+
+			resource.SetFallbackCondition("team-a")
+
+			By("Verifying sync status is Fallback")
+			Expect(resource.Status.SyncStatus).To(Equal(openawarenessv1beta1.SyncStatusFallback))
+			Expect(resource.Status.LastSyncTime).NotTo(BeNil())
+
+			By("Verifying Ready condition is False")
+			readyCondition := helper.FindCondition(resource.Status.Conditions, openawarenessv1beta1.ConditionTypeReady)
+			Expect(readyCondition).NotTo(BeNil())
+			Expect(readyCondition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(readyCondition.Reason).To(Equal(openawarenessv1beta1.ReasonFallbackConfig))
+			Expect(readyCondition.Message).To(ContainSubstring("team-a"))
+
+			By("Verifying Synced condition is True")
+			syncedCondition := helper.FindCondition(resource.Status.Conditions, openawarenessv1beta1.ConditionTypeSynced)
+			Expect(syncedCondition).NotTo(BeNil())
+			Expect(syncedCondition.Status).To(Equal(metav1.ConditionTrue))
+		})
+
 		It("should set failed condition correctly", func() {
 			resource := &openawarenessv1beta1.MimirAlertTenant{}
 
@@ -251,6 +684,66 @@ receivers:
 			Expect(syncedCondition.Status).To(Equal(metav1.ConditionFalse))
 		})
 
+		It("should set paused condition correctly", func() {
+			resource := &openawarenessv1beta1.MimirAlertTenant{}
+
+			resource.SetPausedCondition()
+
+			By("Verifying sync status is Paused")
+			Expect(resource.Status.SyncStatus).To(Equal(openawarenessv1beta1.SyncStatusPaused))
+
+			By("Verifying ConfigurationValidation is Valid")
+			Expect(resource.Status.ConfigurationValidation).To(Equal(openawarenessv1beta1.ConfigValidationValid))
+
+			By("Verifying Paused condition is True")
+			pausedCondition := helper.FindCondition(resource.Status.Conditions, openawarenessv1beta1.ConditionTypePaused)
+			Expect(pausedCondition).NotTo(BeNil())
+			Expect(pausedCondition.Status).To(Equal(metav1.ConditionTrue))
+			Expect(pausedCondition.Reason).To(Equal(openawarenessv1beta1.ReasonPaused))
+		})
+
+		It("should set policy violation condition correctly", func() {
+			resource := &openawarenessv1beta1.MimirAlertTenant{}
+
+			By("Recording violations")
+			resource.SetPolicyViolationCondition([]string{"route: a default receiver is required"})
+			policyCondition := helper.FindCondition(resource.Status.Conditions,
+				openawarenessv1beta1.ConditionTypePolicyViolation)
+			Expect(policyCondition).NotTo(BeNil())
+			Expect(policyCondition.Status).To(Equal(metav1.ConditionTrue))
+			Expect(policyCondition.Reason).To(Equal(openawarenessv1beta1.ReasonPolicyViolation))
+			Expect(policyCondition.Message).To(ContainSubstring("a default receiver is required"))
+
+			By("Clearing violations")
+			resource.SetPolicyViolationCondition(nil)
+			policyCondition = helper.FindCondition(resource.Status.Conditions,
+				openawarenessv1beta1.ConditionTypePolicyViolation)
+			Expect(policyCondition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(policyCondition.Reason).To(Equal(openawarenessv1beta1.ReasonPolicyCompliant))
+		})
+
+		It("should keep the transition time of conditions whose status does not change", func() {
+			resource := &openawarenessv1beta1.MimirAlertTenant{}
+			resource.Generation = 1
+			resource.SetConfigInvalidCondition(openawarenessv1beta1.ReasonInvalidYAML, "Invalid YAML")
+			readyCondition := helper.FindCondition(resource.Status.Conditions, openawarenessv1beta1.ConditionTypeReady)
+			transition := metav1.NewTime(readyCondition.LastTransitionTime.Add(-time.Hour))
+			readyCondition.LastTransitionTime = transition
+
+			By("Setting another failure of the same status for a new generation")
+			resource.Generation = 2
+			resource.SetFailedCondition(openawarenessv1beta1.ReasonNetworkError, "Network error")
+			readyCondition = helper.FindCondition(resource.Status.Conditions, openawarenessv1beta1.ConditionTypeReady)
+			Expect(readyCondition.LastTransitionTime).To(Equal(transition))
+			Expect(readyCondition.Reason).To(Equal(openawarenessv1beta1.ReasonNetworkError))
+			Expect(readyCondition.ObservedGeneration).To(Equal(int64(2)))
+
+			By("Changing the status")
+			resource.SetSyncedCondition()
+			readyCondition = helper.FindCondition(resource.Status.Conditions, openawarenessv1beta1.ConditionTypeReady)
+			Expect(readyCondition.LastTransitionTime.After(transition.Time)).To(BeTrue())
+		})
+
 		It("should update existing conditions rather than duplicate", func() {
 			resource := &openawarenessv1beta1.MimirAlertTenant{}
 
@@ -0,0 +1,77 @@
+package openawareness
+
+import (
+	"context"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	k8sClient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	openawarenessv1beta1 "github.com/syndlex/openawareness-controller/api/openawareness/v1beta1"
+	"github.com/syndlex/openawareness-controller/internal/controller/utils"
+	"github.com/syndlex/openawareness-controller/internal/logging"
+	"github.com/syndlex/openawareness-controller/internal/operatorconfig"
+)
+
+// OperatorConfigReconciler reconciles an OperatorConfig object
+type OperatorConfigReconciler struct {
+	k8sClient.Client
+	Scheme *runtime.Scheme
+}
+
+// +kubebuilder:rbac:groups=openawareness.syndlex,resources=operatorconfigs,verbs=get;list;watch
+// +kubebuilder:rbac:groups=openawareness.syndlex,resources=operatorconfigs/status,verbs=get;update;patch
+
+// Reconcile reports in the Ready condition of an OperatorConfig whether the controllers apply
+// its settings. The settings themselves are read by the PrometheusRule and MimirAlertTenant
+// controllers on every reconciliation, see operatorconfig.Source.
+//
+// The reconciliation process:
+// 1. Fetches the OperatorConfig resource
+// 2. Ignores OperatorConfigs not named openawarenessv1beta1.OperatorConfigName
+// 3. Validates the settings, invalid OperatorConfigs are ignored as a whole
+// 4. Updates the Ready condition
+func (r *OperatorConfigReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	config := &openawarenessv1beta1.OperatorConfig{}
+	if err := r.Get(ctx, req.NamespacedName, config); err != nil {
+		return ctrl.Result{}, k8sClient.IgnoreNotFound(err)
+	}
+	original := config.DeepCopy()
+
+	switch _, err := operatorconfig.Merge(operatorconfig.Settings{}, config.Spec); {
+	case config.Name != openawarenessv1beta1.OperatorConfigName:
+		setReadyCondition(&config.Status.Conditions, config.Generation,
+			metav1.ConditionFalse, openawarenessv1beta1.ReasonIgnoredName,
+			fmt.Sprintf("Only the OperatorConfig named %s is read by the controllers",
+				openawarenessv1beta1.OperatorConfigName))
+	case err != nil:
+		logger.Error(err, "Invalid OperatorConfig, the controllers keep their default settings")
+		setReadyCondition(&config.Status.Conditions, config.Generation,
+			metav1.ConditionFalse, openawarenessv1beta1.ReasonInvalidSettings, err.Error())
+	default:
+		logger.Info("OperatorConfig settings applied")
+		setReadyCondition(&config.Status.Conditions, config.Generation,
+			metav1.ConditionTrue, openawarenessv1beta1.ReasonSettingsApplied,
+			"Settings are applied to the controllers")
+	}
+
+	if err := utils.PatchStatus(ctx, r.Client, config, original); err != nil {
+		logger.Error(err, "Failed to update status")
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *OperatorConfigReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&openawarenessv1beta1.OperatorConfig{}).
+		WithOptions(controller.Options{LogConstructor: logging.LogConstructor(mgr.GetLogger(), "operatorconfig")}).
+		Complete(logging.TrackAttempts(r))
+}
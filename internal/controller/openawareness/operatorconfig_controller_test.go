@@ -0,0 +1,80 @@
+package openawareness
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	openawarenessv1beta1 "github.com/syndlex/openawareness-controller/api/openawareness/v1beta1"
+)
+
+var _ = Describe("OperatorConfig Controller", func() {
+	var (
+		ctx        context.Context
+		reconciler *OperatorConfigReconciler
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		reconciler = &OperatorConfigReconciler{
+			Client: testClient,
+			Scheme: testClient.Scheme(),
+		}
+	})
+
+	reconcileReady := func(config *openawarenessv1beta1.OperatorConfig) *metav1.Condition {
+		Expect(testClient.Create(ctx, config)).To(Succeed())
+		DeferCleanup(func() { Expect(testClient.Delete(ctx, config)).To(Succeed()) })
+
+		key := types.NamespacedName{Name: config.Name}
+		_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(testClient.Get(ctx, key, config)).To(Succeed())
+		return meta.FindStatusCondition(config.Status.Conditions, openawarenessv1beta1.ConditionTypeReady)
+	}
+
+	It("should report valid settings as applied", func() {
+		condition := reconcileReady(&openawarenessv1beta1.OperatorConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: openawarenessv1beta1.OperatorConfigName},
+			Spec: openawarenessv1beta1.OperatorConfigSpec{
+				SyncTimeout: &metav1.Duration{Duration: 30 * time.Second},
+				RulePolicy:  &openawarenessv1beta1.RulePolicySpec{Mode: "block"},
+			},
+		})
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Reason).To(Equal(openawarenessv1beta1.ReasonSettingsApplied))
+	})
+
+	It("should report invalid settings", func() {
+		condition := reconcileReady(&openawarenessv1beta1.OperatorConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: openawarenessv1beta1.OperatorConfigName},
+			Spec: openawarenessv1beta1.OperatorConfigSpec{
+				RuleSelector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{{
+					Key:      "team",
+					Operator: "Unknown",
+				}}},
+			},
+		})
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal(openawarenessv1beta1.ReasonInvalidSettings))
+		Expect(condition.Message).To(ContainSubstring("ruleSelector"))
+	})
+
+	It("should ignore OperatorConfigs with another name", func() {
+		condition := reconcileReady(&openawarenessv1beta1.OperatorConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: "other"},
+		})
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal(openawarenessv1beta1.ReasonIgnoredName))
+	})
+})
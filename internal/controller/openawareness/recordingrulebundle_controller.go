@@ -0,0 +1,417 @@
+package openawareness
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	k8sClient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/yaml"
+
+	openawarenessv1beta1 "github.com/syndlex/openawareness-controller/api/openawareness/v1beta1"
+	"github.com/syndlex/openawareness-controller/internal/controller/utils"
+	"github.com/syndlex/openawareness-controller/internal/logging"
+)
+
+const (
+	// libraryConfigMapIndexKey indexes RecordingRuleBundles by the ConfigMap holding their library
+	libraryConfigMapIndexKey = ".spec.library.configMapRef.name"
+	// recordingRuleBundleLabel identifies the RecordingRuleBundle a generated PrometheusRule belongs to
+	recordingRuleBundleLabel = "openawareness.io/recording-rule-bundle"
+	// recordingRuleBundleNamespaceLabel is the namespace of the RecordingRuleBundle a generated
+	// PrometheusRule belongs to, which may differ from the namespace of the PrometheusRule
+	recordingRuleBundleNamespaceLabel = "openawareness.io/recording-rule-bundle-namespace"
+	// recordingRuleVersionLabel is the library version a generated PrometheusRule was generated from
+	recordingRuleVersionLabel = "openawareness.io/recording-rule-version"
+	// maxGeneratedRuleNameLength is the maximum length of the name of a generated PrometheusRule
+	maxGeneratedRuleNameLength = 253
+)
+
+var (
+	// errLibraryNotFound is returned when the ConfigMap or key holding a library does not exist
+	errLibraryNotFound = errors.New("recording rule library not found")
+	// errInvalidLibrary is returned when a library cannot be expanded into recording rule groups
+	errInvalidLibrary = errors.New("invalid recording rule library")
+	// errGeneratedRuleConflict is returned when the PrometheusRule to generate exists and does not
+	// belong to the bundle
+	errGeneratedRuleConflict = errors.New("PrometheusRule not generated by this RecordingRuleBundle")
+)
+
+// RecordingRuleBundleReconciler reconciles a RecordingRuleBundle object
+type RecordingRuleBundleReconciler struct {
+	k8sClient.Client
+	Scheme *runtime.Scheme
+	// ReadOnly adds no finalizers, the PrometheusRules generated for deleted bundles are left
+	// behind. The generated PrometheusRules are still synced by the read-only PrometheusRule
+	// controller, which does not push them
+	ReadOnly bool
+}
+
+//nolint:lll
+// +kubebuilder:rbac:groups=openawareness.syndlex,resources=recordingrulebundles,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=openawareness.syndlex,resources=recordingrulebundles/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=openawareness.syndlex,resources=recordingrulebundles/finalizers,verbs=update
+// +kubebuilder:rbac:groups=openawareness.syndlex,resources=mimiralerttenants,verbs=get;list;watch
+// +kubebuilder:rbac:groups=monitoring.coreos.com,resources=prometheusrules,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch
+
+// Reconcile expands the recording rule library of a RecordingRuleBundle into a PrometheusRule
+// per selected MimirAlertTenant. Each PrometheusRule is created in the namespace of its tenant
+// and carries the tenant's client and tenant annotations, so the PrometheusRules controller
+// pushes the groups to the Mimir tenant of the MimirAlertTenant.
+//
+// The reconciliation process:
+// 1. Fetches the RecordingRuleBundle and handles its finalizer, which deletes the generated rules
+// 2. Reads and validates the library, inline or from a ConfigMap
+// 3. Creates or updates the PrometheusRule of every selected MimirAlertTenant
+// 4. Deletes the generated PrometheusRules of tenants no longer selected
+// 5. Updates status with the library version and the number of tenants
+func (r *RecordingRuleBundleReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	bundle := &openawarenessv1beta1.RecordingRuleBundle{}
+	if err := r.Get(ctx, req.NamespacedName, bundle); err != nil {
+		return ctrl.Result{}, k8sClient.IgnoreNotFound(err)
+	}
+	original := bundle.DeepCopy()
+
+	// Generated PrometheusRules live in the namespaces of the tenants and cannot be owned by the bundle
+	var err error
+	isDeleting := !bundle.DeletionTimestamp.IsZero()
+	if !r.ReadOnly {
+		isDeleting, err = utils.HandleFinalizer(ctx, r.Client, bundle, utils.FinalizerAnnotation,
+			func(ctx context.Context) error {
+				logger.Info("Deleting generated PrometheusRules of RecordingRuleBundle")
+				return r.deleteGeneratedRules(ctx, bundle, nil)
+			})
+	}
+	if err != nil {
+		logger.Error(err, "Failed to handle finalizer")
+		return ctrl.Result{}, err
+	}
+	if isDeleting {
+		return ctrl.Result{}, nil
+	}
+
+	groups, err := r.libraryGroups(ctx, bundle)
+	if err != nil {
+		logger.Error(err, "Failed to read recording rule library")
+		switch {
+		case errors.Is(err, errLibraryNotFound):
+			// The ConfigMap watch triggers a new reconciliation once the library exists
+			return ctrl.Result{}, r.setFailed(ctx, bundle, original, openawarenessv1beta1.ReasonLibraryNotFound, err.Error())
+		case errors.Is(err, errInvalidLibrary):
+			return ctrl.Result{}, r.setFailed(ctx, bundle, original, openawarenessv1beta1.ReasonInvalidLibrary, err.Error())
+		default:
+			return ctrl.Result{}, err
+		}
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(&bundle.Spec.TenantSelector)
+	if err != nil {
+		return ctrl.Result{}, r.setFailed(ctx, bundle, original, openawarenessv1beta1.ReasonInvalidTenantSelector,
+			fmt.Sprintf("invalid tenantSelector: %v", err))
+	}
+	tenants := &openawarenessv1beta1.MimirAlertTenantList{}
+	if err := r.List(ctx, tenants, k8sClient.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return ctrl.Result{}, fmt.Errorf("listing MimirAlertTenants: %w", err)
+	}
+
+	generated := map[types.NamespacedName]bool{}
+	for i := range tenants.Items {
+		tenant := &tenants.Items[i]
+		if !tenant.DeletionTimestamp.IsZero() {
+			continue
+		}
+		key, err := r.generateRule(ctx, bundle, tenant, groups)
+		if errors.Is(err, errGeneratedRuleConflict) {
+			return ctrl.Result{}, r.setFailed(ctx, bundle, original, openawarenessv1beta1.ReasonConflict, err.Error())
+		}
+		if err != nil {
+			logger.Error(err, "Failed to create or update PrometheusRule for RecordingRuleBundle",
+				"mimirAlertTenant", utils.OwnerReference(tenant))
+			return ctrl.Result{}, err
+		}
+		generated[key] = true
+	}
+
+	if err := r.deleteGeneratedRules(ctx, bundle, generated); err != nil {
+		logger.Error(err, "Failed to delete PrometheusRules of tenants no longer selected")
+		return ctrl.Result{}, err
+	}
+
+	logging.SuccessInfo(logger, utils.OwnerReference(bundle), "Generated recording rules of RecordingRuleBundle",
+		"version", bundle.Spec.Library.Version,
+		"tenants", len(generated))
+
+	bundle.Status.Version = bundle.Spec.Library.Version
+	bundle.Status.Tenants = int32(len(generated))
+	setReadyCondition(&bundle.Status.Conditions, bundle.Generation,
+		metav1.ConditionTrue, openawarenessv1beta1.ReasonRulesGenerated,
+		fmt.Sprintf("Recording rules of library version %s generated for %d tenants",
+			bundle.Spec.Library.Version, len(generated)))
+	if err := utils.PatchStatus(ctx, r.Client, bundle, original); err != nil {
+		logger.Error(err, "Failed to update status")
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// setFailed sets the Ready condition to False and patches the status against original.
+// Returns the status update error, if any.
+func (r *RecordingRuleBundleReconciler) setFailed(
+	ctx context.Context,
+	bundle, original *openawarenessv1beta1.RecordingRuleBundle,
+	reason, message string,
+) error {
+	setReadyCondition(&bundle.Status.Conditions, bundle.Generation, metav1.ConditionFalse, reason, message)
+	if err := utils.PatchStatus(ctx, r.Client, bundle, original); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to update status")
+		return err
+	}
+	return nil
+}
+
+// libraryGroups reads the library of the bundle, inline or from its ConfigMap, and parses it.
+// Returns an error wrapping errLibraryNotFound if the ConfigMap or key does not exist, and
+// errInvalidLibrary if the library cannot be parsed.
+func (r *RecordingRuleBundleReconciler) libraryGroups(
+	ctx context.Context,
+	bundle *openawarenessv1beta1.RecordingRuleBundle,
+) ([]monitoringv1.RuleGroup, error) {
+	library := bundle.Spec.Library
+	content := library.Groups
+	if ref := library.ConfigMapRef; ref != nil {
+		configMap := &corev1.ConfigMap{}
+		if err := r.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: bundle.Namespace}, configMap); err != nil {
+			if apierrors.IsNotFound(err) {
+				return nil, fmt.Errorf("%w: ConfigMap %s does not exist", errLibraryNotFound, ref.Name)
+			}
+			return nil, err
+		}
+		var ok bool
+		if content, ok = configMap.Data[ref.Key]; !ok {
+			return nil, fmt.Errorf("%w: key %s not found in ConfigMap %s", errLibraryNotFound, ref.Key, ref.Name)
+		}
+	}
+	return parseRecordingRuleLibrary(content)
+}
+
+// parseRecordingRuleLibrary parses the rule groups of a library. Every group must be named
+// uniquely and hold recording rules only. Returns an error wrapping errInvalidLibrary otherwise.
+func parseRecordingRuleLibrary(content string) ([]monitoringv1.RuleGroup, error) {
+	var groups []monitoringv1.RuleGroup
+	if err := yaml.UnmarshalStrict([]byte(content), &groups); err != nil {
+		return nil, fmt.Errorf("%w: %w", errInvalidLibrary, err)
+	}
+	if len(groups) == 0 {
+		return nil, fmt.Errorf("%w: library contains no rule groups", errInvalidLibrary)
+	}
+
+	names := map[string]bool{}
+	for i, group := range groups {
+		if group.Name == "" {
+			return nil, fmt.Errorf("%w: rule group %d has no name", errInvalidLibrary, i)
+		}
+		if names[group.Name] {
+			return nil, fmt.Errorf("%w: duplicate rule group %s", errInvalidLibrary, group.Name)
+		}
+		names[group.Name] = true
+		for j, rule := range group.Rules {
+			if rule.Alert != "" || rule.Record == "" {
+				return nil, fmt.Errorf("%w: rule %d of group %s is not a recording rule", errInvalidLibrary, j, group.Name)
+			}
+		}
+	}
+	return groups, nil
+}
+
+// generateRule creates or updates the PrometheusRule of the bundle for tenant in the namespace of
+// the tenant. Returns the key of the PrometheusRule, and an error wrapping errGeneratedRuleConflict
+// if a PrometheusRule of the same name exists that was not generated by the bundle.
+func (r *RecordingRuleBundleReconciler) generateRule(
+	ctx context.Context,
+	bundle *openawarenessv1beta1.RecordingRuleBundle,
+	tenant *openawarenessv1beta1.MimirAlertTenant,
+	groups []monitoringv1.RuleGroup,
+) (types.NamespacedName, error) {
+	rule := &monitoringv1.PrometheusRule{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      bundleRuleName(bundle, tenant),
+			Namespace: tenant.Namespace,
+		},
+	}
+	key := types.NamespacedName{Name: rule.Name, Namespace: rule.Namespace}
+
+	_, err := controllerutil.CreateOrUpdate(ctx, r.Client, rule, func() error {
+		if !rule.CreationTimestamp.IsZero() && !generatedBy(rule, bundle) {
+			return fmt.Errorf("%w: %s/%s", errGeneratedRuleConflict, rule.Namespace, rule.Name)
+		}
+		syncAnnotations(tenant, rule)
+		if rule.Labels == nil {
+			rule.Labels = map[string]string{}
+		}
+		rule.Labels[recordingRuleBundleLabel] = bundle.Name
+		rule.Labels[recordingRuleBundleNamespaceLabel] = bundle.Namespace
+		rule.Labels[recordingRuleVersionLabel] = bundle.Spec.Library.Version
+		rule.Spec.Groups = groups
+		return nil
+	})
+	return key, err
+}
+
+// generatedBy reports whether the PrometheusRule was generated by the bundle.
+func generatedBy(rule *monitoringv1.PrometheusRule, bundle *openawarenessv1beta1.RecordingRuleBundle) bool {
+	return rule.Labels[recordingRuleBundleLabel] == bundle.Name &&
+		rule.Labels[recordingRuleBundleNamespaceLabel] == bundle.Namespace
+}
+
+// bundleRuleName returns the name of the PrometheusRule generated by the bundle for tenant.
+// Names exceeding the maximum length are shortened and made unique with a hash.
+func bundleRuleName(
+	bundle *openawarenessv1beta1.RecordingRuleBundle,
+	tenant *openawarenessv1beta1.MimirAlertTenant,
+) string {
+	name := "recordingrules-" + bundle.Name + "-" + tenant.Name
+	if len(name) <= maxGeneratedRuleNameLength {
+		return name
+	}
+	sum := sha256.Sum256([]byte(bundle.Namespace + "/" + name))
+	suffix := "-" + hex.EncodeToString(sum[:])[:8]
+	return name[:maxGeneratedRuleNameLength-len(suffix)] + suffix
+}
+
+// deleteGeneratedRules deletes the PrometheusRules generated by the bundle in all namespaces,
+// except those in keep.
+func (r *RecordingRuleBundleReconciler) deleteGeneratedRules(
+	ctx context.Context,
+	bundle *openawarenessv1beta1.RecordingRuleBundle,
+	keep map[types.NamespacedName]bool,
+) error {
+	rules := &monitoringv1.PrometheusRuleList{}
+	if err := r.List(ctx, rules, k8sClient.MatchingLabels{
+		recordingRuleBundleLabel:          bundle.Name,
+		recordingRuleBundleNamespaceLabel: bundle.Namespace,
+	}); err != nil {
+		return fmt.Errorf("listing generated PrometheusRules: %w", err)
+	}
+
+	for i := range rules.Items {
+		rule := &rules.Items[i]
+		if keep[types.NamespacedName{Name: rule.Name, Namespace: rule.Namespace}] {
+			continue
+		}
+		log.FromContext(ctx).Info("Deleting generated PrometheusRule",
+			"prometheusRule", utils.OwnerReference(rule))
+		if err := r.Delete(ctx, rule); k8sClient.IgnoreNotFound(err) != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+// It indexes bundles by their library ConfigMap so library changes are rolled out, re-queues
+// all bundles on changes of MimirAlertTenants, whose labels decide whether they are selected,
+// and watches generated PrometheusRules so manual changes are reverted.
+func (r *RecordingRuleBundleReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if err := mgr.GetFieldIndexer().IndexField(
+		context.Background(),
+		&openawarenessv1beta1.RecordingRuleBundle{},
+		libraryConfigMapIndexKey,
+		func(obj k8sClient.Object) []string {
+			bundle, ok := obj.(*openawarenessv1beta1.RecordingRuleBundle)
+			if !ok || bundle.Spec.Library.ConfigMapRef == nil {
+				return nil
+			}
+			return []string{bundle.Spec.Library.ConfigMapRef.Name}
+		},
+	); err != nil {
+		return fmt.Errorf("indexing RecordingRuleBundles by library ConfigMap: %w", err)
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&openawarenessv1beta1.RecordingRuleBundle{}).
+		Watches(
+			&monitoringv1.PrometheusRule{},
+			handler.EnqueueRequestsFromMapFunc(findBundleForRule),
+		).
+		Watches(
+			&openawarenessv1beta1.MimirAlertTenant{},
+			handler.EnqueueRequestsFromMapFunc(r.findAllBundles),
+		).
+		Watches(
+			&corev1.ConfigMap{},
+			handler.EnqueueRequestsFromMapFunc(r.findBundlesForConfigMap),
+		).
+		WithOptions(controller.Options{LogConstructor: logging.LogConstructor(mgr.GetLogger(), "recordingrulebundle")}).
+		Complete(logging.TrackAttempts(r))
+}
+
+// findBundleForRule maps a change of a generated PrometheusRule to the bundle it belongs to.
+func findBundleForRule(_ context.Context, obj k8sClient.Object) []reconcile.Request {
+	name := obj.GetLabels()[recordingRuleBundleLabel]
+	namespace := obj.GetLabels()[recordingRuleBundleNamespaceLabel]
+	if name == "" || namespace == "" {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: name, Namespace: namespace}}}
+}
+
+// findAllBundles maps a change of a MimirAlertTenant to all bundles, since a label change may
+// select or deselect the tenant.
+func (r *RecordingRuleBundleReconciler) findAllBundles(ctx context.Context, _ k8sClient.Object) []reconcile.Request {
+	bundles := &openawarenessv1beta1.RecordingRuleBundleList{}
+	if err := r.List(ctx, bundles); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to list RecordingRuleBundles for MimirAlertTenant watch")
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(bundles.Items))
+	for _, bundle := range bundles.Items {
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Name: bundle.Name, Namespace: bundle.Namespace},
+		})
+	}
+	return requests
+}
+
+// findBundlesForConfigMap maps a change of a ConfigMap to the bundles in its namespace reading
+// their library from it.
+func (r *RecordingRuleBundleReconciler) findBundlesForConfigMap(
+	ctx context.Context,
+	obj k8sClient.Object,
+) []reconcile.Request {
+	bundles := &openawarenessv1beta1.RecordingRuleBundleList{}
+	if err := r.List(ctx, bundles,
+		k8sClient.InNamespace(obj.GetNamespace()),
+		k8sClient.MatchingFields{libraryConfigMapIndexKey: obj.GetName()},
+	); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to list RecordingRuleBundles for library ConfigMap",
+			"configMap", obj.GetName())
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(bundles.Items))
+	for _, bundle := range bundles.Items {
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Name: bundle.Name, Namespace: bundle.Namespace},
+		})
+	}
+	return requests
+}
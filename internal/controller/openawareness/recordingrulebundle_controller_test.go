@@ -0,0 +1,221 @@
+package openawareness
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"github.com/syndlex/openawareness-controller/internal/controller/utils"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	openawarenessv1beta1 "github.com/syndlex/openawareness-controller/api/openawareness/v1beta1"
+)
+
+var _ = Describe("RecordingRuleBundle Controller", func() {
+	const (
+		bundleName      = "test-bundle"
+		bundleNamespace = "default"
+		tenantNamespace = "recording-rules-tenants"
+		selectedLabel   = "openawareness.io/standard-recording-rules"
+		library         = `
+- name: http-aggregations
+  rules:
+    - record: job:http_requests:rate5m
+      expr: sum by (job) (rate(http_requests_total[5m]))
+`
+	)
+
+	var (
+		ctx                context.Context
+		reconciler         *RecordingRuleBundleReconciler
+		typeNamespacedName types.NamespacedName
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		reconciler = &RecordingRuleBundleReconciler{
+			Client: testClient,
+			Scheme: testClient.Scheme(),
+		}
+		typeNamespacedName = types.NamespacedName{Name: bundleName, Namespace: bundleNamespace}
+
+		namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: tenantNamespace}}
+		if err := testClient.Create(ctx, namespace); err != nil {
+			Expect(apierrors.IsAlreadyExists(err)).To(BeTrue())
+		}
+	})
+
+	reconcileBundle := func() {
+		_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: typeNamespacedName})
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	readyCondition := func() *metav1.Condition {
+		bundle := &openawarenessv1beta1.RecordingRuleBundle{}
+		Expect(testClient.Get(ctx, typeNamespacedName, bundle)).To(Succeed())
+		return meta.FindStatusCondition(bundle.Status.Conditions, openawarenessv1beta1.ConditionTypeReady)
+	}
+
+	createBundle := func(spec openawarenessv1beta1.RecordingRuleBundleSpec) *openawarenessv1beta1.RecordingRuleBundle {
+		bundle := &openawarenessv1beta1.RecordingRuleBundle{
+			ObjectMeta: metav1.ObjectMeta{Name: bundleName, Namespace: bundleNamespace},
+			Spec:       spec,
+		}
+		Expect(testClient.Create(ctx, bundle)).To(Succeed())
+		DeferCleanup(func() {
+			Expect(testClient.Get(ctx, typeNamespacedName, bundle)).To(Succeed())
+			Expect(testClient.Delete(ctx, bundle)).To(Succeed())
+			reconcileBundle()
+		})
+		return bundle
+	}
+
+	createTenant := func(name string, selected bool) *openawarenessv1beta1.MimirAlertTenant {
+		tenant := &openawarenessv1beta1.MimirAlertTenant{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: tenantNamespace,
+				Annotations: map[string]string{
+					utils.ClientNameAnnotation:  "test-client",
+					utils.MimirTenantAnnotation: name,
+				},
+			},
+			Spec: openawarenessv1beta1.MimirAlertTenantSpec{
+				AlertmanagerConfig: "route:\n  receiver: default\nreceivers:\n  - name: default\n",
+			},
+		}
+		if selected {
+			tenant.Labels = map[string]string{selectedLabel: "enabled"}
+		}
+		Expect(testClient.Create(ctx, tenant)).To(Succeed())
+		DeferCleanup(func() { Expect(testClient.Delete(ctx, tenant)).To(Succeed()) })
+		return tenant
+	}
+
+	selector := metav1.LabelSelector{MatchLabels: map[string]string{selectedLabel: "enabled"}}
+
+	It("should generate a PrometheusRule per selected tenant in the tenant namespace", func() {
+		createTenant("team-a", true)
+		createTenant("team-b", false)
+		createBundle(openawarenessv1beta1.RecordingRuleBundleSpec{
+			Library:        openawarenessv1beta1.RecordingRuleLibrary{Version: "1.0.0", Groups: library},
+			TenantSelector: selector,
+		})
+
+		reconcileBundle()
+
+		rule := &monitoringv1.PrometheusRule{}
+		Expect(testClient.Get(ctx, types.NamespacedName{
+			Name:      "recordingrules-" + bundleName + "-team-a",
+			Namespace: tenantNamespace,
+		}, rule)).To(Succeed())
+		Expect(rule.Annotations).To(HaveKeyWithValue(utils.ClientNameAnnotation, "test-client"))
+		Expect(rule.Annotations).To(HaveKeyWithValue(utils.MimirTenantAnnotation, "team-a"))
+		Expect(rule.Labels).To(HaveKeyWithValue(recordingRuleVersionLabel, "1.0.0"))
+		Expect(rule.Spec.Groups).To(HaveLen(1))
+		Expect(rule.Spec.Groups[0].Rules[0].Record).To(Equal("job:http_requests:rate5m"))
+
+		err := testClient.Get(ctx, types.NamespacedName{
+			Name:      "recordingrules-" + bundleName + "-team-b",
+			Namespace: tenantNamespace,
+		}, &monitoringv1.PrometheusRule{})
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+
+		bundle := &openawarenessv1beta1.RecordingRuleBundle{}
+		Expect(testClient.Get(ctx, typeNamespacedName, bundle)).To(Succeed())
+		Expect(bundle.Status.Version).To(Equal("1.0.0"))
+		Expect(bundle.Status.Tenants).To(Equal(int32(1)))
+		Expect(meta.IsStatusConditionTrue(bundle.Status.Conditions, openawarenessv1beta1.ConditionTypeReady)).To(BeTrue())
+	})
+
+	It("should delete the PrometheusRules of tenants no longer selected", func() {
+		tenant := createTenant("team-c", true)
+		bundle := createBundle(openawarenessv1beta1.RecordingRuleBundleSpec{
+			Library:        openawarenessv1beta1.RecordingRuleLibrary{Version: "1.0.0", Groups: library},
+			TenantSelector: selector,
+		})
+		ruleKey := types.NamespacedName{Name: "recordingrules-" + bundleName + "-team-c", Namespace: tenantNamespace}
+
+		reconcileBundle()
+		Expect(testClient.Get(ctx, ruleKey, &monitoringv1.PrometheusRule{})).To(Succeed())
+
+		By("Removing the label of the tenant")
+		tenant.Labels = nil
+		Expect(testClient.Update(ctx, tenant)).To(Succeed())
+		reconcileBundle()
+		Expect(apierrors.IsNotFound(testClient.Get(ctx, ruleKey, &monitoringv1.PrometheusRule{}))).To(BeTrue())
+
+		By("Selecting the tenant again and changing the selector of the bundle")
+		tenant.Labels = map[string]string{selectedLabel: "enabled"}
+		Expect(testClient.Update(ctx, tenant)).To(Succeed())
+		reconcileBundle()
+		Expect(testClient.Get(ctx, ruleKey, &monitoringv1.PrometheusRule{})).To(Succeed())
+
+		Expect(testClient.Get(ctx, typeNamespacedName, bundle)).To(Succeed())
+		Expect(bundle.Finalizers).To(ContainElement(utils.FinalizerAnnotation))
+		bundle.Spec.TenantSelector = metav1.LabelSelector{MatchLabels: map[string]string{selectedLabel: "none"}}
+		Expect(testClient.Update(ctx, bundle)).To(Succeed())
+		reconcileBundle()
+		Expect(apierrors.IsNotFound(testClient.Get(ctx, ruleKey, &monitoringv1.PrometheusRule{}))).To(BeTrue())
+	})
+
+	It("should refuse libraries with alerting rules", func() {
+		createBundle(openawarenessv1beta1.RecordingRuleBundleSpec{
+			Library: openawarenessv1beta1.RecordingRuleLibrary{
+				Version: "1.0.0",
+				Groups: `
+- name: alerts
+  rules:
+    - alert: HighErrorRate
+      expr: job:http_request_errors:rate5m > 1
+`,
+			},
+			TenantSelector: selector,
+		})
+
+		reconcileBundle()
+
+		condition := readyCondition()
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal(openawarenessv1beta1.ReasonInvalidLibrary))
+		Expect(condition.Message).To(ContainSubstring("not a recording rule"))
+	})
+
+	It("should read the library from a ConfigMap once it exists", func() {
+		createTenant("team-d", true)
+		createBundle(openawarenessv1beta1.RecordingRuleBundleSpec{
+			Library: openawarenessv1beta1.RecordingRuleLibrary{
+				Version: "2.0.0",
+				ConfigMapRef: &corev1.ConfigMapKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "recording-rules"},
+					Key:                  "rules.yaml",
+				},
+			},
+			TenantSelector: selector,
+		})
+
+		reconcileBundle()
+		condition := readyCondition()
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Reason).To(Equal(openawarenessv1beta1.ReasonLibraryNotFound))
+
+		configMap := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "recording-rules", Namespace: bundleNamespace},
+			Data:       map[string]string{"rules.yaml": library},
+		}
+		Expect(testClient.Create(ctx, configMap)).To(Succeed())
+		DeferCleanup(func() { Expect(testClient.Delete(ctx, configMap)).To(Succeed()) })
+
+		reconcileBundle()
+		condition = readyCondition()
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+	})
+})
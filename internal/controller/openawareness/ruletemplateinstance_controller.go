@@ -0,0 +1,298 @@
+package openawareness
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	k8sClient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/yaml"
+
+	openawarenessv1beta1 "github.com/syndlex/openawareness-controller/api/openawareness/v1beta1"
+	"github.com/syndlex/openawareness-controller/internal/controller/utils"
+	"github.com/syndlex/openawareness-controller/internal/logging"
+)
+
+const (
+	// templateRefIndexKey indexes RuleTemplateInstances by their referenced RuleTemplate
+	templateRefIndexKey = ".spec.templateRef"
+	// ruleTemplateLabel identifies the RuleTemplate a generated PrometheusRule was rendered from
+	ruleTemplateLabel = "openawareness.io/rule-template"
+)
+
+// errMissingParameter is returned when required template parameters have no value
+var errMissingParameter = errors.New("missing required parameters")
+
+// RuleTemplateInstanceReconciler reconciles a RuleTemplateInstance object
+type RuleTemplateInstanceReconciler struct {
+	k8sClient.Client
+	Scheme *runtime.Scheme
+	// GlobalValues provides controller-wide template values, nil if not configured
+	GlobalValues *utils.GlobalValues
+	// ClusterName identifies the cluster in templates as [[ .Meta.Cluster ]]
+	ClusterName string
+}
+
+//nolint:lll
+// +kubebuilder:rbac:groups=openawareness.syndlex,resources=ruletemplates,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=openawareness.syndlex,resources=ruletemplateinstances,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=openawareness.syndlex,resources=ruletemplateinstances/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=openawareness.syndlex,resources=ruletemplateinstances/finalizers,verbs=update
+// +kubebuilder:rbac:groups=monitoring.coreos.com,resources=prometheusrules,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch
+
+// Reconcile renders the RuleTemplate referenced by a RuleTemplateInstance with the
+// instance's parameter values and writes the resulting rule groups to an owned
+// PrometheusRule. The PrometheusRule carries the instance's client and tenant annotations,
+// so the PrometheusRules controller pushes the groups to Mimir.
+//
+// The reconciliation process:
+// 1. Fetches the RuleTemplateInstance and its RuleTemplate
+// 2. Merges parameter defaults, SecretDataReferences and inline values
+// 3. Renders the rule groups, reporting failures in the Ready condition
+// 4. Creates or updates the owned PrometheusRule
+// 5. Updates status with the generated PrometheusRule name
+func (r *RuleTemplateInstanceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	instance := &openawarenessv1beta1.RuleTemplateInstance{}
+	if err := r.Get(ctx, req.NamespacedName, instance); err != nil {
+		return ctrl.Result{}, k8sClient.IgnoreNotFound(err)
+	}
+	original := instance.DeepCopy()
+
+	ruleTemplate := &openawarenessv1beta1.RuleTemplate{}
+	if err := r.Get(ctx, types.NamespacedName{
+		Name:      instance.Spec.TemplateRef,
+		Namespace: instance.Namespace,
+	}, ruleTemplate); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return ctrl.Result{}, err
+		}
+		// The RuleTemplate watch triggers a new reconciliation once the template exists
+		return ctrl.Result{}, r.setFailed(ctx, instance, original, openawarenessv1beta1.ReasonTemplateNotFound,
+			fmt.Sprintf("RuleTemplate %s not found", instance.Spec.TemplateRef))
+	}
+
+	referenceData, _, err := utils.GetSecretData(ctx, r.Client, logger, instance.Namespace,
+		instance.Spec.SecretDataReferences, openawarenessv1beta1.ReferenceMergeOverrideSilently)
+	if err != nil {
+		logger.Error(err, "Failed to get template data")
+		if updateErr := r.setFailed(ctx, instance, original, openawarenessv1beta1.ReasonTemplateDataNotFound,
+			err.Error()); updateErr != nil {
+			return ctrl.Result{}, updateErr
+		}
+		return ctrl.Result{}, err
+	}
+
+	globals, err := r.GlobalValues.Get(ctx)
+	if err != nil {
+		logger.Error(err, "Failed to get global template values")
+		if updateErr := r.setFailed(ctx, instance, original, openawarenessv1beta1.ReasonTemplateDataNotFound,
+			err.Error()); updateErr != nil {
+			return ctrl.Result{}, updateErr
+		}
+		return ctrl.Result{}, err
+	}
+
+	groups, err := renderRuleTemplate(ruleTemplate, referenceData, instance.Spec.Values,
+		utils.TemplateBuiltins{
+			Global: globals,
+			Meta:   utils.NewTemplateMetadata(instance, r.ClusterName),
+		})
+	if err != nil {
+		logger.Error(err, "Failed to render RuleTemplate",
+			"template", ruleTemplate.Name)
+		reason := openawarenessv1beta1.ReasonInvalidTemplate
+		if errors.Is(err, errMissingParameter) {
+			reason = openawarenessv1beta1.ReasonMissingParameter
+		}
+		// Spec changes trigger a new reconciliation, retrying does not help
+		return ctrl.Result{}, r.setFailed(ctx, instance, original, reason, err.Error())
+	}
+
+	rule := &monitoringv1.PrometheusRule{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "ruletemplate-" + instance.Name,
+			Namespace: instance.Namespace,
+		},
+	}
+	operation, err := controllerutil.CreateOrUpdate(ctx, r.Client, rule, func() error {
+		syncAnnotations(instance, rule)
+		if rule.Labels == nil {
+			rule.Labels = map[string]string{}
+		}
+		rule.Labels[ruleTemplateLabel] = ruleTemplate.Name
+		rule.Spec.Groups = groups
+		return controllerutil.SetControllerReference(instance, rule, r.Scheme)
+	})
+	if err != nil {
+		logger.Error(err, "Failed to create or update PrometheusRule for RuleTemplateInstance",
+			"prometheusRule", rule.Name)
+		return ctrl.Result{}, err
+	}
+
+	logging.SuccessInfo(logger, utils.OwnerReference(instance), "Rendered RuleTemplate into PrometheusRule",
+		"template", ruleTemplate.Name,
+		"prometheusRule", rule.Name,
+		logging.KeyOperation, operation)
+
+	instance.Status.PrometheusRule = rule.Name
+	setReadyCondition(&instance.Status.Conditions, instance.Generation,
+		metav1.ConditionTrue, openawarenessv1beta1.ReasonRulesGenerated,
+		fmt.Sprintf("Rules generated in PrometheusRule %s", rule.Name))
+	if err := utils.PatchStatus(ctx, r.Client, instance, original); err != nil {
+		logger.Error(err, "Failed to update status")
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// setFailed sets the Ready condition to False and patches the status against original.
+// Returns the status update error, if any.
+func (r *RuleTemplateInstanceReconciler) setFailed(
+	ctx context.Context,
+	instance, original *openawarenessv1beta1.RuleTemplateInstance,
+	reason, message string,
+) error {
+	setReadyCondition(&instance.Status.Conditions, instance.Generation, metav1.ConditionFalse, reason, message)
+	if err := utils.PatchStatus(ctx, r.Client, instance, original); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to update status")
+		return err
+	}
+	return nil
+}
+
+// renderRuleTemplate renders the groups of a RuleTemplate into PrometheusRule rule groups.
+// Parameter values are merged from the template defaults, the reference data and the
+// inline values, with later sources taking precedence. Global values and metadata of
+// the instance are available as [[ .Global.NAME ]] and [[ .Meta.FIELD ]].
+// Returns an error wrapping errMissingParameter if a required parameter has no value.
+func renderRuleTemplate(
+	ruleTemplate *openawarenessv1beta1.RuleTemplate,
+	referenceData map[string]string,
+	values map[string]string,
+	builtins utils.TemplateBuiltins,
+) ([]monitoringv1.RuleGroup, error) {
+	data := make(map[string]string)
+	for _, param := range ruleTemplate.Spec.Parameters {
+		if param.Default != "" {
+			data[param.Name] = param.Default
+		}
+	}
+	for k, v := range referenceData {
+		data[k] = v
+	}
+	for k, v := range values {
+		data[k] = v
+	}
+
+	var missing []string
+	for _, param := range ruleTemplate.Spec.Parameters {
+		if param.Required && data[param.Name] == "" {
+			missing = append(missing, param.Name)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return nil, fmt.Errorf("%w: %v", errMissingParameter, missing)
+	}
+
+	rendered, err := utils.RenderTemplateWithBuiltins(ruleTemplate.Spec.Groups, data, builtins)
+	if err != nil {
+		return nil, err
+	}
+
+	var groups []monitoringv1.RuleGroup
+	if err := yaml.UnmarshalStrict([]byte(rendered), &groups); err != nil {
+		return nil, fmt.Errorf("invalid rule groups in rendered template: %w", err)
+	}
+	if len(groups) == 0 {
+		return nil, fmt.Errorf("rendered template contains no rule groups")
+	}
+	for i, group := range groups {
+		if group.Name == "" {
+			return nil, fmt.Errorf("rule group %d in rendered template has no name", i)
+		}
+	}
+
+	return groups, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+// It indexes instances by their RuleTemplate so template changes are rolled out to
+// every instance, and watches generated PrometheusRules so manual changes are reverted.
+func (r *RuleTemplateInstanceReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if err := mgr.GetFieldIndexer().IndexField(
+		context.Background(),
+		&openawarenessv1beta1.RuleTemplateInstance{},
+		templateRefIndexKey,
+		func(obj k8sClient.Object) []string {
+			instance, ok := obj.(*openawarenessv1beta1.RuleTemplateInstance)
+			if !ok || instance.Spec.TemplateRef == "" {
+				return nil
+			}
+			return []string{instance.Spec.TemplateRef}
+		},
+	); err != nil {
+		return fmt.Errorf("indexing RuleTemplateInstances by template: %w", err)
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&openawarenessv1beta1.RuleTemplateInstance{}).
+		Owns(&monitoringv1.PrometheusRule{}).
+		Watches(
+			&openawarenessv1beta1.RuleTemplate{},
+			handler.EnqueueRequestsFromMapFunc(r.findInstancesForTemplate),
+		).
+		WithOptions(controller.Options{LogConstructor: logging.LogConstructor(mgr.GetLogger(), "ruletemplateinstance")}).
+		Complete(logging.TrackAttempts(r))
+}
+
+// findInstancesForTemplate maps RuleTemplate changes to reconciliation requests for all
+// RuleTemplateInstances in the same namespace referencing the template.
+func (r *RuleTemplateInstanceReconciler) findInstancesForTemplate(
+	ctx context.Context,
+	obj k8sClient.Object,
+) []reconcile.Request {
+	logger := log.FromContext(ctx)
+
+	instanceList := &openawarenessv1beta1.RuleTemplateInstanceList{}
+	if err := r.List(ctx, instanceList,
+		k8sClient.InNamespace(obj.GetNamespace()),
+		k8sClient.MatchingFields{templateRefIndexKey: obj.GetName()},
+	); err != nil {
+		logger.Error(err, "Failed to list RuleTemplateInstances for RuleTemplate watch")
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(instanceList.Items))
+	for _, instance := range instanceList.Items {
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Name:      instance.Name,
+				Namespace: instance.Namespace,
+			},
+		})
+	}
+
+	logger.V(1).Info("Found RuleTemplateInstances referencing RuleTemplate",
+		"ruleTemplate", obj.GetName(),
+		"count", len(requests))
+
+	return requests
+}
@@ -0,0 +1,182 @@
+package openawareness
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"github.com/syndlex/openawareness-controller/internal/controller/utils"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	openawarenessv1beta1 "github.com/syndlex/openawareness-controller/api/openawareness/v1beta1"
+)
+
+var _ = Describe("RuleTemplateInstance Controller", func() {
+	const (
+		templateName  = "test-rule-template"
+		instanceName  = "test-rule-template-instance"
+		testNamespace = "default"
+		groupsYAML    = `- name: pod-restarts
+  rules:
+    - alert: PodCrashLooping
+      expr: increase(kube_pod_container_status_restarts_total{namespace="[[ .namespace ]]"}[1h]) > [[ .threshold ]]
+      labels:
+        severity: warning
+      annotations:
+        summary: "Pod {{ $labels.pod }} is restarting"
+`
+	)
+
+	newTemplate := func() *openawarenessv1beta1.RuleTemplate {
+		return &openawarenessv1beta1.RuleTemplate{
+			ObjectMeta: metav1.ObjectMeta{Name: templateName, Namespace: testNamespace},
+			Spec: openawarenessv1beta1.RuleTemplateSpec{
+				Parameters: []openawarenessv1beta1.RuleTemplateParameter{
+					{Name: "namespace", Required: true},
+					{Name: "threshold", Default: "3"},
+				},
+				Groups: groupsYAML,
+			},
+		}
+	}
+
+	Context("When rendering a RuleTemplate", func() {
+		It("should merge defaults, reference data and inline values", func() {
+			groups, err := renderRuleTemplate(newTemplate(),
+				map[string]string{"namespace": "from-reference", "threshold": "5"},
+				map[string]string{"namespace": "payments"}, utils.TemplateBuiltins{})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(groups).To(HaveLen(1))
+			Expect(groups[0].Rules[0].Expr.String()).To(Equal(
+				`increase(kube_pod_container_status_restarts_total{namespace="payments"}[1h]) > 5`))
+			Expect(groups[0].Rules[0].Annotations["summary"]).To(Equal("Pod {{ $labels.pod }} is restarting"))
+		})
+
+		It("should expose global values", func() {
+			ruleTemplate := newTemplate()
+			ruleTemplate.Spec.Groups = "- name: [[ .Global.CLUSTER ]]-[[ .namespace ]]\n"
+
+			groups, err := renderRuleTemplate(ruleTemplate, nil,
+				map[string]string{"namespace": "payments"},
+				utils.TemplateBuiltins{Global: map[string]string{"CLUSTER": "eu-1"}})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(groups[0].Name).To(Equal("eu-1-payments"))
+		})
+
+		It("should expose metadata of the instance", func() {
+			ruleTemplate := newTemplate()
+			ruleTemplate.Spec.Groups = "- name: [[ .Meta.Cluster ]]-[[ .Meta.Namespace ]]-[[ .Meta.Name ]]\n"
+
+			groups, err := renderRuleTemplate(ruleTemplate, nil,
+				map[string]string{"namespace": "payments"},
+				utils.TemplateBuiltins{Meta: utils.TemplateMetadata{
+					Namespace: "payments",
+					Name:      "restarts",
+					Cluster:   "eu-1",
+				}})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(groups[0].Name).To(Equal("eu-1-payments-restarts"))
+		})
+
+		It("should fail when a required parameter is missing", func() {
+			_, err := renderRuleTemplate(newTemplate(), nil, nil, utils.TemplateBuiltins{})
+
+			Expect(err).To(MatchError(errMissingParameter))
+			Expect(err.Error()).To(ContainSubstring("namespace"))
+		})
+
+		It("should fail when the rendered groups are not valid rule groups", func() {
+			ruleTemplate := newTemplate()
+			ruleTemplate.Spec.Groups = "- name: broken\n  unknownField: true\n"
+
+			_, err := renderRuleTemplate(ruleTemplate, nil, map[string]string{"namespace": "payments"},
+				utils.TemplateBuiltins{})
+
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("When reconciling a RuleTemplateInstance", func() {
+		var (
+			ctx        context.Context
+			reconciler *RuleTemplateInstanceReconciler
+			request    ctrl.Request
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			reconciler = &RuleTemplateInstanceReconciler{
+				Client: testClient,
+				Scheme: testClient.Scheme(),
+			}
+			request = ctrl.Request{NamespacedName: types.NamespacedName{Name: instanceName, Namespace: testNamespace}}
+		})
+
+		newInstance := func() *openawarenessv1beta1.RuleTemplateInstance {
+			return &openawarenessv1beta1.RuleTemplateInstance{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      instanceName,
+					Namespace: testNamespace,
+					Annotations: map[string]string{
+						utils.ClientNameAnnotation:  "test-client",
+						utils.MimirTenantAnnotation: "test-tenant",
+					},
+				},
+				Spec: openawarenessv1beta1.RuleTemplateInstanceSpec{
+					TemplateRef: templateName,
+					Values:      map[string]string{"namespace": "payments"},
+				},
+			}
+		}
+
+		It("should generate an owned PrometheusRule from the template", func() {
+			ruleTemplate := newTemplate()
+			Expect(testClient.Create(ctx, ruleTemplate)).To(Succeed())
+			defer func() { Expect(testClient.Delete(ctx, ruleTemplate)).To(Succeed()) }()
+			instance := newInstance()
+			Expect(testClient.Create(ctx, instance)).To(Succeed())
+			defer func() { Expect(testClient.Delete(ctx, instance)).To(Succeed()) }()
+
+			_, err := reconciler.Reconcile(ctx, request)
+			Expect(err).NotTo(HaveOccurred())
+
+			rule := &monitoringv1.PrometheusRule{}
+			Expect(testClient.Get(ctx, types.NamespacedName{
+				Name:      "ruletemplate-" + instanceName,
+				Namespace: testNamespace,
+			}, rule)).To(Succeed())
+			Expect(rule.Annotations).To(HaveKeyWithValue(utils.ClientNameAnnotation, "test-client"))
+			Expect(rule.Labels).To(HaveKeyWithValue(ruleTemplateLabel, templateName))
+			Expect(rule.Spec.Groups).To(HaveLen(1))
+			Expect(metav1.IsControlledBy(rule, instance)).To(BeTrue())
+
+			Expect(testClient.Get(ctx, request.NamespacedName, instance)).To(Succeed())
+			Expect(instance.Status.PrometheusRule).To(Equal(rule.Name))
+			Expect(meta.IsStatusConditionTrue(instance.Status.Conditions,
+				openawarenessv1beta1.ConditionTypeReady)).To(BeTrue())
+
+			Expect(testClient.Delete(ctx, rule)).To(Succeed())
+		})
+
+		It("should report a missing RuleTemplate in the Ready condition", func() {
+			instance := newInstance()
+			Expect(testClient.Create(ctx, instance)).To(Succeed())
+			defer func() { Expect(testClient.Delete(ctx, instance)).To(Succeed()) }()
+
+			_, err := reconciler.Reconcile(ctx, request)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(testClient.Get(ctx, request.NamespacedName, instance)).To(Succeed())
+			condition := meta.FindStatusCondition(instance.Status.Conditions, openawarenessv1beta1.ConditionTypeReady)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Reason).To(Equal(openawarenessv1beta1.ReasonTemplateNotFound))
+		})
+	})
+})
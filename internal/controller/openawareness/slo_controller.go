@@ -0,0 +1,116 @@
+package openawareness
+
+import (
+	"context"
+	"fmt"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	k8sClient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	openawarenessv1beta1 "github.com/syndlex/openawareness-controller/api/openawareness/v1beta1"
+	"github.com/syndlex/openawareness-controller/internal/controller/utils"
+	"github.com/syndlex/openawareness-controller/internal/logging"
+	"github.com/syndlex/openawareness-controller/internal/slo"
+)
+
+// SLOReconciler reconciles a SLO object
+type SLOReconciler struct {
+	k8sClient.Client
+	Scheme *runtime.Scheme
+}
+
+// +kubebuilder:rbac:groups=openawareness.syndlex,resources=slos,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=openawareness.syndlex,resources=slos/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=openawareness.syndlex,resources=slos/finalizers,verbs=update
+// +kubebuilder:rbac:groups=monitoring.coreos.com,resources=prometheusrules,verbs=get;list;watch;create;update;patch;delete
+
+// Reconcile generates a PrometheusRule with multi-window multi-burn-rate recording and
+// alerting rules from an SLO. The generated PrometheusRule is owned by the SLO and carries
+// its client and tenant annotations, so the PrometheusRules controller syncs it to Mimir
+// and Kubernetes garbage collection removes it together with the SLO.
+//
+// The reconciliation process:
+// 1. Fetches the SLO resource
+// 2. Generates the rule groups, reporting invalid specs in the Ready condition
+// 3. Creates or updates the owned PrometheusRule
+// 4. Updates status with the generated PrometheusRule name
+func (r *SLOReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	s := &openawarenessv1beta1.SLO{}
+	if err := r.Get(ctx, req.NamespacedName, s); err != nil {
+		return ctrl.Result{}, k8sClient.IgnoreNotFound(err)
+	}
+	original := s.DeepCopy()
+
+	groups, err := slo.GenerateRuleGroups(s)
+	if err != nil {
+		logger.Error(err, "Invalid SLO")
+		setReadyCondition(&s.Status.Conditions, s.Generation,
+			metav1.ConditionFalse, openawarenessv1beta1.ReasonInvalidSLO, err.Error())
+		if updateErr := utils.PatchStatus(ctx, r.Client, s, original); updateErr != nil {
+			logger.Error(updateErr, "Failed to update status")
+			return ctrl.Result{}, updateErr
+		}
+		// Spec changes trigger a new reconciliation, retrying does not help
+		return ctrl.Result{}, nil
+	}
+
+	rule := &monitoringv1.PrometheusRule{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      generatedRuleName(s),
+			Namespace: s.Namespace,
+		},
+	}
+	operation, err := controllerutil.CreateOrUpdate(ctx, r.Client, rule, func() error {
+		syncAnnotations(s, rule)
+		if rule.Labels == nil {
+			rule.Labels = map[string]string{}
+		}
+		rule.Labels[slo.ServiceLabel] = s.Spec.Service
+		rule.Labels[slo.NameLabel] = s.Name
+		rule.Spec.Groups = groups
+		return controllerutil.SetControllerReference(s, rule, r.Scheme)
+	})
+	if err != nil {
+		logger.Error(err, "Failed to create or update PrometheusRule for SLO",
+			"prometheusRule", rule.Name)
+		return ctrl.Result{}, err
+	}
+
+	logging.SuccessInfo(logger, utils.OwnerReference(s), "Generated PrometheusRule for SLO",
+		"prometheusRule", rule.Name,
+		logging.KeyOperation, operation)
+
+	s.Status.PrometheusRule = rule.Name
+	setReadyCondition(&s.Status.Conditions, s.Generation,
+		metav1.ConditionTrue, openawarenessv1beta1.ReasonRulesGenerated,
+		fmt.Sprintf("Rules generated in PrometheusRule %s", rule.Name))
+	if err := utils.PatchStatus(ctx, r.Client, s, original); err != nil {
+		logger.Error(err, "Failed to update status")
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// generatedRuleName returns the name of the PrometheusRule generated for an SLO.
+func generatedRuleName(s *openawarenessv1beta1.SLO) string {
+	return "slo-" + s.Name
+}
+
+// SetupWithManager sets up the controller with the Manager.
+// Generated PrometheusRules are watched so manual changes are reverted.
+func (r *SLOReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&openawarenessv1beta1.SLO{}).
+		Owns(&monitoringv1.PrometheusRule{}).
+		WithOptions(controller.Options{LogConstructor: logging.LogConstructor(mgr.GetLogger(), "slo")}).
+		Complete(logging.TrackAttempts(r))
+}
@@ -0,0 +1,98 @@
+package openawareness
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"github.com/syndlex/openawareness-controller/internal/controller/utils"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	openawarenessv1beta1 "github.com/syndlex/openawareness-controller/api/openawareness/v1beta1"
+)
+
+var _ = Describe("SLO Controller", func() {
+	const (
+		sloName      = "test-slo"
+		sloNamespace = "default"
+	)
+
+	var (
+		ctx                context.Context
+		reconciler         *SLOReconciler
+		typeNamespacedName types.NamespacedName
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		reconciler = &SLOReconciler{
+			Client: testClient,
+			Scheme: testClient.Scheme(),
+		}
+		typeNamespacedName = types.NamespacedName{Name: sloName, Namespace: sloNamespace}
+	})
+
+	newSLO := func(objective string) *openawarenessv1beta1.SLO {
+		return &openawarenessv1beta1.SLO{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      sloName,
+				Namespace: sloNamespace,
+				Annotations: map[string]string{
+					utils.ClientNameAnnotation:  "test-client",
+					utils.MimirTenantAnnotation: "test-tenant",
+				},
+			},
+			Spec: openawarenessv1beta1.SLOSpec{
+				Service:   "api",
+				Objective: objective,
+				SLI: openawarenessv1beta1.SLI{
+					ErrorQuery: `sum(rate(http_requests_total{code=~"5.."}[{{ .window }}]))`,
+					TotalQuery: `sum(rate(http_requests_total[{{ .window }}]))`,
+				},
+			},
+		}
+	}
+
+	It("should generate an owned PrometheusRule with the SLO annotations", func() {
+		s := newSLO("99.9")
+		Expect(testClient.Create(ctx, s)).To(Succeed())
+		defer func() { Expect(testClient.Delete(ctx, s)).To(Succeed()) }()
+
+		_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: typeNamespacedName})
+		Expect(err).NotTo(HaveOccurred())
+
+		rule := &monitoringv1.PrometheusRule{}
+		Expect(testClient.Get(ctx, types.NamespacedName{Name: "slo-" + sloName, Namespace: sloNamespace}, rule)).
+			To(Succeed())
+		Expect(rule.Annotations).To(HaveKeyWithValue(utils.ClientNameAnnotation, "test-client"))
+		Expect(rule.Annotations).To(HaveKeyWithValue(utils.MimirTenantAnnotation, "test-tenant"))
+		Expect(rule.Spec.Groups).To(HaveLen(2))
+		Expect(metav1.IsControlledBy(rule, s)).To(BeTrue())
+
+		Expect(testClient.Get(ctx, typeNamespacedName, s)).To(Succeed())
+		Expect(s.Status.PrometheusRule).To(Equal(rule.Name))
+		Expect(meta.IsStatusConditionTrue(s.Status.Conditions, openawarenessv1beta1.ConditionTypeReady)).To(BeTrue())
+
+		Expect(testClient.Delete(ctx, rule)).To(Succeed())
+	})
+
+	It("should report an invalid SLI query in the Ready condition", func() {
+		s := newSLO("99.9")
+		s.Spec.SLI.ErrorQuery = "sum(rate(x[{{ .interval }}]))"
+		Expect(testClient.Create(ctx, s)).To(Succeed())
+		defer func() { Expect(testClient.Delete(ctx, s)).To(Succeed()) }()
+
+		_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: typeNamespacedName})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(testClient.Get(ctx, typeNamespacedName, s)).To(Succeed())
+		condition := meta.FindStatusCondition(s.Status.Conditions, openawarenessv1beta1.ConditionTypeReady)
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal(openawarenessv1beta1.ReasonInvalidSLO))
+	})
+})
@@ -0,0 +1,265 @@
+package openawareness
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	k8sClient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	openawarenessv1beta1 "github.com/syndlex/openawareness-controller/api/openawareness/v1beta1"
+	"github.com/syndlex/openawareness-controller/internal/clients"
+	"github.com/syndlex/openawareness-controller/internal/controller/utils"
+	"github.com/syndlex/openawareness-controller/internal/events"
+	"github.com/syndlex/openawareness-controller/internal/logging"
+	"github.com/syndlex/openawareness-controller/internal/snapshot"
+)
+
+// snapshotUploadTimeout bounds an upload of a snapshot to its URL
+const snapshotUploadTimeout = time.Minute
+
+// errSnapshotDestinationTaken is returned when the ConfigMap or Secret of a snapshot exists and
+// is not owned by the Snapshot
+var errSnapshotDestinationTaken = errors.New("destination exists and is not owned by the Snapshot")
+
+// SnapshotReconciler captures the rule groups and Alertmanager configuration of the tenant of a
+// Snapshot once and writes them to its destination, then deletes the Snapshot when its
+// retention expired.
+type SnapshotReconciler struct {
+	k8sClient.Client
+	Scheme       *runtime.Scheme
+	RulerClients clients.RulerClientCacheInterface
+	// Recorder emits events on completed and failed Snapshots.
+	// Events are not emitted if nil.
+	Recorder record.EventRecorder
+	// HTTPClient uploads snapshots to their URL, a client with a timeout of one minute if nil
+	HTTPClient *http.Client
+}
+
+// +kubebuilder:rbac:groups=openawareness.syndlex,resources=snapshots,verbs=get;list;watch;delete
+// +kubebuilder:rbac:groups=openawareness.syndlex,resources=snapshots/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=openawareness.syndlex,resources=clientconfigs,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+
+// Reconcile captures the tenant of a Snapshot.
+//
+// The reconciliation process:
+// 1. Fetches the Snapshot, completed Snapshots are deleted once their retention expired
+// 2. Resolves its ClientConfig and tenant alias, tenants the ClientConfig does not allow fail
+// 3. Reads the rule groups of all rule namespaces and the Alertmanager configuration of the tenant
+// 4. Writes them to an owned ConfigMap or Secret, or uploads them to the URL
+// 5. Records the completion in the status, the tenant is never captured again
+func (r *SnapshotReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	snap := &openawarenessv1beta1.Snapshot{}
+	if err := r.Get(ctx, req.NamespacedName, snap); err != nil {
+		return ctrl.Result{}, k8sClient.IgnoreNotFound(err)
+	}
+	if !snap.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+	if snap.Status.CompletionTime != nil {
+		return r.expire(ctx, snap)
+	}
+	original := snap.DeepCopy()
+
+	ref := snap.Spec.ClientConfig
+	clientConfig := &openawarenessv1beta1.ClientConfig{}
+	if err := r.Get(ctx, k8sClient.ObjectKey{Namespace: ref.Namespace, Name: ref.Name}, clientConfig); err != nil {
+		err = fmt.Errorf("getting ClientConfig %s/%s: %w", ref.Namespace, ref.Name, err)
+		return ctrl.Result{}, r.setFailed(ctx, snap, original, openawarenessv1beta1.ReasonCaptureFailed, err)
+	}
+	tenantID := clientConfig.ResolveTenant(snap.Spec.Tenant)
+	if tenantID == "" {
+		tenantID = utils.DefaultTenantID
+	}
+	logger = logger.WithValues(logging.KeyTenant, tenantID, logging.KeyClient, clientConfig.Name)
+	snap.Status.Tenant = tenantID
+	if err := utils.CheckTenantsAllowed(clientConfig, tenantID); err != nil {
+		// Only a change of the ClientConfig allows the tenant, the Snapshot is not retried
+		logger.Info("Tenant is not allowed by the ClientConfig, not capturing the Snapshot")
+		_ = r.setFailed(ctx, snap, original, openawarenessv1beta1.ReasonTenantNotAllowed, err)
+		return ctrl.Result{}, nil
+	}
+
+	awarenessClient, err := utils.ClientForConfig(ctx, r.RulerClients, clientConfig)
+	if err == nil && awarenessClient == nil {
+		err = fmt.Errorf("ClientConfig %s is not a Mimir client", utils.OwnerReference(clientConfig))
+	}
+	if err != nil {
+		return ctrl.Result{}, r.setFailed(ctx, snap, original, openawarenessv1beta1.ReasonCaptureFailed, err)
+	}
+	captured, err := snapshot.Capture(ctx, awarenessClient, utils.OwnerReference(clientConfig), tenantID)
+	if err != nil {
+		return ctrl.Result{}, r.setFailed(ctx, snap, original, openawarenessv1beta1.ReasonCaptureFailed, err)
+	}
+	data, err := captured.Marshal()
+	if err != nil {
+		return ctrl.Result{}, r.setFailed(ctx, snap, original, openawarenessv1beta1.ReasonCaptureFailed, err)
+	}
+
+	location, err := r.write(ctx, snap, data)
+	if errors.Is(err, errSnapshotDestinationTaken) {
+		// Retrying overwrites nothing until the object is removed or the Snapshot recreated
+		_ = r.setFailed(ctx, snap, original, openawarenessv1beta1.ReasonWriteFailed, err)
+		return ctrl.Result{}, nil
+	}
+	if err != nil {
+		return ctrl.Result{}, r.setFailed(ctx, snap, original, openawarenessv1beta1.ReasonWriteFailed, err)
+	}
+
+	logger.Info("Captured Snapshot of tenant", "location", location, "ruleGroups", captured.RuleGroups())
+	now := metav1.Now()
+	snap.Status.CompletionTime = &now
+	snap.Status.RuleGroups = int32(captured.RuleGroups())
+	snap.Status.Location = location
+	utils.SetCondition(&snap.Status.Conditions, metav1.Condition{
+		Type:               openawarenessv1beta1.ConditionTypeCompleted,
+		Status:             metav1.ConditionTrue,
+		Reason:             openawarenessv1beta1.ReasonSnapshotWritten,
+		Message:            fmt.Sprintf("Captured %d rule groups of tenant %s to %s", captured.RuleGroups(), tenantID, location),
+		ObservedGeneration: snap.Generation,
+	})
+	if err := utils.PatchStatus(ctx, r.Client, snap, original); err != nil {
+		logger.Error(err, "Failed to update status")
+		return ctrl.Result{}, err
+	}
+	events.Eventf(r.Recorder, snap, events.SnapshotCompleted,
+		"Captured %d rule groups of tenant %s to %s", captured.RuleGroups(), tenantID, location)
+	return r.expire(ctx, snap)
+}
+
+// expire deletes a completed Snapshot whose retention expired, and requeues it for the expiry
+// otherwise. Snapshots without retention are kept.
+func (r *SnapshotReconciler) expire(ctx context.Context, snap *openawarenessv1beta1.Snapshot) (ctrl.Result, error) {
+	if snap.Spec.Retention == nil {
+		return ctrl.Result{}, nil
+	}
+	remaining := time.Until(snap.Status.CompletionTime.Add(snap.Spec.Retention.Duration))
+	if remaining > 0 {
+		return ctrl.Result{RequeueAfter: remaining}, nil
+	}
+	log.FromContext(ctx).Info("Deleting Snapshot after its retention expired")
+	// The owned ConfigMap or Secret is garbage collected with the Snapshot
+	return ctrl.Result{}, k8sClient.IgnoreNotFound(r.Delete(ctx, snap))
+}
+
+// write writes data to the destination of the Snapshot.
+// Returns the location written to, and an error wrapping errSnapshotDestinationTaken if the
+// ConfigMap or Secret exists and is not owned by the Snapshot.
+func (r *SnapshotReconciler) write(ctx context.Context, snap *openawarenessv1beta1.Snapshot, data []byte) (string, error) {
+	destination := snap.Spec.Destination
+	switch {
+	case destination.ConfigMap != "":
+		configMap := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: destination.ConfigMap, Namespace: snap.Namespace},
+		}
+		return "ConfigMap/" + configMap.Name, r.writeOwned(ctx, snap, configMap, func() {
+			configMap.Data = map[string]string{snapshot.Key: string(data)}
+		})
+	case destination.Secret != "":
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: destination.Secret, Namespace: snap.Namespace},
+		}
+		return "Secret/" + secret.Name, r.writeOwned(ctx, snap, secret, func() {
+			secret.Type = corev1.SecretTypeOpaque
+			secret.Data = map[string][]byte{snapshot.Key: data}
+		})
+	default:
+		credentials, err := r.credentials(ctx, snap)
+		if err != nil {
+			return "", err
+		}
+		httpClient := r.HTTPClient
+		if httpClient == nil {
+			httpClient = &http.Client{Timeout: snapshotUploadTimeout}
+		}
+		return snapshot.RedactURL(destination.URL),
+			snapshot.Upload(ctx, httpClient, destination.URL, data, credentials)
+	}
+}
+
+// writeOwned creates or updates obj with the data set by fill, controlled by the Snapshot.
+// Returns an error wrapping errSnapshotDestinationTaken if obj exists and is not controlled by
+// the Snapshot, e.g. a ConfigMap of another application.
+func (r *SnapshotReconciler) writeOwned(
+	ctx context.Context,
+	snap *openawarenessv1beta1.Snapshot,
+	obj k8sClient.Object,
+	fill func(),
+) error {
+	_, err := controllerutil.CreateOrUpdate(ctx, r.Client, obj, func() error {
+		if obj.GetResourceVersion() != "" && !metav1.IsControlledBy(obj, snap) {
+			return fmt.Errorf("%s: %w", obj.GetName(), errSnapshotDestinationTaken)
+		}
+		fill()
+		return controllerutil.SetControllerReference(snap, obj, r.Scheme)
+	})
+	return err
+}
+
+// credentials reads the upload credentials of the secretRef of the Snapshot: the token key, or
+// the username and password keys. Returns no credentials if the Snapshot has no secretRef.
+func (r *SnapshotReconciler) credentials(
+	ctx context.Context,
+	snap *openawarenessv1beta1.Snapshot,
+) (snapshot.Credentials, error) {
+	ref := snap.Spec.Destination.SecretRef
+	if ref == nil {
+		return snapshot.Credentials{}, nil
+	}
+	secret := &corev1.Secret{}
+	if err := r.Get(ctx, k8sClient.ObjectKey{Namespace: snap.Namespace, Name: ref.Name}, secret); err != nil {
+		return snapshot.Credentials{}, fmt.Errorf("getting upload credentials Secret %s: %w", ref.Name, err)
+	}
+	return snapshot.Credentials{
+		Token:    string(secret.Data["token"]),
+		Username: string(secret.Data["username"]),
+		Password: string(secret.Data["password"]),
+	}, nil
+}
+
+// setFailed sets the Completed condition to False with the error, emits a SnapshotFailed event
+// and patches the status against original. Returns err, or the status update error.
+func (r *SnapshotReconciler) setFailed(
+	ctx context.Context,
+	snap, original *openawarenessv1beta1.Snapshot,
+	reason string,
+	err error,
+) error {
+	log.FromContext(ctx).Error(err, "Failed to capture Snapshot", "reason", reason)
+	utils.SetCondition(&snap.Status.Conditions, metav1.Condition{
+		Type:               openawarenessv1beta1.ConditionTypeCompleted,
+		Status:             metav1.ConditionFalse,
+		Reason:             reason,
+		Message:            err.Error(),
+		ObservedGeneration: snap.Generation,
+	})
+	events.Event(r.Recorder, snap, events.SnapshotFailed, err.Error())
+	if updateErr := utils.PatchStatus(ctx, r.Client, snap, original); updateErr != nil {
+		log.FromContext(ctx).Error(updateErr, "Failed to update status")
+		return updateErr
+	}
+	return err
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *SnapshotReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&openawarenessv1beta1.Snapshot{}).
+		WithOptions(controller.Options{LogConstructor: logging.LogConstructor(mgr.GetLogger(), "snapshot")}).
+		Complete(logging.TrackAttempts(r))
+}
@@ -0,0 +1,192 @@
+package openawareness
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	openawarenessv1beta1 "github.com/syndlex/openawareness-controller/api/openawareness/v1beta1"
+	"github.com/syndlex/openawareness-controller/internal/clients"
+	"github.com/syndlex/openawareness-controller/internal/snapshot"
+)
+
+var _ = Describe("Snapshot Controller", func() {
+	const teamConfig = "route:\n  receiver: team\nreceivers:\n  - name: team\n"
+
+	var (
+		ctx          context.Context
+		mimirClient  *clients.MockAwarenessClient
+		recorder     *record.FakeRecorder
+		reconciler   *SnapshotReconciler
+		clientConfig *openawarenessv1beta1.ClientConfig
+		snap         *openawarenessv1beta1.Snapshot
+		request      ctrl.Request
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		mimirClient = clients.NewMockAwarenessClient()
+		Expect(mimirClient.CreateAlertmanagerConfig(ctx, teamConfig, nil, "org-payments")).To(Succeed())
+		cache := clients.NewMockRulerClientCache()
+		cache.SetClient("snapshot-client", mimirClient)
+		recorder = record.NewFakeRecorder(10)
+		reconciler = &SnapshotReconciler{
+			Client:       testClient,
+			Scheme:       scheme.Scheme,
+			RulerClients: cache,
+			Recorder:     recorder,
+		}
+
+		clientConfig = &openawarenessv1beta1.ClientConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: "snapshot-client", Namespace: "default"},
+			Spec: openawarenessv1beta1.ClientConfigSpec{
+				Address:       "http://localhost:9009",
+				Type:          openawarenessv1beta1.Mimir,
+				TenantAliases: map[string]string{"payments": "org-payments"},
+				DeniedTenants: []string{"org-denied"},
+			},
+		}
+		Expect(testClient.Create(ctx, clientConfig)).To(Succeed())
+		DeferCleanup(func() { Expect(testClient.Delete(ctx, clientConfig)).To(Succeed()) })
+
+		snap = &openawarenessv1beta1.Snapshot{
+			ObjectMeta: metav1.ObjectMeta{Name: "audit-payments", Namespace: "default"},
+			Spec: openawarenessv1beta1.SnapshotSpec{
+				ClientConfig: openawarenessv1beta1.ClientConfigReference{Namespace: "default", Name: "snapshot-client"},
+				Tenant:       "payments",
+				Destination:  openawarenessv1beta1.SnapshotDestination{ConfigMap: "audit-payments"},
+			},
+		}
+		request = ctrl.Request{NamespacedName: types.NamespacedName{Name: snap.Name, Namespace: snap.Namespace}}
+	})
+
+	createSnapshot := func() {
+		Expect(testClient.Create(ctx, snap)).To(Succeed())
+		DeferCleanup(func() { _ = testClient.Delete(ctx, snap) })
+	}
+
+	It("should capture the tenant to an owned ConfigMap once", func() {
+		createSnapshot()
+		_, err := reconciler.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+
+		configMap := &corev1.ConfigMap{}
+		Expect(testClient.Get(ctx, types.NamespacedName{Name: "audit-payments", Namespace: "default"}, configMap)).To(Succeed())
+		DeferCleanup(func() { Expect(testClient.Delete(ctx, configMap)).To(Succeed()) })
+		Expect(configMap.Data[snapshot.Key]).To(SatisfyAll(
+			ContainSubstring("tenant: org-payments"), ContainSubstring("receiver: team")))
+		Expect(metav1.IsControlledBy(configMap, snap)).To(BeTrue())
+
+		Expect(testClient.Get(ctx, request.NamespacedName, snap)).To(Succeed())
+		Expect(snap.Status.CompletionTime).NotTo(BeNil())
+		Expect(snap.Status.Tenant).To(Equal("org-payments"))
+		Expect(snap.Status.Location).To(Equal("ConfigMap/audit-payments"))
+		Expect(meta.IsStatusConditionTrue(snap.Status.Conditions, openawarenessv1beta1.ConditionTypeCompleted)).To(BeTrue())
+		Expect(recorder.Events).To(Receive(ContainSubstring("SnapshotCompleted")))
+
+		By("not capturing the tenant again")
+		Expect(mimirClient.CreateAlertmanagerConfig(ctx, "route:\n  receiver: changed\n", nil, "org-payments")).To(Succeed())
+		_, err = reconciler.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(testClient.Get(ctx, types.NamespacedName{Name: "audit-payments", Namespace: "default"}, configMap)).To(Succeed())
+		Expect(configMap.Data[snapshot.Key]).NotTo(ContainSubstring("receiver: changed"))
+	})
+
+	It("should delete the Snapshot once its retention expired", func() {
+		snap.Spec.Retention = &metav1.Duration{Duration: time.Hour}
+		createSnapshot()
+		result, err := reconciler.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(BeNumerically("~", time.Hour, time.Minute))
+		DeferCleanup(func() {
+			_ = testClient.Delete(ctx, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "audit-payments", Namespace: "default"},
+			})
+		})
+
+		Expect(testClient.Get(ctx, request.NamespacedName, snap)).To(Succeed())
+		original := snap.DeepCopy()
+		expired := metav1.NewTime(time.Now().Add(-2 * time.Hour))
+		snap.Status.CompletionTime = &expired
+		Expect(testClient.Status().Patch(ctx, snap, client.MergeFrom(original))).To(Succeed())
+
+		_, err = reconciler.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+		err = testClient.Get(ctx, request.NamespacedName, snap)
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("should upload the snapshot to a URL with the credentials of the secretRef", func() {
+		var authorization, body string
+		server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+			authorization = r.Header.Get("Authorization")
+			raw, _ := io.ReadAll(r.Body)
+			body = string(raw)
+		}))
+		DeferCleanup(server.Close)
+		credentials := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "snapshot-upload", Namespace: "default"},
+			StringData: map[string]string{"token": "upload-token"},
+		}
+		Expect(testClient.Create(ctx, credentials)).To(Succeed())
+		DeferCleanup(func() { Expect(testClient.Delete(ctx, credentials)).To(Succeed()) })
+
+		snap.Spec.Destination = openawarenessv1beta1.SnapshotDestination{
+			URL:       server.URL + "/audit.yaml?X-Amz-Signature=secret",
+			SecretRef: &corev1.LocalObjectReference{Name: "snapshot-upload"},
+		}
+		createSnapshot()
+		_, err := reconciler.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(authorization).To(Equal("Bearer upload-token"))
+		Expect(body).To(ContainSubstring("receiver: team"))
+		Expect(testClient.Get(ctx, request.NamespacedName, snap)).To(Succeed())
+		Expect(snap.Status.Location).To(Equal(server.URL + "/audit.yaml"))
+	})
+
+	It("should fail without retrying for denied tenants and foreign ConfigMaps", func() {
+		By("Denying the tenant of the Snapshot")
+		snap.Spec.Tenant = "org-denied"
+		createSnapshot()
+		_, err := reconciler.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(testClient.Get(ctx, request.NamespacedName, snap)).To(Succeed())
+		Expect(snap.Status.CompletionTime).To(BeNil())
+		condition := meta.FindStatusCondition(snap.Status.Conditions, openawarenessv1beta1.ConditionTypeCompleted)
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Reason).To(Equal(openawarenessv1beta1.ReasonTenantNotAllowed))
+		Expect(recorder.Events).To(Receive(ContainSubstring("SnapshotFailed")))
+
+		By("Refusing to overwrite a ConfigMap not owned by the Snapshot")
+		foreign := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "audit-payments", Namespace: "default"},
+			Data:       map[string]string{"app.yaml": "keep"},
+		}
+		Expect(testClient.Create(ctx, foreign)).To(Succeed())
+		DeferCleanup(func() { Expect(testClient.Delete(ctx, foreign)).To(Succeed()) })
+		snap.Spec.Tenant = "payments"
+		Expect(testClient.Update(ctx, snap)).To(Succeed())
+		_, err = reconciler.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(testClient.Get(ctx, types.NamespacedName{Name: "audit-payments", Namespace: "default"}, foreign)).To(Succeed())
+		Expect(foreign.Data).To(Equal(map[string]string{"app.yaml": "keep"}))
+		Expect(testClient.Get(ctx, request.NamespacedName, snap)).To(Succeed())
+		condition = meta.FindStatusCondition(snap.Status.Conditions, openawarenessv1beta1.ConditionTypeCompleted)
+		Expect(condition.Reason).To(Equal(openawarenessv1beta1.ReasonWriteFailed))
+	})
+})
@@ -0,0 +1,280 @@
+package openawareness
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	k8sClient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/cluster"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+	"sigs.k8s.io/yaml"
+
+	openawarenessv1beta1 "github.com/syndlex/openawareness-controller/api/openawareness/v1beta1"
+	"github.com/syndlex/openawareness-controller/internal/clients"
+	"github.com/syndlex/openawareness-controller/internal/controller/utils"
+	"github.com/syndlex/openawareness-controller/internal/events"
+	"github.com/syndlex/openawareness-controller/internal/logging"
+	"github.com/syndlex/openawareness-controller/internal/mimir"
+)
+
+// errInvalidBootstrapConfig is returned when the bootstrap template does not render to an
+// Alertmanager configuration
+var errInvalidBootstrapConfig = errors.New("invalid tenant bootstrap configuration")
+
+// TenantBootstrapReconciler pushes a minimal Alertmanager configuration to the tenants of
+// TenantMappings that have none, so new tenants never run on the fallback configuration of
+// Mimir. MimirAlertTenants of the tenant replace the bootstrap configuration when they push.
+type TenantBootstrapReconciler struct {
+	k8sClient.Client
+	RulerClients clients.RulerClientCacheInterface
+	// Recorder emits events on TenantMappings whose tenant was bootstrapped.
+	// Events are not emitted if nil.
+	Recorder record.EventRecorder
+	// Template is the Alertmanager configuration pushed to new tenants, rendered like the
+	// configuration of a MimirAlertTenant with [[ .Meta.Tenant ]] as the bootstrapped tenant
+	Template string
+	// GlobalValues provides controller-wide template values, nil if not configured
+	GlobalValues *utils.GlobalValues
+	// ClusterName identifies the cluster in templates as [[ .Meta.Cluster ]]
+	ClusterName string
+	// Identity names this installation in the managed-by header of bootstrapped
+	// configurations, see utils.InstanceIdentity
+	Identity string
+	// ResourceCluster is the hub cluster MimirAlertTenants are read from, the manager's
+	// cluster if nil. TenantMappings and ClientConfigs are always read from the manager's cluster.
+	ResourceCluster cluster.Cluster
+	// ReadOnly only logs the tenants that would be bootstrapped
+	ReadOnly bool
+}
+
+// +kubebuilder:rbac:groups=openawareness.syndlex,resources=tenantmappings,verbs=get;list;watch
+// +kubebuilder:rbac:groups=openawareness.syndlex,resources=clientconfigs,verbs=get;list;watch
+// +kubebuilder:rbac:groups=openawareness.syndlex,resources=mimiralerttenants,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+
+// Reconcile bootstraps the tenant of a TenantMapping.
+//
+// The reconciliation process:
+// 1. Fetches the TenantMapping, mappings without tenant are skipped
+// 2. Resolves its ClientConfig and tenant alias like the resources it maps
+// 3. Reads the Alertmanager configuration of the tenant from Mimir
+// 4. Pushes the rendered bootstrap configuration if Mimir serves the fallback configuration
+//
+// Tenants with a configuration are left alone, whether it was pushed by a MimirAlertTenant, a
+// previous bootstrap or outside of the controller. The bootstrap configuration carries a
+// managed-by header naming the TenantMapping.
+func (r *TenantBootstrapReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	mapping := &openawarenessv1beta1.TenantMapping{}
+	if err := r.Get(ctx, req.NamespacedName, mapping); err != nil {
+		return ctrl.Result{}, k8sClient.IgnoreNotFound(err)
+	}
+	if !mapping.DeletionTimestamp.IsZero() || mapping.Spec.Tenant == "" {
+		return ctrl.Result{}, nil
+	}
+
+	clientConfig, err := r.clientConfigFor(ctx, mapping)
+	if apierrors.IsNotFound(err) {
+		// The mapping is bootstrapped once the ClientConfig is created
+		logger.V(1).Info("ClientConfig of the TenantMapping not found, skipping tenant bootstrap")
+		return ctrl.Result{}, nil
+	}
+	if err != nil {
+		logger.Error(err, "Failed to resolve the ClientConfig of the TenantMapping")
+		return ctrl.Result{}, err
+	}
+	if clientConfig == nil {
+		logger.V(1).Info("No ClientConfig applies to the TenantMapping, skipping tenant bootstrap")
+		return ctrl.Result{}, nil
+	}
+	tenantID := clientConfig.ResolveTenant(mapping.Spec.Tenant)
+	logger = logger.WithValues(logging.KeyTenant, tenantID, logging.KeyClient, clientConfig.Name)
+	if err := utils.CheckTenantsAllowed(clientConfig, tenantID); err != nil {
+		logger.Info("Tenant is not allowed by the ClientConfig, skipping tenant bootstrap")
+		return ctrl.Result{}, nil
+	}
+
+	awarenessClient, err := utils.ClientForConfig(ctx, r.RulerClients, clientConfig)
+	if err != nil {
+		logger.Error(err, "Failed to get Mimir client")
+		return ctrl.Result{}, err
+	}
+	if awarenessClient == nil {
+		// Prometheus clients have no Alertmanager to bootstrap
+		return ctrl.Result{}, nil
+	}
+	stored, _, err := awarenessClient.GetAlertmanagerConfig(ctx, tenantID)
+	if err != nil {
+		logger.Error(err, "Failed to get the Alertmanager configuration of the tenant")
+		return ctrl.Result{}, err
+	}
+	if !mimir.UsesFallbackConfig(stored) {
+		logger.V(1).Info("Tenant has an Alertmanager configuration, skipping tenant bootstrap")
+		return ctrl.Result{}, nil
+	}
+
+	config, err := r.render(ctx, mapping, tenantID, clientConfig)
+	if err != nil {
+		logger.Error(err, "Failed to render the tenant bootstrap configuration")
+		events.Eventf(r.Recorder, mapping, events.TenantBootstrapFailed,
+			"Failed to render the bootstrap configuration of tenant %s: %s", tenantID, err)
+		if errors.Is(err, errInvalidBootstrapConfig) {
+			// The template only changes with a restart, retrying does not help
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+	if r.ReadOnly {
+		logger.Info("Tenant is served the fallback configuration, not bootstrapping it in read-only mode")
+		return ctrl.Result{}, nil
+	}
+	if err := awarenessClient.CreateAlertmanagerConfig(ctx, config, nil, tenantID); err != nil {
+		logger.Error(err, "Failed to push the tenant bootstrap configuration")
+		events.Eventf(r.Recorder, mapping, events.TenantBootstrapFailed,
+			"Failed to push the bootstrap configuration of tenant %s: %s", tenantID, err)
+		return ctrl.Result{}, err
+	}
+
+	logger.Info("Bootstrapped Alertmanager configuration of tenant")
+	events.Eventf(r.Recorder, mapping, events.TenantBootstrapped,
+		"Pushed the bootstrap Alertmanager configuration to tenant %s of ClientConfig %s",
+		tenantID, utils.OwnerReference(clientConfig))
+	return ctrl.Result{}, nil
+}
+
+// clientConfigFor returns the ClientConfig referenced by the mapping, or the cluster-wide
+// default ClientConfig. Returns nil if the mapping references none and no default exists.
+func (r *TenantBootstrapReconciler) clientConfigFor(
+	ctx context.Context,
+	mapping *openawarenessv1beta1.TenantMapping,
+) (*openawarenessv1beta1.ClientConfig, error) {
+	if ref := mapping.Spec.ClientConfig; ref != nil {
+		clientConfig := &openawarenessv1beta1.ClientConfig{}
+		if err := r.Get(ctx, k8sClient.ObjectKey{Name: ref.Name, Namespace: ref.Namespace}, clientConfig); err != nil {
+			return nil, fmt.Errorf("getting ClientConfig %s/%s: %w", ref.Namespace, ref.Name, err)
+		}
+		return clientConfig, nil
+	}
+
+	clientConfigs := &openawarenessv1beta1.ClientConfigList{}
+	if err := r.List(ctx, clientConfigs); err != nil {
+		return nil, fmt.Errorf("listing ClientConfigs: %w", err)
+	}
+	// TenantMappings are cluster-scoped, only cluster-wide defaults apply
+	return utils.DefaultClientConfig(clientConfigs.Items, "")
+}
+
+// render renders the bootstrap template for the tenant and prefixes it with a managed-by header
+// naming the mapping. Returns an error wrapping errInvalidBootstrapConfig if the template does
+// not render to a YAML document.
+func (r *TenantBootstrapReconciler) render(
+	ctx context.Context,
+	mapping *openawarenessv1beta1.TenantMapping,
+	tenantID string,
+	clientConfig *openawarenessv1beta1.ClientConfig,
+) (string, error) {
+	globals, err := r.GlobalValues.Get(ctx)
+	if err != nil {
+		return "", err
+	}
+	config, err := RenderTenantBootstrapConfig(r.Template, utils.TemplateBuiltins{
+		Global: globals,
+		Meta: utils.TemplateMetadata{
+			Name:       mapping.Name,
+			Tenant:     tenantID,
+			ClientName: clientConfig.Name,
+			Cluster:    r.ClusterName,
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+	header := fmt.Sprintf("%s TenantMapping %s", utils.ManagedByHeader, mapping.Name)
+	if r.Identity != "" {
+		header += " instance=" + r.Identity
+	}
+	return header + "\n" + config, nil
+}
+
+// RenderTenantBootstrapConfig renders the bootstrap template with the given builtins.
+// Returns an error wrapping errInvalidBootstrapConfig if the template does not parse or render
+// to a YAML document, so it can be checked on start with placeholder builtins.
+func RenderTenantBootstrapConfig(template string, builtins utils.TemplateBuiltins) (string, error) {
+	config, err := utils.RenderTemplateWithBuiltins(template, nil, builtins)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", errInvalidBootstrapConfig, err)
+	}
+	document := map[string]any{}
+	if err := yaml.Unmarshal([]byte(config), &document); err != nil {
+		return "", fmt.Errorf("%w: %w", errInvalidBootstrapConfig, err)
+	}
+	if len(document) == 0 {
+		return "", fmt.Errorf("%w: the configuration is empty", errInvalidBootstrapConfig)
+	}
+	return config, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+// ClientConfigs are watched so mappings are bootstrapped once their ClientConfig exists, and
+// deleted MimirAlertTenants so a tenant whose configuration was removed with its
+// MimirAlertTenant is bootstrapped again.
+func (r *TenantBootstrapReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	resources := cluster.Cluster(mgr)
+	if r.ResourceCluster != nil {
+		resources = r.ResourceCluster
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("tenantbootstrap").
+		For(&openawarenessv1beta1.TenantMapping{},
+			builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(
+			&openawarenessv1beta1.ClientConfig{},
+			handler.EnqueueRequestsFromMapFunc(r.findAllMappings),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{}),
+		).
+		WatchesRawSource(source.Kind(resources.GetCache(), &openawarenessv1beta1.MimirAlertTenant{},
+			handler.TypedEnqueueRequestsFromMapFunc(func(
+				ctx context.Context, _ *openawarenessv1beta1.MimirAlertTenant,
+			) []reconcile.Request {
+				return r.findAllMappings(ctx, nil)
+			}),
+			predicate.TypedFuncs[*openawarenessv1beta1.MimirAlertTenant]{
+				CreateFunc: func(event.TypedCreateEvent[*openawarenessv1beta1.MimirAlertTenant]) bool { return false },
+				UpdateFunc: func(event.TypedUpdateEvent[*openawarenessv1beta1.MimirAlertTenant]) bool { return false },
+				GenericFunc: func(event.TypedGenericEvent[*openawarenessv1beta1.MimirAlertTenant]) bool {
+					return false
+				},
+			})).
+		WithOptions(controller.Options{LogConstructor: logging.LogConstructor(mgr.GetLogger(), "tenantbootstrap")}).
+		Complete(logging.TrackAttempts(r))
+}
+
+// findAllMappings maps a change to reconciliation requests of all TenantMappings with a tenant.
+func (r *TenantBootstrapReconciler) findAllMappings(ctx context.Context, _ k8sClient.Object) []reconcile.Request {
+	mappings := &openawarenessv1beta1.TenantMappingList{}
+	if err := r.List(ctx, mappings); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to list TenantMappings for tenant bootstrap")
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, mapping := range mappings.Items {
+		if mapping.Spec.Tenant != "" {
+			requests = append(requests, reconcile.Request{NamespacedName: k8sClient.ObjectKeyFromObject(&mapping)})
+		}
+	}
+	return requests
+}
@@ -0,0 +1,139 @@
+package openawareness
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/syndlex/openawareness-controller/internal/clients"
+	"github.com/syndlex/openawareness-controller/internal/controller/utils"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	openawarenessv1beta1 "github.com/syndlex/openawareness-controller/api/openawareness/v1beta1"
+)
+
+var _ = Describe("TenantBootstrap Controller", func() {
+	const bootstrapTemplate = `route:
+  receiver: default
+receivers:
+  - name: default
+    webhook_configs:
+      - url: http://alerts.example.org/[[ .Meta.Tenant ]]
+`
+
+	var (
+		ctx          context.Context
+		mimirClient  *clients.MockAwarenessClient
+		recorder     *record.FakeRecorder
+		reconciler   *TenantBootstrapReconciler
+		clientConfig *openawarenessv1beta1.ClientConfig
+		mapping      *openawarenessv1beta1.TenantMapping
+		request      ctrl.Request
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		mimirClient = clients.NewMockAwarenessClient()
+		cache := clients.NewMockRulerClientCache()
+		cache.SetClient("bootstrap-client", mimirClient)
+		recorder = record.NewFakeRecorder(10)
+		reconciler = &TenantBootstrapReconciler{
+			Client:       testClient,
+			RulerClients: cache,
+			Recorder:     recorder,
+			Template:     bootstrapTemplate,
+		}
+
+		clientConfig = &openawarenessv1beta1.ClientConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: "bootstrap-client", Namespace: "default"},
+			Spec: openawarenessv1beta1.ClientConfigSpec{
+				Address:       "http://localhost:9009",
+				Type:          openawarenessv1beta1.Mimir,
+				TenantAliases: map[string]string{"payments": "org-payments"},
+				DeniedTenants: []string{"org-denied"},
+			},
+		}
+		Expect(testClient.Create(ctx, clientConfig)).To(Succeed())
+		DeferCleanup(func() { Expect(testClient.Delete(ctx, clientConfig)).To(Succeed()) })
+
+		mapping = &openawarenessv1beta1.TenantMapping{
+			ObjectMeta: metav1.ObjectMeta{Name: "bootstrap-payments"},
+			Spec: openawarenessv1beta1.TenantMappingSpec{
+				NamespaceSelector: metav1.LabelSelector{MatchLabels: map[string]string{"team": "payments"}},
+				Tenant:            "payments",
+				ClientConfig: &openawarenessv1beta1.ClientConfigReference{
+					Namespace: "default",
+					Name:      "bootstrap-client",
+				},
+			},
+		}
+		Expect(testClient.Create(ctx, mapping)).To(Succeed())
+		DeferCleanup(func() { Expect(testClient.Delete(ctx, mapping)).To(Succeed()) })
+		request = ctrl.Request{NamespacedName: types.NamespacedName{Name: mapping.Name}}
+	})
+
+	It("should push the bootstrap configuration to a tenant served the fallback configuration", func() {
+		_, err := reconciler.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+
+		stored, _, err := mimirClient.GetAlertmanagerConfig(ctx, "org-payments")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stored).To(HavePrefix(utils.ManagedByHeader + " TenantMapping bootstrap-payments\n"))
+		Expect(stored).To(ContainSubstring("url: http://alerts.example.org/org-payments"))
+		Expect(recorder.Events).To(Receive(ContainSubstring("TenantBootstrapped")))
+	})
+
+	It("should leave tenants with a configuration alone", func() {
+		const existing = "route:\n  receiver: team\nreceivers:\n  - name: team\n"
+		Expect(mimirClient.CreateAlertmanagerConfig(ctx, existing, nil, "org-payments")).To(Succeed())
+
+		_, err := reconciler.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+
+		stored, _, err := mimirClient.GetAlertmanagerConfig(ctx, "org-payments")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stored).To(Equal(existing))
+		Expect(recorder.Events).To(BeEmpty())
+	})
+
+	It("should not bootstrap denied tenants or in read-only mode", func() {
+		By("Denying the tenant of the mapping")
+		mapping.Spec.Tenant = "org-denied"
+		Expect(testClient.Update(ctx, mapping)).To(Succeed())
+		_, err := reconciler.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+		stored, _, _ := mimirClient.GetAlertmanagerConfig(ctx, "org-denied")
+		Expect(stored).To(BeEmpty())
+
+		By("Enabling the read-only mode")
+		mapping.Spec.Tenant = "payments"
+		Expect(testClient.Update(ctx, mapping)).To(Succeed())
+		reconciler.ReadOnly = true
+		_, err = reconciler.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+		stored, _, _ = mimirClient.GetAlertmanagerConfig(ctx, "org-payments")
+		Expect(stored).To(BeEmpty())
+		Expect(recorder.Events).To(BeEmpty())
+	})
+
+	It("should retry failed pushes", func() {
+		mimirClient.SetCreateAlertConfigError(errors.New("unavailable"))
+
+		_, err := reconciler.Reconcile(ctx, request)
+		Expect(err).To(HaveOccurred())
+		Expect(recorder.Events).To(Receive(ContainSubstring("TenantBootstrapFailed")))
+	})
+})
+
+var _ = Describe("RenderTenantBootstrapConfig", func() {
+	It("should refuse templates not rendering to a configuration", func() {
+		for _, template := range []string{"", "# only a comment\n", "route: [[ .Meta.Tenant", "route: [unclosed"} {
+			_, err := RenderTenantBootstrapConfig(template, utils.TemplateBuiltins{})
+			Expect(errors.Is(err, errInvalidBootstrapConfig)).To(BeTrue(), "template %q", template)
+		}
+	})
+})
@@ -0,0 +1,142 @@
+//nolint:revive // utils is a standard package name for utilities
+package utils
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+	k8sClient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	openawarenessv1beta1 "github.com/syndlex/openawareness-controller/api/openawareness/v1beta1"
+)
+
+// ErrInvalidGlobals is returned for MimirAlertGlobals that cannot be merged into a configuration
+var ErrInvalidGlobals = errors.New("invalid global settings")
+
+// ErrDuplicateGlobals is returned for MimirAlertGlobals setting a key already set by other
+// MimirAlertGlobals of the same tenant
+var ErrDuplicateGlobals = errors.New("duplicate global settings")
+
+// ComposedAlertGlobals returns the MimirAlertGlobals contributed to the tenants of a chain
+// returned by ResolveExtends, in the order they are merged: the globals of base tenants
+// first and the globals of each tenant sorted by name.
+func ComposedAlertGlobals(
+	ctx context.Context,
+	reader k8sClient.Reader,
+	chain []*openawarenessv1beta1.MimirAlertTenant,
+) ([]openawarenessv1beta1.MimirAlertGlobals, error) {
+	namespace := chain[len(chain)-1].Namespace
+	globalsList := &openawarenessv1beta1.MimirAlertGlobalsList{}
+	if err := reader.List(ctx, globalsList, k8sClient.InNamespace(namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list MimirAlertGlobals in %s: %w", namespace, err)
+	}
+
+	position := func(globals openawarenessv1beta1.MimirAlertGlobals) int {
+		return slices.IndexFunc(chain, func(tenant *openawarenessv1beta1.MimirAlertTenant) bool {
+			return tenant.Name == globals.Spec.Tenant.Name
+		})
+	}
+	var composed []openawarenessv1beta1.MimirAlertGlobals
+	for _, globals := range globalsList.Items {
+		if position(globals) >= 0 {
+			composed = append(composed, globals)
+		}
+	}
+	slices.SortFunc(composed, func(a, b openawarenessv1beta1.MimirAlertGlobals) int {
+		return cmp.Or(cmp.Compare(position(a), position(b)), strings.Compare(a.Name, b.Name))
+	})
+	return composed, nil
+}
+
+// MergeAlertGlobals sets the global settings of globals in the global section of the rendered
+// Alertmanager configuration config. The settings are rendered with the same template data and
+// builtins as the configuration. Their keys replace the keys of the configuration and of the
+// MimirAlertGlobals of base tenants, so a tenant can override inherited settings.
+// MimirAlertGlobals are rejected and left out if they cannot be rendered or parsed, or set a key
+// already set by MimirAlertGlobals of the same tenant merged before them.
+// Returns the merged configuration and the rejection errors by MimirAlertGlobals name, which
+// wrap ErrInvalidGlobals or ErrDuplicateGlobals, or an error wrapping ErrComposition if config
+// is not a YAML mapping.
+func MergeAlertGlobals(
+	config string,
+	globals []openawarenessv1beta1.MimirAlertGlobals,
+	data map[string]string,
+	builtins TemplateBuiltins,
+) (string, map[string]error, error) {
+	if len(globals) == 0 {
+		return config, nil, nil
+	}
+	merged, err := unmarshalMapping(config)
+	if err != nil {
+		return "", nil, fmt.Errorf("%w with MimirAlertGlobals: %w", ErrComposition, err)
+	}
+
+	global, _ := merged["global"].(map[string]any)
+	if global == nil {
+		global = map[string]any{}
+	}
+	// owners tracks the MimirAlertGlobals setting a key for the tenant they belong to
+	owners := map[string]map[string]string{}
+	rejected := map[string]error{}
+	for _, entry := range globals {
+		settings, err := renderAlertGlobals(entry, data, builtins)
+		if err != nil {
+			rejected[entry.Name] = err
+			continue
+		}
+		tenantOwners := owners[entry.Spec.Tenant.Name]
+		if tenantOwners == nil {
+			tenantOwners = map[string]string{}
+			owners[entry.Spec.Tenant.Name] = tenantOwners
+		}
+		if key, owner, ok := firstOwnedKey(settings, tenantOwners); ok {
+			rejected[entry.Name] = fmt.Errorf("%w: %s is already set by MimirAlertGlobals %s",
+				ErrDuplicateGlobals, key, owner)
+			continue
+		}
+		for key := range settings {
+			tenantOwners[key] = entry.Name
+		}
+		maps.Copy(global, settings)
+	}
+
+	merged["global"] = global
+	rendered, err := yaml.Marshal(merged)
+	if err != nil {
+		return "", nil, err
+	}
+	return string(rendered), rejected, nil
+}
+
+// renderAlertGlobals renders and parses the global settings of MimirAlertGlobals.
+func renderAlertGlobals(
+	globals openawarenessv1beta1.MimirAlertGlobals,
+	data map[string]string,
+	builtins TemplateBuiltins,
+) (map[string]any, error) {
+	rendered, err := RenderTemplateWithBuiltins(globals.Spec.Global, data, builtins)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidGlobals, err)
+	}
+	settings, err := unmarshalMapping(rendered)
+	if err != nil {
+		return nil, fmt.Errorf("%w: global must be a YAML mapping: %w", ErrInvalidGlobals, err)
+	}
+	return settings, nil
+}
+
+// firstOwnedKey returns the first key of settings in sorted order that has an owner.
+func firstOwnedKey(settings map[string]any, owners map[string]string) (string, string, bool) {
+	for _, key := range slices.Sorted(maps.Keys(settings)) {
+		if owner, ok := owners[key]; ok {
+			return key, owner, true
+		}
+	}
+	return "", "", false
+}
@@ -0,0 +1,120 @@
+//nolint:revive // utils is a standard package name for utilities
+package utils
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	openawarenessv1beta1 "github.com/syndlex/openawareness-controller/api/openawareness/v1beta1"
+)
+
+func alertGlobals(name, tenant, global string) openawarenessv1beta1.MimirAlertGlobals {
+	return openawarenessv1beta1.MimirAlertGlobals{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "team"},
+		Spec: openawarenessv1beta1.MimirAlertGlobalsSpec{
+			Tenant: openawarenessv1beta1.TenantReference{Name: tenant},
+			Global: global,
+		},
+	}
+}
+
+func TestComposedAlertGlobals(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := openawarenessv1beta1.AddToScheme(scheme); err != nil {
+		t.Fatalf("adding scheme: %v", err)
+	}
+	globals := []openawarenessv1beta1.MimirAlertGlobals{
+		alertGlobals("a-smtp", "oncall", ""),
+		alertGlobals("z-platform", "root", ""),
+		alertGlobals("b-slack", "oncall", ""),
+		alertGlobals("search", "other", ""),
+	}
+	builder := fake.NewClientBuilder().WithScheme(scheme)
+	for i := range globals {
+		builder = builder.WithObjects(&globals[i])
+	}
+	chain := []*openawarenessv1beta1.MimirAlertTenant{
+		extendingTenant("root", "", ""),
+		extendingTenant("oncall", "root", ""),
+	}
+
+	composed, err := ComposedAlertGlobals(context.Background(), builder.Build(), chain)
+	if err != nil {
+		t.Fatalf("ComposedAlertGlobals() error = %v", err)
+	}
+	var names []string
+	for _, entry := range composed {
+		names = append(names, entry.Name)
+	}
+	if !reflect.DeepEqual(names, []string{"z-platform", "a-smtp", "b-slack"}) {
+		t.Errorf("expected the globals of base tenants first, sorted by name, got %v", names)
+	}
+}
+
+func TestMergeAlertGlobals(t *testing.T) {
+	config := `
+global:
+  resolve_timeout: 1m
+  smtp_from: team@example.org
+route:
+  receiver: default
+receivers:
+  - name: default
+`
+	globals := []openawarenessv1beta1.MimirAlertGlobals{
+		alertGlobals("platform", "root", "resolve_timeout: 10m\nsmtp_smarthost: smtp.example.org:587"),
+		alertGlobals("smtp", "oncall", "resolve_timeout: 5m\nsmtp_auth_password: '[[ .PASSWORD ]]'"),
+		alertGlobals("duplicate", "oncall", "slack_api_url: https://slack\nresolve_timeout: 2m"),
+		alertGlobals("invalid", "oncall", "- a list"),
+	}
+
+	merged, rejected, err := MergeAlertGlobals(config, globals, map[string]string{"PASSWORD": "secret"}, TemplateBuiltins{})
+	if err != nil {
+		t.Fatalf("MergeAlertGlobals() error = %v", err)
+	}
+	want := `
+global:
+  resolve_timeout: 5m
+  smtp_from: team@example.org
+  smtp_smarthost: smtp.example.org:587
+  smtp_auth_password: secret
+route:
+  receiver: default
+receivers:
+  - name: default
+`
+	var got, expected map[string]any
+	if err := yaml.Unmarshal([]byte(merged), &got); err != nil {
+		t.Fatalf("merged configuration is invalid: %v", err)
+	}
+	if err := yaml.Unmarshal([]byte(want), &expected); err != nil {
+		t.Fatalf("expected configuration is invalid: %v", err)
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("unexpected merged configuration:\n%s", merged)
+	}
+
+	if !errors.Is(rejected["duplicate"], ErrDuplicateGlobals) {
+		t.Errorf("expected a duplicate globals error, got %v", rejected["duplicate"])
+	}
+	if !errors.Is(rejected["invalid"], ErrInvalidGlobals) {
+		t.Errorf("expected an invalid globals error, got %v", rejected["invalid"])
+	}
+	if len(rejected) != 2 {
+		t.Errorf("expected 2 rejected globals, got %v", rejected)
+	}
+
+	if unchanged, _, err := MergeAlertGlobals("- a list", nil, nil, TemplateBuiltins{}); err != nil || unchanged != "- a list" {
+		t.Errorf("expected the configuration unchanged without globals, got %q, %v", unchanged, err)
+	}
+	if _, _, err := MergeAlertGlobals("- a list", globals, nil, TemplateBuiltins{}); !errors.Is(err, ErrComposition) {
+		t.Errorf("expected a composition error, got %v", err)
+	}
+}
@@ -0,0 +1,101 @@
+//nolint:revive // utils is a standard package name for utilities
+package utils
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	k8sClient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	openawarenessv1beta1 "github.com/syndlex/openawareness-controller/api/openawareness/v1beta1"
+)
+
+// RenderAlertmanagerConfig renders the Alertmanager configuration of a MimirAlertTenant the
+// way the MimirAlertTenant controller pushes it: the extended tenants, alertmanagerConfigFrom
+// Secrets, SecretDataReferences and global values are resolved through reader, the configurations of the extended tenants
+// are rendered with the tenant's metadata and composed with the files of their templateSources
+// fetched through templateSources, the MimirAlertRoutes,
+// MimirAlertGlobals, MimirMuteTimings and MimirInhibitRules of the tenants are merged, leaving out
+// rejected ones, and the managed-by header is added.
+// Returns the configuration and the composed template files, or an error if a base tenant,
+// a configuration Secret, a template source, the routes, the globals, the mute timings, the inhibition rules or the template data cannot be read or the configuration cannot
+// be rendered.
+func RenderAlertmanagerConfig(
+	ctx context.Context,
+	reader k8sClient.Reader,
+	logger logr.Logger,
+	tenant *openawarenessv1beta1.MimirAlertTenant,
+	globalValues *GlobalValues,
+	clusterName string,
+	templateSources TemplateSourceFetcher,
+) (string, map[string]string, error) {
+	chain, err := ResolveExtends(ctx, reader, tenant)
+	if err != nil {
+		return "", nil, err
+	}
+	chain, err = ResolveConfigSources(ctx, reader, chain)
+	if err != nil {
+		return "", nil, err
+	}
+	chain, err = ResolveTemplateSources(ctx, templateSources, chain)
+	if err != nil {
+		return "", nil, err
+	}
+
+	data, _, err := GetSecretData(ctx, reader, logger, tenant.Namespace,
+		ComposedReferences(chain), tenant.Spec.ReferenceMergeStrategy)
+	if err != nil {
+		return "", nil, err
+	}
+
+	globals, err := globalValues.Get(ctx)
+	if err != nil {
+		return "", nil, err
+	}
+
+	builtins := TemplateBuiltins{
+		Global: globals,
+		Meta:   NewTemplateMetadata(tenant, clusterName),
+	}
+	rendered, err := RenderComposedConfig(chain, data, builtins)
+	if err != nil {
+		return "", nil, err
+	}
+
+	routes, err := ComposedRoutes(ctx, reader, chain)
+	if err != nil {
+		return "", nil, err
+	}
+	rendered, _, err = MergeRoutes(rendered, routes, data, builtins)
+	if err != nil {
+		return "", nil, err
+	}
+
+	alertGlobals, err := ComposedAlertGlobals(ctx, reader, chain)
+	if err != nil {
+		return "", nil, err
+	}
+	rendered, _, err = MergeAlertGlobals(rendered, alertGlobals, data, builtins)
+	if err != nil {
+		return "", nil, err
+	}
+
+	muteTimings, err := ComposedMuteTimings(ctx, reader, chain)
+	if err != nil {
+		return "", nil, err
+	}
+	rendered, _, err = MergeMuteTimings(rendered, muteTimings)
+	if err != nil {
+		return "", nil, err
+	}
+
+	inhibitRules, err := ComposedInhibitRules(ctx, reader, chain)
+	if err != nil {
+		return "", nil, err
+	}
+	rendered, _, err = MergeInhibitRules(rendered, inhibitRules)
+	if err != nil {
+		return "", nil, err
+	}
+	return AddManagedByHeader(rendered, "MimirAlertTenant", tenant, ""), ComposedTemplateFiles(chain), nil
+}
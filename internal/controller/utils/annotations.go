@@ -2,9 +2,15 @@
 package utils
 
 import (
+	"encoding/json"
 	"fmt"
+	"maps"
+	"slices"
+	"strings"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	openawarenessv1beta1 "github.com/syndlex/openawareness-controller/api/openawareness/v1beta1"
 )
 
 // GetRequiredAnnotations extracts and validates required annotations from a Kubernetes object.
@@ -42,3 +48,157 @@ func GetRequiredAnnotations(obj metav1.Object, annotationKeys ...string) (map[st
 
 	return result, nil
 }
+
+// GetTenantID returns the Mimir tenant referenced by the object's tenant annotation, resolved
+// through the tenant aliases of clientConfig. Objects without the annotation use the default
+// tenant of clientConfig, falling back to DefaultTenantID. clientConfig may be nil, e.g. before
+// it is read, in which case the annotation is used as written.
+func GetTenantID(obj metav1.Object, clientConfig *openawarenessv1beta1.ClientConfig) string {
+	if tenantID := clientConfig.ResolveTenant(obj.GetAnnotations()[MimirTenantAnnotation]); tenantID != "" {
+		return tenantID
+	}
+	return DefaultTenantID
+}
+
+// RecordingTenantID returns the Mimir tenant recording rules of the object are pushed to.
+// Falls back to GetTenantID when the recording-tenant annotation is missing or empty.
+func RecordingTenantID(obj metav1.Object, clientConfig *openawarenessv1beta1.ClientConfig) string {
+	if tenantID := obj.GetAnnotations()[RecordingTenantAnnotation]; tenantID != "" {
+		return clientConfig.ResolveTenant(tenantID)
+	}
+	return GetTenantID(obj, clientConfig)
+}
+
+// AlertingTenantID returns the Mimir tenant alerting rules of the object are pushed to.
+// Falls back to GetTenantID when the alerting-tenant annotation is missing or empty.
+func AlertingTenantID(obj metav1.Object, clientConfig *openawarenessv1beta1.ClientConfig) string {
+	if tenantID := obj.GetAnnotations()[AlertingTenantAnnotation]; tenantID != "" {
+		return clientConfig.ResolveTenant(tenantID)
+	}
+	return GetTenantID(obj, clientConfig)
+}
+
+// GroupTenants returns the Mimir tenants of the rule groups named in the GroupTenantsAnnotation of
+// the object, by group name, resolved through the tenant aliases of clientConfig, which may be
+// nil. Returns nil without the annotation, and an error if it is not a JSON object mapping group
+// names to non-empty tenants.
+func GroupTenants(obj metav1.Object, clientConfig *openawarenessv1beta1.ClientConfig) (map[string]string, error) {
+	value, ok := obj.GetAnnotations()[GroupTenantsAnnotation]
+	if !ok {
+		return nil, nil
+	}
+	var groupTenants map[string]string
+	if err := json.Unmarshal([]byte(value), &groupTenants); err != nil {
+		return nil, fmt.Errorf("invalid %s annotation, expected a JSON object mapping group names to tenants: %w",
+			GroupTenantsAnnotation, err)
+	}
+	for group, tenantID := range groupTenants {
+		tenantID = strings.TrimSpace(tenantID)
+		if group == "" || tenantID == "" {
+			return nil, fmt.Errorf("invalid %s annotation, group %q has no tenant", GroupTenantsAnnotation, group)
+		}
+		groupTenants[group] = clientConfig.ResolveTenant(tenantID)
+	}
+	return groupTenants, nil
+}
+
+// SourceTenants returns the source tenants of the rule groups named in the SourceTenantsAnnotation
+// of the object, by group name, resolved through the tenant aliases of clientConfig, which may be
+// nil, sorted and without duplicates. Returns nil without the annotation, and an error if it is
+// not a JSON object mapping group names to non-empty lists of non-empty tenants.
+func SourceTenants(obj metav1.Object, clientConfig *openawarenessv1beta1.ClientConfig) (map[string][]string, error) {
+	value, ok := obj.GetAnnotations()[SourceTenantsAnnotation]
+	if !ok {
+		return nil, nil
+	}
+	var sourceTenants map[string][]string
+	if err := json.Unmarshal([]byte(value), &sourceTenants); err != nil {
+		return nil, fmt.Errorf("invalid %s annotation, expected a JSON object mapping group names to lists of tenants: %w",
+			SourceTenantsAnnotation, err)
+	}
+	for group, tenantIDs := range sourceTenants {
+		if group == "" || len(tenantIDs) == 0 {
+			return nil, fmt.Errorf("invalid %s annotation, group %q has no source tenants", SourceTenantsAnnotation, group)
+		}
+		resolved := make([]string, 0, len(tenantIDs))
+		for _, tenantID := range tenantIDs {
+			if tenantID = strings.TrimSpace(tenantID); tenantID == "" {
+				return nil, fmt.Errorf("invalid %s annotation, group %q has an empty source tenant",
+					SourceTenantsAnnotation, group)
+			}
+			resolved = append(resolved, clientConfig.ResolveTenant(tenantID))
+		}
+		slices.Sort(resolved)
+		sourceTenants[group] = slices.Compact(resolved)
+	}
+	return sourceTenants, nil
+}
+
+// TenantIDs returns the distinct Mimir tenants the object is synced to: its recording
+// and alerting tenants, which are both GetTenantID unless overridden by annotation, followed by
+// the tenants of GroupTenants in order. An invalid GroupTenantsAnnotation adds no tenants, it is
+// reported when the rule groups are converted.
+func TenantIDs(obj metav1.Object, clientConfig *openawarenessv1beta1.ClientConfig) []string {
+	recordingTenant, alertingTenant := RecordingTenantID(obj, clientConfig), AlertingTenantID(obj, clientConfig)
+	tenantIDs := []string{recordingTenant}
+	if recordingTenant != alertingTenant {
+		tenantIDs = append(tenantIDs, alertingTenant)
+	}
+	groupTenants, _ := GroupTenants(obj, clientConfig)
+	for _, tenantID := range slices.Sorted(maps.Values(groupTenants)) {
+		if !slices.Contains(tenantIDs, tenantID) {
+			tenantIDs = append(tenantIDs, tenantID)
+		}
+	}
+	return tenantIDs
+}
+
+// RulesNamespace returns the Mimir rule namespace the rule groups of the object are pushed to,
+// set by the RulesNamespaceAnnotation. Defaults to the Kubernetes namespace of the object.
+func RulesNamespace(obj metav1.Object) string {
+	if namespace := strings.TrimSpace(obj.GetAnnotations()[RulesNamespaceAnnotation]); namespace != "" {
+		return namespace
+	}
+	return obj.GetNamespace()
+}
+
+// SyncedRulesNamespace returns the Mimir rule namespace the rule groups of the object were last
+// synced to, recorded in the SyncedRulesNamespaceAnnotation. Defaults to the Kubernetes namespace
+// of the object.
+func SyncedRulesNamespace(obj metav1.Object) string {
+	if namespace := obj.GetAnnotations()[SyncedRulesNamespaceAnnotation]; namespace != "" {
+		return namespace
+	}
+	return obj.GetNamespace()
+}
+
+// TemplatingEnabled reports whether the object opts in to templating with TemplateAnnotation.
+func TemplatingEnabled(obj metav1.Object) bool {
+	return obj.GetAnnotations()[TemplateAnnotation] == "true"
+}
+
+// DualWriteEnabled reports whether the object opts in to the parity check with DualWriteAnnotation.
+func DualWriteEnabled(obj metav1.Object) bool {
+	return obj.GetAnnotations()[DualWriteAnnotation] == "true"
+}
+
+// SecretDataRefs returns the ConfigMaps and Secrets listed by the SecretDataRefsAnnotation of the
+// object in the form "ConfigMap/name,Secret/name", nil without the annotation.
+// Returns an error if an entry is not of that form.
+func SecretDataRefs(obj metav1.Object) ([]openawarenessv1beta1.SecretDataReference, error) {
+	value := obj.GetAnnotations()[SecretDataRefsAnnotation]
+	var refs []openawarenessv1beta1.SecretDataReference
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		kind, name, ok := strings.Cut(entry, "/")
+		if !ok || name == "" || (kind != "ConfigMap" && kind != "Secret") {
+			return nil, fmt.Errorf("invalid %s annotation entry %q, expected ConfigMap/name or Secret/name",
+				SecretDataRefsAnnotation, entry)
+		}
+		refs = append(refs, openawarenessv1beta1.SecretDataReference{Kind: kind, Name: name})
+	}
+	return refs, nil
+}
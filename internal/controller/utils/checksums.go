@@ -0,0 +1,138 @@
+//nolint:revive // utils is a standard package name for utilities
+package utils
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/prometheus/prometheus/model/rulefmt"
+	"gopkg.in/yaml.v3"
+	k8sClient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	openawarenessv1beta1 "github.com/syndlex/openawareness-controller/api/openawareness/v1beta1"
+)
+
+// GroupChecksums holds the checksums of the rule groups pushed for a resource by tenant and
+// group name, see GroupChecksumsAnnotation.
+type GroupChecksums map[string]map[string]string
+
+// GroupChecksumsOf returns the checksums recorded in the GroupChecksumsAnnotation of obj.
+// Returns nil if the annotation is missing or invalid, so every group counts as changed.
+func GroupChecksumsOf(obj k8sClient.Object) GroupChecksums {
+	value, ok := obj.GetAnnotations()[GroupChecksumsAnnotation]
+	if !ok {
+		return nil
+	}
+	var checksums GroupChecksums
+	if err := json.Unmarshal([]byte(value), &checksums); err != nil {
+		return nil
+	}
+	return checksums
+}
+
+// RuleGroupChecksum returns the checksum of group as pushed to the tenant through
+// clientConfig, evaluated against sourceTenants if any. It covers the generation of the
+// ClientConfig, so all groups count as changed once the ClientConfig, e.g. its address, changes.
+func RuleGroupChecksum(
+	clientConfig *openawarenessv1beta1.ClientConfig,
+	tenantID string,
+	group rulefmt.RuleGroup,
+	sourceTenants ...string,
+) (string, error) {
+	content, err := yaml.Marshal(group)
+	if err != nil {
+		return "", fmt.Errorf("marshaling rule group %s: %w", group.Name, err)
+	}
+	hash := sha256.New()
+	if clientConfig != nil {
+		_, _ = fmt.Fprintf(hash, "%s/%s/%d\n", clientConfig.Namespace, clientConfig.Name, clientConfig.Generation)
+	}
+	_, _ = fmt.Fprintf(hash, "%s\n", tenantID)
+	// Groups without source tenants keep the checksums recorded before they were supported
+	if len(sourceTenants) > 0 {
+		_, _ = fmt.Fprintf(hash, "source_tenants=%s\n", strings.Join(sourceTenants, ","))
+	}
+	_, _ = hash.Write(content)
+	return hex.EncodeToString(hash.Sum(nil))[:16], nil
+}
+
+// Unchanged reports whether the group of the tenant was pushed with checksum.
+func (c GroupChecksums) Unchanged(tenantID, group, checksum string) bool {
+	recorded, ok := c[tenantID][group]
+	return ok && recorded == checksum
+}
+
+// Removed returns the names of the groups recorded for the tenant that are not in groups,
+// e.g. because they were renamed, sorted by name.
+func (c GroupChecksums) Removed(tenantID string, groups []rulefmt.RuleGroup) []string {
+	var removed []string
+	for _, name := range slices.Sorted(maps.Keys(c[tenantID])) {
+		if !slices.ContainsFunc(groups, func(group rulefmt.RuleGroup) bool { return group.Name == name }) {
+			removed = append(removed, name)
+		}
+	}
+	return removed
+}
+
+// SetGroupChecksums writes checksums to the GroupChecksumsAnnotation of obj, which is patched
+// only if they changed. Empty checksums remove the annotation.
+func SetGroupChecksums(ctx context.Context, client k8sClient.Client, obj k8sClient.Object, checksums GroupChecksums) error {
+	value := ""
+	if len(checksums) > 0 {
+		content, err := json.Marshal(checksums)
+		if err != nil {
+			return fmt.Errorf("marshaling group checksums: %w", err)
+		}
+		value = string(content)
+	}
+	if obj.GetAnnotations()[GroupChecksumsAnnotation] == value {
+		return nil
+	}
+
+	base, ok := obj.DeepCopyObject().(k8sClient.Object)
+	if !ok {
+		return nil
+	}
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	if value == "" {
+		delete(annotations, GroupChecksumsAnnotation)
+	} else {
+		annotations[GroupChecksumsAnnotation] = value
+	}
+	obj.SetAnnotations(annotations)
+	return client.Patch(ctx, obj, k8sClient.MergeFrom(base))
+}
+
+// SetSyncedRulesNamespace records namespace in the SyncedRulesNamespaceAnnotation of obj, which is
+// patched only if it changed. The annotation is removed if namespace is the Kubernetes namespace
+// of obj.
+func SetSyncedRulesNamespace(ctx context.Context, client k8sClient.Client, obj k8sClient.Object, namespace string) error {
+	if SyncedRulesNamespace(obj) == namespace {
+		return nil
+	}
+
+	base, ok := obj.DeepCopyObject().(k8sClient.Object)
+	if !ok {
+		return nil
+	}
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	if namespace == obj.GetNamespace() {
+		delete(annotations, SyncedRulesNamespaceAnnotation)
+	} else {
+		annotations[SyncedRulesNamespaceAnnotation] = namespace
+	}
+	obj.SetAnnotations(annotations)
+	return client.Patch(ctx, obj, k8sClient.MergeFrom(base))
+}
@@ -0,0 +1,132 @@
+//nolint:revive // utils is a standard package name for utilities
+package utils
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"github.com/prometheus/prometheus/model/rulefmt"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	openawarenessv1beta1 "github.com/syndlex/openawareness-controller/api/openawareness/v1beta1"
+)
+
+func TestRuleGroupChecksum(t *testing.T) {
+	clientConfig := &openawarenessv1beta1.ClientConfig{ObjectMeta: metav1.ObjectMeta{Name: "mimir", Namespace: "team", Generation: 1}}
+	group := rulefmt.RuleGroup{Name: "group", Rules: []rulefmt.Rule{{Record: "up:sum", Expr: "sum(up)"}}}
+
+	checksum, err := RuleGroupChecksum(clientConfig, "tenant", group)
+	if err != nil {
+		t.Fatalf("RuleGroupChecksum: %v", err)
+	}
+	if again, _ := RuleGroupChecksum(clientConfig, "tenant", group); again != checksum {
+		t.Errorf("expected a stable checksum, got %s and %s", checksum, again)
+	}
+
+	changed := group
+	changed.Rules = []rulefmt.Rule{{Record: "up:sum", Expr: "sum(up) by (job)"}}
+	otherClient := clientConfig.DeepCopy()
+	otherClient.Generation = 2
+	for name, other := range map[string]func() (string, error){
+		"content":    func() (string, error) { return RuleGroupChecksum(clientConfig, "tenant", changed) },
+		"tenant":     func() (string, error) { return RuleGroupChecksum(clientConfig, "other", group) },
+		"generation": func() (string, error) { return RuleGroupChecksum(otherClient, "tenant", group) },
+		"sources":    func() (string, error) { return RuleGroupChecksum(clientConfig, "tenant", group, "team-a") },
+	} {
+		if got, _ := other(); got == checksum {
+			t.Errorf("expected a changed %s to change the checksum", name)
+		}
+	}
+}
+
+func TestGroupChecksums(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := monitoringv1.AddToScheme(scheme); err != nil {
+		t.Fatalf("adding scheme: %v", err)
+	}
+	rule := &monitoringv1.PrometheusRule{ObjectMeta: metav1.ObjectMeta{Name: "rule", Namespace: "team"}}
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(rule).Build()
+	ctx := context.Background()
+
+	if GroupChecksumsOf(rule) != nil {
+		t.Fatalf("expected no checksums without annotation")
+	}
+	checksums := GroupChecksums{"tenant": {"a": "1", "b": "2"}}
+	if err := SetGroupChecksums(ctx, k8sClient, rule, checksums); err != nil {
+		t.Fatalf("SetGroupChecksums: %v", err)
+	}
+
+	stored := &monitoringv1.PrometheusRule{}
+	if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(rule), stored); err != nil {
+		t.Fatalf("getting rule: %v", err)
+	}
+	recorded := GroupChecksumsOf(stored)
+	if !recorded.Unchanged("tenant", "a", "1") || recorded.Unchanged("tenant", "a", "2") || recorded.Unchanged("other", "a", "1") {
+		t.Errorf("unexpected recorded checksums %v", recorded)
+	}
+	// Group b was renamed to c
+	if removed := recorded.Removed("tenant", []rulefmt.RuleGroup{{Name: "a"}, {Name: "c"}}); !slices.Equal(removed, []string{"b"}) {
+		t.Errorf("Removed() = %v, want [b]", removed)
+	}
+
+	if err := SetGroupChecksums(ctx, k8sClient, stored, nil); err != nil {
+		t.Fatalf("SetGroupChecksums: %v", err)
+	}
+	if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(rule), stored); err != nil {
+		t.Fatalf("getting rule: %v", err)
+	}
+	if _, ok := stored.Annotations[GroupChecksumsAnnotation]; ok {
+		t.Errorf("expected empty checksums to remove the annotation")
+	}
+
+	stored.Annotations = map[string]string{GroupChecksumsAnnotation: "not json"}
+	if GroupChecksumsOf(stored) != nil {
+		t.Errorf("expected invalid checksums to be ignored")
+	}
+}
+
+func TestRulesNamespace(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := monitoringv1.AddToScheme(scheme); err != nil {
+		t.Fatalf("adding scheme: %v", err)
+	}
+	rule := &monitoringv1.PrometheusRule{ObjectMeta: metav1.ObjectMeta{Name: "rule", Namespace: "team",
+		Annotations: map[string]string{RulesNamespaceAnnotation: " alerts "}}}
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(rule).Build()
+	ctx := context.Background()
+
+	if got := RulesNamespace(rule); got != "alerts" {
+		t.Errorf("RulesNamespace() = %q, want alerts", got)
+	}
+	if got := SyncedRulesNamespace(rule); got != "team" {
+		t.Errorf("SyncedRulesNamespace() = %q, want the Kubernetes namespace", got)
+	}
+
+	if err := SetSyncedRulesNamespace(ctx, k8sClient, rule, "alerts"); err != nil {
+		t.Fatalf("SetSyncedRulesNamespace: %v", err)
+	}
+	stored := &monitoringv1.PrometheusRule{}
+	if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(rule), stored); err != nil {
+		t.Fatalf("getting rule: %v", err)
+	}
+	if got := SyncedRulesNamespace(stored); got != "alerts" {
+		t.Errorf("SyncedRulesNamespace() = %q, want alerts", got)
+	}
+
+	// Moving back to the Kubernetes namespace removes the annotation
+	delete(stored.Annotations, RulesNamespaceAnnotation)
+	if got := RulesNamespace(stored); got != "team" {
+		t.Errorf("RulesNamespace() = %q, want the Kubernetes namespace", got)
+	}
+	if err := SetSyncedRulesNamespace(ctx, k8sClient, stored, "team"); err != nil {
+		t.Fatalf("SetSyncedRulesNamespace: %v", err)
+	}
+	if _, ok := stored.Annotations[SyncedRulesNamespaceAnnotation]; ok {
+		t.Error("expected the synced rules namespace annotation to be removed")
+	}
+}
@@ -0,0 +1,294 @@
+//nolint:revive // utils is a standard package name for utilities
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	k8sClient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	openawarenessv1beta1 "github.com/syndlex/openawareness-controller/api/openawareness/v1beta1"
+	"github.com/syndlex/openawareness-controller/internal/clients"
+)
+
+var (
+	// ErrDefaultClientConflict is returned when several default ClientConfigs apply to a resource
+	ErrDefaultClientConflict = errors.New("multiple default ClientConfigs")
+	errNoClientConfig        = errors.New("no ClientConfig referenced and no default ClientConfig exists")
+	errAmbiguousClientConfig = errors.New("ClientConfig name exists in several namespaces")
+	// ErrTenantNotAllowed is returned when a resource is synced to a tenant its ClientConfig does not allow
+	ErrTenantNotAllowed = errors.New("tenant not allowed")
+)
+
+// ResolveClient returns the API client for the ClientConfig referenced by the object's
+// ClientNameAnnotation, or the default ClientConfig if the annotation is not set.
+// A referenced ClientConfig is resolved as described in GetClientConfig and the
+// client is taken from the cache or created on demand, so callers do not depend on
+// the ClientConfig controller having reconciled first.
+// Returns the resolved ClientConfig alongside the client.
+func ResolveClient(
+	ctx context.Context,
+	reader k8sClient.Reader,
+	cache clients.RulerClientCacheInterface,
+	obj k8sClient.Object,
+) (clients.AwarenessClient, *openawarenessv1beta1.ClientConfig, error) {
+	clientConfig, err := GetClientConfig(ctx, reader, obj)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	awarenessClient, err := ClientForConfig(ctx, cache, clientConfig)
+	if err != nil {
+		return nil, clientConfig, err
+	}
+	return awarenessClient, clientConfig, nil
+}
+
+// GetClientConfig reads the ClientConfig referenced by the object's ClientNameAnnotation, see
+// ClientConfigRef. A name without namespace is read from the object's namespace, or from the
+// only namespace having a ClientConfig of that name if the object's namespace has none.
+// Objects without the annotation use the ClientConfig of the TenantMapping selecting their
+// namespace, else the default ClientConfig of their namespace, or the cluster-wide default.
+// The tenant of the TenantMapping becomes the default tenant of the returned ClientConfig.
+// Returns an error if no ClientConfig applies, several defaults or TenantMappings apply, the
+// name exists in several other namespaces, or the ClientConfig cannot be read.
+func GetClientConfig(
+	ctx context.Context,
+	reader k8sClient.Reader,
+	obj k8sClient.Object,
+) (*openawarenessv1beta1.ClientConfig, error) {
+	mapping, err := TenantMappingFor(ctx, reader, obj.GetNamespace())
+	if err != nil {
+		return nil, err
+	}
+
+	key, qualified := ClientConfigRef(obj)
+	if key.Name == "" && mapping != nil && mapping.Spec.ClientConfig != nil {
+		key = k8sClient.ObjectKey{Name: mapping.Spec.ClientConfig.Name, Namespace: mapping.Spec.ClientConfig.Namespace}
+		qualified = true
+	}
+	if key.Name == "" {
+		clientConfigs := &openawarenessv1beta1.ClientConfigList{}
+		if err := reader.List(ctx, clientConfigs); err != nil {
+			return nil, fmt.Errorf("listing ClientConfigs: %w", err)
+		}
+		clientConfig, err := DefaultClientConfig(clientConfigs.Items, obj.GetNamespace())
+		if err != nil {
+			return nil, err
+		}
+		if clientConfig == nil {
+			return nil, fmt.Errorf("%w: annotation '%s' is missing or empty for %s/%s",
+				errNoClientConfig, ClientNameAnnotation, obj.GetNamespace(), obj.GetName())
+		}
+		return applyTenantMapping(clientConfig, mapping), nil
+	}
+
+	clientConfig := &openawarenessv1beta1.ClientConfig{}
+	err = reader.Get(ctx, key, clientConfig)
+	if err != nil && !qualified && apierrors.IsNotFound(err) {
+		// ClientConfigs were referenced by name across namespaces before the namespace of the
+		// object was considered, keep resolving those references
+		clientConfigs := &openawarenessv1beta1.ClientConfigList{}
+		if listErr := reader.List(ctx, clientConfigs); listErr != nil {
+			return nil, fmt.Errorf("listing ClientConfigs: %w", listErr)
+		}
+		found, findErr := clientConfigNamed(clientConfigs.Items, key.Name)
+		if findErr != nil {
+			return nil, findErr
+		}
+		if found != nil {
+			return applyTenantMapping(found, mapping), nil
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("getting ClientConfig %s/%s: %w", key.Namespace, key.Name, err)
+	}
+	return applyTenantMapping(clientConfig, mapping), nil
+}
+
+// ClientConfigRef returns the key of the ClientConfig referenced by the object's
+// ClientNameAnnotation, either "namespace/name" or a name in the object's namespace.
+// qualified reports that the annotation names the namespace. Returns an empty key if the
+// annotation is not set.
+func ClientConfigRef(obj k8sClient.Object) (key k8sClient.ObjectKey, qualified bool) {
+	ref := obj.GetAnnotations()[ClientNameAnnotation]
+	if namespace, name, ok := strings.Cut(ref, "/"); ok {
+		return k8sClient.ObjectKey{Name: name, Namespace: namespace}, true
+	}
+	return k8sClient.ObjectKey{Name: ref, Namespace: obj.GetNamespace()}, false
+}
+
+// clientConfigNamed returns the only ClientConfig among clientConfigs named name, nil if none
+// exists, and errAmbiguousClientConfig if several namespaces have one.
+func clientConfigNamed(
+	clientConfigs []openawarenessv1beta1.ClientConfig,
+	name string,
+) (*openawarenessv1beta1.ClientConfig, error) {
+	var found []*openawarenessv1beta1.ClientConfig
+	for i := range clientConfigs {
+		if clientConfigs[i].Name == name {
+			found = append(found, &clientConfigs[i])
+		}
+	}
+	switch len(found) {
+	case 0:
+		return nil, nil
+	case 1:
+		return found[0], nil
+	default:
+		namespaces := make([]string, 0, len(found))
+		for _, clientConfig := range found {
+			namespaces = append(namespaces, clientConfig.Namespace)
+		}
+		return nil, fmt.Errorf("%w: %s in %s, reference it as namespace/name",
+			errAmbiguousClientConfig, name, strings.Join(namespaces, ", "))
+	}
+}
+
+// DefaultClientConfig returns the default ClientConfig among clientConfigs applying to resources
+// in namespace. A default of the namespace takes precedence over a cluster-wide default.
+// Returns nil if no default applies, and ErrDefaultClientConflict if several defaults of the
+// same scope apply.
+func DefaultClientConfig(
+	clientConfigs []openawarenessv1beta1.ClientConfig,
+	namespace string,
+) (*openawarenessv1beta1.ClientConfig, error) {
+	for _, scope := range []openawarenessv1beta1.DefaultScope{
+		openawarenessv1beta1.DefaultScopeNamespace,
+		openawarenessv1beta1.DefaultScopeCluster,
+	} {
+		var defaults []*openawarenessv1beta1.ClientConfig
+		for i := range clientConfigs {
+			if clientConfigs[i].DeletionTimestamp.IsZero() && clientConfigs[i].IsDefaultFor(namespace, scope) {
+				defaults = append(defaults, &clientConfigs[i])
+			}
+		}
+		switch len(defaults) {
+		case 0:
+			continue
+		case 1:
+			return defaults[0], nil
+		default:
+			names := make([]string, 0, len(defaults))
+			for _, clientConfig := range defaults {
+				names = append(names, OwnerReference(clientConfig))
+			}
+			return nil, fmt.Errorf("%w for namespace %s: %s", ErrDefaultClientConflict, namespace, strings.Join(names, ", "))
+		}
+	}
+	return nil, nil
+}
+
+// ConflictingDefaults returns the other ClientConfigs that are a default for the same scope
+// as clientConfig, as namespace/name. Returns nil if clientConfig is not a default.
+func ConflictingDefaults(
+	clientConfig *openawarenessv1beta1.ClientConfig,
+	clientConfigs []openawarenessv1beta1.ClientConfig,
+) []string {
+	if !clientConfig.Spec.Default {
+		return nil
+	}
+	scope := clientConfig.EffectiveDefaultScope()
+
+	var conflicts []string
+	for i := range clientConfigs {
+		other := &clientConfigs[i]
+		if other.Namespace == clientConfig.Namespace && other.Name == clientConfig.Name {
+			continue
+		}
+		if other.DeletionTimestamp.IsZero() && other.IsDefaultFor(clientConfig.Namespace, scope) {
+			conflicts = append(conflicts, OwnerReference(other))
+		}
+	}
+	return conflicts
+}
+
+// ClientNameFor returns the name of the ClientConfig the object is synced with, see
+// ClientConfigFor, or the ClientNameAnnotation if that ClientConfig does not exist.
+// Returns an empty string if no ClientConfig applies.
+func ClientNameFor(
+	obj k8sClient.Object,
+	clientConfigs []openawarenessv1beta1.ClientConfig,
+	mappings *TenantMappings,
+) string {
+	if clientConfig := ClientConfigFor(obj, clientConfigs, mappings); clientConfig != nil {
+		return clientConfig.Name
+	}
+	return obj.GetAnnotations()[ClientNameAnnotation]
+}
+
+// ClientConfigFor returns the ClientConfig among clientConfigs the object is synced with: the
+// one referenced by the ClientNameAnnotation, resolved as in GetClientConfig, the one of the
+// TenantMapping selecting the object's namespace, or the applicable default ClientConfig.
+// The tenant of the TenantMapping becomes the default tenant of the returned ClientConfig.
+// mappings may be nil. Returns nil if no ClientConfig exists, the referenced name exists in
+// several other namespaces, or several defaults or TenantMappings apply.
+func ClientConfigFor(
+	obj k8sClient.Object,
+	clientConfigs []openawarenessv1beta1.ClientConfig,
+	mappings *TenantMappings,
+) *openawarenessv1beta1.ClientConfig {
+	mapping, err := mappings.For(obj.GetNamespace())
+	if err != nil {
+		return nil
+	}
+
+	key, qualified := ClientConfigRef(obj)
+	if key.Name == "" && mapping != nil && mapping.Spec.ClientConfig != nil {
+		key = k8sClient.ObjectKey{Name: mapping.Spec.ClientConfig.Name, Namespace: mapping.Spec.ClientConfig.Namespace}
+		qualified = true
+	}
+	if key.Name != "" {
+		for i := range clientConfigs {
+			if clientConfigs[i].Name == key.Name && clientConfigs[i].Namespace == key.Namespace {
+				return applyTenantMapping(&clientConfigs[i], mapping)
+			}
+		}
+		if qualified {
+			return nil
+		}
+		clientConfig, err := clientConfigNamed(clientConfigs, key.Name)
+		if err != nil || clientConfig == nil {
+			return nil
+		}
+		return applyTenantMapping(clientConfig, mapping)
+	}
+	clientConfig, err := DefaultClientConfig(clientConfigs, obj.GetNamespace())
+	if err != nil {
+		return nil
+	}
+	return applyTenantMapping(clientConfig, mapping)
+}
+
+// ClientForConfig gets the cached client for the ClientConfig or creates it on demand.
+func ClientForConfig(
+	ctx context.Context,
+	cache clients.RulerClientCacheInterface,
+	clientConfig *openawarenessv1beta1.ClientConfig,
+) (clients.AwarenessClient, error) {
+	if cache == nil {
+		return nil, fmt.Errorf("ruler clients cache is nil for ClientConfig %s/%s",
+			clientConfig.Namespace, clientConfig.Name)
+	}
+
+	if clientConfig.Spec.Type == openawarenessv1beta1.Prometheus {
+		return nil, cache.AddPromClient(ctx, clientConfig.Spec.Address, clientConfig.Name)
+	}
+
+	return cache.GetOrCreateMimirClient(ctx, clientConfig)
+}
+
+// CheckTenantsAllowed returns an error wrapping ErrTenantNotAllowed naming the first of
+// tenantIDs that clientConfig does not allow, see ClientConfig.TenantAllowed.
+func CheckTenantsAllowed(clientConfig *openawarenessv1beta1.ClientConfig, tenantIDs ...string) error {
+	for _, tenantID := range tenantIDs {
+		if !clientConfig.TenantAllowed(tenantID) {
+			return fmt.Errorf("%w: tenant %s is not allowed by ClientConfig %s/%s",
+				ErrTenantNotAllowed, tenantID, clientConfig.Namespace, clientConfig.Name)
+		}
+	}
+	return nil
+}
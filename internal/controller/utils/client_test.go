@@ -0,0 +1,472 @@
+//nolint:revive // utils is a standard package name for utilities
+package utils
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"slices"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	openawarenessv1beta1 "github.com/syndlex/openawareness-controller/api/openawareness/v1beta1"
+	"github.com/syndlex/openawareness-controller/internal/clients"
+)
+
+func TestResolveClient(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := openawarenessv1beta1.AddToScheme(scheme); err != nil {
+		t.Fatalf("adding scheme: %v", err)
+	}
+
+	clientConfig := &openawarenessv1beta1.ClientConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "mimir", Namespace: "team"},
+		Spec: openawarenessv1beta1.ClientConfigSpec{
+			Address: "http://mimir:9009",
+			Type:    openawarenessv1beta1.Mimir,
+		},
+	}
+	reader := fake.NewClientBuilder().WithScheme(scheme).WithObjects(clientConfig).Build()
+
+	tests := []struct {
+		name        string
+		obj         *openawarenessv1beta1.MimirAlertTenant
+		expectError bool
+	}{
+		{
+			name: "resolves ClientConfig in the same namespace",
+			obj: &openawarenessv1beta1.MimirAlertTenant{ObjectMeta: metav1.ObjectMeta{
+				Name: "tenant", Namespace: "team",
+				Annotations: map[string]string{ClientNameAnnotation: "mimir"},
+			}},
+		},
+		{
+			name: "missing annotation",
+			obj: &openawarenessv1beta1.MimirAlertTenant{ObjectMeta: metav1.ObjectMeta{
+				Name: "tenant", Namespace: "team",
+			}},
+			expectError: true,
+		},
+		{
+			name: "falls back to the ClientConfig in another namespace",
+			obj: &openawarenessv1beta1.MimirAlertTenant{ObjectMeta: metav1.ObjectMeta{
+				Name: "tenant", Namespace: "other",
+				Annotations: map[string]string{ClientNameAnnotation: "mimir"},
+			}},
+		},
+		{
+			name: "resolves ClientConfig by namespace and name",
+			obj: &openawarenessv1beta1.MimirAlertTenant{ObjectMeta: metav1.ObjectMeta{
+				Name: "tenant", Namespace: "other",
+				Annotations: map[string]string{ClientNameAnnotation: "team/mimir"},
+			}},
+		},
+		{
+			name: "ClientConfig missing in the referenced namespace",
+			obj: &openawarenessv1beta1.MimirAlertTenant{ObjectMeta: metav1.ObjectMeta{
+				Name: "tenant", Namespace: "team",
+				Annotations: map[string]string{ClientNameAnnotation: "other/mimir"},
+			}},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			awarenessClient, resolved, err := ResolveClient(context.Background(), reader, clients.NewMockRulerClientCache(), tt.obj)
+			if tt.expectError {
+				if err == nil {
+					t.Error("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if awarenessClient == nil || resolved == nil || resolved.Spec.Address != clientConfig.Spec.Address {
+				t.Errorf("unexpected result client=%v clientConfig=%v", awarenessClient, resolved)
+			}
+		})
+	}
+}
+
+func TestGetClientConfigDefaults(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := openawarenessv1beta1.AddToScheme(scheme); err != nil {
+		t.Fatalf("adding scheme: %v", err)
+	}
+
+	clientConfig := func(namespace, name string, scope openawarenessv1beta1.DefaultScope) client.Object {
+		return &openawarenessv1beta1.ClientConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Spec:       openawarenessv1beta1.ClientConfigSpec{Default: scope != "", DefaultScope: scope},
+		}
+	}
+
+	tests := []struct {
+		name          string
+		clientConfigs []client.Object
+		namespace     string
+		annotation    string
+		expected      string
+		// expectedNamespace is checked if set
+		expectedNamespace string
+		expectError       error
+		expectNotFound    bool
+	}{
+		{
+			name:          "annotation takes precedence over defaults",
+			clientConfigs: []client.Object{clientConfig("team", "named", ""), clientConfig("team", "default", "Namespace")},
+			namespace:     "team",
+			annotation:    "named",
+			expected:      "named",
+		},
+		{
+			name:              "annotation resolves in another namespace",
+			clientConfigs:     []client.Object{clientConfig("monitoring", "mimir", "")},
+			namespace:         "team",
+			annotation:        "mimir",
+			expected:          "mimir",
+			expectedNamespace: "monitoring",
+		},
+		{
+			name:              "annotation prefers the object's namespace",
+			clientConfigs:     []client.Object{clientConfig("monitoring", "mimir", ""), clientConfig("team", "mimir", "")},
+			namespace:         "team",
+			annotation:        "mimir",
+			expected:          "mimir",
+			expectedNamespace: "team",
+		},
+		{
+			name:              "annotation with namespace",
+			clientConfigs:     []client.Object{clientConfig("monitoring", "mimir", ""), clientConfig("team", "mimir", "")},
+			namespace:         "team",
+			annotation:        "monitoring/mimir",
+			expected:          "mimir",
+			expectedNamespace: "monitoring",
+		},
+		{
+			name:           "annotation with namespace does not fall back",
+			clientConfigs:  []client.Object{clientConfig("team", "mimir", "")},
+			namespace:      "team",
+			annotation:     "monitoring/mimir",
+			expectNotFound: true,
+		},
+		{
+			name:          "annotation in several other namespaces",
+			clientConfigs: []client.Object{clientConfig("monitoring", "mimir", ""), clientConfig("other", "mimir", "")},
+			namespace:     "team",
+			annotation:    "mimir",
+			expectError:   errAmbiguousClientConfig,
+		},
+		{
+			name:          "namespace default",
+			clientConfigs: []client.Object{clientConfig("team", "default", "Namespace"), clientConfig("other", "other", "Namespace")},
+			namespace:     "team",
+			expected:      "default",
+		},
+		{
+			name:          "namespace default takes precedence over cluster default",
+			clientConfigs: []client.Object{clientConfig("team", "default", "Namespace"), clientConfig("system", "global", "Cluster")},
+			namespace:     "team",
+			expected:      "default",
+		},
+		{
+			name:          "cluster default",
+			clientConfigs: []client.Object{clientConfig("other", "default", "Namespace"), clientConfig("system", "global", "Cluster")},
+			namespace:     "team",
+			expected:      "global",
+		},
+		{
+			name:          "conflicting defaults",
+			clientConfigs: []client.Object{clientConfig("team", "a", "Namespace"), clientConfig("team", "b", "Namespace")},
+			namespace:     "team",
+			expectError:   ErrDefaultClientConflict,
+		},
+		{
+			name:          "no default",
+			clientConfigs: []client.Object{clientConfig("team", "named", "")},
+			namespace:     "team",
+			expectError:   errNoClientConfig,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader := fake.NewClientBuilder().WithScheme(scheme).WithObjects(tt.clientConfigs...).Build()
+			obj := &openawarenessv1beta1.MimirAlertTenant{ObjectMeta: metav1.ObjectMeta{Name: "tenant", Namespace: tt.namespace}}
+			if tt.annotation != "" {
+				obj.Annotations = map[string]string{ClientNameAnnotation: tt.annotation}
+			}
+
+			resolved, err := GetClientConfig(context.Background(), reader, obj)
+			if tt.expectNotFound {
+				if !apierrors.IsNotFound(err) {
+					t.Errorf("expected not found error, got %v", err)
+				}
+				return
+			}
+			if tt.expectError != nil {
+				if !errors.Is(err, tt.expectError) {
+					t.Errorf("expected error %v, got %v", tt.expectError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if resolved.Name != tt.expected {
+				t.Errorf("GetClientConfig() = %s, want %s", resolved.Name, tt.expected)
+			}
+			if tt.expectedNamespace != "" && resolved.Namespace != tt.expectedNamespace {
+				t.Errorf("GetClientConfig() namespace = %s, want %s", resolved.Namespace, tt.expectedNamespace)
+			}
+
+			clientConfigs := &openawarenessv1beta1.ClientConfigList{}
+			if err := reader.List(context.Background(), clientConfigs); err != nil {
+				t.Fatalf("listing ClientConfigs: %v", err)
+			}
+			if found := ClientConfigFor(obj, clientConfigs.Items, nil); found == nil ||
+				found.Namespace != resolved.Namespace || found.Name != resolved.Name {
+				t.Errorf("ClientConfigFor() = %v, want %s/%s", found, resolved.Namespace, resolved.Name)
+			}
+		})
+	}
+}
+
+func TestConflictingDefaults(t *testing.T) {
+	clientConfigs := []openawarenessv1beta1.ClientConfig{
+		{ObjectMeta: metav1.ObjectMeta{Name: "a", Namespace: "team"}, Spec: openawarenessv1beta1.ClientConfigSpec{Default: true}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "b", Namespace: "team"}, Spec: openawarenessv1beta1.ClientConfigSpec{Default: true}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "c", Namespace: "other"}, Spec: openawarenessv1beta1.ClientConfigSpec{Default: true}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "d", Namespace: "team"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "e", Namespace: "system"}, Spec: openawarenessv1beta1.ClientConfigSpec{
+			Default: true, DefaultScope: openawarenessv1beta1.DefaultScopeCluster,
+		}},
+	}
+
+	if got := ConflictingDefaults(&clientConfigs[0], clientConfigs); len(got) != 1 || got[0] != "team/b" {
+		t.Errorf("ConflictingDefaults(team/a) = %v, want [team/b]", got)
+	}
+	if got := ConflictingDefaults(&clientConfigs[2], clientConfigs); got != nil {
+		t.Errorf("ConflictingDefaults(other/c) = %v, want none", got)
+	}
+	if got := ConflictingDefaults(&clientConfigs[3], clientConfigs); got != nil {
+		t.Errorf("ConflictingDefaults(team/d) = %v, want none for a non-default", got)
+	}
+	if got := ConflictingDefaults(&clientConfigs[4], clientConfigs); got != nil {
+		t.Errorf("ConflictingDefaults(system/e) = %v, want none", got)
+	}
+}
+
+func TestTenantIDsWithAliases(t *testing.T) {
+	clientConfig := &openawarenessv1beta1.ClientConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "mimir", Namespace: "team"},
+		Spec: openawarenessv1beta1.ClientConfigSpec{
+			DefaultTenant: "platform",
+			TenantAliases: map[string]string{"platform": "org-1", "payments": "org-2"},
+		},
+	}
+
+	tests := []struct {
+		name         string
+		annotations  map[string]string
+		clientConfig *openawarenessv1beta1.ClientConfig
+		expected     []string
+	}{
+		{
+			name:         "default tenant resolved through its alias",
+			clientConfig: clientConfig,
+			expected:     []string{"org-1"},
+		},
+		{
+			name:         "annotated alias",
+			annotations:  map[string]string{MimirTenantAnnotation: "payments"},
+			clientConfig: clientConfig,
+			expected:     []string{"org-2"},
+		},
+		{
+			name:         "annotated org ID without alias",
+			annotations:  map[string]string{MimirTenantAnnotation: "org-3"},
+			clientConfig: clientConfig,
+			expected:     []string{"org-3"},
+		},
+		{
+			name:         "recording tenant alias",
+			annotations:  map[string]string{RecordingTenantAnnotation: "payments"},
+			clientConfig: clientConfig,
+			expected:     []string{"org-2", "org-1"},
+		},
+		{
+			name: "group tenants after the rule tenants",
+			annotations: map[string]string{
+				GroupTenantsAnnotation: `{"billing-alerts":"team-billing","payments-alerts":"payments","other":"platform"}`,
+			},
+			clientConfig: clientConfig,
+			expected:     []string{"org-1", "org-2", "team-billing"},
+		},
+		{
+			name:        "without ClientConfig",
+			annotations: map[string]string{MimirTenantAnnotation: "payments"},
+			expected:    []string{"payments"},
+		},
+		{
+			name:         "without default tenant",
+			clientConfig: &openawarenessv1beta1.ClientConfig{},
+			expected:     []string{DefaultTenantID},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			obj := &openawarenessv1beta1.MimirAlertTenant{ObjectMeta: metav1.ObjectMeta{
+				Name: "tenant", Namespace: "team", Annotations: tt.annotations,
+			}}
+			got := TenantIDs(obj, tt.clientConfig)
+			if len(got) != len(tt.expected) {
+				t.Fatalf("TenantIDs() = %v, expected %v", got, tt.expected)
+			}
+			for i := range got {
+				if got[i] != tt.expected[i] {
+					t.Errorf("TenantIDs() = %v, expected %v", got, tt.expected)
+				}
+			}
+		})
+	}
+}
+
+func TestGroupTenants(t *testing.T) {
+	clientConfig := &openawarenessv1beta1.ClientConfig{Spec: openawarenessv1beta1.ClientConfigSpec{
+		TenantAliases: map[string]string{"billing": "org-2"},
+	}}
+
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		expected    map[string]string
+		wantErr     bool
+	}{
+		{
+			name: "without annotation",
+		},
+		{
+			name:        "tenants resolved through aliases",
+			annotations: map[string]string{GroupTenantsAnnotation: `{"billing-alerts":"billing","slo":" team-slo "}`},
+			expected:    map[string]string{"billing-alerts": "org-2", "slo": "team-slo"},
+		},
+		{
+			name:        "not a JSON object",
+			annotations: map[string]string{GroupTenantsAnnotation: "billing-alerts=billing"},
+			wantErr:     true,
+		},
+		{
+			name:        "empty tenant",
+			annotations: map[string]string{GroupTenantsAnnotation: `{"billing-alerts":""}`},
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			obj := &openawarenessv1beta1.MimirAlertTenant{ObjectMeta: metav1.ObjectMeta{
+				Name: "tenant", Namespace: "team", Annotations: tt.annotations,
+			}}
+			got, err := GroupTenants(obj, clientConfig)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GroupTenants() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !maps.Equal(got, tt.expected) {
+				t.Errorf("GroupTenants() = %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSourceTenants(t *testing.T) {
+	clientConfig := &openawarenessv1beta1.ClientConfig{Spec: openawarenessv1beta1.ClientConfigSpec{
+		TenantAliases: map[string]string{"billing": "org-2"},
+	}}
+
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		expected    map[string][]string
+		wantErr     bool
+	}{
+		{
+			name: "without annotation",
+		},
+		{
+			name:        "tenants resolved through aliases, sorted and deduplicated",
+			annotations: map[string]string{SourceTenantsAnnotation: `{"global":["team-b","billing","team-b"," org-2 "]}`},
+			expected:    map[string][]string{"global": {"org-2", "team-b"}},
+		},
+		{
+			name:        "not a JSON object",
+			annotations: map[string]string{SourceTenantsAnnotation: `["team-a"]`},
+			wantErr:     true,
+		},
+		{
+			name:        "no source tenants",
+			annotations: map[string]string{SourceTenantsAnnotation: `{"global":[]}`},
+			wantErr:     true,
+		},
+		{
+			name:        "empty source tenant",
+			annotations: map[string]string{SourceTenantsAnnotation: `{"global":["team-a",""]}`},
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			obj := &openawarenessv1beta1.MimirAlertTenant{ObjectMeta: metav1.ObjectMeta{
+				Name: "tenant", Namespace: "team", Annotations: tt.annotations,
+			}}
+			got, err := SourceTenants(obj, clientConfig)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SourceTenants() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !maps.EqualFunc(got, tt.expected, slices.Equal[[]string]) {
+				t.Errorf("SourceTenants() = %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCheckTenantsAllowed(t *testing.T) {
+	clientConfig := &openawarenessv1beta1.ClientConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "mimir", Namespace: "team"},
+		Spec: openawarenessv1beta1.ClientConfigSpec{
+			AllowedTenants: []string{"org-1", "org-2"},
+			DeniedTenants:  []string{"org-2"},
+		},
+	}
+
+	tests := []struct {
+		name         string
+		clientConfig *openawarenessv1beta1.ClientConfig
+		tenantIDs    []string
+		expectError  bool
+	}{
+		{name: "allowed tenant", clientConfig: clientConfig, tenantIDs: []string{"org-1"}},
+		{name: "tenant outside the allowed set", clientConfig: clientConfig, tenantIDs: []string{"org-1", "org-3"}, expectError: true},
+		{name: "denied tenant takes precedence", clientConfig: clientConfig, tenantIDs: []string{"org-2"}, expectError: true},
+		{name: "no restrictions", clientConfig: &openawarenessv1beta1.ClientConfig{}, tenantIDs: []string{"org-3"}},
+		{name: "without ClientConfig", tenantIDs: []string{"org-3"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckTenantsAllowed(tt.clientConfig, tt.tenantIDs...)
+			if tt.expectError != errors.Is(err, ErrTenantNotAllowed) {
+				t.Errorf("CheckTenantsAllowed() = %v, expectError %v", err, tt.expectError)
+			}
+		})
+	}
+}
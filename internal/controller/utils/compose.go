@@ -0,0 +1,239 @@
+//nolint:revive // utils is a standard package name for utilities
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+	k8sClient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	openawarenessv1beta1 "github.com/syndlex/openawareness-controller/api/openawareness/v1beta1"
+)
+
+// ErrExtendsCycle is returned when MimirAlertTenants extend each other in a cycle
+var ErrExtendsCycle = errors.New("extends cycle")
+
+// ErrComposition is returned when the configurations of extended tenants cannot be merged
+var ErrComposition = errors.New("cannot compose alertmanagerConfig")
+
+// namedListKeys are the Alertmanager configuration lists whose entries are merged by name
+var namedListKeys = []string{"receivers", "time_intervals", "mute_time_intervals"}
+
+// ResolveExtends follows spec.extends of tenant through the tenants of its namespace.
+// Returns the chain of tenants ordered from the root base to tenant itself, an error
+// wrapping ErrExtendsCycle for cyclic extensions, or the error reading a base tenant.
+func ResolveExtends(
+	ctx context.Context,
+	reader k8sClient.Reader,
+	tenant *openawarenessv1beta1.MimirAlertTenant,
+) ([]*openawarenessv1beta1.MimirAlertTenant, error) {
+	chain := []*openawarenessv1beta1.MimirAlertTenant{tenant}
+	visited := map[string]bool{tenant.Name: true}
+	for current := tenant; current.Spec.Extends != nil; {
+		name := current.Spec.Extends.Name
+		if visited[name] {
+			return nil, fmt.Errorf("%w: %s extends %s, which is already part of the chain of %s",
+				ErrExtendsCycle, current.Name, name, tenant.Name)
+		}
+		visited[name] = true
+
+		base := &openawarenessv1beta1.MimirAlertTenant{}
+		if err := reader.Get(ctx, k8sClient.ObjectKey{Namespace: tenant.Namespace, Name: name}, base); err != nil {
+			return nil, fmt.Errorf("failed to get base MimirAlertTenant %s/%s: %w", tenant.Namespace, name, err)
+		}
+		chain = append(chain, base)
+		current = base
+	}
+	slices.Reverse(chain)
+	return chain, nil
+}
+
+// BaseNames returns the names of the base tenants of a chain returned by ResolveExtends.
+func BaseNames(chain []*openawarenessv1beta1.MimirAlertTenant) []string {
+	names := make([]string, 0, len(chain)-1)
+	for _, tenant := range chain[:len(chain)-1] {
+		names = append(names, tenant.Name)
+	}
+	return names
+}
+
+// ComposedReferences returns the SecretDataReferences of a chain, the references of a
+// base before those of the tenants extending it, so later references override the base.
+func ComposedReferences(chain []*openawarenessv1beta1.MimirAlertTenant) []openawarenessv1beta1.SecretDataReference {
+	var references []openawarenessv1beta1.SecretDataReference
+	for _, tenant := range chain {
+		references = append(references, tenant.Spec.SecretDataReferences...)
+	}
+	return references
+}
+
+// ComposedTemplateFiles returns the template files of a chain, files of a tenant replacing
+// the base files of the same name.
+func ComposedTemplateFiles(chain []*openawarenessv1beta1.MimirAlertTenant) map[string]string {
+	files := map[string]string{}
+	for _, tenant := range chain {
+		maps.Copy(files, tenant.Spec.TemplateFiles)
+	}
+	return files
+}
+
+// RenderComposedConfig renders the alertmanagerConfig of every tenant of a chain with the
+// same template data and builtins, and merges the rendered configurations over each other
+// with MergeAlertmanagerConfigs. A chain of a single tenant is rendered unchanged.
+// Returns the template error of a tenant, or an error wrapping ErrComposition if the
+// rendered configurations cannot be merged.
+func RenderComposedConfig(
+	chain []*openawarenessv1beta1.MimirAlertTenant,
+	data map[string]string,
+	builtins TemplateBuiltins,
+) (string, error) {
+	var composed string
+	for _, tenant := range chain {
+		rendered, err := RenderTemplateWithBuiltins(tenant.ToConfigDTO(), data, builtins)
+		if err != nil {
+			return "", err
+		}
+		if strings.TrimSpace(rendered) == "" {
+			continue
+		}
+		if composed == "" {
+			composed = rendered
+			continue
+		}
+		if composed, err = MergeAlertmanagerConfigs(composed, rendered); err != nil {
+			return "", fmt.Errorf("%w of %s: %w", ErrComposition, tenant.Name, err)
+		}
+	}
+	return composed, nil
+}
+
+// MergeAlertmanagerConfigs merges the Alertmanager configuration overlay over base:
+//   - receivers, time_intervals and mute_time_intervals replace base entries of the same
+//     name, other entries are added
+//   - the route settings override the base route, the child routes are placed before the
+//     base child routes
+//   - inhibit_rules and templates are added to those of the base
+//   - global settings override the base settings one by one
+//   - all other settings replace the base settings
+//
+// Returns an error if a configuration is not a YAML mapping.
+func MergeAlertmanagerConfigs(base, overlay string) (string, error) {
+	baseConfig, err := unmarshalMapping(base)
+	if err != nil {
+		return "", fmt.Errorf("invalid base configuration: %w", err)
+	}
+	overlayConfig, err := unmarshalMapping(overlay)
+	if err != nil {
+		return "", fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	for key, value := range overlayConfig {
+		switch {
+		case slices.Contains(namedListKeys, key):
+			baseConfig[key] = mergeNamedLists(baseConfig[key], value)
+		case key == "route":
+			baseConfig[key] = mergeRoutes(baseConfig[key], value)
+		case key == "inhibit_rules":
+			baseConfig[key] = append(asList(baseConfig[key]), asList(value)...)
+		case key == "templates":
+			baseConfig[key] = mergeUnique(asList(baseConfig[key]), asList(value))
+		case key == "global":
+			baseConfig[key] = mergeMappings(baseConfig[key], value)
+		default:
+			baseConfig[key] = value
+		}
+	}
+
+	merged, err := yaml.Marshal(baseConfig)
+	if err != nil {
+		return "", err
+	}
+	return string(merged), nil
+}
+
+// unmarshalMapping parses a YAML mapping, an empty document is an empty mapping.
+func unmarshalMapping(config string) (map[string]any, error) {
+	mapping := map[string]any{}
+	if err := yaml.Unmarshal([]byte(config), &mapping); err != nil {
+		return nil, err
+	}
+	return mapping, nil
+}
+
+// asList returns value as list, nil if it is not a list.
+func asList(value any) []any {
+	list, _ := value.([]any)
+	return list
+}
+
+// mergeMappings returns base with the keys of overlay set, overlay if base is no mapping.
+func mergeMappings(base, overlay any) any {
+	baseMapping, ok := base.(map[string]any)
+	overlayMapping, overlayOK := overlay.(map[string]any)
+	if !ok || !overlayOK {
+		return overlay
+	}
+	maps.Copy(baseMapping, overlayMapping)
+	return baseMapping
+}
+
+// mergeRoutes merges the overlay route over the base route, placing the overlay child
+// routes before the base child routes.
+func mergeRoutes(base, overlay any) any {
+	baseRoute, ok := base.(map[string]any)
+	overlayRoute, overlayOK := overlay.(map[string]any)
+	if !ok || !overlayOK {
+		return overlay
+	}
+	for key, value := range overlayRoute {
+		if key == "routes" {
+			baseRoute[key] = append(asList(value), asList(baseRoute[key])...)
+			continue
+		}
+		baseRoute[key] = value
+	}
+	return baseRoute
+}
+
+// mergeNamedLists replaces the base entries with the name of an overlay entry and adds
+// the other overlay entries.
+func mergeNamedLists(base, overlay any) []any {
+	merged := asList(base)
+	for _, entry := range asList(overlay) {
+		index := slices.IndexFunc(merged, func(existing any) bool {
+			return entryName(existing) != "" && entryName(existing) == entryName(entry)
+		})
+		if index < 0 {
+			merged = append(merged, entry)
+			continue
+		}
+		merged[index] = entry
+	}
+	return merged
+}
+
+// entryName returns the name of a list entry, empty if it has none.
+func entryName(entry any) string {
+	mapping, _ := entry.(map[string]any)
+	name, _ := mapping["name"].(string)
+	return name
+}
+
+// mergeUnique adds the overlay values not contained in base. Values other than strings
+// are always added.
+func mergeUnique(base, overlay []any) []any {
+	for _, value := range overlay {
+		if text, ok := value.(string); ok && slices.ContainsFunc(base, func(existing any) bool {
+			return existing == text
+		}) {
+			continue
+		}
+		base = append(base, value)
+	}
+	return base
+}
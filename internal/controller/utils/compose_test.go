@@ -0,0 +1,184 @@
+//nolint:revive // utils is a standard package name for utilities
+package utils
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	openawarenessv1beta1 "github.com/syndlex/openawareness-controller/api/openawareness/v1beta1"
+)
+
+func extendingTenant(name, extends, config string) *openawarenessv1beta1.MimirAlertTenant {
+	tenant := &openawarenessv1beta1.MimirAlertTenant{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "team"},
+		Spec:       openawarenessv1beta1.MimirAlertTenantSpec{AlertmanagerConfig: config},
+	}
+	if extends != "" {
+		tenant.Spec.Extends = &openawarenessv1beta1.TenantReference{Name: extends}
+	}
+	return tenant
+}
+
+func TestResolveExtends(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := openawarenessv1beta1.AddToScheme(scheme); err != nil {
+		t.Fatalf("adding scheme: %v", err)
+	}
+	reader := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		extendingTenant("root", "", "route: {}"),
+		extendingTenant("team", "root", ""),
+		extendingTenant("a", "b", ""),
+		extendingTenant("b", "a", ""),
+	).Build()
+	ctx := context.Background()
+
+	chain, err := ResolveExtends(ctx, reader, extendingTenant("oncall", "team", ""))
+	if err != nil {
+		t.Fatalf("ResolveExtends() error = %v", err)
+	}
+	var names []string
+	for _, tenant := range chain {
+		names = append(names, tenant.Name)
+	}
+	if !reflect.DeepEqual(names, []string{"root", "team", "oncall"}) {
+		t.Errorf("expected chain [root team oncall], got %v", names)
+	}
+	if bases := BaseNames(chain); !reflect.DeepEqual(bases, []string{"root", "team"}) {
+		t.Errorf("expected bases [root team], got %v", bases)
+	}
+
+	if _, err := ResolveExtends(ctx, reader, extendingTenant("a", "b", "")); !errors.Is(err, ErrExtendsCycle) {
+		t.Errorf("expected a cycle error, got %v", err)
+	}
+	if _, err := ResolveExtends(ctx, reader, extendingTenant("self", "self", "")); !errors.Is(err, ErrExtendsCycle) {
+		t.Errorf("expected a cycle error for a self reference, got %v", err)
+	}
+	if _, err := ResolveExtends(ctx, reader, extendingTenant("orphan", "missing", "")); !apierrors.IsNotFound(err) {
+		t.Errorf("expected a not found error, got %v", err)
+	}
+}
+
+func TestMergeAlertmanagerConfigs(t *testing.T) {
+	base := `
+global:
+  resolve_timeout: 5m
+  smtp_from: alerts@example.org
+route:
+  receiver: default
+  group_by: [alertname]
+  routes:
+    - receiver: email
+      matchers: ['team="a"']
+receivers:
+  - name: default
+  - name: email
+    email_configs:
+      - to: a@example.org
+inhibit_rules:
+  - source_matchers: ['severity="critical"']
+    target_matchers: ['severity="warning"']
+templates: [base.tmpl]
+`
+	overlay := `
+global:
+  resolve_timeout: 10m
+route:
+  group_by: [alertname, service]
+  routes:
+    - receiver: pager
+      matchers: ['severity="critical"']
+receivers:
+  - name: email
+    email_configs:
+      - to: oncall@example.org
+  - name: pager
+inhibit_rules:
+  - source_matchers: ['alertname="Maintenance"']
+templates: [base.tmpl, pager.tmpl]
+`
+	want := `
+global:
+  resolve_timeout: 10m
+  smtp_from: alerts@example.org
+route:
+  receiver: default
+  group_by: [alertname, service]
+  routes:
+    - receiver: pager
+      matchers: ['severity="critical"']
+    - receiver: email
+      matchers: ['team="a"']
+receivers:
+  - name: default
+  - name: email
+    email_configs:
+      - to: oncall@example.org
+  - name: pager
+inhibit_rules:
+  - source_matchers: ['severity="critical"']
+    target_matchers: ['severity="warning"']
+  - source_matchers: ['alertname="Maintenance"']
+templates: [base.tmpl, pager.tmpl]
+`
+
+	merged, err := MergeAlertmanagerConfigs(base, overlay)
+	if err != nil {
+		t.Fatalf("MergeAlertmanagerConfigs() error = %v", err)
+	}
+	var got, expected map[string]any
+	if err := yaml.Unmarshal([]byte(merged), &got); err != nil {
+		t.Fatalf("merged configuration is invalid: %v", err)
+	}
+	if err := yaml.Unmarshal([]byte(want), &expected); err != nil {
+		t.Fatalf("expected configuration is invalid: %v", err)
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("unexpected merged configuration:\n%s", merged)
+	}
+
+	if _, err := MergeAlertmanagerConfigs(base, "- not a mapping"); err == nil {
+		t.Error("expected an error for an overlay that is not a mapping")
+	}
+}
+
+func TestRenderComposedConfig(t *testing.T) {
+	root := extendingTenant("root", "", "route:\n  receiver: [[ .RECEIVER ]]\n")
+	root.Spec.TemplateFiles = map[string]string{"base.tmpl": "base", "shared.tmpl": "root"}
+	overlay := extendingTenant("oncall", "root", "receivers:\n  - name: [[ .RECEIVER ]]\n")
+	overlay.Spec.TemplateFiles = map[string]string{"shared.tmpl": "oncall"}
+	data := map[string]string{"RECEIVER": "slack"}
+
+	// A single tenant is rendered unchanged
+	single, err := RenderComposedConfig([]*openawarenessv1beta1.MimirAlertTenant{root}, data, TemplateBuiltins{})
+	if err != nil || single != "route:\n  receiver: slack\n" {
+		t.Errorf("RenderComposedConfig() = %q, %v", single, err)
+	}
+
+	chain := []*openawarenessv1beta1.MimirAlertTenant{root, overlay}
+	composed, err := RenderComposedConfig(chain, data, TemplateBuiltins{})
+	if err != nil {
+		t.Fatalf("RenderComposedConfig() error = %v", err)
+	}
+	if !strings.Contains(composed, "receiver: slack") || !strings.Contains(composed, "- name: slack") {
+		t.Errorf("expected the rendered configurations to be composed, got:\n%s", composed)
+	}
+	files := ComposedTemplateFiles(chain)
+	if !reflect.DeepEqual(files, map[string]string{"base.tmpl": "base", "shared.tmpl": "oncall"}) {
+		t.Errorf("unexpected template files %v", files)
+	}
+
+	broken := extendingTenant("broken", "root", "- a list")
+	_, err = RenderComposedConfig([]*openawarenessv1beta1.MimirAlertTenant{root, broken}, data, TemplateBuiltins{})
+	if !errors.Is(err, ErrComposition) {
+		t.Errorf("expected a composition error, got %v", err)
+	}
+}
@@ -0,0 +1,69 @@
+//nolint:revive // utils is a standard package name for utilities
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	k8sClient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	openawarenessv1beta1 "github.com/syndlex/openawareness-controller/api/openawareness/v1beta1"
+)
+
+// ErrConfigSecret is returned when the alertmanagerConfigFrom Secret key of a tenant cannot be read
+var ErrConfigSecret = errors.New("cannot read alertmanagerConfigFrom")
+
+// ResolveConfigSources returns a chain returned by ResolveExtends with the alertmanagerConfig
+// of every tenant setting alertmanagerConfigFrom read from the referenced Secret key. These
+// tenants are copied, the others are returned unchanged. A missing optional Secret or key
+// leaves the configuration empty.
+// Returns an error wrapping ErrConfigSecret if a required Secret or key is missing, or the
+// error reading a Secret.
+func ResolveConfigSources(
+	ctx context.Context,
+	reader k8sClient.Reader,
+	chain []*openawarenessv1beta1.MimirAlertTenant,
+) ([]*openawarenessv1beta1.MimirAlertTenant, error) {
+	resolved := make([]*openawarenessv1beta1.MimirAlertTenant, 0, len(chain))
+	for _, tenant := range chain {
+		if tenant.Spec.AlertmanagerConfigFrom == nil {
+			resolved = append(resolved, tenant)
+			continue
+		}
+		config, err := readConfigSecret(ctx, reader, tenant.Namespace, tenant.Spec.AlertmanagerConfigFrom.SecretKeyRef)
+		if err != nil {
+			return nil, fmt.Errorf("%w of %s: %w", ErrConfigSecret, tenant.Name, err)
+		}
+		tenant = tenant.DeepCopy()
+		tenant.Spec.AlertmanagerConfig = config
+		resolved = append(resolved, tenant)
+	}
+	return resolved, nil
+}
+
+// readConfigSecret returns the value of the selected Secret key, empty if an optional
+// Secret or key is missing.
+func readConfigSecret(
+	ctx context.Context,
+	reader k8sClient.Reader,
+	namespace string,
+	selector corev1.SecretKeySelector,
+) (string, error) {
+	optional := selector.Optional != nil && *selector.Optional
+	secret := &corev1.Secret{}
+	err := reader.Get(ctx, k8sClient.ObjectKey{Namespace: namespace, Name: selector.Name}, secret)
+	if apierrors.IsNotFound(err) && optional {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get Secret %s: %w", selector.Name, err)
+	}
+	value, ok := secret.Data[selector.Key]
+	if !ok && !optional {
+		return "", fmt.Errorf("key %s not found in Secret %s", selector.Key, selector.Name)
+	}
+	return string(value), nil
+}
@@ -0,0 +1,74 @@
+//nolint:revive // utils is a standard package name for utilities
+package utils
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	openawarenessv1beta1 "github.com/syndlex/openawareness-controller/api/openawareness/v1beta1"
+)
+
+func TestResolveConfigSources(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatalf("adding scheme: %v", err)
+	}
+	reader := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "alertmanager", Namespace: "team"},
+		Data:       map[string][]byte{"config.yaml": []byte("route: {}")},
+	}).Build()
+	fromSecret := func(name, key string, optional bool) *openawarenessv1beta1.MimirAlertTenant {
+		tenant := extendingTenant("oncall", "root", "")
+		tenant.Spec.AlertmanagerConfigFrom = &openawarenessv1beta1.AlertmanagerConfigSource{
+			SecretKeyRef: corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: name},
+				Key:                  key,
+				Optional:             ptr.To(optional),
+			},
+		}
+		return tenant
+	}
+	ctx := context.Background()
+
+	root := extendingTenant("root", "", "receivers: []")
+	tenant := fromSecret("alertmanager", "config.yaml", false)
+	chain, err := ResolveConfigSources(ctx, reader, []*openawarenessv1beta1.MimirAlertTenant{root, tenant})
+	if err != nil {
+		t.Fatalf("ResolveConfigSources() error = %v", err)
+	}
+	if chain[0] != root {
+		t.Errorf("expected tenants without alertmanagerConfigFrom to be returned unchanged")
+	}
+	if chain[1].Spec.AlertmanagerConfig != "route: {}" {
+		t.Errorf("expected the configuration to be read from the Secret, got %q", chain[1].Spec.AlertmanagerConfig)
+	}
+	if tenant.Spec.AlertmanagerConfig != "" {
+		t.Errorf("expected the tenant not to be changed, got %q", tenant.Spec.AlertmanagerConfig)
+	}
+
+	for _, missing := range []*openawarenessv1beta1.MimirAlertTenant{
+		fromSecret("missing", "config.yaml", false),
+		fromSecret("alertmanager", "missing", false),
+	} {
+		_, err := ResolveConfigSources(ctx, reader, []*openawarenessv1beta1.MimirAlertTenant{missing})
+		if !errors.Is(err, ErrConfigSecret) {
+			t.Errorf("expected a config secret error, got %v", err)
+		}
+	}
+	for _, optional := range []*openawarenessv1beta1.MimirAlertTenant{
+		fromSecret("missing", "config.yaml", true),
+		fromSecret("alertmanager", "missing", true),
+	} {
+		chain, err := ResolveConfigSources(ctx, reader, []*openawarenessv1beta1.MimirAlertTenant{optional})
+		if err != nil || chain[0].Spec.AlertmanagerConfig != "" {
+			t.Errorf("expected a missing optional Secret key to leave the configuration empty, got %v", err)
+		}
+	}
+}
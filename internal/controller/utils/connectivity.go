@@ -0,0 +1,125 @@
+//nolint:revive // utils is a standard package name for utilities
+package utils
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	openawarenessv1beta1 "github.com/syndlex/openawareness-controller/api/openawareness/v1beta1"
+	"github.com/syndlex/openawareness-controller/internal/mimir"
+)
+
+// connectivityLayers are the connectivity condition types of a ClientConfig in the order the
+// connection to the endpoint passes them.
+var connectivityLayers = []string{
+	openawarenessv1beta1.ConditionTypeURLValid,
+	openawarenessv1beta1.ConditionTypeDNSResolvable,
+	openawarenessv1beta1.ConditionTypeTLSHandshake,
+	openawarenessv1beta1.ConditionTypeAuthenticated,
+	openawarenessv1beta1.ConditionTypeAPIReachable,
+}
+
+// failedLayer returns the index in connectivityLayers of the layer the health check of address
+// failed at with err, and the number of layers known to be passed before it. Errors are
+// classified by type: refused connections and timeouts fail before the TLS handshake and
+// authentication are reached, a token exchange before the endpoint is contacted.
+// Returns -1 for errors not attributed to a layer, e.g. an open circuit breaker.
+func failedLayer(err error, address string) (int, int) {
+	if endpoint, parseErr := url.Parse(address); parseErr != nil || endpoint.Host == "" ||
+		(endpoint.Scheme != "http" && endpoint.Scheme != "https") {
+		return 0, 0
+	}
+
+	var dnsErr *net.DNSError
+	var apiErr *mimir.APIError
+	var opErr *net.OpError
+	var netErr net.Error
+	switch {
+	case errors.Is(err, mimir.ErrCircuitOpen):
+		return -1, 0
+	case errors.As(err, &dnsErr):
+		return 1, 1
+	case isTLSError(err):
+		return 2, 2
+	case errors.Is(err, mimir.ErrTokenExchange):
+		return 3, 1
+	case errors.As(err, &apiErr):
+		if apiErr.StatusCode == http.StatusUnauthorized || apiErr.StatusCode == http.StatusForbidden {
+			return 3, 3
+		}
+		return 4, 4
+	case errors.As(err, &opErr) && opErr.Op == "proxyconnect":
+		// The endpoint was not reached, its host is resolved by the proxy
+		return 4, 1
+	case errors.As(err, &opErr), errors.Is(err, context.DeadlineExceeded),
+		errors.As(err, &netErr) && netErr.Timeout():
+		return 4, 2
+	default:
+		return -1, 0
+	}
+}
+
+// isTLSError reports whether err failed the TLS handshake, e.g. on an untrusted certificate.
+func isTLSError(err error) bool {
+	var verificationErr *tls.CertificateVerificationError
+	var recordErr tls.RecordHeaderError
+	var alertErr tls.AlertError
+	var authorityErr x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+	var invalidErr x509.CertificateInvalidError
+	return errors.As(err, &verificationErr) || errors.As(err, &recordErr) || errors.As(err, &alertErr) ||
+		errors.As(err, &authorityErr) || errors.As(err, &hostnameErr) || errors.As(err, &invalidErr)
+}
+
+// SetConnectivityConditions sets the URLValid, DNSResolvable, TLSHandshake, Authenticated and
+// APIReachable conditions from the result of a health check of a ClientConfig connecting to
+// address, so it must only be called with the outcome of a check that ran. Without error all
+// are true. Otherwise the layer err failed at, see failedLayer, is false with the reason and
+// message of CategorizeError, the layers passed before it are true and the others unknown.
+// Errors not attributed to a layer, e.g. a missing CA ConfigMap or an open circuit breaker,
+// set all layers unknown. TLSHandshake is true for http addresses that passed DNS.
+func SetConnectivityConditions(conditions *[]metav1.Condition, err error, address string, generation int64) {
+	failed, passed := len(connectivityLayers), len(connectivityLayers)
+	var reason, message string
+	if err != nil {
+		reason, message = CategorizeError(err)
+		failed, passed = failedLayer(err, address)
+		if failed == 0 {
+			reason, message = openawarenessv1beta1.ReasonInvalidURL, "Invalid URL format"
+		}
+	}
+
+	for i, layer := range connectivityLayers {
+		condition := metav1.Condition{
+			Type:               layer,
+			Status:             metav1.ConditionUnknown,
+			ObservedGeneration: generation,
+			Reason:             openawarenessv1beta1.ReasonNotChecked,
+			Message:            "Not reached by the health check",
+		}
+		switch {
+		case i == failed:
+			condition.Status, condition.Reason, condition.Message = metav1.ConditionFalse, reason, message
+		case i < passed:
+			condition.Status, condition.Reason = metav1.ConditionTrue, openawarenessv1beta1.ReasonCheckPassed
+			condition.Message = fmt.Sprintf("Passed by the health check of %s", address)
+		case failed < 0:
+			condition.Message = "The health check failure could not be attributed to a layer"
+		}
+		if layer == openawarenessv1beta1.ConditionTypeTLSHandshake && i <= passed && i != failed &&
+			strings.HasPrefix(address, "http://") {
+			condition.Status, condition.Reason = metav1.ConditionTrue, openawarenessv1beta1.ReasonPlainHTTP
+			condition.Message = "The address uses http, no TLS handshake takes place"
+		}
+		SetCondition(conditions, condition)
+	}
+}
@@ -0,0 +1,88 @@
+//nolint:revive // utils is a standard package name for utilities
+package utils
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	openawarenessv1beta1 "github.com/syndlex/openawareness-controller/api/openawareness/v1beta1"
+	"github.com/syndlex/openawareness-controller/internal/mimir"
+)
+
+func TestSetConnectivityConditions(t *testing.T) {
+	const (
+		tr = metav1.ConditionTrue
+		f  = metav1.ConditionFalse
+		u  = metav1.ConditionUnknown
+	)
+	refused := &url.Error{Op: "Get", URL: "https://mimir:8080", Err: &net.OpError{
+		Op: "dial", Net: "tcp", Err: errors.New("connect: connection refused"),
+	}}
+	tests := []struct {
+		name    string
+		err     error
+		address string
+		// expected status of URLValid, DNSResolvable, TLSHandshake, Authenticated and APIReachable
+		expected []metav1.ConditionStatus
+	}{
+		{"connected", nil, "https://mimir:8080", []metav1.ConditionStatus{tr, tr, tr, tr, tr}},
+		{"invalid URL", errors.New("Get \"mimir:8080\": unsupported protocol scheme"), "mimir:8080",
+			[]metav1.ConditionStatus{f, u, u, u, u}},
+		{"unknown host", &url.Error{Op: "Get", URL: "https://mimir:8080", Err: &net.OpError{
+			Op: "dial", Net: "tcp", Err: &net.DNSError{Err: "no such host", Name: "mimir", IsNotFound: true},
+		}}, "https://mimir:8080", []metav1.ConditionStatus{tr, f, u, u, u}},
+		{"bad certificate", fmt.Errorf("health check failed: %w", &tls.CertificateVerificationError{
+			Err: x509.UnknownAuthorityError{},
+		}), "https://mimir:8080", []metav1.ConditionStatus{tr, tr, f, u, u}},
+		{"token exchange", fmt.Errorf("%w: HTTP 401", mimir.ErrTokenExchange), "https://mimir:8080",
+			[]metav1.ConditionStatus{tr, u, u, f, u}},
+		{"unauthorized", &mimir.APIError{StatusCode: 401}, "https://mimir:8080",
+			[]metav1.ConditionStatus{tr, tr, tr, f, u}},
+		{"server error", &mimir.APIError{StatusCode: 503}, "https://mimir:8080",
+			[]metav1.ConditionStatus{tr, tr, tr, tr, f}},
+		{"connection refused", refused, "https://mimir:8080", []metav1.ConditionStatus{tr, tr, u, u, f}},
+		{"connection refused over http", refused, "http://mimir:8080", []metav1.ConditionStatus{tr, tr, tr, u, f}},
+		{"timeout", fmt.Errorf("health check failed: %w", context.DeadlineExceeded), "https://mimir:8080",
+			[]metav1.ConditionStatus{tr, tr, u, u, f}},
+		{"circuit open", fmt.Errorf("%w, GET request refused", mimir.ErrCircuitOpen), "https://mimir:8080",
+			[]metav1.ConditionStatus{u, u, u, u, u}},
+		// Errors are classified by type, not by their message
+		{"untyped", errors.New("dial tcp 10.0.0.1:8080: connect: connection refused"), "https://mimir:8080",
+			[]metav1.ConditionStatus{u, u, u, u, u}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var conditions []metav1.Condition
+			SetConnectivityConditions(&conditions, tc.err, tc.address, 3)
+			for i, layer := range connectivityLayers {
+				condition := meta.FindStatusCondition(conditions, layer)
+				if condition == nil {
+					t.Fatalf("expected condition %s", layer)
+				}
+				if condition.Status != tc.expected[i] {
+					t.Errorf("expected %s to be %s, got %s (%s)", layer, tc.expected[i], condition.Status, condition.Reason)
+				}
+				if condition.ObservedGeneration != 3 {
+					t.Errorf("expected %s to observe generation 3, got %d", layer, condition.ObservedGeneration)
+				}
+			}
+		})
+	}
+
+	// A failing layer carries the reason of the error
+	var conditions []metav1.Condition
+	SetConnectivityConditions(&conditions, &mimir.APIError{StatusCode: 403}, "https://mimir:8080", 1)
+	if reason := meta.FindStatusCondition(conditions, openawarenessv1beta1.ConditionTypeAuthenticated).Reason; reason !=
+		openawarenessv1beta1.ReasonForbidden {
+		t.Errorf("expected Authenticated to carry reason Forbidden, got %s", reason)
+	}
+}
@@ -10,6 +10,74 @@ const (
 	ClientNameAnnotation string = "openawareness.io/client-name"
 	// MimirTenantAnnotation specifies the Mimir tenant for rules and alerts
 	MimirTenantAnnotation string = "openawareness.io/mimir-tenant"
+	// RecordingTenantAnnotation specifies the Mimir tenant recording rules of a PrometheusRule are pushed to
+	RecordingTenantAnnotation string = "openawareness.io/recording-tenant"
+	// AlertingTenantAnnotation specifies the Mimir tenant alerting rules of a PrometheusRule are pushed to
+	AlertingTenantAnnotation string = "openawareness.io/alerting-tenant"
+	// GroupTenantsAnnotation routes single rule groups of a PrometheusRule to other Mimir tenants, a
+	// JSON object mapping group names to tenants, e.g. {"billing-alerts":"team-billing"}
+	GroupTenantsAnnotation string = "openawareness.io/group-tenant.map"
+	// SourceTenantsAnnotation sets the source tenants Mimir evaluates single rule groups of a
+	// PrometheusRule against, a JSON object mapping group names to lists of tenants, e.g.
+	// {"global-slos":["team-a","team-b"]}
+	SourceTenantsAnnotation string = "openawareness.io/source-tenants.map"
+	// ExtraLabelsAnnotation adds labels in the form "key=value,key=value" to every alerting rule of a PrometheusRule
+	ExtraLabelsAnnotation string = "openawareness.io/extra-labels"
+	// PausedAnnotation stops all remote mutations for a resource while set to "true"
+	PausedAnnotation string = "openawareness.io/paused"
+	// SyncTimeoutAnnotation bounds the Mimir API operations of a PrometheusRule reconciliation (Go duration)
+	SyncTimeoutAnnotation string = "openawareness.io/sync-timeout"
+	// RestoreBackupAnnotation makes the ClientConfig controller push the backed up state of all
+	// tenants of a ClientConfig again while set to "true"; it is removed once restored
+	RestoreBackupAnnotation string = "openawareness.io/restore-backup"
+	// ControllerVersionAnnotation records the controller version that last synced a resource
+	ControllerVersionAnnotation string = "openawareness.io/controller-version"
+	// GroupChecksumsAnnotation records the checksums of the rule groups of a PrometheusRule last
+	// pushed to each tenant, written by the controller after each successful sync
+	GroupChecksumsAnnotation string = "openawareness.io/group-checksums"
+	// PriorityAnnotation orders the sync of a resource against the others waiting for it
+	// ("critical", "high", "normal" or "low")
+	PriorityAnnotation string = "openawareness.io/priority"
+	// EvaluationIntervalAnnotation sets the evaluation interval (Prometheus duration) of the groups
+	// of a PrometheusRule that do not set one
+	EvaluationIntervalAnnotation string = "openawareness.io/evaluation-interval"
+	// QueryOffsetAnnotation sets the query offset (Prometheus duration) of the groups of a
+	// PrometheusRule that do not set one
+	QueryOffsetAnnotation string = "openawareness.io/query-offset"
+	// ConfirmDeleteAnnotation allows the deletion of the remote data of a resource that is
+	// tenant-wide, e.g. the Alertmanager configuration of a MimirAlertTenant, while set to "true"
+	ConfirmDeleteAnnotation string = "openawareness.io/confirm-delete"
+	// TemplateAnnotation makes the controller render the expressions, labels and annotations of the
+	// rules of a PrometheusRule as templates before converting them while set to "true"
+	TemplateAnnotation string = "openawareness.io/template"
+	// SecretDataRefsAnnotation lists the ConfigMaps and Secrets providing the template variables of a
+	// PrometheusRule in the form "ConfigMap/name,Secret/name"
+	SecretDataRefsAnnotation string = "openawareness.io/secret-data-refs"
+	// RulesNamespaceAnnotation sets the Mimir rule namespace the rule groups of a PrometheusRule are
+	// pushed to, the Kubernetes namespace of the PrometheusRule by default
+	RulesNamespaceAnnotation string = "openawareness.io/rules-namespace"
+	// SyncedRulesNamespaceAnnotation records the Mimir rule namespace the rule groups of a
+	// PrometheusRule were last synced to, if it is not the Kubernetes namespace of the PrometheusRule
+	SyncedRulesNamespaceAnnotation string = "openawareness.io/synced-rules-namespace"
+	// DualWriteAnnotation marks a PrometheusRule that is evaluated by prometheus-operator and pushed to
+	// Mimir at the same time while set to "true", the controller compares the series both produce
+	DualWriteAnnotation string = "openawareness.io/dual-write"
+	// RequeueOnStartAnnotation is set to "true" by the controller on resources whose sync was
+	// interrupted by a shutdown, they are reconciled first after the controller restarts
+	RequeueOnStartAnnotation string = "openawareness.io/requeue-on-start"
+	// InjectMatchersAnnotation lists the label matchers added to every vector selector of the rule
+	// expressions of a PrometheusRule in the form "key=value,key=value", e.g. "cluster=eu-1"
+	InjectMatchersAnnotation string = "openawareness.io/inject-matchers"
+	// InjectMatchersDryRunAnnotation makes the controller only report the expressions the
+	// InjectMatchersAnnotation would rewrite as an event instead of pushing them while set to "true"
+	InjectMatchersDryRunAnnotation string = "openawareness.io/inject-matchers-dry-run"
+	// OwnerLabel is the rule label marking rules pushed to Mimir by this operator
+	OwnerLabel string = "openawareness_owner"
+	// InstanceLabel is the rule label naming the operator installation that pushed a rule, set if
+	// the installation has an identity, see InstanceIdentity
+	InstanceLabel string = "openawareness_instance"
+	// ManagedByHeader is the comment prefix written at the top of every pushed Alertmanager configuration
+	ManagedByHeader string = "# managed-by: openawareness-controller"
 	// DefaultTenantID is the default tenant used when no tenant is specified
 	DefaultTenantID string = "anonymous"
 )
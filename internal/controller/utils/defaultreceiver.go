@@ -0,0 +1,109 @@
+//nolint:revive // utils is a standard package name for utilities
+package utils
+
+import (
+	"errors"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultReceiverName is the name of the catch-all receiver injected by DefaultReceiverInject
+const DefaultReceiverName = "openawareness-default"
+
+// ErrNoDefaultReceiver is returned for configurations whose top-level route has no receiver
+// or references a receiver that is not defined, so unmatched alerts would be dropped
+var ErrNoDefaultReceiver = errors.New("no default receiver")
+
+// DefaultReceiverMode controls how configurations without a resolvable default receiver are handled.
+type DefaultReceiverMode string
+
+const (
+	// DefaultReceiverDisabled leaves the top-level route unchecked
+	DefaultReceiverDisabled DefaultReceiverMode = ""
+	// DefaultReceiverFail rejects configurations without a resolvable default receiver
+	DefaultReceiverFail DefaultReceiverMode = "fail"
+	// DefaultReceiverInject adds a catch-all webhook receiver as default receiver
+	DefaultReceiverInject DefaultReceiverMode = "inject"
+)
+
+// ParseDefaultReceiverMode parses a default receiver mode flag value. An empty value disables
+// the check.
+func ParseDefaultReceiverMode(value string) (DefaultReceiverMode, error) {
+	switch mode := DefaultReceiverMode(value); mode {
+	case DefaultReceiverDisabled, DefaultReceiverFail, DefaultReceiverInject:
+		return mode, nil
+	default:
+		return DefaultReceiverDisabled, fmt.Errorf("invalid default receiver mode %q, expected %q or %q",
+			value, DefaultReceiverFail, DefaultReceiverInject)
+	}
+}
+
+// DefaultReceiver makes sure alerts matching no route of a rendered Alertmanager configuration
+// reach a receiver. A nil DefaultReceiver or one with DefaultReceiverDisabled accepts every
+// configuration.
+type DefaultReceiver struct {
+	Mode DefaultReceiverMode
+	// WebhookURL is the URL the receiver injected by DefaultReceiverInject sends alerts to
+	WebhookURL string
+}
+
+// Enabled reports whether configurations are checked for a default receiver.
+func (d *DefaultReceiver) Enabled() bool {
+	return d != nil && d.Mode != DefaultReceiverDisabled
+}
+
+// Ensure checks that the top-level route of config references a defined receiver.
+// If it does not, DefaultReceiverInject adds a webhook receiver named DefaultReceiverName
+// sending to WebhookURL, or reuses a receiver of that name, and makes it the receiver of the
+// top-level route; DefaultReceiverFail returns an error wrapping ErrNoDefaultReceiver.
+// Returns the configuration, unchanged unless the receiver was injected, and whether it was
+// injected. Returns an error if config is not a YAML mapping.
+func (d *DefaultReceiver) Ensure(config string) (string, bool, error) {
+	if !d.Enabled() {
+		return config, false, nil
+	}
+	parsed, err := unmarshalMapping(config)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to parse Alertmanager configuration: %w", err)
+	}
+
+	receivers := asList(parsed["receivers"])
+	names := map[string]bool{}
+	for _, receiver := range receivers {
+		names[entryName(receiver)] = true
+	}
+	root, _ := parsed["route"].(map[string]any)
+	receiver, _ := root["receiver"].(string)
+	if receiver != "" && names[receiver] {
+		return config, false, nil
+	}
+
+	if d.Mode != DefaultReceiverInject {
+		if receiver == "" {
+			return "", false, fmt.Errorf("%w: the top-level route has no receiver", ErrNoDefaultReceiver)
+		}
+		return "", false, fmt.Errorf("%w: receiver %s of the top-level route is not defined",
+			ErrNoDefaultReceiver, receiver)
+	}
+
+	if !names[DefaultReceiverName] {
+		receivers = append(receivers, map[string]any{
+			"name": DefaultReceiverName,
+			"webhook_configs": []any{
+				map[string]any{"url": d.WebhookURL},
+			},
+		})
+	}
+	if root == nil {
+		root = map[string]any{}
+	}
+	root["receiver"] = DefaultReceiverName
+	parsed["route"] = root
+	parsed["receivers"] = receivers
+	rendered, err := yaml.Marshal(parsed)
+	if err != nil {
+		return "", false, err
+	}
+	return string(rendered), true, nil
+}
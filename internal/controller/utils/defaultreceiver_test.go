@@ -0,0 +1,88 @@
+//nolint:revive // utils is a standard package name for utilities
+package utils
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestParseDefaultReceiverMode(t *testing.T) {
+	for _, value := range []string{"", "fail", "inject"} {
+		if _, err := ParseDefaultReceiverMode(value); err != nil {
+			t.Errorf("ParseDefaultReceiverMode(%q) returned error: %v", value, err)
+		}
+	}
+	if _, err := ParseDefaultReceiverMode("warn"); err == nil {
+		t.Error("expected an error for an unknown mode")
+	}
+}
+
+func TestDefaultReceiverEnsure(t *testing.T) {
+	resolvable := "route:\n  receiver: default\nreceivers:\n  - name: default\n"
+	for _, receiver := range []*DefaultReceiver{nil, {}, {Mode: DefaultReceiverFail}, {Mode: DefaultReceiverInject}} {
+		ensured, injected, err := receiver.Ensure(resolvable)
+		if err != nil || injected || ensured != resolvable {
+			t.Errorf("expected a resolvable default receiver to be accepted unchanged, got %q, %v, %v",
+				ensured, injected, err)
+		}
+	}
+	if ensured, _, err := (*DefaultReceiver)(nil).Ensure("- a list"); err != nil || ensured != "- a list" {
+		t.Errorf("expected a disabled check to accept every configuration, got %q, %v", ensured, err)
+	}
+
+	fail := &DefaultReceiver{Mode: DefaultReceiverFail}
+	for _, config := range []string{
+		"receivers:\n  - name: default\n",
+		"route:\n  group_by: [alertname]\nreceivers:\n  - name: default\n",
+		"route:\n  receiver: missing\nreceivers:\n  - name: default\n",
+	} {
+		if _, _, err := fail.Ensure(config); !errors.Is(err, ErrNoDefaultReceiver) {
+			t.Errorf("expected a missing default receiver error for %q, got %v", config, err)
+		}
+	}
+	if _, _, err := fail.Ensure("- a list"); err == nil || errors.Is(err, ErrNoDefaultReceiver) {
+		t.Errorf("expected a parse error, got %v", err)
+	}
+
+	inject := &DefaultReceiver{Mode: DefaultReceiverInject, WebhookURL: "http://catch-all"}
+	ensured, injected, err := inject.Ensure("route:\n  receiver: missing\n  group_by: [alertname]\nreceivers:\n  - name: team\n")
+	if err != nil || !injected {
+		t.Fatalf("expected the default receiver to be injected, got %v, %v", injected, err)
+	}
+	want := `
+route:
+  receiver: openawareness-default
+  group_by: [alertname]
+receivers:
+  - name: team
+  - name: openawareness-default
+    webhook_configs:
+      - url: http://catch-all
+`
+	var got, expected map[string]any
+	if err := yaml.Unmarshal([]byte(ensured), &got); err != nil {
+		t.Fatalf("ensured configuration is invalid: %v", err)
+	}
+	if err := yaml.Unmarshal([]byte(want), &expected); err != nil {
+		t.Fatalf("expected configuration is invalid: %v", err)
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("unexpected ensured configuration:\n%s", ensured)
+	}
+
+	// A receiver named like the injected one is reused
+	ensured, injected, err = inject.Ensure("receivers:\n  - name: openawareness-default\n")
+	if err != nil || !injected {
+		t.Fatalf("expected the default receiver to be set, got %v, %v", injected, err)
+	}
+	want = "route:\n  receiver: openawareness-default\nreceivers:\n  - name: openawareness-default\n"
+	got, expected = nil, nil
+	_ = yaml.Unmarshal([]byte(ensured), &got)
+	_ = yaml.Unmarshal([]byte(want), &expected)
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected the existing receiver to be reused, got:\n%s", ensured)
+	}
+}
@@ -0,0 +1,77 @@
+//nolint:revive // utils is a standard package name for utilities
+package utils
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+const (
+	// RedactedValue replaces the values of secret fields in configuration diffs
+	RedactedValue = "<redacted>"
+	// truncatedMarker ends diffs cut to their maximum length
+	truncatedMarker = "\n... (truncated)"
+)
+
+// secretFieldPattern matches YAML mapping entries, optionally list items, with a scalar value.
+var secretFieldPattern = regexp.MustCompile(`^(\s*(?:-\s+)?)([A-Za-z0-9_]+)(:\s+)(\S.*)$`)
+
+// secretFieldSuffixes are the suffixes of Alertmanager configuration fields holding credentials,
+// e.g. smtp_auth_password, slack_api_url, routing_key or bot_token.
+var secretFieldSuffixes = []string{
+	"password", "secret", "token", "_key", "api_url", "webhook_url", "credentials",
+}
+
+// RedactConfig replaces the values of credential fields of an Alertmanager configuration with
+// RedactedValue, line by line, so the configuration can be shown in events and status.
+func RedactConfig(config string) string {
+	lines := strings.Split(config, "\n")
+	for i, line := range lines {
+		match := secretFieldPattern.FindStringSubmatch(line)
+		if match == nil || !isSecretField(match[2]) {
+			continue
+		}
+		lines[i] = match[1] + match[2] + match[3] + RedactedValue
+	}
+	return strings.Join(lines, "\n")
+}
+
+// isSecretField reports whether an Alertmanager configuration field holds credentials.
+func isSecretField(field string) bool {
+	field = strings.ToLower(field)
+	for _, suffix := range secretFieldSuffixes {
+		if strings.HasSuffix(field, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// ConfigDiff returns the unified diff between the previously pushed and the current Alertmanager
+// configuration, both redacted with RedactConfig and without managed-by header.
+// Returns an empty string if there was no previous configuration or nothing changed. Diffs
+// longer than maxLength are truncated, zero disables the limit.
+func ConfigDiff(previous, current string, maxLength int) string {
+	previous = RedactConfig(StripManagedByHeader(previous))
+	current = RedactConfig(StripManagedByHeader(current))
+	if strings.TrimSpace(previous) == "" || previous == current {
+		return ""
+	}
+
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(previous),
+		B:        difflib.SplitLines(current),
+		FromFile: "previous",
+		ToFile:   "current",
+		Context:  2,
+	})
+	if err != nil {
+		return ""
+	}
+	if maxLength > 0 && len(diff) > maxLength {
+		diff = diff[:max(maxLength-len(truncatedMarker), 0)] + truncatedMarker
+	}
+	return diff
+}
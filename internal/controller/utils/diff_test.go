@@ -0,0 +1,97 @@
+//nolint:revive // utils is a standard package name for utilities
+package utils
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRedactConfig(t *testing.T) {
+	config := `global:
+  smtp_auth_password: hunter2
+  slack_api_url: https://hooks.slack.com/services/T0/B0/secret
+receivers:
+  - name: pager
+    pagerduty_configs:
+      - routing_key: abc123
+  - name: telegram
+    telegram_configs:
+      - bot_token: "123:abc"
+        chat_id: 42`
+
+	redacted := RedactConfig(config)
+	for _, secret := range []string{"hunter2", "hooks.slack.com", "abc123", "123:abc"} {
+		if strings.Contains(redacted, secret) {
+			t.Errorf("expected %q to be redacted, got:\n%s", secret, redacted)
+		}
+	}
+	for _, kept := range []string{"name: pager", "chat_id: 42", "      - routing_key: " + RedactedValue} {
+		if !strings.Contains(redacted, kept) {
+			t.Errorf("expected %q to be kept, got:\n%s", kept, redacted)
+		}
+	}
+}
+
+func TestConfigDiff(t *testing.T) {
+	previous := ManagedByHeader + " MimirAlertTenant team/alerts\n" +
+		"route:\n  receiver: default\nglobal:\n  smtp_auth_password: old\n"
+	current := ManagedByHeader + " MimirAlertTenant team/alerts\n" +
+		"route:\n  receiver: pager\nglobal:\n  smtp_auth_password: new\n"
+
+	tests := []struct {
+		name      string
+		previous  string
+		current   string
+		maxLength int
+		want      []string
+		wantEmpty bool
+	}{
+		{
+			name:     "changed configuration",
+			previous: previous,
+			current:  current,
+			want:     []string{"--- previous", "+++ current", "-  receiver: default", "+  receiver: pager"},
+		},
+		{
+			name:      "initial push has no diff",
+			current:   current,
+			wantEmpty: true,
+		},
+		{
+			name:      "changes of redacted values only have no diff",
+			previous:  strings.Replace(current, "new", "newer", 1),
+			current:   current,
+			wantEmpty: true,
+		},
+		{
+			name:      "long diff is truncated",
+			previous:  previous,
+			current:   current,
+			maxLength: 40,
+			want:      []string{truncatedMarker},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff := ConfigDiff(tt.previous, tt.current, tt.maxLength)
+			if tt.wantEmpty {
+				if diff != "" {
+					t.Errorf("expected no diff, got:\n%s", diff)
+				}
+				return
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(diff, want) {
+					t.Errorf("expected diff to contain %q, got:\n%s", want, diff)
+				}
+			}
+			if strings.Contains(diff, "old") || strings.Contains(diff, ManagedByHeader) {
+				t.Errorf("expected redacted diff without header, got:\n%s", diff)
+			}
+			if tt.maxLength > 0 && len(diff) > tt.maxLength {
+				t.Errorf("expected diff of at most %d bytes, got %d", tt.maxLength, len(diff))
+			}
+		})
+	}
+}
@@ -5,15 +5,21 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
 	"strings"
 
 	openawarenessv1beta1 "github.com/syndlex/openawareness-controller/api/openawareness/v1beta1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/syndlex/openawareness-controller/internal/mimir"
 )
 
 // CategorizeError determines the appropriate reason and message for an error.
 // It analyzes the error message and returns a standardized reason code and human-readable message.
 // This function is used by both ClientConfig and MimirAlertTenant controllers for consistent error handling.
+// Errors of the Mimir API are categorized by their status code, the message carries the
+// response snippet redacted with RedactConfig instead of the response body.
 func CategorizeError(err error) (string, string) {
 	if err == nil {
 		return openawarenessv1beta1.ReasonSynced, "Operation successful"
@@ -24,9 +30,20 @@ func CategorizeError(err error) (string, string) {
 		return openawarenessv1beta1.ReasonTimeoutError, "Operation deadline exceeded"
 	}
 
+	if errors.Is(err, mimir.ErrCircuitOpen) {
+		return openawarenessv1beta1.ReasonCircuitOpen, fmt.Sprintf("Requests paused: %s", err.Error())
+	}
+
 	errMsg := err.Error()
 
 	// Check error categories in priority order
+	if reason, msg := checkTokenExchangeError(errMsg); reason != "" {
+		return reason, msg
+	}
+	var apiErr *mimir.APIError
+	if errors.As(err, &apiErr) {
+		return categorizeAPIError(apiErr)
+	}
 	if reason, msg := checkDNSError(errMsg); reason != "" {
 		return reason, msg
 	}
@@ -50,6 +67,59 @@ func CategorizeError(err error) (string, string) {
 	return openawarenessv1beta1.ReasonNetworkError, fmt.Sprintf("Connection failed: %s", errMsg)
 }
 
+// StatusMessage returns the message of err to store in the status of a resource. For errors of
+// the Mimir API, the message of CategorizeError is returned, which carries the redacted
+// response snippet; other errors are returned as is.
+func StatusMessage(err error) string {
+	var apiErr *mimir.APIError
+	if errors.As(err, &apiErr) {
+		_, msg := categorizeAPIError(apiErr)
+		return msg
+	}
+	return err.Error()
+}
+
+// categorizeAPIError returns the reason of the status code of a Mimir API error, and a message
+// naming the status, path and tenant of the request, followed by the redacted response snippet.
+func categorizeAPIError(apiErr *mimir.APIError) (string, string) {
+	var reason, summary string
+	switch code := apiErr.StatusCode; {
+	case code == http.StatusUnauthorized:
+		reason, summary = openawarenessv1beta1.ReasonUnauthorized, "Authentication failed"
+	case code == http.StatusForbidden:
+		reason, summary = openawarenessv1beta1.ReasonForbidden, "Access forbidden"
+	case code == http.StatusNotFound:
+		reason, summary = openawarenessv1beta1.ReasonNotFound, "Endpoint not found"
+	case code == http.StatusConflict:
+		reason, summary = openawarenessv1beta1.ReasonConflict, "Resource conflict"
+	case code == http.StatusTooManyRequests:
+		reason, summary = openawarenessv1beta1.ReasonTooManyRequests, "Rate limit exceeded"
+	case code >= http.StatusInternalServerError:
+		reason, summary = openawarenessv1beta1.ReasonServerError, "Server error"
+	default:
+		reason, summary = openawarenessv1beta1.ReasonRequestRejected, "Request rejected"
+	}
+
+	msg := fmt.Sprintf("%s: HTTP %d", summary, apiErr.StatusCode)
+	if apiErr.Path != "" {
+		msg += " from " + apiErr.Path
+	}
+	if apiErr.TenantID != "" {
+		msg += " for tenant " + apiErr.TenantID
+	}
+	if apiErr.Snippet != "" {
+		msg += ": " + RedactConfig(apiErr.Snippet)
+	}
+	return reason, msg
+}
+
+func checkTokenExchangeError(errMsg string) (string, string) {
+	if strings.Contains(errMsg, "token exchange failed") {
+		return openawarenessv1beta1.ReasonTokenExchangeFailed, "Service account token exchange failed"
+	}
+	return "", ""
+}
+
 func checkDNSError(errMsg string) (string, string) {
 	if strings.Contains(errMsg, "no such host") || strings.Contains(errMsg, "dns") {
 		return openawarenessv1beta1.ReasonDNSResolutionError, "DNS resolution failed"
@@ -119,28 +189,13 @@ func checkHTTPError(errMsg string) (string, string) {
 	return "", ""
 }
 
-// SetCondition sets or updates a condition in the conditions list.
-// If a condition with the same type already exists, it updates it; otherwise, it appends the new condition.
-// This ensures that each condition type appears only once in the list.
-// Note: conditions must be a non-nil pointer to a slice.
+// SetCondition sets or updates a condition in the conditions list through meta.SetStatusCondition,
+// so each condition type appears only once. The LastTransitionTime is only changed if the
+// status of the condition changes, and set to now if the new condition does not carry one.
+// A nil conditions pointer is ignored.
 func SetCondition(conditions *[]metav1.Condition, newCondition metav1.Condition) {
 	if conditions == nil {
 		return
 	}
-
-	if *conditions == nil {
-		*conditions = []metav1.Condition{}
-	}
-
-	// Find existing condition of the same type
-	for i, condition := range *conditions {
-		if condition.Type == newCondition.Type {
-			// Update existing condition
-			(*conditions)[i] = newCondition
-			return
-		}
-	}
-
-	// Condition doesn't exist, append it
-	*conditions = append(*conditions, newCondition)
+	meta.SetStatusCondition(conditions, newCondition)
 }
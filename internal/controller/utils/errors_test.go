@@ -4,10 +4,14 @@ package utils
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
+	"time"
 
 	openawarenessv1beta1 "github.com/syndlex/openawareness-controller/api/openawareness/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/syndlex/openawareness-controller/internal/mimir"
 )
 
 func TestCategorizeError(t *testing.T) {
@@ -29,6 +33,18 @@ func TestCategorizeError(t *testing.T) {
 			expectedReason: openawarenessv1beta1.ReasonTimeoutError,
 			expectedMsg:    "Operation deadline exceeded",
 		},
+		{
+			name:           "wrapped sync timeout",
+			err:            fmt.Errorf("creating rule group: %w", context.DeadlineExceeded),
+			expectedReason: openawarenessv1beta1.ReasonTimeoutError,
+			expectedMsg:    "Operation deadline exceeded",
+		},
+		{
+			name:           "circuit breaker open",
+			err:            fmt.Errorf("%w, POST request to /rules refused", mimir.ErrCircuitOpen),
+			expectedReason: openawarenessv1beta1.ReasonCircuitOpen,
+			expectedMsg:    "Requests paused: circuit breaker open, POST request to /rules refused",
+		},
 		{
 			name:           "DNS resolution error - no such host",
 			err:            errors.New("dial tcp: lookup example.com: no such host"),
@@ -119,6 +135,30 @@ func TestCategorizeError(t *testing.T) {
 			expectedReason: openawarenessv1beta1.ReasonServerError,
 			expectedMsg:    "Server error",
 		},
+		{
+			name:           "token exchange failure",
+			err:            errors.New("token exchange failed: server returned HTTP status: 401 Unauthorized"),
+			expectedReason: openawarenessv1beta1.ReasonTokenExchangeFailed,
+			expectedMsg:    "Service account token exchange failed",
+		},
+		{
+			name: "Mimir API error",
+			err: fmt.Errorf("%w, POST request to https://mimir/api/v1/alerts failed", &mimir.APIError{
+				StatusCode: 400,
+				TenantID:   "team-a",
+				Path:       "/api/v1/alerts",
+				Snippet:    "error validating config:\nsmtp_auth_password: hunter2",
+			}),
+			expectedReason: openawarenessv1beta1.ReasonRequestRejected,
+			expectedMsg: "Request rejected: HTTP 400 from /api/v1/alerts for tenant team-a: " +
+				"error validating config:\nsmtp_auth_password: <redacted>",
+		},
+		{
+			name:           "Mimir API server error without snippet",
+			err:            &mimir.APIError{StatusCode: 503, Path: "/prometheus/config/v1/rules/ns"},
+			expectedReason: openawarenessv1beta1.ReasonServerError,
+			expectedMsg:    "Server error: HTTP 503 from /prometheus/config/v1/rules/ns",
+		},
 		{
 			name:           "unknown error defaults to network error",
 			err:            errors.New("something went wrong"),
@@ -140,6 +180,18 @@ func TestCategorizeError(t *testing.T) {
 	}
 }
 
+func TestStatusMessage(t *testing.T) {
+	plain := errors.New("rendering failed")
+	if got := StatusMessage(plain); got != "rendering failed" {
+		t.Errorf("StatusMessage() = %q, want the error message", got)
+	}
+
+	apiErr := fmt.Errorf("pushing: %w", &mimir.APIError{StatusCode: 401, Snippet: "bot_token: abc"})
+	if got, want := StatusMessage(apiErr), "Authentication failed: HTTP 401: bot_token: <redacted>"; got != want {
+		t.Errorf("StatusMessage() = %q, want %q", got, want)
+	}
+}
+
 func TestSetCondition(t *testing.T) {
 	now := metav1.Now()
 
@@ -237,6 +289,34 @@ func TestSetCondition(t *testing.T) {
 	}
 }
 
+func TestSetConditionTransitionTime(t *testing.T) {
+	transition := metav1.NewTime(metav1.Now().Add(-time.Hour).Truncate(time.Second))
+	conditions := []metav1.Condition{{
+		Type:               "Ready",
+		Status:             metav1.ConditionTrue,
+		LastTransitionTime: transition,
+		Reason:             "Success",
+	}}
+
+	SetCondition(&conditions, metav1.Condition{
+		Type:               "Ready",
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: 2,
+		Reason:             "StillSuccess",
+	})
+	if !conditions[0].LastTransitionTime.Equal(&transition) {
+		t.Errorf("expected the transition time to be kept for an unchanged status, got %v", conditions[0].LastTransitionTime)
+	}
+	if conditions[0].Reason != "StillSuccess" || conditions[0].ObservedGeneration != 2 {
+		t.Errorf("expected reason and generation to be updated, got %+v", conditions[0])
+	}
+
+	SetCondition(&conditions, metav1.Condition{Type: "Ready", Status: metav1.ConditionFalse, Reason: "Error"})
+	if !conditions[0].LastTransitionTime.After(transition.Time) {
+		t.Errorf("expected the transition time to be updated for a status change, got %v", conditions[0].LastTransitionTime)
+	}
+}
+
 func TestSetConditionNilList(t *testing.T) {
 	var conditions *[]metav1.Condition
 	now := metav1.Now()
@@ -0,0 +1,34 @@
+//nolint:revive // utils is a standard package name for utilities
+package utils
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+// DefaultEventAggregationInterval is the default interval in which repeated events are collapsed
+const DefaultEventAggregationInterval = time.Hour
+
+// EventCorrelatorOptions returns correlator options collapsing all events of a resource with
+// the same type and reason into a single event: repeated failures increase the count and
+// lastTimestamp of the event and replace its message with the latest one, instead of
+// creating a new event on every retry.
+// Events are collapsed until no similar event was recorded for interval.
+func EventCorrelatorOptions(interval time.Duration) record.CorrelatorOptions {
+	return record.CorrelatorOptions{
+		// Collapse from the first event on, not only once several distinct messages were seen
+		MaxEvents:            1,
+		MaxIntervalInSeconds: int(interval.Seconds()),
+		MessageFunc: func(event *corev1.Event) string {
+			return event.Message
+		},
+	}
+}
+
+// NewEventBroadcaster returns an event broadcaster collapsing repeated events as described
+// in EventCorrelatorOptions.
+func NewEventBroadcaster(interval time.Duration) record.EventBroadcaster {
+	return record.NewBroadcasterWithCorrelatorOptions(EventCorrelatorOptions(interval))
+}
@@ -0,0 +1,61 @@
+//nolint:revive // utils is a standard package name for utilities
+package utils
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+func warningEvent(reason, message string) *corev1.Event {
+	now := metav1.Now()
+	return &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{Name: "rules.1", Namespace: "default"},
+		InvolvedObject: corev1.ObjectReference{
+			Kind: "PrometheusRule", Namespace: "default", Name: "rules", UID: "uid",
+		},
+		Reason:         reason,
+		Message:        message,
+		Type:           corev1.EventTypeWarning,
+		Source:         corev1.EventSource{Component: "prometheusrules-controller"},
+		FirstTimestamp: now,
+		LastTimestamp:  now,
+		Count:          1,
+	}
+}
+
+func TestEventCorrelatorOptions(t *testing.T) {
+	correlator := record.NewEventCorrelatorWithOptions(EventCorrelatorOptions(time.Hour))
+
+	first, err := correlator.EventCorrelate(warningEvent("RuleGroupCreateFailed", "connection refused"))
+	if err != nil || first.Skip {
+		t.Fatalf("EventCorrelate() = %+v, %v", first, err)
+	}
+	correlator.UpdateState(first.Event)
+
+	// A repeated failure with a different message updates the first event
+	second, err := correlator.EventCorrelate(warningEvent("RuleGroupCreateFailed", "i/o timeout"))
+	if err != nil || second.Skip {
+		t.Fatalf("EventCorrelate() = %+v, %v", second, err)
+	}
+	if second.Event.Name != first.Event.Name || second.Event.Count != 2 || second.Patch == nil {
+		t.Errorf("expected the first event %s to be patched to count 2, got %s with count %d",
+			first.Event.Name, second.Event.Name, second.Event.Count)
+	}
+	if second.Event.Message != "i/o timeout" {
+		t.Errorf("expected the latest message, got %q", second.Event.Message)
+	}
+	correlator.UpdateState(second.Event)
+
+	// Other reasons are reported separately
+	other, err := correlator.EventCorrelate(warningEvent("RuleGroupDeleteFailed", "connection refused"))
+	if err != nil || other.Skip {
+		t.Fatalf("EventCorrelate() = %+v, %v", other, err)
+	}
+	if other.Event.Name == first.Event.Name || other.Event.Count != 1 {
+		t.Errorf("expected a new event for another reason, got %s with count %d", other.Event.Name, other.Event.Count)
+	}
+}
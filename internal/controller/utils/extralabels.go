@@ -0,0 +1,104 @@
+//nolint:revive // utils is a standard package name for utilities
+package utils
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/prometheus/prometheus/model/rulefmt"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	k8sClient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ExtraLabels enriches alerting rules with routing-relevant labels, so alerts fired from
+// Mimir carry them without every team adding them. A nil ExtraLabels only applies the
+// extra-labels annotation of a resource.
+type ExtraLabels struct {
+	// Static labels are added to every alerting rule, e.g. cluster and region
+	Static map[string]string
+	// NamespaceLabels are copied from the labels of the resource's namespace if present
+	NamespaceLabels []string
+	// Reader reads namespaces, required if NamespaceLabels is set
+	Reader k8sClient.Reader
+}
+
+// For returns the extra labels of obj: the static labels, overridden by the copied
+// namespace labels, overridden by the extra-labels annotation of obj.
+// Returns an error if the namespace cannot be read or the annotation is invalid.
+func (e *ExtraLabels) For(ctx context.Context, obj metav1.Object) (map[string]string, error) {
+	labels := map[string]string{}
+	if e != nil {
+		for k, v := range e.Static {
+			labels[k] = v
+		}
+		if len(e.NamespaceLabels) > 0 {
+			namespace := &corev1.Namespace{}
+			if err := e.Reader.Get(ctx, types.NamespacedName{Name: obj.GetNamespace()}, namespace); err != nil {
+				return nil, fmt.Errorf("failed to get namespace %s: %w", obj.GetNamespace(), err)
+			}
+			for _, key := range e.NamespaceLabels {
+				if value := namespace.Labels[key]; value != "" {
+					labels[key] = value
+				}
+			}
+		}
+	}
+
+	annotated, err := ParseLabels(obj.GetAnnotations()[ExtraLabelsAnnotation])
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s annotation: %w", ExtraLabelsAnnotation, err)
+	}
+	for k, v := range annotated {
+		labels[k] = v
+	}
+	return labels, nil
+}
+
+// ParseLabels parses comma-separated labels in the form "key=value,key=value".
+// Returns an error if an entry is not a key=value pair or has an empty key.
+func ParseLabels(value string) (map[string]string, error) {
+	labels := map[string]string{}
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		key, val, ok := strings.Cut(entry, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid label %q, expected key=value", entry)
+		}
+		labels[key] = strings.TrimSpace(val)
+	}
+	return labels, nil
+}
+
+// InjectLabels adds the labels to every alerting rule of the groups. Labels already set
+// on the rule or its group take precedence. Label maps are copied so the source objects
+// the groups were converted from are not modified.
+func InjectLabels(groups []rulefmt.RuleGroup, labels map[string]string) {
+	if len(labels) == 0 {
+		return
+	}
+	for i := range groups {
+		for j := range groups[i].Rules {
+			rule := &groups[i].Rules[j]
+			if rule.Alert == "" {
+				continue
+			}
+			merged := make(map[string]string, len(rule.Labels)+len(labels))
+			for k, v := range labels {
+				if _, exists := groups[i].Labels[k]; !exists {
+					merged[k] = v
+				}
+			}
+			for k, v := range rule.Labels {
+				merged[k] = v
+			}
+			rule.Labels = merged
+		}
+	}
+}
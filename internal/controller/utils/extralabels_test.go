@@ -0,0 +1,81 @@
+//nolint:revive // utils is a standard package name for utilities
+package utils
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/prometheus/prometheus/model/rulefmt"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestExtraLabelsFor(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatalf("adding scheme: %v", err)
+	}
+	reader := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: "payments", Labels: map[string]string{"team": "payments", "tier": "1"}},
+	}).Build()
+	ctx := context.Background()
+	rule := &metav1.ObjectMeta{Name: "rules", Namespace: "payments", Annotations: map[string]string{
+		ExtraLabelsAnnotation: "region=us, service=checkout",
+	}}
+
+	var unset *ExtraLabels
+	labels, err := unset.For(ctx, rule)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := map[string]string{"region": "us", "service": "checkout"}; !reflect.DeepEqual(labels, want) {
+		t.Errorf("expected only annotated labels from nil ExtraLabels, got %v", labels)
+	}
+
+	extra := &ExtraLabels{
+		Static:          map[string]string{"cluster": "prod", "region": "eu", "team": "platform"},
+		NamespaceLabels: []string{"team", "missing"},
+		Reader:          reader,
+	}
+	labels, err = extra.For(ctx, rule)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := map[string]string{"cluster": "prod", "region": "us", "team": "payments", "service": "checkout"}
+	if !reflect.DeepEqual(labels, want) {
+		t.Errorf("For() = %v, want %v", labels, want)
+	}
+
+	rule.Annotations[ExtraLabelsAnnotation] = "region"
+	if _, err := extra.For(ctx, rule); err == nil {
+		t.Error("expected an error for an invalid annotation")
+	}
+}
+
+func TestInjectLabels(t *testing.T) {
+	ruleLabels := map[string]string{"severity": "critical", "team": "payments"}
+	groups := []rulefmt.RuleGroup{{
+		Name:   "group",
+		Labels: map[string]string{"region": "us"},
+		Rules: []rulefmt.Rule{
+			{Alert: "A", Labels: ruleLabels},
+			{Record: "job:up:sum"},
+		},
+	}}
+
+	InjectLabels(groups, map[string]string{"cluster": "prod", "region": "eu", "team": "platform"})
+
+	want := map[string]string{"severity": "critical", "team": "payments", "cluster": "prod"}
+	if got := groups[0].Rules[0].Labels; !reflect.DeepEqual(got, want) {
+		t.Errorf("alerting rule labels = %v, want %v", got, want)
+	}
+	if groups[0].Rules[1].Labels != nil {
+		t.Errorf("expected recording rules to be left unchanged, got %v", groups[0].Rules[1].Labels)
+	}
+	if len(ruleLabels) != 2 {
+		t.Errorf("expected the source labels not to be modified, got %v", ruleLabels)
+	}
+}
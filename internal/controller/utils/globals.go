@@ -0,0 +1,43 @@
+//nolint:revive // utils is a standard package name for utilities
+package utils
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	k8sClient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// GlobalValues reads controller-wide template values from a ConfigMap.
+// The ConfigMap is read on every call through the (cached) reader, so changes are
+// picked up by the next reconciliation. A nil GlobalValues provides no values.
+type GlobalValues struct {
+	Reader    k8sClient.Reader
+	ConfigMap types.NamespacedName
+}
+
+// Get returns the data of the global values ConfigMap.
+// Returns an error if the ConfigMap cannot be read.
+func (g *GlobalValues) Get(ctx context.Context) (map[string]string, error) {
+	if g == nil || g.ConfigMap.Name == "" {
+		return nil, nil
+	}
+
+	cm := &corev1.ConfigMap{}
+	if err := g.Reader.Get(ctx, g.ConfigMap, cm); err != nil {
+		return nil, fmt.Errorf("failed to get global values ConfigMap %s: %w", g.ConfigMap, err)
+	}
+	return cm.Data, nil
+}
+
+// ParseNamespacedName parses a reference in the form "namespace/name".
+func ParseNamespacedName(ref string) (types.NamespacedName, error) {
+	namespace, name, ok := strings.Cut(ref, "/")
+	if !ok || namespace == "" || name == "" || strings.Contains(name, "/") {
+		return types.NamespacedName{}, fmt.Errorf("invalid reference %q, expected namespace/name", ref)
+	}
+	return types.NamespacedName{Namespace: namespace, Name: name}, nil
+}
@@ -0,0 +1,76 @@
+//nolint:revive // utils is a standard package name for utilities
+package utils
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestGlobalValues(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatalf("adding scheme: %v", err)
+	}
+	reader := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "globals", Namespace: "platform"},
+		Data:       map[string]string{"CLUSTER": "eu-1"},
+	}).Build()
+	ctx := context.Background()
+
+	var unset *GlobalValues
+	if values, err := unset.Get(ctx); err != nil || values != nil {
+		t.Errorf("expected no values from nil GlobalValues, got %v, %v", values, err)
+	}
+
+	globals := &GlobalValues{Reader: reader, ConfigMap: types.NamespacedName{Namespace: "platform", Name: "globals"}}
+	values, err := globals.Get(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if values["CLUSTER"] != "eu-1" {
+		t.Errorf("expected CLUSTER=eu-1, got %v", values)
+	}
+
+	missing := &GlobalValues{Reader: reader, ConfigMap: types.NamespacedName{Namespace: "platform", Name: "missing"}}
+	if _, err := missing.Get(ctx); err == nil {
+		t.Error("expected an error for a missing ConfigMap")
+	}
+}
+
+func TestParseNamespacedName(t *testing.T) {
+	tests := []struct {
+		ref         string
+		expected    types.NamespacedName
+		expectError bool
+	}{
+		{ref: "platform/globals", expected: types.NamespacedName{Namespace: "platform", Name: "globals"}},
+		{ref: "globals", expectError: true},
+		{ref: "/globals", expectError: true},
+		{ref: "platform/", expectError: true},
+		{ref: "a/b/c", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			got, err := ParseNamespacedName(tt.ref)
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("expected an error, got %v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("ParseNamespacedName(%q) = %v, want %v", tt.ref, got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+//nolint:revive // utils is a standard package name for utilities
+package utils
+
+import (
+	"context"
+
+	k8sClient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	openawarenessv1beta1 "github.com/syndlex/openawareness-controller/api/openawareness/v1beta1"
+)
+
+// hubClient reads and writes synced resources in a hub cluster while ClientConfigs, which
+// define the Mimir endpoints, and TenantMappings are read from the cluster the controller
+// runs in.
+type hubClient struct {
+	k8sClient.Client
+	local k8sClient.Reader
+}
+
+// NewHubClient returns a client operating on the hub cluster through hub, except for
+// ClientConfigs and TenantMappings, which are read from the local cluster through local.
+func NewHubClient(hub k8sClient.Client, local k8sClient.Reader) k8sClient.Client {
+	return &hubClient{Client: hub, local: local}
+}
+
+// Get reads ClientConfigs and TenantMappings from the local cluster and all other objects from
+// the hub cluster.
+func (c *hubClient) Get(
+	ctx context.Context,
+	key k8sClient.ObjectKey,
+	obj k8sClient.Object,
+	opts ...k8sClient.GetOption,
+) error {
+	switch obj.(type) {
+	case *openawarenessv1beta1.ClientConfig, *openawarenessv1beta1.TenantMapping:
+		return c.local.Get(ctx, key, obj, opts...)
+	}
+	return c.Client.Get(ctx, key, obj, opts...)
+}
+
+// List lists ClientConfigs and TenantMappings from the local cluster and all other objects from
+// the hub cluster.
+func (c *hubClient) List(ctx context.Context, list k8sClient.ObjectList, opts ...k8sClient.ListOption) error {
+	switch list.(type) {
+	case *openawarenessv1beta1.ClientConfigList, *openawarenessv1beta1.TenantMappingList:
+		return c.local.List(ctx, list, opts...)
+	}
+	return c.Client.List(ctx, list, opts...)
+}
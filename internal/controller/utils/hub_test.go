@@ -0,0 +1,57 @@
+//nolint:revive // utils is a standard package name for utilities
+package utils
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	openawarenessv1beta1 "github.com/syndlex/openawareness-controller/api/openawareness/v1beta1"
+)
+
+func TestHubClient(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := openawarenessv1beta1.AddToScheme(scheme); err != nil {
+		t.Fatalf("adding scheme: %v", err)
+	}
+
+	local := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&openawarenessv1beta1.ClientConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "mimir", Namespace: "team"},
+	}, &openawarenessv1beta1.TenantMapping{
+		ObjectMeta: metav1.ObjectMeta{Name: "team"},
+	}).Build()
+	hub := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&openawarenessv1beta1.MimirAlertTenant{
+		ObjectMeta: metav1.ObjectMeta{Name: "tenant", Namespace: "team"},
+	}).Build()
+	hubClient := NewHubClient(hub, local)
+	ctx := context.Background()
+
+	if err := hubClient.Get(ctx, client.ObjectKey{Namespace: "team", Name: "mimir"},
+		&openawarenessv1beta1.ClientConfig{}); err != nil {
+		t.Errorf("expected the ClientConfig to be read from the local cluster: %v", err)
+	}
+	if err := hubClient.Get(ctx, client.ObjectKey{Name: "team"}, &openawarenessv1beta1.TenantMapping{}); err != nil {
+		t.Errorf("expected the TenantMapping to be read from the local cluster: %v", err)
+	}
+	clientConfigs := &openawarenessv1beta1.ClientConfigList{}
+	if err := hubClient.List(ctx, clientConfigs); err != nil || len(clientConfigs.Items) != 1 {
+		t.Errorf("expected the local ClientConfig, got %d (%v)", len(clientConfigs.Items), err)
+	}
+
+	tenant := &openawarenessv1beta1.MimirAlertTenant{}
+	if err := hubClient.Get(ctx, client.ObjectKey{Namespace: "team", Name: "tenant"}, tenant); err != nil {
+		t.Fatalf("expected the MimirAlertTenant to be read from the hub cluster: %v", err)
+	}
+	tenant.Annotations = map[string]string{MimirTenantAnnotation: "team"}
+	if err := hubClient.Update(ctx, tenant); err != nil {
+		t.Fatalf("expected the MimirAlertTenant to be updated in the hub cluster: %v", err)
+	}
+	if err := local.Get(ctx, client.ObjectKey{Namespace: "team", Name: "tenant"},
+		&openawarenessv1beta1.MimirAlertTenant{}); err == nil {
+		t.Error("expected the local cluster to be untouched")
+	}
+}
@@ -0,0 +1,200 @@
+//nolint:revive // utils is a standard package name for utilities
+package utils
+
+import (
+	"maps"
+	"slices"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	k8sClient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	openawarenessv1beta1 "github.com/syndlex/openawareness-controller/api/openawareness/v1beta1"
+)
+
+// ClientNameIndexKey is the field index key under which resources are indexed by the
+// ClientConfig they reference through ClientNameAnnotation.
+const ClientNameIndexKey = ".metadata.annotations.clientName"
+
+// DefaultClientIndexValue is the index value of resources without ClientNameAnnotation,
+// which use the default ClientConfig. It cannot collide with a ClientConfig name.
+const DefaultClientIndexValue = "*default*"
+
+// ClientNameIndexer is a client.IndexerFunc returning the ClientConfig referenced by the
+// object's ClientNameAnnotation as written, a name or "namespace/name", or
+// DefaultClientIndexValue without the annotation.
+func ClientNameIndexer(obj k8sClient.Object) []string {
+	clientName := obj.GetAnnotations()[ClientNameAnnotation]
+	if clientName == "" {
+		return []string{DefaultClientIndexValue}
+	}
+	return []string{clientName}
+}
+
+// ClientNameIndexValues returns the ClientNameIndexKey values of the resources that may
+// reference clientConfig: its name, which resolves across namespaces, see GetClientConfig,
+// and its "namespace/name".
+func ClientNameIndexValues(clientConfig *openawarenessv1beta1.ClientConfig) []string {
+	return []string{clientConfig.Name, clientConfig.Namespace + "/" + clientConfig.Name}
+}
+
+// ClientConfigChangedPredicate filters the updates of ClientConfigs watched by the resources
+// referencing them down to changes of the spec or the connection status. Health checks
+// rewriting the rest of the status do not requeue the referencing resources.
+func ClientConfigChangedPredicate() predicate.Funcs {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldConfig, ok := e.ObjectOld.(*openawarenessv1beta1.ClientConfig)
+			if !ok {
+				return true
+			}
+			newConfig, ok := e.ObjectNew.(*openawarenessv1beta1.ClientConfig)
+			if !ok {
+				return true
+			}
+			return oldConfig.Generation != newConfig.Generation ||
+				oldConfig.Status.ConnectionStatus != newConfig.Status.ConnectionStatus
+		},
+	}
+}
+
+// DefaultClientListOptions returns the list options selecting the resources without
+// ClientNameAnnotation within the scope of the default ClientConfig.
+func DefaultClientListOptions(clientConfig *openawarenessv1beta1.ClientConfig) []k8sClient.ListOption {
+	opts := []k8sClient.ListOption{k8sClient.MatchingFields{ClientNameIndexKey: DefaultClientIndexValue}}
+	if clientConfig.EffectiveDefaultScope() == openawarenessv1beta1.DefaultScopeNamespace {
+		opts = append(opts, k8sClient.InNamespace(clientConfig.Namespace))
+	}
+	return opts
+}
+
+// ExtendsIndexKey is the field index key under which MimirAlertTenants are indexed by the
+// tenant they extend.
+const ExtendsIndexKey = ".spec.extends.name"
+
+// ExtendsIndexer is a client.IndexerFunc returning the name of the tenant a MimirAlertTenant
+// extends, nothing if it extends no tenant.
+func ExtendsIndexer(obj k8sClient.Object) []string {
+	tenant, ok := obj.(*openawarenessv1beta1.MimirAlertTenant)
+	if !ok || tenant.Spec.Extends == nil {
+		return nil
+	}
+	return []string{tenant.Spec.Extends.Name}
+}
+
+// ConfigSecretIndexKey is the field index key under which MimirAlertTenants are indexed by the
+// Secret their alertmanagerConfig is read from.
+const ConfigSecretIndexKey = ".spec.alertmanagerConfigFrom.secretKeyRef.name"
+
+// ConfigSecretIndexer is a client.IndexerFunc returning the name of the Secret the
+// alertmanagerConfig of a MimirAlertTenant is read from, nothing if it is set inline.
+func ConfigSecretIndexer(obj k8sClient.Object) []string {
+	tenant, ok := obj.(*openawarenessv1beta1.MimirAlertTenant)
+	if !ok || tenant.Spec.AlertmanagerConfigFrom == nil {
+		return nil
+	}
+	return []string{tenant.Spec.AlertmanagerConfigFrom.SecretKeyRef.Name}
+}
+
+// TenantIndexKey is the field index key under which resources are indexed by the Mimir tenant
+// of their MimirTenantAnnotation as written, before it is resolved through a ClientConfig.
+const TenantIndexKey = ".metadata.annotations.tenant"
+
+// DefaultTenantIndexValue is the TenantIndexKey value of resources without MimirTenantAnnotation,
+// which target the default tenant of their ClientConfig. It cannot collide with a tenant ID.
+const DefaultTenantIndexValue = "*default*"
+
+// TenantIndexer is a client.IndexerFunc returning the Mimir tenant of the object's
+// MimirTenantAnnotation, or DefaultTenantIndexValue without the annotation.
+func TenantIndexer(obj k8sClient.Object) []string {
+	tenantID := obj.GetAnnotations()[MimirTenantAnnotation]
+	if tenantID == "" {
+		return []string{DefaultTenantIndexValue}
+	}
+	return []string{tenantID}
+}
+
+// TenantIndexValues returns the TenantIndexKey values of the resources that may target tenantID
+// through clientConfig: tenantID, the aliases of tenantID and DefaultTenantIndexValue. Whether a
+// resource does is decided by resolving its tenant, see GetTenantID.
+func TenantIndexValues(clientConfig *openawarenessv1beta1.ClientConfig, tenantID string) []string {
+	values := []string{tenantID, DefaultTenantIndexValue}
+	if clientConfig == nil {
+		return values
+	}
+	for _, alias := range slices.Sorted(maps.Keys(clientConfig.Spec.TenantAliases)) {
+		if alias != tenantID && clientConfig.Spec.TenantAliases[alias] == tenantID {
+			values = append(values, alias)
+		}
+	}
+	return values
+}
+
+// QuotaExceededIndexKey is the field index key under which MimirAlertTenants exceeding the quota
+// of the controller are indexed.
+const QuotaExceededIndexKey = ".status.conditions.quotaExceeded"
+
+// QuotaExceededIndexer is a client.IndexerFunc returning "true" for MimirAlertTenants whose
+// ConfigValid condition reports an exceeded quota, nothing otherwise.
+func QuotaExceededIndexer(obj k8sClient.Object) []string {
+	tenant, ok := obj.(*openawarenessv1beta1.MimirAlertTenant)
+	if !ok {
+		return nil
+	}
+	valid := meta.FindStatusCondition(tenant.Status.Conditions, openawarenessv1beta1.ConditionTypeConfigValid)
+	if valid == nil || valid.Reason != openawarenessv1beta1.ReasonQuotaExceeded {
+		return nil
+	}
+	return []string{"true"}
+}
+
+// CAConfigMapIndexKey is the field index key under which ClientConfigs are indexed by the
+// ConfigMap their CA bundle is read from.
+const CAConfigMapIndexKey = ".spec.tls.caConfigMapRef.name"
+
+// CAConfigMapIndexer is a client.IndexerFunc returning the name of the ConfigMap the CA
+// bundle of a ClientConfig is read from, nothing if it references none.
+func CAConfigMapIndexer(obj k8sClient.Object) []string {
+	clientConfig, ok := obj.(*openawarenessv1beta1.ClientConfig)
+	if !ok || clientConfig.Spec.TLS == nil || clientConfig.Spec.TLS.CAConfigMapRef == nil {
+		return nil
+	}
+	return []string{clientConfig.Spec.TLS.CAConfigMapRef.Name}
+}
+
+// HMACSecretIndexKey is the field index key under which ClientConfigs are indexed by the
+// Secret their HMAC signing key is read from.
+const HMACSecretIndexKey = ".spec.auth.hmac.secretRef.name"
+
+// HMACSecretIndexer is a client.IndexerFunc returning the name of the Secret the HMAC signing
+// key of a ClientConfig is read from, nothing if it does not sign requests.
+func HMACSecretIndexer(obj k8sClient.Object) []string {
+	clientConfig, ok := obj.(*openawarenessv1beta1.ClientConfig)
+	if !ok || clientConfig.Spec.Auth == nil || clientConfig.Spec.Auth.HMAC == nil {
+		return nil
+	}
+	return []string{clientConfig.Spec.Auth.HMAC.SecretRef.Name}
+}
+
+// SecretDataRefIndexKey is the field index key under which templated resources are indexed by
+// the ConfigMaps and Secrets of their SecretDataRefsAnnotation.
+const SecretDataRefIndexKey = ".metadata.annotations.secretDataRefs"
+
+// SecretDataRefIndexer is a client.IndexerFunc returning the ConfigMaps and Secrets providing
+// the template variables of an object in the form "Kind/name", nothing if the object does not
+// opt in to templating or its SecretDataRefsAnnotation is invalid.
+func SecretDataRefIndexer(obj k8sClient.Object) []string {
+	if !TemplatingEnabled(obj) {
+		return nil
+	}
+	refs, err := SecretDataRefs(obj)
+	if err != nil {
+		return nil
+	}
+	values := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		values = append(values, ref.Kind+"/"+ref.Name)
+	}
+	return values
+}
@@ -0,0 +1,133 @@
+//nolint:revive // utils is a standard package name for utilities
+package utils
+
+import (
+	"reflect"
+	"testing"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	openawarenessv1beta1 "github.com/syndlex/openawareness-controller/api/openawareness/v1beta1"
+)
+
+func TestClientNameIndexer(t *testing.T) {
+	withAnnotation := &monitoringv1.PrometheusRule{ObjectMeta: metav1.ObjectMeta{
+		Annotations: map[string]string{ClientNameAnnotation: "mimir"},
+	}}
+	if got := ClientNameIndexer(withAnnotation); len(got) != 1 || got[0] != "mimir" {
+		t.Errorf("ClientNameIndexer() = %v, want [mimir]", got)
+	}
+
+	withoutAnnotation := &monitoringv1.PrometheusRule{}
+	if got := ClientNameIndexer(withoutAnnotation); len(got) != 1 || got[0] != DefaultClientIndexValue {
+		t.Errorf("ClientNameIndexer() = %v, want [%s]", got, DefaultClientIndexValue)
+	}
+}
+
+func TestSecretDataRefIndexer(t *testing.T) {
+	rule := &monitoringv1.PrometheusRule{ObjectMeta: metav1.ObjectMeta{
+		Annotations: map[string]string{
+			TemplateAnnotation:       "true",
+			SecretDataRefsAnnotation: "ConfigMap/thresholds, Secret/credentials",
+		},
+	}}
+	if got, want := SecretDataRefIndexer(rule), []string{"ConfigMap/thresholds", "Secret/credentials"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("SecretDataRefIndexer() = %v, want %v", got, want)
+	}
+
+	rule.Annotations[TemplateAnnotation] = "false"
+	if got := SecretDataRefIndexer(rule); len(got) != 0 {
+		t.Errorf("expected rules not opting in to templating not to be indexed, got %v", got)
+	}
+
+	rule.Annotations[TemplateAnnotation] = "true"
+	rule.Annotations[SecretDataRefsAnnotation] = "thresholds"
+	if got := SecretDataRefIndexer(rule); len(got) != 0 {
+		t.Errorf("expected rules with an invalid annotation not to be indexed, got %v", got)
+	}
+	if _, err := SecretDataRefs(rule); err == nil {
+		t.Error("expected an error for an entry without kind")
+	}
+	rule.Annotations[SecretDataRefsAnnotation] = "Deployment/thresholds"
+	if _, err := SecretDataRefs(rule); err == nil {
+		t.Error("expected an error for an unsupported kind")
+	}
+}
+
+func TestClientConfigChangedPredicate(t *testing.T) {
+	connected := &openawarenessv1beta1.ClientConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "mimir", Generation: 1},
+		Status: openawarenessv1beta1.ClientConfigStatus{
+			ConnectionStatus: openawarenessv1beta1.ConnectionStatusConnected,
+		},
+	}
+	healthChecked := connected.DeepCopy()
+	now := metav1.Now()
+	healthChecked.Status.LastConnectionTime = &now
+	disconnected := connected.DeepCopy()
+	disconnected.Status.ConnectionStatus = openawarenessv1beta1.ConnectionStatusDisconnected
+	changed := connected.DeepCopy()
+	changed.Generation = 2
+
+	tests := []struct {
+		name string
+		new  *openawarenessv1beta1.ClientConfig
+		want bool
+	}{
+		{name: "status rewritten by health check", new: healthChecked, want: false},
+		{name: "connection status changed", new: disconnected, want: true},
+		{name: "spec changed", new: changed, want: true},
+	}
+	pred := ClientConfigChangedPredicate()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pred.Update(event.UpdateEvent{ObjectOld: connected, ObjectNew: tt.new}); got != tt.want {
+				t.Errorf("Update() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	if !pred.Delete(event.DeleteEvent{Object: connected}) {
+		t.Error("expected deletions of ClientConfigs to pass")
+	}
+}
+
+func TestTenantIndexer(t *testing.T) {
+	withAnnotation := &openawarenessv1beta1.MimirAlertTenant{ObjectMeta: metav1.ObjectMeta{
+		Annotations: map[string]string{MimirTenantAnnotation: "team-a"},
+	}}
+	if got := TenantIndexer(withAnnotation); !reflect.DeepEqual(got, []string{"team-a"}) {
+		t.Errorf("TenantIndexer() = %v, want [team-a]", got)
+	}
+	if got := TenantIndexer(&openawarenessv1beta1.MimirAlertTenant{}); !reflect.DeepEqual(got,
+		[]string{DefaultTenantIndexValue}) {
+		t.Errorf("TenantIndexer() = %v, want [%s]", got, DefaultTenantIndexValue)
+	}
+
+	clientConfig := &openawarenessv1beta1.ClientConfig{Spec: openawarenessv1beta1.ClientConfigSpec{
+		TenantAliases: map[string]string{"payments": "org-1", "billing": "org-1", "shop": "org-2"},
+	}}
+	want := []string{"org-1", DefaultTenantIndexValue, "billing", "payments"}
+	if got := TenantIndexValues(clientConfig, "org-1"); !reflect.DeepEqual(got, want) {
+		t.Errorf("TenantIndexValues() = %v, want %v", got, want)
+	}
+	if got := TenantIndexValues(nil, "org-1"); !reflect.DeepEqual(got, want[:2]) {
+		t.Errorf("TenantIndexValues() = %v, want %v", got, want[:2])
+	}
+}
+
+func TestQuotaExceededIndexer(t *testing.T) {
+	tenant := &openawarenessv1beta1.MimirAlertTenant{}
+	if got := QuotaExceededIndexer(tenant); len(got) != 0 {
+		t.Errorf("expected tenants without ConfigValid condition not to be indexed, got %v", got)
+	}
+	tenant.Status.Conditions = []metav1.Condition{{
+		Type:   openawarenessv1beta1.ConditionTypeConfigValid,
+		Status: metav1.ConditionFalse,
+		Reason: openawarenessv1beta1.ReasonQuotaExceeded,
+	}}
+	if got := QuotaExceededIndexer(tenant); !reflect.DeepEqual(got, []string{"true"}) {
+		t.Errorf("QuotaExceededIndexer() = %v, want [true]", got)
+	}
+}
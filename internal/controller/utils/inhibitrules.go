@@ -0,0 +1,211 @@
+//nolint:revive // utils is a standard package name for utilities
+package utils
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+	k8sClient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	openawarenessv1beta1 "github.com/syndlex/openawareness-controller/api/openawareness/v1beta1"
+)
+
+// ErrInvalidInhibitRule is returned for MimirInhibitRules that cannot be merged into a configuration
+var ErrInvalidInhibitRule = errors.New("invalid inhibition rule")
+
+// ErrDuplicateInhibitRule is returned for MimirInhibitRules equal to an inhibition rule of the
+// configuration or of other MimirInhibitRules of the tenant chain
+var ErrDuplicateInhibitRule = errors.New("duplicate inhibition rule")
+
+var (
+	// matcherPattern matches an Alertmanager matcher, e.g. severity="warning" or team=~"a|b"
+	matcherPattern = regexp.MustCompile(
+		`^\s*("[^"]+"|[a-zA-Z_][a-zA-Z0-9_]*)\s*(=~|!~|!=|=)\s*("(?:[^"\\]|\\.)*"|[^"\s,{}]*)\s*$`)
+	// labelNamePattern matches a label name
+	labelNamePattern = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+)
+
+// ComposedInhibitRules returns the MimirInhibitRules contributed to the tenants of a chain
+// returned by ResolveExtends, in the order they are merged: the inhibition rules of base
+// tenants first and the inhibition rules of each tenant sorted by name.
+func ComposedInhibitRules(
+	ctx context.Context,
+	reader k8sClient.Reader,
+	chain []*openawarenessv1beta1.MimirAlertTenant,
+) ([]openawarenessv1beta1.MimirInhibitRule, error) {
+	namespace := chain[len(chain)-1].Namespace
+	ruleList := &openawarenessv1beta1.MimirInhibitRuleList{}
+	if err := reader.List(ctx, ruleList, k8sClient.InNamespace(namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list MimirInhibitRules in %s: %w", namespace, err)
+	}
+
+	position := func(rule openawarenessv1beta1.MimirInhibitRule) int {
+		return slices.IndexFunc(chain, func(tenant *openawarenessv1beta1.MimirAlertTenant) bool {
+			return tenant.Name == rule.Spec.Tenant.Name
+		})
+	}
+	var composed []openawarenessv1beta1.MimirInhibitRule
+	for _, rule := range ruleList.Items {
+		if position(rule) >= 0 {
+			composed = append(composed, rule)
+		}
+	}
+	slices.SortFunc(composed, func(a, b openawarenessv1beta1.MimirInhibitRule) int {
+		return cmp.Or(cmp.Compare(position(a), position(b)), strings.Compare(a.Name, b.Name))
+	})
+	return composed, nil
+}
+
+// MergeInhibitRules appends the inhibition rules of rules to the inhibit_rules of the rendered
+// Alertmanager configuration config, after the inhibition rules of the configuration.
+// MimirInhibitRules are rejected and left out if a matcher or label name is invalid, or they
+// equal an inhibition rule of the configuration or of a MimirInhibitRule merged before them,
+// regardless of the order of matchers and labels.
+// Returns the merged configuration and the rejection errors by MimirInhibitRule name, which
+// wrap ErrInvalidInhibitRule or ErrDuplicateInhibitRule, or an error wrapping ErrComposition if
+// config is not a YAML mapping.
+func MergeInhibitRules(
+	config string,
+	rules []openawarenessv1beta1.MimirInhibitRule,
+) (string, map[string]error, error) {
+	if len(rules) == 0 {
+		return config, nil, nil
+	}
+	merged, err := unmarshalMapping(config)
+	if err != nil {
+		return "", nil, fmt.Errorf("%w with MimirInhibitRules: %w", ErrComposition, err)
+	}
+
+	// owners tracks the source of each inhibition rule by its key, empty for the configuration itself
+	inhibitRules := asList(merged["inhibit_rules"])
+	owners := map[string]string{}
+	for _, entry := range inhibitRules {
+		if inline, ok := entry.(map[string]any); ok {
+			owners[inlineInhibitRuleKey(inline)] = ""
+		}
+	}
+
+	rejected := map[string]error{}
+	for i := range rules {
+		rule := &rules[i]
+		if err := validateInhibitRule(rule.Spec); err != nil {
+			rejected[rule.Name] = err
+			continue
+		}
+		key := inhibitRuleKey(rule.Spec.SourceMatchers, rule.Spec.TargetMatchers, rule.Spec.Equal)
+		if owner, ok := owners[key]; ok {
+			if owner == "" {
+				rejected[rule.Name] = fmt.Errorf("%w: the configuration already defines the same inhibition rule",
+					ErrDuplicateInhibitRule)
+			} else {
+				rejected[rule.Name] = fmt.Errorf("%w: MimirInhibitRule %s already defines the same inhibition rule",
+					ErrDuplicateInhibitRule, owner)
+			}
+			continue
+		}
+		owners[key] = rule.Name
+		inhibitRules = append(inhibitRules, renderInhibitRule(rule.Spec))
+	}
+
+	merged["inhibit_rules"] = inhibitRules
+	rendered, err := yaml.Marshal(merged)
+	if err != nil {
+		return "", nil, err
+	}
+	return string(rendered), rejected, nil
+}
+
+// validateInhibitRule checks the matchers and equal label names of an inhibition rule.
+func validateInhibitRule(spec openawarenessv1beta1.MimirInhibitRuleSpec) error {
+	if len(spec.SourceMatchers) == 0 || len(spec.TargetMatchers) == 0 {
+		return fmt.Errorf("%w: source and target matchers are required", ErrInvalidInhibitRule)
+	}
+	for _, matcher := range slices.Concat(spec.SourceMatchers, spec.TargetMatchers) {
+		if !matcherPattern.MatchString(matcher) {
+			return fmt.Errorf("%w: invalid matcher %q", ErrInvalidInhibitRule, matcher)
+		}
+	}
+	for _, label := range spec.Equal {
+		if !labelNamePattern.MatchString(label) {
+			return fmt.Errorf("%w: invalid label name %q in equal", ErrInvalidInhibitRule, label)
+		}
+	}
+	return nil
+}
+
+// renderInhibitRule returns the inhibition rule of spec in the Alertmanager configuration format.
+func renderInhibitRule(spec openawarenessv1beta1.MimirInhibitRuleSpec) map[string]any {
+	rendered := map[string]any{
+		"source_matchers": toAnyList(spec.SourceMatchers),
+		"target_matchers": toAnyList(spec.TargetMatchers),
+	}
+	if len(spec.Equal) > 0 {
+		rendered["equal"] = toAnyList(spec.Equal)
+	}
+	return rendered
+}
+
+// inlineInhibitRuleKey returns the key of an inhibition rule of a configuration, translating
+// the deprecated source_match, source_match_re, target_match and target_match_re to matchers.
+func inlineInhibitRuleKey(rule map[string]any) string {
+	matchers := func(prefix string) []string {
+		list := toStringList(rule[prefix+"_matchers"])
+		for operator, key := range map[string]string{"=": prefix + "_match", "=~": prefix + "_match_re"} {
+			values, _ := rule[key].(map[string]any)
+			for _, name := range slices.Sorted(maps.Keys(values)) {
+				list = append(list, name+operator+strconv.Quote(fmt.Sprint(values[name])))
+			}
+		}
+		return list
+	}
+	return inhibitRuleKey(matchers("source"), matchers("target"), toStringList(rule["equal"]))
+}
+
+// inhibitRuleKey identifies an inhibition rule independently of the order of its matchers and
+// labels and of the whitespace and quoting around matcher operators.
+func inhibitRuleKey(sourceMatchers, targetMatchers, equal []string) string {
+	normalize := func(values []string) string {
+		normalized := make([]string, 0, len(values))
+		for _, value := range values {
+			if parts := matcherPattern.FindStringSubmatch(value); parts != nil {
+				name, operator, matched := strings.Trim(parts[1], `"`), parts[2], parts[3]
+				if unquoted, err := strconv.Unquote(matched); err == nil {
+					matched = unquoted
+				}
+				value = name + operator + strconv.Quote(matched)
+			}
+			normalized = append(normalized, strings.TrimSpace(value))
+		}
+		slices.Sort(normalized)
+		return strings.Join(normalized, ",")
+	}
+	return normalize(sourceMatchers) + "|" + normalize(targetMatchers) + "|" + normalize(equal)
+}
+
+// toAnyList returns values as a YAML list.
+func toAnyList(values []string) []any {
+	list := make([]any, 0, len(values))
+	for _, value := range values {
+		list = append(list, value)
+	}
+	return list
+}
+
+// toStringList returns the string entries of value if it is a list.
+func toStringList(value any) []string {
+	var list []string
+	for _, entry := range asList(value) {
+		if s, ok := entry.(string); ok {
+			list = append(list, s)
+		}
+	}
+	return list
+}
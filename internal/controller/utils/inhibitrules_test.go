@@ -0,0 +1,140 @@
+//nolint:revive // utils is a standard package name for utilities
+package utils
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	openawarenessv1beta1 "github.com/syndlex/openawareness-controller/api/openawareness/v1beta1"
+)
+
+func inhibitRule(name, tenant string, source, target, equal []string) openawarenessv1beta1.MimirInhibitRule {
+	return openawarenessv1beta1.MimirInhibitRule{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "team"},
+		Spec: openawarenessv1beta1.MimirInhibitRuleSpec{
+			Tenant:         openawarenessv1beta1.TenantReference{Name: tenant},
+			SourceMatchers: source,
+			TargetMatchers: target,
+			Equal:          equal,
+		},
+	}
+}
+
+func TestComposedInhibitRules(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := openawarenessv1beta1.AddToScheme(scheme); err != nil {
+		t.Fatalf("adding scheme: %v", err)
+	}
+	rules := []openawarenessv1beta1.MimirInhibitRule{
+		inhibitRule("a-critical", "oncall", nil, nil, nil),
+		inhibitRule("z-cluster-down", "root", nil, nil, nil),
+		inhibitRule("b-maintenance", "oncall", nil, nil, nil),
+		inhibitRule("search", "other", nil, nil, nil),
+	}
+	builder := fake.NewClientBuilder().WithScheme(scheme)
+	for i := range rules {
+		builder = builder.WithObjects(&rules[i])
+	}
+	chain := []*openawarenessv1beta1.MimirAlertTenant{
+		extendingTenant("root", "", ""),
+		extendingTenant("oncall", "root", ""),
+	}
+
+	composed, err := ComposedInhibitRules(context.Background(), builder.Build(), chain)
+	if err != nil {
+		t.Fatalf("ComposedInhibitRules() error = %v", err)
+	}
+	var names []string
+	for _, entry := range composed {
+		names = append(names, entry.Name)
+	}
+	if !reflect.DeepEqual(names, []string{"z-cluster-down", "a-critical", "b-maintenance"}) {
+		t.Errorf("expected the inhibition rules of base tenants first, sorted by name, got %v", names)
+	}
+}
+
+func TestMergeInhibitRules(t *testing.T) {
+	config := `
+route:
+  receiver: default
+receivers:
+  - name: default
+inhibit_rules:
+  - source_match:
+      severity: critical
+    target_match:
+      severity: warning
+    equal: [alertname]
+`
+	rules := []openawarenessv1beta1.MimirInhibitRule{
+		inhibitRule("cluster-down", "root",
+			[]string{`alertname="ClusterDown"`}, []string{`severity=~"warning|info"`}, []string{"cluster"}),
+		inhibitRule("inline", "oncall",
+			[]string{`severity = "critical"`}, []string{"severity=warning"}, []string{"alertname"}),
+		inhibitRule("reordered", "oncall",
+			[]string{`"alertname"="ClusterDown"`}, []string{`severity=~"warning|info"`}, []string{"cluster"}),
+		inhibitRule("matcher", "oncall", []string{`alertname=="x"`}, []string{`severity="warning"`}, nil),
+		inhibitRule("label", "oncall", []string{`alertname="x"`}, []string{`severity="warning"`}, []string{"not-a-label"}),
+		inhibitRule("maintenance", "oncall", []string{`alertname="Maintenance"`}, []string{`team="payments"`}, nil),
+	}
+
+	merged, rejected, err := MergeInhibitRules(config, rules)
+	if err != nil {
+		t.Fatalf("MergeInhibitRules() error = %v", err)
+	}
+	want := `
+route:
+  receiver: default
+receivers:
+  - name: default
+inhibit_rules:
+  - source_match:
+      severity: critical
+    target_match:
+      severity: warning
+    equal: [alertname]
+  - source_matchers: ['alertname="ClusterDown"']
+    target_matchers: ['severity=~"warning|info"']
+    equal: [cluster]
+  - source_matchers: ['alertname="Maintenance"']
+    target_matchers: ['team="payments"']
+`
+	var got, expected map[string]any
+	if err := yaml.Unmarshal([]byte(merged), &got); err != nil {
+		t.Fatalf("merged configuration is invalid: %v", err)
+	}
+	if err := yaml.Unmarshal([]byte(want), &expected); err != nil {
+		t.Fatalf("expected configuration is invalid: %v", err)
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("unexpected merged configuration:\n%s", merged)
+	}
+
+	for _, name := range []string{"inline", "reordered"} {
+		if !errors.Is(rejected[name], ErrDuplicateInhibitRule) {
+			t.Errorf("expected a duplicate inhibition rule error for %s, got %v", name, rejected[name])
+		}
+	}
+	for _, name := range []string{"matcher", "label"} {
+		if !errors.Is(rejected[name], ErrInvalidInhibitRule) {
+			t.Errorf("expected an invalid inhibition rule error for %s, got %v", name, rejected[name])
+		}
+	}
+	if len(rejected) != 4 {
+		t.Errorf("expected 4 rejected inhibition rules, got %v", rejected)
+	}
+
+	if unchanged, _, err := MergeInhibitRules("- a list", nil); err != nil || unchanged != "- a list" {
+		t.Errorf("expected the configuration unchanged without inhibition rules, got %q, %v", unchanged, err)
+	}
+	if _, _, err := MergeInhibitRules("- a list", rules); !errors.Is(err, ErrComposition) {
+		t.Errorf("expected a composition error, got %v", err)
+	}
+}
@@ -0,0 +1,198 @@
+//nolint:revive // utils is a standard package name for utilities
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	openawarenessv1beta1 "github.com/syndlex/openawareness-controller/api/openawareness/v1beta1"
+)
+
+// ErrInvalidMatcher is returned for matchers that cannot be parsed with the matcher syntax of a tenant
+var ErrInvalidMatcher = errors.New("invalid matcher")
+
+// classicMatcherPattern matches a matcher of the classic syntax, the value is checked separately
+var classicMatcherPattern = regexp.MustCompile(`^\s*([a-zA-Z_:][a-zA-Z0-9_:]*)\s*(=~|!~|!=|=)\s*((?s).*?)\s*$`)
+
+// matcherOperators are the operators of a matcher, longest first
+var matcherOperators = []string{"=~", "!~", "!=", "="}
+
+// ValidateMatchers parses the matchers of the routes and inhibition rules of the rendered
+// Alertmanager configuration config with syntax. Nothing is checked if syntax is empty.
+// Returns the errors of all invalid matchers with their path in the configuration, each
+// wrapping ErrInvalidMatcher.
+func ValidateMatchers(config string, syntax openawarenessv1beta1.MatcherSyntax) error {
+	if syntax == "" {
+		return nil
+	}
+	mapping, err := unmarshalMapping(config)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	check := func(path string, value any) {
+		for i, matchers := range toStringList(value) {
+			if err := parseMatchers(matchers, syntax); err != nil {
+				errs = append(errs, fmt.Errorf("%s[%d]: %w", path, i, err))
+			}
+		}
+	}
+	var walk func(path string, route map[string]any)
+	walk = func(path string, route map[string]any) {
+		check(path+".matchers", route["matchers"])
+		for i, child := range asList(route["routes"]) {
+			if childRoute, ok := child.(map[string]any); ok {
+				walk(fmt.Sprintf("%s.routes[%d]", path, i), childRoute)
+			}
+		}
+	}
+	if route, ok := mapping["route"].(map[string]any); ok {
+		walk("route", route)
+	}
+	for i, entry := range asList(mapping["inhibit_rules"]) {
+		if rule, ok := entry.(map[string]any); ok {
+			check(fmt.Sprintf("inhibit_rules[%d].source_matchers", i), rule["source_matchers"])
+			check(fmt.Sprintf("inhibit_rules[%d].target_matchers", i), rule["target_matchers"])
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// parseMatchers parses a comma separated list of matchers, optionally in braces, like an
+// entry of the matchers of a route.
+func parseMatchers(input string, syntax openawarenessv1beta1.MatcherSyntax) error {
+	trimmed := strings.TrimSpace(input)
+	if strings.HasPrefix(trimmed, "{") {
+		if !strings.HasSuffix(trimmed, "}") {
+			return fmt.Errorf("%w %q for syntax %s: missing closing brace", ErrInvalidMatcher, input, syntax)
+		}
+		trimmed = trimmed[1 : len(trimmed)-1]
+	}
+	for _, matcher := range splitMatchers(trimmed) {
+		var err error
+		switch syntax {
+		case openawarenessv1beta1.MatcherSyntaxUTF8:
+			err = parseUTF8Matcher(matcher)
+		default:
+			err = parseClassicMatcher(matcher)
+		}
+		if err != nil {
+			return fmt.Errorf("%w %q for syntax %s: %w", ErrInvalidMatcher, matcher, syntax, err)
+		}
+	}
+	return nil
+}
+
+// splitMatchers splits a list of matchers at the commas outside of double quotes.
+func splitMatchers(input string) []string {
+	var matchers []string
+	start, quoted, escaped := 0, false, false
+	for i, r := range input {
+		switch {
+		case escaped:
+			escaped = false
+		case r == '\\' && quoted:
+			escaped = true
+		case r == '"':
+			quoted = !quoted
+		case r == ',' && !quoted:
+			matchers = append(matchers, input[start:i])
+			start = i + 1
+		}
+	}
+	return append(matchers, input[start:])
+}
+
+// parseClassicMatcher parses a matcher like the classic Alertmanager parser: an unquoted label
+// name and a quoted or unquoted value.
+func parseClassicMatcher(matcher string) error {
+	parts := classicMatcherPattern.FindStringSubmatch(matcher)
+	if parts == nil {
+		return errors.New("expected a label name of letters, digits, underscores and colons and an operator")
+	}
+	value := parts[3]
+	if len(value) >= 2 && strings.HasPrefix(value, `"`) && strings.HasSuffix(value, `"`) {
+		unquoted, err := strconv.Unquote(value)
+		if err != nil {
+			return fmt.Errorf("invalid quoted value: %w", err)
+		}
+		value = unquoted
+	}
+	return checkMatcherValue(parts[2], value)
+}
+
+// parseUTF8Matcher parses a matcher like the UTF-8 strict Alertmanager parser: the label name
+// and the value are quoted or unquoted without reserved characters.
+func parseUTF8Matcher(matcher string) error {
+	rest := strings.TrimLeftFunc(matcher, unicode.IsSpace)
+	name, rest, err := utf8MatcherToken(rest)
+	if err != nil {
+		return fmt.Errorf("label name: %w", err)
+	}
+	if name == "" {
+		return errors.New("missing label name")
+	}
+	rest = strings.TrimLeftFunc(rest, unicode.IsSpace)
+	operator := ""
+	for _, candidate := range matcherOperators {
+		if strings.HasPrefix(rest, candidate) {
+			operator = candidate
+			break
+		}
+	}
+	if operator == "" {
+		return errors.New("expected an operator after the label name")
+	}
+	rest = strings.TrimLeftFunc(rest[len(operator):], unicode.IsSpace)
+	value, rest, err := utf8MatcherToken(rest)
+	if err != nil {
+		return fmt.Errorf("value: %w", err)
+	}
+	if strings.TrimSpace(rest) != "" {
+		return fmt.Errorf("unexpected %q after the value, quote values containing reserved characters",
+			strings.TrimSpace(rest))
+	}
+	return checkMatcherValue(operator, value)
+}
+
+// utf8MatcherToken reads a double quoted or unquoted token from the start of input and
+// returns its unquoted value and the remaining input.
+func utf8MatcherToken(input string) (string, string, error) {
+	if strings.HasPrefix(input, `"`) {
+		quoted, err := strconv.QuotedPrefix(input)
+		if err != nil {
+			return "", "", fmt.Errorf("unterminated or invalid quoted string: %w", err)
+		}
+		unquoted, err := strconv.Unquote(quoted)
+		if err != nil {
+			return "", "", err
+		}
+		return unquoted, input[len(quoted):], nil
+	}
+	end := strings.IndexFunc(input, isReservedMatcherRune)
+	if end < 0 {
+		end = len(input)
+	}
+	return input[:end], input[end:], nil
+}
+
+// isReservedMatcherRune reports whether r must be quoted in the UTF-8 matcher syntax.
+func isReservedMatcherRune(r rune) bool {
+	return unicode.IsSpace(r) || strings.ContainsRune("{}!=~,\\\"'`", r)
+}
+
+// checkMatcherValue checks that the value of a regular expression matcher compiles.
+func checkMatcherValue(operator, value string) error {
+	if operator != "=~" && operator != "!~" {
+		return nil
+	}
+	if _, err := regexp.Compile("^(?:" + value + ")$"); err != nil {
+		return fmt.Errorf("invalid regular expression: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+//nolint:revive // utils is a standard package name for utilities
+package utils
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	openawarenessv1beta1 "github.com/syndlex/openawareness-controller/api/openawareness/v1beta1"
+)
+
+func TestParseMatchers(t *testing.T) {
+	classic, utf8 := openawarenessv1beta1.MatcherSyntaxClassic, openawarenessv1beta1.MatcherSyntaxUTF8
+	tests := []struct {
+		matchers string
+		syntax   openawarenessv1beta1.MatcherSyntax
+		valid    bool
+	}{
+		{matchers: `severity="critical"`, syntax: classic, valid: true},
+		{matchers: `severity="critical"`, syntax: utf8, valid: true},
+		{matchers: `{team=~"a|b", severity!=info}`, syntax: classic, valid: true},
+		{matchers: `{team=~"a|b", severity!=info}`, syntax: utf8, valid: true},
+		{matchers: `service="api, web"`, syntax: utf8, valid: true},
+		{matchers: `"service.name"="api"`, syntax: utf8, valid: true},
+		{matchers: `"service.name"="api"`, syntax: classic},
+		{matchers: `service.name="api"`, syntax: classic},
+		{matchers: `summary=disk full`, syntax: classic, valid: true},
+		{matchers: `summary=disk full`, syntax: utf8},
+		{matchers: `team=~"a("`, syntax: classic},
+		{matchers: `team=~"a("`, syntax: utf8},
+		{matchers: `team="unterminated`, syntax: utf8},
+		{matchers: `{team="a"`, syntax: classic},
+		{matchers: `="a"`, syntax: utf8},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.syntax)+"/"+tt.matchers, func(t *testing.T) {
+			err := parseMatchers(tt.matchers, tt.syntax)
+			if tt.valid && err != nil {
+				t.Errorf("expected valid matchers, got %v", err)
+			}
+			if !tt.valid && !errors.Is(err, ErrInvalidMatcher) {
+				t.Errorf("expected ErrInvalidMatcher, got %v", err)
+			}
+		})
+	}
+}
+
+func TestValidateMatchers(t *testing.T) {
+	config := `
+route:
+  receiver: default
+  matchers: ['"service.name"="api"']
+  routes:
+    - receiver: oncall
+      matchers: ['severity="critical"']
+      routes:
+        - receiver: db
+          matchers: ['summary=disk full']
+inhibit_rules:
+  - source_matchers: ['severity="critical"']
+    target_matchers: ['team=~"a("']
+`
+	if err := ValidateMatchers(config, ""); err != nil {
+		t.Errorf("expected no validation without syntax, got %v", err)
+	}
+
+	err := ValidateMatchers(config, openawarenessv1beta1.MatcherSyntaxClassic)
+	if !errors.Is(err, ErrInvalidMatcher) {
+		t.Fatalf("expected ErrInvalidMatcher, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "route.matchers[0]") ||
+		!strings.Contains(err.Error(), "inhibit_rules[0].target_matchers[0]") ||
+		strings.Contains(err.Error(), "route.routes[0].routes[0]") {
+		t.Errorf("expected the quoted label name and the regular expression to be reported, got %v", err)
+	}
+
+	err = ValidateMatchers(config, openawarenessv1beta1.MatcherSyntaxUTF8)
+	if err == nil || !strings.Contains(err.Error(), "route.routes[0].routes[0].matchers[0]") ||
+		strings.Contains(err.Error(), "route.matchers[0]") {
+		t.Errorf("expected the unquoted value with a space to be reported, got %v", err)
+	}
+}
@@ -0,0 +1,261 @@
+//nolint:revive // utils is a standard package name for utilities
+package utils
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+	k8sClient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	openawarenessv1beta1 "github.com/syndlex/openawareness-controller/api/openawareness/v1beta1"
+)
+
+// ErrInvalidMuteTiming is returned for MimirMuteTimings that cannot be merged into a configuration
+var ErrInvalidMuteTiming = errors.New("invalid time interval")
+
+// ErrDuplicateMuteTiming is returned for MimirMuteTimings whose time interval name is already
+// used by the configuration or by other MimirMuteTimings of the tenant chain
+var ErrDuplicateMuteTiming = errors.New("duplicate time interval")
+
+var (
+	weekdays = []string{"sunday", "monday", "tuesday", "wednesday", "thursday", "friday", "saturday"}
+	months   = []string{
+		"january", "february", "march", "april", "may", "june",
+		"july", "august", "september", "october", "november", "december",
+	}
+)
+
+// ComposedMuteTimings returns the MimirMuteTimings contributed to the tenants of a chain
+// returned by ResolveExtends, in the order they are merged: the time intervals of base tenants
+// first and the time intervals of each tenant sorted by name.
+func ComposedMuteTimings(
+	ctx context.Context,
+	reader k8sClient.Reader,
+	chain []*openawarenessv1beta1.MimirAlertTenant,
+) ([]openawarenessv1beta1.MimirMuteTiming, error) {
+	namespace := chain[len(chain)-1].Namespace
+	timingList := &openawarenessv1beta1.MimirMuteTimingList{}
+	if err := reader.List(ctx, timingList, k8sClient.InNamespace(namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list MimirMuteTimings in %s: %w", namespace, err)
+	}
+
+	position := func(timing openawarenessv1beta1.MimirMuteTiming) int {
+		return slices.IndexFunc(chain, func(tenant *openawarenessv1beta1.MimirAlertTenant) bool {
+			return tenant.Name == timing.Spec.Tenant.Name
+		})
+	}
+	var composed []openawarenessv1beta1.MimirMuteTiming
+	for _, timing := range timingList.Items {
+		if position(timing) >= 0 {
+			composed = append(composed, timing)
+		}
+	}
+	slices.SortFunc(composed, func(a, b openawarenessv1beta1.MimirMuteTiming) int {
+		return cmp.Or(cmp.Compare(position(a), position(b)), strings.Compare(a.Name, b.Name))
+	})
+	return composed, nil
+}
+
+// MergeMuteTimings appends the time intervals of timings to the time_intervals of the rendered
+// Alertmanager configuration config, where routes reference them by name.
+// MimirMuteTimings are rejected and left out if a window is invalid, or their name is already
+// used in time_intervals or mute_time_intervals of the configuration or by MimirMuteTimings
+// merged before them.
+// Returns the merged configuration and the rejection errors by MimirMuteTiming name, which
+// wrap ErrInvalidMuteTiming or ErrDuplicateMuteTiming, or an error wrapping ErrComposition if
+// config is not a YAML mapping.
+func MergeMuteTimings(
+	config string,
+	timings []openawarenessv1beta1.MimirMuteTiming,
+) (string, map[string]error, error) {
+	if len(timings) == 0 {
+		return config, nil, nil
+	}
+	merged, err := unmarshalMapping(config)
+	if err != nil {
+		return "", nil, fmt.Errorf("%w with MimirMuteTimings: %w", ErrComposition, err)
+	}
+
+	// owners tracks the source of each time interval name, empty for the configuration itself
+	owners := map[string]string{}
+	for _, key := range []string{"time_intervals", "mute_time_intervals"} {
+		for _, entry := range asList(merged[key]) {
+			if interval, ok := entry.(map[string]any); ok {
+				if name, ok := interval["name"].(string); ok {
+					owners[name] = ""
+				}
+			}
+		}
+	}
+
+	intervals := asList(merged["time_intervals"])
+	rejected := map[string]error{}
+	for i := range timings {
+		timing := &timings[i]
+		name := timing.IntervalName()
+		if owner, ok := owners[name]; ok {
+			if owner == "" {
+				rejected[timing.Name] = fmt.Errorf("%w: %s is already defined by the configuration",
+					ErrDuplicateMuteTiming, name)
+			} else {
+				rejected[timing.Name] = fmt.Errorf("%w: %s is already defined by MimirMuteTiming %s",
+					ErrDuplicateMuteTiming, name, owner)
+			}
+			continue
+		}
+		windows, err := renderTimeIntervals(timing.Spec.TimeIntervals)
+		if err != nil {
+			rejected[timing.Name] = err
+			continue
+		}
+		owners[name] = timing.Name
+		intervals = append(intervals, map[string]any{"name": name, "time_intervals": windows})
+	}
+
+	if len(intervals) > 0 {
+		merged["time_intervals"] = intervals
+	}
+	rendered, err := yaml.Marshal(merged)
+	if err != nil {
+		return "", nil, err
+	}
+	return string(rendered), rejected, nil
+}
+
+// renderTimeIntervals validates windows and renders them in the Alertmanager configuration format.
+func renderTimeIntervals(windows []openawarenessv1beta1.TimeInterval) ([]any, error) {
+	if len(windows) == 0 {
+		return nil, fmt.Errorf("%w: no time intervals", ErrInvalidMuteTiming)
+	}
+	rendered := make([]any, 0, len(windows))
+	for _, window := range windows {
+		entry := map[string]any{}
+		if len(window.Times) > 0 {
+			times := make([]any, 0, len(window.Times))
+			for _, timeRange := range window.Times {
+				if err := validateTimeRange(timeRange); err != nil {
+					return nil, err
+				}
+				times = append(times, map[string]any{"start_time": timeRange.StartTime, "end_time": timeRange.EndTime})
+			}
+			entry["times"] = times
+		}
+		fields := []struct {
+			key      string
+			values   []string
+			validate func(string) error
+		}{
+			{"weekdays", window.Weekdays, validateRange("weekday", namedBound(weekdays, 0), false)},
+			{"days_of_month", window.DaysOfMonth, validateRange("day of month", numericBound(-31, 31), true)},
+			{"months", window.Months, validateRange("month", namedBound(months, 1), false)},
+			{"years", window.Years, validateRange("year", numericBound(1, 9999), false)},
+		}
+		for _, field := range fields {
+			if len(field.values) == 0 {
+				continue
+			}
+			values := make([]any, 0, len(field.values))
+			for _, value := range field.values {
+				if err := field.validate(value); err != nil {
+					return nil, err
+				}
+				values = append(values, value)
+			}
+			entry[field.key] = values
+		}
+		if window.Location != "" {
+			if _, err := time.LoadLocation(window.Location); err != nil {
+				return nil, fmt.Errorf("%w: unknown location %s", ErrInvalidMuteTiming, window.Location)
+			}
+			entry["location"] = window.Location
+		}
+		rendered = append(rendered, entry)
+	}
+	return rendered, nil
+}
+
+// validateTimeRange checks that timeRange is an HH:MM range ending after it starts, up to 24:00.
+func validateTimeRange(timeRange openawarenessv1beta1.TimeRange) error {
+	start, err := minuteOfDay(timeRange.StartTime)
+	if err != nil {
+		return err
+	}
+	end, err := minuteOfDay(timeRange.EndTime)
+	if err != nil {
+		return err
+	}
+	if start >= end {
+		return fmt.Errorf("%w: time range %s-%s must end after it starts",
+			ErrInvalidMuteTiming, timeRange.StartTime, timeRange.EndTime)
+	}
+	return nil
+}
+
+// minuteOfDay parses an HH:MM time of day, up to 24:00.
+func minuteOfDay(value string) (int, error) {
+	hours, minutes, ok := strings.Cut(value, ":")
+	hour, hourErr := strconv.Atoi(hours)
+	minute, minuteErr := strconv.Atoi(minutes)
+	if !ok || len(hours) != 2 || len(minutes) != 2 || hourErr != nil || minuteErr != nil ||
+		hour < 0 || minute < 0 || minute > 59 || hour*60+minute > 24*60 {
+		return 0, fmt.Errorf("%w: invalid time %q, must be HH:MM up to 24:00", ErrInvalidMuteTiming, value)
+	}
+	return hour*60 + minute, nil
+}
+
+// namedBound parses a value of names, case-insensitive, or of its number if offset is not zero:
+// names[0] is offset.
+func namedBound(names []string, offset int) func(string) (int, bool) {
+	return func(value string) (int, bool) {
+		if index := slices.Index(names, strings.ToLower(value)); index >= 0 {
+			return index + offset, true
+		}
+		number, err := strconv.Atoi(value)
+		if offset == 0 || err != nil || number < offset || number >= len(names)+offset {
+			return 0, false
+		}
+		return number, true
+	}
+}
+
+// numericBound parses a number between minimum and maximum, excluding zero.
+func numericBound(minimum, maximum int) func(string) (int, bool) {
+	return func(value string) (int, bool) {
+		number, err := strconv.Atoi(value)
+		if err != nil || number == 0 || number < minimum || number > maximum {
+			return 0, false
+		}
+		return number, true
+	}
+}
+
+// validateRange returns a validation of a single value or an inclusive "start:end" range of
+// values parsed by bound. Ranges must not end before they start, unless signed, where a range
+// from a positive to a negative value, e.g. "1:-1", spans to the end counted backwards.
+func validateRange(kind string, bound func(string) (int, bool), signed bool) func(string) error {
+	return func(value string) error {
+		start, end, isRange := strings.Cut(value, ":")
+		first, ok := bound(start)
+		if !ok {
+			return fmt.Errorf("%w: invalid %s %q", ErrInvalidMuteTiming, kind, value)
+		}
+		if !isRange {
+			return nil
+		}
+		last, ok := bound(end)
+		if !ok {
+			return fmt.Errorf("%w: invalid %s %q", ErrInvalidMuteTiming, kind, value)
+		}
+		if last < first && !(signed && first > 0 && last < 0) {
+			return fmt.Errorf("%w: %s range %q ends before it starts", ErrInvalidMuteTiming, kind, value)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,146 @@
+//nolint:revive // utils is a standard package name for utilities
+package utils
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	openawarenessv1beta1 "github.com/syndlex/openawareness-controller/api/openawareness/v1beta1"
+)
+
+func muteTiming(name, tenant, intervalName string, windows ...openawarenessv1beta1.TimeInterval) openawarenessv1beta1.MimirMuteTiming {
+	return openawarenessv1beta1.MimirMuteTiming{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "team"},
+		Spec: openawarenessv1beta1.MimirMuteTimingSpec{
+			Tenant:        openawarenessv1beta1.TenantReference{Name: tenant},
+			Name:          intervalName,
+			TimeIntervals: windows,
+		},
+	}
+}
+
+func TestComposedMuteTimings(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := openawarenessv1beta1.AddToScheme(scheme); err != nil {
+		t.Fatalf("adding scheme: %v", err)
+	}
+	timings := []openawarenessv1beta1.MimirMuteTiming{
+		muteTiming("a-weekend", "oncall", ""),
+		muteTiming("z-maintenance", "root", ""),
+		muteTiming("b-nights", "oncall", ""),
+		muteTiming("search", "other", ""),
+	}
+	builder := fake.NewClientBuilder().WithScheme(scheme)
+	for i := range timings {
+		builder = builder.WithObjects(&timings[i])
+	}
+	chain := []*openawarenessv1beta1.MimirAlertTenant{
+		extendingTenant("root", "", ""),
+		extendingTenant("oncall", "root", ""),
+	}
+
+	composed, err := ComposedMuteTimings(context.Background(), builder.Build(), chain)
+	if err != nil {
+		t.Fatalf("ComposedMuteTimings() error = %v", err)
+	}
+	var names []string
+	for _, entry := range composed {
+		names = append(names, entry.Name)
+	}
+	if !reflect.DeepEqual(names, []string{"z-maintenance", "a-weekend", "b-nights"}) {
+		t.Errorf("expected the time intervals of base tenants first, sorted by name, got %v", names)
+	}
+}
+
+func TestMergeMuteTimings(t *testing.T) {
+	config := `
+route:
+  receiver: default
+receivers:
+  - name: default
+mute_time_intervals:
+  - name: legacy
+`
+	weekend := openawarenessv1beta1.TimeInterval{Weekdays: []string{"Saturday", "sunday"}, Location: "Europe/Berlin"}
+	nights := openawarenessv1beta1.TimeInterval{
+		Times:       []openawarenessv1beta1.TimeRange{{StartTime: "22:00", EndTime: "24:00"}},
+		DaysOfMonth: []string{"1:-1"},
+		Months:      []string{"december", "1:3"},
+		Years:       []string{"2026"},
+	}
+	timings := []openawarenessv1beta1.MimirMuteTiming{
+		muteTiming("weekend", "root", "", weekend),
+		muteTiming("nights", "oncall", "off-hours", nights),
+		muteTiming("legacy", "oncall", "", weekend),
+		muteTiming("duplicate", "oncall", "off-hours", weekend),
+		muteTiming("reversed", "oncall", "", openawarenessv1beta1.TimeInterval{
+			Times: []openawarenessv1beta1.TimeRange{{StartTime: "18:00", EndTime: "09:00"}},
+		}),
+		muteTiming("weekday", "oncall", "", openawarenessv1beta1.TimeInterval{Weekdays: []string{"funday"}}),
+		muteTiming("location", "oncall", "", openawarenessv1beta1.TimeInterval{Location: "Moon/Base"}),
+	}
+
+	merged, rejected, err := MergeMuteTimings(config, timings)
+	if err != nil {
+		t.Fatalf("MergeMuteTimings() error = %v", err)
+	}
+	want := `
+route:
+  receiver: default
+receivers:
+  - name: default
+mute_time_intervals:
+  - name: legacy
+time_intervals:
+  - name: weekend
+    time_intervals:
+      - weekdays: ["Saturday", "sunday"]
+        location: Europe/Berlin
+  - name: off-hours
+    time_intervals:
+      - times:
+          - start_time: "22:00"
+            end_time: "24:00"
+        days_of_month: ["1:-1"]
+        months: ["december", "1:3"]
+        years: ["2026"]
+`
+	var got, expected map[string]any
+	if err := yaml.Unmarshal([]byte(merged), &got); err != nil {
+		t.Fatalf("merged configuration is invalid: %v", err)
+	}
+	if err := yaml.Unmarshal([]byte(want), &expected); err != nil {
+		t.Fatalf("expected configuration is invalid: %v", err)
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("unexpected merged configuration:\n%s", merged)
+	}
+
+	for _, name := range []string{"legacy", "duplicate"} {
+		if !errors.Is(rejected[name], ErrDuplicateMuteTiming) {
+			t.Errorf("expected a duplicate time interval error for %s, got %v", name, rejected[name])
+		}
+	}
+	for _, name := range []string{"reversed", "weekday", "location"} {
+		if !errors.Is(rejected[name], ErrInvalidMuteTiming) {
+			t.Errorf("expected an invalid time interval error for %s, got %v", name, rejected[name])
+		}
+	}
+	if len(rejected) != 5 {
+		t.Errorf("expected 5 rejected time intervals, got %v", rejected)
+	}
+
+	if unchanged, _, err := MergeMuteTimings("- a list", nil); err != nil || unchanged != "- a list" {
+		t.Errorf("expected the configuration unchanged without time intervals, got %q, %v", unchanged, err)
+	}
+	if _, _, err := MergeMuteTimings("- a list", timings); !errors.Is(err, ErrComposition) {
+		t.Errorf("expected a composition error, got %v", err)
+	}
+}
@@ -0,0 +1,92 @@
+//nolint:revive // utils is a standard package name for utilities
+package utils
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/prometheus/prometheus/model/rulefmt"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// OwnerReference returns the "<namespace>/<name>" provenance value of a Kubernetes object.
+// It is used as the value of OwnerLabel and in the managed-by header of Alertmanager configurations.
+func OwnerReference(obj metav1.Object) string {
+	return obj.GetNamespace() + "/" + obj.GetName()
+}
+
+// SetOwnerLabel adds the OwnerLabel with the given owner to every rule of the given groups.
+// Label maps are copied so the source objects the groups were converted from are not modified.
+func SetOwnerLabel(groups []rulefmt.RuleGroup, owner string) {
+	for i := range groups {
+		for j := range groups[i].Rules {
+			labels := make(map[string]string, len(groups[i].Rules[j].Labels)+1)
+			for k, v := range groups[i].Rules[j].Labels {
+				labels[k] = v
+			}
+			labels[OwnerLabel] = owner
+			groups[i].Rules[j].Labels = labels
+		}
+	}
+}
+
+// InstanceIdentity returns the identity of an operator installation as "<cluster>/<installation>",
+// or the one that is set, so installations pushing to the same Mimir can be told apart.
+// Empty if neither is set.
+func InstanceIdentity(cluster, installation string) string {
+	if cluster == "" || installation == "" {
+		return cluster + installation
+	}
+	return cluster + "/" + installation
+}
+
+// SetInstanceLabel adds the InstanceLabel with the identity of the installation to every rule of
+// the given groups, nothing if identity is empty. Label maps are copied like in SetOwnerLabel.
+func SetInstanceLabel(groups []rulefmt.RuleGroup, identity string) {
+	if identity == "" {
+		return
+	}
+	for i := range groups {
+		for j := range groups[i].Rules {
+			labels := make(map[string]string, len(groups[i].Rules[j].Labels)+1)
+			for k, v := range groups[i].Rules[j].Labels {
+				labels[k] = v
+			}
+			labels[InstanceLabel] = identity
+			groups[i].Rules[j].Labels = labels
+		}
+	}
+}
+
+// InstanceOf returns the InstanceLabel of the first rule of the group carrying one, empty if
+// the group was pushed by an installation without identity or by someone else.
+func InstanceOf(group rulefmt.RuleGroup) string {
+	for _, rule := range group.Rules {
+		if instance := rule.Labels[InstanceLabel]; instance != "" {
+			return instance
+		}
+	}
+	return ""
+}
+
+// AddManagedByHeader prefixes an Alertmanager configuration with a comment naming the
+// resource it was generated from, followed by the identity of the installation if set.
+// An existing managed-by header is replaced.
+func AddManagedByHeader(config string, kind string, obj metav1.Object, identity string) string {
+	header := fmt.Sprintf("%s %s %s", ManagedByHeader, kind, OwnerReference(obj))
+	if identity != "" {
+		header += " instance=" + identity
+	}
+	return header + "\n" + StripManagedByHeader(config)
+}
+
+// StripManagedByHeader removes a leading managed-by header line from an Alertmanager configuration.
+func StripManagedByHeader(config string) string {
+	if !strings.HasPrefix(config, ManagedByHeader) {
+		return config
+	}
+	if idx := strings.Index(config, "\n"); idx >= 0 {
+		return config[idx+1:]
+	}
+	return ""
+}
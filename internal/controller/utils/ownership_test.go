@@ -0,0 +1,80 @@
+//nolint:revive // utils is a standard package name for utilities
+package utils
+
+import (
+	"testing"
+
+	"github.com/prometheus/prometheus/model/rulefmt"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSetOwnerLabel(t *testing.T) {
+	source := map[string]string{"severity": "critical"}
+	groups := []rulefmt.RuleGroup{{
+		Name: "group",
+		Rules: []rulefmt.Rule{
+			{Alert: "WithLabels", Labels: source},
+			{Record: "without:labels"},
+		},
+	}}
+
+	SetOwnerLabel(groups, "default/my-rules")
+
+	for _, rule := range groups[0].Rules {
+		if rule.Labels[OwnerLabel] != "default/my-rules" {
+			t.Errorf("expected owner label on rule %s%s, got %v", rule.Alert, rule.Record, rule.Labels)
+		}
+	}
+	if groups[0].Rules[0].Labels["severity"] != "critical" {
+		t.Error("expected existing labels to be preserved")
+	}
+	if _, exists := source[OwnerLabel]; exists {
+		t.Error("expected source label map not to be modified")
+	}
+}
+
+func TestAddManagedByHeader(t *testing.T) {
+	obj := &metav1.ObjectMeta{Name: "alerts", Namespace: "team"}
+	config := "route:\n  receiver: default\n"
+
+	withHeader := AddManagedByHeader(config, "MimirAlertTenant", obj, "")
+	expected := ManagedByHeader + " MimirAlertTenant team/alerts\n" + config
+	if withHeader != expected {
+		t.Errorf("AddManagedByHeader() = %q, want %q", withHeader, expected)
+	}
+
+	if again := AddManagedByHeader(withHeader, "MimirAlertTenant", obj, ""); again != expected {
+		t.Errorf("expected header to be replaced rather than duplicated, got %q", again)
+	}
+	if identified := AddManagedByHeader(withHeader, "MimirAlertTenant", obj, "eu-1/blue"); identified !=
+		ManagedByHeader+" MimirAlertTenant team/alerts instance=eu-1/blue\n"+config {
+		t.Errorf("expected the header to name the instance, got %q", identified)
+	}
+
+	if stripped := StripManagedByHeader(withHeader); stripped != config {
+		t.Errorf("StripManagedByHeader() = %q, want %q", stripped, config)
+	}
+}
+
+func TestInstanceIdentity(t *testing.T) {
+	for _, tc := range []struct{ cluster, installation, want string }{
+		{"eu-1", "blue", "eu-1/blue"},
+		{"eu-1", "", "eu-1"},
+		{"", "blue", "blue"},
+		{"", "", ""},
+	} {
+		if got := InstanceIdentity(tc.cluster, tc.installation); got != tc.want {
+			t.Errorf("InstanceIdentity(%q, %q) = %q, want %q", tc.cluster, tc.installation, got, tc.want)
+		}
+	}
+
+	groups := []rulefmt.RuleGroup{{Name: "group", Rules: []rulefmt.Rule{{Record: "a"}, {Record: "b"}}}}
+	SetInstanceLabel(groups, "")
+	if InstanceOf(groups[0]) != "" {
+		t.Errorf("expected no instance label without identity, got %v", groups[0].Rules[0].Labels)
+	}
+	SetInstanceLabel(groups, "eu-1/blue")
+	if InstanceOf(groups[0]) != "eu-1/blue" || groups[0].Rules[1].Labels[InstanceLabel] != "eu-1/blue" {
+		t.Errorf("expected the instance label on every rule, got %+v", groups[0].Rules)
+	}
+}
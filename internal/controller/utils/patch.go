@@ -0,0 +1,61 @@
+//nolint:revive // utils is a standard package name for utilities
+package utils
+
+import (
+	"context"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/util/retry"
+	k8sClient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// PatchStatus writes the status changes of obj since original, the object as read at the start
+// of the reconciliation, as merge patch of the status subresource.
+// The patch carries no resourceVersion, so it does not conflict with other writers of the
+// object; the controller owns the status, so the status fields it changes are never stale.
+func PatchStatus(ctx context.Context, client k8sClient.Client, obj, original k8sClient.Object) error {
+	base, ok := original.DeepCopyObject().(k8sClient.Object)
+	if !ok {
+		return client.Status().Patch(ctx, obj, k8sClient.MergeFrom(original))
+	}
+	// Metadata written since the original was read, e.g. finalizers, is not part of the patch
+	base.SetResourceVersion(obj.GetResourceVersion())
+	base.SetFinalizers(obj.GetFinalizers())
+	return client.Status().Patch(ctx, obj, k8sClient.MergeFrom(base))
+}
+
+// AddFinalizer adds finalizer to obj unless present, see PatchFinalizers.
+func AddFinalizer(ctx context.Context, client k8sClient.Client, obj k8sClient.Object, finalizer string) error {
+	return PatchFinalizers(ctx, client, obj, func() bool {
+		return controllerutil.AddFinalizer(obj, finalizer)
+	})
+}
+
+// RemoveFinalizer removes finalizer from obj if present, see PatchFinalizers.
+func RemoveFinalizer(ctx context.Context, client k8sClient.Client, obj k8sClient.Object, finalizer string) error {
+	return PatchFinalizers(ctx, client, obj, func() bool {
+		return controllerutil.RemoveFinalizer(obj, finalizer)
+	})
+}
+
+// PatchFinalizers applies mutate, which changes the finalizers of obj and reports whether
+// they changed, and writes the change as merge patch guarded by the resourceVersion of obj.
+// Merge patches replace the whole finalizer list, so on conflicts with other writers obj is
+// read again and mutate is retried on the latest version instead of dropping their finalizers.
+// Other changes to obj not yet written are lost if it is read again.
+func PatchFinalizers(ctx context.Context, client k8sClient.Client, obj k8sClient.Object, mutate func() bool) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		base, ok := obj.DeepCopyObject().(k8sClient.Object)
+		if !ok || !mutate() {
+			return nil
+		}
+		err := client.Patch(ctx, obj, k8sClient.MergeFromWithOptions(base, k8sClient.MergeFromWithOptimisticLock{}))
+		if apierrors.IsConflict(err) {
+			if getErr := client.Get(ctx, k8sClient.ObjectKeyFromObject(obj), obj); getErr != nil {
+				return getErr
+			}
+		}
+		return err
+	})
+}
@@ -0,0 +1,74 @@
+//nolint:revive // utils is a standard package name for utilities
+package utils
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	openawarenessv1beta1 "github.com/syndlex/openawareness-controller/api/openawareness/v1beta1"
+)
+
+func TestPatchStatusAndFinalizers(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := openawarenessv1beta1.AddToScheme(scheme); err != nil {
+		t.Fatalf("adding scheme: %v", err)
+	}
+	stored := &openawarenessv1beta1.MimirAlertTenant{
+		ObjectMeta: metav1.ObjectMeta{Name: "tenant", Namespace: "team"},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(stored).WithStatusSubresource(stored).Build()
+	ctx := context.Background()
+	key := client.ObjectKeyFromObject(stored)
+
+	tenant := &openawarenessv1beta1.MimirAlertTenant{}
+	if err := c.Get(ctx, key, tenant); err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	original := tenant.DeepCopy()
+
+	// Another writer changes the tenant after it was read
+	other := &openawarenessv1beta1.MimirAlertTenant{}
+	if err := c.Get(ctx, key, other); err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	controllerutil.AddFinalizer(other, "other.io/finalizer")
+	if err := c.Update(ctx, other); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+
+	if err := AddFinalizer(ctx, c, tenant, FinalizerAnnotation); err != nil {
+		t.Fatalf("AddFinalizer() error = %v", err)
+	}
+	tenant.SetSyncedCondition()
+	if err := PatchStatus(ctx, c, tenant, original); err != nil {
+		t.Fatalf("PatchStatus() error = %v", err)
+	}
+
+	latest := &openawarenessv1beta1.MimirAlertTenant{}
+	if err := c.Get(ctx, key, latest); err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if !controllerutil.ContainsFinalizer(latest, "other.io/finalizer") ||
+		!controllerutil.ContainsFinalizer(latest, FinalizerAnnotation) {
+		t.Errorf("expected both finalizers, got %v", latest.Finalizers)
+	}
+	if latest.Status.SyncStatus != openawarenessv1beta1.SyncStatusSynced {
+		t.Errorf("expected the status to be patched, got %q", latest.Status.SyncStatus)
+	}
+
+	if err := RemoveFinalizer(ctx, c, latest, FinalizerAnnotation); err != nil {
+		t.Fatalf("RemoveFinalizer() error = %v", err)
+	}
+	if err := c.Get(ctx, key, latest); err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if len(latest.Finalizers) != 1 || latest.Finalizers[0] != "other.io/finalizer" {
+		t.Errorf("expected only the other finalizer, got %v", latest.Finalizers)
+	}
+}
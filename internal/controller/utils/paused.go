@@ -0,0 +1,64 @@
+//nolint:revive // utils is a standard package name for utilities
+package utils
+
+import (
+	"strconv"
+
+	openawarenessv1beta1 "github.com/syndlex/openawareness-controller/api/openawareness/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// IsPaused reports whether the object carries the paused annotation with a true value.
+// Paused resources are still validated and have their status updated, but the
+// controllers do not push, update or delete anything in Mimir for them.
+func IsPaused(obj metav1.Object) bool {
+	value, exists := obj.GetAnnotations()[PausedAnnotation]
+	if !exists {
+		return false
+	}
+	paused, err := strconv.ParseBool(value)
+	return err == nil && paused
+}
+
+// SetPausedCondition records the paused state in the given conditions list.
+// A Paused=True condition is always written for paused resources. For resources that
+// are not paused, the condition is only flipped to False if it was set before, so
+// resources that were never paused do not carry a Paused condition at all.
+func SetPausedCondition(conditions *[]metav1.Condition, paused bool, generation int64) {
+	if conditions == nil {
+		return
+	}
+
+	if !paused {
+		existing := FindCondition(*conditions, openawarenessv1beta1.ConditionTypePaused)
+		if existing == nil || existing.Status == metav1.ConditionFalse {
+			return
+		}
+		SetCondition(conditions, metav1.Condition{
+			Type:               openawarenessv1beta1.ConditionTypePaused,
+			Status:             metav1.ConditionFalse,
+			ObservedGeneration: generation,
+			Reason:             openawarenessv1beta1.ReasonResumed,
+			Message:            "Remote synchronization resumed",
+		})
+		return
+	}
+
+	SetCondition(conditions, metav1.Condition{
+		Type:               openawarenessv1beta1.ConditionTypePaused,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: generation,
+		Reason:             openawarenessv1beta1.ReasonPaused,
+		Message:            "Remote changes are paused via the " + PausedAnnotation + " annotation",
+	})
+}
+
+// FindCondition returns a pointer to the condition with the given type, or nil if it is not present.
+func FindCondition(conditions []metav1.Condition, conditionType string) *metav1.Condition {
+	for i := range conditions {
+		if conditions[i].Type == conditionType {
+			return &conditions[i]
+		}
+	}
+	return nil
+}
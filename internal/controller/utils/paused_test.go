@@ -0,0 +1,85 @@
+//nolint:revive // utils is a standard package name for utilities
+package utils
+
+import (
+	"testing"
+
+	openawarenessv1beta1 "github.com/syndlex/openawareness-controller/api/openawareness/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestIsPaused(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		expected    bool
+	}{
+		{
+			name:        "no annotations",
+			annotations: nil,
+			expected:    false,
+		},
+		{
+			name:        "paused true",
+			annotations: map[string]string{PausedAnnotation: "true"},
+			expected:    true,
+		},
+		{
+			name:        "paused false",
+			annotations: map[string]string{PausedAnnotation: "false"},
+			expected:    false,
+		},
+		{
+			name:        "invalid value is treated as not paused",
+			annotations: map[string]string{PausedAnnotation: "yes please"},
+			expected:    false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			obj := &metav1.ObjectMeta{Annotations: tt.annotations}
+			if got := IsPaused(obj); got != tt.expected {
+				t.Errorf("IsPaused() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSetPausedCondition(t *testing.T) {
+	t.Run("not paused and never paused adds no condition", func(t *testing.T) {
+		var conditions []metav1.Condition
+		SetPausedCondition(&conditions, false, 1)
+		if len(conditions) != 0 {
+			t.Errorf("expected no conditions, got %d", len(conditions))
+		}
+	})
+
+	t.Run("paused adds true condition", func(t *testing.T) {
+		var conditions []metav1.Condition
+		SetPausedCondition(&conditions, true, 1)
+		condition := FindCondition(conditions, openawarenessv1beta1.ConditionTypePaused)
+		if condition == nil {
+			t.Fatal("expected Paused condition to be set")
+		}
+		if condition.Status != metav1.ConditionTrue || condition.Reason != openawarenessv1beta1.ReasonPaused {
+			t.Errorf("unexpected condition %+v", condition)
+		}
+	})
+
+	t.Run("resume flips condition to false", func(t *testing.T) {
+		var conditions []metav1.Condition
+		SetPausedCondition(&conditions, true, 1)
+		SetPausedCondition(&conditions, false, 2)
+		condition := FindCondition(conditions, openawarenessv1beta1.ConditionTypePaused)
+		if condition == nil {
+			t.Fatal("expected Paused condition to be kept")
+		}
+		if condition.Status != metav1.ConditionFalse || condition.Reason != openawarenessv1beta1.ReasonResumed {
+			t.Errorf("unexpected condition %+v", condition)
+		}
+		if condition.ObservedGeneration != 2 {
+			t.Errorf("ObservedGeneration = %d, want 2", condition.ObservedGeneration)
+		}
+	})
+}
@@ -0,0 +1,118 @@
+//nolint:revive // utils is a standard package name for utilities
+package utils
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/utils/ptr"
+	k8sClient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/priorityqueue"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// Sync priorities of the PriorityAnnotation values. Resources without or with an unknown
+// value have PriorityNormal.
+const (
+	PriorityLow      = 0
+	PriorityNormal   = 10
+	PriorityHigh     = 20
+	PriorityCritical = 30
+	// PriorityInterrupted is the priority of resources marked with the RequeueOnStartAnnotation,
+	// above all PriorityAnnotation values
+	PriorityInterrupted = 40
+)
+
+// SyncPriority returns the sync priority of obj set by its PriorityAnnotation.
+func SyncPriority(obj k8sClient.Object) int {
+	switch obj.GetAnnotations()[PriorityAnnotation] {
+	case "critical":
+		return PriorityCritical
+	case "high":
+		return PriorityHigh
+	case "low":
+		return PriorityLow
+	default:
+		return PriorityNormal
+	}
+}
+
+// EnqueueByPriority returns an event handler enqueueing the object of every event like
+// handler.EnqueueRequestForObject, ordered by SyncPriority when the controller uses a priority
+// queue. Objects of the initial list and unchanged resyncs, e.g. all resources after a
+// controller start, are queued below changed objects but still in priority order, so critical
+// resources are synced first. Resources whose sync was interrupted by a shutdown are queued
+// before all others, see RequeueOnStartAnnotation.
+func EnqueueByPriority[T k8sClient.Object]() handler.TypedEventHandler[T, reconcile.Request] {
+	return priorityEnqueuer[T]{}
+}
+
+type priorityEnqueuer[T k8sClient.Object] struct{}
+
+// Create implements handler.TypedEventHandler.
+func (priorityEnqueuer[T]) Create(
+	_ context.Context,
+	evt event.TypedCreateEvent[T],
+	queue workqueue.TypedRateLimitingInterface[reconcile.Request],
+) {
+	enqueueWithPriority(queue, evt.Object, evt.IsInInitialList)
+}
+
+// Update implements handler.TypedEventHandler.
+func (priorityEnqueuer[T]) Update(
+	_ context.Context,
+	evt event.TypedUpdateEvent[T],
+	queue workqueue.TypedRateLimitingInterface[reconcile.Request],
+) {
+	unchanged := evt.ObjectOld.GetResourceVersion() == evt.ObjectNew.GetResourceVersion()
+	enqueueWithPriority(queue, evt.ObjectNew, unchanged)
+}
+
+// Delete implements handler.TypedEventHandler.
+func (priorityEnqueuer[T]) Delete(
+	_ context.Context,
+	evt event.TypedDeleteEvent[T],
+	queue workqueue.TypedRateLimitingInterface[reconcile.Request],
+) {
+	enqueueWithPriority(queue, evt.Object, false)
+}
+
+// Generic implements handler.TypedEventHandler.
+func (priorityEnqueuer[T]) Generic(
+	_ context.Context,
+	evt event.TypedGenericEvent[T],
+	queue workqueue.TypedRateLimitingInterface[reconcile.Request],
+) {
+	enqueueWithPriority(queue, evt.Object, false)
+}
+
+// enqueueWithPriority adds the request of obj with its sync priority, lowered by
+// handler.LowPriority for unchanged objects, or with PriorityInterrupted for objects marked
+// with the RequeueOnStartAnnotation. Other queues than priority queues get a plain Add.
+func enqueueWithPriority(
+	queue workqueue.TypedRateLimitingInterface[reconcile.Request],
+	obj k8sClient.Object,
+	unchanged bool,
+) {
+	request := reconcile.Request{NamespacedName: types.NamespacedName{
+		Namespace: obj.GetNamespace(),
+		Name:      obj.GetName(),
+	}}
+	priorityQueue, ok := queue.(priorityqueue.PriorityQueue[reconcile.Request])
+	if !ok {
+		queue.Add(request)
+		return
+	}
+
+	priority := SyncPriority(obj)
+	switch {
+	case RequeueOnStart(obj):
+		priority = PriorityInterrupted
+	case unchanged:
+		priority += handler.LowPriority
+	}
+	priorityQueue.AddWithOpts(priorityqueue.AddOpts{Priority: ptr.To(priority)}, request)
+}
@@ -0,0 +1,62 @@
+//nolint:revive // utils is a standard package name for utilities
+package utils
+
+import (
+	"context"
+	"testing"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/controller/priorityqueue"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestEnqueueByPriority(t *testing.T) {
+	rule := func(name, priority string) *monitoringv1.PrometheusRule {
+		rule := &monitoringv1.PrometheusRule{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "team", ResourceVersion: "1"}}
+		if priority != "" {
+			rule.Annotations = map[string]string{PriorityAnnotation: priority}
+		}
+		return rule
+	}
+	// A rule whose sync was interrupted by the shutdown before the controller start
+	interrupted := rule("interrupted", "low")
+	interrupted.Annotations[RequeueOnStartAnnotation] = "true"
+	queue := priorityqueue.New[reconcile.Request]("test")
+	t.Cleanup(queue.ShutDown)
+	enqueuer := EnqueueByPriority[*monitoringv1.PrometheusRule]()
+	ctx := context.Background()
+
+	// The initial list after a controller start, in arbitrary order
+	for _, obj := range []*monitoringv1.PrometheusRule{
+		rule("recording", "low"), rule("unset", ""), rule("alerts", "critical"), rule("unknown", "urgent"), rule("slo", "high"),
+		interrupted,
+	} {
+		enqueuer.Create(ctx, event.TypedCreateEvent[*monitoringv1.PrometheusRule]{Object: obj, IsInInitialList: true}, queue)
+	}
+	// A change made while the initial list is synced
+	changed := rule("changed", "low")
+	changed.ResourceVersion = "2"
+	enqueuer.Update(ctx, event.TypedUpdateEvent[*monitoringv1.PrometheusRule]{ObjectOld: rule("changed", "low"), ObjectNew: changed}, queue)
+
+	// Items of the same priority are returned in insertion order
+	expected := []string{"interrupted", "changed", "alerts", "slo", "unset", "unknown", "recording"}
+	for i, name := range expected {
+		request, priority, _ := queue.GetWithPriority()
+		queue.Done(request)
+		if request.Name != name {
+			t.Errorf("item %d = %s (priority %d), want %s", i, request.Name, priority, name)
+		}
+	}
+}
+
+func TestSyncPriority(t *testing.T) {
+	tests := map[string]int{"critical": PriorityCritical, "high": PriorityHigh, "": PriorityNormal, "low": PriorityLow, "other": PriorityNormal}
+	for value, expected := range tests {
+		obj := &monitoringv1.PrometheusRule{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{PriorityAnnotation: value}}}
+		if got := SyncPriority(obj); got != expected {
+			t.Errorf("SyncPriority(%q) = %d, want %d", value, got, expected)
+		}
+	}
+}
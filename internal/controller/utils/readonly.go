@@ -0,0 +1,46 @@
+//nolint:revive // utils is a standard package name for utilities
+package utils
+
+import (
+	"errors"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	openawarenessv1beta1 "github.com/syndlex/openawareness-controller/api/openawareness/v1beta1"
+)
+
+// SetWouldSyncCondition records the outcome of a read-only reconciliation in conditions: True
+// with reason WouldSync and the changes a push would make, see SyncOutcome.Diff, or False with
+// reason WouldFail and the error of the stage that failed.
+func SetWouldSyncCondition(conditions *[]metav1.Condition, generation int64, stage SyncStage, diff string, err error) {
+	condition := metav1.Condition{
+		Type:               openawarenessv1beta1.ConditionTypeWouldSync,
+		Status:             metav1.ConditionTrue,
+		Reason:             openawarenessv1beta1.ReasonWouldSync,
+		Message:            "Mimir is in sync, nothing would be pushed",
+		ObservedGeneration: generation,
+	}
+	switch {
+	case err != nil:
+		condition.Status, condition.Reason = metav1.ConditionFalse, openawarenessv1beta1.ReasonWouldFail
+		condition.Message = fmt.Sprintf("%s would fail: %s", stage, StatusMessage(err))
+	case diff != "":
+		condition.Message = "A sync would change Mimir:\n" + diff
+	}
+	SetCondition(conditions, condition)
+}
+
+// ReadOnlyResult returns the result of a read-only reconciliation that failed with err in stage.
+// Failures to resolve the client or read the remote system are returned for retry, the others
+// wait for changes of the resource, like refused tenants.
+func ReadOnlyResult(stage SyncStage, err error) (ctrl.Result, error) {
+	if err == nil || errors.Is(err, ErrTenantNotAllowed) {
+		return ctrl.Result{}, nil
+	}
+	if stage == SyncStageResolve || stage == SyncStageDiff || errors.Is(err, ErrSyncInterrupted) {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
+}
@@ -0,0 +1,90 @@
+//nolint:revive // utils is a standard package name for utilities
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	openawarenessv1beta1 "github.com/syndlex/openawareness-controller/api/openawareness/v1beta1"
+)
+
+func TestSetWouldSyncCondition(t *testing.T) {
+	tests := []struct {
+		name        string
+		diff        string
+		err         error
+		wantStatus  metav1.ConditionStatus
+		wantReason  string
+		wantMessage string
+	}{
+		{
+			name:        "in sync",
+			wantStatus:  metav1.ConditionTrue,
+			wantReason:  openawarenessv1beta1.ReasonWouldSync,
+			wantMessage: "nothing would be pushed",
+		},
+		{
+			name:        "changes",
+			diff:        "create team-a/api",
+			wantStatus:  metav1.ConditionTrue,
+			wantReason:  openawarenessv1beta1.ReasonWouldSync,
+			wantMessage: "create team-a/api",
+		},
+		{
+			name:        "failure",
+			err:         errors.New("connection refused"),
+			wantStatus:  metav1.ConditionFalse,
+			wantReason:  openawarenessv1beta1.ReasonWouldFail,
+			wantMessage: "Diff would fail: connection refused",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var conditions []metav1.Condition
+			SetWouldSyncCondition(&conditions, 3, SyncStageDiff, tt.diff, tt.err)
+			condition := FindCondition(conditions, openawarenessv1beta1.ConditionTypeWouldSync)
+			if condition == nil {
+				t.Fatal("expected a WouldSync condition")
+			}
+			if condition.Status != tt.wantStatus || condition.Reason != tt.wantReason ||
+				condition.ObservedGeneration != 3 {
+				t.Errorf("got %s/%s at generation %d, want %s/%s at generation 3",
+					condition.Status, condition.Reason, condition.ObservedGeneration, tt.wantStatus, tt.wantReason)
+			}
+			if !strings.Contains(condition.Message, tt.wantMessage) {
+				t.Errorf("message %q does not contain %q", condition.Message, tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestReadOnlyResult(t *testing.T) {
+	failure := errors.New("failed")
+	tests := []struct {
+		stage   SyncStage
+		err     error
+		wantErr bool
+	}{
+		{stage: SyncStageDiff},
+		{stage: SyncStageResolve, err: failure, wantErr: true},
+		{stage: SyncStageDiff, err: failure, wantErr: true},
+		{stage: SyncStageDiff, err: fmt.Errorf("tenant: %w", ErrTenantNotAllowed)},
+		{stage: SyncStageValidate, err: failure},
+		{stage: SyncStageRender, err: fmt.Errorf("%w: %w", ErrSyncInterrupted, failure), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s/%v", tt.stage, tt.err), func(t *testing.T) {
+			result, err := ReadOnlyResult(tt.stage, tt.err)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ReadOnlyResult() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !result.IsZero() {
+				t.Errorf("expected no requeue, got %v", result)
+			}
+		})
+	}
+}
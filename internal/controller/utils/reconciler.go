@@ -14,7 +14,7 @@ import (
 //
 // Parameters:
 //   - ctx: The context for the operation
-//   - client: The Kubernetes client for updating resources
+//   - client: The Kubernetes client for patching resources
 //   - obj: The Kubernetes object to manage
 //   - finalizerName: The name of the finalizer to add/remove
 //   - cleanupFunc: Optional cleanup function to execute before finalizer removal (can be nil)
@@ -37,11 +37,8 @@ func HandleFinalizer(ctx context.Context, client k8sClient.Client, obj k8sClient
 	// Check if object is being deleted
 	if obj.GetDeletionTimestamp().IsZero() {
 		// Object is NOT being deleted - ensure finalizer is present
-		if !controllerutil.ContainsFinalizer(obj, finalizerName) {
-			controllerutil.AddFinalizer(obj, finalizerName)
-			if err := client.Update(ctx, obj); err != nil {
-				return false, err
-			}
+		if err := AddFinalizer(ctx, client, obj, finalizerName); err != nil {
+			return false, err
 		}
 		return false, nil
 	}
@@ -59,8 +56,7 @@ func HandleFinalizer(ctx context.Context, client k8sClient.Client, obj k8sClient
 		}
 
 		// Remove finalizer
-		controllerutil.RemoveFinalizer(obj, finalizerName)
-		if err := client.Update(ctx, obj); err != nil {
+		if err := RemoveFinalizer(ctx, client, obj, finalizerName); err != nil {
 			return true, err
 		}
 	}
@@ -0,0 +1,178 @@
+//nolint:revive // utils is a standard package name for utilities
+package utils
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	k8sClient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// DefaultResyncRate is the default number of outdated resources re-pushed per minute
+const DefaultResyncRate = 30
+
+// ResyncPacer spreads the re-push of resources last synced by another controller version, e.g.
+// after an upgrade changing the rendering or conversion, over time instead of pushing all of
+// them at once. Resources are stamped with ControllerVersionAnnotation once synced; resources
+// without stamp are new or predate the stamp and are synced right away.
+type ResyncPacer struct {
+	// Version is the running controller version, stamping and pacing are disabled if empty
+	Version string
+	// Interval is the minimum time between two paced re-pushes, zero disables pacing
+	Interval time.Duration
+
+	mu sync.Mutex
+	// next is the time of the next free re-push slot
+	next time.Time
+	// due holds the re-push slot of every outdated resource waiting for it
+	due map[types.UID]time.Time
+	now func() time.Time
+}
+
+// NewResyncPacer returns a ResyncPacer for the running version re-pushing perMinute outdated
+// resources per minute. A perMinute of zero or less stamps resources without pacing.
+func NewResyncPacer(version string, perMinute int) *ResyncPacer {
+	pacer := &ResyncPacer{Version: version}
+	if perMinute > 0 {
+		pacer.Interval = time.Minute / time.Duration(perMinute)
+	}
+	return pacer
+}
+
+// Outdated reports whether obj was last synced by another controller version.
+func (p *ResyncPacer) Outdated(obj k8sClient.Object) bool {
+	if p == nil || p.Version == "" {
+		return false
+	}
+	stamp, ok := obj.GetAnnotations()[ControllerVersionAnnotation]
+	return ok && stamp != p.Version
+}
+
+// Wait returns how long the sync of obj has to wait for its re-push slot. The slot of an
+// outdated resource is assigned on its first call, later calls return the remaining time.
+// Returns zero if obj is not outdated or its slot is due.
+func (p *ResyncPacer) Wait(obj k8sClient.Object) time.Duration {
+	if !p.Outdated(obj) || p.Interval <= 0 {
+		return 0
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := time.Now()
+	if p.now != nil {
+		now = p.now()
+	}
+	if p.due == nil {
+		p.due = map[types.UID]time.Time{}
+	}
+	due, ok := p.due[obj.GetUID()]
+	if !ok {
+		due = p.next
+		if due.Before(now) {
+			due = now
+		}
+		p.due[obj.GetUID()] = due
+		p.next = due.Add(p.Interval)
+	}
+	return due.Sub(now)
+}
+
+// Done stamps obj with the running version after a successful sync and releases its slot.
+func (p *ResyncPacer) Done(ctx context.Context, client k8sClient.Client, obj k8sClient.Object) error {
+	if p == nil || p.Version == "" {
+		return nil
+	}
+	p.Forget(obj)
+	if obj.GetAnnotations()[ControllerVersionAnnotation] == p.Version {
+		return nil
+	}
+
+	base, ok := obj.DeepCopyObject().(k8sClient.Object)
+	if !ok {
+		return nil
+	}
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[ControllerVersionAnnotation] = p.Version
+	obj.SetAnnotations(annotations)
+	return client.Patch(ctx, obj, k8sClient.MergeFrom(base))
+}
+
+// Forget releases the re-push slot of obj, e.g. when it is deleted.
+func (p *ResyncPacer) Forget(obj k8sClient.Object) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.due, obj.GetUID())
+}
+
+// ResyncTracker records when resources were last pushed in full on resync, so the reconcile
+// requeued after SyncReconciler.ResyncInterval pushes them again even if their recorded
+// checksums match, correcting changes made directly in the remote system. Resources are tracked
+// by namespace and name, so they can be forgotten once they are gone. The zero value is ready to
+// use and must be shared by the reconciles of a controller.
+type ResyncTracker struct {
+	mu sync.Mutex
+	// last holds the time of the last full push of every tracked resource
+	last map[types.NamespacedName]time.Time
+	now  func() time.Time
+}
+
+// Due reports whether interval elapsed since the last full push of obj. The first call for obj
+// starts its interval, resources are pushed as usual on their first sync. Returns false if t is
+// nil or interval is zero or less.
+func (t *ResyncTracker) Due(obj k8sClient.Object, interval time.Duration) bool {
+	if t == nil || interval <= 0 {
+		return false
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	now := time.Now()
+	if t.now != nil {
+		now = t.now()
+	}
+	if t.last == nil {
+		t.last = map[types.NamespacedName]time.Time{}
+	}
+	key := k8sClient.ObjectKeyFromObject(obj)
+	last, ok := t.last[key]
+	if !ok {
+		t.last[key] = now
+		return false
+	}
+	return now.Sub(last) >= interval
+}
+
+// Done records the full push of obj, its next one is due after the resync interval.
+func (t *ResyncTracker) Done(obj k8sClient.Object) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	now := time.Now()
+	if t.now != nil {
+		now = t.now()
+	}
+	if t.last == nil {
+		t.last = map[types.NamespacedName]time.Time{}
+	}
+	t.last[k8sClient.ObjectKeyFromObject(obj)] = now
+}
+
+// Forget stops tracking the resource with key, e.g. when it is deleted or no longer found.
+func (t *ResyncTracker) Forget(key types.NamespacedName) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.last, key)
+}
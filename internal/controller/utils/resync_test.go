@@ -0,0 +1,132 @@
+//nolint:revive // utils is a standard package name for utilities
+package utils
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	openawarenessv1beta1 "github.com/syndlex/openawareness-controller/api/openawareness/v1beta1"
+)
+
+func stampedTenant(name, version string) *openawarenessv1beta1.MimirAlertTenant {
+	tenant := &openawarenessv1beta1.MimirAlertTenant{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "team", UID: types.UID(name)},
+	}
+	if version != "" {
+		tenant.Annotations = map[string]string{ControllerVersionAnnotation: version}
+	}
+	return tenant
+}
+
+func TestResyncPacerWait(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	pacer := NewResyncPacer("v2", 2)
+	pacer.now = func() time.Time { return now }
+
+	if delay := pacer.Wait(stampedTenant("new", "")); delay != 0 {
+		t.Errorf("expected unstamped resources to sync right away, got %v", delay)
+	}
+	if delay := pacer.Wait(stampedTenant("current", "v2")); delay != 0 {
+		t.Errorf("expected resources of the running version to sync right away, got %v", delay)
+	}
+
+	first, second, third := stampedTenant("a", "v1"), stampedTenant("b", "v1"), stampedTenant("c", "v1")
+	if delay := pacer.Wait(first); delay != 0 {
+		t.Errorf("expected the first outdated resource to sync right away, got %v", delay)
+	}
+	if delay := pacer.Wait(second); delay != 30*time.Second {
+		t.Errorf("expected the second outdated resource to wait 30s, got %v", delay)
+	}
+	if delay := pacer.Wait(third); delay != time.Minute {
+		t.Errorf("expected the third outdated resource to wait 1m, got %v", delay)
+	}
+
+	now = now.Add(20 * time.Second)
+	if delay := pacer.Wait(second); delay != 10*time.Second {
+		t.Errorf("expected the slot to be kept across calls, got %v", delay)
+	}
+
+	unpaced := NewResyncPacer("v2", 0)
+	if delay := unpaced.Wait(second); delay != 0 {
+		t.Errorf("expected no pacing without rate, got %v", delay)
+	}
+	var disabled *ResyncPacer
+	if disabled.Outdated(first) || disabled.Wait(first) != 0 {
+		t.Error("expected a nil pacer to neither report nor pace outdated resources")
+	}
+}
+
+func TestResyncPacerDone(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := openawarenessv1beta1.AddToScheme(scheme); err != nil {
+		t.Fatalf("adding scheme: %v", err)
+	}
+	tenant := stampedTenant("a", "v1")
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(tenant).Build()
+	ctx := context.Background()
+
+	pacer := NewResyncPacer("v2", 1)
+	pacer.Wait(tenant)
+	if err := pacer.Done(ctx, c, tenant); err != nil {
+		t.Fatalf("Done() error = %v", err)
+	}
+	if len(pacer.due) != 0 {
+		t.Errorf("expected the slot to be released, got %v", pacer.due)
+	}
+
+	latest := &openawarenessv1beta1.MimirAlertTenant{}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(tenant), latest); err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if got := latest.Annotations[ControllerVersionAnnotation]; got != "v2" {
+		t.Errorf("expected the tenant to be stamped with v2, got %q", got)
+	}
+	if pacer.Outdated(latest) {
+		t.Error("expected the stamped tenant to be up to date")
+	}
+}
+
+func TestResyncTrackerDue(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	tracker := &ResyncTracker{now: func() time.Time { return now }}
+	tenant := stampedTenant("a", "")
+
+	if tracker.Due(tenant, time.Hour) {
+		t.Error("expected the first sync not to be a resync")
+	}
+	now = now.Add(30 * time.Minute)
+	if tracker.Due(tenant, time.Hour) {
+		t.Error("expected no resync before the interval elapsed")
+	}
+	now = now.Add(30 * time.Minute)
+	if !tracker.Due(tenant, time.Hour) {
+		t.Error("expected a resync once the interval elapsed")
+	}
+	tracker.Done(tenant)
+	if tracker.Due(tenant, time.Hour) {
+		t.Error("expected Done to restart the interval")
+	}
+	if tracker.Due(tenant, 0) {
+		t.Error("expected no resync without interval")
+	}
+
+	tracker.Forget(client.ObjectKeyFromObject(tenant))
+	now = now.Add(2 * time.Hour)
+	if tracker.Due(tenant, time.Hour) {
+		t.Error("expected a forgotten resource to start a new interval")
+	}
+
+	var disabled *ResyncTracker
+	disabled.Done(tenant)
+	disabled.Forget(client.ObjectKeyFromObject(tenant))
+	if disabled.Due(tenant, time.Hour) {
+		t.Error("expected a nil tracker to never report a resync")
+	}
+}
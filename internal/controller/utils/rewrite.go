@@ -0,0 +1,111 @@
+//nolint:revive // utils is a standard package name for utilities
+package utils
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/rulefmt"
+	"github.com/prometheus/prometheus/promql/parser"
+)
+
+// ExpressionRewrite is an expression of a rule changed by RewriteExpressions.
+type ExpressionRewrite struct {
+	Group string
+	// Rule is the record or alert name of the rule
+	Rule   string
+	Before string
+	After  string
+}
+
+// String returns the rewrite as a diff of the expression, e.g. "node/InstanceDown:\n- up == 0\n+ ...".
+func (r ExpressionRewrite) String() string {
+	return fmt.Sprintf("%s/%s:\n- %s\n+ %s", r.Group, r.Rule, r.Before, r.After)
+}
+
+// InjectMatchers adds an equality matcher of every label in matchers to the vector selectors of
+// expr, including those of range vectors and subqueries, that do not match the label yet.
+// Returns expr unchanged if no selector changed, the rewritten expression formatted by the PromQL
+// parser otherwise, and an error if expr is not valid PromQL.
+func InjectMatchers(expr string, matchers map[string]string) (string, error) {
+	if len(matchers) == 0 {
+		return expr, nil
+	}
+	parsed, err := parser.ParseExpr(expr)
+	if err != nil {
+		return "", err
+	}
+
+	names := make([]string, 0, len(matchers))
+	for name := range matchers {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	changed := false
+	parser.Inspect(parsed, func(node parser.Node, _ []parser.Node) error {
+		selector, ok := node.(*parser.VectorSelector)
+		if !ok {
+			return nil
+		}
+		for _, name := range names {
+			if slices.ContainsFunc(selector.LabelMatchers, func(m *labels.Matcher) bool { return m.Name == name }) {
+				continue
+			}
+			selector.LabelMatchers = append(selector.LabelMatchers,
+				labels.MustNewMatcher(labels.MatchEqual, name, matchers[name]))
+			changed = true
+		}
+		return nil
+	})
+	if !changed {
+		return expr, nil
+	}
+	return parsed.String(), nil
+}
+
+// RewriteExpressions injects the matchers into the expressions of every rule of the groups, see
+// InjectMatchers. The groups are only modified if dryRun is false.
+// Returns the changed expressions, and an error naming the rule if an expression is not valid PromQL.
+func RewriteExpressions(groups []rulefmt.RuleGroup, matchers map[string]string, dryRun bool) ([]ExpressionRewrite, error) {
+	var rewrites []ExpressionRewrite
+	for i := range groups {
+		for j := range groups[i].Rules {
+			rule := &groups[i].Rules[j]
+			name := rule.Record
+			if name == "" {
+				name = rule.Alert
+			}
+			rewritten, err := InjectMatchers(rule.Expr, matchers)
+			if err != nil {
+				return nil, fmt.Errorf("rule group %s: rule %s: %w", groups[i].Name, name, err)
+			}
+			if rewritten == rule.Expr {
+				continue
+			}
+			rewrites = append(rewrites, ExpressionRewrite{
+				Group: groups[i].Name, Rule: name, Before: rule.Expr, After: rewritten,
+			})
+			if !dryRun {
+				rule.Expr = rewritten
+			}
+		}
+	}
+	return rewrites, nil
+}
+
+// RewriteDiff joins the rewrites to a diff of the changed expressions. Diffs longer than
+// maxLength are truncated, zero disables the limit.
+func RewriteDiff(rewrites []ExpressionRewrite, maxLength int) string {
+	entries := make([]string, 0, len(rewrites))
+	for _, rewrite := range rewrites {
+		entries = append(entries, rewrite.String())
+	}
+	diff := strings.Join(entries, "\n")
+	if maxLength > 0 && len(diff) > maxLength {
+		diff = diff[:max(maxLength-len(truncatedMarker), 0)] + truncatedMarker
+	}
+	return diff
+}
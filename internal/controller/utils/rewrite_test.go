@@ -0,0 +1,94 @@
+//nolint:revive // utils is a standard package name for utilities
+package utils
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/prometheus/prometheus/model/rulefmt"
+)
+
+func TestInjectMatchers(t *testing.T) {
+	matchers := map[string]string{"cluster": "eu-1", "env": "prod"}
+	tests := []struct {
+		expr     string
+		expected string
+	}{
+		{expr: `up == 0`, expected: `up{cluster="eu-1",env="prod"} == 0`},
+		{
+			expr:     `sum by (job) (rate(http_requests_total{code="500"}[5m]))`,
+			expected: `sum by (job) (rate(http_requests_total{cluster="eu-1",code="500",env="prod"}[5m]))`,
+		},
+		{
+			expr:     `max_over_time(up{cluster="us-1"}[1h:5m])`,
+			expected: `max_over_time(up{cluster="us-1",env="prod"}[1h:5m])`,
+		},
+		{expr: `vector(1)`, expected: `vector(1)`},
+		{expr: `up{cluster="eu-1", env=~"prod|staging"}`, expected: `up{cluster="eu-1", env=~"prod|staging"}`},
+	}
+	for _, test := range tests {
+		rewritten, err := InjectMatchers(test.expr, matchers)
+		if err != nil {
+			t.Fatalf("InjectMatchers(%q) returned %v", test.expr, err)
+		}
+		if rewritten != test.expected {
+			t.Errorf("InjectMatchers(%q) = %q, expected %q", test.expr, rewritten, test.expected)
+		}
+	}
+
+	if _, err := InjectMatchers(`sum(`, matchers); err == nil {
+		t.Error("expected an error for an invalid expression")
+	}
+}
+
+func TestRewriteExpressions(t *testing.T) {
+	newGroups := func() []rulefmt.RuleGroup {
+		return []rulefmt.RuleGroup{{
+			Name: "node",
+			Rules: []rulefmt.Rule{
+				{Record: "job:up:sum", Expr: `sum by (job) (up)`},
+				{Alert: "AlwaysFiring", Expr: `vector(1)`},
+			},
+		}}
+	}
+	matchers := map[string]string{"cluster": "eu-1"}
+
+	groups := newGroups()
+	rewrites, err := RewriteExpressions(groups, matchers, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rewrites) != 1 || rewrites[0].Rule != "job:up:sum" {
+		t.Fatalf("expected the recording rule to be rewritten, got %v", rewrites)
+	}
+	if groups[0].Rules[0].Expr != `sum by (job) (up)` {
+		t.Errorf("expected a dry run to keep the expression, got %q", groups[0].Rules[0].Expr)
+	}
+
+	if _, err := RewriteExpressions(groups, matchers, false); err != nil {
+		t.Fatal(err)
+	}
+	if groups[0].Rules[0].Expr != `sum by (job) (up{cluster="eu-1"})` {
+		t.Errorf("expected the expression to be rewritten, got %q", groups[0].Rules[0].Expr)
+	}
+
+	groups = newGroups()
+	groups[0].Rules[1].Expr = `vector(`
+	if _, err := RewriteExpressions(groups, matchers, false); err == nil ||
+		!strings.Contains(err.Error(), "AlwaysFiring") {
+		t.Errorf("expected an error naming the invalid rule, got %v", err)
+	}
+}
+
+func TestRewriteDiff(t *testing.T) {
+	rewrites := []ExpressionRewrite{
+		{Group: "node", Rule: "InstanceDown", Before: `up == 0`, After: `up{cluster="eu-1"} == 0`},
+	}
+	expected := "node/InstanceDown:\n- up == 0\n+ up{cluster=\"eu-1\"} == 0"
+	if diff := RewriteDiff(rewrites, 0); diff != expected {
+		t.Errorf("expected %q, got %q", expected, diff)
+	}
+	if diff := RewriteDiff(rewrites, 30); len(diff) != 30 || !strings.HasSuffix(diff, truncatedMarker) {
+		t.Errorf("expected the diff to be truncated to 30 bytes, got %q", diff)
+	}
+}
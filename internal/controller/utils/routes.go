@@ -0,0 +1,184 @@
+//nolint:revive // utils is a standard package name for utilities
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+	k8sClient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	openawarenessv1beta1 "github.com/syndlex/openawareness-controller/api/openawareness/v1beta1"
+)
+
+// ErrInvalidRoute is returned for MimirAlertRoutes that cannot be merged into a configuration
+var ErrInvalidRoute = errors.New("invalid route")
+
+// ErrDuplicateReceiver is returned for MimirAlertRoutes defining a receiver name already in use
+var ErrDuplicateReceiver = errors.New("duplicate receiver")
+
+// matcherKeys are the route settings selecting alerts, which are owned by spec.matchers
+var matcherKeys = []string{"matchers", "match", "match_re"}
+
+// ComposedRoutes returns the MimirAlertRoutes contributed to the tenants of a chain returned
+// by ResolveExtends, sorted by name so they are merged in a deterministic order.
+func ComposedRoutes(
+	ctx context.Context,
+	reader k8sClient.Reader,
+	chain []*openawarenessv1beta1.MimirAlertTenant,
+) ([]openawarenessv1beta1.MimirAlertRoute, error) {
+	namespace := chain[len(chain)-1].Namespace
+	routeList := &openawarenessv1beta1.MimirAlertRouteList{}
+	if err := reader.List(ctx, routeList, k8sClient.InNamespace(namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list MimirAlertRoutes in %s: %w", namespace, err)
+	}
+
+	var routes []openawarenessv1beta1.MimirAlertRoute
+	for _, route := range routeList.Items {
+		if slices.ContainsFunc(chain, func(tenant *openawarenessv1beta1.MimirAlertTenant) bool {
+			return tenant.Name == route.Spec.Tenant.Name
+		}) {
+			routes = append(routes, route)
+		}
+	}
+	slices.SortFunc(routes, func(a, b openawarenessv1beta1.MimirAlertRoute) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return routes, nil
+}
+
+// MergeRoutes adds the routing subtrees and receivers of routes to the rendered Alertmanager
+// configuration config. The route settings and receivers are rendered with the same template
+// data and builtins as the configuration. Every route is added as child route after the child
+// routes of config, with spec.matchers as matchers, so routes of the configuration take
+// precedence unless they continue.
+// A route is rejected and left out if it cannot be rendered or parsed, sets matchers itself,
+// references an unknown receiver or defines a receiver name already used by the configuration
+// or a route merged before it.
+// Returns the merged configuration and the rejection errors by route name, which wrap
+// ErrInvalidRoute or ErrDuplicateReceiver, or an error wrapping ErrComposition if config is
+// not a YAML mapping.
+func MergeRoutes(
+	config string,
+	routes []openawarenessv1beta1.MimirAlertRoute,
+	data map[string]string,
+	builtins TemplateBuiltins,
+) (string, map[string]error, error) {
+	if len(routes) == 0 {
+		return config, nil, nil
+	}
+	merged, err := unmarshalMapping(config)
+	if err != nil {
+		return "", nil, fmt.Errorf("%w with MimirAlertRoutes: %w", ErrComposition, err)
+	}
+
+	receivers := asList(merged["receivers"])
+	names := map[string]bool{}
+	for _, receiver := range receivers {
+		names[entryName(receiver)] = true
+	}
+	root, _ := merged["route"].(map[string]any)
+	if root == nil {
+		root = map[string]any{}
+	}
+	children := asList(root["routes"])
+
+	rejected := map[string]error{}
+	for _, route := range routes {
+		subtree, routeReceivers, err := renderRoute(route, data, builtins, names)
+		if err != nil {
+			rejected[route.Name] = err
+			continue
+		}
+		for _, receiver := range routeReceivers {
+			names[entryName(receiver)] = true
+		}
+		receivers = append(receivers, routeReceivers...)
+		children = append(children, subtree)
+	}
+
+	root["routes"] = children
+	merged["route"] = root
+	merged["receivers"] = receivers
+	rendered, err := yaml.Marshal(merged)
+	if err != nil {
+		return "", nil, err
+	}
+	return string(rendered), rejected, nil
+}
+
+// renderRoute renders and validates the subtree and receivers of a route against the receiver
+// names already in use.
+func renderRoute(
+	route openawarenessv1beta1.MimirAlertRoute,
+	data map[string]string,
+	builtins TemplateBuiltins,
+	names map[string]bool,
+) (map[string]any, []any, error) {
+	renderedRoute, err := RenderTemplateWithBuiltins(route.Spec.Route, data, builtins)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: route: %w", ErrInvalidRoute, err)
+	}
+	subtree, err := unmarshalMapping(renderedRoute)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: route must be a YAML mapping: %w", ErrInvalidRoute, err)
+	}
+	for _, key := range matcherKeys {
+		if _, ok := subtree[key]; ok {
+			return nil, nil, fmt.Errorf("%w: route must not set %s, use spec.matchers", ErrInvalidRoute, key)
+		}
+	}
+
+	renderedReceivers, err := RenderTemplateWithBuiltins(route.Spec.Receivers, data, builtins)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: receivers: %w", ErrInvalidRoute, err)
+	}
+	var receivers []any
+	if err := yaml.Unmarshal([]byte(renderedReceivers), &receivers); err != nil {
+		return nil, nil, fmt.Errorf("%w: receivers must be a YAML list: %w", ErrInvalidRoute, err)
+	}
+
+	known := map[string]bool{}
+	for name := range names {
+		known[name] = true
+	}
+	for _, receiver := range receivers {
+		name := entryName(receiver)
+		switch {
+		case name == "":
+			return nil, nil, fmt.Errorf("%w: receivers must have a name", ErrInvalidRoute)
+		case known[name]:
+			return nil, nil, fmt.Errorf("%w: receiver %s is already defined", ErrDuplicateReceiver, name)
+		}
+		known[name] = true
+	}
+	for _, name := range routeReceivers(subtree) {
+		if !known[name] {
+			return nil, nil, fmt.Errorf("%w: receiver %s is not defined", ErrInvalidRoute, name)
+		}
+	}
+
+	matchers := make([]any, 0, len(route.Spec.Matchers))
+	for _, matcher := range route.Spec.Matchers {
+		matchers = append(matchers, matcher)
+	}
+	subtree["matchers"] = matchers
+	return subtree, receivers, nil
+}
+
+// routeReceivers returns the receivers referenced by a route and its child routes.
+func routeReceivers(route map[string]any) []string {
+	var names []string
+	if receiver, ok := route["receiver"].(string); ok && receiver != "" {
+		names = append(names, receiver)
+	}
+	for _, child := range asList(route["routes"]) {
+		if childRoute, ok := child.(map[string]any); ok {
+			names = append(names, routeReceivers(childRoute)...)
+		}
+	}
+	return names
+}
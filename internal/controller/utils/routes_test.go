@@ -0,0 +1,132 @@
+//nolint:revive // utils is a standard package name for utilities
+package utils
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	openawarenessv1beta1 "github.com/syndlex/openawareness-controller/api/openawareness/v1beta1"
+)
+
+func alertRoute(name, tenant, route, receivers string) openawarenessv1beta1.MimirAlertRoute {
+	return openawarenessv1beta1.MimirAlertRoute{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "team"},
+		Spec: openawarenessv1beta1.MimirAlertRouteSpec{
+			Tenant:    openawarenessv1beta1.TenantReference{Name: tenant},
+			Matchers:  []string{`team="` + name + `"`},
+			Route:     route,
+			Receivers: receivers,
+		},
+	}
+}
+
+func TestComposedRoutes(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := openawarenessv1beta1.AddToScheme(scheme); err != nil {
+		t.Fatalf("adding scheme: %v", err)
+	}
+	routes := []openawarenessv1beta1.MimirAlertRoute{
+		alertRoute("payments", "oncall", "", ""),
+		alertRoute("checkout", "root", "", ""),
+		alertRoute("search", "other", "", ""),
+	}
+	builder := fake.NewClientBuilder().WithScheme(scheme)
+	for i := range routes {
+		builder = builder.WithObjects(&routes[i])
+	}
+	chain := []*openawarenessv1beta1.MimirAlertTenant{
+		extendingTenant("root", "", ""),
+		extendingTenant("oncall", "root", ""),
+	}
+
+	composed, err := ComposedRoutes(context.Background(), builder.Build(), chain)
+	if err != nil {
+		t.Fatalf("ComposedRoutes() error = %v", err)
+	}
+	var names []string
+	for _, route := range composed {
+		names = append(names, route.Name)
+	}
+	if !reflect.DeepEqual(names, []string{"checkout", "payments"}) {
+		t.Errorf("expected the routes of the chain sorted by name, got %v", names)
+	}
+}
+
+func TestMergeRoutes(t *testing.T) {
+	config := `
+route:
+  receiver: default
+  routes:
+    - receiver: default
+      matchers: ['severity="info"']
+receivers:
+  - name: default
+`
+	routes := []openawarenessv1beta1.MimirAlertRoute{
+		alertRoute("checkout", "oncall", "receiver: checkout", "- name: checkout\n  webhook_configs:\n    - url: [[ .URL ]]\n"),
+		alertRoute("payments", "oncall", "receiver: payments\ngroup_by: [service]", "- name: payments"),
+		alertRoute("duplicate", "oncall", "receiver: default", "- name: checkout"),
+		alertRoute("matchers", "oncall", "matchers: ['team=\"x\"']", ""),
+		alertRoute("unknown", "oncall", "routes:\n  - receiver: missing", ""),
+		alertRoute("invalid", "oncall", "- a list", ""),
+	}
+
+	merged, rejected, err := MergeRoutes(config, routes, map[string]string{"URL": "http://hook"}, TemplateBuiltins{})
+	if err != nil {
+		t.Fatalf("MergeRoutes() error = %v", err)
+	}
+	want := `
+route:
+  receiver: default
+  routes:
+    - receiver: default
+      matchers: ['severity="info"']
+    - receiver: checkout
+      matchers: ['team="checkout"']
+    - receiver: payments
+      group_by: [service]
+      matchers: ['team="payments"']
+receivers:
+  - name: default
+  - name: checkout
+    webhook_configs:
+      - url: http://hook
+  - name: payments
+`
+	var got, expected map[string]any
+	if err := yaml.Unmarshal([]byte(merged), &got); err != nil {
+		t.Fatalf("merged configuration is invalid: %v", err)
+	}
+	if err := yaml.Unmarshal([]byte(want), &expected); err != nil {
+		t.Fatalf("expected configuration is invalid: %v", err)
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("unexpected merged configuration:\n%s", merged)
+	}
+
+	if !errors.Is(rejected["duplicate"], ErrDuplicateReceiver) {
+		t.Errorf("expected a duplicate receiver error, got %v", rejected["duplicate"])
+	}
+	for _, name := range []string{"matchers", "unknown", "invalid"} {
+		if !errors.Is(rejected[name], ErrInvalidRoute) {
+			t.Errorf("expected route %s to be rejected as invalid, got %v", name, rejected[name])
+		}
+	}
+	if len(rejected) != 4 {
+		t.Errorf("expected 4 rejected routes, got %v", rejected)
+	}
+
+	if unchanged, _, err := MergeRoutes("- a list", nil, nil, TemplateBuiltins{}); err != nil || unchanged != "- a list" {
+		t.Errorf("expected the configuration unchanged without routes, got %q, %v", unchanged, err)
+	}
+	if _, _, err := MergeRoutes("- a list", routes, nil, TemplateBuiltins{}); !errors.Is(err, ErrComposition) {
+		t.Errorf("expected a composition error, got %v", err)
+	}
+}
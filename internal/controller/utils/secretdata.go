@@ -0,0 +1,133 @@
+//nolint:revive // utils is a standard package name for utilities
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sClient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	openawarenessv1beta1 "github.com/syndlex/openawareness-controller/api/openawareness/v1beta1"
+)
+
+// ErrReferenceConflict is returned when SecretDataReferences define conflicting values
+// and the Error merge strategy is used
+var ErrReferenceConflict = errors.New("conflicting values in SecretDataReferences")
+
+// MetadataOf returns the metadata-only object of a core kind, e.g. Secret, to watch its
+// objects without caching their data. The data is read from the API server when needed.
+func MetadataOf(kind string) *metav1.PartialObjectMetadata {
+	return &metav1.PartialObjectMetadata{TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: kind}}
+}
+
+// GetSecretData fetches and merges data from all SecretDataReferences in the given namespace.
+// Returns a map of key-value pairs for templating.
+// Later references override earlier ones in case of key conflicts. Keys defined with
+// different values by several references are returned as conflicts, unless the strategy
+// is OverrideSilently; with the Error strategy an error wrapping ErrReferenceConflict
+// is returned alongside the conflicts.
+// Returns error if a required (non-optional) reference is not found.
+func GetSecretData(
+	ctx context.Context,
+	reader k8sClient.Reader,
+	logger logr.Logger,
+	namespace string,
+	refs []openawarenessv1beta1.SecretDataReference,
+	strategy openawarenessv1beta1.ReferenceMergeStrategy,
+) (map[string]string, []openawarenessv1beta1.ReferenceConflict, error) {
+	data := make(map[string]string)
+	sources := make(map[string][]string)
+	conflicting := make(map[string]bool)
+
+	for _, ref := range refs {
+		refData, err := FetchReferenceData(ctx, reader, namespace, ref)
+		if err != nil {
+			if ref.Optional {
+				logger.Info("Optional reference not found, skipping", "reference", ref.Kind+"/"+ref.Name)
+				continue
+			}
+			return nil, nil, fmt.Errorf("failed to get %s %s: %w", ref.Kind, ref.Name, err)
+		}
+
+		// Merge data (later refs override earlier ones)
+		source := ref.Kind + "/" + ref.Name
+		for k, v := range refData {
+			if existing, ok := data[k]; ok && existing != v {
+				conflicting[k] = true
+			}
+			data[k] = v
+			sources[k] = append(sources[k], source)
+		}
+	}
+
+	if strategy == "" || strategy == openawarenessv1beta1.ReferenceMergeOverrideSilently || len(conflicting) == 0 {
+		return data, nil, nil
+	}
+
+	keys := make([]string, 0, len(conflicting))
+	for k := range conflicting {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	conflicts := make([]openawarenessv1beta1.ReferenceConflict, 0, len(keys))
+	for _, k := range keys {
+		conflicts = append(conflicts, openawarenessv1beta1.ReferenceConflict{
+			Key:           k,
+			Sources:       sources[k],
+			WinningSource: sources[k][len(sources[k])-1],
+		})
+	}
+
+	if strategy == openawarenessv1beta1.ReferenceMergeError {
+		return nil, conflicts, fmt.Errorf("%w: %s", ErrReferenceConflict, strings.Join(keys, ", "))
+	}
+
+	logger.Info("SecretDataReferences override conflicting keys",
+		"keys", keys)
+	return data, conflicts, nil
+}
+
+// FetchReferenceData retrieves data from a single ConfigMap or Secret
+func FetchReferenceData(
+	ctx context.Context,
+	reader k8sClient.Reader,
+	namespace string,
+	ref openawarenessv1beta1.SecretDataReference,
+) (map[string]string, error) {
+	switch ref.Kind {
+	case "ConfigMap":
+		cm := &corev1.ConfigMap{}
+		if err := reader.Get(ctx, k8sClient.ObjectKey{
+			Name:      ref.Name,
+			Namespace: namespace,
+		}, cm); err != nil {
+			return nil, err
+		}
+		return cm.Data, nil
+
+	case "Secret":
+		secret := &corev1.Secret{}
+		if err := reader.Get(ctx, k8sClient.ObjectKey{
+			Name:      ref.Name,
+			Namespace: namespace,
+		}, secret); err != nil {
+			return nil, err
+		}
+		// Convert []byte to string
+		data := make(map[string]string, len(secret.Data))
+		for k, v := range secret.Data {
+			data[k] = string(v)
+		}
+		return data, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported reference kind: %s", ref.Kind)
+	}
+}
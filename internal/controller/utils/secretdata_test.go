@@ -0,0 +1,121 @@
+//nolint:revive // utils is a standard package name for utilities
+package utils
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	openawarenessv1beta1 "github.com/syndlex/openawareness-controller/api/openawareness/v1beta1"
+)
+
+func TestGetSecretData(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatalf("adding scheme: %v", err)
+	}
+
+	reader := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "values", Namespace: "team"},
+			Data:       map[string]string{"env": "dev", "cluster": "eu-1"},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "credentials", Namespace: "team"},
+			Data:       map[string][]byte{"env": []byte("prod"), "token": []byte("secret")},
+		},
+	).Build()
+
+	configMapRef := openawarenessv1beta1.SecretDataReference{Name: "values", Kind: "ConfigMap"}
+	secretRef := openawarenessv1beta1.SecretDataReference{Name: "credentials", Kind: "Secret"}
+
+	tests := []struct {
+		name              string
+		refs              []openawarenessv1beta1.SecretDataReference
+		strategy          openawarenessv1beta1.ReferenceMergeStrategy
+		expected          map[string]string
+		expectedConflicts []string
+		expectError       bool
+	}{
+		{
+			name:     "later references override earlier ones",
+			refs:     []openawarenessv1beta1.SecretDataReference{configMapRef, secretRef},
+			expected: map[string]string{"env": "prod", "cluster": "eu-1", "token": "secret"},
+		},
+		{
+			name:              "override with warning reports conflicts",
+			refs:              []openawarenessv1beta1.SecretDataReference{configMapRef, secretRef},
+			strategy:          openawarenessv1beta1.ReferenceMergeOverrideWithWarning,
+			expected:          map[string]string{"env": "prod", "cluster": "eu-1", "token": "secret"},
+			expectedConflicts: []string{"env"},
+		},
+		{
+			name:              "error strategy fails on conflicts",
+			refs:              []openawarenessv1beta1.SecretDataReference{configMapRef, secretRef},
+			strategy:          openawarenessv1beta1.ReferenceMergeError,
+			expectedConflicts: []string{"env"},
+			expectError:       true,
+		},
+		{
+			name:     "same reference twice is not a conflict",
+			refs:     []openawarenessv1beta1.SecretDataReference{configMapRef, configMapRef},
+			strategy: openawarenessv1beta1.ReferenceMergeError,
+			expected: map[string]string{"env": "dev", "cluster": "eu-1"},
+		},
+		{
+			name: "optional missing reference is skipped",
+			refs: []openawarenessv1beta1.SecretDataReference{
+				configMapRef,
+				{Name: "missing", Kind: "ConfigMap", Optional: true},
+			},
+			expected: map[string]string{"env": "dev", "cluster": "eu-1"},
+		},
+		{
+			name:        "required missing reference fails",
+			refs:        []openawarenessv1beta1.SecretDataReference{{Name: "missing", Kind: "Secret"}},
+			expectError: true,
+		},
+		{
+			name:        "unsupported kind fails",
+			refs:        []openawarenessv1beta1.SecretDataReference{{Name: "values", Kind: "Pod"}},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, conflicts, err := GetSecretData(context.Background(), reader, logr.Discard(), "team",
+				tt.refs, tt.strategy)
+			if len(conflicts) != len(tt.expectedConflicts) {
+				t.Fatalf("expected conflicts for %v, got %+v", tt.expectedConflicts, conflicts)
+			}
+			for i, key := range tt.expectedConflicts {
+				if conflicts[i].Key != key || conflicts[i].WinningSource != "Secret/credentials" {
+					t.Errorf("unexpected conflict %+v, want key %s won by Secret/credentials", conflicts[i], key)
+				}
+			}
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("expected an error, got %v", data)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(data) != len(tt.expected) {
+				t.Fatalf("expected %v, got %v", tt.expected, data)
+			}
+			for k, v := range tt.expected {
+				if data[k] != v {
+					t.Errorf("data[%q] = %q, want %q", k, data[k], v)
+				}
+			}
+		})
+	}
+}
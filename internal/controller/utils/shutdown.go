@@ -0,0 +1,74 @@
+//nolint:revive // utils is a standard package name for utilities
+package utils
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	k8sClient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// DefaultShutdownGracePeriod is the default time reconciliations in flight may finish after
+	// a shutdown signal
+	DefaultShutdownGracePeriod = 30 * time.Second
+	// ShutdownMarkTimeout bounds the writes recording a reconciliation interrupted by a shutdown,
+	// the manager must wait this long on top of the grace period
+	ShutdownMarkTimeout = 10 * time.Second
+)
+
+// ErrSyncInterrupted reports a reconciliation cut off at the end of the shutdown grace period
+var ErrSyncInterrupted = errors.New("sync interrupted by controller shutdown")
+
+// WithShutdownGrace returns a context that is only cancelled grace after ctx, so a reconciliation
+// in flight when the manager stops on SIGTERM can finish its pushes instead of leaving partially
+// pushed rule groups behind. Values of ctx are kept. A grace of zero or less returns a context
+// cancelled with ctx.
+func WithShutdownGrace(ctx context.Context, grace time.Duration) (context.Context, context.CancelFunc) {
+	if grace <= 0 {
+		return context.WithCancel(ctx)
+	}
+	graceCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	stop := context.AfterFunc(ctx, func() {
+		timer := time.NewTimer(grace)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+			cancel()
+		case <-graceCtx.Done():
+		}
+	})
+	return graceCtx, func() {
+		stop()
+		cancel()
+	}
+}
+
+// RequeueOnStart reports whether obj is marked with the RequeueOnStartAnnotation.
+func RequeueOnStart(obj k8sClient.Object) bool {
+	return obj.GetAnnotations()[RequeueOnStartAnnotation] == "true"
+}
+
+// SetRequeueOnStart adds the RequeueOnStartAnnotation to obj, or removes it if requeue is false.
+// Nothing is written if the annotation is already in the desired state.
+func SetRequeueOnStart(ctx context.Context, client k8sClient.Client, obj k8sClient.Object, requeue bool) error {
+	if RequeueOnStart(obj) == requeue {
+		return nil
+	}
+	base, ok := obj.DeepCopyObject().(k8sClient.Object)
+	if !ok {
+		return nil
+	}
+	annotations := obj.GetAnnotations()
+	if requeue {
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		annotations[RequeueOnStartAnnotation] = "true"
+	} else {
+		delete(annotations, RequeueOnStartAnnotation)
+	}
+	obj.SetAnnotations(annotations)
+	return client.Patch(ctx, obj, k8sClient.MergeFrom(base))
+}
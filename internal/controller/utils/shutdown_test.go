@@ -0,0 +1,46 @@
+//nolint:revive // utils is a standard package name for utilities
+package utils
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestWithShutdownGrace(t *testing.T) {
+	type key struct{}
+	parent, stop := context.WithCancel(context.WithValue(context.Background(), key{}, "value"))
+	ctx, cancel := WithShutdownGrace(parent, 50*time.Millisecond)
+	defer cancel()
+	if ctx.Value(key{}) != "value" {
+		t.Error("expected the values of the parent to be kept")
+	}
+
+	stop()
+	if ctx.Err() != nil {
+		t.Fatal("expected the context to outlive its parent for the grace period")
+	}
+	select {
+	case <-ctx.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the context to be cancelled after the grace period")
+	}
+
+	// Without grace period, the context is cancelled with its parent
+	parent, stop = context.WithCancel(context.Background())
+	ctx, cancel = WithShutdownGrace(parent, 0)
+	defer cancel()
+	stop()
+	if ctx.Err() == nil {
+		t.Error("expected the context to be cancelled with its parent")
+	}
+
+	// Cancelling the context ends the grace period
+	parent, stop = context.WithCancel(context.Background())
+	defer stop()
+	ctx, cancel = WithShutdownGrace(parent, time.Hour)
+	cancel()
+	if ctx.Err() == nil {
+		t.Error("expected cancel to cancel the context")
+	}
+}
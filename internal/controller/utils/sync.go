@@ -0,0 +1,381 @@
+//nolint:revive // utils is a standard package name for utilities
+package utils
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	ctrl "sigs.k8s.io/controller-runtime"
+	k8sClient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	openawarenessv1beta1 "github.com/syndlex/openawareness-controller/api/openawareness/v1beta1"
+	"github.com/syndlex/openawareness-controller/internal/clients"
+	"github.com/syndlex/openawareness-controller/internal/logging"
+	"github.com/syndlex/openawareness-controller/internal/metrics"
+)
+
+// SyncStage identifies the step of a SyncReconciler reconciliation an outcome belongs to.
+type SyncStage string
+
+const (
+	// SyncStageResolve resolves the client of the remote system
+	SyncStageResolve SyncStage = "Resolve"
+	// SyncStageRender renders the payload from the resource
+	SyncStageRender SyncStage = "Render"
+	// SyncStageValidate validates the rendered payload
+	SyncStageValidate SyncStage = "Validate"
+	// SyncStagePaused reports a paused resource that was validated but not pushed
+	SyncStagePaused SyncStage = "Paused"
+	// SyncStagePush pushes the payload to the remote system
+	SyncStagePush SyncStage = "Push"
+	// SyncStageSynced reports a successfully pushed payload
+	SyncStageSynced SyncStage = "Synced"
+	// SyncStageDiff compares the payload with the remote system instead of pushing it, in
+	// read-only mode
+	SyncStageDiff SyncStage = "Diff"
+	// SyncStageDelete removes the resource from the remote system on deletion
+	SyncStageDelete SyncStage = "Delete"
+)
+
+// SyncState carries a resource through one SyncReconciler reconciliation.
+type SyncState[T k8sClient.Object] struct {
+	// Object is the reconciled resource
+	Object T
+	// Original is the resource as read, status changes are patched against it, see PatchStatus
+	Original T
+	// Reconciliation records the metrics of the reconciliation
+	Reconciliation *metrics.Reconciliation
+	// Timeout bounds the operations on the remote system, zero if disabled
+	Timeout time.Duration
+	// SyncContext is bounded by Timeout and passed to the operations on the remote system
+	SyncContext context.Context
+	// ClientConfig is the ClientConfig the resource is synced with, set by SyncAdapter.Resolve.
+	// The tenants of the resource are resolved through its default tenant and tenant aliases
+	ClientConfig *openawarenessv1beta1.ClientConfig
+	// ReadOnly reports that the payload is diffed against the remote system instead of pushed,
+	// see SyncReconciler.ReadOnly
+	ReadOnly bool
+	// Resync reports that the resync interval elapsed, the payload is pushed in full even if
+	// parts of it are recorded as unchanged, see SyncReconciler.Resyncs
+	Resync bool
+}
+
+// SyncOutcome is the result of a SyncReconciler stage reported to SyncAdapter.Report.
+type SyncOutcome[P any] struct {
+	// Stage is the stage that completed or failed
+	Stage SyncStage
+	// Err is the error of the stage, nil for SyncStagePaused, SyncStageSynced and
+	// successful deletions
+	Err error
+	// Payload is the rendered payload, set from SyncStageValidate on
+	Payload P
+	// Remote is the resolved client, set once it was resolved
+	Remote clients.AwarenessClient
+	// Diff summarizes the changes a push would make, empty if the remote system is in sync.
+	// Set for a successful SyncStageDiff
+	Diff string
+}
+
+// SyncAdapter connects a resource type synced to a remote system through annotations to
+// SyncReconciler. T is the resource, P the payload rendered from it and pushed.
+type SyncAdapter[T k8sClient.Object, P any] interface {
+	// NewObject returns an empty resource to read the reconciled resource into
+	NewObject() T
+	// SyncTimeout returns the timeout of the operations on the remote system for obj
+	SyncTimeout(obj T) time.Duration
+	// Resolve returns the client of the remote system the resource is synced to
+	Resolve(ctx context.Context, state *SyncState[T]) (clients.AwarenessClient, error)
+	// Render computes the payload of the resource
+	Render(ctx context.Context, state *SyncState[T]) (P, error)
+	// Validate checks the rendered payload before it is pushed
+	Validate(ctx context.Context, state *SyncState[T], payload P) error
+	// Push writes the payload to the remote system
+	Push(ctx context.Context, state *SyncState[T], remote clients.AwarenessClient, payload P) error
+	// Diff summarizes the changes pushing the payload would make to the remote system without
+	// changing it, empty if it is in sync
+	Diff(ctx context.Context, state *SyncState[T], remote clients.AwarenessClient, payload P) (string, error)
+	// Delete removes the resource from the remote system
+	Delete(ctx context.Context, state *SyncState[T], remote clients.AwarenessClient) error
+	// Report records the outcome of a stage in the status or events of the resource and
+	// returns the result of the reconciliation. For SyncStageDelete, the finalizer is only
+	// released if Report returns a zero result and no error.
+	Report(ctx context.Context, state *SyncState[T], outcome SyncOutcome[P]) (ctrl.Result, error)
+}
+
+// SyncReconciler implements the reconciliation shared by resources synced to a remote system:
+// resolve the client, render, validate and push the payload, and report the outcome. The
+// resource specific steps are provided by the Adapter.
+//
+// The reconciliation process:
+//  1. Fetches the resource, paused resources being deleted are left untouched until resumed
+//  2. Registers the finalizer, or on deletion resolves the client, deletes the resource from
+//     the remote system and releases the finalizer
+//  3. Renders and validates the payload
+//  4. Reports paused resources without resolving the client or pushing the payload
+//  5. Resolves the client and pushes the payload under the sync timeout
+//  6. Reports the successful sync and stamps the resource with the controller version
+//
+// Resources stamped by another controller version wait for their re-push slot of the Pacer
+// before step 2.
+// Resources not matching the Selector are skipped before step 2, synced resources are pushed
+// again after ResyncInterval.
+//
+// On shutdown, reconciliations in flight may finish for ShutdownGrace. Those still failing
+// afterwards are reported with an error wrapping ErrSyncInterrupted and marked with the
+// RequeueOnStartAnnotation, which is removed again by their next successful sync.
+//
+// With ResolveBeforeFinalizer, the client is resolved before the finalizer is handled, so
+// the finalizer is only registered once the client resolves and deletion is blocked while
+// it does not. Paused resources then skip the finalizer until resumed.
+//
+// With ReadOnly, step 5 diffs the payload against the remote system instead of pushing it and
+// reports the outcome as SyncStageDiff, no finalizer is registered and resources being deleted
+// are left to the controller holding their finalizer. Failures of the earlier stages are
+// reported with state.ReadOnly set. Nothing but the status of the resources is changed.
+type SyncReconciler[T k8sClient.Object, P any] struct {
+	// Client reads the resources and patches their finalizers
+	Client k8sClient.Client
+	// Adapter provides the resource specific steps
+	Adapter SyncAdapter[T, P]
+	// Kind labels the reconciliation metrics, see metrics.StartReconciliation
+	Kind string
+	// Finalizer guards the removal of the resource from the remote system
+	Finalizer string
+	// ResolveBeforeFinalizer resolves the client before the finalizer is handled
+	ResolveBeforeFinalizer bool
+	// Pacer stamps synced resources with the controller version and paces the re-push of
+	// resources synced by another version, nil disables both
+	Pacer *ResyncPacer
+	// Selector limits the synced resources to those with matching labels, nil selects all.
+	// Resources not matching are neither synced nor removed from the remote system, unless
+	// they are deleted
+	Selector labels.Selector
+	// ResyncInterval requeues synced resources to push them again, 0 disables it
+	ResyncInterval time.Duration
+	// Resyncs sets SyncState.Resync once ResyncInterval elapsed since the last full push of a
+	// resource, nil never sets it
+	Resyncs *ResyncTracker
+	// ShutdownGrace is the time a reconciliation in flight may finish after the manager stops,
+	// 0 cancels it immediately
+	ShutdownGrace time.Duration
+	// ReadOnly diffs the resources against the remote system instead of syncing them
+	ReadOnly bool
+}
+
+// Reconcile reconciles the resource of req, see SyncReconciler.
+func (s *SyncReconciler[T, P]) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
+	logger := log.FromContext(ctx)
+	reconciliation := metrics.StartReconciliation(s.Kind)
+	defer func() { reconciliation.Done(result, err) }()
+
+	ctx, cancelGrace := WithShutdownGrace(ctx, s.ShutdownGrace)
+	defer cancelGrace()
+
+	obj := s.Adapter.NewObject()
+	if err := s.Client.Get(ctx, req.NamespacedName, obj); err != nil {
+		if apierrors.IsNotFound(err) {
+			// Resources deleted without finalizer never pass the delete path
+			s.Resyncs.Forget(req.NamespacedName)
+		}
+		return ctrl.Result{}, k8sClient.IgnoreNotFound(err)
+	}
+	logging.SuccessInfo(logger, req.String(), "Found "+s.Kind)
+
+	original, ok := obj.DeepCopyObject().(T)
+	if !ok {
+		original = obj
+	}
+	deleting := !obj.GetDeletionTimestamp().IsZero()
+	paused := IsPaused(obj)
+
+	// Paused resources keep their finalizer so they are only removed from the
+	// remote system once resumed
+	if deleting && paused {
+		logger.Info(s.Kind + " is paused, deferring removal until resumed")
+		return ctrl.Result{}, nil
+	}
+
+	// Read-only controllers never hold the finalizer, another controller removes the resource
+	if deleting && s.ReadOnly {
+		logger.Info(s.Kind + " is being deleted, leaving its removal to the controller holding the finalizer")
+		return ctrl.Result{}, nil
+	}
+
+	if !deleting && s.Selector != nil && !s.Selector.Matches(labels.Set(obj.GetLabels())) {
+		logger.V(1).Info(s.Kind + " does not match the selector, skipping sync")
+		return ctrl.Result{}, nil
+	}
+
+	// Resources synced by another controller version wait for their re-push slot
+	if !deleting && !paused && !s.ReadOnly {
+		if delay := s.Pacer.Wait(obj); delay > 0 {
+			logger.V(1).Info(s.Kind+" was synced by another controller version, deferring re-push",
+				"delay", delay)
+			return ctrl.Result{RequeueAfter: delay}, nil
+		}
+	}
+
+	timeout := s.Adapter.SyncTimeout(obj)
+	syncCtx, cancel := WithSyncTimeout(ctx, timeout)
+	defer cancel()
+
+	state := &SyncState[T]{
+		Object:         obj,
+		Original:       original,
+		Reconciliation: reconciliation,
+		Timeout:        timeout,
+		SyncContext:    syncCtx,
+		ReadOnly:       s.ReadOnly,
+		Resync:         !deleting && !s.ReadOnly && s.Resyncs.Due(obj, s.ResyncInterval),
+	}
+
+	var remote clients.AwarenessClient
+	if s.ResolveBeforeFinalizer && !paused {
+		if remote, err = s.Adapter.Resolve(syncCtx, state); err != nil {
+			return s.report(ctx, state, SyncOutcome[P]{Stage: SyncStageResolve, Err: err})
+		}
+	}
+
+	if deleting {
+		return s.delete(ctx, state, remote)
+	}
+	if !s.ReadOnly && (remote != nil || !s.ResolveBeforeFinalizer) {
+		if err := AddFinalizer(ctx, s.Client, obj, s.Finalizer); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	payload, err := s.Adapter.Render(ctx, state)
+	if err != nil {
+		return s.report(ctx, state, SyncOutcome[P]{Stage: SyncStageRender, Err: err, Remote: remote})
+	}
+	if err := s.Adapter.Validate(ctx, state, payload); err != nil {
+		return s.report(ctx, state, SyncOutcome[P]{
+			Stage: SyncStageValidate, Err: err, Payload: payload, Remote: remote,
+		})
+	}
+
+	// Paused resources are validated but nothing is pushed
+	if paused {
+		logger.Info(s.Kind + " is paused, skipping sync")
+		return s.report(ctx, state, SyncOutcome[P]{Stage: SyncStagePaused, Payload: payload})
+	}
+
+	if remote == nil {
+		if remote, err = s.Adapter.Resolve(syncCtx, state); err != nil {
+			return s.report(ctx, state, SyncOutcome[P]{Stage: SyncStageResolve, Err: err, Payload: payload})
+		}
+	}
+	if s.ReadOnly {
+		return s.diff(ctx, state, remote, payload)
+	}
+	if err := s.Adapter.Push(syncCtx, state, remote, payload); err != nil {
+		return s.report(ctx, state, SyncOutcome[P]{
+			Stage: SyncStagePush, Err: err, Payload: payload, Remote: remote,
+		})
+	}
+	result, err = s.report(ctx, state, SyncOutcome[P]{Stage: SyncStageSynced, Payload: payload, Remote: remote})
+	if err != nil {
+		return result, err
+	}
+	if err := s.Pacer.Done(ctx, s.Client, obj); err != nil {
+		return ctrl.Result{}, err
+	}
+	if state.Resync {
+		s.Resyncs.Done(obj)
+	}
+	if err := SetRequeueOnStart(ctx, s.Client, obj, false); err != nil {
+		return ctrl.Result{}, err
+	}
+	if result.IsZero() && s.ResyncInterval > 0 {
+		result.RequeueAfter = s.ResyncInterval
+	}
+	return result, nil
+}
+
+// diff reports the changes pushing the payload would make as SyncStageDiff, and requeues the
+// resource after ResyncInterval to diff it again.
+func (s *SyncReconciler[T, P]) diff(
+	ctx context.Context,
+	state *SyncState[T],
+	remote clients.AwarenessClient,
+	payload P,
+) (ctrl.Result, error) {
+	diff, err := s.Adapter.Diff(state.SyncContext, state, remote, payload)
+	result, err := s.report(ctx, state, SyncOutcome[P]{
+		Stage: SyncStageDiff, Err: err, Payload: payload, Remote: remote, Diff: diff,
+	})
+	if err == nil && result.IsZero() && s.ResyncInterval > 0 {
+		result.RequeueAfter = s.ResyncInterval
+	}
+	return result, err
+}
+
+// delete removes a resource being deleted from the remote system and releases its finalizer,
+// unless the adapter keeps it by reporting an error or requeue for SyncStageDelete.
+// Without ResolveBeforeFinalizer the client is resolved here, its failures are reported
+// as SyncStageDelete.
+func (s *SyncReconciler[T, P]) delete(
+	ctx context.Context,
+	state *SyncState[T],
+	remote clients.AwarenessClient,
+) (ctrl.Result, error) {
+	obj := state.Object
+	s.Pacer.Forget(obj)
+	s.Resyncs.Forget(k8sClient.ObjectKeyFromObject(obj))
+	if !controllerutil.ContainsFinalizer(obj, s.Finalizer) {
+		return ctrl.Result{}, nil
+	}
+
+	var err error
+	if remote == nil {
+		remote, err = s.Adapter.Resolve(state.SyncContext, state)
+	}
+	if err == nil {
+		err = s.Adapter.Delete(state.SyncContext, state, remote)
+	}
+	result, err := s.report(ctx, state, SyncOutcome[P]{Stage: SyncStageDelete, Err: err, Remote: remote})
+	if err != nil || !result.IsZero() {
+		return result, err
+	}
+
+	if err := RemoveFinalizer(ctx, s.Client, obj, s.Finalizer); err != nil {
+		return ctrl.Result{}, err
+	}
+	log.FromContext(ctx).Info(s.Kind + " was deleted")
+	return ctrl.Result{}, nil
+}
+
+// report passes the outcome to the Adapter, logging its stage as operation. Failures after the
+// shutdown grace period ended are reported wrapping ErrSyncInterrupted and the resource is marked
+// with the RequeueOnStartAnnotation, both within ShutdownMarkTimeout as the context is cancelled
+// already. Read-only resources are not marked.
+func (s *SyncReconciler[T, P]) report(
+	ctx context.Context,
+	state *SyncState[T],
+	outcome SyncOutcome[P],
+) (ctrl.Result, error) {
+	ctx = log.IntoContext(ctx, log.FromContext(ctx).WithValues(logging.KeyOperation, outcome.Stage))
+	if outcome.Err == nil || ctx.Err() == nil {
+		return s.Adapter.Report(ctx, state, outcome)
+	}
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), ShutdownMarkTimeout)
+	defer cancel()
+	obj := state.Object
+	log.FromContext(ctx).Info(s.Kind+" sync was interrupted by the shutdown, it is retried on start",
+		"error", outcome.Err.Error())
+	outcome.Err = fmt.Errorf("%w: %w", ErrSyncInterrupted, outcome.Err)
+	if state.ReadOnly {
+		return s.Adapter.Report(ctx, state, outcome)
+	}
+	if err := SetRequeueOnStart(ctx, s.Client, obj, true); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to mark "+s.Kind+" for requeue on start")
+	}
+	return s.Adapter.Report(ctx, state, outcome)
+}
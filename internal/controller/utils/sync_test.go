@@ -0,0 +1,334 @@
+//nolint:revive // utils is a standard package name for utilities
+package utils
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	openawarenessv1beta1 "github.com/syndlex/openawareness-controller/api/openawareness/v1beta1"
+	"github.com/syndlex/openawareness-controller/internal/clients"
+	"github.com/syndlex/openawareness-controller/internal/metrics"
+)
+
+// recordingAdapter records the stages SyncReconciler runs and fails the configured stage.
+type recordingAdapter struct {
+	failStage SyncStage
+	// keepFinalizer makes Report fail deletions
+	keepFinalizer bool
+	stages        []SyncStage
+}
+
+func (a *recordingAdapter) step(stage SyncStage) error {
+	a.stages = append(a.stages, stage)
+	if stage == a.failStage {
+		return errors.New(string(stage) + " failed")
+	}
+	return nil
+}
+
+func (a *recordingAdapter) NewObject() *openawarenessv1beta1.MimirAlertTenant {
+	return &openawarenessv1beta1.MimirAlertTenant{}
+}
+
+func (a *recordingAdapter) SyncTimeout(_ *openawarenessv1beta1.MimirAlertTenant) time.Duration {
+	return time.Second
+}
+
+func (a *recordingAdapter) Resolve(
+	_ context.Context,
+	_ *SyncState[*openawarenessv1beta1.MimirAlertTenant],
+) (clients.AwarenessClient, error) {
+	if err := a.step(SyncStageResolve); err != nil {
+		return nil, err
+	}
+	return clients.NewMockAwarenessClient(), nil
+}
+
+func (a *recordingAdapter) Render(
+	_ context.Context,
+	_ *SyncState[*openawarenessv1beta1.MimirAlertTenant],
+) (string, error) {
+	return "config", a.step(SyncStageRender)
+}
+
+func (a *recordingAdapter) Validate(
+	_ context.Context,
+	_ *SyncState[*openawarenessv1beta1.MimirAlertTenant],
+	_ string,
+) error {
+	return a.step(SyncStageValidate)
+}
+
+func (a *recordingAdapter) Push(
+	_ context.Context,
+	_ *SyncState[*openawarenessv1beta1.MimirAlertTenant],
+	_ clients.AwarenessClient,
+	_ string,
+) error {
+	return a.step(SyncStagePush)
+}
+
+func (a *recordingAdapter) Diff(
+	_ context.Context,
+	_ *SyncState[*openawarenessv1beta1.MimirAlertTenant],
+	_ clients.AwarenessClient,
+	_ string,
+) (string, error) {
+	return "", a.step(SyncStageDiff)
+}
+
+func (a *recordingAdapter) Delete(
+	_ context.Context,
+	_ *SyncState[*openawarenessv1beta1.MimirAlertTenant],
+	_ clients.AwarenessClient,
+) error {
+	return a.step(SyncStageDelete)
+}
+
+func (a *recordingAdapter) Report(
+	_ context.Context,
+	_ *SyncState[*openawarenessv1beta1.MimirAlertTenant],
+	outcome SyncOutcome[string],
+) (ctrl.Result, error) {
+	if outcome.Stage == SyncStageSynced || outcome.Stage == SyncStagePaused {
+		a.stages = append(a.stages, outcome.Stage)
+	}
+	if outcome.Stage == SyncStageDelete && a.keepFinalizer {
+		return ctrl.Result{}, errors.New("deletion failed")
+	}
+	return ctrl.Result{}, outcome.Err
+}
+
+func TestSyncReconciler(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := openawarenessv1beta1.AddToScheme(scheme); err != nil {
+		t.Fatalf("adding scheme: %v", err)
+	}
+	key := types.NamespacedName{Name: "tenant", Namespace: "team"}
+	deleted := metav1.Now()
+
+	tests := []struct {
+		name                   string
+		annotations            map[string]string
+		labels                 map[string]string
+		selector               labels.Selector
+		resyncInterval         time.Duration
+		deleting               bool
+		failStage              SyncStage
+		keepFinalizer          bool
+		resolveBeforeFinalizer bool
+		shutdown               bool
+		readOnly               bool
+		wantStages             []SyncStage
+		wantErr                bool
+		wantInterrupted        bool
+		wantFinalizer          bool
+		wantRequeueOnStart     bool
+		wantRequeueAfter       time.Duration
+	}{
+		{
+			name:          "syncs the rendered payload",
+			wantStages:    []SyncStage{SyncStageRender, SyncStageValidate, SyncStageResolve, SyncStagePush, SyncStageSynced},
+			wantFinalizer: true,
+		},
+		{
+			name:          "invalid payload is not pushed",
+			failStage:     SyncStageValidate,
+			wantStages:    []SyncStage{SyncStageRender, SyncStageValidate},
+			wantErr:       true,
+			wantFinalizer: true,
+		},
+		{
+			name:          "paused resource is validated without resolving the client",
+			annotations:   map[string]string{PausedAnnotation: "true"},
+			wantStages:    []SyncStage{SyncStageRender, SyncStageValidate, SyncStagePaused},
+			wantFinalizer: true,
+		},
+		{
+			name:                   "finalizer waits for the client when resolving first",
+			failStage:              SyncStageResolve,
+			resolveBeforeFinalizer: true,
+			wantStages:             []SyncStage{SyncStageResolve},
+			wantErr:                true,
+		},
+		{
+			name:       "deletion removes the resource and releases the finalizer",
+			deleting:   true,
+			wantStages: []SyncStage{SyncStageResolve, SyncStageDelete},
+		},
+		{
+			name:          "failed deletion reported as error keeps the finalizer",
+			deleting:      true,
+			keepFinalizer: true,
+			wantStages:    []SyncStage{SyncStageResolve, SyncStageDelete},
+			wantErr:       true,
+			wantFinalizer: true,
+		},
+		{
+			name:             "synced resource is pushed again after the resync interval",
+			resyncInterval:   time.Hour,
+			wantStages:       []SyncStage{SyncStageRender, SyncStageValidate, SyncStageResolve, SyncStagePush, SyncStageSynced},
+			wantFinalizer:    true,
+			wantRequeueAfter: time.Hour,
+		},
+		{
+			name:     "resource not matching the selector is not synced",
+			labels:   map[string]string{"team": "b"},
+			selector: labels.SelectorFromSet(labels.Set{"team": "a"}),
+		},
+		{
+			name:          "resource matching the selector is synced",
+			labels:        map[string]string{"team": "a"},
+			selector:      labels.SelectorFromSet(labels.Set{"team": "a"}),
+			wantStages:    []SyncStage{SyncStageRender, SyncStageValidate, SyncStageResolve, SyncStagePush, SyncStageSynced},
+			wantFinalizer: true,
+		},
+		{
+			name:       "deleted resource not matching the selector is removed",
+			selector:   labels.SelectorFromSet(labels.Set{"team": "a"}),
+			deleting:   true,
+			wantStages: []SyncStage{SyncStageResolve, SyncStageDelete},
+		},
+		{
+			name:               "push failing after the shutdown marks the resource for requeue on start",
+			shutdown:           true,
+			failStage:          SyncStagePush,
+			wantStages:         []SyncStage{SyncStageRender, SyncStageValidate, SyncStageResolve, SyncStagePush},
+			wantErr:            true,
+			wantInterrupted:    true,
+			wantFinalizer:      true,
+			wantRequeueOnStart: true,
+		},
+		{
+			name:          "successful sync removes the requeue on start mark",
+			annotations:   map[string]string{RequeueOnStartAnnotation: "true"},
+			wantStages:    []SyncStage{SyncStageRender, SyncStageValidate, SyncStageResolve, SyncStagePush, SyncStageSynced},
+			wantFinalizer: true,
+		},
+		{
+			name:          "paused resource is not removed",
+			annotations:   map[string]string{PausedAnnotation: "true"},
+			deleting:      true,
+			wantFinalizer: true,
+		},
+		{
+			name:             "read-only resource is diffed instead of pushed without finalizer",
+			readOnly:         true,
+			resyncInterval:   time.Hour,
+			wantStages:       []SyncStage{SyncStageRender, SyncStageValidate, SyncStageResolve, SyncStageDiff},
+			wantRequeueAfter: time.Hour,
+		},
+		{
+			name:          "read-only resource being deleted is left to the finalizer",
+			readOnly:      true,
+			deleting:      true,
+			wantFinalizer: true,
+		},
+		{
+			name:            "read-only diff failing after the shutdown is not marked for requeue on start",
+			readOnly:        true,
+			shutdown:        true,
+			failStage:       SyncStageDiff,
+			wantStages:      []SyncStage{SyncStageRender, SyncStageValidate, SyncStageResolve, SyncStageDiff},
+			wantErr:         true,
+			wantInterrupted: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tenant := &openawarenessv1beta1.MimirAlertTenant{
+				ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace,
+					Annotations: tt.annotations, Labels: tt.labels},
+			}
+			if tt.deleting {
+				tenant.Finalizers = []string{FinalizerAnnotation}
+				tenant.DeletionTimestamp = &deleted
+			}
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(tenant).Build()
+			adapter := &recordingAdapter{failStage: tt.failStage, keepFinalizer: tt.keepFinalizer}
+			reconciler := &SyncReconciler[*openawarenessv1beta1.MimirAlertTenant, string]{
+				Client:                 c,
+				Adapter:                adapter,
+				Kind:                   metrics.KindMimirAlertTenant,
+				Finalizer:              FinalizerAnnotation,
+				ResolveBeforeFinalizer: tt.resolveBeforeFinalizer,
+				Selector:               tt.selector,
+				ResyncInterval:         tt.resyncInterval,
+				ReadOnly:               tt.readOnly,
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if tt.shutdown {
+				cancel()
+			}
+			result, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Reconcile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if errors.Is(err, ErrSyncInterrupted) != tt.wantInterrupted {
+				t.Errorf("Reconcile() error = %v, want interrupted %v", err, tt.wantInterrupted)
+			}
+			if result.RequeueAfter != tt.wantRequeueAfter {
+				t.Errorf("RequeueAfter = %v, want %v", result.RequeueAfter, tt.wantRequeueAfter)
+			}
+			if !reflect.DeepEqual(adapter.stages, tt.wantStages) {
+				t.Errorf("stages = %v, want %v", adapter.stages, tt.wantStages)
+			}
+
+			latest := &openawarenessv1beta1.MimirAlertTenant{}
+			if err := c.Get(context.Background(), key, latest); err != nil {
+				// The fake client removes deleted objects once their finalizers are released
+				if tt.wantFinalizer {
+					t.Fatalf("get: %v", err)
+				}
+				return
+			}
+			if got := controllerutil.ContainsFinalizer(latest, FinalizerAnnotation); got != tt.wantFinalizer {
+				t.Errorf("finalizer present = %v, want %v", got, tt.wantFinalizer)
+			}
+			if got := RequeueOnStart(latest); got != tt.wantRequeueOnStart {
+				t.Errorf("requeue on start = %v, want %v", got, tt.wantRequeueOnStart)
+			}
+		})
+	}
+}
+
+func TestSyncReconcilerForgetsMissingResources(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := openawarenessv1beta1.AddToScheme(scheme); err != nil {
+		t.Fatalf("adding scheme: %v", err)
+	}
+	tenant := &openawarenessv1beta1.MimirAlertTenant{
+		ObjectMeta: metav1.ObjectMeta{Name: "tenant", Namespace: "team"},
+	}
+	resyncs := &ResyncTracker{}
+	resyncs.Done(tenant)
+	reconciler := &SyncReconciler[*openawarenessv1beta1.MimirAlertTenant, string]{
+		Client:  fake.NewClientBuilder().WithScheme(scheme).Build(),
+		Adapter: &recordingAdapter{},
+		Kind:    metrics.KindMimirAlertTenant,
+		Resyncs: resyncs,
+	}
+
+	// The tenant was deleted without passing the delete path, e.g. without finalizer
+	if _, err := reconciler.Reconcile(context.Background(),
+		ctrl.Request{NamespacedName: types.NamespacedName{Name: "tenant", Namespace: "team"}}); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if len(resyncs.last) != 0 {
+		t.Errorf("expected the missing tenant to be forgotten, still tracking %v", resyncs.last)
+	}
+}
@@ -4,14 +4,90 @@ package utils
 import (
 	"bytes"
 	"fmt"
+	"strings"
 	"text/template"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// globalKeyPrefix and metaKeyPrefix mark built-in values inside templateData. Kubernetes
+// ConfigMap and Secret keys cannot contain NUL, so built-in values never collide with
+// template variables.
+const (
+	globalKeyPrefix = "\x00global."
+	metaKeyPrefix   = "\x00meta."
 )
 
+// templateData holds the variables passed to a template. It is a map so that missing
+// variables render as empty strings; controller-wide global values are exposed through
+// the Global method as [[ .Global.NAME ]] and metadata about the rendered resource
+// through the Meta method as [[ .Meta.FIELD ]].
+type templateData map[string]string
+
+// Global returns the controller-wide global values of the template data.
+func (d templateData) Global() map[string]string {
+	return d.withPrefix(globalKeyPrefix)
+}
+
+// Meta returns the metadata of the rendered resource, keyed by TemplateMetadata field name.
+func (d templateData) Meta() map[string]string {
+	return d.withPrefix(metaKeyPrefix)
+}
+
+// withPrefix returns the values whose keys start with prefix, with the prefix removed.
+func (d templateData) withPrefix(prefix string) map[string]string {
+	values := make(map[string]string)
+	for k, v := range d {
+		if name, ok := strings.CutPrefix(k, prefix); ok {
+			values[name] = v
+		}
+	}
+	return values
+}
+
+// TemplateMetadata describes the resource a template is rendered for.
+// Its fields are available in templates as [[ .Meta.Namespace ]], [[ .Meta.Name ]],
+// [[ .Meta.Tenant ]], [[ .Meta.ClientName ]] and [[ .Meta.Cluster ]].
+type TemplateMetadata struct {
+	Namespace  string
+	Name       string
+	Tenant     string
+	ClientName string
+	Cluster    string
+}
+
+// NewTemplateMetadata returns the template metadata of obj. The tenant is resolved
+// like for Mimir API calls, falling back to DefaultTenantID. Templates are rendered before
+// the ClientConfig is read, so tenant aliases are not resolved.
+func NewTemplateMetadata(obj metav1.Object, cluster string) TemplateMetadata {
+	return TemplateMetadata{
+		Namespace:  obj.GetNamespace(),
+		Name:       obj.GetName(),
+		Tenant:     GetTenantID(obj, nil),
+		ClientName: obj.GetAnnotations()[ClientNameAnnotation],
+		Cluster:    cluster,
+	}
+}
+
+// TemplateBuiltins holds the values exposed to a template besides its variables.
+type TemplateBuiltins struct {
+	// Global holds controller-wide values, available as [[ .Global.NAME ]]
+	Global map[string]string
+	// Meta describes the rendered resource, available as [[ .Meta.FIELD ]]
+	Meta TemplateMetadata
+}
+
 // RenderTemplate processes the input string as a Go template with the provided data.
 // Uses [[ ]] delimiters instead of {{ }} to avoid conflicts with Alertmanager templates.
 // Supports the "default" function for fallback values: [[ .VAR | default "fallback" ]]
 // Returns the rendered string or an error if template parsing or execution fails.
 func RenderTemplate(templateStr string, data map[string]string) (string, error) {
+	return RenderTemplateWithBuiltins(templateStr, data, TemplateBuiltins{})
+}
+
+// RenderTemplateWithBuiltins renders a template like RenderTemplate and additionally
+// exposes the given global values and resource metadata.
+func RenderTemplateWithBuiltins(templateStr string, data map[string]string, builtins TemplateBuiltins) (string, error) {
 	// Create template with custom delimiters [[ ]] and custom functions
 	tmpl, err := template.New("config").
 		Delims("[[", "]]").
@@ -24,9 +100,23 @@ func RenderTemplate(templateStr string, data map[string]string) (string, error)
 		return "", fmt.Errorf("failed to parse template: %w", err)
 	}
 
+	values := make(templateData, len(data)+len(builtins.Global)+5)
+	for k, v := range data {
+		values[k] = v
+	}
+	for k, v := range builtins.Global {
+		values[globalKeyPrefix+k] = v
+	}
+	meta := builtins.Meta
+	values[metaKeyPrefix+"Namespace"] = meta.Namespace
+	values[metaKeyPrefix+"Name"] = meta.Name
+	values[metaKeyPrefix+"Tenant"] = meta.Tenant
+	values[metaKeyPrefix+"ClientName"] = meta.ClientName
+	values[metaKeyPrefix+"Cluster"] = meta.Cluster
+
 	// Execute template
 	var buf bytes.Buffer
-	if err := tmpl.Execute(&buf, data); err != nil {
+	if err := tmpl.Execute(&buf, values); err != nil {
 		return "", fmt.Errorf("failed to execute template: %w", err)
 	}
 
@@ -5,6 +5,7 @@ import (
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func TestTemplate(t *testing.T) {
@@ -245,4 +246,84 @@ receivers:
 			Expect(result).To(ContainSubstring("another: another-default"))
 		})
 	})
+
+	Context("Global values", func() {
+		It("should expose global values under .Global", func() {
+			template := "cluster: [[ .Global.CLUSTER ]], team: [[ .TEAM ]]"
+
+			result, err := RenderTemplateWithBuiltins(template,
+				map[string]string{"TEAM": "payments"},
+				TemplateBuiltins{Global: map[string]string{"CLUSTER": "eu-1"}})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal("cluster: eu-1, team: payments"))
+		})
+
+		It("should render missing global values as empty and support defaults", func() {
+			template := "[[ .Global.MISSING ]]|[[ .Global.ENV | default \"dev\" ]]"
+
+			result, err := RenderTemplateWithBuiltins(template, nil, TemplateBuiltins{})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal("|dev"))
+		})
+
+		It("should not expose global values as top-level variables", func() {
+			template := "[[ .CLUSTER ]]"
+
+			result, err := RenderTemplateWithBuiltins(template, nil,
+				TemplateBuiltins{Global: map[string]string{"CLUSTER": "eu-1"}})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(BeEmpty())
+		})
+	})
+
+	Context("Resource metadata", func() {
+		It("should expose resource metadata under .Meta", func() {
+			template := "[[ .Meta.Cluster ]]/[[ .Meta.Namespace ]]/[[ .Meta.Name ]] " +
+				"tenant=[[ .Meta.Tenant ]] client=[[ .Meta.ClientName ]]"
+			meta := TemplateMetadata{
+				Namespace:  "payments",
+				Name:       "alerts",
+				Tenant:     "team-a",
+				ClientName: "mimir",
+				Cluster:    "eu-1",
+			}
+
+			result, err := RenderTemplateWithBuiltins(template, nil, TemplateBuiltins{Meta: meta})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal("eu-1/payments/alerts tenant=team-a client=mimir"))
+		})
+
+		It("should support defaults for empty metadata", func() {
+			template := "[[ .Meta.Cluster | default \"local\" ]]"
+
+			result, err := RenderTemplateWithBuiltins(template, nil, TemplateBuiltins{})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal("local"))
+		})
+
+		It("should build metadata from an object", func() {
+			obj := &metav1.ObjectMeta{
+				Name:      "alerts",
+				Namespace: "payments",
+				Annotations: map[string]string{
+					ClientNameAnnotation:  "mimir",
+					MimirTenantAnnotation: "team-a",
+				},
+			}
+
+			Expect(NewTemplateMetadata(obj, "eu-1")).To(Equal(TemplateMetadata{
+				Namespace:  "payments",
+				Name:       "alerts",
+				Tenant:     "team-a",
+				ClientName: "mimir",
+				Cluster:    "eu-1",
+			}))
+			Expect(NewTemplateMetadata(&metav1.ObjectMeta{Name: "x"}, "").Tenant).To(Equal(DefaultTenantID))
+		})
+	})
 })
@@ -0,0 +1,64 @@
+//nolint:revive // utils is a standard package name for utilities
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+
+	openawarenessv1beta1 "github.com/syndlex/openawareness-controller/api/openawareness/v1beta1"
+)
+
+// ErrTemplateSource is returned when the template files of a templateSource cannot be fetched
+var ErrTemplateSource = errors.New("cannot fetch templateSource")
+
+// TemplateSourceFetcher fetches the template files of a templateSource.
+type TemplateSourceFetcher interface {
+	// Fetch returns the template files by name, credentials are read in namespace
+	Fetch(ctx context.Context, namespace string, source *openawarenessv1beta1.TemplateSource) (map[string]string, error)
+}
+
+// ResolveTemplateSources returns a chain returned by ResolveExtends with the templateFiles of
+// every tenant setting templateSource replaced by the fetched files, overridden by the
+// templateFiles of the tenant. These tenants are copied, the others are returned unchanged.
+// Returns an error wrapping ErrTemplateSource if fetcher is nil or fails.
+func ResolveTemplateSources(
+	ctx context.Context,
+	fetcher TemplateSourceFetcher,
+	chain []*openawarenessv1beta1.MimirAlertTenant,
+) ([]*openawarenessv1beta1.MimirAlertTenant, error) {
+	resolved := make([]*openawarenessv1beta1.MimirAlertTenant, 0, len(chain))
+	for _, tenant := range chain {
+		if tenant.Spec.TemplateSource == nil {
+			resolved = append(resolved, tenant)
+			continue
+		}
+		if fetcher == nil {
+			return nil, fmt.Errorf("%w of %s: template sources are not supported here", ErrTemplateSource, tenant.Name)
+		}
+		files, err := fetcher.Fetch(ctx, tenant.Namespace, tenant.Spec.TemplateSource)
+		if err != nil {
+			return nil, fmt.Errorf("%w of %s: %w", ErrTemplateSource, tenant.Name, err)
+		}
+		tenant = tenant.DeepCopy()
+		files = maps.Clone(files)
+		if files == nil {
+			files = map[string]string{}
+		}
+		maps.Copy(files, tenant.Spec.TemplateFiles)
+		tenant.Spec.TemplateFiles = files
+		resolved = append(resolved, tenant)
+	}
+	return resolved, nil
+}
+
+// UsesTemplateSource reports whether a tenant of the chain sets templateSource.
+func UsesTemplateSource(chain []*openawarenessv1beta1.MimirAlertTenant) bool {
+	for _, tenant := range chain {
+		if tenant.Spec.TemplateSource != nil {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,63 @@
+//nolint:revive // utils is a standard package name for utilities
+package utils
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	openawarenessv1beta1 "github.com/syndlex/openawareness-controller/api/openawareness/v1beta1"
+)
+
+// staticTemplateSource returns the same files for every source, or err
+type staticTemplateSource struct {
+	files map[string]string
+	err   error
+}
+
+func (s staticTemplateSource) Fetch(
+	_ context.Context,
+	_ string,
+	_ *openawarenessv1beta1.TemplateSource,
+) (map[string]string, error) {
+	return s.files, s.err
+}
+
+func TestResolveTemplateSources(t *testing.T) {
+	root := extendingTenant("root", "", "route: {}")
+	tenant := extendingTenant("oncall", "root", "")
+	tenant.Spec.TemplateSource = &openawarenessv1beta1.TemplateSource{
+		OCI: &openawarenessv1beta1.OCITemplateSource{Reference: "registry.example.com/templates:v1"},
+	}
+	tenant.Spec.TemplateFiles = map[string]string{"slack.tmpl": "inline"}
+	chain := []*openawarenessv1beta1.MimirAlertTenant{root, tenant}
+	fetcher := staticTemplateSource{files: map[string]string{"slack.tmpl": "fetched", "email.tmpl": "fetched"}}
+	ctx := context.Background()
+
+	resolved, err := ResolveTemplateSources(ctx, fetcher, chain)
+	if err != nil {
+		t.Fatalf("ResolveTemplateSources() error = %v", err)
+	}
+	if resolved[0] != root {
+		t.Errorf("expected tenants without templateSource to be returned unchanged")
+	}
+	want := map[string]string{"slack.tmpl": "inline", "email.tmpl": "fetched"}
+	if !reflect.DeepEqual(resolved[1].Spec.TemplateFiles, want) {
+		t.Errorf("expected inline files to override fetched files, got %v", resolved[1].Spec.TemplateFiles)
+	}
+	if len(tenant.Spec.TemplateFiles) != 1 || len(fetcher.files) != 2 {
+		t.Errorf("expected the tenant and the fetched files not to be changed")
+	}
+	if !UsesTemplateSource(chain) || UsesTemplateSource(chain[:1]) {
+		t.Errorf("expected only the chain with the tenant to use a template source")
+	}
+
+	if _, err := ResolveTemplateSources(ctx, nil, chain); !errors.Is(err, ErrTemplateSource) {
+		t.Errorf("expected a template source error without fetcher, got %v", err)
+	}
+	failing := staticTemplateSource{err: errors.New("unavailable")}
+	if _, err := ResolveTemplateSources(ctx, failing, chain); !errors.Is(err, ErrTemplateSource) {
+		t.Errorf("expected a template source error, got %v", err)
+	}
+}
@@ -0,0 +1,163 @@
+//nolint:revive // utils is a standard package name for utilities
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	k8sClient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	openawarenessv1beta1 "github.com/syndlex/openawareness-controller/api/openawareness/v1beta1"
+)
+
+// ErrTenantMappingConflict is returned when several TenantMappings select the same namespace
+var ErrTenantMappingConflict = errors.New("multiple TenantMappings")
+
+// TenantMappings holds the TenantMappings and the labels of the namespaces they select from,
+// to map many resources without reading them again per resource.
+type TenantMappings struct {
+	mappings        []openawarenessv1beta1.TenantMapping
+	namespaceLabels map[string]map[string]string
+}
+
+// ListTenantMappings lists the TenantMappings and, if any exist, the namespaces.
+func ListTenantMappings(ctx context.Context, reader k8sClient.Reader) (*TenantMappings, error) {
+	mappingList := &openawarenessv1beta1.TenantMappingList{}
+	if err := reader.List(ctx, mappingList); err != nil {
+		return nil, fmt.Errorf("listing TenantMappings: %w", err)
+	}
+	mappings := &TenantMappings{mappings: mappingList.Items}
+	if len(mappingList.Items) == 0 {
+		return mappings, nil
+	}
+
+	namespaces := &corev1.NamespaceList{}
+	if err := reader.List(ctx, namespaces); err != nil {
+		return nil, fmt.Errorf("listing namespaces: %w", err)
+	}
+	mappings.namespaceLabels = make(map[string]map[string]string, len(namespaces.Items))
+	for _, namespace := range namespaces.Items {
+		mappings.namespaceLabels[namespace.Name] = namespace.Labels
+	}
+	return mappings, nil
+}
+
+// TenantMappingFor returns the TenantMapping selecting namespace, nil if none does.
+// Returns ErrTenantMappingConflict if several TenantMappings select it.
+func TenantMappingFor(
+	ctx context.Context,
+	reader k8sClient.Reader,
+	namespace string,
+) (*openawarenessv1beta1.TenantMapping, error) {
+	mappingList := &openawarenessv1beta1.TenantMappingList{}
+	if err := reader.List(ctx, mappingList); err != nil {
+		return nil, fmt.Errorf("listing TenantMappings: %w", err)
+	}
+	if len(mappingList.Items) == 0 {
+		return nil, nil
+	}
+
+	ns := &corev1.Namespace{}
+	if err := reader.Get(ctx, k8sClient.ObjectKey{Name: namespace}, ns); err != nil {
+		return nil, fmt.Errorf("getting namespace %s: %w", namespace, err)
+	}
+	mappings := &TenantMappings{
+		mappings:        mappingList.Items,
+		namespaceLabels: map[string]map[string]string{namespace: ns.Labels},
+	}
+	return mappings.For(namespace)
+}
+
+// For returns the TenantMapping selecting namespace, nil if none does or mappings is nil.
+// Returns ErrTenantMappingConflict if several TenantMappings select it.
+func (m *TenantMappings) For(namespace string) (*openawarenessv1beta1.TenantMapping, error) {
+	if m == nil {
+		return nil, nil
+	}
+	namespaceLabels, ok := m.namespaceLabels[namespace]
+	if !ok {
+		return nil, nil
+	}
+
+	var matches []*openawarenessv1beta1.TenantMapping
+	for i := range m.mappings {
+		mapping := &m.mappings[i]
+		if !mapping.DeletionTimestamp.IsZero() {
+			continue
+		}
+		selector, err := metav1.LabelSelectorAsSelector(&mapping.Spec.NamespaceSelector)
+		if err != nil {
+			continue
+		}
+		if selector.Matches(labels.Set(namespaceLabels)) {
+			matches = append(matches, mapping)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return nil, nil
+	case 1:
+		return matches[0], nil
+	default:
+		names := make([]string, 0, len(matches))
+		for _, mapping := range matches {
+			names = append(names, mapping.Name)
+		}
+		return nil, fmt.Errorf("%w select namespace %s: %s", ErrTenantMappingConflict, namespace, strings.Join(names, ", "))
+	}
+}
+
+// References reports whether any of the mappings references clientConfig.
+func (m *TenantMappings) References(clientConfig *openawarenessv1beta1.ClientConfig) bool {
+	if m == nil {
+		return false
+	}
+	for i := range m.mappings {
+		ref := m.mappings[i].Spec.ClientConfig
+		if ref != nil && ref.Namespace == clientConfig.Namespace && ref.Name == clientConfig.Name {
+			return true
+		}
+	}
+	return false
+}
+
+// applyTenantMapping returns clientConfig with the tenant of mapping as default tenant, a copy
+// so cached ClientConfigs are not modified. Returns clientConfig unchanged without mapping
+// tenant.
+func applyTenantMapping(
+	clientConfig *openawarenessv1beta1.ClientConfig,
+	mapping *openawarenessv1beta1.TenantMapping,
+) *openawarenessv1beta1.ClientConfig {
+	if clientConfig == nil || mapping == nil || mapping.Spec.Tenant == "" {
+		return clientConfig
+	}
+	mapped := clientConfig.DeepCopy()
+	mapped.Spec.DefaultTenant = mapping.Spec.Tenant
+	return mapped
+}
+
+// ReferencedByTenantMapping reports whether any TenantMapping references clientConfig, which is
+// then used by the resources without ClientNameAnnotation in the selected namespaces.
+func ReferencedByTenantMapping(
+	ctx context.Context,
+	reader k8sClient.Reader,
+	clientConfig *openawarenessv1beta1.ClientConfig,
+) (bool, error) {
+	mappingList := &openawarenessv1beta1.TenantMappingList{}
+	if err := reader.List(ctx, mappingList); err != nil {
+		return false, fmt.Errorf("listing TenantMappings: %w", err)
+	}
+	mappings := &TenantMappings{mappings: mappingList.Items}
+	return mappings.References(clientConfig), nil
+}
+
+// UsesTenantMapping reports whether a TenantMapping may apply to obj, which lacks the
+// ClientNameAnnotation or the MimirTenantAnnotation.
+func UsesTenantMapping(obj k8sClient.Object) bool {
+	return obj.GetAnnotations()[ClientNameAnnotation] == "" || obj.GetAnnotations()[MimirTenantAnnotation] == ""
+}
@@ -0,0 +1,139 @@
+//nolint:revive // utils is a standard package name for utilities
+package utils
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	openawarenessv1beta1 "github.com/syndlex/openawareness-controller/api/openawareness/v1beta1"
+)
+
+func tenantMapping(name, team, tenant string, ref *openawarenessv1beta1.ClientConfigReference) *openawarenessv1beta1.TenantMapping {
+	return &openawarenessv1beta1.TenantMapping{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: openawarenessv1beta1.TenantMappingSpec{
+			NamespaceSelector: metav1.LabelSelector{MatchLabels: map[string]string{"team": team}},
+			Tenant:            tenant,
+			ClientConfig:      ref,
+		},
+	}
+}
+
+func TestGetClientConfigWithTenantMapping(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatalf("adding scheme: %v", err)
+	}
+	if err := openawarenessv1beta1.AddToScheme(scheme); err != nil {
+		t.Fatalf("adding scheme: %v", err)
+	}
+
+	clientConfig := func(namespace, name string, scope openawarenessv1beta1.DefaultScope) client.Object {
+		return &openawarenessv1beta1.ClientConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Spec:       openawarenessv1beta1.ClientConfigSpec{Default: scope != "", DefaultScope: scope},
+		}
+	}
+
+	reader := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "payments", Labels: map[string]string{"team": "payments"}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "search", Labels: map[string]string{"team": "search"}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "shared", Labels: map[string]string{"team": "shared"}}},
+		clientConfig("payments", "local", ""),
+		clientConfig("search", "default", "Namespace"),
+		clientConfig("monitoring", "central", ""),
+		tenantMapping("payments", "payments", "payments-org",
+			&openawarenessv1beta1.ClientConfigReference{Namespace: "monitoring", Name: "central"}),
+		tenantMapping("search", "search", "search-org", nil),
+		tenantMapping("shared-a", "shared", "a", nil),
+		tenantMapping("shared-b", "shared", "b", nil),
+	).Build()
+
+	tests := []struct {
+		name           string
+		namespace      string
+		annotations    map[string]string
+		expectedClient string
+		expectedTenant string
+		expectError    error
+	}{
+		{
+			name:           "mapped ClientConfig and tenant",
+			namespace:      "payments",
+			expectedClient: "central",
+			expectedTenant: "payments-org",
+		},
+		{
+			name:           "annotations take precedence over the mapping",
+			namespace:      "payments",
+			annotations:    map[string]string{ClientNameAnnotation: "local", MimirTenantAnnotation: "explicit"},
+			expectedClient: "local",
+			expectedTenant: "explicit",
+		},
+		{
+			name:           "mapped tenant with the default ClientConfig",
+			namespace:      "search",
+			expectedClient: "default",
+			expectedTenant: "search-org",
+		},
+		{
+			name:        "conflicting mappings",
+			namespace:   "shared",
+			expectError: ErrTenantMappingConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			obj := &openawarenessv1beta1.MimirAlertTenant{ObjectMeta: metav1.ObjectMeta{
+				Name: "tenant", Namespace: tt.namespace, Annotations: tt.annotations,
+			}}
+
+			resolved, err := GetClientConfig(context.Background(), reader, obj)
+			if tt.expectError != nil {
+				if !errors.Is(err, tt.expectError) {
+					t.Errorf("expected error %v, got %v", tt.expectError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if resolved.Name != tt.expectedClient {
+				t.Errorf("GetClientConfig() = %s, want %s", resolved.Name, tt.expectedClient)
+			}
+			if got := GetTenantID(obj, resolved); got != tt.expectedTenant {
+				t.Errorf("GetTenantID() = %s, want %s", got, tt.expectedTenant)
+			}
+
+			clientConfigs := &openawarenessv1beta1.ClientConfigList{}
+			if err := reader.List(context.Background(), clientConfigs); err != nil {
+				t.Fatalf("listing ClientConfigs: %v", err)
+			}
+			mappings, err := ListTenantMappings(context.Background(), reader)
+			if err != nil {
+				t.Fatalf("ListTenantMappings: %v", err)
+			}
+			if got := ClientConfigFor(obj, clientConfigs.Items, mappings); got == nil ||
+				got.Name != tt.expectedClient || GetTenantID(obj, got) != tt.expectedTenant {
+				t.Errorf("ClientConfigFor() disagrees with GetClientConfig(): %v", got)
+			}
+		})
+	}
+
+	cached := &openawarenessv1beta1.ClientConfig{}
+	if err := reader.Get(context.Background(), client.ObjectKey{Namespace: "monitoring", Name: "central"}, cached); err != nil {
+		t.Fatalf("getting ClientConfig: %v", err)
+	}
+	if cached.Spec.DefaultTenant != "" {
+		t.Errorf("expected the mapped tenant not to be written to the ClientConfig, got %q", cached.Spec.DefaultTenant)
+	}
+}
@@ -0,0 +1,39 @@
+//nolint:revive // utils is a standard package name for utilities
+package utils
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DefaultSyncTimeout is the default timeout of the Mimir API operations of one reconciliation
+const DefaultSyncTimeout = 30 * time.Second
+
+// SyncTimeout returns the timeout of the Mimir API operations for obj: the duration of
+// SyncTimeoutAnnotation if set, defaultTimeout otherwise.
+// Returns defaultTimeout and an error if the annotation is not a valid non-negative duration.
+func SyncTimeout(obj metav1.Object, defaultTimeout time.Duration) (time.Duration, error) {
+	value, ok := obj.GetAnnotations()[SyncTimeoutAnnotation]
+	if !ok {
+		return defaultTimeout, nil
+	}
+	timeout, err := time.ParseDuration(value)
+	if err != nil || timeout < 0 {
+		return defaultTimeout, fmt.Errorf("invalid %s annotation %q, expected a non-negative duration such as 30s",
+			SyncTimeoutAnnotation, value)
+	}
+	return timeout, nil
+}
+
+// WithSyncTimeout returns a context whose deadline bounds the Mimir API operations of one
+// reconciliation, so a hung request cannot hold a reconcile worker indefinitely.
+// A timeout of zero leaves the context without deadline.
+func WithSyncTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
@@ -0,0 +1,62 @@
+//nolint:revive // utils is a standard package name for utilities
+package utils
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSyncTimeout(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		expected    time.Duration
+		expectError bool
+	}{
+		{name: "default without annotation", expected: time.Minute},
+		{name: "annotation", annotations: map[string]string{SyncTimeoutAnnotation: "10s"}, expected: 10 * time.Second},
+		{name: "disabled", annotations: map[string]string{SyncTimeoutAnnotation: "0s"}, expected: 0},
+		{
+			name:        "invalid annotation falls back to default",
+			annotations: map[string]string{SyncTimeoutAnnotation: "soon"},
+			expected:    time.Minute,
+			expectError: true,
+		},
+		{
+			name:        "negative annotation falls back to default",
+			annotations: map[string]string{SyncTimeoutAnnotation: "-1s"},
+			expected:    time.Minute,
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			obj := &metav1.ObjectMeta{Annotations: tt.annotations}
+			got, err := SyncTimeout(obj, time.Minute)
+			if (err != nil) != tt.expectError {
+				t.Errorf("SyncTimeout() error = %v, expectError %v", err, tt.expectError)
+			}
+			if got != tt.expected {
+				t.Errorf("SyncTimeout() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestWithSyncTimeout(t *testing.T) {
+	ctx, cancel := WithSyncTimeout(context.Background(), time.Minute)
+	defer cancel()
+	if deadline, ok := ctx.Deadline(); !ok || time.Until(deadline) > time.Minute {
+		t.Errorf("expected a deadline within a minute, got %v (set: %v)", deadline, ok)
+	}
+
+	ctx, cancel = WithSyncTimeout(context.Background(), 0)
+	defer cancel()
+	if _, ok := ctx.Deadline(); ok {
+		t.Error("expected no deadline for a zero timeout")
+	}
+}
@@ -0,0 +1,11 @@
+//nolint:revive // utils is a standard package name for utilities
+package utils
+
+const (
+	// DefaultPrometheusRuleWorkers is the default number of PrometheusRules reconciled in parallel
+	DefaultPrometheusRuleWorkers = 4
+	// DefaultAlertTenantWorkers is the default number of MimirAlertTenants reconciled in parallel
+	DefaultAlertTenantWorkers = 2
+	// DefaultClientConfigWorkers is the default number of ClientConfigs reconciled in parallel
+	DefaultClientConfigWorkers = 1
+)
@@ -0,0 +1,317 @@
+// Package debugapi serves the desired state the controller pushes to Mimir, to debug drift
+// between Git, the controller and Mimir.
+package debugapi
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"github.com/prometheus/prometheus/model/rulefmt"
+	"gopkg.in/yaml.v3"
+	certutil "k8s.io/client-go/util/cert"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/metrics/filters"
+
+	openawarenessv1beta1 "github.com/syndlex/openawareness-controller/api/openawareness/v1beta1"
+	"github.com/syndlex/openawareness-controller/internal/clients"
+	monitoringcoreoscom "github.com/syndlex/openawareness-controller/internal/controller/monitoring.coreos.com"
+	"github.com/syndlex/openawareness-controller/internal/controller/utils"
+	"github.com/syndlex/openawareness-controller/internal/events"
+	"github.com/syndlex/openawareness-controller/internal/mimir"
+)
+
+const (
+	// DefaultBindAddress is the address the debug API listens on by default
+	DefaultBindAddress = ":8082"
+
+	// clientQueryParameter restricts the served resources to those referencing a ClientConfig
+	clientQueryParameter = "client"
+	// readHeaderTimeout limits the time to read request headers
+	readHeaderTimeout = 10 * time.Second
+)
+
+// Handler serves the desired rendered state of a Mimir tenant:
+//   - GET /tenants/{tenant}/alertmanager returns the Alertmanager payload of the tenant's MimirAlertTenant
+//   - GET /tenants/{tenant}/rules returns the rule groups of the tenant's PrometheusRules by rule namespace
+//   - GET /mimir/requests returns the last requests sent to Mimir, if recorded on a Tape
+//   - GET /mimir/clients returns the clients of the client cache, with their last health check
+//   - GET /events/reasons returns the catalog of event reasons as JSON
+//
+// The tenant routes return YAML in the format of the Mimir API. Only resources referencing a
+// ClientConfig are served; the optional ?client=<name> query parameter restricts them to a single
+// ClientConfig.
+type Handler struct {
+	Client client.Reader
+	// GlobalValues provides controller-wide template values, nil if not configured
+	GlobalValues *utils.GlobalValues
+	// ClusterName identifies the cluster in templates as [[ .Meta.Cluster ]]
+	ClusterName string
+	// ExtraLabels are added to every alerting rule, only the extra-labels annotation applies if nil
+	ExtraLabels *utils.ExtraLabels
+	// TemplateSources fetches the templateSources of tenants, tenants using one fail to render if nil
+	TemplateSources utils.TemplateSourceFetcher
+	// Tape holds the recorded requests to Mimir, GET /mimir/requests is not found if nil
+	Tape *mimir.Tape
+	// ClientCache holds the clients of the ClientConfigs, GET /mimir/clients is not found if nil
+	ClientCache *clients.RulerClientCache
+}
+
+// Routes returns the HTTP handler serving the debug API.
+func (h *Handler) Routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /tenants/{tenant}/alertmanager", h.alertmanager)
+	mux.HandleFunc("GET /tenants/{tenant}/rules", h.rules)
+	mux.HandleFunc("GET /mimir/requests", h.requests)
+	mux.HandleFunc("GET /mimir/clients", h.clients)
+	mux.HandleFunc("GET /events/reasons", h.eventReasons)
+	return mux
+}
+
+// NewServer returns a manager runnable serving handler over HTTPS with a self-signed certificate.
+// Requests are authenticated with TokenReviews and authorized with SubjectAccessReviews for the
+// request path, like the metrics endpoint.
+// Returns an error if the authentication filter or the certificate cannot be created.
+func NewServer(mgr manager.Manager, addr string, handler http.Handler) (*manager.Server, error) {
+	filter, err := filters.WithAuthenticationAndAuthorization(mgr.GetConfig(), mgr.GetHTTPClient())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create debug API authentication filter: %w", err)
+	}
+	handler, err = filter(mgr.GetLogger().WithName("debug-api"), handler)
+	if err != nil {
+		return nil, fmt.Errorf("failed to wrap debug API handler: %w", err)
+	}
+
+	certPEM, keyPEM, err := certutil.GenerateSelfSignedCertKey("localhost", nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate debug API certificate: %w", err)
+	}
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load debug API certificate: %w", err)
+	}
+
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+
+	return &manager.Server{
+		Name:     "debug-api",
+		Server:   &http.Server{Handler: handler, ReadHeaderTimeout: readHeaderTimeout},
+		Listener: tls.NewListener(listener, &tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: tls.VersionTLS12}),
+	}, nil
+}
+
+// alertmanager serves the Alertmanager payload of the tenant's MimirAlertTenant.
+func (h *Handler) alertmanager(w http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+	tenantID := req.PathValue("tenant")
+
+	clientConfigs := &openawarenessv1beta1.ClientConfigList{}
+	if err := h.Client.List(ctx, clientConfigs); err != nil {
+		http.Error(w, fmt.Sprintf("failed to list ClientConfigs: %v", err), http.StatusInternalServerError)
+		return
+	}
+	mappings, err := utils.ListTenantMappings(ctx, h.Client)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to list TenantMappings: %v", err), http.StatusInternalServerError)
+		return
+	}
+	list := &openawarenessv1beta1.MimirAlertTenantList{}
+	if err := h.Client.List(ctx, list); err != nil {
+		http.Error(w, fmt.Sprintf("failed to list MimirAlertTenants: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	var matches []*openawarenessv1beta1.MimirAlertTenant
+	for i := range list.Items {
+		if servedFor(&list.Items[i], clientConfigs.Items, mappings, tenantID, req) {
+			matches = append(matches, &list.Items[i])
+		}
+	}
+	switch len(matches) {
+	case 0:
+		http.Error(w, fmt.Sprintf("no MimirAlertTenant for tenant %s", tenantID), http.StatusNotFound)
+		return
+	case 1:
+	default:
+		names := make([]string, 0, len(matches))
+		for _, match := range matches {
+			names = append(names, utils.OwnerReference(match))
+		}
+		sort.Strings(names)
+		http.Error(w, fmt.Sprintf("multiple MimirAlertTenants for tenant %s: %s", tenantID, strings.Join(names, ", ")),
+			http.StatusConflict)
+		return
+	}
+
+	tenant := matches[0]
+	config, templates, err := utils.RenderAlertmanagerConfig(ctx, h.Client, log.FromContext(ctx), tenant,
+		h.GlobalValues, h.ClusterName, h.TemplateSources)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to render MimirAlertTenant %s: %v", utils.OwnerReference(tenant), err),
+			http.StatusUnprocessableEntity)
+		return
+	}
+
+	payload, err := mimir.AlertmanagerPayload(config, templates)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to marshal Alertmanager payload: %v", err), http.StatusInternalServerError)
+		return
+	}
+	writeYAML(w, payload)
+}
+
+// rules serves the rule groups of the tenant's PrometheusRules keyed by Mimir rule namespace.
+func (h *Handler) rules(w http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+	tenantID := req.PathValue("tenant")
+
+	clientConfigs := &openawarenessv1beta1.ClientConfigList{}
+	if err := h.Client.List(ctx, clientConfigs); err != nil {
+		http.Error(w, fmt.Sprintf("failed to list ClientConfigs: %v", err), http.StatusInternalServerError)
+		return
+	}
+	mappings, err := utils.ListTenantMappings(ctx, h.Client)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to list TenantMappings: %v", err), http.StatusInternalServerError)
+		return
+	}
+	list := &monitoringv1.PrometheusRuleList{}
+	if err := h.Client.List(ctx, list); err != nil {
+		http.Error(w, fmt.Sprintf("failed to list PrometheusRules: %v", err), http.StatusInternalServerError)
+		return
+	}
+	sort.Slice(list.Items, func(i, j int) bool {
+		return utils.OwnerReference(&list.Items[i]) < utils.OwnerReference(&list.Items[j])
+	})
+
+	namespaces := make(map[string][]rulefmt.RuleGroup)
+	for i := range list.Items {
+		rule := &list.Items[i]
+		if !servedFor(rule, clientConfigs.Items, mappings, tenantID, req) {
+			continue
+		}
+		templated, err := monitoringcoreoscom.ResolveRuleTemplates(ctx, h.Client, rule)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("failed to render the templates of PrometheusRule %s: %v", utils.OwnerReference(rule), err),
+				http.StatusUnprocessableEntity)
+			return
+		}
+		groups, err := monitoringcoreoscom.DesiredRuleGroups(templated)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("failed to convert PrometheusRule %s: %v", utils.OwnerReference(rule), err),
+				http.StatusUnprocessableEntity)
+			return
+		}
+		labels, err := h.ExtraLabels.For(ctx, rule)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("failed to get extra labels of PrometheusRule %s: %v", utils.OwnerReference(rule), err),
+				http.StatusUnprocessableEntity)
+			return
+		}
+		utils.InjectLabels(groups, labels)
+		partitions := monitoringcoreoscom.PartitionRuleGroups(rule, groups, utils.ClientConfigFor(rule, clientConfigs.Items, mappings))
+		namespace := utils.RulesNamespace(rule)
+		namespaces[namespace] = append(namespaces[namespace], partitions[tenantID]...)
+	}
+
+	payload, err := yaml.Marshal(namespaces)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to marshal rule groups: %v", err), http.StatusInternalServerError)
+		return
+	}
+	writeYAML(w, payload)
+}
+
+// requests serves the requests recorded on the Tape, oldest first, optionally restricted to a
+// tenant by the ?tenant=<id> query parameter.
+func (h *Handler) requests(w http.ResponseWriter, req *http.Request) {
+	if h.Tape == nil {
+		http.Error(w, "request recording is disabled, set --mimir-request-tape-size", http.StatusNotFound)
+		return
+	}
+	entries := h.Tape.Entries()
+	if tenantID := req.URL.Query().Get("tenant"); tenantID != "" {
+		entries = slices.DeleteFunc(entries, func(entry mimir.TapeEntry) bool {
+			return entry.TenantID != tenantID
+		})
+	}
+	if entries == nil {
+		entries = []mimir.TapeEntry{}
+	}
+
+	payload, err := yaml.Marshal(entries)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to marshal recorded requests: %v", err), http.StatusInternalServerError)
+		return
+	}
+	writeYAML(w, payload)
+}
+
+// clients serves the clients of the client cache sorted by name, optionally restricted to a
+// ClientConfig by the ?client=<name> query parameter.
+func (h *Handler) clients(w http.ResponseWriter, req *http.Request) {
+	if h.ClientCache == nil {
+		http.Error(w, "client cache not available", http.StatusNotFound)
+		return
+	}
+	infos := h.ClientCache.Clients()
+	if name := req.URL.Query().Get(clientQueryParameter); name != "" {
+		infos = slices.DeleteFunc(infos, func(info clients.ClientInfo) bool {
+			return info.Name != name
+		})
+	}
+
+	payload, err := yaml.Marshal(infos)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to marshal clients: %v", err), http.StatusInternalServerError)
+		return
+	}
+	writeYAML(w, payload)
+}
+
+// eventReasons serves the catalog of the reasons of the events emitted by the controller.
+func (h *Handler) eventReasons(w http.ResponseWriter, _ *http.Request) {
+	catalog, err := events.RenderCatalog()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(catalog)
+}
+
+// servedFor reports whether obj is synced to the tenant and matches the request's client filter.
+// Objects being deleted or neither referencing a ClientConfig nor having a default are never synced.
+// The tenants of obj are resolved through the tenant aliases of its ClientConfig.
+func servedFor(
+	obj client.Object,
+	clientConfigs []openawarenessv1beta1.ClientConfig,
+	mappings *utils.TenantMappings,
+	tenantID string,
+	req *http.Request,
+) bool {
+	clientName := utils.ClientNameFor(obj, clientConfigs, mappings)
+	tenantIDs := utils.TenantIDs(obj, utils.ClientConfigFor(obj, clientConfigs, mappings))
+	if clientName == "" || !obj.GetDeletionTimestamp().IsZero() || !slices.Contains(tenantIDs, tenantID) {
+		return false
+	}
+	filter := req.URL.Query().Get(clientQueryParameter)
+	return filter == "" || filter == clientName
+}
+
+// writeYAML writes a YAML response body.
+func writeYAML(w http.ResponseWriter, payload []byte) {
+	w.Header().Set("Content-Type", "application/yaml")
+	_, _ = w.Write(payload)
+}
@@ -0,0 +1,254 @@
+package debugapi
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	openawarenessv1beta1 "github.com/syndlex/openawareness-controller/api/openawareness/v1beta1"
+	"github.com/syndlex/openawareness-controller/internal/clients"
+	"github.com/syndlex/openawareness-controller/internal/controller/utils"
+	"github.com/syndlex/openawareness-controller/internal/mimir"
+)
+
+func newTestHandler(t *testing.T) http.Handler {
+	t.Helper()
+
+	scheme := runtime.NewScheme()
+	for _, add := range []func(*runtime.Scheme) error{
+		corev1.AddToScheme, openawarenessv1beta1.AddToScheme, monitoringv1.AddToScheme,
+	} {
+		if err := add(scheme); err != nil {
+			t.Fatalf("failed to build scheme: %v", err)
+		}
+	}
+
+	annotations := func(tenant string) map[string]string {
+		return map[string]string{utils.ClientNameAnnotation: "mimir", utils.MimirTenantAnnotation: tenant}
+	}
+	objects := []runtime.Object{
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "receivers", Namespace: "payments"},
+			Data:       map[string]string{"RECEIVER": "payments-slack"},
+		},
+		&openawarenessv1beta1.MimirAlertTenant{
+			ObjectMeta: metav1.ObjectMeta{Name: "alerts", Namespace: "payments", Annotations: annotations("team-a")},
+			Spec: openawarenessv1beta1.MimirAlertTenantSpec{
+				AlertmanagerConfig: "route:\n  receiver: [[ .RECEIVER ]]\n# [[ .Meta.Cluster ]]\n",
+				TemplateFiles:      map[string]string{"slack.tmpl": "{{ define \"title\" }}Alert{{ end }}"},
+				SecretDataReferences: []openawarenessv1beta1.SecretDataReference{
+					{Kind: "ConfigMap", Name: "receivers"},
+				},
+			},
+		},
+		&openawarenessv1beta1.MimirAlertTenant{
+			ObjectMeta: metav1.ObjectMeta{Name: "first", Namespace: "shared", Annotations: annotations("team-b")},
+			Spec:       openawarenessv1beta1.MimirAlertTenantSpec{AlertmanagerConfig: "route: {}"},
+		},
+		&openawarenessv1beta1.MimirAlertTenant{
+			ObjectMeta: metav1.ObjectMeta{Name: "second", Namespace: "shared", Annotations: annotations("team-b")},
+			Spec:       openawarenessv1beta1.MimirAlertTenantSpec{AlertmanagerConfig: "route: {}"},
+		},
+		&monitoringv1.PrometheusRule{
+			ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "payments", Annotations: annotations("team-a")},
+			Spec: monitoringv1.PrometheusRuleSpec{Groups: []monitoringv1.RuleGroup{{
+				Name:  "api",
+				Rules: []monitoringv1.Rule{{Alert: "APIDown", Expr: intstr.FromString("up == 0")}},
+			}}},
+		},
+		&monitoringv1.PrometheusRule{
+			ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "payments", Annotations: annotations("team-b")},
+			Spec: monitoringv1.PrometheusRuleSpec{Groups: []monitoringv1.RuleGroup{{
+				Name:  "other",
+				Rules: []monitoringv1.Rule{{Alert: "OtherDown", Expr: intstr.FromString("up == 0")}},
+			}}},
+		},
+	}
+
+	reader := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(objects...).Build()
+	return (&Handler{Client: reader, ClusterName: "eu-1"}).Routes()
+}
+
+func get(t *testing.T, handler http.Handler, path string) (int, string) {
+	t.Helper()
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+	body, err := io.ReadAll(recorder.Body)
+	if err != nil {
+		t.Fatalf("failed to read body: %v", err)
+	}
+	return recorder.Code, string(body)
+}
+
+func TestAlertmanager(t *testing.T) {
+	handler := newTestHandler(t)
+
+	tests := []struct {
+		name     string
+		path     string
+		wantCode int
+		want     []string
+	}{
+		{
+			name:     "rendered payload",
+			path:     "/tenants/team-a/alertmanager",
+			wantCode: http.StatusOK,
+			want: []string{
+				"alertmanager_config:",
+				"# managed-by: openawareness-controller MimirAlertTenant payments/alerts",
+				"receiver: payments-slack",
+				"# eu-1",
+				"template_files:",
+				"slack.tmpl:",
+			},
+		},
+		{
+			name:     "unknown tenant",
+			path:     "/tenants/unknown/alertmanager",
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name:     "other client",
+			path:     "/tenants/team-a/alertmanager?client=other",
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name:     "conflicting tenants",
+			path:     "/tenants/team-b/alertmanager",
+			wantCode: http.StatusConflict,
+			want:     []string{"shared/first, shared/second"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, body := get(t, handler, tt.path)
+			if code != tt.wantCode {
+				t.Fatalf("got status %d, want %d: %s", code, tt.wantCode, body)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(body, want) {
+					t.Errorf("expected body to contain %q, got:\n%s", want, body)
+				}
+			}
+		})
+	}
+}
+
+func TestRules(t *testing.T) {
+	handler := newTestHandler(t)
+
+	code, body := get(t, handler, "/tenants/team-a/rules")
+	if code != http.StatusOK {
+		t.Fatalf("got status %d: %s", code, body)
+	}
+	for _, want := range []string{"payments:", "name: api", "alert: APIDown", utils.OwnerLabel + ": payments/api"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected body to contain %q, got:\n%s", want, body)
+		}
+	}
+	if strings.Contains(body, "OtherDown") {
+		t.Errorf("expected rules of other tenants to be excluded, got:\n%s", body)
+	}
+
+	code, body = get(t, handler, "/tenants/unknown/rules")
+	if code != http.StatusOK || strings.TrimSpace(body) != "{}" {
+		t.Errorf("expected empty rule namespaces, got %d: %s", code, body)
+	}
+}
+
+func TestRequests(t *testing.T) {
+	code, _ := get(t, newTestHandler(t), "/mimir/requests")
+	if code != http.StatusNotFound {
+		t.Errorf("expected requests not found without a tape, got %d", code)
+	}
+
+	tape := mimir.NewTape(10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	t.Cleanup(server.Close)
+	client := &http.Client{Transport: tape.RoundTripper(http.DefaultTransport)}
+	for _, tenant := range []string{"team-a", "team-b"} {
+		req := httptest.NewRequest(http.MethodPost, server.URL+"/api/v1/alerts", nil)
+		req.RequestURI = ""
+		req.Header.Set("X-Scope-OrgID", tenant)
+		res, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+		_ = res.Body.Close()
+	}
+
+	handler := (&Handler{Tape: tape}).Routes()
+	code, body := get(t, handler, "/mimir/requests?tenant=team-b")
+	if code != http.StatusOK {
+		t.Fatalf("got status %d: %s", code, body)
+	}
+	if !strings.Contains(body, "tenantID: team-b") || !strings.Contains(body, "status: 202") ||
+		strings.Contains(body, "team-a") {
+		t.Errorf("expected only the request of team-b, got:\n%s", body)
+	}
+}
+
+func TestClients(t *testing.T) {
+	code, _ := get(t, newTestHandler(t), "/mimir/clients")
+	if code != http.StatusNotFound {
+		t.Errorf("expected clients not found without a client cache, got %d", code)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+	cache := clients.NewRulerClientCache()
+	for _, name := range []string{"mimir-b", "mimir-a"} {
+		clientConfig := &openawarenessv1beta1.ClientConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "monitoring"},
+			Spec:       openawarenessv1beta1.ClientConfigSpec{Address: server.URL, Type: openawarenessv1beta1.Mimir},
+		}
+		if err := cache.AddMimirClient(context.Background(), clientConfig); err != nil {
+			t.Fatalf("AddMimirClient: %v", err)
+		}
+	}
+	t.Cleanup(func() {
+		cache.RemoveClient("monitoring", "mimir-a")
+		cache.RemoveClient("monitoring", "mimir-b")
+	})
+
+	handler := (&Handler{ClientCache: cache}).Routes()
+	code, body := get(t, handler, "/mimir/clients")
+	if code != http.StatusOK {
+		t.Fatalf("got status %d: %s", code, body)
+	}
+	if !strings.Contains(body, "name: mimir-a") || !strings.Contains(body, "address: "+server.URL) ||
+		!strings.Contains(body, "lastHealthCheck:") || strings.Index(body, "mimir-a") > strings.Index(body, "mimir-b") {
+		t.Errorf("expected both clients sorted by name, got:\n%s", body)
+	}
+
+	code, body = get(t, handler, "/mimir/clients?client=mimir-b")
+	if code != http.StatusOK || !strings.Contains(body, "name: mimir-b") || strings.Contains(body, "mimir-a") {
+		t.Errorf("expected only mimir-b, got %d:\n%s", code, body)
+	}
+}
+
+func TestEventReasons(t *testing.T) {
+	code, body := get(t, (&Handler{}).Routes(), "/events/reasons")
+	if code != http.StatusOK {
+		t.Fatalf("got status %d: %s", code, body)
+	}
+	if !strings.Contains(body, `"reason": "RuleGroupCreateFailed"`) || !strings.Contains(body, `"type": "Warning"`) {
+		t.Errorf("expected the event catalog, got:\n%s", body)
+	}
+}
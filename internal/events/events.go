@@ -0,0 +1,91 @@
+// Package events defines the reasons of the Kubernetes events emitted by the controller and
+// documents them in a catalog, so alerts on controller events can rely on stable reasons.
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+)
+
+// Reason is the reason of an event emitted by the controller. Every reason is documented in
+// the Catalog, which also fixes the type of its events.
+type Reason string
+
+// Event records an event with reason and message on obj, of the type the Catalog defines for
+// reason. Reasons missing from the Catalog are recorded as warnings.
+// Nothing is recorded if recorder is nil.
+func Event(recorder record.EventRecorder, obj runtime.Object, reason Reason, message string) {
+	if recorder == nil {
+		return
+	}
+	recorder.Event(obj, eventType(reason), string(reason), message)
+}
+
+// Eventf is like Event with a message formatted as with fmt.Sprintf.
+func Eventf(recorder record.EventRecorder, obj runtime.Object, reason Reason, format string, args ...any) {
+	if recorder == nil {
+		return
+	}
+	recorder.Eventf(obj, eventType(reason), string(reason), format, args...)
+}
+
+// eventType returns the type of the events of reason, corev1.EventTypeWarning if unknown.
+func eventType(reason Reason) string {
+	if definition, ok := Lookup(reason); ok {
+		return definition.Type
+	}
+	return corev1.EventTypeWarning
+}
+
+// Definition documents an event reason.
+type Definition struct {
+	Reason Reason `json:"reason"`
+	// Type is the type of the events, Normal or Warning
+	Type string `json:"type"`
+	// Kinds are the kinds of the objects the events are emitted on
+	Kinds []string `json:"kinds"`
+	// Description explains when the events are emitted
+	Description string `json:"description"`
+}
+
+// catalogHeader identifies the generator of the rendered catalog
+const catalogHeader = "manager event-catalog"
+
+// Lookup returns the definition of reason, false if it is not in the Catalog.
+func Lookup(reason Reason) (Definition, bool) {
+	index := slices.IndexFunc(definitions, func(definition Definition) bool {
+		return definition.Reason == reason
+	})
+	if index < 0 {
+		return Definition{}, false
+	}
+	return definitions[index], true
+}
+
+// Catalog returns the definitions of all event reasons sorted by reason.
+func Catalog() []Definition {
+	catalog := slices.Clone(definitions)
+	slices.SortFunc(catalog, func(a, b Definition) int {
+		return strings.Compare(string(a.Reason), string(b.Reason))
+	})
+	return catalog
+}
+
+// RenderCatalog returns the Catalog as indented JSON, as served by the debug API and written
+// to docs/event-reasons.json by `make event-catalog`.
+func RenderCatalog() ([]byte, error) {
+	rendered, err := json.MarshalIndent(struct {
+		GeneratedBy string       `json:"generatedBy"`
+		Reasons     []Definition `json:"reasons"`
+	}{GeneratedBy: catalogHeader, Reasons: Catalog()}, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("rendering the event catalog: %w", err)
+	}
+	return append(rendered, '\n'), nil
+}
@@ -0,0 +1,86 @@
+package events
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestRenderedCatalogIsCurrent(t *testing.T) {
+	rendered, err := RenderCatalog()
+	if err != nil {
+		t.Fatalf("RenderCatalog() error = %v", err)
+	}
+	committed, err := os.ReadFile(filepath.Join("..", "..", "docs", "event-reasons.json"))
+	if err != nil {
+		t.Fatalf("reading the committed catalog: %v", err)
+	}
+	if string(committed) != string(rendered) {
+		t.Errorf("docs/event-reasons.json is outdated, run `make event-catalog`")
+	}
+}
+
+func TestCatalogDefinitions(t *testing.T) {
+	seen := map[Reason]bool{}
+	for _, definition := range Catalog() {
+		if seen[definition.Reason] {
+			t.Errorf("reason %s is defined twice", definition.Reason)
+		}
+		seen[definition.Reason] = true
+		if definition.Type != corev1.EventTypeNormal && definition.Type != corev1.EventTypeWarning {
+			t.Errorf("reason %s has invalid type %q", definition.Reason, definition.Type)
+		}
+		if definition.Description == "" || len(definition.Kinds) == 0 {
+			t.Errorf("reason %s lacks a description or kinds", definition.Reason)
+		}
+	}
+}
+
+// TestReasonsAreTyped guards against events recorded without a catalogued reason.
+func TestReasonsAreTyped(t *testing.T) {
+	direct := regexp.MustCompile(`(?i)recorder\.(Annotated)?Eventf?\(`)
+	root := filepath.Join("..", "..")
+	for _, dir := range []string{"cmd", "internal"} {
+		err := filepath.WalkDir(filepath.Join(root, dir), func(path string, entry os.DirEntry, err error) error {
+			if err != nil || entry.IsDir() || !strings.HasSuffix(path, ".go") ||
+				strings.HasSuffix(path, "_test.go") || filepath.Base(filepath.Dir(path)) == "events" {
+				return err
+			}
+			content, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			if direct.Match(content) {
+				t.Errorf("%s records events without events.Event or events.Eventf", path)
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestEvent(t *testing.T) {
+	recorder := record.NewFakeRecorder(3)
+	pod := &corev1.Pod{}
+	Event(recorder, pod, RuleGroupsSynced, "synced")
+	Eventf(recorder, pod, RuleGroupCreateFailed, "failed %d", 2)
+	Event(recorder, pod, "Uncatalogued", "unknown")
+	Event(nil, pod, RuleGroupsSynced, "ignored")
+
+	for _, expected := range []string{
+		"Normal RuleGroupsSynced synced",
+		"Warning RuleGroupCreateFailed failed 2",
+		"Warning Uncatalogued unknown",
+	} {
+		if event := <-recorder.Events; event != expected {
+			t.Errorf("expected event %q, got %q", expected, event)
+		}
+	}
+}
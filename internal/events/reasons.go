@@ -0,0 +1,196 @@
+package events
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	openawarenessv1beta1 "github.com/syndlex/openawareness-controller/api/openawareness/v1beta1"
+)
+
+// Object kinds events are emitted on
+const (
+	kindPrometheusRule   = "PrometheusRule"
+	kindClientConfig     = "ClientConfig"
+	kindMimirAlertTenant = "MimirAlertTenant"
+	kindTenantMapping    = "TenantMapping"
+	kindSnapshot         = "Snapshot"
+)
+
+// Reasons of events on PrometheusRules
+const (
+	// ClientNotFound The referenced ClientConfig does not exist
+	ClientNotFound Reason = "ClientNotFound"
+	// ClientDisconnected The referenced ClientConfig is not connected to Mimir
+	ClientDisconnected Reason = "ClientDisconnected"
+	// ClientUnavailable The Mimir client of the referenced ClientConfig cannot be created
+	ClientUnavailable Reason = "ClientUnavailable"
+	// CircuitOpen Requests are refused after consecutive server errors
+	CircuitOpen Reason = openawarenessv1beta1.ReasonCircuitOpen
+	// TimeoutError A Mimir API operation exceeded the sync timeout
+	TimeoutError Reason = openawarenessv1beta1.ReasonTimeoutError
+	// InvalidSyncTimeout The sync-timeout annotation is invalid
+	InvalidSyncTimeout Reason = "InvalidSyncTimeout"
+	// Interrupted The sync was interrupted by a controller shutdown
+	Interrupted Reason = openawarenessv1beta1.ReasonInterrupted
+	// TenantNotAllowed The tenant is not allowed by the ClientConfig
+	TenantNotAllowed Reason = openawarenessv1beta1.ReasonTenantNotAllowed
+	// InvalidRuleGroups The rule groups cannot be rendered or are invalid
+	InvalidRuleGroups Reason = "InvalidRuleGroups"
+	// RuleIntervalTooShort Rule group intervals are below the minimum of the ClientConfig
+	RuleIntervalTooShort Reason = "RuleIntervalTooShort"
+	// RuleIntervalClamped Rule group intervals were raised to the minimum of the ClientConfig
+	RuleIntervalClamped Reason = "RuleIntervalClamped"
+	// ExpressionRewritePreview Expressions would be rewritten with injected label matchers
+	ExpressionRewritePreview Reason = "ExpressionRewritePreview"
+	// SyncPaused Remote changes are paused via annotation
+	SyncPaused Reason = "SyncPaused"
+	// RuleGroupCreateFailed Rule groups cannot be pushed to Mimir
+	RuleGroupCreateFailed Reason = "RuleGroupCreateFailed"
+	// RuleGroupDeleteFailed Rule groups cannot be deleted from Mimir
+	RuleGroupDeleteFailed Reason = "RuleGroupDeleteFailed"
+	// RuleGroupsDeleted The rule groups were deleted from Mimir
+	RuleGroupsDeleted Reason = "RuleGroupsDeleted"
+	// RuleGroupsSynced The rule groups were synced to Mimir
+	RuleGroupsSynced Reason = "RuleGroupsSynced"
+	// RuleGroupsPruned Rule groups removed from the rule were deleted from Mimir
+	RuleGroupsPruned Reason = "RuleGroupsPruned"
+	// RulePolicyViolation A rule violates a rule policy
+	RulePolicyViolation Reason = "RulePolicyViolation"
+	// RuleGroupsBlocked The rule groups are not synced because of blocking policy violations
+	RuleGroupsBlocked Reason = "RuleGroupsBlocked"
+	// DuplicateRuleName Rule groups have duplicate rule names
+	DuplicateRuleName Reason = "DuplicateRuleName"
+	// RuleGroupsActivationUnknown Whether the synced rule groups are evaluated cannot be checked
+	RuleGroupsActivationUnknown Reason = "RuleGroupsActivationUnknown"
+	// RuleGroupsInactive Synced rule groups are not evaluated by the ruler
+	RuleGroupsInactive Reason = "RuleGroupsInactive"
+	// RuleGroupsActive The synced rule groups are evaluated by the ruler
+	RuleGroupsActive Reason = "RuleGroupsActive"
+	// RuleSimulationFailed Rules cannot be evaluated against the tenant
+	RuleSimulationFailed Reason = "RuleSimulationFailed"
+	// MissingSeriesWarning Rule expressions select series the tenant does not have
+	MissingSeriesWarning Reason = "MissingSeriesWarning"
+	// RuleGroupModified Rule groups were modified in Mimir outside the controller
+	RuleGroupModified Reason = "RuleGroupModified"
+	// EvaluationParityMismatch Rules evaluate differently in Mimir and the reference
+	EvaluationParityMismatch Reason = "EvaluationParityMismatch"
+)
+
+// Reasons of events on ClientConfigs
+const (
+	// Reloaded The TLS client certificate was reloaded
+	Reloaded Reason = "Reloaded"
+	// BackupRestoreFailed The backup of the ClientConfig cannot be restored
+	BackupRestoreFailed Reason = "BackupRestoreFailed"
+	// BackupRestored The backup of the ClientConfig was restored
+	BackupRestored Reason = "BackupRestored"
+)
+
+// Reasons of events on MimirAlertTenants
+const (
+	// DefaultReceiverInjected A default receiver was added to the configuration
+	DefaultReceiverInjected Reason = openawarenessv1beta1.ReasonDefaultReceiverInjected
+	// QuotaExceeded The configuration exceeds a quota
+	QuotaExceeded Reason = openawarenessv1beta1.ReasonQuotaExceeded
+	// ConfigurationChanged The configuration in Mimir changed
+	ConfigurationChanged Reason = "ConfigurationChanged"
+	// RemoteDataRetained The configuration is kept in Mimir after deletion
+	RemoteDataRetained Reason = "RemoteDataRetained"
+	// FallbackConfig The tenant is served the fallback configuration of Mimir
+	FallbackConfig Reason = openawarenessv1beta1.ReasonFallbackConfig
+	// NotificationsFailed Alertmanager notifications of the tenant failed
+	NotificationsFailed Reason = "NotificationsFailed"
+)
+
+// Reasons of events on TenantMappings
+const (
+	// TenantBootstrapped The Alertmanager configuration of a new tenant was bootstrapped
+	TenantBootstrapped Reason = "TenantBootstrapped"
+	// TenantBootstrapFailed The Alertmanager configuration of a new tenant cannot be bootstrapped
+	TenantBootstrapFailed Reason = "TenantBootstrapFailed"
+)
+
+// Reasons of events on Snapshots
+const (
+	// SnapshotCompleted The snapshot was captured and written
+	SnapshotCompleted Reason = "SnapshotCompleted"
+	// SnapshotFailed The snapshot cannot be captured or written
+	SnapshotFailed Reason = "SnapshotFailed"
+)
+
+// definitions documents every reason above
+var definitions = []Definition{
+	warning(ClientNotFound, "The ClientConfig referenced by the rule does not exist, the sync is retried.",
+		kindPrometheusRule),
+	warning(ClientDisconnected, "The ClientConfig referenced by the rule is not connected to Mimir, the sync is retried.",
+		kindPrometheusRule),
+	warning(ClientUnavailable, "The Mimir client of the referenced ClientConfig cannot be created, the sync is retried.",
+		kindPrometheusRule),
+	warning(CircuitOpen, "Requests to Mimir are refused after consecutive server errors until the circuit breaker "+
+		"of the ClientConfig closes again.", kindPrometheusRule),
+	warning(TimeoutError, "A Mimir API operation did not complete within the sync timeout.", kindPrometheusRule),
+	warning(InvalidSyncTimeout, "The sync-timeout annotation cannot be parsed, the default timeout is used.",
+		kindPrometheusRule),
+	warning(Interrupted, "The sync was interrupted by a controller shutdown and is retried on start.",
+		kindPrometheusRule),
+	warning(TenantNotAllowed, "The tenant is denied or not allowed by the ClientConfig, nothing is synced.",
+		kindPrometheusRule, kindMimirAlertTenant),
+	warning(InvalidRuleGroups, "The rule groups cannot be rendered or fail validation, nothing is synced.",
+		kindPrometheusRule),
+	warning(RuleIntervalTooShort, "Rule group intervals are below the minimum of the ClientConfig, nothing is synced.",
+		kindPrometheusRule),
+	warning(RuleIntervalClamped, "Rule group intervals below the minimum of the ClientConfig were raised to it.",
+		kindPrometheusRule),
+	normal(ExpressionRewritePreview, "Dry-run of the expression rewrite, lists the expressions label matchers "+
+		"would be injected into.", kindPrometheusRule),
+	normal(SyncPaused, "Remote changes are paused via the paused annotation.", kindPrometheusRule),
+	warning(RuleGroupCreateFailed, "Rule groups cannot be pushed to Mimir, the sync is retried.", kindPrometheusRule),
+	warning(RuleGroupDeleteFailed, "Rule groups cannot be deleted from Mimir, the deletion is retried.",
+		kindPrometheusRule),
+	normal(RuleGroupsDeleted, "All rule groups of the deleted rule were removed from Mimir.", kindPrometheusRule),
+	normal(RuleGroupsSynced, "The rule groups were synced to Mimir.", kindPrometheusRule),
+	normal(RuleGroupsPruned, "Rule groups no longer in the rule were deleted from Mimir.", kindPrometheusRule),
+	warning(RulePolicyViolation, "A rule violates a rule policy, one event per finding.", kindPrometheusRule),
+	warning(RuleGroupsBlocked, "Rule policy violations with the block action prevent the sync.", kindPrometheusRule),
+	warning(DuplicateRuleName, "Rule groups of the rule contain duplicate rule names.", kindPrometheusRule),
+	warning(RuleGroupsActivationUnknown, "Whether the synced rule groups are evaluated by the ruler cannot be checked.",
+		kindPrometheusRule),
+	warning(RuleGroupsInactive, "Synced rule groups are not evaluated by the ruler.", kindPrometheusRule),
+	normal(RuleGroupsActive, "The synced rule groups are evaluated by the ruler.", kindPrometheusRule),
+	warning(RuleSimulationFailed, "Rules cannot be evaluated against the data of the tenant.", kindPrometheusRule),
+	warning(MissingSeriesWarning, "Rule expressions select series the tenant does not have.", kindPrometheusRule),
+	warning(RuleGroupModified, "Rule groups synced by the controller were modified in Mimir by someone else.",
+		kindPrometheusRule),
+	warning(EvaluationParityMismatch, "Rules evaluate differently in Mimir than in the reference Prometheus.",
+		kindPrometheusRule),
+	normal(Reloaded, "The TLS client certificate of the ClientConfig was reloaded from disk.", kindClientConfig),
+	warning(BackupRestoreFailed, "The backup referenced by the ClientConfig cannot be restored to Mimir.",
+		kindClientConfig),
+	normal(BackupRestored, "The backup referenced by the ClientConfig was restored to Mimir.", kindClientConfig),
+	warning(DefaultReceiverInjected, "The top-level route has no resolvable receiver, alerts matching "+
+		"no route are sent to the default receiver.",
+		kindMimirAlertTenant),
+	warning(QuotaExceeded, "The configuration exceeds a quota of the ClientConfig, nothing is synced.",
+		kindMimirAlertTenant),
+	normal(ConfigurationChanged, "The Alertmanager configuration in Mimir changed.", kindMimirAlertTenant),
+	warning(RemoteDataRetained, "The Alertmanager configuration is kept in Mimir after the deletion "+
+		"because the confirm-delete annotation is not set.",
+		kindMimirAlertTenant),
+	warning(FallbackConfig, "Mimir serves its fallback configuration because the synced "+
+		"configuration is blank.", kindMimirAlertTenant),
+	warning(NotificationsFailed, "Alertmanager notifications of the tenant failed since the last check.",
+		kindMimirAlertTenant),
+	normal(TenantBootstrapped, "The Alertmanager configuration of a new tenant was bootstrapped from the template.",
+		kindTenantMapping),
+	warning(TenantBootstrapFailed, "The Alertmanager configuration of a new tenant cannot be bootstrapped.",
+		kindTenantMapping),
+	normal(SnapshotCompleted, "The tenant was captured and the snapshot written to its destination.", kindSnapshot),
+	warning(SnapshotFailed, "The tenant cannot be captured or the snapshot cannot be written.", kindSnapshot),
+}
+
+func normal(reason Reason, description string, kinds ...string) Definition {
+	return Definition{Reason: reason, Type: corev1.EventTypeNormal, Kinds: kinds, Description: description}
+}
+
+func warning(reason Reason, description string, kinds ...string) Definition {
+	return Definition{Reason: reason, Type: corev1.EventTypeWarning, Kinds: kinds, Description: description}
+}
@@ -0,0 +1,283 @@
+// Package gc provides garbage collection of Mimir rule namespaces left behind by deleted resources.
+package gc
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"github.com/prometheus/prometheus/model/rulefmt"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	openawarenessv1beta1 "github.com/syndlex/openawareness-controller/api/openawareness/v1beta1"
+	"github.com/syndlex/openawareness-controller/internal/clients"
+	"github.com/syndlex/openawareness-controller/internal/controller/utils"
+	"github.com/syndlex/openawareness-controller/internal/logging"
+)
+
+// Sweeper periodically removes Mimir rule groups that carry the operator's
+// ownership marker (the utils.OwnerLabel rule label) in rule namespaces that no
+// longer have a corresponding PrometheusRule in the cluster.
+//
+// Tenants are discovered from the tenant annotations of existing PrometheusRules and
+// MimirAlertTenants referencing a ClientConfig, plus the default tenant.
+// Groups without the ownership marker are never touched.
+type Sweeper struct {
+	Client       client.Client
+	RulerClients clients.RulerClientCacheInterface
+	// Interval is the time between two sweeps
+	Interval time.Duration
+	// DryRun only reports orphaned groups instead of deleting them
+	DryRun bool
+	// Identity names this installation, only groups carrying it in their instance label are
+	// deleted. Without identity, only groups without instance label are. See utils.InstanceIdentity
+	Identity string
+	// AdoptUnlabeled deletes groups without instance label too, which were pushed before the
+	// Identity was set. Only safe if no other installation without identity pushes to the Mimir.
+	AdoptUnlabeled bool
+}
+
+// Ensure Sweeper can be added to a controller manager
+var _ manager.Runnable = (*Sweeper)(nil)
+
+// Start runs a sweep every Interval until the context is cancelled.
+// Errors of a single sweep are logged and do not stop the sweeper.
+func (s *Sweeper) Start(ctx context.Context) error {
+	logger := log.FromContext(ctx).WithName("gc")
+	logger.Info("Starting rule namespace garbage collection",
+		"interval", s.Interval,
+		"dryRun", s.DryRun)
+
+	ticker := time.NewTicker(s.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := s.Sweep(log.IntoContext(ctx, logger)); err != nil {
+				logger.Error(err, "Garbage collection sweep failed")
+			}
+		}
+	}
+}
+
+// Sweep performs a single garbage collection pass over all Mimir ClientConfigs.
+// Returns the first error encountered while listing Kubernetes resources;
+// per-client and per-tenant errors are logged and skipped.
+func (s *Sweeper) Sweep(ctx context.Context) error {
+	logger := log.FromContext(ctx)
+
+	clientConfigs := &openawarenessv1beta1.ClientConfigList{}
+	if err := s.Client.List(ctx, clientConfigs); err != nil {
+		return fmt.Errorf("listing ClientConfigs: %w", err)
+	}
+	mappings, err := utils.ListTenantMappings(ctx, s.Client)
+	if err != nil {
+		return err
+	}
+
+	rules := &monitoringv1.PrometheusRuleList{}
+	if err := s.Client.List(ctx, rules); err != nil {
+		return fmt.Errorf("listing PrometheusRules: %w", err)
+	}
+
+	tenants := &openawarenessv1beta1.MimirAlertTenantList{}
+	if err := s.Client.List(ctx, tenants); err != nil {
+		return fmt.Errorf("listing MimirAlertTenants: %w", err)
+	}
+
+	for i := range clientConfigs.Items {
+		clientConfig := &clientConfigs.Items[i]
+		if clientConfig.Spec.Type != openawarenessv1beta1.Mimir || utils.IsPaused(clientConfig) {
+			continue
+		}
+
+		mimirClient, err := s.RulerClients.GetOrCreateMimirClient(ctx, clientConfig)
+		if err != nil {
+			logger.Error(err, "Skipping ClientConfig, unable to get client", logging.KeyClient, clientConfig.Name)
+			continue
+		}
+
+		owned := ownedNamespaces(clientConfig, rules.Items, clientConfigs.Items, mappings)
+		for tenantID := range knownTenants(clientConfig, rules.Items, tenants.Items, clientConfigs.Items, mappings) {
+			// Nothing was pushed to tenants the ClientConfig does not allow
+			if !clientConfig.TenantAllowed(tenantID) {
+				continue
+			}
+			if err := s.sweepTenant(ctx, mimirClient, tenantID, owned[tenantID]); err != nil {
+				logger.Error(err, "Failed to sweep tenant",
+					logging.KeyClient, clientConfig.Name,
+					logging.KeyTenant, tenantID)
+			}
+		}
+	}
+
+	return nil
+}
+
+// sweepTenant deletes (or reports) the operator-owned rule groups of a single tenant in rule
+// namespaces that are not in the owned set. Groups without ownership marker, e.g. pushed by hand
+// or by another installation, are kept; a namespace is deleted at once only if all its groups
+// are orphaned.
+func (s *Sweeper) sweepTenant(
+	ctx context.Context,
+	mimirClient clients.AwarenessClient,
+	tenantID string,
+	owned map[string]struct{},
+) error {
+	logger := log.FromContext(ctx)
+
+	// Rules are walked namespace by namespace to keep large tenants out of memory
+	err := mimirClient.WalkRules(ctx, tenantID, func(namespace string, groups []rulefmt.RuleGroup) error {
+		if _, exists := owned[namespace]; exists {
+			return nil
+		}
+		var orphaned []string
+		for _, group := range groups {
+			if hasOwnershipMarker(group, s.Identity, s.AdoptUnlabeled) {
+				orphaned = append(orphaned, group.Name)
+			}
+		}
+		if len(orphaned) == 0 {
+			return nil
+		}
+
+		if s.DryRun {
+			logger.Info("Found orphaned rule groups (dry-run, not deleting)",
+				"rulesNamespace", namespace,
+				logging.KeyTenant, tenantID,
+				"groups", orphaned)
+			return nil
+		}
+
+		// Groups of others share the namespace, only the orphaned groups are deleted
+		if len(orphaned) < len(groups) {
+			for _, name := range orphaned {
+				if err := mimirClient.DeleteRuleGroup(ctx, namespace, name, tenantID); err != nil {
+					logger.Error(err, "Failed to delete orphaned rule group",
+						"rulesNamespace", namespace,
+						"group", name,
+						logging.KeyTenant, tenantID)
+					continue
+				}
+				logger.Info("Deleted orphaned rule group",
+					"rulesNamespace", namespace,
+					"group", name,
+					logging.KeyTenant, tenantID)
+			}
+			return nil
+		}
+
+		if err := mimirClient.DeleteNamespace(ctx, namespace, tenantID); err != nil {
+			logger.Error(err, "Failed to delete orphaned rule namespace",
+				"rulesNamespace", namespace,
+				logging.KeyTenant, tenantID)
+			return nil
+		}
+		logger.Info("Deleted orphaned rule namespace",
+			"rulesNamespace", namespace,
+			logging.KeyTenant, tenantID,
+			"groupCount", len(groups))
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("listing rules for tenant %s: %w", tenantID, err)
+	}
+
+	return nil
+}
+
+// ownedNamespaces returns the Mimir rule namespaces still backed by a PrometheusRule
+// for the given ClientConfig, keyed by tenant ID, including the recording and alerting tenants
+// of rules split across tenants. Rules own their rule namespace and the one they were last
+// synced to, see utils.RulesNamespace. Rules without client-name annotation belong to their
+// default ClientConfig or the one of their TenantMapping. Tenants are resolved through the
+// tenant aliases of the ClientConfig.
+func ownedNamespaces(
+	clientConfig *openawarenessv1beta1.ClientConfig,
+	rules []monitoringv1.PrometheusRule,
+	clientConfigs []openawarenessv1beta1.ClientConfig,
+	mappings *utils.TenantMappings,
+) map[string]map[string]struct{} {
+	owned := map[string]map[string]struct{}{}
+	for i := range rules {
+		rule := &rules[i]
+		if !syncedWith(rule, clientConfig, clientConfigs, mappings) {
+			continue
+		}
+		for _, tenantID := range utils.TenantIDs(rule, utils.ClientConfigFor(rule, clientConfigs, mappings)) {
+			if owned[tenantID] == nil {
+				owned[tenantID] = map[string]struct{}{}
+			}
+			owned[tenantID][utils.RulesNamespace(rule)] = struct{}{}
+			// Groups are removed from the namespace they were synced to by the controller
+			owned[tenantID][utils.SyncedRulesNamespace(rule)] = struct{}{}
+		}
+	}
+	return owned
+}
+
+// knownTenants returns all tenants referenced through the given ClientConfig, always including the default tenant.
+func knownTenants(
+	clientConfig *openawarenessv1beta1.ClientConfig,
+	rules []monitoringv1.PrometheusRule,
+	alertTenants []openawarenessv1beta1.MimirAlertTenant,
+	clientConfigs []openawarenessv1beta1.ClientConfig,
+	mappings *utils.TenantMappings,
+) map[string]struct{} {
+	tenants := map[string]struct{}{utils.DefaultTenantID: {}}
+	for i := range rules {
+		if syncedWith(&rules[i], clientConfig, clientConfigs, mappings) {
+			for _, tenantID := range utils.TenantIDs(&rules[i], utils.ClientConfigFor(&rules[i], clientConfigs, mappings)) {
+				tenants[tenantID] = struct{}{}
+			}
+		}
+	}
+	for i := range alertTenants {
+		if syncedWith(&alertTenants[i], clientConfig, clientConfigs, mappings) {
+			tenants[utils.GetTenantID(&alertTenants[i], utils.ClientConfigFor(&alertTenants[i], clientConfigs, mappings))] = struct{}{}
+		}
+	}
+	return tenants
+}
+
+// syncedWith reports whether obj is synced with clientConfig, comparing the namespace and name
+// of the ClientConfig it resolves to, so same-named ClientConfigs of other namespaces are told
+// apart. Objects whose ClientConfig cannot be resolved match by client name, so their rule
+// namespaces are kept.
+func syncedWith(
+	obj client.Object,
+	clientConfig *openawarenessv1beta1.ClientConfig,
+	clientConfigs []openawarenessv1beta1.ClientConfig,
+	mappings *utils.TenantMappings,
+) bool {
+	if resolved := utils.ClientConfigFor(obj, clientConfigs, mappings); resolved != nil {
+		return resolved.Namespace == clientConfig.Namespace && resolved.Name == clientConfig.Name
+	}
+	if key, qualified := utils.ClientConfigRef(obj); qualified {
+		return key.Namespace == clientConfig.Namespace && key.Name == clientConfig.Name
+	}
+	return utils.ClientNameFor(obj, clientConfigs, mappings) == clientConfig.Name
+}
+
+// hasOwnershipMarker reports whether any rule in the group was pushed by this operator
+// installation: its instance label matches identity. Rules without instance label were pushed
+// by an installation without identity and only count as owned if identity is empty too, they
+// may belong to another installation sharing the Mimir, or if adoptUnlabeled is set.
+func hasOwnershipMarker(group rulefmt.RuleGroup, identity string, adoptUnlabeled bool) bool {
+	for _, rule := range group.Rules {
+		if rule.Labels[utils.OwnerLabel] == "" {
+			continue
+		}
+		instance := rule.Labels[utils.InstanceLabel]
+		if instance == identity || (adoptUnlabeled && instance == "") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,206 @@
+package gc
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"testing"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"github.com/prometheus/prometheus/model/rulefmt"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	openawarenessv1beta1 "github.com/syndlex/openawareness-controller/api/openawareness/v1beta1"
+	"github.com/syndlex/openawareness-controller/internal/clients"
+	"github.com/syndlex/openawareness-controller/internal/controller/utils"
+)
+
+func TestHasOwnershipMarker(t *testing.T) {
+	owned := rulefmt.RuleGroup{
+		Name:  "owned",
+		Rules: []rulefmt.Rule{{Alert: "A", Labels: map[string]string{utils.OwnerLabel: "default/rules"}}},
+	}
+	foreign := rulefmt.RuleGroup{
+		Name:  "foreign",
+		Rules: []rulefmt.Rule{{Alert: "B", Labels: map[string]string{"team": "x"}}},
+	}
+
+	other := rulefmt.RuleGroup{
+		Name: "other",
+		Rules: []rulefmt.Rule{{Alert: "C", Labels: map[string]string{
+			utils.OwnerLabel:    "default/rules",
+			utils.InstanceLabel: "eu-1/green",
+		}}},
+	}
+
+	if !hasOwnershipMarker(owned, "", false) {
+		t.Error("expected groups with owner label to be marked as owned")
+	}
+	if hasOwnershipMarker(owned, "eu-1/blue", false) {
+		t.Error("expected groups without instance label not to be marked as owned by an installation with identity")
+	}
+	if hasOwnershipMarker(foreign, "", false) {
+		t.Error("expected groups without owner label not to be marked as owned")
+	}
+	if hasOwnershipMarker(other, "eu-1/blue", false) || !hasOwnershipMarker(other, "eu-1/green", false) {
+		t.Error("expected groups of another installation not to be marked as owned")
+	}
+
+	// Groups pushed before the identity was set are adopted
+	if !hasOwnershipMarker(owned, "eu-1/blue", true) {
+		t.Error("expected groups without instance label to be adopted")
+	}
+	if hasOwnershipMarker(foreign, "eu-1/blue", true) || hasOwnershipMarker(other, "eu-1/blue", true) {
+		t.Error("expected groups without owner label or of another installation not to be adopted")
+	}
+}
+
+// sweepClient serves fixed rule namespaces and records the deleted groups and namespaces.
+type sweepClient struct {
+	*clients.MockAwarenessClient
+	namespaces        map[string][]rulefmt.RuleGroup
+	deletedGroups     []string
+	deletedNamespaces []string
+}
+
+func (c *sweepClient) WalkRules(
+	_ context.Context,
+	_ string,
+	fn func(namespace string, groups []rulefmt.RuleGroup) error,
+) error {
+	for _, namespace := range slices.Sorted(maps.Keys(c.namespaces)) {
+		if err := fn(namespace, c.namespaces[namespace]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *sweepClient) DeleteRuleGroup(_ context.Context, namespace, groupName string, _ string) error {
+	c.deletedGroups = append(c.deletedGroups, namespace+"/"+groupName)
+	return nil
+}
+
+func (c *sweepClient) DeleteNamespace(_ context.Context, namespace string, _ string) error {
+	c.deletedNamespaces = append(c.deletedNamespaces, namespace)
+	return nil
+}
+
+func TestSweepTenant(t *testing.T) {
+	ours := func(name string) rulefmt.RuleGroup {
+		return rulefmt.RuleGroup{
+			Name:  name,
+			Rules: []rulefmt.Rule{{Alert: "A", Labels: map[string]string{utils.OwnerLabel: "default/rules"}}},
+		}
+	}
+	handPushed := rulefmt.RuleGroup{Name: "manual", Rules: []rulefmt.Rule{{Alert: "B"}}}
+	mimirClient := &sweepClient{
+		MockAwarenessClient: clients.NewMockAwarenessClient(),
+		namespaces: map[string][]rulefmt.RuleGroup{
+			"active":   {ours("kept")},
+			"orphaned": {ours("a"), ours("b")},
+			"shared":   {ours("c"), handPushed},
+			"foreign":  {handPushed},
+		},
+	}
+
+	sweeper := &Sweeper{}
+	owned := map[string]struct{}{"active": {}}
+	if err := sweeper.sweepTenant(context.Background(), mimirClient, "tenant", owned); err != nil {
+		t.Fatalf("sweepTenant() error = %v", err)
+	}
+	if !slices.Equal(mimirClient.deletedNamespaces, []string{"orphaned"}) {
+		t.Errorf("expected only the fully orphaned namespace to be deleted, got %v", mimirClient.deletedNamespaces)
+	}
+	if !slices.Equal(mimirClient.deletedGroups, []string{"shared/c"}) {
+		t.Errorf("expected only the orphaned group of the shared namespace to be deleted, got %v",
+			mimirClient.deletedGroups)
+	}
+}
+
+func TestOwnedNamespacesAndTenants(t *testing.T) {
+	rules := []monitoringv1.PrometheusRule{
+		{ObjectMeta: metav1.ObjectMeta{Name: "a", Namespace: "team-a", Annotations: map[string]string{
+			utils.ClientNameAnnotation:  "mimir",
+			utils.MimirTenantAnnotation: "tenant-a",
+		}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "b", Namespace: "team-b", Annotations: map[string]string{
+			utils.ClientNameAnnotation: "mimir",
+		}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "c", Namespace: "team-c", Annotations: map[string]string{
+			utils.ClientNameAnnotation:  "other",
+			utils.MimirTenantAnnotation: "tenant-c",
+		}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "e", Namespace: "team-e", Annotations: map[string]string{
+			utils.MimirTenantAnnotation: "tenant-e",
+		}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "f", Namespace: "team-f", Annotations: map[string]string{
+			utils.ClientNameAnnotation:           "mimir",
+			utils.MimirTenantAnnotation:          "tenant-f",
+			utils.RulesNamespaceAnnotation:       "alerts",
+			utils.SyncedRulesNamespaceAnnotation: "legacy",
+		}}},
+	}
+	clientConfigs := []openawarenessv1beta1.ClientConfig{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "mimir", Namespace: "team-e"},
+			Spec:       openawarenessv1beta1.ClientConfigSpec{Default: true},
+		},
+	}
+	alertTenants := []openawarenessv1beta1.MimirAlertTenant{
+		{ObjectMeta: metav1.ObjectMeta{Name: "t", Namespace: "team-d", Annotations: map[string]string{
+			utils.ClientNameAnnotation:  "mimir",
+			utils.MimirTenantAnnotation: "tenant-d",
+		}}},
+	}
+
+	owned := ownedNamespaces(&clientConfigs[0], rules, clientConfigs, nil)
+	if _, ok := owned["tenant-a"]["team-a"]; !ok {
+		t.Error("expected team-a to be owned for tenant-a")
+	}
+	if _, ok := owned[utils.DefaultTenantID]["team-b"]; !ok {
+		t.Error("expected team-b to be owned for the default tenant")
+	}
+	if _, ok := owned["tenant-c"]; ok {
+		t.Error("expected rules of other clients to be ignored")
+	}
+	if _, ok := owned["tenant-e"]["team-e"]; !ok {
+		t.Error("expected team-e to be owned through the default ClientConfig")
+	}
+	if _, ok := owned["tenant-f"]["team-f"]; ok || len(owned["tenant-f"]) != 2 {
+		t.Errorf("expected the rule and synced namespaces to be owned for tenant-f, got %v", owned["tenant-f"])
+	}
+
+	tenants := knownTenants(&clientConfigs[0], rules, alertTenants, clientConfigs, nil)
+	for _, tenantID := range []string{"tenant-a", "tenant-d", "tenant-e", "tenant-f", utils.DefaultTenantID} {
+		if _, ok := tenants[tenantID]; !ok {
+			t.Errorf("expected tenant %s to be known", tenantID)
+		}
+	}
+	if len(tenants) != 5 {
+		t.Errorf("expected 5 tenants, got %d", len(tenants))
+	}
+}
+
+func TestOwnedNamespacesOfSameNamedClientConfigs(t *testing.T) {
+	clientConfigs := []openawarenessv1beta1.ClientConfig{
+		{ObjectMeta: metav1.ObjectMeta{Name: "mimir", Namespace: "team-a"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "mimir", Namespace: "team-b"}},
+	}
+	rules := []monitoringv1.PrometheusRule{
+		{ObjectMeta: metav1.ObjectMeta{Name: "a", Namespace: "team-a", Annotations: map[string]string{
+			utils.ClientNameAnnotation:  "mimir",
+			utils.MimirTenantAnnotation: "tenant-a",
+		}}},
+	}
+
+	if _, ok := ownedNamespaces(&clientConfigs[0], rules, clientConfigs, nil)["tenant-a"]["team-a"]; !ok {
+		t.Error("expected team-a to be owned through the ClientConfig of its namespace")
+	}
+	if owned := ownedNamespaces(&clientConfigs[1], rules, clientConfigs, nil); len(owned) != 0 {
+		t.Errorf("expected nothing to be owned through the same-named ClientConfig of team-b, got %v", owned)
+	}
+	if _, ok := knownTenants(&clientConfigs[1], rules, nil, clientConfigs, nil)["tenant-a"]; ok {
+		t.Error("expected tenant-a not to be known through the same-named ClientConfig of team-b")
+	}
+}
@@ -0,0 +1,214 @@
+// Package ksm generates the kube-state-metrics custom resource state configuration exposing the
+// statuses of the openawareness resources as metrics.
+package ksm
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	openawarenessv1beta1 "github.com/syndlex/openawareness-controller/api/openawareness/v1beta1"
+	"gopkg.in/yaml.v3"
+)
+
+// MetricNamePrefix prefixes the names of all metrics, followed by the lower-case kind
+const MetricNamePrefix = "openawareness"
+
+// header marks the rendered configuration as generated
+const header = "# Code generated by `manager ksm-config`. DO NOT EDIT.\n"
+
+// Config is a kube-state-metrics CustomResourceStateMetrics configuration.
+type Config struct {
+	Kind string `yaml:"kind"`
+	Spec Spec   `yaml:"spec"`
+}
+
+// Spec lists the resources whose metrics are generated.
+type Spec struct {
+	Resources []Resource `yaml:"resources"`
+}
+
+// Resource generates the metrics of one kind.
+type Resource struct {
+	GroupVersionKind GroupVersionKind `yaml:"groupVersionKind"`
+	MetricNamePrefix string           `yaml:"metricNamePrefix"`
+	LabelsFromPath   map[string]Path  `yaml:"labelsFromPath"`
+	Metrics          []Metric         `yaml:"metrics"`
+}
+
+// GroupVersionKind identifies the kind of a Resource.
+type GroupVersionKind struct {
+	Group   string `yaml:"group"`
+	Version string `yaml:"version"`
+	Kind    string `yaml:"kind"`
+}
+
+// Metric is a metric generated for every object of a Resource.
+type Metric struct {
+	Name string `yaml:"name"`
+	Help string `yaml:"help"`
+	Each Each   `yaml:"each"`
+}
+
+// Each defines how the values of a Metric are read, Type selects the field that applies.
+type Each struct {
+	Type     string    `yaml:"type"`
+	Gauge    *Gauge    `yaml:"gauge,omitempty"`
+	StateSet *StateSet `yaml:"stateSet,omitempty"`
+}
+
+// Gauge reads a numeric value, timestamps are converted to Unix seconds and "True" and "False" to 1 and 0.
+type Gauge struct {
+	Path           Path            `yaml:"path"`
+	ValueFrom      Path            `yaml:"valueFrom,omitempty"`
+	LabelsFromPath map[string]Path `yaml:"labelsFromPath,omitempty"`
+	NilIsZero      bool            `yaml:"nilIsZero,omitempty"`
+}
+
+// StateSet reports a series per possible value of an enum field, 1 for the current value.
+type StateSet struct {
+	Path      Path     `yaml:"path"`
+	LabelName string   `yaml:"labelName"`
+	List      []string `yaml:"list"`
+}
+
+// Path is a path to a field of an object, rendered in flow style.
+type Path []string
+
+// MarshalYAML renders the path in flow style, e.g. [status, conditions].
+func (p Path) MarshalYAML() (interface{}, error) {
+	node := &yaml.Node{Kind: yaml.SequenceNode, Style: yaml.FlowStyle}
+	for _, segment := range p {
+		node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: segment})
+	}
+	return node, nil
+}
+
+// NewConfig returns the configuration of the metrics of ClientConfigs, MimirAlertTenants and
+// PrometheusRuleSyncs. The enum values of the StateSets are taken from the API types, so the
+// configuration follows them when it is rendered again.
+func NewConfig() Config {
+	return Config{
+		Kind: "CustomResourceStateMetrics",
+		Spec: Spec{Resources: []Resource{
+			resource("ClientConfig",
+				stateSetMetric("connection_status", "Connection status of the ClientConfig.",
+					Path{"status", "connectionStatus"},
+					string(openawarenessv1beta1.ConnectionStatusConnected),
+					string(openawarenessv1beta1.ConnectionStatusDisconnected)),
+				gaugeMetric("last_connection_time_seconds",
+					"Unix time of the last successful connection of the ClientConfig.",
+					Path{"status", "lastConnectionTime"}),
+				gaugeMetric("tenants", "Number of tenants listed through the Mimir admin API.",
+					Path{"status", "tenants", "count"}),
+				conditionMetric("ClientConfig"),
+				gaugeMetric("generation", "Generation of the ClientConfig.", Path{"metadata", "generation"}),
+				gaugeMetric("observed_generation", "Generation of the ClientConfig the status is based upon.",
+					Path{"status", "observedGeneration"}),
+			),
+			resource("MimirAlertTenant",
+				stateSetMetric("sync_status", "Sync status of the Alertmanager configuration.",
+					Path{"status", "syncStatus"},
+					openawarenessv1beta1.SyncStatusSynced,
+					openawarenessv1beta1.SyncStatusFailed,
+					openawarenessv1beta1.SyncStatusPending,
+					openawarenessv1beta1.SyncStatusPaused,
+					openawarenessv1beta1.SyncStatusFallback),
+				stateSetMetric("configuration_validation", "Validation result of the Alertmanager configuration.",
+					Path{"status", "configurationValidation"},
+					openawarenessv1beta1.ConfigValidationValid,
+					openawarenessv1beta1.ConfigValidationInvalid),
+				gaugeMetric("last_sync_time_seconds",
+					"Unix time of the last successful sync of the Alertmanager configuration.",
+					Path{"status", "lastSyncTime"}),
+				conditionMetric("MimirAlertTenant"),
+				gaugeMetric("generation", "Generation of the MimirAlertTenant.", Path{"metadata", "generation"}),
+				gaugeMetric("observed_generation", "Generation of the MimirAlertTenant the status is based upon.",
+					Path{"status", "observedGeneration"}),
+			),
+			withPrefix(resource("PrometheusRuleSync",
+				countMetric("groups_desired", "Rule groups of the PrometheusRule over all its tenants.",
+					Path{"status", "groupsDesired"}),
+				countMetric("groups_synced", "Rule groups of the PrometheusRule synced to Mimir.",
+					Path{"status", "groupsSynced"}),
+				countMetric("groups_failed", "Rule groups of the PrometheusRule that could not be synced to Mimir.",
+					Path{"status", "groupsFailed"}),
+				conditionMetric("PrometheusRule sync"),
+			), MetricNamePrefix+"_prometheusrule"),
+		}},
+	}
+}
+
+// Render returns the configuration as YAML, marked as generated.
+func Render(config Config) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(header)
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(config); err != nil {
+		return nil, fmt.Errorf("encoding the custom resource state configuration: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, fmt.Errorf("encoding the custom resource state configuration: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// resource returns the metrics of kind, labeled with the name and namespace of the object.
+func resource(kind string, metrics ...Metric) Resource {
+	return Resource{
+		GroupVersionKind: GroupVersionKind{
+			Group:   openawarenessv1beta1.GroupVersion.Group,
+			Version: openawarenessv1beta1.GroupVersion.Version,
+			Kind:    kind,
+		},
+		MetricNamePrefix: MetricNamePrefix + "_" + strings.ToLower(kind),
+		LabelsFromPath: map[string]Path{
+			"name":      {"metadata", "name"},
+			"namespace": {"metadata", "namespace"},
+		},
+		Metrics: metrics,
+	}
+}
+
+// withPrefix returns r with the metric name prefix replaced.
+func withPrefix(r Resource, prefix string) Resource {
+	r.MetricNamePrefix = prefix
+	return r
+}
+
+// gaugeMetric returns a gauge of the value at path, absent while the field is not set.
+func gaugeMetric(name, help string, path Path) Metric {
+	return Metric{Name: name, Help: help, Each: Each{Type: "Gauge", Gauge: &Gauge{Path: path}}}
+}
+
+// countMetric returns a gauge of the count at path, zero while the field is not set.
+func countMetric(name, help string, path Path) Metric {
+	metric := gaugeMetric(name, help, path)
+	metric.Each.Gauge.NilIsZero = true
+	return metric
+}
+
+// stateSetMetric returns a state set of the enum field at path with the values list.
+func stateSetMetric(name, help string, path Path, list ...string) Metric {
+	return Metric{Name: name, Help: help, Each: Each{
+		Type:     "StateSet",
+		StateSet: &StateSet{Path: path, LabelName: "status", List: list},
+	}}
+}
+
+// conditionMetric returns a gauge per status condition, 1 if the condition is True.
+func conditionMetric(subject string) Metric {
+	return Metric{
+		Name: "status_condition",
+		Help: fmt.Sprintf("Conditions of the %s, 1 if the condition is True.", subject),
+		Each: Each{Type: "Gauge", Gauge: &Gauge{
+			Path:      Path{"status", "conditions"},
+			ValueFrom: Path{"status"},
+			LabelsFromPath: map[string]Path{
+				"type":   {"type"},
+				"reason": {"reason"},
+			},
+		}},
+	}
+}
@@ -0,0 +1,74 @@
+package ksm
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestRenderedConfigIsCurrent(t *testing.T) {
+	rendered, err := Render(NewConfig())
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	committed, err := os.ReadFile(filepath.Join("..", "..", "config", "kube-state-metrics", "custom-resource-state.yaml"))
+	if err != nil {
+		t.Fatalf("reading the committed configuration: %v", err)
+	}
+	if string(committed) != string(rendered) {
+		t.Errorf("config/kube-state-metrics/custom-resource-state.yaml is outdated, run `make ksm-config`")
+	}
+}
+
+// crdEnum returns the enum values of the status field of the CRD of plural.
+func crdEnum(t *testing.T, plural, field string) []string {
+	t.Helper()
+	content, err := os.ReadFile(filepath.Join("..", "..", "config", "crd", "bases", "openawareness.syndlex_"+plural+".yaml"))
+	if err != nil {
+		t.Fatalf("reading the CRD: %v", err)
+	}
+	var crd struct {
+		Spec struct {
+			Versions []struct {
+				Schema struct {
+					OpenAPIV3Schema struct {
+						Properties map[string]struct {
+							Properties map[string]struct {
+								Enum []string `yaml:"enum"`
+							} `yaml:"properties"`
+						} `yaml:"properties"`
+					} `yaml:"openAPIV3Schema"`
+				} `yaml:"schema"`
+			} `yaml:"versions"`
+		} `yaml:"spec"`
+	}
+	if err := yaml.Unmarshal(content, &crd); err != nil {
+		t.Fatalf("parsing the CRD: %v", err)
+	}
+	return crd.Spec.Versions[0].Schema.OpenAPIV3Schema.Properties["status"].Properties[field].Enum
+}
+
+func TestStateSetsCoverEnums(t *testing.T) {
+	plurals := map[string]string{"ClientConfig": "clientconfigs", "MimirAlertTenant": "mimiralerttenants"}
+	checked := 0
+	for _, resource := range NewConfig().Spec.Resources {
+		for _, metric := range resource.Metrics {
+			if metric.Each.StateSet == nil {
+				continue
+			}
+			path := metric.Each.StateSet.Path
+			enum := crdEnum(t, plurals[resource.GroupVersionKind.Kind], path[len(path)-1])
+			if strings.Join(metric.Each.StateSet.List, ",") != strings.Join(enum, ",") {
+				t.Errorf("%s %s lists %v, the CRD allows %v",
+					resource.GroupVersionKind.Kind, metric.Name, metric.Each.StateSet.List, enum)
+			}
+			checked++
+		}
+	}
+	if checked != 3 {
+		t.Errorf("expected 3 state sets, checked %d", checked)
+	}
+}
@@ -0,0 +1,73 @@
+// Package logging standardizes the structured keys of the controller logs and samples
+// high-frequency success logs.
+package logging
+
+import (
+	"context"
+	"sync"
+
+	"github.com/go-logr/logr"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// Structured keys shared by all controllers, so log searches and dashboards can rely on them.
+const (
+	// KeyResource is the name of the reconciled resource
+	KeyResource = "resource"
+	// KeyNamespace is the Kubernetes namespace of the reconciled resource
+	KeyNamespace = "namespace"
+	// KeyTenant is the Mimir tenant (org ID) an operation applies to
+	KeyTenant = "tenant"
+	// KeyClient is the name of the ClientConfig used to reach Mimir
+	KeyClient = "client"
+	// KeyOperation is the operation logged, e.g. the stage of a sync
+	KeyOperation = "operation"
+	// KeyAttempt counts the reconciliations of a resource since its last successful one, from 1
+	KeyAttempt = "attempt"
+)
+
+// LogConstructor returns the controller.Options LogConstructor of the named controller. It
+// replaces the keys controller-runtime adds per request by KeyResource and KeyNamespace.
+func LogConstructor(base logr.Logger, controllerName string) func(*reconcile.Request) logr.Logger {
+	base = base.WithValues("controller", controllerName)
+	return func(req *reconcile.Request) logr.Logger {
+		if req == nil {
+			return base
+		}
+		return base.WithValues(KeyResource, req.Name, KeyNamespace, req.Namespace)
+	}
+}
+
+// TrackAttempts wraps a reconciler to add KeyAttempt to the logger of every reconciliation.
+// Reconciliations returning an error count as failed attempts, any other result resets the count.
+func TrackAttempts(next reconcile.Reconciler) reconcile.Reconciler {
+	return &attemptTracker{next: next, failures: map[reconcile.Request]int{}}
+}
+
+// attemptTracker counts the consecutive failed reconciliations of every request.
+type attemptTracker struct {
+	next reconcile.Reconciler
+
+	mu       sync.Mutex
+	failures map[reconcile.Request]int
+}
+
+// Reconcile implements reconcile.Reconciler.
+func (t *attemptTracker) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	t.mu.Lock()
+	attempt := t.failures[req] + 1
+	t.mu.Unlock()
+
+	ctx = log.IntoContext(ctx, log.FromContext(ctx).WithValues(KeyAttempt, attempt))
+	result, err := t.next.Reconcile(ctx, req)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if err != nil {
+		t.failures[req] = attempt
+	} else {
+		delete(t.failures, req)
+	}
+	return result, err
+}
@@ -0,0 +1,71 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr/funcr"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// failingReconciler logs every reconciliation and fails while fail is set
+type failingReconciler struct {
+	fail bool
+}
+
+func (r *failingReconciler) Reconcile(ctx context.Context, _ reconcile.Request) (reconcile.Result, error) {
+	log.FromContext(ctx).Info("reconciling")
+	if r.fail {
+		return reconcile.Result{}, errors.New("unavailable")
+	}
+	return reconcile.Result{}, nil
+}
+
+func TestTrackAttempts(t *testing.T) {
+	var lines []string
+	logger := funcr.New(func(_, args string) { lines = append(lines, args) }, funcr.Options{})
+	ctx := log.IntoContext(context.Background(), logger)
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "team-a", Name: "rules"}}
+	next := &failingReconciler{fail: true}
+	tracker := TrackAttempts(next)
+
+	for range 2 {
+		_, _ = tracker.Reconcile(ctx, req)
+	}
+	next.fail = false
+	_, _ = tracker.Reconcile(ctx, req)
+	_, _ = tracker.Reconcile(ctx, req)
+
+	want := []string{`"attempt"=1`, `"attempt"=2`, `"attempt"=3`, `"attempt"=1`}
+	if len(lines) != len(want) {
+		t.Fatalf("got %d log lines, want %d: %v", len(lines), len(want), lines)
+	}
+	for i, line := range lines {
+		if !strings.Contains(line, want[i]) {
+			t.Errorf("line %d = %s, want it to contain %s", i, line, want[i])
+		}
+	}
+}
+
+func TestLogConstructor(t *testing.T) {
+	var line string
+	base := funcr.New(func(_, args string) { line = args }, funcr.Options{})
+	construct := LogConstructor(base, "prometheusrule")
+
+	req := &reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "team-a", Name: "rules"}}
+	construct(req).Info("reconciling")
+	for _, want := range []string{`"controller"="prometheusrule"`, `"resource"="rules"`, `"namespace"="team-a"`} {
+		if !strings.Contains(line, want) {
+			t.Errorf("log line %s does not contain %s", line, want)
+		}
+	}
+
+	construct(nil).Info("starting")
+	if strings.Contains(line, `"resource"`) {
+		t.Errorf("log line %s without request contains the resource", line)
+	}
+}
@@ -0,0 +1,107 @@
+package logging
+
+import (
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+)
+
+// KeySampled is the number of logs of the same key sampled out since the previous one
+const KeySampled = "sampled"
+
+// Sampler limits high-frequency success logs to one per Interval and key, e.g. per resource.
+// Logs sampled out are written at V(1) instead, so they are still available with verbose
+// logging. The zero value and a nil Sampler log everything.
+type Sampler struct {
+	// Interval between two logs of the same key, 0 disables sampling
+	Interval time.Duration
+
+	mu        sync.Mutex
+	entries   map[string]*sampleEntry
+	lastPrune time.Time
+	now       func() time.Time
+}
+
+// sampleEntry is the last log of a key and the logs sampled out since.
+type sampleEntry struct {
+	logged  time.Time
+	sampled int
+}
+
+// NewSampler returns a Sampler logging once per interval and key.
+func NewSampler(interval time.Duration) *Sampler {
+	return &Sampler{Interval: interval}
+}
+
+// Info logs msg like logger.Info if no log of the same msg and key was written within the
+// interval, adding KeySampled if logs were sampled out since. Otherwise msg is logged at V(1).
+func (s *Sampler) Info(logger logr.Logger, key, msg string, keysAndValues ...any) {
+	sampled, ok := s.allow(msg + "\x00" + key)
+	if !ok {
+		logger.V(1).Info(msg, keysAndValues...)
+		return
+	}
+	if sampled > 0 {
+		keysAndValues = append(keysAndValues, KeySampled, sampled)
+	}
+	logger.Info(msg, keysAndValues...)
+}
+
+// allow reports whether a log of key is due and how many were sampled out since the last one.
+// Entries older than the interval are pruned at most once per interval, they are due anyway but
+// lose the count of logs sampled out.
+func (s *Sampler) allow(key string) (int, bool) {
+	if s == nil {
+		return 0, true
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.Interval <= 0 {
+		return 0, true
+	}
+
+	now := time.Now()
+	if s.now != nil {
+		now = s.now()
+	}
+	if s.entries == nil {
+		s.entries = map[string]*sampleEntry{}
+	}
+	entry, exists := s.entries[key]
+	if exists && now.Sub(entry.logged) < s.Interval {
+		entry.sampled++
+		return 0, false
+	}
+	sampled := 0
+	if exists {
+		sampled = entry.sampled
+	}
+	if now.Sub(s.lastPrune) >= s.Interval {
+		for k, entry := range s.entries {
+			if now.Sub(entry.logged) >= s.Interval {
+				delete(s.entries, k)
+			}
+		}
+		s.lastPrune = now
+	}
+	s.entries[key] = &sampleEntry{logged: now}
+	return sampled, true
+}
+
+// successLogs samples the success logs of all controllers, see SetSuccessLogInterval
+var successLogs = &Sampler{}
+
+// SetSuccessLogInterval sets the interval between two success logs of the same key written
+// through SuccessInfo, 0 disables sampling. It is meant to be called once on start.
+func SetSuccessLogInterval(interval time.Duration) {
+	successLogs.mu.Lock()
+	defer successLogs.mu.Unlock()
+	successLogs.Interval = interval
+}
+
+// SuccessInfo logs a high-frequency success message through the shared Sampler, see
+// Sampler.Info. key identifies what succeeded, e.g. the namespace and name of a resource.
+func SuccessInfo(logger logr.Logger, key, msg string, keysAndValues ...any) {
+	successLogs.Info(logger, key, msg, keysAndValues...)
+}
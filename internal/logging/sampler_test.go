@@ -0,0 +1,52 @@
+package logging
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSamplerAllow(t *testing.T) {
+	now := time.Unix(0, 0)
+	sampler := &Sampler{Interval: time.Minute, now: func() time.Time { return now }}
+
+	steps := []struct {
+		advance     time.Duration
+		key         string
+		wantAllowed bool
+		wantSampled int
+	}{
+		{key: "a", wantAllowed: true},
+		{key: "a", wantAllowed: false},
+		{advance: 30 * time.Second, key: "a", wantAllowed: false},
+		// Keys are sampled independently
+		{key: "b", wantAllowed: true},
+		{advance: 30 * time.Second, key: "a", wantAllowed: true, wantSampled: 2},
+		{key: "a", wantAllowed: false},
+	}
+	for i, step := range steps {
+		now = now.Add(step.advance)
+		sampled, allowed := sampler.allow(step.key)
+		if allowed != step.wantAllowed || sampled != step.wantSampled {
+			t.Errorf("step %d: allow(%q) = %d, %v, want %d, %v",
+				i, step.key, sampled, allowed, step.wantSampled, step.wantAllowed)
+		}
+	}
+
+	// Keys not logged within the interval are pruned
+	now = now.Add(2 * time.Minute)
+	sampler.allow("c")
+	if _, exists := sampler.entries["b"]; exists {
+		t.Errorf("expected the entry of key b to be pruned")
+	}
+}
+
+func TestSamplerDisabled(t *testing.T) {
+	var nilSampler *Sampler
+	for _, sampler := range []*Sampler{nilSampler, {}} {
+		for range 3 {
+			if sampled, allowed := sampler.allow("a"); !allowed || sampled != 0 {
+				t.Errorf("allow() = %d, %v, want 0, true", sampled, allowed)
+			}
+		}
+	}
+}
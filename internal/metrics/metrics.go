@@ -0,0 +1,208 @@
+// Package metrics provides the custom Prometheus metrics of the controller, complementing
+// the default controller-runtime metrics with resource kind and sync outcome labels.
+package metrics
+
+import (
+	"net/http"
+	"time"
+	"unicode/utf8"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	ctrl "sigs.k8s.io/controller-runtime"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// Resource kinds used as the kind label
+const (
+	KindPrometheusRule   = "PrometheusRule"
+	KindMimirAlertTenant = "MimirAlertTenant"
+)
+
+// Sync outcomes used as the outcome label
+const (
+	// OutcomeSuccess is a reconciliation that completed without retry
+	OutcomeSuccess = "success"
+	// OutcomeRequeue is a reconciliation that is retried after a delay
+	OutcomeRequeue = "requeue"
+	// OutcomeError is a reconciliation that returned an error
+	OutcomeError = "error"
+)
+
+// Exemplar label names identifying the synced tenant and ClientConfig
+const (
+	TenantExemplarLabel = "tenant"
+	ClientExemplarLabel = "client"
+)
+
+// OpenMetricsPath is the metrics server path exposing metrics in the OpenMetrics format.
+// Exemplars are only exposed in this format, which the default /metrics endpoint does not serve.
+const OpenMetricsPath = "/metrics/openmetrics"
+
+var (
+	// reconcileDuration is the duration of reconciliations by kind and outcome
+	reconcileDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "openawareness",
+		Name:      "reconcile_duration_seconds",
+		Help:      "Duration of reconciliations by resource kind and sync outcome.",
+		Buckets:   []float64{0.01, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60},
+	}, []string{"kind", "outcome"})
+
+	// queueDepth reports the depth of the work queue of every instrumented kind
+	queueDepth = newQueueDepthCollector()
+
+	// circuitBreakerOpen reports whether the circuit breaker of the Mimir client of a ClientConfig is open
+	circuitBreakerOpen = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "openawareness",
+		Name:      "mimir_circuit_breaker_open",
+		Help:      "Whether the circuit breaker of the Mimir client of a ClientConfig refuses requests.",
+	}, []string{"namespace", "client"})
+
+	// circuitBreakerTrips counts how often the circuit breaker of a ClientConfig opened
+	circuitBreakerTrips = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "openawareness",
+		Name:      "mimir_circuit_breaker_trips_total",
+		Help:      "Number of times the circuit breaker of the Mimir client of a ClientConfig opened.",
+	}, []string{"namespace", "client"})
+
+	// cachedClientCreated reports the creation time of every client in the client cache
+	cachedClientCreated = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "openawareness",
+		Name:      "client_cache_client_created_timestamp_seconds",
+		Help:      "Creation time of the cached client of a ClientConfig, in seconds since the epoch.",
+	}, []string{"namespace", "client"})
+
+	// cachedClientHealthy reports whether the last health check of a cached client succeeded
+	cachedClientHealthy = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "openawareness",
+		Name:      "client_cache_client_healthy",
+		Help:      "Whether the last health check of the cached client of a ClientConfig succeeded.",
+	}, []string{"namespace", "client"})
+
+	// cachedClientLastHealthCheck reports the time of the last health check of every cached client
+	cachedClientLastHealthCheck = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "openawareness",
+		Name:      "client_cache_client_last_health_check_timestamp_seconds",
+		Help:      "Time of the last health check of the cached client of a ClientConfig, in seconds since the epoch.",
+	}, []string{"namespace", "client"})
+)
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(reconcileDuration, queueDepth, circuitBreakerOpen, circuitBreakerTrips,
+		cachedClientCreated, cachedClientHealthy, cachedClientLastHealthCheck)
+}
+
+// Handler serves the controller-runtime registry in the OpenMetrics format, including exemplars.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(ctrlmetrics.Registry, promhttp.HandlerOpts{
+		ErrorHandling:     promhttp.HTTPErrorOnError,
+		EnableOpenMetrics: true,
+	})
+}
+
+// Reconciliation measures a single reconciliation. The tenant and ClientConfig are
+// attached as exemplar once known, to drill down from slow syncs to the affected tenant.
+type Reconciliation struct {
+	kind   string
+	start  time.Time
+	tenant string
+	client string
+}
+
+// StartReconciliation starts measuring a reconciliation of kind.
+func StartReconciliation(kind string) *Reconciliation {
+	return &Reconciliation{kind: kind, start: time.Now()}
+}
+
+// SetTarget records the tenant and ClientConfig the reconciliation syncs to.
+func (r *Reconciliation) SetTarget(tenant, client string) {
+	r.tenant = tenant
+	r.client = client
+}
+
+// Done observes the duration of the reconciliation with the outcome derived from its result.
+func (r *Reconciliation) Done(result ctrl.Result, err error) {
+	observer := reconcileDuration.WithLabelValues(r.kind, Outcome(result, err))
+	duration := time.Since(r.start).Seconds()
+
+	exemplar := r.exemplar()
+	if exemplar == nil {
+		observer.Observe(duration)
+		return
+	}
+	observer.(prometheus.ExemplarObserver).ObserveWithExemplar(duration, exemplar)
+}
+
+// exemplar returns the exemplar labels of the reconciliation, nil if the target is unknown
+// or the labels exceed the exemplar size limit.
+func (r *Reconciliation) exemplar() prometheus.Labels {
+	labels := prometheus.Labels{}
+	if r.tenant != "" {
+		labels[TenantExemplarLabel] = r.tenant
+	}
+	if r.client != "" {
+		labels[ClientExemplarLabel] = r.client
+	}
+	if len(labels) == 0 {
+		return nil
+	}
+
+	runes := 0
+	for name, value := range labels {
+		runes += utf8.RuneCountInString(name) + utf8.RuneCountInString(value)
+	}
+	if runes > prometheus.ExemplarMaxRunes {
+		return nil
+	}
+	return labels
+}
+
+// SetCircuitBreakerOpen records whether the circuit breaker of the Mimir client of the
+// ClientConfig with the given namespace and name is open, counting a trip whenever it opens.
+func SetCircuitBreakerOpen(namespace, client string, open bool) {
+	if !open {
+		circuitBreakerOpen.WithLabelValues(namespace, client).Set(0)
+		return
+	}
+	circuitBreakerOpen.WithLabelValues(namespace, client).Set(1)
+	circuitBreakerTrips.WithLabelValues(namespace, client).Inc()
+}
+
+// DeleteCircuitBreaker removes the circuit breaker metrics of the ClientConfig with the given
+// namespace and name whose client was removed.
+func DeleteCircuitBreaker(namespace, client string) {
+	circuitBreakerOpen.DeleteLabelValues(namespace, client)
+	circuitBreakerTrips.DeleteLabelValues(namespace, client)
+}
+
+// SetCachedClient records the creation time of the cached client of the ClientConfig with the
+// given namespace and name, the time of its last health check and whether it succeeded.
+func SetCachedClient(namespace, client string, created, lastHealthCheck time.Time, healthy bool) {
+	cachedClientCreated.WithLabelValues(namespace, client).Set(float64(created.Unix()))
+	cachedClientLastHealthCheck.WithLabelValues(namespace, client).Set(float64(lastHealthCheck.Unix()))
+	value := 0.0
+	if healthy {
+		value = 1
+	}
+	cachedClientHealthy.WithLabelValues(namespace, client).Set(value)
+}
+
+// DeleteCachedClient removes the client cache metrics of the ClientConfig with the given
+// namespace and name whose client was removed.
+func DeleteCachedClient(namespace, client string) {
+	cachedClientCreated.DeleteLabelValues(namespace, client)
+	cachedClientHealthy.DeleteLabelValues(namespace, client)
+	cachedClientLastHealthCheck.DeleteLabelValues(namespace, client)
+}
+
+// Outcome returns the sync outcome of a reconciliation with the given result.
+func Outcome(result ctrl.Result, err error) string {
+	switch {
+	case err != nil:
+		return OutcomeError
+	case result.RequeueAfter > 0:
+		return OutcomeRequeue
+	default:
+		return OutcomeSuccess
+	}
+}
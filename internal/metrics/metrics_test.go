@@ -0,0 +1,128 @@
+package metrics
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// scrape returns the metrics served by Handler in the OpenMetrics format.
+func scrape(t *testing.T) string {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, OpenMetricsPath, nil)
+	req.Header.Set("Accept", "application/openmetrics-text; version=1.0.0")
+	rec := httptest.NewRecorder()
+	Handler().ServeHTTP(rec, req)
+	body, err := io.ReadAll(rec.Result().Body)
+	if err != nil {
+		t.Fatalf("reading metrics: %v", err)
+	}
+	return string(body)
+}
+
+func TestOutcome(t *testing.T) {
+	tests := []struct {
+		name     string
+		result   ctrl.Result
+		err      error
+		expected string
+	}{
+		{name: "success", expected: OutcomeSuccess},
+		{name: "requeue", result: ctrl.Result{RequeueAfter: time.Second}, expected: OutcomeRequeue},
+		{name: "error", result: ctrl.Result{RequeueAfter: time.Second}, err: errors.New("boom"), expected: OutcomeError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Outcome(tt.result, tt.err); got != tt.expected {
+				t.Errorf("Outcome() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestReconciliationExemplar(t *testing.T) {
+	reconciliation := StartReconciliation(KindMimirAlertTenant)
+	reconciliation.SetTarget("team-a", "mimir")
+	reconciliation.Done(ctrl.Result{}, errors.New("boom"))
+
+	// Too long to be attached as exemplar, still observed
+	reconciliation = StartReconciliation(KindPrometheusRule)
+	reconciliation.SetTarget(strings.Repeat("t", 200), "mimir")
+	reconciliation.Done(ctrl.Result{}, nil)
+
+	metrics := scrape(t)
+	if !strings.Contains(metrics, `openawareness_reconcile_duration_seconds_count{kind="MimirAlertTenant",outcome="error"} 1`) {
+		t.Errorf("expected an errored MimirAlertTenant reconciliation, got:\n%s", metrics)
+	}
+	if !strings.Contains(metrics, `tenant="team-a"`) || !strings.Contains(metrics, `client="mimir"`) {
+		t.Errorf("expected an exemplar with tenant and client, got:\n%s", metrics)
+	}
+	if !strings.Contains(metrics, `openawareness_reconcile_duration_seconds_count{kind="PrometheusRule",outcome="success"} 1`) {
+		t.Errorf("expected a successful PrometheusRule reconciliation, got:\n%s", metrics)
+	}
+}
+
+func TestQueueDepth(t *testing.T) {
+	queue := NewQueue(KindPrometheusRule)("prometheusrule",
+		workqueue.DefaultTypedControllerRateLimiter[reconcile.Request]())
+	t.Cleanup(queue.ShutDown)
+
+	// Duplicates are queued once
+	queue.Add(reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "a"}})
+	queue.Add(reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "a"}})
+	queue.Add(reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "b"}})
+
+	if metrics := scrape(t); !strings.Contains(metrics, `openawareness_queue_depth{kind="PrometheusRule"} 2`) {
+		t.Errorf("expected a queue depth of 2, got:\n%s", metrics)
+	}
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	SetCircuitBreakerOpen("monitoring", "flaky", true)
+	SetCircuitBreakerOpen("monitoring", "flaky", false)
+	SetCircuitBreakerOpen("monitoring", "flaky", true)
+
+	metrics := scrape(t)
+	if !strings.Contains(metrics, `openawareness_mimir_circuit_breaker_open{client="flaky",namespace="monitoring"} 1`) {
+		t.Errorf("expected an open circuit breaker, got:\n%s", metrics)
+	}
+	if !strings.Contains(metrics, `openawareness_mimir_circuit_breaker_trips_total{client="flaky",namespace="monitoring"} 2`) {
+		t.Errorf("expected 2 trips, got:\n%s", metrics)
+	}
+
+	DeleteCircuitBreaker("monitoring", "flaky")
+	if metrics := scrape(t); strings.Contains(metrics, `client="flaky"`) {
+		t.Errorf("expected the circuit breaker metrics to be removed, got:\n%s", metrics)
+	}
+}
+
+func TestCachedClient(t *testing.T) {
+	SetCachedClient("monitoring", "mimir", time.Unix(1700000000, 0), time.Unix(1700000000, 0), true)
+	SetCachedClient("monitoring", "mimir", time.Unix(1700000000, 0), time.Unix(1800000000, 0), false)
+
+	metrics := scrape(t)
+	if !strings.Contains(metrics, `openawareness_client_cache_client_created_timestamp_seconds{client="mimir",namespace="monitoring"} 1.7e+09`) {
+		t.Errorf("expected the creation time of the client, got:\n%s", metrics)
+	}
+	if !strings.Contains(metrics, `openawareness_client_cache_client_healthy{client="mimir",namespace="monitoring"} 0`) {
+		t.Errorf("expected an unhealthy client, got:\n%s", metrics)
+	}
+	if !strings.Contains(metrics, `openawareness_client_cache_client_last_health_check_timestamp_seconds{client="mimir",namespace="monitoring"} 1.8e+09`) {
+		t.Errorf("expected the time of the last health check, got:\n%s", metrics)
+	}
+
+	DeleteCachedClient("monitoring", "mimir")
+	if metrics := scrape(t); strings.Contains(metrics, `openawareness_client_cache_client_healthy{client="mimir",namespace="monitoring"}`) {
+		t.Errorf("expected the client cache metrics to be removed, got:\n%s", metrics)
+	}
+}
@@ -0,0 +1,67 @@
+package metrics
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/controller/priorityqueue"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// queueDepthDesc describes the work queue depth by resource kind
+var queueDepthDesc = prometheus.NewDesc(
+	"openawareness_queue_depth",
+	"Number of resources waiting for reconciliation by resource kind.",
+	[]string{"kind"}, nil,
+)
+
+// queueDepthCollector reads the length of the registered work queues on every scrape.
+type queueDepthCollector struct {
+	mu     sync.Mutex
+	queues map[string]workqueue.TypedInterface[reconcile.Request]
+}
+
+func newQueueDepthCollector() *queueDepthCollector {
+	return &queueDepthCollector{queues: map[string]workqueue.TypedInterface[reconcile.Request]{}}
+}
+
+// Describe implements prometheus.Collector.
+func (c *queueDepthCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- queueDepthDesc
+}
+
+// Collect implements prometheus.Collector.
+func (c *queueDepthCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for kind, queue := range c.queues {
+		ch <- prometheus.MustNewConstMetric(queueDepthDesc, prometheus.GaugeValue, float64(queue.Len()), kind)
+	}
+}
+
+// register reports the depth of queue for kind, replacing a previously registered queue.
+func (c *queueDepthCollector) register(kind string, queue workqueue.TypedInterface[reconcile.Request]) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.queues[kind] = queue
+}
+
+// NewQueue returns a controller.Options.NewQueue function creating the priority queue of
+// controller-runtime, so resources are synced in the order of their sync priority, and
+// reporting its depth as openawareness_queue_depth for kind.
+func NewQueue(kind string) func(
+	string, workqueue.TypedRateLimiter[reconcile.Request],
+) workqueue.TypedRateLimitingInterface[reconcile.Request] {
+	return func(
+		controllerName string, rateLimiter workqueue.TypedRateLimiter[reconcile.Request],
+	) workqueue.TypedRateLimitingInterface[reconcile.Request] {
+		queue := priorityqueue.New(controllerName, func(o *priorityqueue.Opts[reconcile.Request]) {
+			o.RateLimiter = rateLimiter
+			o.Log = log.Log.WithValues("controller", controllerName)
+		})
+		queueDepth.register(kind, queue)
+		return queue
+	}
+}
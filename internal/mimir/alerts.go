@@ -6,6 +6,7 @@ import (
 	"context"
 	"errors"
 	"io"
+	"strings"
 
 	pkgerrors "github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
@@ -20,15 +21,21 @@ type configCompat struct {
 	AlertmanagerConfig string            `yaml:"alertmanager_config"`
 }
 
+// AlertmanagerPayload returns the request body sent to Mimir for an Alertmanager
+// configuration and its template files.
+func AlertmanagerPayload(cfg string, templates map[string]string) ([]byte, error) {
+	return yaml.Marshal(&configCompat{
+		TemplateFiles:      templates,
+		AlertmanagerConfig: cfg,
+	})
+}
+
 // CreateAlertmanagerConfig creates or updates the Alertmanager configuration for the tenant.
 // It packages the configuration and templates into the required format and sends it to the Mimir API.
 // The tenantID parameter specifies which tenant this configuration belongs to.
 // Returns an error if marshaling or the API request fails.
 func (r *Client) CreateAlertmanagerConfig(ctx context.Context, cfg string, templates map[string]string, tenantID string) error {
-	payload, err := yaml.Marshal(&configCompat{
-		TemplateFiles:      templates,
-		AlertmanagerConfig: cfg,
-	})
+	payload, err := AlertmanagerPayload(cfg, templates)
 	if err != nil {
 		return err
 	}
@@ -71,8 +78,9 @@ func (r *Client) DeleteAlermanagerConfig(ctx context.Context, tenantID string) e
 // The tenantID parameter specifies which tenant's configuration to retrieve.
 // Returns the configuration string, template files map, and an error if the request or unmarshaling fails.
 // Returns empty strings and nil map when no configuration exists (404 Not Found).
+// The configuration is not downloaded again if the server reports it unchanged via ETag.
 func (r *Client) GetAlertmanagerConfig(ctx context.Context, tenantID string) (string, map[string]string, error) {
-	res, err := r.doRequest(ctx, alertmanagerAPI, "GET", nil, -1, tenantID)
+	body, err := r.getCached(ctx, alertmanagerAPI, tenantID)
 	if err != nil {
 		// Check if the error is ErrResourceNotFound (404) - this is expected when no config exists yet
 		// Use errors.Is to handle wrapped errors correctly
@@ -84,13 +92,6 @@ func (r *Client) GetAlertmanagerConfig(ctx context.Context, tenantID string) (st
 		return "", nil, err
 	}
 
-	defer func() { _ = res.Body.Close() }()
-
-	body, err := io.ReadAll(res.Body)
-	if err != nil {
-		return "", nil, err
-	}
-
 	compat := configCompat{}
 	err = yaml.Unmarshal(body, &compat)
 	if err != nil {
@@ -104,6 +105,20 @@ func (r *Client) GetAlertmanagerConfig(ctx context.Context, tenantID string) (st
 	return compat.AlertmanagerConfig, compat.TemplateFiles, nil
 }
 
+// UsesFallbackConfig reports whether Mimir serves the fallback Alertmanager configuration for a
+// tenant whose stored configuration, as returned by GetAlertmanagerConfig, is config.
+// Mimir uses the fallback configuration, if one is configured, for tenants without stored
+// configuration (404) or with a blank one; comments and whitespace count as blank.
+func UsesFallbackConfig(config string) bool {
+	for _, line := range strings.Split(config, "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && !strings.HasPrefix(line, "#") {
+			return false
+		}
+	}
+	return true
+}
+
 // GetAlertmanagerStatus retrieves the status of the Alertmanager for the tenant.
 // The tenantID parameter specifies which tenant's status to retrieve.
 // Returns the raw status response as a string, or an error if the request fails.
@@ -0,0 +1,50 @@
+package mimir
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestUsesFallbackConfig(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		body     string
+		fallback bool
+	}{
+		{name: "no stored configuration", status: http.StatusNotFound, fallback: true},
+		{name: "blank configuration", status: http.StatusOK, body: "alertmanager_config: \"\"\n", fallback: true},
+		{
+			name:     "comments only",
+			status:   http.StatusOK,
+			body:     "alertmanager_config: |\n  # managed-by: MimirAlertTenant ns/tenant\n\n",
+			fallback: true,
+		},
+		{
+			name:   "stored configuration",
+			status: http.StatusOK,
+			body:   "alertmanager_config: |\n  route:\n    receiver: default\n  receivers:\n  - name: default\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			t.Cleanup(server.Close)
+			client := newTestClient(t, server.URL)
+
+			config, _, err := client.GetAlertmanagerConfig(context.Background(), "tenant-a")
+			if err != nil {
+				t.Fatalf("GetAlertmanagerConfig: %v", err)
+			}
+			if got := UsesFallbackConfig(config); got != tt.fallback {
+				t.Errorf("UsesFallbackConfig(%q) = %v, want %v", config, got, tt.fallback)
+			}
+		})
+	}
+}
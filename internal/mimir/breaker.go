@@ -0,0 +1,122 @@
+package mimir
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultCircuitBreakerThreshold is the default number of consecutive 5xx responses opening
+	// the circuit breaker
+	DefaultCircuitBreakerThreshold = 10
+	// DefaultCircuitBreakerCoolDown is the default time requests are refused once the circuit
+	// breaker opened
+	DefaultCircuitBreakerCoolDown = time.Minute
+)
+
+// ErrCircuitOpen is returned for requests refused while the circuit breaker of a Client is open
+var ErrCircuitOpen = errors.New("circuit breaker open")
+
+// CircuitBreakerConfig configures the circuit breaker of a Client, which refuses requests for a
+// cool-down after consecutive server errors, so an outage of Mimir is not amplified by retries.
+type CircuitBreakerConfig struct {
+	// Threshold is the number of consecutive 5xx responses opening the circuit breaker,
+	// the circuit breaker is disabled if zero
+	Threshold int
+	// CoolDown is the time requests are refused once the circuit breaker opened,
+	// DefaultCircuitBreakerCoolDown if zero
+	CoolDown time.Duration
+	// OnChange is called with the new state whenever the circuit breaker opens or closes
+	OnChange func(open bool)
+}
+
+// circuitBreaker counts consecutive 5xx responses and refuses requests for a cool-down once
+// they reach the threshold. After the cool-down requests are let through again, and the next
+// 5xx response opens the circuit breaker immediately, while any other response closes it.
+// A nil circuitBreaker lets all requests through.
+type circuitBreaker struct {
+	cfg CircuitBreakerConfig
+	// now returns the current time, replaced in tests
+	now func() time.Time
+
+	mu        sync.Mutex
+	failures  int
+	openUntil time.Time
+	open      bool
+}
+
+// newCircuitBreaker returns the circuit breaker configured by cfg, nil if it is disabled.
+func newCircuitBreaker(cfg CircuitBreakerConfig) *circuitBreaker {
+	if cfg.Threshold <= 0 {
+		return nil
+	}
+	if cfg.CoolDown <= 0 {
+		cfg.CoolDown = DefaultCircuitBreakerCoolDown
+	}
+	return &circuitBreaker{cfg: cfg, now: time.Now}
+}
+
+// allow returns an error wrapping ErrCircuitOpen if requests are refused.
+func (b *circuitBreaker) allow() error {
+	if b == nil {
+		return nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.now().Before(b.openUntil) {
+		return fmt.Errorf("%w after %d consecutive server errors, retrying after %s",
+			ErrCircuitOpen, b.failures, b.openUntil.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// record counts a response with statusCode, opening the circuit breaker once the consecutive
+// 5xx responses reach the threshold and closing it on any other response.
+func (b *circuitBreaker) record(statusCode int) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	wasOpen := b.open
+	if statusCode >= http.StatusInternalServerError {
+		b.failures++
+		// Half-open after the cool-down, a single server error opens it again
+		if b.failures >= b.cfg.Threshold {
+			b.openUntil = b.now().Add(b.cfg.CoolDown)
+			b.open = true
+		}
+	} else {
+		b.failures = 0
+		b.openUntil = time.Time{}
+		b.open = false
+	}
+	changed := wasOpen != b.open
+	open := b.open
+	b.mu.Unlock()
+
+	if changed && b.cfg.OnChange != nil {
+		b.cfg.OnChange(open)
+	}
+}
+
+// until returns the time until which requests are refused, zero if they are let through.
+func (b *circuitBreaker) until() time.Time {
+	if b == nil {
+		return time.Time{}
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.now().Before(b.openUntil) {
+		return time.Time{}
+	}
+	return b.openUntil
+}
+
+// CircuitOpenUntil returns the time until which the circuit breaker refuses requests, zero if
+// it is closed, half-open after its cool-down, or disabled.
+func (r *Client) CircuitOpenUntil() time.Time {
+	return r.breaker.until()
+}
@@ -0,0 +1,113 @@
+package mimir
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/prometheus/model/rulefmt"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	var status, requests atomic.Int32
+	status.Store(http.StatusServiceUnavailable)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(int(status.Load()))
+	}))
+	t.Cleanup(server.Close)
+
+	var changes []bool
+	client, err := New(context.Background(), Config{
+		Address: server.URL,
+		CircuitBreaker: CircuitBreakerConfig{
+			Threshold: 3,
+			CoolDown:  time.Minute,
+			OnChange:  func(open bool) { changes = append(changes, open) },
+		},
+	})
+	if err != nil {
+		t.Fatalf("creating client: %v", err)
+	}
+	now := time.Now()
+	client.breaker.now = func() time.Time { return now }
+	ctx := context.Background()
+	push := func() error {
+		return client.CreateRuleGroup(ctx, "ns", rulefmt.RuleGroup{Name: "group"}, "tenant")
+	}
+
+	for range 3 {
+		if err := push(); err == nil || errors.Is(err, ErrCircuitOpen) {
+			t.Fatalf("expected the server error, got %v", err)
+		}
+	}
+	if err := push(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected the circuit breaker to refuse the request, got %v", err)
+	}
+	if got := requests.Load(); got != 3 {
+		t.Errorf("expected 3 requests to be sent, got %d", got)
+	}
+	if got := client.CircuitOpenUntil(); !got.Equal(now.Add(time.Minute)) {
+		t.Errorf("expected the circuit breaker to be open until %v, got %v", now.Add(time.Minute), got)
+	}
+
+	// Half-open after the cool-down, a single server error opens it again
+	now = now.Add(time.Minute)
+	if err := push(); err == nil || errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected the server error, got %v", err)
+	}
+	if err := push(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected the circuit breaker to open again, got %v", err)
+	}
+
+	// The health check is sent while open and closes the circuit breaker once Mimir recovered
+	status.Store(http.StatusOK)
+	if err := client.HealthCheck(ctx); err != nil {
+		t.Fatalf("HealthCheck: %v", err)
+	}
+	if err := push(); err != nil {
+		t.Fatalf("expected the circuit breaker to be closed, got %v", err)
+	}
+	if !client.CircuitOpenUntil().IsZero() {
+		t.Error("expected the circuit breaker to be closed")
+	}
+	if len(changes) != 2 || !changes[0] || changes[1] {
+		t.Errorf("expected the circuit breaker to open and close once, got %v", changes)
+	}
+}
+
+func TestCircuitBreakerIgnoresClientErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := New(context.Background(), Config{
+		Address:        server.URL,
+		CircuitBreaker: CircuitBreakerConfig{Threshold: 1},
+	})
+	if err != nil {
+		t.Fatalf("creating client: %v", err)
+	}
+	for range 3 {
+		err := client.CreateRuleGroup(context.Background(), "ns", rulefmt.RuleGroup{Name: "group"}, "tenant")
+		if err == nil || errors.Is(err, ErrCircuitOpen) {
+			t.Fatalf("expected the client error, got %v", err)
+		}
+	}
+}
+
+func TestCircuitBreakerDisabled(t *testing.T) {
+	if newCircuitBreaker(CircuitBreakerConfig{}) != nil {
+		t.Error("expected no circuit breaker without threshold")
+	}
+	var breaker *circuitBreaker
+	breaker.record(http.StatusInternalServerError)
+	if err := breaker.allow(); err != nil {
+		t.Errorf("expected a disabled circuit breaker to allow requests, got %v", err)
+	}
+}
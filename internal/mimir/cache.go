@@ -0,0 +1,101 @@
+package mimir
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/syndlex/openawareness-controller/internal/logging"
+)
+
+// responseKey identifies a cached GET response.
+type responseKey struct {
+	path     string
+	tenantID string
+}
+
+// cachedResponse is the last response of a GET request. The body is only kept if the
+// server sent an ETag, as it is only served again after the server answered
+// If-None-Match with 304 Not Modified.
+type cachedResponse struct {
+	etag string
+	hash [sha256.Size]byte
+	body []byte
+}
+
+// responseCache caches the last GET response per path and tenant, so unchanged
+// configurations are not downloaded again on every resync.
+type responseCache struct {
+	mu        sync.Mutex
+	responses map[responseKey]cachedResponse
+}
+
+func newResponseCache() *responseCache {
+	return &responseCache{responses: map[responseKey]cachedResponse{}}
+}
+
+func (c *responseCache) get(key responseKey) (cachedResponse, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	response, ok := c.responses[key]
+	return response, ok
+}
+
+func (c *responseCache) set(key responseKey, response cachedResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.responses[key] = response
+}
+
+func (c *responseCache) delete(key responseKey) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.responses, key)
+}
+
+// getCached performs a GET request of path for the tenant and returns the response body.
+// If the previous response carried an ETag it is sent as If-None-Match and the cached
+// body is returned when the server answers 304 Not Modified. Servers without ETag
+// support are detected as unchanged by comparing the hash of the body instead.
+func (r *Client) getCached(ctx context.Context, path string, tenantID string) ([]byte, error) {
+	key := responseKey{path: path, tenantID: tenantID}
+	cached, found := r.responses.get(key)
+
+	header := http.Header{}
+	if found && cached.etag != "" {
+		header.Set("If-None-Match", cached.etag)
+	}
+
+	res, err := r.doRequestWithHeader(ctx, path, "GET", nil, -1, tenantID, header)
+	if err != nil {
+		if errors.Is(err, ErrResourceNotFound) {
+			r.responses.delete(key)
+		}
+		return nil, err
+	}
+	defer func() { _ = res.Body.Close() }()
+
+	if res.StatusCode == http.StatusNotModified && found && cached.etag != "" {
+		r.log.V(1).Info("response not modified", "path", path, logging.KeyTenant, tenantID)
+		return cached.body, nil
+	}
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	response := cachedResponse{etag: res.Header.Get("ETag"), hash: sha256.Sum256(body)}
+	if found && cached.hash == response.hash {
+		r.log.V(1).Info("response unchanged", "path", path, logging.KeyTenant, tenantID)
+	}
+	if response.etag != "" {
+		response.body = body
+	}
+	r.responses.set(key, response)
+
+	return body, nil
+}
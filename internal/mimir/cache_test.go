@@ -0,0 +1,63 @@
+package mimir
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetCachedConditionalRequests(t *testing.T) {
+	config := "alertmanager_config: |\n  route:\n    receiver: team\n"
+	etag := `"v1"`
+	var ifNoneMatch []string
+	var downloads int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ifNoneMatch = append(ifNoneMatch, r.Header.Get("If-None-Match"))
+		if etag != "" && r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		downloads++
+		if etag != "" {
+			w.Header().Set("ETag", etag)
+		}
+		_, _ = w.Write([]byte(config))
+	}))
+	t.Cleanup(server.Close)
+	client := newTestClient(t, server.URL)
+	ctx := context.Background()
+
+	for range 2 {
+		cfg, _, err := client.GetAlertmanagerConfig(ctx, "tenant-a")
+		if err != nil {
+			t.Fatalf("GetAlertmanagerConfig: %v", err)
+		}
+		if cfg != "route:\n  receiver: team\n" {
+			t.Fatalf("unexpected config %q", cfg)
+		}
+	}
+	if downloads != 1 || ifNoneMatch[0] != "" || ifNoneMatch[1] != `"v1"` {
+		t.Errorf("expected a single download and a revalidation, got %d downloads and If-None-Match %q",
+			downloads, ifNoneMatch)
+	}
+
+	// Other tenants are cached separately
+	if _, _, err := client.GetAlertmanagerConfig(ctx, "tenant-b"); err != nil {
+		t.Fatalf("GetAlertmanagerConfig: %v", err)
+	}
+	if ifNoneMatch[2] != "" {
+		t.Errorf("expected no If-None-Match for another tenant, got %q", ifNoneMatch[2])
+	}
+
+	// Servers without ETag support are always downloaded
+	etag, downloads = "", 0
+	for range 2 {
+		if _, _, err := client.GetAlertmanagerConfig(ctx, "tenant-c"); err != nil {
+			t.Fatalf("GetAlertmanagerConfig: %v", err)
+		}
+	}
+	if downloads != 2 {
+		t.Errorf("expected 2 downloads without ETag, got %d", downloads)
+	}
+}
@@ -3,11 +3,16 @@ package mimir
 
 import (
 	"context"
+	"crypto/x509"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"net/url"
+	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
@@ -15,19 +20,32 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
 	"github.com/grafana/dskit/crypto/tls"
+	"github.com/grafana/dskit/tenant"
 	"github.com/grafana/dskit/user"
+
+	"github.com/syndlex/openawareness-controller/internal/logging"
 )
 
 const (
 	rulerAPIPath  = "/prometheus/config/v1/rules"
 	legacyAPIPath = "/api/v1/rules"
+
+	// InstanceHeader names the operator installation sending a request, so gateways can tell
+	// installations pushing to the same Mimir apart, see Config.ExtraHeaders
+	InstanceHeader = "X-Openawareness-Instance"
 )
 
+// pathPrefixPattern matches absolute paths of unreserved characters, with optional trailing slash
+var pathPrefixPattern = regexp.MustCompile(`^(/[A-Za-z0-9._~-]+)+/?$`)
+
 var (
 	// ErrResourceNotFound indicates the requested resource was not found (404)
 	ErrResourceNotFound = errors.New("requested resource not found")
-	errConflict         = errors.New("conflict with current state of target resource")
-	errTooManyRequests  = errors.New("too many requests")
+	// ErrTokenExchange indicates the service account token could not be exchanged for a
+	// gateway token, see TokenExchangeConfig
+	ErrTokenExchange   = errors.New("token exchange failed")
+	errConflict        = errors.New("conflict with current state of target resource")
+	errTooManyRequests = errors.New("too many requests")
 )
 
 // UserAgent returns build information in format suitable to be used in HTTP User-Agent header.
@@ -45,6 +63,30 @@ type Config struct {
 	MimirHTTPPrefix string            `yaml:"mimir_http_prefix"`
 	AuthToken       string            `yaml:"auth_token"`
 	ExtraHeaders    map[string]string `yaml:"extra_headers"`
+	// TokenExchange authenticates with a token obtained by exchanging the
+	// service account token of the controller, disabled if nil
+	TokenExchange *TokenExchangeConfig `yaml:"-"`
+	// OnCertificateReload is called after a rotated TLS client certificate was loaded
+	OnCertificateReload func() `yaml:"-"`
+	// Transport tunes the connection pooling of the client
+	Transport TransportConfig `yaml:"-"`
+	// GzipRequests compresses request bodies with gzip
+	GzipRequests bool `yaml:"gzip_requests"`
+	// CABundle holds PEM CA certificates used to verify the server certificate together with
+	// TLS.CAPath instead of the system roots, the system roots are used if empty
+	CABundle []byte `yaml:"-"`
+	// Tape records the requests of the client for troubleshooting, disabled if nil
+	Tape *Tape `yaml:"-"`
+	// PathPrefix is prepended to the paths of all API requests, e.g. "/mimir" for gateways
+	// exposing the Mimir API below a path
+	PathPrefix string `yaml:"path_prefix"`
+	// CircuitBreaker refuses requests for a cool-down after consecutive server errors
+	CircuitBreaker CircuitBreakerConfig `yaml:"-"`
+	// Middlewares prepare every request in order right before it is sent, e.g. HMACSigner
+	Middlewares []RequestMiddleware `yaml:"-"`
+	// ProxyURL is the SOCKS5 proxy all connections of the client are dialed through, e.g. a
+	// bastion or an SSH tunnel, the proxy of the environment is used if empty
+	ProxyURL string `yaml:"proxy_url"`
 }
 
 // Client is a client to the Mimir API.
@@ -56,8 +98,24 @@ type Client struct {
 	Client       http.Client
 	apiPath      string
 	authToken    string
+	tokens       *tokenExchanger
 	extraHeaders map[string]string
-	log          logr.Logger
+	gzipRequests bool
+	// pathPrefix is prepended to the paths of all requests, without trailing slash
+	pathPrefix string
+	log        logr.Logger
+	// stopCertificateWatch stops reloading the TLS client certificate, nil if none is used
+	stopCertificateWatch context.CancelFunc
+	// responses caches the last GET responses for conditional requests
+	responses *responseCache
+	// breaker refuses requests after consecutive server errors, nil if disabled
+	breaker *circuitBreaker
+	// limiter shares the requests in flight fairly across tenants, nil if unlimited
+	limiter *fairLimiter
+	// middlewares prepare every request before it is sent
+	middlewares []RequestMiddleware
+	// proxyURL is the redacted URL of the proxy connections are dialed through, empty if none
+	proxyURL string
 }
 
 // New returns a new Client.
@@ -67,14 +125,18 @@ func New(ctx context.Context, cfg Config) (*Client, error) {
 	if err != nil {
 		return nil, err
 	}
+	if err := ValidatePathPrefix(cfg.PathPrefix); err != nil {
+		return nil, err
+	}
 
 	logger.Info("New Mimir client created",
 		"address", cfg.Address)
 
-	client := http.Client{}
-
-	// Setup TLS client
-	tlsConfig, err := cfg.TLS.GetTLSConfig()
+	// Setup TLS client. The client certificate is loaded by watchClientCertificate
+	// so that rotated certificates are picked up.
+	tlsClientConfig := cfg.TLS
+	tlsClientConfig.CertPath, tlsClientConfig.KeyPath = "", ""
+	tlsConfig, err := tlsClientConfig.GetTLSConfig()
 	if err != nil {
 		logger.Error(err, "Mimir client initialization unsuccessful",
 			"tls-ca", cfg.TLS.CAPath,
@@ -83,14 +145,27 @@ func New(ctx context.Context, cfg Config) (*Client, error) {
 		)
 		return nil, fmt.Errorf("mimir client initialization unsuccessful")
 	}
+	if len(cfg.CABundle) > 0 {
+		if tlsConfig.RootCAs == nil {
+			tlsConfig.RootCAs = x509.NewCertPool()
+		}
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(cfg.CABundle) {
+			return nil, errors.New("no PEM certificate found in the CA bundle")
+		}
+	}
 
-	if tlsConfig != nil {
-		transport := &http.Transport{
-			Proxy:           http.ProxyFromEnvironment,
-			TLSClientConfig: tlsConfig,
+	// Connections are pooled across requests and tenants of the client
+	transport := newTransport(cfg.Transport, tlsConfig)
+	var proxyURL string
+	if cfg.ProxyURL != "" {
+		proxy, err := ParseProxyURL(cfg.ProxyURL)
+		if err != nil {
+			return nil, err
 		}
-		client = http.Client{Transport: transport}
+		transport.Proxy = http.ProxyURL(proxy)
+		proxyURL = proxy.Redacted()
 	}
+	client := http.Client{Transport: cfg.Tape.RoundTripper(transport)}
 
 	path := rulerAPIPath
 	if cfg.UseLegacyRoutes {
@@ -100,20 +175,95 @@ func New(ctx context.Context, cfg Config) (*Client, error) {
 		}
 	}
 
+	var tokens *tokenExchanger
+	if cfg.TokenExchange != nil {
+		if cfg.User != "" || cfg.Key != "" || cfg.AuthToken != "" {
+			return nil, errors.New("token exchange cannot be combined with basic auth or an auth token")
+		}
+		if tokens, err = newTokenExchanger(*cfg.TokenExchange, &client); err != nil {
+			return nil, err
+		}
+	}
+
+	var stopCertificateWatch context.CancelFunc
+	if cfg.TLS.CertPath != "" || cfg.TLS.KeyPath != "" {
+		if tlsConfig == nil {
+			return nil, errors.New("client certificate requires a TLS configuration")
+		}
+		stopCertificateWatch, err = watchClientCertificate(logger, transport,
+			cfg.TLS.CertPath, cfg.TLS.KeyPath, cfg.OnCertificateReload)
+		if err != nil {
+			return nil, fmt.Errorf("loading TLS client certificate: %w", err)
+		}
+	}
+
 	return &Client{
-		user:         cfg.User,
-		key:          cfg.Key,
-		endpoint:     endpoint,
-		Client:       client,
-		apiPath:      path,
-		authToken:    cfg.AuthToken,
-		extraHeaders: cfg.ExtraHeaders,
-		log:          logger,
+		user:                 cfg.User,
+		key:                  cfg.Key,
+		endpoint:             endpoint,
+		Client:               client,
+		apiPath:              path,
+		authToken:            cfg.AuthToken,
+		tokens:               tokens,
+		extraHeaders:         cfg.ExtraHeaders,
+		gzipRequests:         cfg.GzipRequests,
+		pathPrefix:           strings.TrimSuffix(cfg.PathPrefix, "/"),
+		log:                  logger,
+		stopCertificateWatch: stopCertificateWatch,
+		responses:            newResponseCache(),
+		breaker:              newCircuitBreaker(cfg.CircuitBreaker),
+		limiter:              newFairLimiter(cfg.Transport.MaxConcurrentRequests),
+		middlewares:          cfg.Middlewares,
+		proxyURL:             proxyURL,
 	}, nil
 }
 
+// Close releases the resources of the client, stopping the reload of its TLS client certificate.
+func (r *Client) Close() {
+	if r.stopCertificateWatch != nil {
+		r.stopCertificateWatch()
+	}
+}
+
+// ParseProxyURL parses the URL of a SOCKS5 proxy, socks5:// to resolve host names locally or
+// socks5h:// to resolve them through the proxy, e.g. names only known behind a bastion.
+// Returns an error for other schemes or URLs without host.
+func ParseProxyURL(proxyURL string) (*url.URL, error) {
+	parsed, err := url.Parse(proxyURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy URL: %w", err)
+	}
+	if parsed.Scheme != "socks5" && parsed.Scheme != "socks5h" {
+		return nil, fmt.Errorf("unsupported proxy scheme %q, use socks5 or socks5h", parsed.Scheme)
+	}
+	if parsed.Host == "" {
+		return nil, fmt.Errorf("proxy URL %s has no host", parsed.Redacted())
+	}
+	return parsed, nil
+}
+
+// ValidatePathPrefix checks that prefix is empty or an absolute URL path without query, fragment,
+// dot segments or characters requiring escaping, e.g. "/mimir".
+func ValidatePathPrefix(prefix string) error {
+	if prefix == "" {
+		return nil
+	}
+	if !pathPrefixPattern.MatchString(prefix) {
+		return fmt.Errorf("invalid URL path prefix %q, must be an absolute path like /mimir", prefix)
+	}
+	for segment := range strings.SplitSeq(strings.Trim(prefix, "/"), "/") {
+		if segment == "." || segment == ".." {
+			return fmt.Errorf("invalid URL path prefix %q, must not contain dot segments", prefix)
+		}
+	}
+	return nil
+}
+
 // HealthCheck performs a lightweight health check by attempting to list rules
 // for an empty namespace. This verifies connectivity, authentication, and basic API access.
+// The rules are listed below the path prefix, so a wrong prefix fails the check, and through
+// the proxy, so a proxy that cannot be reached or cannot reach Mimir fails the check.
+// The check is sent while the circuit breaker is open, so a recovered Mimir closes it.
 func (r *Client) HealthCheck(ctx context.Context) error {
 	r.log.V(1).Info("Performing health check")
 
@@ -121,9 +271,16 @@ func (r *Client) HealthCheck(ctx context.Context) error {
 	// List rules for a system namespace that should always be accessible
 	req := r.apiPath
 
-	res, err := r.doRequest(ctx, req, "GET", nil, -1, "")
+	res, err := r.sendRequest(ctx, req, "GET", nil, -1, "", nil)
 	if err != nil {
 		r.log.Error(err, "Health check failed")
+		if r.pathPrefix != "" && errors.Is(err, ErrResourceNotFound) {
+			return fmt.Errorf("%w, check that the Mimir API is served below path prefix %s", err, r.pathPrefix)
+		}
+		var opErr *net.OpError
+		if r.proxyURL != "" && errors.As(err, &opErr) && opErr.Op == "proxyconnect" {
+			return fmt.Errorf("%w, check that the proxy %s is reachable and can reach Mimir", err, r.proxyURL)
+		}
 		return err
 	}
 	defer func() { _ = res.Body.Close() }()
@@ -133,10 +290,17 @@ func (r *Client) HealthCheck(ctx context.Context) error {
 }
 
 // Query executes a PromQL query against the Mimir cluster.
-func (r *Client) Query(ctx context.Context, query string, tenantID string) (*http.Response, error) {
+// Multiple tenant IDs are sent as a single federated X-Scope-OrgID header ("a|b"),
+// which requires tenant federation to be enabled in Mimir.
+func (r *Client) Query(ctx context.Context, query string, tenantIDs []string) (*http.Response, error) {
+	orgID, err := FederatedOrgID(tenantIDs)
+	if err != nil {
+		return nil, err
+	}
+
 	req := fmt.Sprintf("/prometheus/api/v1/query?query=%s&time=%d", url.QueryEscape(query), time.Now().Unix())
 
-	res, err := r.doRequest(ctx, req, "GET", nil, -1, tenantID)
+	res, err := r.doRequest(ctx, req, "GET", nil, -1, orgID)
 	if err != nil {
 		return nil, err
 	}
@@ -144,6 +308,130 @@ func (r *Client) Query(ctx context.Context, query string, tenantID string) (*htt
 	return res, nil
 }
 
+type queryResponse struct {
+	Status string `json:"status"`
+	Data   struct {
+		ResultType string          `json:"resultType"`
+		Result     json.RawMessage `json:"result"`
+	} `json:"data"`
+}
+
+// QueryHasSeries executes a PromQL instant query against the tenants like Query, asking Mimir for
+// at most one series (limit=1), and reports whether the result holds any series. Scalar and
+// string results always count as a series.
+// Returns an error if the query fails to execute, e.g. because it is invalid or exceeds a limit.
+func (r *Client) QueryHasSeries(ctx context.Context, query string, tenantIDs []string) (bool, error) {
+	orgID, err := FederatedOrgID(tenantIDs)
+	if err != nil {
+		return false, err
+	}
+
+	req := fmt.Sprintf("/prometheus/api/v1/query?query=%s&time=%d&limit=1", url.QueryEscape(query), time.Now().Unix())
+	res, err := r.doRequest(ctx, req, "GET", nil, -1, orgID)
+	if err != nil {
+		return false, err
+	}
+
+	defer func() { _ = res.Body.Close() }()
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return false, err
+	}
+
+	var result queryResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return false, fmt.Errorf("unable to unmarshal query response, %w", err)
+	}
+	if result.Status != "success" {
+		return false, fmt.Errorf("query returned status %q", result.Status)
+	}
+	switch result.Data.ResultType {
+	case "vector", "matrix":
+		var series []json.RawMessage
+		if err := json.Unmarshal(result.Data.Result, &series); err != nil {
+			return false, fmt.Errorf("unable to unmarshal query result, %w", err)
+		}
+		return len(series) > 0, nil
+	default:
+		return true, nil
+	}
+}
+
+// CountSeries executes count(query) as a PromQL instant query against the tenants like Query and
+// returns the number of series the query selects, zero if it selects none.
+// Returns an error if the query fails to execute, e.g. because it is invalid or exceeds a limit.
+func (r *Client) CountSeries(ctx context.Context, query string, tenantIDs []string) (int64, error) {
+	orgID, err := FederatedOrgID(tenantIDs)
+	if err != nil {
+		return 0, err
+	}
+
+	req := fmt.Sprintf("/prometheus/api/v1/query?query=%s&time=%d",
+		url.QueryEscape("count("+query+")"), time.Now().Unix())
+	res, err := r.doRequest(ctx, req, "GET", nil, -1, orgID)
+	if err != nil {
+		return 0, err
+	}
+
+	defer func() { _ = res.Body.Close() }()
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return 0, err
+	}
+	return ParseSeriesCount(body)
+}
+
+// ParseSeriesCount reads the result of a count() instant query from the body of a Prometheus
+// query API response: the value of its single sample, or zero for an empty vector.
+// Returns an error if the query failed or the result is not a vector.
+func ParseSeriesCount(body []byte) (int64, error) {
+	var result queryResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return 0, fmt.Errorf("unable to unmarshal query response, %w", err)
+	}
+	if result.Status != "success" {
+		return 0, fmt.Errorf("query returned status %q", result.Status)
+	}
+	if result.Data.ResultType != "vector" {
+		return 0, fmt.Errorf("expected a vector result, got %q", result.Data.ResultType)
+	}
+	var samples []struct {
+		Value [2]json.RawMessage `json:"value"`
+	}
+	if err := json.Unmarshal(result.Data.Result, &samples); err != nil {
+		return 0, fmt.Errorf("unable to unmarshal query result, %w", err)
+	}
+	if len(samples) == 0 {
+		return 0, nil
+	}
+	var value string
+	if err := json.Unmarshal(samples[0].Value[1], &value); err != nil {
+		return 0, fmt.Errorf("unable to unmarshal sample value, %w", err)
+	}
+	count, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid sample value %q: %w", value, err)
+	}
+	return int64(count), nil
+}
+
+// FederatedOrgID validates the given tenant IDs and joins them into an X-Scope-OrgID
+// header value. Tenant IDs are sorted and de-duplicated as Mimir expects for federated reads.
+// Returns an error if no tenant ID is given or any tenant ID is invalid.
+func FederatedOrgID(tenantIDs []string) (string, error) {
+	if len(tenantIDs) == 0 {
+		return "", errors.New("at least one tenant ID is required")
+	}
+	for _, tenantID := range tenantIDs {
+		if err := tenant.ValidTenantID(tenantID); err != nil {
+			return "", fmt.Errorf("invalid tenant ID %q: %w", tenantID, err)
+		}
+	}
+	// NormalizeTenantIDs sorts in place, so work on a copy of the caller's slice
+	normalized := tenant.NormalizeTenantIDs(append([]string(nil), tenantIDs...))
+	return tenant.JoinTenantIDs(normalized), nil
+}
+
 func (r *Client) doRequest(
 	ctx context.Context,
 	path, method string,
@@ -151,10 +439,52 @@ func (r *Client) doRequest(
 	contentLength int64,
 	tenantID string,
 ) (*http.Response, error) {
-	req, err := buildRequest(ctx, path, method, *r.endpoint, payload, contentLength)
+	return r.doRequestWithHeader(ctx, path, method, payload, contentLength, tenantID, nil)
+}
+
+// doRequestWithHeader sends a request with additional headers. A 304 Not Modified
+// response to a conditional request is returned without error.
+// Returns an error wrapping ErrCircuitOpen without sending the request while the circuit
+// breaker is open.
+func (r *Client) doRequestWithHeader(
+	ctx context.Context,
+	path, method string,
+	payload io.Reader,
+	contentLength int64,
+	tenantID string,
+	header http.Header,
+) (*http.Response, error) {
+	if err := r.breaker.allow(); err != nil {
+		return nil, fmt.Errorf("%w, %s request to %s refused", err, method, path)
+	}
+	return r.sendRequest(ctx, path, method, payload, contentLength, tenantID, header)
+}
+
+// sendRequest sends a request regardless of the circuit breaker, which records its response.
+func (r *Client) sendRequest(
+	ctx context.Context,
+	path, method string,
+	payload io.Reader,
+	contentLength int64,
+	tenantID string,
+	header http.Header,
+) (*http.Response, error) {
+	compressed := r.gzipRequests && payload != nil && contentLength >= gzipMinSize
+	if compressed {
+		body, err := gzipPayload(payload)
+		if err != nil {
+			return nil, err
+		}
+		payload, contentLength = body, int64(body.Len())
+	}
+
+	req, err := buildRequest(ctx, r.pathPrefix+path, method, *r.endpoint, payload, contentLength)
 	if err != nil {
 		return nil, err
 	}
+	if compressed {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
 
 	switch {
 	case (r.user != "" || r.key != "") && r.authToken != "":
@@ -173,11 +503,27 @@ func (r *Client) doRequest(
 
 	case r.authToken != "":
 		req.Header.Add("Authorization", "Bearer "+r.authToken)
+
+	case r.tokens != nil:
+		token, err := r.tokens.Token(ctx)
+		if err != nil {
+			r.log.Error(err, "error during setting up request to mimir api",
+				"url", req.URL.String(),
+				"method", req.Method,
+			)
+			return nil, err
+		}
+		req.Header.Add("Authorization", "Bearer "+token)
 	}
 
 	for k, v := range r.extraHeaders {
 		req.Header.Add(k, v)
 	}
+	for k, values := range header {
+		for _, v := range values {
+			req.Header.Add(k, v)
+		}
+	}
 
 	// Use provided tenant ID if given, otherwise fall back to client's default tenant ID
 	if tenantID != "" {
@@ -186,11 +532,28 @@ func (r *Client) doRequest(
 		req.Header.Add(user.OrgIDHeaderName, r.id)
 	}
 
-	r.log.Info("sending request to Grafana Mimir API",
+	for _, middleware := range r.middlewares {
+		if err := middleware(req); err != nil {
+			r.log.Error(err, "error during setting up request to mimir api",
+				"url", req.URL.String(),
+				"method", req.Method,
+			)
+			return nil, err
+		}
+	}
+
+	orgID := req.Header.Get(user.OrgIDHeaderName)
+	logging.SuccessInfo(r.log, req.Method+" "+req.URL.Path+" "+orgID, "sending request to Grafana Mimir API",
 		"url", req.URL.String(),
-		"method", req.Method)
+		"method", req.Method,
+		logging.KeyTenant, orgID)
 
+	// Requests wait for their turn among the tenants of the client
+	if err := r.limiter.acquire(ctx, orgID); err != nil {
+		return nil, err
+	}
 	resp, err := r.Client.Do(req)
+	r.limiter.release()
 	if err != nil {
 		r.log.Error(err, "error during request to Grafana Mimir API",
 			"url", req.URL.String(),
@@ -198,8 +561,13 @@ func (r *Client) doRequest(
 		)
 		return nil, err
 	}
+	r.breaker.record(resp.StatusCode)
+
+	if resp.StatusCode == http.StatusNotModified && req.Header.Get("If-None-Match") != "" {
+		return resp, nil
+	}
 
-	if err := r.checkResponse(resp); err != nil {
+	if err := r.checkResponse(resp, orgID); err != nil {
 		_ = resp.Body.Close()
 		return nil, fmt.Errorf("%w, %s request to %s failed", err, req.Method, req.URL.String())
 	}
@@ -207,55 +575,38 @@ func (r *Client) doRequest(
 	return resp, nil
 }
 
-// checkResponse checks an API response for errors.
-func (r *Client) checkResponse(resp *http.Response) error {
-	r.log.Info("checking response", "status", resp.Status)
-
+// checkResponse checks an API response for errors, returning an *APIError for responses
+// outside of 2xx. The body of error responses is read up to maxErrorBodySize and logged at V(2).
+func (r *Client) checkResponse(resp *http.Response, tenantID string) error {
+	method, path := "", ""
+	if resp.Request != nil {
+		method, path = resp.Request.Method, resp.Request.URL.Path
+	}
+	// Successful responses are sampled like the requests, failures are always logged
 	if 200 <= resp.StatusCode && resp.StatusCode <= 299 {
+		logging.SuccessInfo(r.log, method+" "+path+" "+tenantID, "checking response",
+			"status", resp.Status, logging.KeyTenant, tenantID)
 		return nil
 	}
+	r.log.Info("checking response", "status", resp.Status, logging.KeyTenant, tenantID)
 
-	bodyHead, err := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	bodyHead, err := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
 	if err != nil {
 		return fmt.Errorf("reading body: %w", err)
 	}
-	bodyStr := string(bodyHead)
-	const msg = "response"
-	if resp.StatusCode == http.StatusNotFound {
-		r.log.Info(msg,
-			"status", resp.Status,
-			"body", bodyStr,
-		)
-		return ErrResourceNotFound
-	}
-	if resp.StatusCode == http.StatusConflict {
-		r.log.Info(msg,
-			"status", resp.Status,
-			"body", bodyStr,
-		)
-		return errConflict
-	}
-	if resp.StatusCode == http.StatusTooManyRequests {
-		r.log.Info(msg,
-			"status", resp.Status,
-			"body", bodyStr,
-		)
-		return errTooManyRequests
-	}
-
-	r.log.Info(msg,
+	r.log.V(2).Info("response",
 		"status", resp.Status,
-		"body", bodyStr,
+		logging.KeyTenant, tenantID,
+		"path", path,
+		"body", string(bodyHead),
 	)
 
-	var errMsg string
-	if bodyStr == "" {
-		errMsg = fmt.Sprintf("server returned HTTP status: %s", resp.Status)
-	} else {
-		errMsg = fmt.Sprintf("server returned HTTP status: %s, body: %q", resp.Status, bodyStr)
+	return &APIError{
+		StatusCode: resp.StatusCode,
+		TenantID:   tenantID,
+		Path:       path,
+		Snippet:    snippet(string(bodyHead)),
 	}
-
-	return errors.New(errMsg)
 }
 
 func joinPath(baseURLPath, targetPath string) string {
@@ -0,0 +1,353 @@
+package mimir
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/grafana/dskit/user"
+	"github.com/prometheus/prometheus/model/rulefmt"
+)
+
+// recordingServer returns a test server that records the X-Scope-OrgID header of every request.
+func recordingServer(t *testing.T) (*httptest.Server, func() []string) {
+	t.Helper()
+	var mu sync.Mutex
+	var orgIDs []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		orgIDs = append(orgIDs, r.Header.Values(user.OrgIDHeaderName)...)
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+	return server, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), orgIDs...)
+	}
+}
+
+func newTestClient(t *testing.T, address string) *Client {
+	t.Helper()
+	client, err := New(context.Background(), Config{Address: address})
+	if err != nil {
+		t.Fatalf("creating client: %v", err)
+	}
+	return client
+}
+
+func TestTenantIsolation(t *testing.T) {
+	server, orgIDs := recordingServer(t)
+	client := newTestClient(t, server.URL)
+	ctx := context.Background()
+
+	group := rulefmt.RuleGroup{Name: "group"}
+	if err := client.CreateRuleGroup(ctx, "ns", group, "tenant-a"); err != nil {
+		t.Fatalf("CreateRuleGroup: %v", err)
+	}
+	if err := client.DeleteRuleGroup(ctx, "ns", "group", "tenant-b"); err != nil {
+		t.Fatalf("DeleteRuleGroup: %v", err)
+	}
+
+	got := orgIDs()
+	if len(got) != 2 || got[0] != "tenant-a" || got[1] != "tenant-b" {
+		t.Errorf("expected exactly one OrgID per request [tenant-a tenant-b], got %v", got)
+	}
+}
+
+func TestCreateFederatedRuleGroup(t *testing.T) {
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	t.Cleanup(server.Close)
+	client := newTestClient(t, server.URL)
+	ctx := context.Background()
+
+	group := rulefmt.RuleGroup{Name: "global", Rules: []rulefmt.Rule{{Record: "job:up:sum", Expr: "sum by (job) (up)"}}}
+	if err := client.CreateFederatedRuleGroup(ctx, "ns", group, []string{"team-a", "team-b"}, "platform"); err != nil {
+		t.Fatalf("CreateFederatedRuleGroup: %v", err)
+	}
+	if err := client.CreateFederatedRuleGroup(ctx, "ns", group, nil, "platform"); err != nil {
+		t.Fatalf("CreateFederatedRuleGroup without source tenants: %v", err)
+	}
+
+	if len(bodies) != 2 {
+		t.Fatalf("expected 2 requests, got %d", len(bodies))
+	}
+	if !strings.Contains(bodies[0], "source_tenants:\n    - team-a\n    - team-b\n") ||
+		!strings.Contains(bodies[0], "record: job:up:sum") {
+		t.Errorf("expected the group with its source tenants, got:\n%s", bodies[0])
+	}
+	if strings.Contains(bodies[1], "source_tenants") {
+		t.Errorf("expected no source tenants without them, got:\n%s", bodies[1])
+	}
+}
+
+func TestQueryFederatedTenants(t *testing.T) {
+	server, orgIDs := recordingServer(t)
+	client := newTestClient(t, server.URL)
+
+	res, err := client.Query(context.Background(), "up", []string{"tenant-b", "tenant-a", "tenant-b"})
+	if err != nil {
+		t.Fatalf("Query: %v", err)
+	}
+	_ = res.Body.Close()
+
+	got := orgIDs()
+	if len(got) != 1 || got[0] != "tenant-a|tenant-b" {
+		t.Errorf("expected federated OrgID tenant-a|tenant-b, got %v", got)
+	}
+}
+
+func TestPathPrefix(t *testing.T) {
+	var mu sync.Mutex
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		paths = append(paths, r.URL.Path)
+		mu.Unlock()
+		if !strings.HasPrefix(r.URL.Path, "/mimir/") {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+	ctx := context.Background()
+
+	client, err := New(ctx, Config{Address: server.URL, PathPrefix: "/mimir/"})
+	if err != nil {
+		t.Fatalf("creating client: %v", err)
+	}
+	if err := client.HealthCheck(ctx); err != nil {
+		t.Fatalf("HealthCheck() with prefix: %v", err)
+	}
+	res, err := client.Query(ctx, "up", []string{"tenant-a"})
+	if err != nil {
+		t.Fatalf("Query() with prefix: %v", err)
+	}
+	_ = res.Body.Close()
+	if err := client.DeleteAlermanagerConfig(ctx, "tenant-a"); err != nil {
+		t.Fatalf("DeleteAlermanagerConfig() with prefix: %v", err)
+	}
+
+	mu.Lock()
+	got := append([]string(nil), paths...)
+	mu.Unlock()
+	want := []string{"/mimir/prometheus/config/v1/rules", "/mimir/prometheus/api/v1/query", "/mimir/api/v1/alerts"}
+	if !slices.Equal(got, want) {
+		t.Errorf("request paths = %v, want %v", got, want)
+	}
+
+	err = newTestClient(t, server.URL).HealthCheck(ctx)
+	if !errors.Is(err, ErrResourceNotFound) {
+		t.Errorf("expected the health check without prefix to fail with not found, got %v", err)
+	}
+	client, err = New(ctx, Config{Address: server.URL, PathPrefix: "/other"})
+	if err != nil {
+		t.Fatalf("creating client: %v", err)
+	}
+	if err := client.HealthCheck(ctx); err == nil || !strings.Contains(err.Error(), "path prefix /other") {
+		t.Errorf("expected the health check to name the wrong prefix, got %v", err)
+	}
+}
+
+func TestValidatePathPrefix(t *testing.T) {
+	for prefix, valid := range map[string]bool{
+		"":          true,
+		"/mimir":    true,
+		"/a/b.c/":   true,
+		"mimir":     false,
+		"/":         false,
+		"/a/../b":   false,
+		"/a?b=c":    false,
+		"/a b":      false,
+		"//mimir":   false,
+		"/mimir#ab": false,
+	} {
+		if err := ValidatePathPrefix(prefix); (err == nil) != valid {
+			t.Errorf("ValidatePathPrefix(%q) = %v, want valid %v", prefix, err, valid)
+		}
+	}
+	if _, err := New(context.Background(), Config{Address: "http://mimir", PathPrefix: "/a/.."}); err == nil {
+		t.Error("expected New() to refuse an invalid path prefix")
+	}
+}
+
+func TestQueryHasSeries(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("limit") != "1" {
+			t.Errorf("expected the query to be limited to one series, got %q", r.URL.RawQuery)
+		}
+		switch r.URL.Query().Get("query") {
+		case "up":
+			_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1,"1"]}]}}`))
+		case "missing":
+			_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[]}}`))
+		case "1":
+			_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"scalar","result":[1,"1"]}}`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"status":"error","errorType":"bad_data","error":"parse error"}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+	client := newTestClient(t, server.URL)
+
+	for query, want := range map[string]bool{"up": true, "missing": false, "1": true} {
+		got, err := client.QueryHasSeries(context.Background(), query, []string{"tenant-a"})
+		if err != nil {
+			t.Fatalf("QueryHasSeries(%q) error = %v", query, err)
+		}
+		if got != want {
+			t.Errorf("QueryHasSeries(%q) = %v, want %v", query, got, want)
+		}
+	}
+	if _, err := client.QueryHasSeries(context.Background(), "up{", []string{"tenant-a"}); err == nil {
+		t.Errorf("expected an error for a failing query")
+	}
+}
+
+func TestCountSeries(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("query") {
+		case "count(up)":
+			_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1,"3"]}]}}`))
+		case "count(missing)":
+			_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[]}}`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"status":"error","errorType":"bad_data","error":"parse error"}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+	client := newTestClient(t, server.URL)
+
+	for query, want := range map[string]int64{"up": 3, "missing": 0} {
+		got, err := client.CountSeries(context.Background(), query, []string{"tenant-a"})
+		if err != nil {
+			t.Fatalf("CountSeries(%q) error = %v", query, err)
+		}
+		if got != want {
+			t.Errorf("CountSeries(%q) = %d, want %d", query, got, want)
+		}
+	}
+	if _, err := client.CountSeries(context.Background(), "up{", []string{"tenant-a"}); err == nil {
+		t.Errorf("expected an error for a failing query")
+	}
+	if _, err := ParseSeriesCount([]byte(`{"status":"success","data":{"resultType":"scalar","result":[1,"1"]}}`)); err == nil {
+		t.Errorf("expected an error for a scalar result")
+	}
+}
+
+func TestFederatedOrgID(t *testing.T) {
+	tests := []struct {
+		name        string
+		tenantIDs   []string
+		expected    string
+		expectError bool
+	}{
+		{name: "single tenant", tenantIDs: []string{"team-a"}, expected: "team-a"},
+		{name: "sorted and de-duplicated", tenantIDs: []string{"b", "a", "b"}, expected: "a|b"},
+		{name: "no tenants", tenantIDs: nil, expectError: true},
+		{name: "tenant containing separator", tenantIDs: []string{"a|b"}, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FederatedOrgID(tt.tenantIDs)
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("expected an error, got %q", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("FederatedOrgID() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestActiveRuleGroups(t *testing.T) {
+	var orgID, path string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		orgID = r.Header.Get(user.OrgIDHeaderName)
+		path = r.URL.Path
+		_, _ = w.Write([]byte(`{"status":"success","data":{"groups":[{"name":"group","file":"ns",` +
+			`"lastEvaluation":"2026-01-02T03:04:05Z","rules":[{"name":"Alert","health":"ok"}]}]}}`))
+	}))
+	t.Cleanup(server.Close)
+	client := newTestClient(t, server.URL)
+
+	groups, err := client.ActiveRuleGroups(context.Background(), "tenant-a")
+	if err != nil {
+		t.Fatalf("ActiveRuleGroups: %v", err)
+	}
+	if orgID != "tenant-a" || path != rulerStateAPIPath {
+		t.Errorf("unexpected request: orgID=%q path=%q", orgID, path)
+	}
+	if len(groups) != 1 || groups[0].File != "ns" || groups[0].Rules[0].Health != "ok" {
+		t.Fatalf("unexpected groups: %+v", groups)
+	}
+	if want := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC); !groups[0].LastEvaluation.Equal(want) {
+		t.Errorf("LastEvaluation = %v, want %v", groups[0].LastEvaluation, want)
+	}
+}
+
+func TestWalkRules(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.EscapedPath())
+		switch r.URL.Path {
+		case rulerAPIPath:
+			_, _ = w.Write([]byte("team/b:\n- name: b\n  rules: []\nteam-a:\n- name: a\n  rules: []\n"))
+		case rulerAPIPath + "/team-a":
+			_, _ = w.Write([]byte("team-a:\n- name: a\n  rules:\n  - alert: A\n    expr: up == 0\n"))
+		default:
+			// team/b was deleted since the namespaces were listed
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	client := newTestClient(t, server.URL)
+	ctx := context.Background()
+
+	namespaces, err := client.ListRuleNamespaces(ctx, "tenant-a")
+	if err != nil {
+		t.Fatalf("ListRuleNamespaces: %v", err)
+	}
+	if len(namespaces) != 2 || namespaces[0] != "team-a" || namespaces[1] != "team/b" {
+		t.Fatalf("unexpected namespaces: %v", namespaces)
+	}
+
+	paths = nil
+	walked := map[string][]rulefmt.RuleGroup{}
+	err = client.WalkRules(ctx, "tenant-a", func(namespace string, groups []rulefmt.RuleGroup) error {
+		walked[namespace] = groups
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("WalkRules: %v", err)
+	}
+	if len(walked) != 1 || len(walked["team-a"]) != 1 || walked["team-a"][0].Rules[0].Alert != "A" {
+		t.Errorf("unexpected walked rules: %+v", walked)
+	}
+	if len(paths) != 3 || paths[2] != rulerAPIPath+"/team%2Fb" {
+		t.Errorf("expected one request per namespace with escaped names, got %v", paths)
+	}
+}
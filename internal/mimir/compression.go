@@ -0,0 +1,23 @@
+package mimir
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+)
+
+// gzipMinSize is the smallest request body compressed, smaller bodies do not gain from it
+const gzipMinSize = 1024
+
+// gzipPayload returns payload gzip compressed.
+func gzipPayload(payload io.Reader) (*bytes.Buffer, error) {
+	var body bytes.Buffer
+	writer := gzip.NewWriter(&body)
+	if _, err := io.Copy(writer, payload); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return &body, nil
+}
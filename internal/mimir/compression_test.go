@@ -0,0 +1,63 @@
+package mimir
+
+import (
+	"compress/gzip"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestGzipRequests(t *testing.T) {
+	large := strings.Repeat("receiver: team\n", 200)
+
+	tests := []struct {
+		name         string
+		gzipRequests bool
+		config       string
+		wantEncoding string
+	}{
+		{name: "uncompressed by default", config: large},
+		{name: "large bodies are compressed", gzipRequests: true, config: large, wantEncoding: "gzip"},
+		{name: "small bodies are not compressed", gzipRequests: true, config: "receiver: team\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var encoding, body string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				encoding = r.Header.Get("Content-Encoding")
+				reader := io.Reader(r.Body)
+				if encoding == "gzip" {
+					gz, err := gzip.NewReader(r.Body)
+					if err != nil {
+						t.Errorf("reading gzip body: %v", err)
+						return
+					}
+					reader = gz
+				}
+				data, _ := io.ReadAll(reader)
+				body = string(data)
+				w.WriteHeader(http.StatusCreated)
+			}))
+			t.Cleanup(server.Close)
+
+			client, err := New(context.Background(), Config{Address: server.URL, GzipRequests: tt.gzipRequests})
+			if err != nil {
+				t.Fatalf("creating client: %v", err)
+			}
+			if err := client.CreateAlertmanagerConfig(context.Background(), tt.config, nil, "tenant"); err != nil {
+				t.Fatalf("CreateAlertmanagerConfig: %v", err)
+			}
+
+			if encoding != tt.wantEncoding {
+				t.Errorf("Content-Encoding = %q, want %q", encoding, tt.wantEncoding)
+			}
+			if !strings.Contains(body, "receiver: team") {
+				t.Errorf("expected the decoded body to contain the configuration, got %q", body)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package mimir
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// maxErrorBodySize limits the part of an error response body that is read and logged
+	maxErrorBodySize = 16 * 1024
+	// maxSnippetSize limits the part of an error response body kept in an APIError
+	maxSnippetSize = 256
+)
+
+// APIError is returned for responses of the Mimir API with a status code outside of 2xx.
+// The error string carries no response body, which may echo the pushed configuration;
+// only a short Snippet of it is kept, the body is logged at V(2).
+// For 404, 409 and 429 responses the error wraps ErrResourceNotFound, errConflict and
+// errTooManyRequests, so they can still be matched with errors.Is.
+type APIError struct {
+	// StatusCode is the HTTP status code of the response
+	StatusCode int
+	// TenantID is the tenant the request was sent for
+	TenantID string
+	// Path is the path of the request, without host and query
+	Path string
+	// Snippet is the start of the response body, at most maxSnippetSize bytes
+	Snippet string
+}
+
+// Error implements error.
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("server returned HTTP status: %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+	if e.Path != "" {
+		msg += " for " + e.Path
+	}
+	if e.TenantID != "" {
+		msg += fmt.Sprintf(" (tenant %s)", e.TenantID)
+	}
+	return msg
+}
+
+// Unwrap returns the sentinel error of the status code, nil if there is none.
+func (e *APIError) Unwrap() error {
+	switch e.StatusCode {
+	case http.StatusNotFound:
+		return ErrResourceNotFound
+	case http.StatusConflict:
+		return errConflict
+	case http.StatusTooManyRequests:
+		return errTooManyRequests
+	}
+	return nil
+}
+
+// snippet returns the start of body with at most maxSnippetSize bytes, cut at a rune
+// boundary, with surrounding whitespace removed.
+func snippet(body string) string {
+	if len(body) > maxSnippetSize {
+		cut := maxSnippetSize
+		for cut > 0 && !utf8.RuneStart(body[cut]) {
+			cut--
+		}
+		body = body[:cut]
+	}
+	return strings.TrimSpace(body)
+}
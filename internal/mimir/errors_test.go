@@ -0,0 +1,68 @@
+package mimir
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/prometheus/model/rulefmt"
+)
+
+func TestAPIError(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		body     string
+		sentinel error
+	}{
+		{name: "bad request", status: http.StatusBadRequest, body: "invalid rule group"},
+		{name: "not found", status: http.StatusNotFound, body: "group does not exist", sentinel: ErrResourceNotFound},
+		{name: "conflict", status: http.StatusConflict, sentinel: errConflict},
+		{name: "rate limited", status: http.StatusTooManyRequests, sentinel: errTooManyRequests},
+		{name: "server error", status: http.StatusInternalServerError, body: strings.Repeat("x", 4*maxSnippetSize)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			t.Cleanup(server.Close)
+			client := newTestClient(t, server.URL)
+
+			err := client.CreateRuleGroup(context.Background(), "ns", rulefmt.RuleGroup{Name: "group"}, "tenant-a")
+
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("expected an APIError, got %v", err)
+			}
+			if apiErr.StatusCode != tt.status || apiErr.TenantID != "tenant-a" {
+				t.Errorf("unexpected status %d or tenant %q", apiErr.StatusCode, apiErr.TenantID)
+			}
+			if !strings.HasSuffix(apiErr.Path, "/prometheus/config/v1/rules/ns") {
+				t.Errorf("unexpected path %q", apiErr.Path)
+			}
+			if len(apiErr.Snippet) > maxSnippetSize || !strings.HasPrefix(tt.body, apiErr.Snippet) {
+				t.Errorf("unexpected snippet %q", apiErr.Snippet)
+			}
+			if tt.body != "" && strings.Contains(err.Error(), tt.body) {
+				t.Errorf("error %q must not contain the response body", err)
+			}
+			if tt.sentinel != nil && !errors.Is(err, tt.sentinel) {
+				t.Errorf("expected error to match %v", tt.sentinel)
+			}
+		})
+	}
+}
+
+func TestSnippetCutsAtRuneBoundary(t *testing.T) {
+	body := strings.Repeat("a", maxSnippetSize-1) + "ü"
+	got := snippet(body)
+	if got != strings.Repeat("a", maxSnippetSize-1) {
+		t.Errorf("expected the partial rune to be dropped, got %q", got[len(got)-4:])
+	}
+}
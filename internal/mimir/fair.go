@@ -0,0 +1,114 @@
+package mimir
+
+import (
+	"context"
+	"slices"
+	"sync"
+)
+
+// fairLimiter bounds the requests in flight and grants freed slots round-robin across the
+// tenants waiting for one, so a tenant with thousands of pushes does not starve tenants with
+// a few. Requests of a tenant are granted in order. A nil fairLimiter does not limit requests.
+type fairLimiter struct {
+	mu    sync.Mutex
+	slots int
+	inUse int
+	// waiting holds the waiters of each tenant, closed when granted a slot
+	waiting map[string][]chan struct{}
+	// order holds the tenants with waiters in round-robin order, next the tenant granted next
+	order []string
+	next  int
+}
+
+// newFairLimiter returns a limiter of slots requests in flight, nil if slots is not positive.
+func newFairLimiter(slots int) *fairLimiter {
+	if slots <= 0 {
+		return nil
+	}
+	return &fairLimiter{slots: slots, waiting: map[string][]chan struct{}{}}
+}
+
+// acquire waits for a slot for a request of the tenant. Returns the error of ctx if it is done
+// before a slot is granted. Every successful acquire must be followed by a release.
+func (l *fairLimiter) acquire(ctx context.Context, tenantID string) error {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	if l.inUse < l.slots && len(l.order) == 0 {
+		l.inUse++
+		l.mu.Unlock()
+		return nil
+	}
+	granted := make(chan struct{})
+	if len(l.waiting[tenantID]) == 0 {
+		l.order = append(l.order, tenantID)
+	}
+	l.waiting[tenantID] = append(l.waiting[tenantID], granted)
+	l.mu.Unlock()
+
+	select {
+	case <-granted:
+		return nil
+	case <-ctx.Done():
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	queue := l.waiting[tenantID]
+	i := slices.Index(queue, granted)
+	if i < 0 {
+		// Granted while giving up, hand the slot on
+		l.releaseLocked()
+		return ctx.Err()
+	}
+	l.waiting[tenantID] = slices.Delete(queue, i, i+1)
+	if len(l.waiting[tenantID]) == 0 {
+		l.removeTenantLocked(tenantID)
+	}
+	return ctx.Err()
+}
+
+// release frees the slot of a request, granting it to the next waiting tenant if any.
+func (l *fairLimiter) release() {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.releaseLocked()
+}
+
+// releaseLocked frees or hands on a slot, the caller must hold the lock.
+func (l *fairLimiter) releaseLocked() {
+	if len(l.order) == 0 {
+		l.inUse--
+		return
+	}
+	l.next %= len(l.order)
+	tenantID := l.order[l.next]
+	queue := l.waiting[tenantID]
+	granted := queue[0]
+	l.waiting[tenantID] = queue[1:]
+	if len(l.waiting[tenantID]) == 0 {
+		// The following tenant moves to the index of the removed one
+		l.removeTenantLocked(tenantID)
+	} else {
+		l.next++
+	}
+	close(granted)
+}
+
+// removeTenantLocked removes a tenant without waiters from the round-robin order, the caller
+// must hold the lock.
+func (l *fairLimiter) removeTenantLocked(tenantID string) {
+	delete(l.waiting, tenantID)
+	i := slices.Index(l.order, tenantID)
+	if i < 0 {
+		return
+	}
+	l.order = slices.Delete(l.order, i, i+1)
+	if i < l.next {
+		l.next--
+	}
+}
@@ -0,0 +1,104 @@
+package mimir
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// waiters returns the number of requests waiting for a slot.
+func (l *fairLimiter) waiters() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	n := 0
+	for _, queue := range l.waiting {
+		n += len(queue)
+	}
+	return n
+}
+
+// enqueue starts a request of the tenant waiting for a slot, which sends name to granted
+// once acquired.
+func enqueue(t *testing.T, limiter *fairLimiter, tenantID, name string, granted chan<- string) {
+	t.Helper()
+	before := limiter.waiters()
+	go func() {
+		if err := limiter.acquire(context.Background(), tenantID); err == nil {
+			granted <- name
+		}
+	}()
+	deadline := time.Now().Add(5 * time.Second)
+	for limiter.waiters() == before {
+		if time.Now().After(deadline) {
+			t.Fatalf("request %s is not waiting", name)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestFairLimiterRoundRobin(t *testing.T) {
+	limiter := newFairLimiter(1)
+	if err := limiter.acquire(context.Background(), "big"); err != nil {
+		t.Fatalf("acquire: %v", err)
+	}
+
+	granted := make(chan string, 4)
+	for _, name := range []string{"big-1", "big-2", "big-3"} {
+		enqueue(t, limiter, "big", name, granted)
+	}
+	enqueue(t, limiter, "small", "small-1", granted)
+
+	var order []string
+	for range 4 {
+		limiter.release()
+		order = append(order, <-granted)
+	}
+	limiter.release()
+
+	expected := []string{"big-1", "small-1", "big-2", "big-3"}
+	for i := range expected {
+		if order[i] != expected[i] {
+			t.Fatalf("expected slots granted in order %v, got %v", expected, order)
+		}
+	}
+	if limiter.inUse != 0 || len(limiter.order) != 0 {
+		t.Errorf("expected all slots released, got %d in use and waiting tenants %v", limiter.inUse, limiter.order)
+	}
+}
+
+func TestFairLimiterCancel(t *testing.T) {
+	limiter := newFairLimiter(1)
+	if err := limiter.acquire(context.Background(), "tenant"); err != nil {
+		t.Fatalf("acquire: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := limiter.acquire(ctx, "other"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the deadline to be exceeded, got %v", err)
+	}
+	if limiter.waiters() != 0 {
+		t.Errorf("expected the cancelled request to stop waiting")
+	}
+
+	limiter.release()
+	if err := limiter.acquire(context.Background(), "other"); err != nil {
+		t.Fatalf("expected the freed slot to be granted, got %v", err)
+	}
+	limiter.release()
+	if limiter.inUse != 0 {
+		t.Errorf("expected no slot in use, got %d", limiter.inUse)
+	}
+}
+
+func TestFairLimiterDisabled(t *testing.T) {
+	limiter := newFairLimiter(0)
+	if limiter != nil {
+		t.Fatal("expected no limiter without slots")
+	}
+	if err := limiter.acquire(context.Background(), "tenant"); err != nil {
+		t.Errorf("expected a disabled limiter to grant requests, got %v", err)
+	}
+	limiter.release()
+}
@@ -0,0 +1,60 @@
+package mimir
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/prometheus/common/expfmt"
+	"github.com/prometheus/common/model"
+)
+
+const alertmanagerAPIMetrics = "/alertmanager/metrics"
+
+// notificationFailureMetrics are the counters of failed notifications exposed by the
+// Alertmanager of a tenant and by the Mimir multi-tenant Alertmanager
+var notificationFailureMetrics = []string{
+	"alertmanager_notifications_failed_total",
+	"cortex_alertmanager_notifications_failed_total",
+}
+
+// GetNotificationFailures scrapes the metrics of the tenant's Alertmanager and returns the
+// number of failed notifications by integration, e.g. slack or email.
+// Series of the multi-tenant Alertmanager carrying a user label are only counted for tenantID.
+// Returns an error if the request fails or the metrics cannot be parsed.
+func (r *Client) GetNotificationFailures(ctx context.Context, tenantID string) (map[string]float64, error) {
+	res, err := r.doRequest(ctx, alertmanagerAPIMetrics, "GET", nil, -1, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = res.Body.Close() }()
+
+	parser := expfmt.NewTextParser(model.UTF8Validation)
+	families, err := parser.TextToMetricFamilies(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse Alertmanager metrics: %w", err)
+	}
+
+	failures := map[string]float64{}
+	for _, name := range notificationFailureMetrics {
+		family, ok := families[name]
+		if !ok {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			var integration string
+			sameTenant := true
+			for _, label := range metric.GetLabel() {
+				switch label.GetName() {
+				case "integration":
+					integration = label.GetValue()
+				case "user":
+					sameTenant = label.GetValue() == tenantID
+				}
+			}
+			if sameTenant && integration != "" {
+				failures[integration] += metric.GetCounter().GetValue()
+			}
+		}
+	}
+	return failures, nil
+}
@@ -0,0 +1,37 @@
+package mimir
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestGetNotificationFailures(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != alertmanagerAPIMetrics {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`# TYPE alertmanager_notifications_failed_total counter
+alertmanager_notifications_failed_total{integration="email"} 2
+alertmanager_notifications_failed_total{integration="slack",reason="other"} 1
+alertmanager_notifications_failed_total{integration="slack",reason="clientError"} 3
+# TYPE cortex_alertmanager_notifications_failed_total counter
+cortex_alertmanager_notifications_failed_total{integration="webhook",user="team-a"} 4
+cortex_alertmanager_notifications_failed_total{integration="webhook",user="team-b"} 9
+`))
+	}))
+	t.Cleanup(server.Close)
+	client := newTestClient(t, server.URL)
+
+	failures, err := client.GetNotificationFailures(context.Background(), "team-a")
+	if err != nil {
+		t.Fatalf("GetNotificationFailures() error = %v", err)
+	}
+	want := map[string]float64{"email": 2, "slack": 4, "webhook": 4}
+	if !reflect.DeepEqual(failures, want) {
+		t.Errorf("expected failures %v, got %v", want, failures)
+	}
+}
@@ -0,0 +1,146 @@
+package mimir
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// socks5Server returns the address of a SOCKS5 proxy without authentication supporting
+// CONNECT to IPv4 addresses and domain names, and the number of connections it proxied.
+func socks5Server(t *testing.T) (string, *atomic.Int32) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = listener.Close() })
+	var proxied atomic.Int32
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer func() { _ = conn.Close() }()
+				target, ok := socks5Handshake(conn)
+				if !ok {
+					return
+				}
+				upstream, err := net.Dial("tcp", target)
+				if err != nil {
+					_, _ = conn.Write([]byte{5, 5, 0, 1, 0, 0, 0, 0, 0, 0})
+					return
+				}
+				defer func() { _ = upstream.Close() }()
+				proxied.Add(1)
+				_, _ = conn.Write([]byte{5, 0, 0, 1, 0, 0, 0, 0, 0, 0})
+				go func() { _, _ = io.Copy(upstream, conn) }()
+				_, _ = io.Copy(conn, upstream)
+			}()
+		}
+	}()
+	return listener.Addr().String(), &proxied
+}
+
+// socks5Handshake negotiates no authentication and reads the target of a CONNECT request.
+func socks5Handshake(conn net.Conn) (string, bool) {
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		return "", false
+	}
+	if _, err := io.ReadFull(conn, make([]byte, header[1])); err != nil {
+		return "", false
+	}
+	_, _ = conn.Write([]byte{5, 0})
+
+	request := make([]byte, 4)
+	if _, err := io.ReadFull(conn, request); err != nil {
+		return "", false
+	}
+	var host string
+	switch request[3] {
+	case 1:
+		ip := make([]byte, 4)
+		if _, err := io.ReadFull(conn, ip); err != nil {
+			return "", false
+		}
+		host = net.IP(ip).String()
+	case 3:
+		length := make([]byte, 1)
+		if _, err := io.ReadFull(conn, length); err != nil {
+			return "", false
+		}
+		name := make([]byte, length[0])
+		if _, err := io.ReadFull(conn, name); err != nil {
+			return "", false
+		}
+		host = string(name)
+	default:
+		return "", false
+	}
+	port := make([]byte, 2)
+	if _, err := io.ReadFull(conn, port); err != nil {
+		return "", false
+	}
+	return net.JoinHostPort(host, strconv.Itoa(int(binary.BigEndian.Uint16(port)))), true
+}
+
+func TestProxy(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+	proxyAddress, proxied := socks5Server(t)
+	ctx := context.Background()
+
+	client, err := New(ctx, Config{Address: server.URL, ProxyURL: "socks5h://" + proxyAddress})
+	if err != nil {
+		t.Fatalf("creating client: %v", err)
+	}
+	if err := client.HealthCheck(ctx); err != nil {
+		t.Fatalf("HealthCheck() through the proxy: %v", err)
+	}
+	if proxied.Load() != 1 {
+		t.Errorf("expected the health check to be sent through the proxy, proxied %d connections", proxied.Load())
+	}
+
+	// Nothing listens on the port of a closed listener
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	unreachable := "socks5://user:secret@" + closed.Addr().String()
+	_ = closed.Close()
+	client, err = New(ctx, Config{Address: server.URL, ProxyURL: unreachable})
+	if err != nil {
+		t.Fatalf("creating client: %v", err)
+	}
+	err = client.HealthCheck(ctx)
+	if err == nil || !strings.Contains(err.Error(), "check that the proxy socks5://user:xxxxx@") ||
+		strings.Contains(err.Error(), "secret") {
+		t.Errorf("expected the health check to name the redacted proxy, got %v", err)
+	}
+}
+
+func TestParseProxyURL(t *testing.T) {
+	for proxyURL, valid := range map[string]bool{
+		"socks5://bastion:1080":    true,
+		"socks5h://localhost:1080": true,
+		"http://proxy:3128":        false,
+		"socks5://":                false,
+		"bastion:1080":             false,
+	} {
+		if _, err := ParseProxyURL(proxyURL); (err == nil) != valid {
+			t.Errorf("ParseProxyURL(%q) error = %v, want valid %v", proxyURL, err, valid)
+		}
+	}
+}
@@ -3,9 +3,13 @@ package mimir
 import (
 	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/url"
+	"sort"
+	"time"
 
 	"github.com/prometheus/prometheus/model/rulefmt"
 
@@ -21,7 +25,36 @@ func (r *Client) CreateRuleGroup(ctx context.Context, namespace string, rg rulef
 	if err != nil {
 		return err
 	}
+	return r.pushRuleGroup(ctx, namespace, payload, tenantID)
+}
+
+// federatedRuleGroup is a rule group with the source tenants Mimir evaluates its rules
+// against, which rulefmt does not support.
+type federatedRuleGroup struct {
+	rulefmt.RuleGroup `yaml:",inline"`
+	SourceTenants     []string `yaml:"source_tenants,omitempty"`
+}
+
+// CreateFederatedRuleGroup creates or updates a rule group in the specified namespace whose
+// rules are evaluated against the data of sourceTenants instead of tenantID, which stores the
+// group and its results. Mimir must have tenant federation for rules enabled.
+// Without sourceTenants it behaves like CreateRuleGroup.
+func (r *Client) CreateFederatedRuleGroup(
+	ctx context.Context,
+	namespace string,
+	rg rulefmt.RuleGroup,
+	sourceTenants []string,
+	tenantID string,
+) error {
+	payload, err := yaml.Marshal(&federatedRuleGroup{RuleGroup: rg, SourceTenants: sourceTenants})
+	if err != nil {
+		return err
+	}
+	return r.pushRuleGroup(ctx, namespace, payload, tenantID)
+}
 
+// pushRuleGroup sends the marshalled rule group payload to the namespace of the tenant.
+func (r *Client) pushRuleGroup(ctx context.Context, namespace string, payload []byte, tenantID string) error {
 	escapedNamespace := url.PathEscape(namespace)
 	path := r.apiPath + "/" + escapedNamespace
 
@@ -65,15 +98,7 @@ func (r *Client) GetRuleGroup(ctx context.Context, namespace, groupName string,
 	escapedGroupName := url.PathEscape(groupName)
 	path := r.apiPath + "/" + escapedNamespace + "/" + escapedGroupName
 
-	fmt.Println(path)
-	res, err := r.doRequest(ctx, path, "GET", nil, -1, tenantID)
-	if err != nil {
-		return nil, err
-	}
-
-	defer func() { _ = res.Body.Close() }()
-	body, err := io.ReadAll(res.Body)
-
+	body, err := r.getCached(ctx, path, tenantID)
 	if err != nil {
 		return nil, err
 	}
@@ -98,28 +123,80 @@ func (r *Client) GetRuleGroup(ctx context.Context, namespace, groupName string,
 func (r *Client) ListRules(ctx context.Context, namespace string, tenantID string) (map[string][]rulefmt.RuleGroup, error) {
 	path := r.apiPath
 	if namespace != "" {
-		path = path + "/" + namespace
+		path = path + "/" + url.PathEscape(namespace)
 	}
 
-	res, err := r.doRequest(ctx, path, "GET", nil, -1, tenantID)
+	body, err := r.getCached(ctx, path, tenantID)
 	if err != nil {
 		return nil, err
 	}
 
-	defer func() { _ = res.Body.Close() }()
-	body, err := io.ReadAll(res.Body)
-
+	ruleSet := map[string][]rulefmt.RuleGroup{}
+	err = yaml.Unmarshal(body, &ruleSet)
 	if err != nil {
 		return nil, err
 	}
 
-	ruleSet := map[string][]rulefmt.RuleGroup{}
-	err = yaml.Unmarshal(body, &ruleSet)
+	return ruleSet, nil
+}
+
+// ListRuleNamespaces retrieves the names of all rule namespaces of the tenant.
+// Only the group names are decoded from the response, so unlike ListRules the rules
+// of the tenant are never held in memory.
+// Returns the sorted namespace names, or an error if the request fails.
+func (r *Client) ListRuleNamespaces(ctx context.Context, tenantID string) ([]string, error) {
+	res, err := r.doRequest(ctx, r.apiPath, "GET", nil, -1, tenantID)
 	if err != nil {
 		return nil, err
 	}
 
-	return ruleSet, nil
+	defer func() { _ = res.Body.Close() }()
+
+	// Decoding into group names only skips the rules of every group
+	ruleSet := map[string][]struct {
+		Name string `yaml:"name"`
+	}{}
+	if err := yaml.NewDecoder(res.Body).Decode(&ruleSet); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("unable to unmarshal response, %w", err)
+	}
+
+	namespaces := make([]string, 0, len(ruleSet))
+	for namespace := range ruleSet {
+		namespaces = append(namespaces, namespace)
+	}
+	sort.Strings(namespaces)
+	return namespaces, nil
+}
+
+// WalkRules lists the rule groups of the tenant one namespace at a time, calling fn
+// for every namespace. Only the rule groups of a single namespace are held in memory,
+// which keeps listing tenants with thousands of groups cheap.
+// Namespaces deleted while walking are skipped. Walking stops at the first error
+// returned by fn or by the API.
+func (r *Client) WalkRules(
+	ctx context.Context,
+	tenantID string,
+	fn func(namespace string, groups []rulefmt.RuleGroup) error,
+) error {
+	namespaces, err := r.ListRuleNamespaces(ctx, tenantID)
+	if err != nil {
+		return err
+	}
+
+	for _, namespace := range namespaces {
+		ruleSet, err := r.ListRules(ctx, namespace, tenantID)
+		if errors.Is(err, ErrResourceNotFound) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("listing rules of namespace %s: %w", namespace, err)
+		}
+		if err := fn(namespace, ruleSet[namespace]); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
 // DeleteNamespace deletes all rule groups in a namespace including the namespace itself.
@@ -140,3 +217,55 @@ func (r *Client) DeleteNamespace(ctx context.Context, namespace string, tenantID
 
 	return nil
 }
+
+const rulerStateAPIPath = "/prometheus/api/v1/rules"
+
+// RuleGroupState is the evaluation state of a rule group as reported by the ruler.
+// File holds the rule namespace the group belongs to.
+type RuleGroupState struct {
+	Name           string      `json:"name"`
+	File           string      `json:"file"`
+	Rules          []RuleState `json:"rules"`
+	LastEvaluation time.Time   `json:"lastEvaluation"`
+}
+
+// RuleState is the evaluation state of a single rule as reported by the ruler.
+type RuleState struct {
+	Name           string    `json:"name"`
+	Health         string    `json:"health"`
+	LastError      string    `json:"lastError,omitempty"`
+	LastEvaluation time.Time `json:"lastEvaluation"`
+}
+
+type rulerStateResponse struct {
+	Status string `json:"status"`
+	Data   struct {
+		Groups []RuleGroupState `json:"groups"`
+	} `json:"data"`
+}
+
+// ActiveRuleGroups retrieves the rule groups currently loaded and evaluated by the ruler for the tenant.
+// Unlike ListRules, which returns the stored configuration, this reflects the ruler's runtime state.
+// Returns an error if the request fails or the response cannot be decoded.
+func (r *Client) ActiveRuleGroups(ctx context.Context, tenantID string) ([]RuleGroupState, error) {
+	res, err := r.doRequest(ctx, rulerStateAPIPath, "GET", nil, -1, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = res.Body.Close() }()
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	state := rulerStateResponse{}
+	if err := json.Unmarshal(body, &state); err != nil {
+		return nil, fmt.Errorf("unable to unmarshal ruler state response, %w", err)
+	}
+	if state.Status != "success" {
+		return nil, fmt.Errorf("ruler state request returned status %q", state.Status)
+	}
+
+	return state.Data.Groups, nil
+}
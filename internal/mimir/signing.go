@@ -0,0 +1,109 @@
+package mimir
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RequestMiddleware prepares a request right before it is sent, after its authentication,
+// headers and compressed body are set, e.g. to sign it for a gateway.
+// Returns an error to abort the request.
+type RequestMiddleware func(req *http.Request) error
+
+const (
+	// DefaultHMACHeader is the header the HMAC signature is sent in by default
+	DefaultHMACHeader = "X-Signature"
+	// HMACTimestampHeader holds the Unix time in seconds the signature was created at, which
+	// is part of the signed content so gateways can reject replayed requests
+	HMACTimestampHeader = "X-Signature-Timestamp"
+
+	// HMACSHA256 signs requests with HMAC-SHA256
+	HMACSHA256 = "SHA256"
+	// HMACSHA512 signs requests with HMAC-SHA512
+	HMACSHA512 = "SHA512"
+)
+
+// HMACConfig configures the HMAC signature of requests.
+type HMACConfig struct {
+	// Key is the shared secret of the signature
+	Key []byte
+	// Header is the header of the signature, DefaultHMACHeader if empty
+	Header string
+	// Algorithm is HMACSHA256 or HMACSHA512, HMACSHA256 if empty
+	Algorithm string
+}
+
+// HMACSigner returns a RequestMiddleware signing requests with an HMAC of
+//
+//	<method>\n<request URI>\n<timestamp>\n<body>
+//
+// sent hex encoded in the configured header, with the timestamp in HMACTimestampHeader.
+// The body is signed as sent, i.e. compressed if the client compresses requests.
+// Returns an error if the key is empty or the algorithm is unknown.
+func HMACSigner(cfg HMACConfig) (RequestMiddleware, error) {
+	if len(cfg.Key) == 0 {
+		return nil, errors.New("HMAC signing requires a key")
+	}
+	var newHash func() hash.Hash
+	switch cfg.Algorithm {
+	case "", HMACSHA256:
+		newHash = sha256.New
+	case HMACSHA512:
+		newHash = sha512.New
+	default:
+		return nil, fmt.Errorf("unknown HMAC algorithm %q, use %s or %s", cfg.Algorithm, HMACSHA256, HMACSHA512)
+	}
+	header := cfg.Header
+	if header == "" {
+		header = DefaultHMACHeader
+	}
+
+	return func(req *http.Request) error {
+		body, err := bodyToSign(req)
+		if err != nil {
+			return fmt.Errorf("reading the body to sign: %w", err)
+		}
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		mac := hmac.New(newHash, cfg.Key)
+		_, _ = fmt.Fprintf(mac, "%s\n%s\n%s\n", req.Method, req.URL.RequestURI(), timestamp)
+		_, _ = mac.Write(body)
+		req.Header.Set(HMACTimestampHeader, timestamp)
+		req.Header.Set(header, hex.EncodeToString(mac.Sum(nil)))
+		return nil
+	}, nil
+}
+
+// bodyToSign returns the body of req, leaving it readable for sending.
+func bodyToSign(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		defer func() { _ = body.Close() }()
+		return io.ReadAll(body)
+	}
+	body, err := io.ReadAll(req.Body)
+	_ = req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(body)), nil
+	}
+	return body, nil
+}
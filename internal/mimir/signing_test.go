@@ -0,0 +1,85 @@
+package mimir
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestHMACSigner(t *testing.T) {
+	if _, err := HMACSigner(HMACConfig{}); err == nil {
+		t.Error("expected an error without key")
+	}
+	if _, err := HMACSigner(HMACConfig{Key: []byte("k"), Algorithm: "MD5"}); err == nil {
+		t.Error("expected an error for an unknown algorithm")
+	}
+
+	var mu sync.Mutex
+	var requests []*http.Request
+	var bodies [][]byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		requests, bodies = append(requests, r), append(bodies, body)
+		mu.Unlock()
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	t.Cleanup(server.Close)
+
+	key := []byte("gateway-secret")
+	signer, err := HMACSigner(HMACConfig{Key: key, Header: "X-Gateway-Signature", Algorithm: HMACSHA512})
+	if err != nil {
+		t.Fatalf("HMACSigner() error = %v", err)
+	}
+	ctx := context.Background()
+	client, err := New(ctx, Config{Address: server.URL, PathPrefix: "/mimir", Middlewares: []RequestMiddleware{signer}})
+	if err != nil {
+		t.Fatalf("creating client: %v", err)
+	}
+	if err := client.CreateAlertmanagerConfig(ctx, "route:\n  receiver: team\n", nil, "tenant-a"); err != nil {
+		t.Fatalf("CreateAlertmanagerConfig() error = %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(requests) != 1 {
+		t.Fatalf("expected one request, got %d", len(requests))
+	}
+	req, body := requests[0], bodies[0]
+	if !strings.Contains(string(body), "receiver: team") {
+		t.Errorf("expected the signed body to be sent, got %q", body)
+	}
+	timestamp := req.Header.Get(HMACTimestampHeader)
+	mac := hmac.New(sha512.New, key)
+	_, _ = io.WriteString(mac, req.Method+"\n"+req.URL.RequestURI()+"\n"+timestamp+"\n")
+	_, _ = mac.Write(body)
+	if timestamp == "" || req.Header.Get("X-Gateway-Signature") != hex.EncodeToString(mac.Sum(nil)) {
+		t.Errorf("unexpected signature %q at %q", req.Header.Get("X-Gateway-Signature"), timestamp)
+	}
+}
+
+func TestRequestMiddlewareError(t *testing.T) {
+	server, orgIDs := recordingServer(t)
+	refused := errors.New("refused")
+	client, err := New(context.Background(), Config{
+		Address:     server.URL,
+		Middlewares: []RequestMiddleware{func(*http.Request) error { return refused }},
+	})
+	if err != nil {
+		t.Fatalf("creating client: %v", err)
+	}
+	if err := client.HealthCheck(context.Background()); !errors.Is(err, refused) {
+		t.Errorf("expected the middleware error, got %v", err)
+	}
+	if len(orgIDs()) != 0 {
+		t.Errorf("expected no request to be sent, got %v", orgIDs())
+	}
+}
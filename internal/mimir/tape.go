@@ -0,0 +1,207 @@
+package mimir
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// TapeBodyLimit is the number of bytes of a request or response body kept by a Tape
+	TapeBodyLimit = 8 * 1024
+
+	// redacted replaces header values and secrets in recorded bodies
+	redacted = "<redacted>"
+)
+
+var (
+	// sensitiveHeaderPattern matches the names of headers and form fields whose values are never recorded
+	sensitiveHeaderPattern = regexp.MustCompile(`(?i)auth|cookie|token|key|secret|password|credential|signature`)
+	// secretFieldPattern matches YAML and JSON fields of Alertmanager configurations holding secrets,
+	// e.g. smtp_auth_password, bot_token, routing_key, api_url or webhook_url, with their value
+	secretFieldPattern = regexp.MustCompile(
+		`(?i)("?[a-z_]*(?:password|secret|token|_key|credentials|url)"?\s*:[ \t]*)` +
+			`("(?:[^"\\]|\\.)*"|'[^']*'|[^\s,}#][^,}\n]*)`)
+)
+
+// TapeEntry is a request to the Mimir API recorded by a Tape with its response.
+// Credentials are redacted from headers and bodies, bodies are truncated to TapeBodyLimit.
+type TapeEntry struct {
+	Time            time.Time   `json:"time" yaml:"time"`
+	Method          string      `json:"method" yaml:"method"`
+	URL             string      `json:"url" yaml:"url"`
+	TenantID        string      `json:"tenantID,omitempty" yaml:"tenantID,omitempty"`
+	RequestHeaders  http.Header `json:"requestHeaders,omitempty" yaml:"requestHeaders,omitempty"`
+	RequestBody     string      `json:"requestBody,omitempty" yaml:"requestBody,omitempty"`
+	Status          int         `json:"status,omitempty" yaml:"status,omitempty"`
+	ResponseHeaders http.Header `json:"responseHeaders,omitempty" yaml:"responseHeaders,omitempty"`
+	ResponseBody    string      `json:"responseBody,omitempty" yaml:"responseBody,omitempty"`
+	Duration        string      `json:"duration" yaml:"duration"`
+	Error           string      `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// Tape records the last requests of Mimir clients in a ring buffer, to inspect what was sent
+// when a push misbehaves. A Tape is shared by all clients and safe for concurrent use.
+type Tape struct {
+	mu      sync.Mutex
+	entries []TapeEntry
+	// next is the index the next entry is recorded at
+	next int
+	// full reports whether the buffer wrapped around
+	full bool
+}
+
+// NewTape returns a Tape keeping the last size requests, nil if size is not positive.
+func NewTape(size int) *Tape {
+	if size <= 0 {
+		return nil
+	}
+	return &Tape{entries: make([]TapeEntry, size)}
+}
+
+// Entries returns the recorded requests, oldest first.
+func (t *Tape) Entries() []TapeEntry {
+	if t == nil {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !t.full {
+		return append([]TapeEntry(nil), t.entries[:t.next]...)
+	}
+	return append(append([]TapeEntry(nil), t.entries[t.next:]...), t.entries[:t.next]...)
+}
+
+// record adds entry, replacing the oldest entry if the tape is full.
+func (t *Tape) record(entry TapeEntry) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.entries[t.next] = entry
+	t.next = (t.next + 1) % len(t.entries)
+	if t.next == 0 {
+		t.full = true
+	}
+}
+
+// RoundTripper returns next recording its requests on the tape, next itself if the tape is nil.
+func (t *Tape) RoundTripper(next http.RoundTripper) http.RoundTripper {
+	if t == nil {
+		return next
+	}
+	return &tapeRoundTripper{tape: t, next: next}
+}
+
+// tapeRoundTripper records the requests sent through next on a Tape.
+type tapeRoundTripper struct {
+	tape *Tape
+	next http.RoundTripper
+}
+
+// RoundTrip sends req through the wrapped RoundTripper and records it with its response.
+// The response body is read and replaced to be recorded; the request body is only recorded
+// if it can be obtained again through req.GetBody.
+func (r *tapeRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	entry := TapeEntry{
+		Time:           time.Now(),
+		Method:         req.Method,
+		URL:            req.URL.Redacted(),
+		TenantID:       req.Header.Get("X-Scope-OrgID"),
+		RequestHeaders: redactHeaders(req.Header),
+		RequestBody:    requestBody(req),
+	}
+
+	res, err := r.next.RoundTrip(req)
+	entry.Duration = time.Since(entry.Time).String()
+	if err != nil {
+		entry.Error = err.Error()
+		r.tape.record(entry)
+		return res, err
+	}
+
+	entry.Status = res.StatusCode
+	entry.ResponseHeaders = redactHeaders(res.Header)
+	if res.Body != nil {
+		body, readErr := io.ReadAll(res.Body)
+		_ = res.Body.Close()
+		res.Body = io.NopCloser(bytes.NewReader(body))
+		entry.ResponseBody = scrubBody(body)
+		if readErr != nil {
+			entry.Error = readErr.Error()
+		}
+	}
+	r.tape.record(entry)
+	return res, nil
+}
+
+// requestBody returns the scrubbed body of req, decompressed if it is gzip encoded.
+func requestBody(req *http.Request) string {
+	if req.GetBody == nil {
+		return ""
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return fmt.Sprintf("<unreadable: %v>", err)
+	}
+	defer func() { _ = body.Close() }()
+
+	var reader io.Reader = body
+	if req.Header.Get("Content-Encoding") == "gzip" {
+		decompressed, err := gzip.NewReader(body)
+		if err != nil {
+			return fmt.Sprintf("<unreadable: %v>", err)
+		}
+		reader = decompressed
+	}
+	payload, err := io.ReadAll(reader)
+	if err != nil {
+		return fmt.Sprintf("<unreadable: %v>", err)
+	}
+	if req.Header.Get("Content-Type") == "application/x-www-form-urlencoded" {
+		// Forms, e.g. of the token exchange, are not YAML and are redacted by field name
+		form, err := url.ParseQuery(string(payload))
+		if err != nil {
+			return redacted
+		}
+		for name := range form {
+			if sensitiveHeaderPattern.MatchString(name) {
+				form[name] = []string{redacted}
+			}
+		}
+		return scrubBody([]byte(form.Encode()))
+	}
+	return scrubBody(payload)
+}
+
+// redactHeaders returns a copy of header with the values of credential headers redacted.
+func redactHeaders(header http.Header) http.Header {
+	if len(header) == 0 {
+		return nil
+	}
+	redactedHeader := make(http.Header, len(header))
+	for name, values := range header {
+		if sensitiveHeaderPattern.MatchString(name) {
+			redactedHeader[name] = []string{redacted}
+			continue
+		}
+		redactedHeader[name] = append([]string(nil), values...)
+	}
+	return redactedHeader
+}
+
+// scrubBody returns body with the values of secret fields redacted, truncated to TapeBodyLimit.
+// Secrets are redacted before truncation, so that truncated secrets cannot leak.
+func scrubBody(body []byte) string {
+	scrubbed := secretFieldPattern.ReplaceAllString(string(body), "${1}"+redacted)
+	if len(scrubbed) <= TapeBodyLimit {
+		return scrubbed
+	}
+	return strings.ToValidUTF8(scrubbed[:TapeBodyLimit], "") +
+		fmt.Sprintf("... (%d bytes truncated)", len(scrubbed)-TapeBodyLimit)
+}
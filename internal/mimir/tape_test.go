@@ -0,0 +1,124 @@
+package mimir
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestTapeRecordsRequests(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if r.Method == http.MethodGet {
+			w.Header().Set("Set-Cookie", "session=secret")
+			_, _ = w.Write([]byte("alertmanager_config: |\n  global:\n    slack_api_url: https://hooks.example.com/T0/B0\n"))
+			return
+		}
+		if strings.Contains(string(body), "hunter2") {
+			t.Errorf("expected the server to receive the configuration unchanged")
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	t.Cleanup(server.Close)
+
+	tape := NewTape(2)
+	client, err := New(context.Background(), Config{
+		Address:      server.URL,
+		AuthToken:    "token",
+		ExtraHeaders: map[string]string{"X-Api-Key": "key", "X-Team": "payments"},
+		GzipRequests: true,
+		Tape:         tape,
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	config := "global:\n  smtp_auth_password: hunter2\nroute:\n  receiver: default\n" + strings.Repeat("#\n", gzipMinSize)
+	if err := client.CreateAlertmanagerConfig(context.Background(), config, nil, "team-a"); err != nil {
+		t.Fatalf("CreateAlertmanagerConfig() error = %v", err)
+	}
+	if _, _, err := client.GetAlertmanagerConfig(context.Background(), "team-a"); err != nil {
+		t.Fatalf("GetAlertmanagerConfig() error = %v", err)
+	}
+
+	entries := tape.Entries()
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 recorded requests, got %d", len(entries))
+	}
+	push, get := entries[0], entries[1]
+	if push.Method != http.MethodPost || push.TenantID != "team-a" || push.Status != http.StatusCreated {
+		t.Errorf("unexpected recorded push: %+v", push)
+	}
+	for _, header := range []string{"Authorization", "X-Api-Key"} {
+		if got := push.RequestHeaders.Get(header); got != redacted {
+			t.Errorf("expected %s to be redacted, got %q", header, got)
+		}
+	}
+	if got := push.RequestHeaders.Get("X-Team"); got != "payments" {
+		t.Errorf("expected X-Team to be recorded, got %q", got)
+	}
+	if !strings.Contains(push.RequestBody, "smtp_auth_password: "+redacted) ||
+		!strings.Contains(push.RequestBody, "receiver: default") || strings.Contains(push.RequestBody, "hunter2") {
+		t.Errorf("expected the decompressed body with the password scrubbed, got:\n%s", push.RequestBody)
+	}
+	if got := get.ResponseHeaders.Get("Set-Cookie"); got != redacted {
+		t.Errorf("expected Set-Cookie to be redacted, got %q", got)
+	}
+	if strings.Contains(get.ResponseBody, "hooks.example.com") {
+		t.Errorf("expected the webhook URL to be scrubbed, got:\n%s", get.ResponseBody)
+	}
+}
+
+func TestTapeKeepsLastRequests(t *testing.T) {
+	tape := NewTape(2)
+	for i := range 3 {
+		tape.record(TapeEntry{URL: fmt.Sprint(i)})
+	}
+	entries := tape.Entries()
+	if len(entries) != 2 || entries[0].URL != "1" || entries[1].URL != "2" {
+		t.Errorf("expected the last two requests oldest first, got %+v", entries)
+	}
+
+	if NewTape(0) != nil {
+		t.Errorf("expected no tape for size 0")
+	}
+	var disabled *Tape
+	if disabled.Entries() != nil || disabled.RoundTripper(http.DefaultTransport) != http.DefaultTransport {
+		t.Errorf("expected a nil tape to record nothing")
+	}
+}
+
+func TestScrubBody(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{
+			name: "yaml",
+			body: "receivers:\n  - pagerduty_configs:\n      - routing_key: 'abc'\n        severity: critical\n",
+			want: "receivers:\n  - pagerduty_configs:\n      - routing_key: <redacted>\n        severity: critical\n",
+		},
+		{
+			name: "json",
+			body: `{"access_token":"eyJ\"x","expires_in":300}`,
+			want: `{"access_token":<redacted>,"expires_in":300}`,
+		},
+		{
+			name: "truncated",
+			body: strings.Repeat("a", TapeBodyLimit+5),
+			want: strings.Repeat("a", TapeBodyLimit) + "... (5 bytes truncated)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := scrubBody([]byte(tt.body)); got != tt.want {
+				t.Errorf("scrubBody() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package mimir
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+)
+
+// distributorUserStatsPath is the admin API page of the distributor listing the ingestion
+// statistics of every tenant
+const distributorUserStatsPath = "/distributor/all_user_stats"
+
+// userStats is the part of the ingestion statistics of a tenant read by ListTenants
+type userStats struct {
+	UserID string `json:"userID"`
+}
+
+// ListTenants lists the tenants known to the distributors of Mimir, i.e. the tenants that
+// ingested series recently, through the admin API, which is not scoped to a tenant.
+// Returns the sorted tenant IDs, or an error if the request fails, e.g. because the admin API
+// is not exposed by the gateway, or the response cannot be decoded.
+func (r *Client) ListTenants(ctx context.Context) ([]string, error) {
+	header := http.Header{"Accept": []string{"application/json"}}
+	res, err := r.doRequestWithHeader(ctx, distributorUserStatsPath, "GET", nil, -1, "", header)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = res.Body.Close() }()
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var stats []userStats
+	if err := json.Unmarshal(body, &stats); err != nil {
+		return nil, fmt.Errorf("unable to unmarshal tenant statistics response, %w", err)
+	}
+	tenants := make([]string, 0, len(stats))
+	for _, entry := range stats {
+		if entry.UserID != "" {
+			tenants = append(tenants, entry.UserID)
+		}
+	}
+	slices.Sort(tenants)
+	return slices.Compact(tenants), nil
+}
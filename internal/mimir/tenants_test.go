@@ -0,0 +1,44 @@
+package mimir
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestListTenants(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != distributorUserStatsPath || r.Header.Get("Accept") != "application/json" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`[
+  {"userID": "team-b", "ingestionRate": 10, "numSeries": 100},
+  {"userID": "team-a", "ingestionRate": 5, "numSeries": 50},
+  {"userID": "team-b", "ingestionRate": 1, "numSeries": 1}
+]`))
+	}))
+	t.Cleanup(server.Close)
+
+	tenants, err := newTestClient(t, server.URL).ListTenants(context.Background())
+	if err != nil {
+		t.Fatalf("ListTenants() error = %v", err)
+	}
+	if want := []string{"team-a", "team-b"}; !reflect.DeepEqual(tenants, want) {
+		t.Errorf("expected tenants %v, got %v", want, tenants)
+	}
+}
+
+func TestListTenantsWithoutAdminAPI(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	t.Cleanup(server.Close)
+
+	_, err := newTestClient(t, server.URL).ListTenants(context.Background())
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+		t.Errorf("expected a not found API error, got %v", err)
+	}
+}
@@ -0,0 +1,60 @@
+package mimir
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net/http"
+	"sync/atomic"
+
+	"github.com/go-logr/logr"
+	"sigs.k8s.io/controller-runtime/pkg/certwatcher"
+)
+
+// watchClientCertificate makes transport present the client certificate at certPath and keyPath,
+// reloading it whenever the files change, e.g. when a mounted Secret is rotated.
+// Idle connections are closed after a reload so new connections use the new certificate,
+// and onReload is called if set.
+// Returns a function stopping the watch, or an error if the certificate cannot be loaded.
+func watchClientCertificate(
+	logger logr.Logger,
+	transport *http.Transport,
+	certPath, keyPath string,
+	onReload func(),
+) (context.CancelFunc, error) {
+	if certPath == "" || keyPath == "" {
+		return nil, errors.New("client certificate requires both a certificate and a key path")
+	}
+
+	watcher, err := certwatcher.New(certPath, keyPath)
+	if err != nil {
+		return nil, err
+	}
+
+	// The callback is invoked once with the initial certificate while registering
+	var registered atomic.Bool
+	watcher.RegisterCallback(func(tls.Certificate) {
+		if !registered.Load() {
+			return
+		}
+		logger.Info("Reloaded TLS client certificate", "cert", certPath)
+		transport.CloseIdleConnections()
+		if onReload != nil {
+			onReload()
+		}
+	})
+	registered.Store(true)
+
+	transport.TLSClientConfig.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+		return watcher.GetCertificate(nil)
+	}
+
+	// The watch outlives the request that created the client, it is stopped by Client.Close
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		if err := watcher.Start(ctx); err != nil {
+			logger.Error(err, "Failed to watch TLS client certificate", "cert", certPath)
+		}
+	}()
+	return cancel, nil
+}
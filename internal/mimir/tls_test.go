@@ -0,0 +1,161 @@
+package mimir
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	dskittls "github.com/grafana/dskit/crypto/tls"
+)
+
+// writeClientCertificate writes a self-signed client certificate with the given common name.
+func writeClientCertificate(t *testing.T, certPath, keyPath, commonName string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Minute),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("creating certificate: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("marshalling key: %v", err)
+	}
+	if err := os.WriteFile(certPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); err != nil {
+		t.Fatalf("writing certificate: %v", err)
+	}
+	if err := os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600); err != nil {
+		t.Fatalf("writing key: %v", err)
+	}
+}
+
+func TestClientCertificateReload(t *testing.T) {
+	var mu sync.Mutex
+	var commonNames []string
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		commonNames = append(commonNames, r.TLS.PeerCertificates[0].Subject.CommonName)
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+	server.StartTLS()
+	t.Cleanup(server.Close)
+
+	dir := t.TempDir()
+	caPath := filepath.Join(dir, "ca.crt")
+	certPath := filepath.Join(dir, "tls.crt")
+	keyPath := filepath.Join(dir, "tls.key")
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(caPath, caPEM, 0o600); err != nil {
+		t.Fatalf("writing CA: %v", err)
+	}
+	writeClientCertificate(t, certPath, keyPath, "first")
+
+	reloaded := make(chan struct{}, 1)
+	client, err := New(context.Background(), Config{
+		Address: server.URL,
+		TLS:     dskittls.ClientConfig{CAPath: caPath, CertPath: certPath, KeyPath: keyPath},
+		OnCertificateReload: func() {
+			select {
+			case reloaded <- struct{}{}:
+			default:
+			}
+		},
+	})
+	if err != nil {
+		t.Fatalf("creating client: %v", err)
+	}
+	t.Cleanup(client.Close)
+
+	if err := client.HealthCheck(context.Background()); err != nil {
+		t.Fatalf("HealthCheck: %v", err)
+	}
+
+	writeClientCertificate(t, certPath, keyPath, "second")
+	select {
+	case <-reloaded:
+	case <-time.After(15 * time.Second):
+		t.Fatal("rotated certificate was not reloaded")
+	}
+
+	if err := client.HealthCheck(context.Background()); err != nil {
+		t.Fatalf("HealthCheck: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(commonNames) != 2 || commonNames[0] != "first" || commonNames[1] != "second" {
+		t.Errorf("expected client certificates [first second], got %v", commonNames)
+	}
+}
+
+func TestClientCertificateErrors(t *testing.T) {
+	dir := t.TempDir()
+	for name, cfg := range map[string]dskittls.ClientConfig{
+		"missing key":         {CertPath: filepath.Join(dir, "tls.crt")},
+		"missing certificate": {CertPath: filepath.Join(dir, "tls.crt"), KeyPath: filepath.Join(dir, "tls.key")},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := New(context.Background(), Config{Address: "https://mimir.example.com", TLS: cfg}); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestServerCertificateVerification(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	ctx := context.Background()
+
+	for name, tt := range map[string]struct {
+		cfg     Config
+		wantErr bool
+	}{
+		"system roots":         {cfg: Config{}, wantErr: true},
+		"CA bundle":            {cfg: Config{CABundle: caPEM}},
+		"insecure skip verify": {cfg: Config{TLS: dskittls.ClientConfig{InsecureSkipVerify: true}}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			tt.cfg.Address = server.URL
+			client, err := New(ctx, tt.cfg)
+			if err != nil {
+				t.Fatalf("creating client: %v", err)
+			}
+			t.Cleanup(client.Close)
+			if err := client.HealthCheck(ctx); (err != nil) != tt.wantErr {
+				t.Errorf("HealthCheck() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	if _, err := New(ctx, Config{Address: server.URL, CABundle: []byte("not a certificate")}); err == nil {
+		t.Error("expected an error for a CA bundle without certificate")
+	}
+}
@@ -0,0 +1,144 @@
+package mimir
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// tokenExchangeGrantType is the OAuth 2.0 token exchange grant type (RFC 8693)
+	tokenExchangeGrantType = "urn:ietf:params:oauth:grant-type:token-exchange"
+	// jwtTokenType identifies the projected service account token as subject token
+	jwtTokenType = "urn:ietf:params:oauth:token-type:jwt"
+	// accessTokenType is the requested token type
+	accessTokenType = "urn:ietf:params:oauth:token-type:access_token"
+
+	// tokenRefreshMargin is how long before expiry an exchanged token is refreshed
+	tokenRefreshMargin = time.Minute
+	// defaultTokenLifetime is used when the token endpoint does not report an expiry
+	defaultTokenLifetime = 5 * time.Minute
+)
+
+// TokenExchangeConfig configures authentication by exchanging the projected service
+// account token of the controller for a gateway token (OAuth 2.0 token exchange, RFC 8693).
+type TokenExchangeConfig struct {
+	// TokenURL is the endpoint of the token exchange
+	TokenURL string
+	// Audience is the audience requested for the exchanged token, omitted if empty
+	Audience string
+	// SubjectTokenPath is the path of the projected service account token
+	SubjectTokenPath string
+}
+
+// tokenExchanger exchanges the service account token and caches the exchanged token
+// until shortly before it expires. The subject token is re-read on every exchange,
+// as the kubelet rotates projected tokens.
+type tokenExchanger struct {
+	cfg    TokenExchangeConfig
+	client *http.Client
+	now    func() time.Time
+
+	mu     sync.Mutex
+	token  string
+	expiry time.Time
+}
+
+// tokenResponse is the successful response of a token exchange
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+func newTokenExchanger(cfg TokenExchangeConfig, client *http.Client) (*tokenExchanger, error) {
+	if cfg.TokenURL == "" {
+		return nil, errors.New("token exchange requires a token URL")
+	}
+	if cfg.SubjectTokenPath == "" {
+		return nil, errors.New("token exchange requires a service account token path")
+	}
+	return &tokenExchanger{cfg: cfg, client: client, now: time.Now}, nil
+}
+
+// Token returns a valid exchanged token, performing a new exchange if the cached token
+// is missing or about to expire.
+func (t *tokenExchanger) Token(ctx context.Context) (string, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.token != "" && t.now().Add(tokenRefreshMargin).Before(t.expiry) {
+		return t.token, nil
+	}
+
+	token, lifetime, err := t.exchange(ctx)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrTokenExchange, err)
+	}
+	t.token = token
+	t.expiry = t.now().Add(lifetime)
+	return t.token, nil
+}
+
+// exchange performs a single token exchange.
+// Returns the exchanged token and its lifetime.
+func (t *tokenExchanger) exchange(ctx context.Context) (string, time.Duration, error) {
+	subjectToken, err := os.ReadFile(t.cfg.SubjectTokenPath)
+	if err != nil {
+		return "", 0, fmt.Errorf("reading service account token: %w", err)
+	}
+
+	form := url.Values{
+		"grant_type":           {tokenExchangeGrantType},
+		"subject_token":        {strings.TrimSpace(string(subjectToken))},
+		"subject_token_type":   {jwtTokenType},
+		"requested_token_type": {accessTokenType},
+	}
+	if t.cfg.Audience != "" {
+		form.Set("audience", t.cfg.Audience)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.cfg.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", 0, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", UserAgent())
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return "", 0, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return "", 0, fmt.Errorf("reading body: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", 0, fmt.Errorf("server returned HTTP status: %s, body: %q", resp.Status, string(body))
+	}
+
+	var token tokenResponse
+	if err := json.Unmarshal(body, &token); err != nil {
+		return "", 0, fmt.Errorf("decoding token response: %w", err)
+	}
+	if token.AccessToken == "" {
+		return "", 0, errors.New("token response contains no access_token")
+	}
+
+	lifetime := defaultTokenLifetime
+	if token.ExpiresIn > 0 {
+		lifetime = time.Duration(token.ExpiresIn) * time.Second
+	}
+	return token.AccessToken, lifetime, nil
+}
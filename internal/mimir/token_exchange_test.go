@@ -0,0 +1,147 @@
+package mimir
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// tokenServer returns a token exchange endpoint issuing numbered tokens and a pointer to
+// the number of exchanges performed.
+func tokenServer(t *testing.T, subjectToken string) (*httptest.Server, *int) {
+	t.Helper()
+	exchanges := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil ||
+			r.PostForm.Get("grant_type") != tokenExchangeGrantType ||
+			r.PostForm.Get("subject_token_type") != jwtTokenType ||
+			r.PostForm.Get("subject_token") != subjectToken ||
+			r.PostForm.Get("audience") != "mimir" {
+			http.Error(w, `{"error":"invalid_request"}`, http.StatusBadRequest)
+			return
+		}
+		exchanges++
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"access_token":"token-` + strconv.Itoa(exchanges) +
+			`","token_type":"Bearer","expires_in":600}`))
+	}))
+	t.Cleanup(server.Close)
+	return server, &exchanges
+}
+
+func writeSubjectToken(t *testing.T, token string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(path, []byte(token+"\n"), 0o600); err != nil {
+		t.Fatalf("writing token: %v", err)
+	}
+	return path
+}
+
+func TestTokenExchangeRefresh(t *testing.T) {
+	server, exchanges := tokenServer(t, "sa-token")
+	exchanger, err := newTokenExchanger(TokenExchangeConfig{
+		TokenURL:         server.URL,
+		Audience:         "mimir",
+		SubjectTokenPath: writeSubjectToken(t, "sa-token"),
+	}, server.Client())
+	if err != nil {
+		t.Fatalf("newTokenExchanger: %v", err)
+	}
+	now := time.Now()
+	exchanger.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	for range 2 {
+		token, err := exchanger.Token(ctx)
+		if err != nil || token != "token-1" {
+			t.Fatalf("Token() = %q, %v, want cached token-1", token, err)
+		}
+	}
+
+	// Refreshed once the token is about to expire
+	now = now.Add(10*time.Minute - tokenRefreshMargin)
+	if token, err := exchanger.Token(ctx); err != nil || token != "token-2" {
+		t.Errorf("Token() = %q, %v, want refreshed token-2", token, err)
+	}
+	if *exchanges != 2 {
+		t.Errorf("expected 2 exchanges, got %d", *exchanges)
+	}
+}
+
+func TestTokenExchangeErrors(t *testing.T) {
+	server, _ := tokenServer(t, "sa-token")
+	tests := []struct {
+		name string
+		cfg  TokenExchangeConfig
+		want string
+	}{
+		{
+			name: "missing subject token",
+			cfg:  TokenExchangeConfig{TokenURL: server.URL, SubjectTokenPath: filepath.Join(t.TempDir(), "missing")},
+			want: "reading service account token",
+		},
+		{
+			name: "rejected exchange",
+			cfg:  TokenExchangeConfig{TokenURL: server.URL, SubjectTokenPath: writeSubjectToken(t, "other")},
+			want: "400 Bad Request",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exchanger, err := newTokenExchanger(tt.cfg, server.Client())
+			if err != nil {
+				t.Fatalf("newTokenExchanger: %v", err)
+			}
+			_, err = exchanger.Token(context.Background())
+			if err == nil || !strings.Contains(err.Error(), "token exchange failed") ||
+				!strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected token exchange error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestClientUsesExchangedToken(t *testing.T) {
+	tokens, _ := tokenServer(t, "sa-token")
+	var authorization string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := New(context.Background(), Config{
+		Address: server.URL,
+		TokenExchange: &TokenExchangeConfig{
+			TokenURL:         tokens.URL,
+			Audience:         "mimir",
+			SubjectTokenPath: writeSubjectToken(t, "sa-token"),
+		},
+	})
+	if err != nil {
+		t.Fatalf("creating client: %v", err)
+	}
+	if err := client.HealthCheck(context.Background()); err != nil {
+		t.Fatalf("HealthCheck: %v", err)
+	}
+	if authorization != "Bearer token-1" {
+		t.Errorf("Authorization = %q, want exchanged bearer token", authorization)
+	}
+
+	_, err = New(context.Background(), Config{
+		Address:       server.URL,
+		AuthToken:     "static",
+		TokenExchange: &TokenExchangeConfig{TokenURL: tokens.URL, SubjectTokenPath: "/token"},
+	})
+	if err == nil {
+		t.Error("expected an error combining token exchange with an auth token")
+	}
+}
@@ -0,0 +1,51 @@
+package mimir
+
+import (
+	"crypto/tls"
+	"net/http"
+	"time"
+)
+
+const (
+	// DefaultMaxIdleConnsPerHost is the default number of idle connections kept per Mimir host,
+	// so concurrent pushes reuse connections instead of opening a new one per request
+	DefaultMaxIdleConnsPerHost = 32
+	// DefaultIdleConnTimeout is the default time an idle connection is kept open
+	DefaultIdleConnTimeout = 90 * time.Second
+	// minMaxIdleConns is the lower bound of the idle connections kept across all hosts
+	minMaxIdleConns = 100
+)
+
+// TransportConfig tunes the connection pooling of the HTTP transport of a Client.
+type TransportConfig struct {
+	// MaxIdleConnsPerHost is the number of idle connections kept per host,
+	// DefaultMaxIdleConnsPerHost if zero
+	MaxIdleConnsPerHost int
+	// IdleConnTimeout is the time an idle connection is kept open, DefaultIdleConnTimeout if zero
+	IdleConnTimeout time.Duration
+	// ForceAttemptHTTP2 negotiates HTTP/2 with TLS endpoints, which multiplexes concurrent
+	// requests over one connection
+	ForceAttemptHTTP2 bool
+	// MaxConcurrentRequests bounds the requests in flight of the client, granted round-robin
+	// across the waiting tenants so that large tenants do not starve small ones; unlimited if zero
+	MaxConcurrentRequests int
+}
+
+// newTransport returns the HTTP transport of a Client based on http.DefaultTransport, tuned
+// by cfg and using tlsConfig for TLS connections if set.
+func newTransport(cfg TransportConfig, tlsConfig *tls.Config) *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	transport.ForceAttemptHTTP2 = cfg.ForceAttemptHTTP2
+
+	transport.MaxIdleConnsPerHost = cfg.MaxIdleConnsPerHost
+	if transport.MaxIdleConnsPerHost <= 0 {
+		transport.MaxIdleConnsPerHost = DefaultMaxIdleConnsPerHost
+	}
+	transport.MaxIdleConns = max(minMaxIdleConns, transport.MaxIdleConnsPerHost)
+	transport.IdleConnTimeout = cfg.IdleConnTimeout
+	if transport.IdleConnTimeout <= 0 {
+		transport.IdleConnTimeout = DefaultIdleConnTimeout
+	}
+	return transport
+}
@@ -0,0 +1,63 @@
+package mimir
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/prometheus/model/rulefmt"
+)
+
+func TestNewTransport(t *testing.T) {
+	defaults := newTransport(TransportConfig{}, nil)
+	if defaults.MaxIdleConnsPerHost != DefaultMaxIdleConnsPerHost ||
+		defaults.IdleConnTimeout != DefaultIdleConnTimeout || defaults.ForceAttemptHTTP2 {
+		t.Errorf("expected defaults, got MaxIdleConnsPerHost=%d IdleConnTimeout=%v ForceAttemptHTTP2=%v",
+			defaults.MaxIdleConnsPerHost, defaults.IdleConnTimeout, defaults.ForceAttemptHTTP2)
+	}
+	if defaults.Proxy == nil {
+		t.Error("expected the proxy of the environment to be used")
+	}
+
+	tuned := newTransport(TransportConfig{
+		MaxIdleConnsPerHost: 500,
+		IdleConnTimeout:     time.Minute,
+		ForceAttemptHTTP2:   true,
+	}, nil)
+	if tuned.MaxIdleConnsPerHost != 500 || tuned.MaxIdleConns != 500 ||
+		tuned.IdleConnTimeout != time.Minute || !tuned.ForceAttemptHTTP2 {
+		t.Errorf("expected tuned transport, got MaxIdleConnsPerHost=%d MaxIdleConns=%d IdleConnTimeout=%v "+
+			"ForceAttemptHTTP2=%v", tuned.MaxIdleConnsPerHost, tuned.MaxIdleConns, tuned.IdleConnTimeout,
+			tuned.ForceAttemptHTTP2)
+	}
+}
+
+func TestClientReusesConnections(t *testing.T) {
+	var connections atomic.Int32
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	server.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			connections.Add(1)
+		}
+	}
+	server.Start()
+	t.Cleanup(server.Close)
+
+	client := newTestClient(t, server.URL)
+	ctx := context.Background()
+	for range 5 {
+		if err := client.CreateRuleGroup(ctx, "ns", rulefmt.RuleGroup{Name: "group"}, "tenant"); err != nil {
+			t.Fatalf("CreateRuleGroup: %v", err)
+		}
+	}
+
+	if got := connections.Load(); got != 1 {
+		t.Errorf("expected sequential pushes to reuse one connection, got %d connections", got)
+	}
+}
@@ -0,0 +1,183 @@
+// Package notifications reports failed Alertmanager notifications as events on MimirAlertTenants.
+package notifications
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	openawarenessv1beta1 "github.com/syndlex/openawareness-controller/api/openawareness/v1beta1"
+	"github.com/syndlex/openawareness-controller/internal/clients"
+	"github.com/syndlex/openawareness-controller/internal/controller/utils"
+	"github.com/syndlex/openawareness-controller/internal/events"
+	"github.com/syndlex/openawareness-controller/internal/logging"
+)
+
+// DefaultThreshold is the default number of failed notifications of an integration
+// between two polls that is reported
+const DefaultThreshold = 1
+
+// Poller periodically reads the failed notification counters of the Alertmanager of every
+// Mimir tenant with a MimirAlertTenant and emits a Warning event on the MimirAlertTenant when
+// an integration failed at least Threshold times since the previous poll.
+//
+// The first poll of a tenant only records the counters, so failures from before the
+// operator started are not reported. Counter resets, e.g. after an Alertmanager restart,
+// count all failures since the reset.
+type Poller struct {
+	Client       client.Client
+	RulerClients clients.RulerClientCacheInterface
+	Recorder     record.EventRecorder
+	// Interval is the time between two polls
+	Interval time.Duration
+	// Threshold is the minimum number of failed notifications of an integration since the
+	// previous poll that is reported. Defaults to DefaultThreshold if not positive.
+	Threshold float64
+
+	mu sync.Mutex
+	// counters holds the failed notifications by integration of the previous poll, keyed by
+	// client name and tenant ID
+	counters map[tenantKey]map[string]float64
+}
+
+// tenantKey identifies a Mimir tenant of a ClientConfig
+type tenantKey struct {
+	clientName string
+	tenantID   string
+}
+
+// Ensure Poller can be added to a controller manager
+var _ manager.Runnable = (*Poller)(nil)
+
+// Start polls every Interval until the context is cancelled.
+// Errors of a single poll are logged and do not stop the poller.
+func (p *Poller) Start(ctx context.Context) error {
+	logger := log.FromContext(ctx).WithName("notifications")
+	logger.Info("Starting Alertmanager notification failure polling",
+		"interval", p.Interval,
+		"threshold", p.threshold())
+
+	ticker := time.NewTicker(p.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := p.Poll(log.IntoContext(ctx, logger)); err != nil {
+				logger.Error(err, "Notification failure poll failed")
+			}
+		}
+	}
+}
+
+// Poll reads the failed notification counters of all tenants with a MimirAlertTenant once
+// and emits events for the integrations that failed since the previous poll.
+// Returns an error if the Kubernetes resources cannot be listed; per-tenant errors are
+// logged and skipped.
+func (p *Poller) Poll(ctx context.Context) error {
+	logger := log.FromContext(ctx)
+
+	clientConfigs := &openawarenessv1beta1.ClientConfigList{}
+	if err := p.Client.List(ctx, clientConfigs); err != nil {
+		return fmt.Errorf("listing ClientConfigs: %w", err)
+	}
+	mappings, err := utils.ListTenantMappings(ctx, p.Client)
+	if err != nil {
+		return err
+	}
+	alertTenants := &openawarenessv1beta1.MimirAlertTenantList{}
+	if err := p.Client.List(ctx, alertTenants); err != nil {
+		return fmt.Errorf("listing MimirAlertTenants: %w", err)
+	}
+
+	owners := map[tenantKey][]*openawarenessv1beta1.MimirAlertTenant{}
+	configs := map[tenantKey]*openawarenessv1beta1.ClientConfig{}
+	for i := range alertTenants.Items {
+		tenant := &alertTenants.Items[i]
+		if utils.IsPaused(tenant) || !tenant.DeletionTimestamp.IsZero() {
+			continue
+		}
+		clientConfig := utils.ClientConfigFor(tenant, clientConfigs.Items, mappings)
+		if clientConfig == nil || clientConfig.Spec.Type != openawarenessv1beta1.Mimir || utils.IsPaused(clientConfig) {
+			continue
+		}
+		key := tenantKey{clientName: clientConfig.Name, tenantID: utils.GetTenantID(tenant, clientConfig)}
+		owners[key] = append(owners[key], tenant)
+		configs[key] = clientConfig
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.counters == nil {
+		p.counters = map[tenantKey]map[string]float64{}
+	}
+	// Tenants without MimirAlertTenant are forgotten and start over with a baseline
+	maps.DeleteFunc(p.counters, func(key tenantKey, _ map[string]float64) bool {
+		_, ok := owners[key]
+		return !ok
+	})
+
+	for key, tenants := range owners {
+		mimirClient, err := p.RulerClients.GetOrCreateMimirClient(ctx, configs[key])
+		if err != nil {
+			logger.Error(err, "Skipping tenant, unable to get client", logging.KeyClient, key.clientName)
+			continue
+		}
+		current, err := mimirClient.GetNotificationFailures(ctx, key.tenantID)
+		if err != nil {
+			logger.Error(err, "Failed to read notification failures",
+				logging.KeyClient, key.clientName,
+				logging.KeyTenant, key.tenantID)
+			continue
+		}
+
+		previous, seen := p.counters[key]
+		p.counters[key] = current
+		if !seen {
+			continue
+		}
+		for _, integration := range slices.Sorted(maps.Keys(current)) {
+			failed := increase(previous[integration], current[integration])
+			if failed < p.threshold() {
+				continue
+			}
+			for _, tenant := range tenants {
+				events.Eventf(p.Recorder, tenant, events.NotificationsFailed,
+					"%.0f notifications via %s failed in Mimir tenant %s since the last check, "+
+						"check the receiver configuration", failed, integration, key.tenantID)
+			}
+			logger.Info("Alertmanager notifications failed",
+				logging.KeyClient, key.clientName,
+				logging.KeyTenant, key.tenantID,
+				"integration", integration,
+				"failed", failed)
+		}
+	}
+	return nil
+}
+
+// threshold returns the configured threshold or DefaultThreshold.
+func (p *Poller) threshold() float64 {
+	if p.Threshold > 0 {
+		return p.Threshold
+	}
+	return DefaultThreshold
+}
+
+// increase returns the increase of a counter between two polls, taking counter resets into account.
+func increase(previous, current float64) float64 {
+	if current < previous {
+		return current
+	}
+	return current - previous
+}
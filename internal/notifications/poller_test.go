@@ -0,0 +1,80 @@
+package notifications
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	openawarenessv1beta1 "github.com/syndlex/openawareness-controller/api/openawareness/v1beta1"
+	"github.com/syndlex/openawareness-controller/internal/clients"
+	"github.com/syndlex/openawareness-controller/internal/controller/utils"
+)
+
+func TestPoll(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := openawarenessv1beta1.AddToScheme(scheme); err != nil {
+		t.Fatalf("adding scheme: %v", err)
+	}
+	clientConfig := &openawarenessv1beta1.ClientConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "mimir", Namespace: "team"},
+		Spec:       openawarenessv1beta1.ClientConfigSpec{Type: openawarenessv1beta1.Mimir},
+	}
+	tenant := &openawarenessv1beta1.MimirAlertTenant{
+		ObjectMeta: metav1.ObjectMeta{Name: "alerts", Namespace: "team", Annotations: map[string]string{
+			utils.ClientNameAnnotation:  "mimir",
+			utils.MimirTenantAnnotation: "team-a",
+		}},
+	}
+	paused := &openawarenessv1beta1.MimirAlertTenant{
+		ObjectMeta: metav1.ObjectMeta{Name: "paused", Namespace: "team", Annotations: map[string]string{
+			utils.ClientNameAnnotation:  "mimir",
+			utils.MimirTenantAnnotation: "team-b",
+			utils.PausedAnnotation:      "true",
+		}},
+	}
+	mimirClient := clients.NewMockAwarenessClient()
+	cache := clients.NewMockRulerClientCache()
+	cache.SetClient("mimir", mimirClient)
+	recorder := record.NewFakeRecorder(10)
+	poller := &Poller{
+		Client:       fake.NewClientBuilder().WithScheme(scheme).WithObjects(clientConfig, tenant, paused).Build(),
+		RulerClients: cache,
+		Recorder:     recorder,
+		Threshold:    2,
+	}
+	ctx := context.Background()
+
+	poll := func(failures map[string]float64) []string {
+		t.Helper()
+		mimirClient.SetNotificationFailures("team-a", failures)
+		mimirClient.SetNotificationFailures("team-b", failures)
+		if err := poller.Poll(ctx); err != nil {
+			t.Fatalf("Poll() error = %v", err)
+		}
+		var events []string
+		for len(recorder.Events) > 0 {
+			events = append(events, <-recorder.Events)
+		}
+		return events
+	}
+
+	if events := poll(map[string]float64{"slack": 10}); len(events) != 0 {
+		t.Errorf("expected the first poll to record a baseline only, got %v", events)
+	}
+	if events := poll(map[string]float64{"slack": 11, "email": 1}); len(events) != 0 {
+		t.Errorf("expected failures below the threshold not to be reported, got %v", events)
+	}
+	events := poll(map[string]float64{"slack": 14, "email": 1})
+	want := "Warning NotificationsFailed 3 notifications via slack failed in Mimir tenant team-a since the last check, " +
+		"check the receiver configuration"
+	if len(events) != 1 || events[0] != want {
+		t.Errorf("expected one event for the slack failures, got %v", events)
+	}
+	if events := poll(map[string]float64{"slack": 2, "email": 1}); len(events) != 1 {
+		t.Errorf("expected the failures since a counter reset to be reported, got %v", events)
+	}
+}
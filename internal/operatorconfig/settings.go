@@ -0,0 +1,229 @@
+// Package operatorconfig applies the settings of the OperatorConfig to the controllers at runtime,
+// overriding the settings given as command-line flags.
+package operatorconfig
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	openawarenessv1beta1 "github.com/syndlex/openawareness-controller/api/openawareness/v1beta1"
+	"github.com/syndlex/openawareness-controller/internal/policy"
+)
+
+// modeDisabled is the OperatorConfig value of policy.ModeDisabled
+const modeDisabled = "disabled"
+
+// DefaultRetry is the backoff of failed reconciliations of controller-runtime
+var DefaultRetry = Retry{BaseDelay: 5 * time.Millisecond, MaxDelay: 1000 * time.Second}
+
+// Settings are the controller settings an OperatorConfig overrides.
+type Settings struct {
+	// SyncTimeout is the default timeout of the Mimir API operations of a reconciliation
+	SyncTimeout time.Duration
+	// ResyncInterval is the interval in which synced resources are pushed again, 0 disables it
+	ResyncInterval time.Duration
+	// Retry is the backoff of failed reconciliations
+	Retry Retry
+	// AlertmanagerPolicy checks rendered Alertmanager configurations
+	AlertmanagerPolicy *policy.AlertmanagerPolicy
+	// RulePolicy checks the alerting rules of PrometheusRules
+	RulePolicy *policy.RulePolicy
+	// RuleSelector selects the synced PrometheusRules, nil selects all
+	RuleSelector labels.Selector
+}
+
+// Retry is an exponential backoff.
+type Retry struct {
+	// BaseDelay is the delay of the first retry, doubled for every further failure
+	BaseDelay time.Duration
+	// MaxDelay is the longest delay
+	MaxDelay time.Duration
+}
+
+// Delay returns the delay of the retry after failures previous failures.
+func (r Retry) Delay(failures int) time.Duration {
+	delay := float64(r.BaseDelay) * math.Pow(2, float64(failures))
+	if delay > float64(r.MaxDelay) {
+		return r.MaxDelay
+	}
+	return time.Duration(delay)
+}
+
+// Merge returns defaults with the fields set in spec overriding them. The policies of defaults
+// are copied, not modified.
+// Returns an error if spec sets a negative duration, an unknown policy mode or an invalid selector.
+func Merge(defaults Settings, spec openawarenessv1beta1.OperatorConfigSpec) (Settings, error) {
+	settings := defaults
+	var errs []error
+	duration := func(field string, value *metav1.Duration, target *time.Duration) {
+		if value == nil {
+			return
+		}
+		if value.Duration < 0 {
+			errs = append(errs, fmt.Errorf("%s: must not be negative", field))
+			return
+		}
+		*target = value.Duration
+	}
+	duration("syncTimeout", spec.SyncTimeout, &settings.SyncTimeout)
+	duration("resyncInterval", spec.ResyncInterval, &settings.ResyncInterval)
+
+	if spec.Retry != nil {
+		duration("retry.baseDelay", spec.Retry.BaseDelay, &settings.Retry.BaseDelay)
+		duration("retry.maxDelay", spec.Retry.MaxDelay, &settings.Retry.MaxDelay)
+		if spec.Retry.BaseDelay != nil && spec.Retry.MaxDelay != nil &&
+			spec.Retry.MaxDelay.Duration < spec.Retry.BaseDelay.Duration {
+			errs = append(errs, errors.New("retry.maxDelay: must not be shorter than retry.baseDelay"))
+		}
+	}
+
+	if spec.AlertmanagerPolicy != nil {
+		amPolicy := policy.AlertmanagerPolicy{}
+		if defaults.AlertmanagerPolicy != nil {
+			amPolicy = *defaults.AlertmanagerPolicy
+		}
+		if spec.AlertmanagerPolicy.Mode != "" {
+			mode, err := parseMode(spec.AlertmanagerPolicy.Mode)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("alertmanagerPolicy.mode: %w", err))
+			}
+			amPolicy.Mode = mode
+		}
+		duration("alertmanagerPolicy.minRepeatInterval", spec.AlertmanagerPolicy.MinRepeatInterval,
+			&amPolicy.MinRepeatInterval)
+		settings.AlertmanagerPolicy = &amPolicy
+	}
+
+	if spec.RulePolicy != nil {
+		rulePolicy := policy.RulePolicy{}
+		if defaults.RulePolicy != nil {
+			rulePolicy = *defaults.RulePolicy
+		}
+		if spec.RulePolicy.Mode != "" {
+			mode, err := parseMode(spec.RulePolicy.Mode)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("rulePolicy.mode: %w", err))
+			}
+			rulePolicy.Mode = mode
+		}
+		if spec.RulePolicy.RequiredLabels != nil {
+			rulePolicy.RequiredLabels = spec.RulePolicy.RequiredLabels
+		}
+		if spec.RulePolicy.RequiredAnnotations != nil {
+			rulePolicy.RequiredAnnotations = spec.RulePolicy.RequiredAnnotations
+		}
+		settings.RulePolicy = &rulePolicy
+	}
+
+	if spec.RuleSelector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(spec.RuleSelector)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("ruleSelector: %w", err))
+		}
+		settings.RuleSelector = selector
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return defaults, err
+	}
+	return settings, nil
+}
+
+// parseMode parses the policy mode of an OperatorConfig.
+func parseMode(value string) (policy.Mode, error) {
+	if value == modeDisabled {
+		return policy.ModeDisabled, nil
+	}
+	return policy.ParseMode(value)
+}
+
+// SelectsRule reports whether a PrometheusRule with the labels is synced.
+func (s Settings) SelectsRule(ruleLabels map[string]string) bool {
+	return s.RuleSelector == nil || s.RuleSelector.Matches(labels.Set(ruleLabels))
+}
+
+// Source reads the OperatorConfig named openawarenessv1beta1.OperatorConfigName. A nil Source
+// applies no OperatorConfig.
+type Source struct {
+	// Reader reads the OperatorConfig, usually from the cache of the manager
+	Reader client.Reader
+}
+
+// Apply returns defaults overridden by the OperatorConfig, see Merge. The defaults are
+// returned unchanged if the OperatorConfig does not exist, cannot be read or is invalid.
+// Invalid OperatorConfigs are reported in their status by the OperatorConfig controller.
+func (s *Source) Apply(ctx context.Context, defaults Settings) Settings {
+	if s == nil {
+		return defaults
+	}
+	config := &openawarenessv1beta1.OperatorConfig{}
+	err := s.Reader.Get(ctx, types.NamespacedName{Name: openawarenessv1beta1.OperatorConfigName}, config)
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			log.FromContext(ctx).Error(err, "Failed to read the OperatorConfig, using the default settings")
+		}
+		return defaults
+	}
+	settings, err := Merge(defaults, config.Spec)
+	if err != nil {
+		return defaults
+	}
+	return settings
+}
+
+// RateLimiter returns the rate limiter of the failed reconciliations of a controller. Retries
+// are delayed by the Retry of the OperatorConfig, defaults if it sets none, read on every
+// failure so changes apply to the next retry. Like the rate limiter of controller-runtime,
+// retries of all resources are also limited to 10 per second with bursts of 100.
+func (s *Source) RateLimiter(defaults Retry) workqueue.TypedRateLimiter[reconcile.Request] {
+	return workqueue.NewTypedMaxOfRateLimiter(
+		&retryRateLimiter{source: s, defaults: defaults, failures: map[reconcile.Request]int{}},
+		&workqueue.TypedBucketRateLimiter[reconcile.Request]{Limiter: rate.NewLimiter(rate.Limit(10), 100)},
+	)
+}
+
+// retryRateLimiter delays the retries of a resource by the current Retry of the OperatorConfig.
+type retryRateLimiter struct {
+	source   *Source
+	defaults Retry
+
+	mu       sync.Mutex
+	failures map[reconcile.Request]int
+}
+
+// When implements workqueue.TypedRateLimiter.
+func (l *retryRateLimiter) When(item reconcile.Request) time.Duration {
+	l.mu.Lock()
+	failures := l.failures[item]
+	l.failures[item] = failures + 1
+	l.mu.Unlock()
+	return l.source.Apply(context.Background(), Settings{Retry: l.defaults}).Retry.Delay(failures)
+}
+
+// Forget implements workqueue.TypedRateLimiter.
+func (l *retryRateLimiter) Forget(item reconcile.Request) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.failures, item)
+}
+
+// NumRequeues implements workqueue.TypedRateLimiter.
+func (l *retryRateLimiter) NumRequeues(item reconcile.Request) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.failures[item]
+}
@@ -0,0 +1,165 @@
+package operatorconfig
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	openawarenessv1beta1 "github.com/syndlex/openawareness-controller/api/openawareness/v1beta1"
+	"github.com/syndlex/openawareness-controller/internal/policy"
+)
+
+func TestMerge(t *testing.T) {
+	amPolicy := &policy.AlertmanagerPolicy{Mode: policy.ModeWarn, MinRepeatInterval: time.Hour}
+	rulePolicy := &policy.RulePolicy{Mode: policy.ModeBlock, RequiredLabels: []string{"severity"}}
+	defaults := Settings{
+		SyncTimeout:        time.Minute,
+		Retry:              DefaultRetry,
+		AlertmanagerPolicy: amPolicy,
+		RulePolicy:         rulePolicy,
+	}
+
+	settings, err := Merge(defaults, openawarenessv1beta1.OperatorConfigSpec{})
+	if err != nil {
+		t.Fatalf("Merge() of an empty spec returned error: %v", err)
+	}
+	if settings.SyncTimeout != time.Minute || settings.AlertmanagerPolicy != amPolicy || settings.RuleSelector != nil {
+		t.Errorf("expected an empty spec to keep the defaults, got %+v", settings)
+	}
+
+	settings, err = Merge(defaults, openawarenessv1beta1.OperatorConfigSpec{
+		SyncTimeout:    &metav1.Duration{Duration: 0},
+		ResyncInterval: &metav1.Duration{Duration: time.Hour},
+		Retry:          &openawarenessv1beta1.RetrySpec{MaxDelay: &metav1.Duration{Duration: time.Minute}},
+		AlertmanagerPolicy: &openawarenessv1beta1.AlertmanagerPolicySpec{
+			Mode: "disabled",
+		},
+		RulePolicy: &openawarenessv1beta1.RulePolicySpec{
+			RequiredLabels:      []string{},
+			RequiredAnnotations: []string{"summary"},
+		},
+		RuleSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "a"}},
+	})
+	if err != nil {
+		t.Fatalf("Merge() returned error: %v", err)
+	}
+	if settings.SyncTimeout != 0 || settings.ResyncInterval != time.Hour {
+		t.Errorf("unexpected durations: %+v", settings)
+	}
+	if settings.Retry.BaseDelay != DefaultRetry.BaseDelay || settings.Retry.MaxDelay != time.Minute {
+		t.Errorf("expected the max delay to be overridden, got %+v", settings.Retry)
+	}
+	if settings.AlertmanagerPolicy.Enabled() || settings.AlertmanagerPolicy.MinRepeatInterval != time.Hour {
+		t.Errorf("expected a disabled Alertmanager policy keeping its interval, got %+v", settings.AlertmanagerPolicy)
+	}
+	if amPolicy.Mode != policy.ModeWarn {
+		t.Error("expected the default Alertmanager policy to be left unchanged")
+	}
+	if !settings.RulePolicy.Blocking() || len(settings.RulePolicy.RequiredLabels) != 0 ||
+		len(settings.RulePolicy.RequiredAnnotations) != 1 {
+		t.Errorf("unexpected rule policy: %+v", settings.RulePolicy)
+	}
+	if !settings.SelectsRule(map[string]string{"team": "a"}) || settings.SelectsRule(map[string]string{"team": "b"}) {
+		t.Error("expected the rule selector to select team a only")
+	}
+
+	for name, spec := range map[string]openawarenessv1beta1.OperatorConfigSpec{
+		"negative duration": {SyncTimeout: &metav1.Duration{Duration: -time.Second}},
+		"max below base delay": {Retry: &openawarenessv1beta1.RetrySpec{
+			BaseDelay: &metav1.Duration{Duration: time.Minute},
+			MaxDelay:  &metav1.Duration{Duration: time.Second},
+		}},
+		"unknown mode": {RulePolicy: &openawarenessv1beta1.RulePolicySpec{Mode: "audit"}},
+		"invalid selector": {RuleSelector: &metav1.LabelSelector{
+			MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "team", Operator: "Unknown"}},
+		}},
+	} {
+		settings, err := Merge(defaults, spec)
+		if err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+		if settings.SyncTimeout != time.Minute || settings.RulePolicy != rulePolicy {
+			t.Errorf("%s: expected the defaults on error, got %+v", name, settings)
+		}
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	retry := Retry{BaseDelay: time.Second, MaxDelay: 5 * time.Second}
+	for failures, want := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second} {
+		if got := retry.Delay(failures); got != want {
+			t.Errorf("Delay(%d) = %v, want %v", failures, got, want)
+		}
+	}
+	if got := retry.Delay(10000); got != 5*time.Second {
+		t.Errorf("expected large failure counts to be capped, got %v", got)
+	}
+}
+
+func TestSource(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := openawarenessv1beta1.AddToScheme(scheme); err != nil {
+		t.Fatalf("adding scheme: %v", err)
+	}
+	ctx := context.Background()
+	defaults := Settings{SyncTimeout: time.Minute, Retry: Retry{BaseDelay: time.Second, MaxDelay: time.Minute}}
+
+	if got := (*Source)(nil).Apply(ctx, defaults); got.SyncTimeout != time.Minute {
+		t.Errorf("expected a nil Source to keep the defaults, got %+v", got)
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).Build()
+	source := &Source{Reader: c}
+	if got := source.Apply(ctx, defaults); got.SyncTimeout != time.Minute {
+		t.Errorf("expected a missing OperatorConfig to keep the defaults, got %+v", got)
+	}
+
+	config := &openawarenessv1beta1.OperatorConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: openawarenessv1beta1.OperatorConfigName},
+		Spec: openawarenessv1beta1.OperatorConfigSpec{
+			SyncTimeout: &metav1.Duration{Duration: time.Second},
+			Retry:       &openawarenessv1beta1.RetrySpec{BaseDelay: &metav1.Duration{Duration: 10 * time.Second}},
+		},
+	}
+	if err := c.Create(ctx, config); err != nil {
+		t.Fatalf("creating OperatorConfig: %v", err)
+	}
+	if got := source.Apply(ctx, defaults); got.SyncTimeout != time.Second {
+		t.Errorf("expected the OperatorConfig to override the sync timeout, got %+v", got)
+	}
+
+	// The rate limiter reads the retry settings on every failure
+	limiter := source.RateLimiter(defaults.Retry)
+	item := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "team", Name: "rule"}}
+	if got := limiter.When(item); got != 10*time.Second {
+		t.Errorf("first retry delay = %v, want 10s", got)
+	}
+	config.Spec.Retry = nil
+	if err := c.Update(ctx, config); err != nil {
+		t.Fatalf("updating OperatorConfig: %v", err)
+	}
+	if got := limiter.When(item); got != 2*time.Second {
+		t.Errorf("second retry delay = %v, want 2s", got)
+	}
+	if got := limiter.NumRequeues(item); got != 2 {
+		t.Errorf("NumRequeues() = %d, want 2", got)
+	}
+	limiter.Forget(item)
+	if got := limiter.When(item); got != time.Second {
+		t.Errorf("retry delay after Forget = %v, want 1s", got)
+	}
+
+	// Invalid OperatorConfigs are ignored as a whole
+	config.Spec.RulePolicy = &openawarenessv1beta1.RulePolicySpec{Mode: "audit"}
+	if err := c.Update(ctx, config); err != nil {
+		t.Fatalf("updating OperatorConfig: %v", err)
+	}
+	if got := source.Apply(ctx, defaults); got.SyncTimeout != time.Minute {
+		t.Errorf("expected an invalid OperatorConfig to keep the defaults, got %+v", got)
+	}
+}
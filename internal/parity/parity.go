@@ -0,0 +1,138 @@
+// Package parity compares the series produced by rules evaluated both by an in-cluster Prometheus
+// and by the Mimir ruler, while PrometheusRules are migrated to Mimir.
+package parity
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/prometheus/model/rulefmt"
+
+	"github.com/syndlex/openawareness-controller/internal/mimir"
+)
+
+// queryTimeout bounds a single query against the in-cluster Prometheus
+const queryTimeout = 30 * time.Second
+
+// ErrNoPrometheus is returned when rules are dual-written but no Prometheus is configured to
+// compare them with
+var ErrNoPrometheus = errors.New("no Prometheus configured to compare dual-written rules with")
+
+// SeriesCounter counts the series a PromQL selector selects, in the given tenants for Mimir.
+type SeriesCounter interface {
+	CountSeries(ctx context.Context, query string, tenantIDs []string) (int64, error)
+}
+
+// Ensure the Mimir client counts series
+var _ SeriesCounter = (*mimir.Client)(nil)
+
+// PrometheusClient counts series through the query API of a Prometheus without tenants.
+type PrometheusClient struct {
+	address    string
+	httpClient *http.Client
+}
+
+// NewPrometheusClient returns a PrometheusClient querying the Prometheus at address, e.g.
+// http://prometheus-operated.monitoring:9090.
+// Returns an error if address is not an absolute http or https URL.
+func NewPrometheusClient(address string) (*PrometheusClient, error) {
+	parsed, err := url.Parse(address)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Prometheus address %q: %w", address, err)
+	}
+	if (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid Prometheus address %q: expected an http or https URL", address)
+	}
+	return &PrometheusClient{
+		address:    strings.TrimSuffix(address, "/"),
+		httpClient: &http.Client{Timeout: queryTimeout},
+	}, nil
+}
+
+// CountSeries executes count(query) as an instant query and returns the number of series the
+// query selects, zero if it selects none. The tenant IDs are ignored.
+func (c *PrometheusClient) CountSeries(ctx context.Context, query string, _ []string) (int64, error) {
+	endpoint := fmt.Sprintf("%s/api/v1/query?query=%s&time=%d",
+		c.address, url.QueryEscape("count("+query+")"), time.Now().Unix())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return 0, err
+	}
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = res.Body.Close() }()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return 0, err
+	}
+	if res.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("query failed with status %s: %s", res.Status, strings.TrimSpace(string(body)))
+	}
+	return mimir.ParseSeriesCount(body)
+}
+
+// Rule is the number of series a rule produces in Prometheus and in Mimir.
+type Rule struct {
+	Group string
+	// Name is the record or alert name of the rule
+	Name       string
+	Prometheus int64
+	Mimir      int64
+}
+
+// Matches reports whether the rule produces the same number of series in both.
+func (r Rule) Matches() bool {
+	return r.Prometheus == r.Mimir
+}
+
+// Selector returns the series a rule produces: the recorded metric of a recording rule, or the
+// ALERTS series of an alerting rule, which hold its pending and firing alerts.
+func Selector(rule rulefmt.Rule) string {
+	if rule.Record != "" {
+		return rule.Record
+	}
+	return fmt.Sprintf("ALERTS{alertname=%s}", strconv.Quote(rule.Alert))
+}
+
+// Compare counts the series produced by every rule of the groups in the Prometheus and in the
+// tenant of Mimir. Rules are compared in order of the groups.
+// Returns the rules counted so far and an error if counting fails in either.
+func Compare(
+	ctx context.Context,
+	prometheus SeriesCounter,
+	mimirCounter SeriesCounter,
+	tenantID string,
+	groups []rulefmt.RuleGroup,
+) ([]Rule, error) {
+	var rules []Rule
+	tenantIDs := []string{tenantID}
+	for _, group := range groups {
+		for _, groupRule := range group.Rules {
+			selector := Selector(groupRule)
+			inPrometheus, err := prometheus.CountSeries(ctx, selector, nil)
+			if err != nil {
+				return rules, fmt.Errorf("counting %s in Prometheus: %w", selector, err)
+			}
+			inMimir, err := mimirCounter.CountSeries(ctx, selector, tenantIDs)
+			if err != nil {
+				return rules, fmt.Errorf("counting %s in tenant %s: %w", selector, tenantID, err)
+			}
+			name := groupRule.Record
+			if name == "" {
+				name = groupRule.Alert
+			}
+			rules = append(rules, Rule{Group: group.Name, Name: name, Prometheus: inPrometheus, Mimir: inMimir})
+		}
+	}
+	return rules, nil
+}
@@ -0,0 +1,91 @@
+package parity
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/prometheus/prometheus/model/rulefmt"
+)
+
+// fakeCounter returns the counts of its selectors, failing for err
+type fakeCounter struct {
+	counts map[string]int64
+	err    error
+}
+
+func (f fakeCounter) CountSeries(_ context.Context, query string, _ []string) (int64, error) {
+	if f.err != nil {
+		return 0, f.err
+	}
+	return f.counts[query], nil
+}
+
+func TestPrometheusClientCountSeries(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/query" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		switch r.URL.Query().Get("query") {
+		case "count(job:up:sum)":
+			_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1,"2"]}]}}`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"status":"error","errorType":"bad_data","error":"parse error"}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := NewPrometheusClient(server.URL + "/")
+	if err != nil {
+		t.Fatalf("NewPrometheusClient() error = %v", err)
+	}
+	got, err := client.CountSeries(context.Background(), "job:up:sum", nil)
+	if err != nil {
+		t.Fatalf("CountSeries() error = %v", err)
+	}
+	if got != 2 {
+		t.Errorf("CountSeries() = %d, want 2", got)
+	}
+	if _, err := client.CountSeries(context.Background(), "up{", nil); err == nil {
+		t.Errorf("expected an error for a failing query")
+	}
+
+	for _, address := range []string{"prometheus:9090", "ftp://prometheus", "http://"} {
+		if _, err := NewPrometheusClient(address); err == nil {
+			t.Errorf("NewPrometheusClient(%q) expected an error", address)
+		}
+	}
+}
+
+func TestCompare(t *testing.T) {
+	groups := []rulefmt.RuleGroup{{Name: "node", Rules: []rulefmt.Rule{
+		{Record: "job:up:sum", Expr: "sum by (job) (up)"},
+		{Alert: "InstanceDown", Expr: "up == 0"},
+	}}}
+	prometheus := fakeCounter{counts: map[string]int64{"job:up:sum": 3, `ALERTS{alertname="InstanceDown"}`: 1}}
+	mimir := fakeCounter{counts: map[string]int64{"job:up:sum": 3}}
+
+	got, err := Compare(context.Background(), prometheus, mimir, "team-a", groups)
+	if err != nil {
+		t.Fatalf("Compare() error = %v", err)
+	}
+	want := []Rule{
+		{Group: "node", Name: "job:up:sum", Prometheus: 3, Mimir: 3},
+		{Group: "node", Name: "InstanceDown", Prometheus: 1, Mimir: 0},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Compare() = %+v, want %+v", got, want)
+	}
+	if !got[0].Matches() || got[1].Matches() {
+		t.Errorf("Matches() = %v, %v, want true, false", got[0].Matches(), got[1].Matches())
+	}
+
+	failure := errors.New("unavailable")
+	if _, err := Compare(context.Background(), prometheus, fakeCounter{err: failure}, "team-a", groups); !errors.Is(err, failure) {
+		t.Errorf("Compare() error = %v, want %v", err, failure)
+	}
+}
@@ -0,0 +1,123 @@
+package policy
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultMinRepeatInterval is the smallest repeat_interval allowed by default
+const DefaultMinRepeatInterval = time.Hour
+
+// severityLabel is the alert label expected in the routing tree
+const severityLabel = "severity"
+
+// severityMatcher matches matchers on the severity label, e.g. severity="critical"
+var severityMatcher = regexp.MustCompile(`^\s*"?` + severityLabel + `"?\s*(=|!=|=~|!~)`)
+
+// AlertmanagerPolicy checks rendered Alertmanager configurations against organizational rules:
+//   - the top-level route has a default receiver
+//   - no route sets a repeat_interval below MinRepeatInterval
+//   - alerts are routed by their severity label
+//
+// A nil AlertmanagerPolicy or one with ModeDisabled reports no violations.
+type AlertmanagerPolicy struct {
+	Mode Mode
+	// MinRepeatInterval is the smallest allowed repeat_interval, 0 disables the check
+	MinRepeatInterval time.Duration
+}
+
+// alertmanagerConfig is the part of an Alertmanager configuration checked by the policy
+type alertmanagerConfig struct {
+	Route *route `yaml:"route"`
+}
+
+// route is a node of the Alertmanager routing tree
+type route struct {
+	Receiver       string            `yaml:"receiver"`
+	RepeatInterval string            `yaml:"repeat_interval"`
+	Match          map[string]string `yaml:"match"`
+	MatchRE        map[string]string `yaml:"match_re"`
+	Matchers       []string          `yaml:"matchers"`
+	Routes         []route           `yaml:"routes"`
+}
+
+// Enabled reports whether the policy checks configurations.
+func (p *AlertmanagerPolicy) Enabled() bool {
+	return p != nil && p.Mode != ModeDisabled
+}
+
+// Blocking reports whether violations prevent the configuration from being synced.
+func (p *AlertmanagerPolicy) Blocking() bool {
+	return p.Enabled() && p.Mode == ModeBlock
+}
+
+// Check returns the policy violations of a rendered Alertmanager configuration.
+// Returns an error if the configuration cannot be parsed.
+func (p *AlertmanagerPolicy) Check(config string) ([]string, error) {
+	if !p.Enabled() {
+		return nil, nil
+	}
+
+	var parsed alertmanagerConfig
+	if err := yaml.Unmarshal([]byte(config), &parsed); err != nil {
+		return nil, fmt.Errorf("failed to parse Alertmanager configuration: %w", err)
+	}
+	if parsed.Route == nil {
+		return []string{"route: a top-level route is required"}, nil
+	}
+
+	var violations []string
+	if parsed.Route.Receiver == "" {
+		violations = append(violations, "route: a default receiver is required")
+	}
+	violations = append(violations, p.checkRepeatIntervals(parsed.Route, "route")...)
+	if !routesBySeverity(parsed.Route) {
+		violations = append(violations, "route: no route matches on the "+severityLabel+" label")
+	}
+	return violations, nil
+}
+
+// checkRepeatIntervals returns the repeat_interval violations of r and its child routes.
+// path identifies r in the routing tree, e.g. route.routes[1].
+func (p *AlertmanagerPolicy) checkRepeatIntervals(r *route, path string) []string {
+	var violations []string
+	if r.RepeatInterval != "" && p.MinRepeatInterval > 0 {
+		interval, err := model.ParseDuration(r.RepeatInterval)
+		switch {
+		case err != nil:
+			violations = append(violations, fmt.Sprintf("%s: invalid repeat_interval %q", path, r.RepeatInterval))
+		case time.Duration(interval) < p.MinRepeatInterval:
+			violations = append(violations, fmt.Sprintf("%s: repeat_interval %s is below the minimum of %s",
+				path, r.RepeatInterval, model.Duration(p.MinRepeatInterval)))
+		}
+	}
+	for i := range r.Routes {
+		violations = append(violations, p.checkRepeatIntervals(&r.Routes[i], fmt.Sprintf("%s.routes[%d]", path, i))...)
+	}
+	return violations
+}
+
+// routesBySeverity reports whether r or any of its child routes matches on the severity label.
+func routesBySeverity(r *route) bool {
+	if _, ok := r.Match[severityLabel]; ok {
+		return true
+	}
+	if _, ok := r.MatchRE[severityLabel]; ok {
+		return true
+	}
+	for _, matcher := range r.Matchers {
+		if severityMatcher.MatchString(matcher) {
+			return true
+		}
+	}
+	for i := range r.Routes {
+		if routesBySeverity(&r.Routes[i]) {
+			return true
+		}
+	}
+	return false
+}
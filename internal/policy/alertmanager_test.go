@@ -0,0 +1,114 @@
+package policy
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestAlertmanagerPolicyCheck(t *testing.T) {
+	policy := &AlertmanagerPolicy{Mode: ModeBlock, MinRepeatInterval: DefaultMinRepeatInterval}
+
+	tests := []struct {
+		name   string
+		config string
+		want   []string
+	}{
+		{
+			name: "compliant",
+			config: `
+route:
+  receiver: default
+  repeat_interval: 4h
+  routes:
+    - receiver: pager
+      matchers: ['severity="critical"']
+      repeat_interval: 1h
+`,
+		},
+		{
+			name: "severity routing through match",
+			config: `
+route:
+  receiver: default
+  routes:
+    - receiver: pager
+      match:
+        severity: critical
+`,
+		},
+		{
+			name:   "missing route",
+			config: "receivers:\n  - name: default\n",
+			want:   []string{"route: a top-level route is required"},
+		},
+		{
+			name: "all violations",
+			config: `
+route:
+  repeat_interval: 5m
+  routes:
+    - receiver: team
+      matchers: ['team="payments"']
+      repeat_interval: 1d
+    - receiver: noisy
+      repeat_interval: 30m
+`,
+			want: []string{
+				"route: a default receiver is required",
+				"route: repeat_interval 5m is below the minimum of 1h",
+				"route.routes[1]: repeat_interval 30m is below the minimum of 1h",
+				"route: no route matches on the severity label",
+			},
+		},
+		{
+			name: "invalid repeat interval",
+			config: `
+route:
+  receiver: default
+  repeat_interval: often
+  routes:
+    - receiver: pager
+      match_re:
+        severity: critical|warning
+`,
+			want: []string{`route: invalid repeat_interval "often"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := policy.Check(tt.config)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got violations %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAlertmanagerPolicyDisabled(t *testing.T) {
+	var nilPolicy *AlertmanagerPolicy
+	for _, policy := range []*AlertmanagerPolicy{nilPolicy, {MinRepeatInterval: time.Hour}} {
+		got, err := policy.Check("route: {}")
+		if err != nil || got != nil {
+			t.Errorf("expected disabled policy to report nothing, got %q, %v", got, err)
+		}
+		if policy.Blocking() {
+			t.Error("expected disabled policy not to block")
+		}
+	}
+}
+
+func TestParseMode(t *testing.T) {
+	for _, value := range []string{"", "warn", "block"} {
+		if _, err := ParseMode(value); err != nil {
+			t.Errorf("ParseMode(%q) returned error: %v", value, err)
+		}
+	}
+	if _, err := ParseMode("enforce"); err == nil {
+		t.Error("expected error for unknown mode")
+	}
+}
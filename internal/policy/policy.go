@@ -0,0 +1,26 @@
+// Package policy enforces organizational rules on configurations before they are synced to Mimir.
+package policy
+
+import "fmt"
+
+// Mode controls how policy violations are handled.
+type Mode string
+
+const (
+	// ModeDisabled skips all policy checks
+	ModeDisabled Mode = ""
+	// ModeWarn reports violations but still syncs the configuration
+	ModeWarn Mode = "warn"
+	// ModeBlock reports violations and does not sync the configuration
+	ModeBlock Mode = "block"
+)
+
+// ParseMode parses a policy mode flag value. An empty value disables the policy.
+func ParseMode(value string) (Mode, error) {
+	switch mode := Mode(value); mode {
+	case ModeDisabled, ModeWarn, ModeBlock:
+		return mode, nil
+	default:
+		return ModeDisabled, fmt.Errorf("invalid policy mode %q, expected %q or %q", value, ModeWarn, ModeBlock)
+	}
+}
@@ -0,0 +1,95 @@
+package policy
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ErrQuotaExceeded is returned for MimirAlertTenants exceeding a TenantQuota
+var ErrQuotaExceeded = errors.New("quota exceeded")
+
+// TenantQuota protects the shared Alertmanager from an unbounded number or size of
+// configurations:
+//   - a namespace has at most MaxPerNamespace MimirAlertTenants
+//   - a Mimir tenant is targeted by at most MaxPerTenant MimirAlertTenants
+//   - a rendered configuration, including its template files, has at most MaxConfigBytes bytes
+//
+// The oldest MimirAlertTenants are within the count limits, newer ones exceed them until older
+// ones are deleted. A zero limit or a nil TenantQuota does not limit anything.
+type TenantQuota struct {
+	// MaxPerNamespace is the number of MimirAlertTenants allowed per namespace
+	MaxPerNamespace int
+	// MaxPerTenant is the number of MimirAlertTenants allowed to target the same Mimir tenant
+	MaxPerTenant int
+	// MaxConfigBytes is the size allowed for a rendered configuration with its template files
+	MaxConfigBytes int
+}
+
+// LimitsCount reports whether the number of MimirAlertTenants is limited.
+func (q *TenantQuota) LimitsCount() bool {
+	return q != nil && (q.MaxPerNamespace > 0 || q.MaxPerTenant > 0)
+}
+
+// CheckConfigSize returns an error wrapping ErrQuotaExceeded if the configuration and its
+// template files exceed MaxConfigBytes.
+func (q *TenantQuota) CheckConfigSize(config string, templates map[string]string) error {
+	if q == nil || q.MaxConfigBytes <= 0 {
+		return nil
+	}
+	size := len(config)
+	for _, template := range templates {
+		size += len(template)
+	}
+	if size > q.MaxConfigBytes {
+		return fmt.Errorf("%w: the configuration has %d bytes including template files, at most %d are allowed",
+			ErrQuotaExceeded, size, q.MaxConfigBytes)
+	}
+	return nil
+}
+
+// CheckNamespaceCount returns an error wrapping ErrQuotaExceeded if obj is not among the
+// MaxPerNamespace oldest of tenants, the MimirAlertTenants of its namespace.
+func (q *TenantQuota) CheckNamespaceCount(obj metav1.Object, tenants []metav1.Object) error {
+	if q == nil || withinCount(obj, tenants, q.MaxPerNamespace) {
+		return nil
+	}
+	return fmt.Errorf("%w: namespace %s has %d MimirAlertTenants, at most %d are allowed",
+		ErrQuotaExceeded, obj.GetNamespace(), len(tenants), q.MaxPerNamespace)
+}
+
+// CheckTenantCount returns an error wrapping ErrQuotaExceeded if obj is not among the
+// MaxPerTenant oldest of tenants, the MimirAlertTenants targeting the Mimir tenant tenantID.
+func (q *TenantQuota) CheckTenantCount(obj metav1.Object, tenantID string, tenants []metav1.Object) error {
+	if q == nil || withinCount(obj, tenants, q.MaxPerTenant) {
+		return nil
+	}
+	return fmt.Errorf("%w: %d MimirAlertTenants target the Mimir tenant %s, at most %d are allowed",
+		ErrQuotaExceeded, len(tenants), tenantID, q.MaxPerTenant)
+}
+
+// withinCount reports whether obj is among the limit oldest of objects, which include obj,
+// ordered by creation time, namespace and name. A limit of zero or less admits every object.
+func withinCount(obj metav1.Object, objects []metav1.Object, limit int) bool {
+	if limit <= 0 || len(objects) <= limit {
+		return true
+	}
+	sorted := slices.Clone(objects)
+	slices.SortFunc(sorted, func(a, b metav1.Object) int {
+		createdA, createdB := a.GetCreationTimestamp(), b.GetCreationTimestamp()
+		switch {
+		case createdA.Before(&createdB):
+			return -1
+		case createdB.Before(&createdA):
+			return 1
+		}
+		return strings.Compare(a.GetNamespace()+"/"+a.GetName(), b.GetNamespace()+"/"+b.GetName())
+	})
+	return slices.ContainsFunc(sorted[:limit], func(other metav1.Object) bool {
+		return other.GetUID() == obj.GetUID() && other.GetNamespace() == obj.GetNamespace() &&
+			other.GetName() == obj.GetName()
+	})
+}
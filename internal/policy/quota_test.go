@@ -0,0 +1,69 @@
+package policy
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func quotaTenant(name string, age time.Duration) *metav1.ObjectMeta {
+	return &metav1.ObjectMeta{
+		Name:              name,
+		Namespace:         "team",
+		UID:               types.UID(name),
+		CreationTimestamp: metav1.NewTime(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC).Add(-age)),
+	}
+}
+
+func TestTenantQuotaCounts(t *testing.T) {
+	oldest, older, sameAge, newest := quotaTenant("oldest", time.Hour), quotaTenant("b-older", time.Minute),
+		quotaTenant("a-older", time.Minute), quotaTenant("newest", 0)
+	tenants := []metav1.Object{newest, older, oldest, sameAge}
+	quota := &TenantQuota{MaxPerNamespace: 2, MaxPerTenant: 3}
+
+	if err := quota.CheckNamespaceCount(oldest, tenants); err != nil {
+		t.Errorf("expected the oldest tenant within the quota, got %v", err)
+	}
+	// Tenants created at the same time are ordered by name
+	if err := quota.CheckNamespaceCount(sameAge, tenants); err != nil {
+		t.Errorf("expected a-older within the quota, got %v", err)
+	}
+	err := quota.CheckNamespaceCount(older, tenants)
+	if !errors.Is(err, ErrQuotaExceeded) || !strings.Contains(err.Error(), "namespace team has 4") {
+		t.Errorf("expected b-older to exceed the namespace quota, got %v", err)
+	}
+
+	if err := quota.CheckTenantCount(older, "payments", tenants); err != nil {
+		t.Errorf("expected b-older within the tenant quota, got %v", err)
+	}
+	err = quota.CheckTenantCount(newest, "payments", tenants)
+	if !errors.Is(err, ErrQuotaExceeded) || !strings.Contains(err.Error(), "Mimir tenant payments") {
+		t.Errorf("expected the newest tenant to exceed the tenant quota, got %v", err)
+	}
+
+	var disabled *TenantQuota
+	if disabled.LimitsCount() || (&TenantQuota{}).LimitsCount() {
+		t.Error("expected no count limit without quota")
+	}
+	if err := (&TenantQuota{}).CheckNamespaceCount(newest, tenants); err != nil {
+		t.Errorf("expected no limit for a zero quota, got %v", err)
+	}
+}
+
+func TestTenantQuotaConfigSize(t *testing.T) {
+	quota := &TenantQuota{MaxConfigBytes: 10}
+	if err := quota.CheckConfigSize("route:", map[string]string{"a.tmpl": "1234"}); err != nil {
+		t.Errorf("expected 10 bytes to be allowed, got %v", err)
+	}
+	err := quota.CheckConfigSize("route:", map[string]string{"a.tmpl": "12345"})
+	if !errors.Is(err, ErrQuotaExceeded) || !strings.Contains(err.Error(), "11 bytes") {
+		t.Errorf("expected the template files to count towards the size, got %v", err)
+	}
+	if err := (*TenantQuota)(nil).CheckConfigSize(strings.Repeat("x", 1<<20), nil); err != nil {
+		t.Errorf("expected no limit without quota, got %v", err)
+	}
+}
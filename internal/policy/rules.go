@@ -0,0 +1,133 @@
+package policy
+
+import (
+	"fmt"
+	"strings"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/promql/parser"
+)
+
+const (
+	// DefaultRequiredLabels are the labels every alert needs by default
+	DefaultRequiredLabels = "severity"
+	// DefaultRequiredAnnotations are the annotations every alert needs by default
+	DefaultRequiredAnnotations = "runbook_url"
+)
+
+// absentFunctions fire as soon as a series is missing, so alerts using them need a for: duration
+var absentFunctions = map[string]bool{
+	"absent":           true,
+	"absent_over_time": true,
+}
+
+// RulePolicy checks the alerting rules of PrometheusRules against organizational rules:
+//   - every alert has the RequiredLabels and RequiredAnnotations
+//   - alerts using absent() or absent_over_time() set a for: duration
+//
+// Recording rules are not checked. A nil RulePolicy or one with ModeDisabled reports no findings.
+type RulePolicy struct {
+	Mode                Mode
+	RequiredLabels      []string
+	RequiredAnnotations []string
+}
+
+// RuleFinding is a policy violation of a single alerting rule.
+type RuleFinding struct {
+	Group   string
+	Alert   string
+	Message string
+}
+
+// String returns the finding in the form "group/alert: message".
+func (f RuleFinding) String() string {
+	return fmt.Sprintf("%s/%s: %s", f.Group, f.Alert, f.Message)
+}
+
+// Enabled reports whether the policy checks rules.
+func (p *RulePolicy) Enabled() bool {
+	return p != nil && p.Mode != ModeDisabled
+}
+
+// Blocking reports whether findings prevent the rules from being pushed.
+func (p *RulePolicy) Blocking() bool {
+	return p.Enabled() && p.Mode == ModeBlock
+}
+
+// Check returns the policy findings of the alerting rules in groups.
+func (p *RulePolicy) Check(groups []monitoringv1.RuleGroup) []RuleFinding {
+	if !p.Enabled() {
+		return nil
+	}
+
+	var findings []RuleFinding
+	for _, group := range groups {
+		for _, rule := range group.Rules {
+			if rule.Alert == "" {
+				continue
+			}
+			for _, message := range p.checkAlert(rule) {
+				findings = append(findings, RuleFinding{Group: group.Name, Alert: rule.Alert, Message: message})
+			}
+		}
+	}
+	return findings
+}
+
+// checkAlert returns the policy violations of a single alerting rule.
+func (p *RulePolicy) checkAlert(rule monitoringv1.Rule) []string {
+	var violations []string
+	for _, label := range p.RequiredLabels {
+		if rule.Labels[label] == "" {
+			violations = append(violations, fmt.Sprintf("missing label %q", label))
+		}
+	}
+	for _, annotation := range p.RequiredAnnotations {
+		if rule.Annotations[annotation] == "" {
+			violations = append(violations, fmt.Sprintf("missing annotation %q", annotation))
+		}
+	}
+	if !hasFor(rule) && usesAbsent(rule.Expr.String()) {
+		violations = append(violations, "absent() is used without a for: duration")
+	}
+	return violations
+}
+
+// hasFor reports whether the rule sets a non-zero for: duration.
+func hasFor(rule monitoringv1.Rule) bool {
+	if rule.For == nil {
+		return false
+	}
+	duration, err := model.ParseDuration(string(*rule.For))
+	return err == nil && duration > 0
+}
+
+// usesAbsent reports whether the PromQL expression calls absent() or absent_over_time().
+// Expressions that cannot be parsed are left to Mimir's validation.
+func usesAbsent(expr string) bool {
+	parsed, err := parser.ParseExpr(expr)
+	if err != nil {
+		return false
+	}
+
+	found := false
+	parser.Inspect(parsed, func(node parser.Node, _ []parser.Node) error {
+		if call, ok := node.(*parser.Call); ok && absentFunctions[call.Func.Name] {
+			found = true
+		}
+		return nil
+	})
+	return found
+}
+
+// ParseList splits a comma-separated flag value, dropping empty entries.
+func ParseList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,85 @@
+package policy
+
+import (
+	"reflect"
+	"testing"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestRulePolicyCheck(t *testing.T) {
+	policy := &RulePolicy{
+		Mode:                ModeWarn,
+		RequiredLabels:      ParseList(DefaultRequiredLabels),
+		RequiredAnnotations: ParseList(DefaultRequiredAnnotations),
+	}
+	forDuration := monitoringv1.Duration("5m")
+	zeroDuration := monitoringv1.Duration("0s")
+
+	compliant := monitoringv1.Rule{
+		Alert:       "HighErrorRate",
+		Expr:        intstr.FromString(`rate(errors_total[5m]) > 1`),
+		Labels:      map[string]string{"severity": "critical"},
+		Annotations: map[string]string{"runbook_url": "https://runbooks.example.org/errors"},
+	}
+	absentWithFor := compliant
+	absentWithFor.Alert = "TargetMissing"
+	absentWithFor.Expr = intstr.FromString(`absent(up{job="api"})`)
+	absentWithFor.For = &forDuration
+	absentWithoutFor := absentWithFor
+	absentWithoutFor.Alert = "TargetGone"
+	absentWithoutFor.Expr = intstr.FromString(`sum(absent_over_time(up{job="api"}[5m]))`)
+	absentWithoutFor.For = &zeroDuration
+
+	groups := []monitoringv1.RuleGroup{
+		{
+			Name: "api",
+			Rules: []monitoringv1.Rule{
+				compliant,
+				absentWithFor,
+				absentWithoutFor,
+				{Record: "job:errors:rate5m", Expr: intstr.FromString(`sum(rate(errors_total[5m])) by (job)`)},
+				{Alert: "Unlabelled", Expr: intstr.FromString(`up == 0`)},
+			},
+		},
+	}
+
+	want := []RuleFinding{
+		{Group: "api", Alert: "TargetGone", Message: "absent() is used without a for: duration"},
+		{Group: "api", Alert: "Unlabelled", Message: `missing label "severity"`},
+		{Group: "api", Alert: "Unlabelled", Message: `missing annotation "runbook_url"`},
+	}
+	if got := policy.Check(groups); !reflect.DeepEqual(got, want) {
+		t.Errorf("got findings %v, want %v", got, want)
+	}
+
+	if got := want[1].String(); got != `api/Unlabelled: missing label "severity"` {
+		t.Errorf("unexpected finding string %q", got)
+	}
+}
+
+func TestRulePolicyDisabled(t *testing.T) {
+	var nilPolicy *RulePolicy
+	groups := []monitoringv1.RuleGroup{{
+		Name:  "api",
+		Rules: []monitoringv1.Rule{{Alert: "Unlabelled", Expr: intstr.FromString(`up == 0`)}},
+	}}
+	for _, policy := range []*RulePolicy{nilPolicy, {RequiredLabels: []string{"severity"}}} {
+		if got := policy.Check(groups); got != nil {
+			t.Errorf("expected disabled policy to report nothing, got %v", got)
+		}
+		if policy.Blocking() {
+			t.Error("expected disabled policy not to block")
+		}
+	}
+}
+
+func TestParseList(t *testing.T) {
+	if got := ParseList(" severity, team,,"); !reflect.DeepEqual(got, []string{"severity", "team"}) {
+		t.Errorf("unexpected list %q", got)
+	}
+	if got := ParseList(""); got != nil {
+		t.Errorf("expected empty list, got %q", got)
+	}
+}
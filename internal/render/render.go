@@ -0,0 +1,219 @@
+// Package render renders and validates local manifests the way the controller does,
+// so CI pipelines can catch problems before the manifests are applied.
+package render
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/go-logr/logr"
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apimachinery/pkg/types"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	openawarenessv1beta1 "github.com/syndlex/openawareness-controller/api/openawareness/v1beta1"
+	"github.com/syndlex/openawareness-controller/internal/controller/utils"
+	"github.com/syndlex/openawareness-controller/internal/mimir"
+	"github.com/syndlex/openawareness-controller/internal/templatesource"
+)
+
+// defaultNamespace is used for manifests without a namespace
+const defaultNamespace = "default"
+
+// Options are the local manifests rendered by AlertmanagerPayload.
+type Options struct {
+	// Tenant is the manifest of the MimirAlertTenant to render
+	Tenant []byte
+	// Values are manifests of the ConfigMaps and Secrets referenced by the tenant, of the
+	// MimirAlertTenants it extends and of the MimirAlertRoutes, MimirAlertGlobals, MimirMuteTimings and
+	// MimirInhibitRules contributing to it.
+	// Each may contain several YAML documents.
+	Values [][]byte
+	// GlobalValues is the manifest of the global values ConfigMap, nil if not used
+	GlobalValues []byte
+	// ClusterName identifies the cluster in templates as [[ .Meta.Cluster ]]
+	ClusterName string
+}
+
+// AlertmanagerPayload renders the MimirAlertTenant of opts and returns the payload the
+// controller would send to Mimir. The referenced ConfigMaps and Secrets, the extended
+// tenants, the MimirAlertRoutes, the MimirAlertGlobals, the MimirMuteTimings and the MimirInhibitRules are read from opts.Values; manifests without a namespace belong to the tenant's namespace.
+// Returns an error if a manifest cannot be decoded or the configuration cannot be rendered.
+func AlertmanagerPayload(ctx context.Context, opts Options) ([]byte, error) {
+	scheme, err := newScheme()
+	if err != nil {
+		return nil, err
+	}
+	decoder := serializer.NewCodecFactory(scheme).UniversalDeserializer()
+
+	tenants, err := decode(decoder, opts.Tenant)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode tenant: %w", err)
+	}
+	if len(tenants) != 1 {
+		return nil, fmt.Errorf("expected exactly one MimirAlertTenant, found %d objects", len(tenants))
+	}
+	tenant, ok := tenants[0].(*openawarenessv1beta1.MimirAlertTenant)
+	if !ok {
+		return nil, fmt.Errorf("expected a MimirAlertTenant, found %T", tenants[0])
+	}
+	if tenant.Namespace == "" {
+		tenant.Namespace = defaultNamespace
+	}
+
+	var objects []client.Object
+	for _, manifest := range opts.Values {
+		values, err := decode(decoder, manifest)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode values: %w", err)
+		}
+		for _, value := range values {
+			object, err := referenceObject(value, tenant.Namespace)
+			if err != nil {
+				return nil, err
+			}
+			objects = append(objects, object)
+		}
+	}
+
+	var globalValues *utils.GlobalValues
+	if opts.GlobalValues != nil {
+		globals, err := decode(decoder, opts.GlobalValues)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode global values: %w", err)
+		}
+		if len(globals) != 1 {
+			return nil, fmt.Errorf("expected exactly one global values ConfigMap, found %d objects", len(globals))
+		}
+		configMap, ok := globals[0].(*corev1.ConfigMap)
+		if !ok {
+			return nil, fmt.Errorf("expected a global values ConfigMap, found %T", globals[0])
+		}
+		if configMap.Namespace == "" {
+			configMap.Namespace = defaultNamespace
+		}
+		objects = append(objects, configMap)
+		globalValues = &utils.GlobalValues{ConfigMap: types.NamespacedName{
+			Namespace: configMap.Namespace,
+			Name:      configMap.Name,
+		}}
+	}
+
+	// An in-memory client lets the controller's rendering read the local manifests
+	reader := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+	if globalValues != nil {
+		globalValues.Reader = reader
+	}
+
+	// Template sources are fetched like the controller does, with credentials from the manifests
+	config, templates, err := utils.RenderAlertmanagerConfig(ctx, reader, logr.Discard(), tenant,
+		globalValues, opts.ClusterName, &templatesource.Fetcher{Reader: reader})
+	if err != nil {
+		return nil, err
+	}
+	if err := tenant.ValidateRenderedConfig(config); err != nil {
+		return nil, err
+	}
+	return mimir.AlertmanagerPayload(config, templates)
+}
+
+// newScheme returns a scheme with the kinds read from local manifests.
+func newScheme() (*runtime.Scheme, error) {
+	scheme := runtime.NewScheme()
+	for _, add := range []func(*runtime.Scheme) error{
+		corev1.AddToScheme, openawarenessv1beta1.AddToScheme, monitoringv1.AddToScheme,
+	} {
+		if err := add(scheme); err != nil {
+			return nil, err
+		}
+	}
+	return scheme, nil
+}
+
+// referenceObject returns a ConfigMap, Secret, extended MimirAlertTenant, MimirAlertRoute, MimirAlertGlobals, MimirMuteTiming
+// or MimirInhibitRule manifest as stored by the API server, defaulting its namespace and merging the stringData of Secrets
+// into their data.
+// Returns an error for any other kind of object.
+func referenceObject(obj runtime.Object, namespace string) (client.Object, error) {
+	switch value := obj.(type) {
+	case *openawarenessv1beta1.MimirAlertTenant:
+		if value.Namespace == "" {
+			value.Namespace = namespace
+		}
+		return value, nil
+	case *openawarenessv1beta1.MimirAlertRoute:
+		if value.Namespace == "" {
+			value.Namespace = namespace
+		}
+		return value, nil
+	case *openawarenessv1beta1.MimirAlertGlobals:
+		if value.Namespace == "" {
+			value.Namespace = namespace
+		}
+		return value, nil
+	case *openawarenessv1beta1.MimirMuteTiming:
+		if value.Namespace == "" {
+			value.Namespace = namespace
+		}
+		return value, nil
+	case *openawarenessv1beta1.MimirInhibitRule:
+		if value.Namespace == "" {
+			value.Namespace = namespace
+		}
+		return value, nil
+	case *corev1.ConfigMap:
+		if value.Namespace == "" {
+			value.Namespace = namespace
+		}
+		return value, nil
+	case *corev1.Secret:
+		if value.Namespace == "" {
+			value.Namespace = namespace
+		}
+		for k, v := range value.StringData {
+			if value.Data == nil {
+				value.Data = map[string][]byte{}
+			}
+			value.Data[k] = []byte(v)
+		}
+		value.StringData = nil
+		return value, nil
+	default:
+		return nil, fmt.Errorf(
+			"values must be ConfigMaps, Secrets, MimirAlertTenants, MimirAlertRoutes, MimirAlertGlobals, "+
+				"MimirMuteTimings or MimirInhibitRules, found %T", obj)
+	}
+}
+
+// decode decodes all YAML documents of a manifest, skipping empty documents.
+func decode(decoder runtime.Decoder, manifest []byte) ([]runtime.Object, error) {
+	reader := utilyaml.NewYAMLReader(bufio.NewReader(bytes.NewReader(manifest)))
+
+	var objects []runtime.Object
+	for {
+		document, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return objects, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(bytes.TrimSpace(document)) == 0 {
+			continue
+		}
+		obj, _, err := decoder.Decode(document, nil, nil)
+		if err != nil {
+			return nil, err
+		}
+		objects = append(objects, obj)
+	}
+}
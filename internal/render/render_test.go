@@ -0,0 +1,211 @@
+package render
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+const tenantManifest = `
+apiVersion: openawareness.syndlex/v1beta1
+kind: MimirAlertTenant
+metadata:
+  name: alerts
+  namespace: payments
+  annotations:
+    openawareness.io/client-name: mimir
+    openawareness.io/mimir-tenant: team-a
+spec:
+  secretDataReferences:
+    - kind: ConfigMap
+      name: receivers
+    - kind: Secret
+      name: slack
+  alertmanagerConfig: |
+    route:
+      receiver: [[ .RECEIVER ]]
+    receivers:
+      - name: [[ .RECEIVER ]]
+        slack_configs:
+          - api_url: [[ .SLACK_URL ]]
+            title: "[[ .Global.ENV ]] [[ .Meta.Cluster ]] [[ .Meta.Tenant ]]"
+`
+
+const valuesManifest = `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: receivers
+data:
+  RECEIVER: payments-slack
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: slack
+stringData:
+  SLACK_URL: https://hooks.slack.example.org/payments
+`
+
+const globalsManifest = `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: globals
+  namespace: openawareness-system
+data:
+  ENV: prod
+`
+
+func TestAlertmanagerPayload(t *testing.T) {
+	payload, err := AlertmanagerPayload(context.Background(), Options{
+		Tenant:       []byte(tenantManifest),
+		Values:       [][]byte{[]byte(valuesManifest)},
+		GlobalValues: []byte(globalsManifest),
+		ClusterName:  "eu-1",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, want := range []string{
+		"alertmanager_config:",
+		"# managed-by: openawareness-controller MimirAlertTenant payments/alerts",
+		"receiver: payments-slack",
+		"api_url: https://hooks.slack.example.org/payments",
+		`title: "prod eu-1 team-a"`,
+	} {
+		if !strings.Contains(string(payload), want) {
+			t.Errorf("expected payload to contain %q, got:\n%s", want, payload)
+		}
+	}
+}
+
+const overlayManifest = `
+apiVersion: openawareness.syndlex/v1beta1
+kind: MimirAlertTenant
+metadata:
+  name: alerts-oncall
+  namespace: payments
+spec:
+  extends:
+    name: alerts
+  alertmanagerConfig: |
+    route:
+      routes:
+        - receiver: pager
+          matchers: ['severity="critical"']
+    receivers:
+      - name: pager
+        pagerduty_configs:
+          - routing_key: [[ .SLACK_URL ]]
+  templateFiles:
+    pager.tmpl: '{{ define "pager" }}[[ .Meta.Name ]]{{ end }}'
+`
+
+func TestAlertmanagerPayloadExtends(t *testing.T) {
+	payload, err := AlertmanagerPayload(context.Background(), Options{
+		Tenant:       []byte(overlayManifest),
+		Values:       [][]byte{[]byte(valuesManifest), []byte(tenantManifest)},
+		GlobalValues: []byte(globalsManifest),
+		ClusterName:  "eu-1",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, want := range []string{
+		"# managed-by: openawareness-controller MimirAlertTenant payments/alerts-oncall",
+		"receiver: payments-slack",
+		"receiver: pager",
+		"- name: payments-slack",
+		"- name: pager",
+		"routing_key: https://hooks.slack.example.org/payments",
+		"pager.tmpl",
+	} {
+		if !strings.Contains(string(payload), want) {
+			t.Errorf("expected payload to contain %q, got:\n%s", want, payload)
+		}
+	}
+}
+
+const routeManifest = `
+apiVersion: openawareness.syndlex/v1beta1
+kind: MimirAlertRoute
+metadata:
+  name: checkout
+spec:
+  tenant:
+    name: alerts
+  matchers: ['team="checkout"']
+  route: |
+    receiver: checkout-slack
+  receivers: |
+    - name: checkout-slack
+      slack_configs:
+        - api_url: [[ .SLACK_URL ]]
+`
+
+func TestAlertmanagerPayloadRoutes(t *testing.T) {
+	payload, err := AlertmanagerPayload(context.Background(), Options{
+		Tenant: []byte(tenantManifest),
+		Values: [][]byte{[]byte(valuesManifest), []byte(routeManifest)},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, want := range []string{
+		"receiver: checkout-slack",
+		`team="checkout"`,
+		"- name: checkout-slack",
+		"- name: payments-slack",
+	} {
+		if !strings.Contains(string(payload), want) {
+			t.Errorf("expected payload to contain %q, got:\n%s", want, payload)
+		}
+	}
+}
+
+func TestAlertmanagerPayloadErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		opts Options
+		want string
+	}{
+		{
+			name: "missing reference",
+			opts: Options{Tenant: []byte(tenantManifest)},
+			want: "failed to get ConfigMap receivers",
+		},
+		{
+			name: "no tenant",
+			opts: Options{Tenant: []byte(valuesManifest)},
+			want: "expected exactly one MimirAlertTenant",
+		},
+		{
+			name: "unsupported values",
+			opts: Options{Tenant: []byte(tenantManifest), Values: [][]byte{[]byte(`
+apiVersion: monitoring.coreos.com/v1
+kind: PrometheusRule
+metadata:
+  name: rules
+`)}},
+			want: "values must be ConfigMaps, Secrets, MimirAlertTenants, MimirAlertRoutes, MimirAlertGlobals, MimirMuteTimings or MimirInhibitRules",
+		},
+		{
+			name: "missing base tenant",
+			opts: Options{Tenant: []byte(overlayManifest)},
+			want: "failed to get base MimirAlertTenant payments/alerts",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := AlertmanagerPayload(context.Background(), tt.opts)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
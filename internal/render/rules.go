@@ -0,0 +1,59 @@
+package render
+
+import (
+	"fmt"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+
+	monitoringcoreoscom "github.com/syndlex/openawareness-controller/internal/controller/monitoring.coreos.com"
+	"github.com/syndlex/openawareness-controller/internal/controller/utils"
+)
+
+// ValidateRules runs the conversion and validation the controller performs before pushing
+// rule groups to Mimir against the PrometheusRules of a manifest, including PromQL parsing.
+// Returns the problems found in the form "namespace/name: problem", and an error if the
+// manifest cannot be decoded or contains no PrometheusRule.
+// Templated PrometheusRules are rendered without template data, so only templates falling back
+// to a default value, e.g. [[ .threshold | default "90" ]], yield valid expressions.
+func ValidateRules(manifest []byte) ([]string, error) {
+	scheme, err := newScheme()
+	if err != nil {
+		return nil, err
+	}
+
+	objects, err := decode(serializer.NewCodecFactory(scheme).UniversalDeserializer(), manifest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode rules: %w", err)
+	}
+	if len(objects) == 0 {
+		return nil, fmt.Errorf("no PrometheusRule found")
+	}
+
+	var problems []string
+	for _, obj := range objects {
+		rule, ok := obj.(*monitoringv1.PrometheusRule)
+		if !ok {
+			return nil, fmt.Errorf("expected PrometheusRules, found %T", obj)
+		}
+		if rule.Namespace == "" {
+			rule.Namespace = defaultNamespace
+		}
+
+		// Template data is not available offline, templates render their default values
+		templated, err := monitoringcoreoscom.RenderRuleTemplates(rule, nil)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", utils.OwnerReference(rule), err))
+			continue
+		}
+		groups, err := monitoringcoreoscom.DesiredRuleGroups(templated)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", utils.OwnerReference(rule), err))
+			continue
+		}
+		for _, err := range monitoringcoreoscom.ValidateRuleGroups(groups) {
+			problems = append(problems, fmt.Sprintf("%s: %v", utils.OwnerReference(rule), err))
+		}
+	}
+	return problems, nil
+}
@@ -0,0 +1,111 @@
+package render
+
+import (
+	"strings"
+	"testing"
+)
+
+const validRules = `
+apiVersion: monitoring.coreos.com/v1
+kind: PrometheusRule
+metadata:
+  name: api
+  namespace: payments
+spec:
+  groups:
+    - name: api
+      rules:
+        - alert: APIDown
+          expr: up{job="api"} == 0
+          for: 5m
+          labels:
+            severity: critical
+          annotations:
+            summary: "{{ $labels.instance }} is down"
+        - record: job:up:sum
+          expr: sum(up) by (job)
+`
+
+const invalidRules = `
+apiVersion: monitoring.coreos.com/v1
+kind: PrometheusRule
+metadata:
+  name: broken
+spec:
+  groups:
+    - name: broken
+      rules:
+        - alert: BadQuery
+          expr: sum(rate(errors_total[5m]) by (job)
+        - alert: BadTemplate
+          expr: up == 0
+          annotations:
+            summary: "{{ .Broken "
+    - name: broken
+      rules:
+        - alert: Duplicate
+          expr: up == 0
+`
+
+func TestValidateRules(t *testing.T) {
+	problems, err := ValidateRules([]byte(validRules + "---" + invalidRules))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []string{"BadQuery", "BadTemplate", `"broken" is repeated`}
+	if len(problems) != len(want) {
+		t.Fatalf("got %d problems, want %d: %q", len(problems), len(want), problems)
+	}
+	for i, problem := range problems {
+		if !strings.HasPrefix(problem, "default/broken: ") || !strings.Contains(problem, want[i]) {
+			t.Errorf("problem %d = %q, want default/broken problem containing %q", i, problem, want[i])
+		}
+	}
+}
+
+func TestValidateRulesValid(t *testing.T) {
+	problems, err := ValidateRules([]byte(validRules))
+	if err != nil || len(problems) != 0 {
+		t.Errorf("expected valid rules, got %q, %v", problems, err)
+	}
+}
+
+const templatedRules = `
+apiVersion: monitoring.coreos.com/v1
+kind: PrometheusRule
+metadata:
+  name: thresholds
+  annotations:
+    openawareness.io/template: "true"
+    openawareness.io/secret-data-refs: ConfigMap/thresholds
+spec:
+  groups:
+    - name: thresholds
+      rules:
+        - alert: HighLatency
+          expr: latency_seconds > [[ .latency | default "2" ]]
+          annotations:
+            summary: '{{ $value }} above [[ .latency | default "2" ]]s'
+        - alert: HighErrorRate
+          expr: error_rate > [[ .errorRate ]]
+`
+
+func TestValidateRulesTemplated(t *testing.T) {
+	problems, err := ValidateRules([]byte(templatedRules))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(problems) != 1 || !strings.Contains(problems[0], "HighErrorRate") {
+		t.Errorf("expected only the template without default value to be invalid, got %q", problems)
+	}
+}
+
+func TestValidateRulesErrors(t *testing.T) {
+	if _, err := ValidateRules([]byte("")); err == nil {
+		t.Error("expected error for empty manifest")
+	}
+	if _, err := ValidateRules([]byte(tenantManifest)); err == nil {
+		t.Error("expected error for non PrometheusRule manifest")
+	}
+}
@@ -0,0 +1,278 @@
+// Package restore pushes the desired state of the resources using a ClientConfig to Mimir
+// again, e.g. after Mimir was restored from an older backup.
+package restore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"github.com/prometheus/prometheus/model/rulefmt"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	openawarenessv1beta1 "github.com/syndlex/openawareness-controller/api/openawareness/v1beta1"
+	"github.com/syndlex/openawareness-controller/internal/clients"
+	monitoringcoreoscom "github.com/syndlex/openawareness-controller/internal/controller/monitoring.coreos.com"
+	"github.com/syndlex/openawareness-controller/internal/controller/utils"
+)
+
+// DefaultVerifyTimeout is the default time to wait until pushed state can be read back
+const DefaultVerifyTimeout = 30 * time.Second
+
+// verifyInterval is the time between two reads of pushed state
+const verifyInterval = time.Second
+
+// ErrNotVerified is returned if pushed state cannot be read back within the verify timeout
+var ErrNotVerified = errors.New("pushed state not verified")
+
+// Restorer pushes the state of the MimirAlertTenants and PrometheusRules synced through a
+// ClientConfig with the same rendering as the controllers, in dependency order: the
+// Alertmanager configurations of all tenants first, then the rule groups. Every push is
+// verified by reading it back before the next one.
+// Paused resources and resources being deleted are skipped.
+type Restorer struct {
+	// Reader reads the resources and their template data
+	Reader client.Reader
+	// Remote is the Mimir client of ClientName
+	Remote clients.AwarenessClient
+	// ClientName is the name of the ClientConfig whose resources are restored
+	ClientName string
+	// TenantID restricts the restore to a single tenant, all tenants if empty
+	TenantID string
+	// GlobalValues provides controller-wide template values, nil if not configured
+	GlobalValues *utils.GlobalValues
+	// ClusterName identifies the cluster in templates as [[ .Meta.Cluster ]]
+	ClusterName string
+	// ExtraLabels are added to every alerting rule, only the extra-labels annotation applies if nil
+	ExtraLabels *utils.ExtraLabels
+	// TemplateSources fetches the templateSources of tenants, tenants using one fail to restore if nil
+	TemplateSources utils.TemplateSourceFetcher
+	// VerifyTimeout bounds the wait for a push to be readable, DefaultVerifyTimeout if zero
+	VerifyTimeout time.Duration
+	// Out receives a line per restored resource
+	Out io.Writer
+}
+
+// Summary counts the state pushed by Run.
+type Summary struct {
+	// AlertmanagerConfigs is the number of Alertmanager configurations pushed
+	AlertmanagerConfigs int
+	// RuleGroups is the number of rule groups pushed
+	RuleGroups int
+}
+
+// Run restores the Alertmanager configurations, then the rule groups.
+// Returns what was pushed, and the first error, which stops the restore.
+func (r *Restorer) Run(ctx context.Context, logger logr.Logger) (Summary, error) {
+	var summary Summary
+	clientConfigs := &openawarenessv1beta1.ClientConfigList{}
+	if err := r.Reader.List(ctx, clientConfigs); err != nil {
+		return summary, fmt.Errorf("listing ClientConfigs: %w", err)
+	}
+	mappings, err := utils.ListTenantMappings(ctx, r.Reader)
+	if err != nil {
+		return summary, err
+	}
+
+	tenants := &openawarenessv1beta1.MimirAlertTenantList{}
+	if err := r.Reader.List(ctx, tenants); err != nil {
+		return summary, fmt.Errorf("listing MimirAlertTenants: %w", err)
+	}
+	sort.Slice(tenants.Items, func(i, j int) bool {
+		return utils.OwnerReference(&tenants.Items[i]) < utils.OwnerReference(&tenants.Items[j])
+	})
+	for i := range tenants.Items {
+		tenant := &tenants.Items[i]
+		if !r.restored(tenant, clientConfigs.Items, mappings) {
+			continue
+		}
+		clientConfig := utils.ClientConfigFor(tenant, clientConfigs.Items, mappings)
+		if err := r.restoreAlertmanagerConfig(ctx, logger, tenant, clientConfig); err != nil {
+			return summary, fmt.Errorf("MimirAlertTenant %s: %w", utils.OwnerReference(tenant), err)
+		}
+		summary.AlertmanagerConfigs++
+	}
+
+	rules := &monitoringv1.PrometheusRuleList{}
+	if err := r.Reader.List(ctx, rules); err != nil {
+		return summary, fmt.Errorf("listing PrometheusRules: %w", err)
+	}
+	sort.Slice(rules.Items, func(i, j int) bool {
+		return utils.OwnerReference(&rules.Items[i]) < utils.OwnerReference(&rules.Items[j])
+	})
+	for i := range rules.Items {
+		rule := &rules.Items[i]
+		if !r.restored(rule, clientConfigs.Items, mappings) {
+			continue
+		}
+		pushed, err := r.restoreRuleGroups(ctx, rule, utils.ClientConfigFor(rule, clientConfigs.Items, mappings))
+		summary.RuleGroups += pushed
+		if err != nil {
+			return summary, fmt.Errorf("PrometheusRule %s: %w", utils.OwnerReference(rule), err)
+		}
+	}
+	return summary, nil
+}
+
+// restored reports whether obj is synced through the ClientConfig to a restored tenant.
+// Resources synced to a tenant the ClientConfig does not allow are not restored, like the
+// controllers refuse to push them.
+func (r *Restorer) restored(
+	obj client.Object,
+	clientConfigs []openawarenessv1beta1.ClientConfig,
+	mappings *utils.TenantMappings,
+) bool {
+	if utils.ClientNameFor(obj, clientConfigs, mappings) != r.ClientName || utils.IsPaused(obj) ||
+		!obj.GetDeletionTimestamp().IsZero() {
+		return false
+	}
+	clientConfig := utils.ClientConfigFor(obj, clientConfigs, mappings)
+	tenantIDs := utils.TenantIDs(obj, clientConfig)
+	if utils.CheckTenantsAllowed(clientConfig, tenantIDs...) != nil {
+		return false
+	}
+	return r.TenantID == "" || slices.Contains(tenantIDs, r.TenantID)
+}
+
+// restoreAlertmanagerConfig renders and pushes the configuration of a tenant and waits
+// until Mimir returns it. The tenant is resolved through clientConfig.
+func (r *Restorer) restoreAlertmanagerConfig(
+	ctx context.Context,
+	logger logr.Logger,
+	tenant *openawarenessv1beta1.MimirAlertTenant,
+	clientConfig *openawarenessv1beta1.ClientConfig,
+) error {
+	tenantID := utils.GetTenantID(tenant, clientConfig)
+	config, templates, err := utils.RenderAlertmanagerConfig(ctx, r.Reader, logger, tenant,
+		r.GlobalValues, r.ClusterName, r.TemplateSources)
+	if err != nil {
+		return fmt.Errorf("rendering: %w", err)
+	}
+	if err := r.Remote.CreateAlertmanagerConfig(ctx, config, templates, tenantID); err != nil {
+		return fmt.Errorf("pushing Alertmanager configuration for tenant %s: %w", tenantID, err)
+	}
+	err = r.verify(ctx, func(ctx context.Context) (bool, error) {
+		pushed, _, err := r.Remote.GetAlertmanagerConfig(ctx, tenantID)
+		return pushed == config, err
+	})
+	if err != nil {
+		return fmt.Errorf("Alertmanager configuration for tenant %s: %w", tenantID, err)
+	}
+	r.printf("restored Alertmanager configuration of MimirAlertTenant %s in tenant %s\n",
+		utils.OwnerReference(tenant), tenantID)
+	return nil
+}
+
+// restoreRuleGroups renders and pushes the rule groups of a PrometheusRule to each of its
+// restored tenants and waits until Mimir returns every group.
+// The tenants are resolved through clientConfig.
+// Returns the number of pushed groups.
+func (r *Restorer) restoreRuleGroups(
+	ctx context.Context,
+	rule *monitoringv1.PrometheusRule,
+	clientConfig *openawarenessv1beta1.ClientConfig,
+) (int, error) {
+	templated, err := monitoringcoreoscom.ResolveRuleTemplates(ctx, r.Reader, rule)
+	if err != nil {
+		return 0, fmt.Errorf("rendering templates: %w", err)
+	}
+	groups, err := monitoringcoreoscom.DesiredRuleGroups(templated)
+	if err != nil {
+		return 0, fmt.Errorf("converting: %w", err)
+	}
+	labels, err := r.ExtraLabels.For(ctx, rule)
+	if err != nil {
+		return 0, fmt.Errorf("getting extra labels: %w", err)
+	}
+	utils.InjectLabels(groups, labels)
+
+	pushed := 0
+	namespace := utils.RulesNamespace(rule)
+	partitions := monitoringcoreoscom.PartitionRuleGroups(rule, groups, clientConfig)
+	// Validated by DesiredRuleGroups
+	sourceTenants, _ := utils.SourceTenants(rule, clientConfig)
+	for _, tenantID := range utils.TenantIDs(rule, clientConfig) {
+		if r.TenantID != "" && tenantID != r.TenantID {
+			continue
+		}
+		for _, group := range partitions[tenantID] {
+			err := monitoringcoreoscom.PushRuleGroup(ctx, r.Remote, namespace, group, sourceTenants[group.Name], tenantID)
+			if err != nil {
+				return pushed, fmt.Errorf("pushing rule group %s for tenant %s: %w", group.Name, tenantID, err)
+			}
+			err = r.verify(ctx, func(ctx context.Context) (bool, error) {
+				return ruleGroupPushed(ctx, r.Remote, namespace, group, tenantID)
+			})
+			if err != nil {
+				return pushed, fmt.Errorf("rule group %s for tenant %s: %w", group.Name, tenantID, err)
+			}
+			pushed++
+		}
+		if len(partitions[tenantID]) > 0 {
+			r.printf("restored %d rule groups of PrometheusRule %s in tenant %s\n",
+				len(partitions[tenantID]), utils.OwnerReference(rule), tenantID)
+		}
+	}
+	return pushed, nil
+}
+
+// ruleGroupPushed reports whether Mimir returns the rule group with all its rules.
+func ruleGroupPushed(
+	ctx context.Context,
+	remote clients.AwarenessClient,
+	namespace string,
+	group rulefmt.RuleGroup,
+	tenantID string,
+) (bool, error) {
+	pushed, err := remote.GetRuleGroup(ctx, namespace, group.Name, tenantID)
+	if err != nil || pushed == nil {
+		return false, err
+	}
+	return len(pushed.Rules) == len(group.Rules), nil
+}
+
+// verify polls check until it succeeds or the verify timeout expires. Errors of check are
+// retried. Returns an error wrapping ErrNotVerified with the last error on timeout.
+func (r *Restorer) verify(ctx context.Context, check func(ctx context.Context) (bool, error)) error {
+	timeout := r.VerifyTimeout
+	if timeout <= 0 {
+		timeout = DefaultVerifyTimeout
+	}
+	var lastErr error
+	err := wait.PollUntilContextTimeout(ctx, verifyInterval, timeout, true, func(ctx context.Context) (bool, error) {
+		done, err := check(ctx)
+		lastErr = err
+		return done && err == nil, nil
+	})
+	if err == nil {
+		return nil
+	}
+	if lastErr != nil {
+		return fmt.Errorf("%w within %s: %w", ErrNotVerified, timeout, lastErr)
+	}
+	return fmt.Errorf("%w within %s", ErrNotVerified, timeout)
+}
+
+// printf writes a progress line to Out, if set.
+func (r *Restorer) printf(format string, args ...any) {
+	if r.Out != nil {
+		_, _ = fmt.Fprintf(r.Out, format, args...)
+	}
+}
+
+// String describes the restored scope.
+func (r *Restorer) String() string {
+	scope := []string{"ClientConfig " + r.ClientName}
+	if r.TenantID != "" {
+		scope = append(scope, "tenant "+r.TenantID)
+	}
+	return strings.Join(scope, ", ")
+}
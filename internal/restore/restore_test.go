@@ -0,0 +1,141 @@
+package restore
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	openawarenessv1beta1 "github.com/syndlex/openawareness-controller/api/openawareness/v1beta1"
+	"github.com/syndlex/openawareness-controller/internal/clients"
+	"github.com/syndlex/openawareness-controller/internal/controller/utils"
+)
+
+const alertmanagerConfig = `route:
+  receiver: default
+receivers:
+- name: default
+`
+
+func TestRun(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := openawarenessv1beta1.AddToScheme(scheme); err != nil {
+		t.Fatalf("adding scheme: %v", err)
+	}
+	if err := monitoringv1.AddToScheme(scheme); err != nil {
+		t.Fatalf("adding scheme: %v", err)
+	}
+	annotations := func(client, tenant string) map[string]string {
+		return map[string]string{utils.ClientNameAnnotation: client, utils.MimirTenantAnnotation: tenant}
+	}
+	tenant := func(name, client, tenantID string) *openawarenessv1beta1.MimirAlertTenant {
+		return &openawarenessv1beta1.MimirAlertTenant{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "team", Annotations: annotations(client, tenantID)},
+			Spec:       openawarenessv1beta1.MimirAlertTenantSpec{AlertmanagerConfig: alertmanagerConfig},
+		}
+	}
+	rule := &monitoringv1.PrometheusRule{
+		ObjectMeta: metav1.ObjectMeta{Name: "rules", Namespace: "team", Annotations: annotations("mimir", "team-a")},
+		Spec: monitoringv1.PrometheusRuleSpec{Groups: []monitoringv1.RuleGroup{{
+			Name:  "availability",
+			Rules: []monitoringv1.Rule{{Alert: "Down", Expr: intstr.FromString("up == 0")}},
+		}}},
+	}
+	objects := []runtime.Object{
+		tenant("team-a", "mimir", "team-a"),
+		tenant("team-b", "mimir", "team-b"),
+		tenant("other", "other", "team-a"),
+		rule,
+	}
+	ctx := context.Background()
+
+	tests := []struct {
+		name         string
+		tenantID     string
+		wantTenants  []string
+		wantSummary  Summary
+		wantProgress string
+	}{
+		{
+			name:         "all tenants",
+			wantTenants:  []string{"team-a", "team-b"},
+			wantSummary:  Summary{AlertmanagerConfigs: 2, RuleGroups: 1},
+			wantProgress: "restored 1 rule groups of PrometheusRule team/rules in tenant team-a",
+		},
+		{
+			name:        "single tenant",
+			tenantID:    "team-b",
+			wantTenants: []string{"team-b"},
+			wantSummary: Summary{AlertmanagerConfigs: 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			remote := clients.NewMockAwarenessClient()
+			out := &bytes.Buffer{}
+			restorer := &Restorer{
+				Reader:     fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(objects...).Build(),
+				Remote:     remote,
+				ClientName: "mimir",
+				TenantID:   tt.tenantID,
+				Out:        out,
+			}
+			summary, err := restorer.Run(ctx, logr.Discard())
+			if err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+			if summary != tt.wantSummary {
+				t.Errorf("Run() summary = %+v, want %+v", summary, tt.wantSummary)
+			}
+			for _, tenantID := range tt.wantTenants {
+				if config, _, _ := remote.GetAlertmanagerConfig(ctx, tenantID); config == "" {
+					t.Errorf("expected the configuration of tenant %s to be pushed", tenantID)
+				}
+			}
+			if !strings.Contains(out.String(), tt.wantProgress) {
+				t.Errorf("expected the output to contain %q, got %q", tt.wantProgress, out.String())
+			}
+		})
+	}
+}
+
+func TestRunStopsOnPushError(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := openawarenessv1beta1.AddToScheme(scheme); err != nil {
+		t.Fatalf("adding scheme: %v", err)
+	}
+	if err := monitoringv1.AddToScheme(scheme); err != nil {
+		t.Fatalf("adding scheme: %v", err)
+	}
+	tenant := &openawarenessv1beta1.MimirAlertTenant{
+		ObjectMeta: metav1.ObjectMeta{Name: "team-a", Namespace: "team", Annotations: map[string]string{
+			utils.ClientNameAnnotation:  "mimir",
+			utils.MimirTenantAnnotation: "team-a",
+		}},
+		Spec: openawarenessv1beta1.MimirAlertTenantSpec{AlertmanagerConfig: alertmanagerConfig},
+	}
+	remote := clients.NewMockAwarenessClient()
+	remote.SetCreateAlertConfigError(errors.New("unavailable"))
+	restorer := &Restorer{
+		Reader:        fake.NewClientBuilder().WithScheme(scheme).WithObjects(tenant).Build(),
+		Remote:        remote,
+		ClientName:    "mimir",
+		VerifyTimeout: time.Second,
+	}
+	summary, err := restorer.Run(context.Background(), logr.Discard())
+	if err == nil || !strings.Contains(err.Error(), "unavailable") {
+		t.Errorf("Run() error = %v, want the push error", err)
+	}
+	if summary != (Summary{}) {
+		t.Errorf("Run() summary = %+v, want nothing pushed", summary)
+	}
+}
@@ -0,0 +1,206 @@
+// Package slo generates multi-window multi-burn-rate alerting rules from SLO definitions.
+package slo
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"github.com/prometheus/common/model"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	openawarenessv1beta1 "github.com/syndlex/openawareness-controller/api/openawareness/v1beta1"
+)
+
+const (
+	// ServiceLabel identifies the service of an SLO on all generated rules
+	ServiceLabel = "slo_service"
+	// NameLabel identifies the SLO on all generated rules
+	NameLabel = "slo_name"
+
+	// DefaultWindow is the SLO period used when the spec does not set one
+	DefaultWindow = "30d"
+
+	// errorRatioRecord is the recording rule name prefix of the SLI error ratio
+	errorRatioRecord = "slo:sli_error:ratio_rate"
+	// alertName is the name of the generated burn rate alerts
+	alertName = "ErrorBudgetBurn"
+)
+
+// burnRateAlert is one multi-window burn rate alert. The alert fires when the error
+// budget consumed over both windows exceeds BudgetConsumed of the whole SLO window.
+type burnRateAlert struct {
+	Long           time.Duration
+	Short          time.Duration
+	BudgetConsumed float64
+	Severity       string
+}
+
+// burnRateAlerts are the alerts recommended by the Google SRE workbook:
+// page on 2% budget in 1h or 5% in 6h, open a ticket on 10% in 1d or 3d.
+var burnRateAlerts = []burnRateAlert{
+	{Long: time.Hour, Short: 5 * time.Minute, BudgetConsumed: 0.02, Severity: "critical"},
+	{Long: 6 * time.Hour, Short: 30 * time.Minute, BudgetConsumed: 0.05, Severity: "critical"},
+	{Long: 24 * time.Hour, Short: 2 * time.Hour, BudgetConsumed: 0.1, Severity: "warning"},
+	{Long: 72 * time.Hour, Short: 6 * time.Hour, BudgetConsumed: 0.1, Severity: "warning"},
+}
+
+// GenerateRuleGroups returns a recording rule group with the SLI error ratio for every
+// burn rate window and an alerting rule group with the multi-window burn rate alerts.
+// Returns an error if the objective, window or SLI queries are invalid.
+func GenerateRuleGroups(s *openawarenessv1beta1.SLO) ([]monitoringv1.RuleGroup, error) {
+	objective, err := strconv.ParseFloat(s.Spec.Objective, 64)
+	if err != nil || objective <= 0 || objective >= 100 {
+		return nil, fmt.Errorf("objective must be a percentage between 0 and 100, got %q", s.Spec.Objective)
+	}
+
+	windowSpec := s.Spec.Window
+	if windowSpec == "" {
+		windowSpec = DefaultWindow
+	}
+	window, err := model.ParseDuration(windowSpec)
+	if err != nil {
+		return nil, fmt.Errorf("invalid window %q: %w", windowSpec, err)
+	}
+
+	sloLabels := map[string]string{
+		ServiceLabel: s.Spec.Service,
+		NameLabel:    s.Name,
+	}
+
+	recordings := make([]monitoringv1.Rule, 0, 2*len(burnRateAlerts))
+	for _, rateWindow := range rateWindows() {
+		expr, err := errorRatioExpr(s.Spec.SLI, rateWindow)
+		if err != nil {
+			return nil, err
+		}
+		recordings = append(recordings, monitoringv1.Rule{
+			Record: errorRatioRecord + rateWindow,
+			Expr:   intstr.FromString(expr),
+			Labels: copyLabels(sloLabels),
+		})
+	}
+
+	errorBudget := formatFloat((100 - objective) / 100)
+	selector := labelSelector(sloLabels)
+	alerts := make([]monitoringv1.Rule, 0, len(burnRateAlerts))
+	for _, alert := range burnRateAlerts {
+		factor := formatFloat(alert.BudgetConsumed * float64(window) / float64(alert.Long))
+		long := model.Duration(alert.Long).String()
+		short := model.Duration(alert.Short).String()
+
+		labels := copyLabels(s.Spec.Labels)
+		for k, v := range sloLabels {
+			labels[k] = v
+		}
+		labels["severity"] = alert.Severity
+		labels["long_window"] = long
+		labels["short_window"] = short
+
+		alerts = append(alerts, monitoringv1.Rule{
+			Alert: alertName,
+			Expr: intstr.FromString(fmt.Sprintf("(%s%s%s > (%s * %s))\nand\n(%s%s%s > (%s * %s))",
+				errorRatioRecord, long, selector, factor, errorBudget,
+				errorRatioRecord, short, selector, factor, errorBudget)),
+			Labels: labels,
+			Annotations: map[string]string{
+				"summary": fmt.Sprintf("%s is burning its error budget too fast", s.Spec.Service),
+				"description": fmt.Sprintf(
+					"SLO %s (%s%% over %s) burns its error budget %sx faster than allowed over the last %s and %s.",
+					s.Name, s.Spec.Objective, windowSpec, factor, long, short),
+			},
+		})
+	}
+
+	return []monitoringv1.RuleGroup{
+		{Name: fmt.Sprintf("slo-%s-recordings", s.Name), Rules: recordings},
+		{Name: fmt.Sprintf("slo-%s-alerts", s.Name), Rules: alerts},
+	}, nil
+}
+
+// rateWindows returns all distinct windows used by the burn rate alerts, shortest first.
+func rateWindows() []string {
+	durations := map[time.Duration]struct{}{}
+	for _, alert := range burnRateAlerts {
+		durations[alert.Long] = struct{}{}
+		durations[alert.Short] = struct{}{}
+	}
+
+	sorted := make([]time.Duration, 0, len(durations))
+	for d := range durations {
+		sorted = append(sorted, d)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	windows := make([]string, 0, len(sorted))
+	for _, d := range sorted {
+		windows = append(windows, model.Duration(d).String())
+	}
+	return windows
+}
+
+// errorRatioExpr renders the SLI queries for the given rate window and divides them.
+func errorRatioExpr(sli openawarenessv1beta1.SLI, window string) (string, error) {
+	errorQuery, err := renderQuery(sli.ErrorQuery, window)
+	if err != nil {
+		return "", fmt.Errorf("invalid errorQuery: %w", err)
+	}
+	totalQuery, err := renderQuery(sli.TotalQuery, window)
+	if err != nil {
+		return "", fmt.Errorf("invalid totalQuery: %w", err)
+	}
+	return fmt.Sprintf("(%s)\n/\n(%s)", errorQuery, totalQuery), nil
+}
+
+// renderQuery replaces the {{ .window }} template variable in a query.
+func renderQuery(query, window string) (string, error) {
+	if strings.TrimSpace(query) == "" {
+		return "", fmt.Errorf("query is empty")
+	}
+
+	tmpl, err := template.New("query").Option("missingkey=error").Parse(query)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, map[string]string{"window": window}); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
+
+// labelSelector renders labels as a PromQL selector with sorted label names.
+func labelSelector(labels map[string]string) string {
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	matchers := make([]string, 0, len(names))
+	for _, name := range names {
+		matchers = append(matchers, fmt.Sprintf("%s=%q", name, labels[name]))
+	}
+	return "{" + strings.Join(matchers, ",") + "}"
+}
+
+// formatFloat formats a ratio without floating point noise (e.g. 0.0009999999 becomes 0.001).
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(math.Round(value*1e9)/1e9, 'f', -1, 64)
+}
+
+// copyLabels returns a copy of labels that is safe to modify.
+func copyLabels(labels map[string]string) map[string]string {
+	copied := make(map[string]string, len(labels))
+	for k, v := range labels {
+		copied[k] = v
+	}
+	return copied
+}
@@ -0,0 +1,103 @@
+package slo
+
+import (
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	openawarenessv1beta1 "github.com/syndlex/openawareness-controller/api/openawareness/v1beta1"
+)
+
+func newSLO(objective, window string) *openawarenessv1beta1.SLO {
+	return &openawarenessv1beta1.SLO{
+		ObjectMeta: metav1.ObjectMeta{Name: "api-availability", Namespace: "default"},
+		Spec: openawarenessv1beta1.SLOSpec{
+			Service:   "api",
+			Objective: objective,
+			Window:    window,
+			SLI: openawarenessv1beta1.SLI{
+				ErrorQuery: `sum(rate(http_requests_total{job="api",code=~"5.."}[{{ .window }}]))`,
+				TotalQuery: `sum(rate(http_requests_total{job="api"}[{{ .window }}]))`,
+			},
+			Labels: map[string]string{"team": "platform"},
+		},
+	}
+}
+
+func TestGenerateRuleGroups(t *testing.T) {
+	groups, err := GenerateRuleGroups(newSLO("99.9", ""))
+	if err != nil {
+		t.Fatalf("GenerateRuleGroups: %v", err)
+	}
+	if len(groups) != 2 {
+		t.Fatalf("expected a recording and an alerting group, got %d groups", len(groups))
+	}
+
+	recordings := groups[0].Rules
+	wantWindows := []string{"5m", "30m", "1h", "2h", "6h", "1d", "3d"}
+	if len(recordings) != len(wantWindows) {
+		t.Fatalf("expected %d recording rules, got %d", len(wantWindows), len(recordings))
+	}
+	for i, window := range wantWindows {
+		if recordings[i].Record != errorRatioRecord+window {
+			t.Errorf("recording %d = %s, want %s", i, recordings[i].Record, errorRatioRecord+window)
+		}
+		if !strings.Contains(recordings[i].Expr.String(), "["+window+"]") {
+			t.Errorf("recording %s does not use its window: %s", recordings[i].Record, recordings[i].Expr.String())
+		}
+	}
+
+	alerts := groups[1].Rules
+	wantFactors := []string{"14.4", "6", "3", "1"}
+	if len(alerts) != len(wantFactors) {
+		t.Fatalf("expected %d alerts, got %d", len(wantFactors), len(alerts))
+	}
+	for i, factor := range wantFactors {
+		if !strings.Contains(alerts[i].Expr.String(), "("+factor+" * 0.001)") {
+			t.Errorf("alert %d does not use burn rate %s: %s", i, factor, alerts[i].Expr.String())
+		}
+		if alerts[i].Labels["team"] != "platform" || alerts[i].Labels[ServiceLabel] != "api" {
+			t.Errorf("alert %d is missing SLO labels: %v", i, alerts[i].Labels)
+		}
+	}
+	if alerts[0].Labels["severity"] != "critical" || alerts[3].Labels["severity"] != "warning" {
+		t.Errorf("unexpected severities: %s, %s", alerts[0].Labels["severity"], alerts[3].Labels["severity"])
+	}
+}
+
+func TestGenerateRuleGroupsScalesWithWindow(t *testing.T) {
+	groups, err := GenerateRuleGroups(newSLO("99", "7d"))
+	if err != nil {
+		t.Fatalf("GenerateRuleGroups: %v", err)
+	}
+	// 2% of a 7d budget within 1h is a burn rate of 3.36
+	if expr := groups[1].Rules[0].Expr.String(); !strings.Contains(expr, "(3.36 * 0.01)") {
+		t.Errorf("expected burn rate scaled to the 7d window, got %s", expr)
+	}
+}
+
+func TestGenerateRuleGroupsInvalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*openawarenessv1beta1.SLO)
+	}{
+		{name: "objective not a number", modify: func(s *openawarenessv1beta1.SLO) { s.Spec.Objective = "high" }},
+		{name: "objective of 100", modify: func(s *openawarenessv1beta1.SLO) { s.Spec.Objective = "100" }},
+		{name: "invalid window", modify: func(s *openawarenessv1beta1.SLO) { s.Spec.Window = "a month" }},
+		{name: "empty error query", modify: func(s *openawarenessv1beta1.SLO) { s.Spec.SLI.ErrorQuery = "" }},
+		{name: "unknown template variable", modify: func(s *openawarenessv1beta1.SLO) {
+			s.Spec.SLI.TotalQuery = "sum(rate(x[{{ .interval }}]))"
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newSLO("99.9", "")
+			tt.modify(s)
+			if _, err := GenerateRuleGroups(s); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
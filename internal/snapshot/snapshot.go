@@ -0,0 +1,139 @@
+// Package snapshot captures the rule groups and Alertmanager configuration of a Mimir tenant as
+// stored in Mimir, e.g. for audits or as a backup before a change, and uploads them to object
+// storage.
+package snapshot
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/prometheus/prometheus/model/rulefmt"
+	"gopkg.in/yaml.v3"
+
+	"github.com/syndlex/openawareness-controller/internal/clients"
+	"github.com/syndlex/openawareness-controller/internal/mimir"
+)
+
+// Key is the ConfigMap and Secret key holding a snapshot
+const Key = "snapshot.yaml"
+
+// Snapshot is the state of a tenant captured from Mimir.
+type Snapshot struct {
+	// ClientConfig is the ClientConfig the tenant was read through as namespace/name
+	ClientConfig string    `yaml:"clientConfig"`
+	Tenant       string    `yaml:"tenant"`
+	CapturedAt   time.Time `yaml:"capturedAt"`
+	// Alertmanager is the Alertmanager configuration, nil if the tenant is served the fallback
+	// configuration of Mimir
+	Alertmanager *Alertmanager `yaml:"alertmanager,omitempty"`
+	// Rules are the rule groups of the tenant by Mimir rule namespace
+	Rules map[string][]rulefmt.RuleGroup `yaml:"rules,omitempty"`
+}
+
+// Alertmanager is the captured Alertmanager configuration and template files of a tenant.
+type Alertmanager struct {
+	Config    string            `yaml:"config"`
+	Templates map[string]string `yaml:"templates,omitempty"`
+}
+
+// Capture reads the rule groups of every rule namespace and the Alertmanager configuration of
+// the tenant through remote. clientConfig identifies the ClientConfig of remote in the snapshot.
+// Returns an error if either cannot be read.
+func Capture(ctx context.Context, remote clients.AwarenessClient, clientConfig, tenantID string) (*Snapshot, error) {
+	snapshot := &Snapshot{
+		ClientConfig: clientConfig,
+		Tenant:       tenantID,
+		CapturedAt:   time.Now().UTC(),
+		Rules:        map[string][]rulefmt.RuleGroup{},
+	}
+	err := remote.WalkRules(ctx, tenantID, func(namespace string, groups []rulefmt.RuleGroup) error {
+		snapshot.Rules[namespace] = append(snapshot.Rules[namespace], slices.Clone(groups)...)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("reading rule groups of tenant %s: %w", tenantID, err)
+	}
+	config, templates, err := remote.GetAlertmanagerConfig(ctx, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("reading Alertmanager configuration of tenant %s: %w", tenantID, err)
+	}
+	if !mimir.UsesFallbackConfig(config) {
+		snapshot.Alertmanager = &Alertmanager{Config: config, Templates: templates}
+	}
+	return snapshot, nil
+}
+
+// RuleGroups returns the number of captured rule groups.
+func (s *Snapshot) RuleGroups() int {
+	count := 0
+	for _, groups := range s.Rules {
+		count += len(groups)
+	}
+	return count
+}
+
+// Marshal returns the snapshot as YAML.
+func (s *Snapshot) Marshal() ([]byte, error) {
+	return yaml.Marshal(s)
+}
+
+// Credentials authenticate an upload: Token as bearer token, otherwise Username and Password
+// with basic authentication if set.
+type Credentials struct {
+	Token    string
+	Username string
+	Password string
+}
+
+// Upload writes data to target in an HTTP PUT request, e.g. to a presigned object storage URL.
+// Returns an error if the request fails or is not answered with a 2xx status.
+func Upload(ctx context.Context, client *http.Client, target string, data []byte, credentials Credentials) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, target, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/yaml")
+	switch {
+	case credentials.Token != "":
+		req.Header.Set("Authorization", "Bearer "+credentials.Token)
+	case credentials.Username != "" || credentials.Password != "":
+		req.SetBasicAuth(credentials.Username, credentials.Password)
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		// url.Error repeats the URL including its query
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("uploading snapshot to %s: %w", RedactURL(target), err)
+	}
+	defer func() { _ = res.Body.Close() }()
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(res.Body, 512))
+		return fmt.Errorf("uploading snapshot to %s failed with status %s: %s",
+			RedactURL(target), res.Status, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+// RedactURL returns target without user info and query, which may hold credentials such as the
+// signature of a presigned URL.
+func RedactURL(target string) string {
+	parsed, err := url.Parse(target)
+	if err != nil {
+		return ""
+	}
+	parsed.User = nil
+	parsed.RawQuery = ""
+	parsed.Fragment = ""
+	return parsed.String()
+}
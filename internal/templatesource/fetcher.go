@@ -0,0 +1,318 @@
+// Package templatesource fetches Alertmanager notification templates of MimirAlertTenants from
+// OCI artifacts and Git repositories.
+package templatesource
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/go-git/go-git/v5/storage/memory"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	corev1 "k8s.io/api/core/v1"
+	"oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/registry/remote"
+	"oras.land/oras-go/v2/registry/remote/auth"
+	"oras.land/oras-go/v2/registry/remote/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	openawarenessv1beta1 "github.com/syndlex/openawareness-controller/api/openawareness/v1beta1"
+	"github.com/syndlex/openawareness-controller/internal/controller/utils"
+)
+
+// DefaultRefreshInterval is the default time fetched files are served before they are fetched again
+const DefaultRefreshInterval = 5 * time.Minute
+
+// MaxSize bounds the total size of the template files of a source
+const MaxSize = 1 << 20
+
+const (
+	// UsernameKey is the key of the username in the credentials Secret
+	UsernameKey = "username"
+	// PasswordKey is the key of the password or token in the credentials Secret
+	PasswordKey = "password"
+)
+
+// ErrTooLarge is returned when the template files of a source exceed MaxSize
+var ErrTooLarge = errors.New("template files exceed the maximum size")
+
+// Fetcher fetches the template files of templateSources and caches them for RefreshInterval,
+// so tenants sharing a source fetch it once. If fetching fails, the files fetched last are
+// served until the source can be fetched again.
+type Fetcher struct {
+	// Reader reads the credential Secrets
+	Reader client.Reader
+	// RefreshInterval is the time fetched files are served from the cache, DefaultRefreshInterval if zero
+	RefreshInterval time.Duration
+
+	mu    sync.Mutex
+	cache map[string]cachedFiles
+}
+
+// cachedFiles are the template files of a source and the time they were fetched
+type cachedFiles struct {
+	files     map[string]string
+	fetchedAt time.Time
+}
+
+// Ensure Fetcher can be used by the controllers
+var _ utils.TemplateSourceFetcher = (*Fetcher)(nil)
+
+// Fetch returns the template files of source, reading its credentials in namespace.
+func (f *Fetcher) Fetch(
+	ctx context.Context,
+	namespace string,
+	source *openawarenessv1beta1.TemplateSource,
+) (map[string]string, error) {
+	key, err := cacheKey(namespace, source)
+	if err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	cached, ok := f.cache[key]
+	f.mu.Unlock()
+	if ok && time.Since(cached.fetchedAt) < f.refreshInterval() {
+		return cached.files, nil
+	}
+
+	files, err := f.fetch(ctx, namespace, source)
+	if err != nil {
+		if ok {
+			log.FromContext(ctx).Info("Failed to refresh template source, serving the files fetched last",
+				"source", describe(source),
+				"fetchedAt", cached.fetchedAt,
+				"error", err.Error())
+			return cached.files, nil
+		}
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.cache == nil {
+		f.cache = map[string]cachedFiles{}
+	}
+	f.cache[key] = cachedFiles{files: files, fetchedAt: time.Now()}
+	return files, nil
+}
+
+// refreshInterval returns RefreshInterval, DefaultRefreshInterval if not positive.
+func (f *Fetcher) refreshInterval() time.Duration {
+	if f.RefreshInterval <= 0 {
+		return DefaultRefreshInterval
+	}
+	return f.RefreshInterval
+}
+
+// fetch fetches the template files of source without cache.
+func (f *Fetcher) fetch(
+	ctx context.Context,
+	namespace string,
+	source *openawarenessv1beta1.TemplateSource,
+) (map[string]string, error) {
+	username, password, err := f.credentials(ctx, namespace, source.SecretRef)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case source.OCI != nil:
+		return fetchOCI(ctx, source.OCI, username, password)
+	case source.Git != nil:
+		return fetchGit(ctx, source.Git, username, password)
+	default:
+		return nil, errors.New("neither oci nor git is set")
+	}
+}
+
+// credentials reads the username and password of the Secret in namespace, empty without Secret.
+func (f *Fetcher) credentials(
+	ctx context.Context,
+	namespace string,
+	ref *corev1.LocalObjectReference,
+) (string, string, error) {
+	if ref == nil {
+		return "", "", nil
+	}
+	secret := &corev1.Secret{}
+	if err := f.Reader.Get(ctx, client.ObjectKey{Namespace: namespace, Name: ref.Name}, secret); err != nil {
+		return "", "", fmt.Errorf("failed to get credentials Secret %s: %w", ref.Name, err)
+	}
+	return string(secret.Data[UsernameKey]), string(secret.Data[PasswordKey]), nil
+}
+
+// fetchOCI pulls the manifest of the artifact and returns its titled layers as files.
+func fetchOCI(
+	ctx context.Context,
+	source *openawarenessv1beta1.OCITemplateSource,
+	username, password string,
+) (map[string]string, error) {
+	repo, err := remote.NewRepository(source.Reference)
+	if err != nil {
+		return nil, fmt.Errorf("invalid OCI reference %s: %w", source.Reference, err)
+	}
+	if repo.Reference.Reference == "" {
+		return nil, fmt.Errorf("OCI reference %s has no tag or digest", source.Reference)
+	}
+	repo.PlainHTTP = source.Insecure
+	authClient := &auth.Client{Client: retry.DefaultClient, Cache: auth.NewCache()}
+	if username != "" || password != "" {
+		authClient.Credential = auth.StaticCredential(repo.Reference.Registry, auth.Credential{
+			Username: username,
+			Password: password,
+		})
+	}
+	repo.Client = authClient
+
+	_, manifestContent, err := oras.FetchBytes(ctx, repo, repo.Reference.Reference, oras.DefaultFetchBytesOptions)
+	if err != nil {
+		return nil, fmt.Errorf("fetching OCI manifest %s: %w", source.Reference, err)
+	}
+	var manifest ocispec.Manifest
+	if err := json.Unmarshal(manifestContent, &manifest); err != nil {
+		return nil, fmt.Errorf("invalid OCI manifest %s: %w", source.Reference, err)
+	}
+
+	files := map[string]string{}
+	var size int64
+	for _, layer := range manifest.Layers {
+		name := path.Base(layer.Annotations[ocispec.AnnotationTitle])
+		if layer.Annotations[ocispec.AnnotationTitle] == "" || name == "." || name == "/" {
+			continue
+		}
+		if size += layer.Size; size > MaxSize {
+			return nil, fmt.Errorf("%w of %d bytes in %s", ErrTooLarge, MaxSize, source.Reference)
+		}
+		data, err := content.FetchAll(ctx, repo, layer)
+		if err != nil {
+			return nil, fmt.Errorf("fetching layer %s of %s: %w", name, source.Reference, err)
+		}
+		files[name] = string(data)
+	}
+	return files, nil
+}
+
+// fetchGit clones the ref of the repository without history and returns the files of the directory.
+func fetchGit(
+	ctx context.Context,
+	source *openawarenessv1beta1.GitTemplateSource,
+	username, password string,
+) (map[string]string, error) {
+	options := &git.CloneOptions{
+		URL:          source.URL,
+		SingleBranch: true,
+		Depth:        1,
+		Tags:         git.NoTags,
+	}
+	if username != "" || password != "" {
+		options.Auth = &githttp.BasicAuth{Username: username, Password: password}
+	}
+
+	var repo *git.Repository
+	var err error
+	for _, ref := range candidateRefs(source.Ref) {
+		options.ReferenceName = ref
+		repo, err = git.CloneContext(ctx, memory.NewStorage(), nil, options)
+		if !errors.Is(err, git.NoMatchingRefSpecError{}) {
+			break
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cloning %s: %w", source.URL, err)
+	}
+
+	head, err := repo.Head()
+	if err != nil {
+		return nil, fmt.Errorf("resolving %s of %s: %w", source.Ref, source.URL, err)
+	}
+	commit, err := repo.CommitObject(head.Hash())
+	if err != nil {
+		return nil, fmt.Errorf("reading commit %s of %s: %w", head.Hash(), source.URL, err)
+	}
+	tree, err := commit.Tree()
+	if err == nil && source.Path != "" && source.Path != "." && source.Path != "/" {
+		tree, err = tree.Tree(path.Clean(source.Path))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading directory %q of %s: %w", source.Path, source.URL, err)
+	}
+	return treeFiles(tree, source.URL)
+}
+
+// candidateRefs returns the references ref may name: the ref itself if fully qualified,
+// otherwise a branch or a tag of that name. An empty ref is the default branch.
+func candidateRefs(ref string) []plumbing.ReferenceName {
+	switch {
+	case ref == "":
+		return []plumbing.ReferenceName{""}
+	case plumbing.ReferenceName(ref).IsBranch() || plumbing.ReferenceName(ref).IsTag():
+		return []plumbing.ReferenceName{plumbing.ReferenceName(ref)}
+	default:
+		return []plumbing.ReferenceName{plumbing.NewBranchReferenceName(ref), plumbing.NewTagReferenceName(ref)}
+	}
+}
+
+// treeFiles returns the contents of the regular files of a tree, not descending into subtrees.
+func treeFiles(tree *object.Tree, url string) (map[string]string, error) {
+	files := map[string]string{}
+	var size int64
+	for _, entry := range tree.Entries {
+		if !entry.Mode.IsFile() {
+			continue
+		}
+		file, err := tree.TreeEntryFile(&entry)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s of %s: %w", entry.Name, url, err)
+		}
+		if size += file.Size; size > MaxSize {
+			return nil, fmt.Errorf("%w of %d bytes in %s", ErrTooLarge, MaxSize, url)
+		}
+		reader, err := file.Reader()
+		if err != nil {
+			return nil, fmt.Errorf("reading %s of %s: %w", entry.Name, url, err)
+		}
+		data, err := io.ReadAll(reader)
+		_ = reader.Close()
+		if err != nil {
+			return nil, fmt.Errorf("reading %s of %s: %w", entry.Name, url, err)
+		}
+		files[entry.Name] = string(data)
+	}
+	return files, nil
+}
+
+// cacheKey identifies a source and the credentials it is fetched with.
+func cacheKey(namespace string, source *openawarenessv1beta1.TemplateSource) (string, error) {
+	key := struct {
+		Source    *openawarenessv1beta1.TemplateSource
+		Namespace string
+	}{Source: source}
+	// Credentials are namespaced, sources without credentials are shared by all namespaces
+	if source.SecretRef != nil {
+		key.Namespace = namespace
+	}
+	encoded, err := json.Marshal(key)
+	return string(encoded), err
+}
+
+// describe returns the OCI reference or Git URL and ref of a source for logs.
+func describe(source *openawarenessv1beta1.TemplateSource) string {
+	switch {
+	case source.OCI != nil:
+		return source.OCI.Reference
+	case source.Git != nil:
+		return source.Git.URL + "@" + source.Git.Ref
+	default:
+		return ""
+	}
+}
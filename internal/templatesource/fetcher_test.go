@@ -0,0 +1,184 @@
+package templatesource
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	openawarenessv1beta1 "github.com/syndlex/openawareness-controller/api/openawareness/v1beta1"
+)
+
+// gitRepository creates a repository with the files committed on branch main and tagged v1.
+func gitRepository(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	repo, err := git.PlainInit(dir, false)
+	if err != nil {
+		t.Fatalf("initializing repository: %v", err)
+	}
+	worktree, err := repo.Worktree()
+	if err != nil {
+		t.Fatalf("getting worktree: %v", err)
+	}
+	for name, content := range files {
+		file := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+			t.Fatalf("creating directory: %v", err)
+		}
+		if err := os.WriteFile(file, []byte(content), 0o600); err != nil {
+			t.Fatalf("writing file: %v", err)
+		}
+		if _, err := worktree.Add(name); err != nil {
+			t.Fatalf("adding file: %v", err)
+		}
+	}
+	commit, err := worktree.Commit("templates", &git.CommitOptions{
+		Author: &object.Signature{Name: "test", Email: "test@example.org", When: time.Now()},
+	})
+	if err != nil {
+		t.Fatalf("committing: %v", err)
+	}
+	if _, err := repo.CreateTag("v1", commit, nil); err != nil {
+		t.Fatalf("tagging: %v", err)
+	}
+	return dir
+}
+
+func TestFetchGit(t *testing.T) {
+	dir := gitRepository(t, map[string]string{
+		"README.md":               "templates",
+		"alerting/slack.tmpl":     `{{ define "slack.title" }}{{ .Status }}{{ end }}`,
+		"alerting/email.tmpl":     `{{ define "email.subject" }}{{ .Status }}{{ end }}`,
+		"alerting/nested/ignored": "ignored",
+	})
+	fetcher := &Fetcher{}
+	ctx := context.Background()
+
+	for _, ref := range []string{"", "master", "v1", "refs/tags/v1"} {
+		files, err := fetcher.fetch(ctx, "team", &openawarenessv1beta1.TemplateSource{
+			Git: &openawarenessv1beta1.GitTemplateSource{URL: dir, Ref: ref, Path: "alerting"},
+		})
+		if err != nil {
+			t.Fatalf("fetch(%q) error = %v", ref, err)
+		}
+		want := map[string]string{
+			"slack.tmpl": `{{ define "slack.title" }}{{ .Status }}{{ end }}`,
+			"email.tmpl": `{{ define "email.subject" }}{{ .Status }}{{ end }}`,
+		}
+		if !reflect.DeepEqual(files, want) {
+			t.Errorf("fetch(%q) = %v, want %v", ref, files, want)
+		}
+	}
+
+	_, err := fetcher.fetch(ctx, "team", &openawarenessv1beta1.TemplateSource{
+		Git: &openawarenessv1beta1.GitTemplateSource{URL: dir, Ref: "missing"},
+	})
+	if err == nil {
+		t.Errorf("expected an error for a missing ref")
+	}
+}
+
+// registry serves an OCI artifact with the files as titled layers under repository/name:tag.
+func registry(t *testing.T, files map[string]string, requests *atomic.Int32) *httptest.Server {
+	t.Helper()
+	blobs := map[digest.Digest][]byte{}
+	manifest := ocispec.Manifest{
+		MediaType: ocispec.MediaTypeImageManifest,
+		Config:    ocispec.DescriptorEmptyJSON,
+	}
+	manifest.SchemaVersion = 2
+	blobs[ocispec.DescriptorEmptyJSON.Digest] = ocispec.DescriptorEmptyJSON.Data
+	for name, content := range files {
+		layer := ocispec.Descriptor{
+			MediaType:   "text/plain",
+			Digest:      digest.FromString(content),
+			Size:        int64(len(content)),
+			Annotations: map[string]string{ocispec.AnnotationTitle: name},
+		}
+		blobs[layer.Digest] = []byte(content)
+		manifest.Layers = append(manifest.Layers, layer)
+	}
+	manifestContent, err := json.Marshal(manifest)
+	if err != nil {
+		t.Fatalf("encoding manifest: %v", err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		switch {
+		case r.URL.Path == "/v2/alerting/templates/manifests/v1":
+			w.Header().Set("Content-Type", ocispec.MediaTypeImageManifest)
+			w.Header().Set("Docker-Content-Digest", fmt.Sprintf("sha256:%x", sha256.Sum256(manifestContent)))
+			_, _ = w.Write(manifestContent)
+		case strings.HasPrefix(r.URL.Path, "/v2/alerting/templates/blobs/"):
+			blob, ok := blobs[digest.Digest(strings.TrimPrefix(r.URL.Path, "/v2/alerting/templates/blobs/"))]
+			if !ok {
+				http.NotFound(w, r)
+				return
+			}
+			_, _ = w.Write(blob)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestFetchOCI(t *testing.T) {
+	files := map[string]string{"slack.tmpl": `{{ define "slack.title" }}{{ .Status }}{{ end }}`}
+	var requests atomic.Int32
+	server := registry(t, files, &requests)
+	source := &openawarenessv1beta1.TemplateSource{OCI: &openawarenessv1beta1.OCITemplateSource{
+		Reference: strings.TrimPrefix(server.URL, "http://") + "/alerting/templates:v1",
+		Insecure:  true,
+	}}
+	fetcher := &Fetcher{Reader: fake.NewClientBuilder().Build(), RefreshInterval: time.Hour}
+	ctx := context.Background()
+
+	fetched, err := fetcher.Fetch(ctx, "team", source)
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+	if !reflect.DeepEqual(fetched, files) {
+		t.Errorf("Fetch() = %v, want %v", fetched, files)
+	}
+
+	fetchRequests := requests.Load()
+	if _, err := fetcher.Fetch(ctx, "other", source); err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+	if requests.Load() != fetchRequests {
+		t.Errorf("expected a source without credentials to be served from the cache for every namespace")
+	}
+
+	// The files fetched last are served while the source is unavailable
+	fetcher.RefreshInterval = time.Nanosecond
+	server.Close()
+	fetched, err = fetcher.Fetch(ctx, "team", source)
+	if err != nil || !reflect.DeepEqual(fetched, files) {
+		t.Errorf("Fetch() = %v, %v, want the cached files", fetched, err)
+	}
+
+	if _, err := fetcher.Fetch(ctx, "team", &openawarenessv1beta1.TemplateSource{
+		OCI: &openawarenessv1beta1.OCITemplateSource{Reference: "registry.example.com/alerting/templates"},
+	}); err == nil {
+		t.Errorf("expected an error for a reference without tag")
+	}
+}
@@ -0,0 +1,11 @@
+// Package testing provides helpers to test against the openawareness controller: creating
+// its resources, waiting for their status, and verifying the state pushed to Mimir, either a
+// real instance or the in-process FakeMimir.
+//
+// Unlike the e2e helpers of this repository, the package does not depend on Ginkgo or Gomega.
+// Waiting helpers poll until the expected state is reached and return an error on timeout, so
+// they can be used from plain go tests, other test frameworks and CI tools.
+//
+// The package is part of the public API of the module and follows its semantic versioning:
+// exported identifiers are only changed incompatibly with a new major version.
+package testing
@@ -0,0 +1,380 @@
+package testing
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/grafana/dskit/user"
+	"github.com/prometheus/prometheus/model/rulefmt"
+	"gopkg.in/yaml.v3"
+)
+
+// Paths of the Mimir API served by FakeMimir.
+const (
+	// RulerAPIPath is the path of the ruler configuration API
+	RulerAPIPath = "/prometheus/config/v1/rules"
+	// AlertmanagerAPIPath is the path of the Alertmanager configuration API
+	AlertmanagerAPIPath = "/api/v1/alerts"
+	// AlertmanagerStatusPath is the path of the status page of the multi-tenant Alertmanager
+	AlertmanagerStatusPath = "/multitenant_alertmanager/status"
+)
+
+// Request is a request served by FakeMimir.
+type Request struct {
+	// Method is the HTTP method of the request
+	Method string
+	// Path is the unescaped path of the request
+	Path string
+	// TenantID is the tenant of the request, from its X-Scope-OrgID header
+	TenantID string
+}
+
+// alertmanagerConfig is the Alertmanager configuration of a tenant as sent and returned by
+// the Alertmanager configuration API.
+type alertmanagerConfig struct {
+	TemplateFiles      map[string]string `yaml:"template_files"`
+	AlertmanagerConfig string            `yaml:"alertmanager_config"`
+}
+
+// FakeMimir is an in-process HTTP server implementing the parts of the Mimir API used by the
+// controller: the ruler configuration API, the Alertmanager configuration API and the
+// Alertmanager status page. State is kept in memory per tenant, as given by the X-Scope-OrgID
+// header. Requests are not authenticated and configurations are not validated.
+//
+// Point a ClientConfig at URL to run the controller against it. Listing all rule groups of
+// a tenant always succeeds, so the health check of the controller passes.
+//
+// Failures are injected with FailWith, FailNext and SetLatency, and changes made outside of
+// the controller, e.g. to test drift detection, with SetRuleGroup and SetAlertmanagerConfig.
+type FakeMimir struct {
+	// URL is the base URL of the server
+	URL string
+
+	server *httptest.Server
+
+	mu sync.Mutex
+	// ruleGroups holds the rule groups by tenant and namespace
+	ruleGroups map[string]map[string][]rulefmt.RuleGroup
+	// alertmanagerConfigs holds the Alertmanager configuration by tenant
+	alertmanagerConfigs map[string]alertmanagerConfig
+	// requests records the served requests in order
+	requests []Request
+	// failStatus, if not zero, is returned for all requests with failBody
+	failStatus int
+	failBody   string
+	// failNext is the number of following requests failed with failNextStatus and failNextBody
+	failNext       int
+	failNextStatus int
+	failNextBody   string
+	// latency delays every response
+	latency time.Duration
+}
+
+// NewFakeMimir starts a FakeMimir. Call Close to stop it.
+func NewFakeMimir() *FakeMimir {
+	m := &FakeMimir{
+		ruleGroups:          map[string]map[string][]rulefmt.RuleGroup{},
+		alertmanagerConfigs: map[string]alertmanagerConfig{},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET "+RulerAPIPath, m.listRuleGroups)
+	mux.HandleFunc("GET "+RulerAPIPath+"/{namespace}", m.listRuleGroups)
+	mux.HandleFunc("POST "+RulerAPIPath+"/{namespace}", m.setRuleGroup)
+	mux.HandleFunc("DELETE "+RulerAPIPath+"/{namespace}", m.deleteRuleNamespace)
+	mux.HandleFunc("GET "+RulerAPIPath+"/{namespace}/{group}", m.getRuleGroup)
+	mux.HandleFunc("DELETE "+RulerAPIPath+"/{namespace}/{group}", m.deleteRuleGroup)
+	mux.HandleFunc("GET "+AlertmanagerAPIPath, m.getAlertmanagerConfig)
+	mux.HandleFunc("POST "+AlertmanagerAPIPath, m.setAlertmanagerConfig)
+	mux.HandleFunc("DELETE "+AlertmanagerAPIPath, m.deleteAlertmanagerConfig)
+	mux.HandleFunc("GET "+AlertmanagerStatusPath, m.getAlertmanagerStatus)
+
+	m.server = httptest.NewServer(m.failing(mux))
+	m.URL = m.server.URL
+	return m
+}
+
+// Close stops the server.
+func (m *FakeMimir) Close() {
+	m.server.Close()
+}
+
+// FailWith makes all following requests fail with the status code and body, e.g. to test
+// how errors are reported. A zero status code serves requests again.
+func (m *FakeMimir) FailWith(status int, body string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.failStatus, m.failBody = status, body
+}
+
+// FailNext makes the next n requests fail with the status code and body, e.g. 429 Too Many
+// Requests or 503 Service Unavailable to test retries. It takes precedence over FailWith.
+func (m *FakeMimir) FailNext(n, status int, body string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.failNext, m.failNextStatus, m.failNextBody = n, status, body
+}
+
+// SetLatency delays every following response by latency, e.g. to test timeouts. Requests
+// canceled by the client while they are delayed are not served.
+func (m *FakeMimir) SetLatency(latency time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.latency = latency
+}
+
+// Requests returns the requests served so far, including failed ones, in order.
+func (m *FakeMimir) Requests() []Request {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Request(nil), m.requests...)
+}
+
+// SetRuleGroup creates or replaces the rule group in the namespace of the tenant, as if it was
+// changed outside of the controller.
+func (m *FakeMimir) SetRuleGroup(tenantID, namespace string, group rulefmt.RuleGroup) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.storeRuleGroup(tenantID, namespace, group)
+}
+
+// SetAlertmanagerConfig replaces the Alertmanager configuration of the tenant, as if it was
+// changed outside of the controller.
+func (m *FakeMimir) SetAlertmanagerConfig(tenantID, config string, templates map[string]string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.alertmanagerConfigs[tenantID] = alertmanagerConfig{TemplateFiles: templates, AlertmanagerConfig: config}
+}
+
+// RuleGroups returns the rule groups of the namespace of the tenant, sorted by name.
+func (m *FakeMimir) RuleGroups(tenantID, namespace string) []rulefmt.RuleGroup {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]rulefmt.RuleGroup(nil), m.ruleGroups[tenantID][namespace]...)
+}
+
+// AlertmanagerConfig returns the Alertmanager configuration and template files of the tenant,
+// and whether the tenant has one.
+func (m *FakeMimir) AlertmanagerConfig(tenantID string) (string, map[string]string, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	config, ok := m.alertmanagerConfigs[tenantID]
+	return config.AlertmanagerConfig, config.TemplateFiles, ok
+}
+
+// failing wraps next to record requests, and to delay and fail them as configured by
+// SetLatency, FailNext and FailWith.
+func (m *FakeMimir) failing(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		m.mu.Lock()
+		m.requests = append(m.requests, Request{
+			Method:   r.Method,
+			Path:     r.URL.Path,
+			TenantID: r.Header.Get(user.OrgIDHeaderName),
+		})
+		status, body, latency := m.failStatus, m.failBody, m.latency
+		if m.failNext > 0 {
+			m.failNext--
+			status, body = m.failNextStatus, m.failNextBody
+		}
+		m.mu.Unlock()
+
+		if latency > 0 {
+			select {
+			case <-time.After(latency):
+			case <-r.Context().Done():
+				return
+			}
+		}
+		if status != 0 {
+			http.Error(w, body, status)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (m *FakeMimir) listRuleGroups(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Header.Get(user.OrgIDHeaderName)
+	namespace := r.PathValue("namespace")
+
+	m.mu.Lock()
+	ruleSet := map[string][]rulefmt.RuleGroup{}
+	for ns, groups := range m.ruleGroups[tenantID] {
+		if namespace == "" || ns == namespace {
+			ruleSet[ns] = groups
+		}
+	}
+	m.mu.Unlock()
+
+	if namespace != "" && len(ruleSet) == 0 {
+		http.Error(w, "no rule groups found", http.StatusNotFound)
+		return
+	}
+	writeYAML(w, ruleSet)
+}
+
+func (m *FakeMimir) getRuleGroup(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Header.Get(user.OrgIDHeaderName)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, group := range m.ruleGroups[tenantID][r.PathValue("namespace")] {
+		if group.Name == r.PathValue("group") {
+			writeYAML(w, group)
+			return
+		}
+	}
+	http.Error(w, "group does not exist", http.StatusNotFound)
+}
+
+func (m *FakeMimir) setRuleGroup(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Header.Get(user.OrgIDHeaderName)
+	namespace := r.PathValue("namespace")
+
+	var group rulefmt.RuleGroup
+	if !readYAML(w, r, &group) {
+		return
+	}
+	if group.Name == "" {
+		http.Error(w, "invalid rules configuration: rule group name must not be empty", http.StatusBadRequest)
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.storeRuleGroup(tenantID, namespace, group)
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// storeRuleGroup creates or replaces the rule group, m.mu must be held.
+func (m *FakeMimir) storeRuleGroup(tenantID, namespace string, group rulefmt.RuleGroup) {
+	if m.ruleGroups[tenantID] == nil {
+		m.ruleGroups[tenantID] = map[string][]rulefmt.RuleGroup{}
+	}
+	groups := m.ruleGroups[tenantID][namespace]
+	replaced := false
+	for i := range groups {
+		if groups[i].Name == group.Name {
+			groups[i], replaced = group, true
+		}
+	}
+	if !replaced {
+		groups = append(groups, group)
+		sort.Slice(groups, func(i, j int) bool { return groups[i].Name < groups[j].Name })
+	}
+	m.ruleGroups[tenantID][namespace] = groups
+}
+
+func (m *FakeMimir) deleteRuleGroup(w http.ResponseWriter, r *http.Request) {
+	tenantID := r.Header.Get(user.OrgIDHeaderName)
+	namespace := r.PathValue("namespace")
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	groups := m.ruleGroups[tenantID][namespace]
+	for i := range groups {
+		if groups[i].Name == r.PathValue("group") {
+			groups = append(groups[:i:i], groups[i+1:]...)
+			break
+		}
+	}
+	if len(groups) == 0 {
+		delete(m.ruleGroups[tenantID], namespace)
+	} else {
+		m.ruleGroups[tenantID][namespace] = groups
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (m *FakeMimir) deleteRuleNamespace(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.ruleGroups[r.Header.Get(user.OrgIDHeaderName)], r.PathValue("namespace"))
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (m *FakeMimir) getAlertmanagerConfig(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	config, ok := m.alertmanagerConfigs[r.Header.Get(user.OrgIDHeaderName)]
+	m.mu.Unlock()
+
+	if !ok {
+		http.Error(w, "alertmanager storage object not found", http.StatusNotFound)
+		return
+	}
+	writeYAML(w, config)
+}
+
+func (m *FakeMimir) setAlertmanagerConfig(w http.ResponseWriter, r *http.Request) {
+	var config alertmanagerConfig
+	if !readYAML(w, r, &config) {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.alertmanagerConfigs[r.Header.Get(user.OrgIDHeaderName)] = config
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (m *FakeMimir) deleteAlertmanagerConfig(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.alertmanagerConfigs, r.Header.Get(user.OrgIDHeaderName))
+	w.WriteHeader(http.StatusOK)
+}
+
+func (m *FakeMimir) getAlertmanagerStatus(w http.ResponseWriter, _ *http.Request) {
+	m.mu.Lock()
+	tenants := make([]string, 0, len(m.alertmanagerConfigs))
+	for tenantID := range m.alertmanagerConfigs {
+		tenants = append(tenants, tenantID)
+	}
+	m.mu.Unlock()
+	sort.Strings(tenants)
+
+	w.Header().Set("Content-Type", "text/plain")
+	_, _ = io.WriteString(w, "Alertmanager status: running\n")
+	for _, tenantID := range tenants {
+		_, _ = io.WriteString(w, "tenant "+tenantID+": configured\n")
+	}
+}
+
+// readYAML decodes the request body, gzip compressed or not, into v, answering 400 Bad Request
+// if it cannot.
+func readYAML(w http.ResponseWriter, r *http.Request, v any) bool {
+	var reader io.Reader = r.Body
+	var err error
+	if r.Header.Get("Content-Encoding") == "gzip" {
+		reader, err = gzip.NewReader(r.Body)
+	}
+	var body []byte
+	if err == nil {
+		body, err = io.ReadAll(reader)
+	}
+	if err == nil {
+		err = yaml.Unmarshal(body, v)
+	}
+	if err != nil {
+		http.Error(w, "error parsing request body: "+err.Error(), http.StatusBadRequest)
+		return false
+	}
+	return true
+}
+
+// writeYAML encodes v as response body.
+func writeYAML(w http.ResponseWriter, v any) {
+	body, err := yaml.Marshal(v)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/yaml")
+	_, _ = w.Write(body)
+}
@@ -0,0 +1,161 @@
+package testing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/prometheus/prometheus/model/rulefmt"
+
+	"github.com/syndlex/openawareness-controller/internal/mimir"
+)
+
+// MimirReader reads the state pushed to Mimir by the controller.
+type MimirReader interface {
+	// GetAlertmanagerConfig returns the Alertmanager configuration and template files of the
+	// tenant, empty if it has none
+	GetAlertmanagerConfig(ctx context.Context, tenantID string) (string, map[string]string, error)
+	// ListRules returns the rule groups of the tenant by namespace, only of namespace if set
+	ListRules(ctx context.Context, namespace string, tenantID string) (map[string][]rulefmt.RuleGroup, error)
+}
+
+// NewMimirClient returns a MimirReader for the Mimir instance at address, e.g. the URL of a
+// FakeMimir.
+func NewMimirClient(ctx context.Context, address string) (MimirReader, error) {
+	return mimir.New(ctx, mimir.Config{Address: address})
+}
+
+// VerifyMimirAlertmanagerConfig waits for the Alertmanager configuration of the tenant to
+// contain the receiver.
+func VerifyMimirAlertmanagerConfig(
+	ctx context.Context,
+	reader MimirReader,
+	tenantID string,
+	expectedReceiver string,
+	timeout, interval time.Duration,
+) error {
+	return poll(ctx, timeout, interval, fmt.Sprintf("receiver %s is pushed", expectedReceiver),
+		func(ctx context.Context) (bool, error) {
+			config, _, err := reader.GetAlertmanagerConfig(ctx, tenantID)
+			if err != nil {
+				return false, err
+			}
+			return strings.Contains(config, expectedReceiver), nil
+		})
+}
+
+// VerifyMimirTemplate waits for the template file to be pushed with the Alertmanager
+// configuration of the tenant.
+func VerifyMimirTemplate(
+	ctx context.Context,
+	reader MimirReader,
+	tenantID string,
+	templateName string,
+	timeout, interval time.Duration,
+) error {
+	return poll(ctx, timeout, interval, fmt.Sprintf("template %s is pushed", templateName),
+		func(ctx context.Context) (bool, error) {
+			_, templates, err := reader.GetAlertmanagerConfig(ctx, tenantID)
+			if err != nil {
+				return false, err
+			}
+			_, exists := templates[templateName]
+			return exists, nil
+		})
+}
+
+// VerifyMimirAlertmanagerConfigDeleted waits for the Alertmanager configuration of the tenant
+// to be deleted.
+func VerifyMimirAlertmanagerConfigDeleted(
+	ctx context.Context,
+	reader MimirReader,
+	tenantID string,
+	timeout, interval time.Duration,
+) error {
+	return poll(ctx, timeout, interval, "the Alertmanager configuration is deleted",
+		func(ctx context.Context) (bool, error) {
+			config, templates, err := reader.GetAlertmanagerConfig(ctx, tenantID)
+			if err != nil {
+				return false, err
+			}
+			return config == "" && len(templates) == 0, nil
+		})
+}
+
+// VerifyMimirRuleGroup waits for the rule group to exist in the namespace of the tenant.
+func VerifyMimirRuleGroup(
+	ctx context.Context,
+	reader MimirReader,
+	tenantID, namespace, groupName string,
+	timeout, interval time.Duration,
+) error {
+	return verifyMimirRuleGroup(ctx, reader, tenantID, namespace, groupName, timeout, interval,
+		fmt.Sprintf("rule group %s exists in namespace %s", groupName, namespace),
+		func(*rulefmt.RuleGroup) bool { return true })
+}
+
+// VerifyMimirRuleGroupContent waits for the rule group in the namespace of the tenant to have
+// the expected number of rules.
+func VerifyMimirRuleGroupContent(
+	ctx context.Context,
+	reader MimirReader,
+	tenantID, namespace, groupName string,
+	expectedRuleCount int,
+	timeout, interval time.Duration,
+) error {
+	return verifyMimirRuleGroup(ctx, reader, tenantID, namespace, groupName, timeout, interval,
+		fmt.Sprintf("rule group %s has %d rules in namespace %s", groupName, expectedRuleCount, namespace),
+		func(group *rulefmt.RuleGroup) bool { return len(group.Rules) == expectedRuleCount })
+}
+
+// VerifyMimirRuleGroupDeleted waits for the rule group to be deleted from the namespace of the
+// tenant. A namespace without rule groups counts as deleted.
+func VerifyMimirRuleGroupDeleted(
+	ctx context.Context,
+	reader MimirReader,
+	tenantID, namespace, groupName string,
+	timeout, interval time.Duration,
+) error {
+	return poll(ctx, timeout, interval, fmt.Sprintf("rule group %s is deleted from namespace %s", groupName, namespace),
+		func(ctx context.Context) (bool, error) {
+			ruleSet, err := reader.ListRules(ctx, namespace, tenantID)
+			if errors.Is(err, mimir.ErrResourceNotFound) {
+				return true, nil
+			}
+			if err != nil {
+				return false, err
+			}
+			for _, group := range ruleSet[namespace] {
+				if group.Name == groupName {
+					return false, nil
+				}
+			}
+			return true, nil
+		})
+}
+
+// verifyMimirRuleGroup waits for the rule group in the namespace of the tenant to exist and
+// satisfy condition.
+func verifyMimirRuleGroup(
+	ctx context.Context,
+	reader MimirReader,
+	tenantID, namespace, groupName string,
+	timeout, interval time.Duration,
+	what string,
+	condition func(*rulefmt.RuleGroup) bool,
+) error {
+	return poll(ctx, timeout, interval, what, func(ctx context.Context) (bool, error) {
+		ruleSet, err := reader.ListRules(ctx, namespace, tenantID)
+		if err != nil {
+			return false, err
+		}
+		for i, group := range ruleSet[namespace] {
+			if group.Name == groupName {
+				return condition(&ruleSet[namespace][i]), nil
+			}
+		}
+		return false, nil
+	})
+}
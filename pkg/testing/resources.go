@@ -0,0 +1,228 @@
+package testing
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	openawarenessv1beta1 "github.com/syndlex/openawareness-controller/api/openawareness/v1beta1"
+	"github.com/syndlex/openawareness-controller/internal/controller/utils"
+)
+
+// Annotations of the resources synced by the controller, see CreateMimirAlertTenant and
+// CreatePrometheusRule.
+const (
+	// ClientNameAnnotation selects the ClientConfig a resource is synced with
+	ClientNameAnnotation = utils.ClientNameAnnotation
+	// MimirTenantAnnotation selects the Mimir tenant a resource is synced to
+	MimirTenantAnnotation = utils.MimirTenantAnnotation
+	// Finalizer is the finalizer the controller adds to the resources it syncs
+	Finalizer = utils.FinalizerAnnotation
+)
+
+// CreateNamespace creates a test namespace.
+// It waits for a namespace of the same name from a previous run to be deleted first.
+func CreateNamespace(
+	ctx context.Context,
+	k8sClient client.Client,
+	name string,
+	timeout, interval time.Duration,
+) (*corev1.Namespace, error) {
+	namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name}}
+	if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(namespace), namespace); err == nil &&
+		namespace.DeletionTimestamp != nil {
+		if err := WaitForDeleted(ctx, k8sClient, namespace, timeout, interval); err != nil {
+			return nil, fmt.Errorf("previous namespace %s: %w", name, err)
+		}
+	}
+
+	namespace = &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name}}
+	if err := k8sClient.Create(ctx, namespace); err != nil {
+		return nil, err
+	}
+	return namespace, nil
+}
+
+// DeleteNamespace deletes a test namespace and waits for it to be fully removed.
+// A nil namespace is ignored.
+func DeleteNamespace(
+	ctx context.Context,
+	k8sClient client.Client,
+	namespace *corev1.Namespace,
+	timeout, interval time.Duration,
+) error {
+	if namespace == nil {
+		return nil
+	}
+	if err := k8sClient.Delete(ctx, namespace); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	return WaitForDeleted(ctx, k8sClient, namespace, timeout, interval)
+}
+
+// Update reads the latest version of obj, applies mutate and updates it, retrying on conflicts.
+func Update(ctx context.Context, k8sClient client.Client, obj client.Object, mutate func()) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(obj), obj); err != nil {
+			return err
+		}
+		mutate()
+		return k8sClient.Update(ctx, obj)
+	})
+}
+
+// CreateClientConfig creates a ClientConfig for the instance at address.
+// It returns the created resource or an error if creation fails.
+func CreateClientConfig(
+	ctx context.Context,
+	k8sClient client.Client,
+	name, namespace, address string,
+	clientType openawarenessv1beta1.ClientType,
+	annotations map[string]string,
+) (*openawarenessv1beta1.ClientConfig, error) {
+	clientConfig := &openawarenessv1beta1.ClientConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   namespace,
+			Annotations: annotations,
+		},
+		Spec: openawarenessv1beta1.ClientConfigSpec{
+			Address: address,
+			Type:    clientType,
+		},
+	}
+	if err := k8sClient.Create(ctx, clientConfig); err != nil {
+		return nil, err
+	}
+	return clientConfig, nil
+}
+
+// WaitForConnectionStatus waits for the ConnectionStatus of a ClientConfig to reach the
+// expected value and returns the ClientConfig.
+func WaitForConnectionStatus(
+	ctx context.Context,
+	k8sClient client.Client,
+	name, namespace string,
+	expectedStatus openawarenessv1beta1.ConnectionStatus,
+	timeout, interval time.Duration,
+) (*openawarenessv1beta1.ClientConfig, error) {
+	clientConfig := &openawarenessv1beta1.ClientConfig{}
+	key := client.ObjectKey{Name: name, Namespace: namespace}
+	err := poll(ctx, timeout, interval, fmt.Sprintf("the ClientConfig is %s", expectedStatus),
+		func(ctx context.Context) (bool, error) {
+			if err := k8sClient.Get(ctx, key, clientConfig); err != nil {
+				return false, err
+			}
+			return clientConfig.Status.ConnectionStatus == expectedStatus, nil
+		})
+	return clientConfig, err
+}
+
+// WaitForConditionsSet waits for conditions to be set in the status of a ClientConfig and
+// returns the ClientConfig.
+func WaitForConditionsSet(
+	ctx context.Context,
+	k8sClient client.Client,
+	name, namespace string,
+	timeout, interval time.Duration,
+) (*openawarenessv1beta1.ClientConfig, error) {
+	clientConfig := &openawarenessv1beta1.ClientConfig{}
+	key := client.ObjectKey{Name: name, Namespace: namespace}
+	err := poll(ctx, timeout, interval, "the ClientConfig has conditions", func(ctx context.Context) (bool, error) {
+		if err := k8sClient.Get(ctx, key, clientConfig); err != nil {
+			return false, err
+		}
+		return len(clientConfig.Status.Conditions) > 0, nil
+	})
+	return clientConfig, err
+}
+
+// CreateMimirAlertTenant creates a MimirAlertTenant synced with the ClientConfig clientName
+// to the Mimir tenant.
+// It returns the created resource or an error if creation fails.
+func CreateMimirAlertTenant(
+	ctx context.Context,
+	k8sClient client.Client,
+	name, namespace, clientName, tenant string,
+	config string,
+	templates map[string]string,
+) (*openawarenessv1beta1.MimirAlertTenant, error) {
+	alertTenant := &openawarenessv1beta1.MimirAlertTenant{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Annotations: map[string]string{
+				ClientNameAnnotation:  clientName,
+				MimirTenantAnnotation: tenant,
+			},
+		},
+		Spec: openawarenessv1beta1.MimirAlertTenantSpec{
+			AlertmanagerConfig: config,
+			TemplateFiles:      templates,
+		},
+	}
+	if err := k8sClient.Create(ctx, alertTenant); err != nil {
+		return nil, err
+	}
+	return alertTenant, nil
+}
+
+// WaitForSyncStatus waits for the SyncStatus of a MimirAlertTenant to be reported, i.e. to be
+// neither empty nor Pending, and returns the MimirAlertTenant.
+func WaitForSyncStatus(
+	ctx context.Context,
+	k8sClient client.Client,
+	name, namespace string,
+	timeout, interval time.Duration,
+) (*openawarenessv1beta1.MimirAlertTenant, error) {
+	alertTenant := &openawarenessv1beta1.MimirAlertTenant{}
+	key := client.ObjectKey{Name: name, Namespace: namespace}
+	err := poll(ctx, timeout, interval, "the MimirAlertTenant reports its sync status",
+		func(ctx context.Context) (bool, error) {
+			if err := k8sClient.Get(ctx, key, alertTenant); err != nil {
+				return false, err
+			}
+			return alertTenant.Status.SyncStatus != "" &&
+				alertTenant.Status.SyncStatus != openawarenessv1beta1.SyncStatusPending, nil
+		})
+	return alertTenant, err
+}
+
+// CreatePrometheusRule creates a PrometheusRule synced with the ClientConfig clientName.
+// The tenant annotation is only set if tenant is not empty.
+// It returns the created resource or an error if creation fails.
+func CreatePrometheusRule(
+	ctx context.Context,
+	k8sClient client.Client,
+	name, namespace string,
+	clientName, tenant string,
+	groups []monitoringv1.RuleGroup,
+) (*monitoringv1.PrometheusRule, error) {
+	annotations := map[string]string{
+		ClientNameAnnotation: clientName,
+	}
+	if tenant != "" {
+		annotations[MimirTenantAnnotation] = tenant
+	}
+
+	prometheusRule := &monitoringv1.PrometheusRule{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   namespace,
+			Annotations: annotations,
+		},
+		Spec: monitoringv1.PrometheusRuleSpec{
+			Groups: groups,
+		},
+	}
+	if err := k8sClient.Create(ctx, prometheusRule); err != nil {
+		return nil, err
+	}
+	return prometheusRule, nil
+}
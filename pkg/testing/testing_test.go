@@ -0,0 +1,204 @@
+package testing_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/prometheus/prometheus/model/rulefmt"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	openawarenessv1beta1 "github.com/syndlex/openawareness-controller/api/openawareness/v1beta1"
+	"github.com/syndlex/openawareness-controller/internal/mimir"
+	oatesting "github.com/syndlex/openawareness-controller/pkg/testing"
+)
+
+const (
+	timeout  = time.Second
+	interval = 10 * time.Millisecond
+)
+
+func TestFakeMimirRuleGroups(t *testing.T) {
+	ctx := context.Background()
+	server := oatesting.NewFakeMimir()
+	t.Cleanup(server.Close)
+	client, err := mimir.New(ctx, mimir.Config{Address: server.URL})
+	if err != nil {
+		t.Fatalf("creating client: %v", err)
+	}
+
+	if err := client.HealthCheck(ctx); err != nil {
+		t.Fatalf("HealthCheck: %v", err)
+	}
+	group := rulefmt.RuleGroup{Name: "group", Rules: []rulefmt.Rule{{Record: "up:sum", Expr: "sum(up)"}}}
+	if err := client.CreateRuleGroup(ctx, "ns", group, "tenant-a"); err != nil {
+		t.Fatalf("CreateRuleGroup: %v", err)
+	}
+
+	if err := oatesting.VerifyMimirRuleGroupContent(ctx, client, "tenant-a", "ns", "group", 1, timeout, interval); err != nil {
+		t.Errorf("VerifyMimirRuleGroupContent: %v", err)
+	}
+	if got := server.RuleGroups("tenant-b", "ns"); len(got) != 0 {
+		t.Errorf("expected tenants to be isolated, got %v", got)
+	}
+	err = oatesting.VerifyMimirRuleGroup(ctx, client, "tenant-a", "ns", "other", 50*time.Millisecond, interval)
+	if !errors.Is(err, oatesting.ErrTimeout) {
+		t.Errorf("expected a missing group to time out, got %v", err)
+	}
+
+	if err := client.DeleteRuleGroup(ctx, "ns", "group", "tenant-a"); err != nil {
+		t.Fatalf("DeleteRuleGroup: %v", err)
+	}
+	if err := oatesting.VerifyMimirRuleGroupDeleted(ctx, client, "tenant-a", "ns", "group", timeout, interval); err != nil {
+		t.Errorf("VerifyMimirRuleGroupDeleted: %v", err)
+	}
+}
+
+func TestFakeMimirAlertmanagerConfig(t *testing.T) {
+	ctx := context.Background()
+	server := oatesting.NewFakeMimir()
+	t.Cleanup(server.Close)
+	reader, err := oatesting.NewMimirClient(ctx, server.URL)
+	if err != nil {
+		t.Fatalf("creating client: %v", err)
+	}
+	client := reader.(*mimir.Client)
+
+	config := "route:\n  receiver: team\nreceivers:\n- name: team\n"
+	if err := client.CreateAlertmanagerConfig(ctx, config, map[string]string{"default": "{{ define \"x\" }}{{ end }}"}, "tenant-a"); err != nil {
+		t.Fatalf("CreateAlertmanagerConfig: %v", err)
+	}
+	if err := oatesting.VerifyMimirAlertmanagerConfig(ctx, reader, "tenant-a", "team", timeout, interval); err != nil {
+		t.Errorf("VerifyMimirAlertmanagerConfig: %v", err)
+	}
+	if err := oatesting.VerifyMimirTemplate(ctx, reader, "tenant-a", "default", timeout, interval); err != nil {
+		t.Errorf("VerifyMimirTemplate: %v", err)
+	}
+	if got, _, ok := server.AlertmanagerConfig("tenant-a"); !ok || got != config {
+		t.Errorf("expected the pushed configuration to be stored, got %q", got)
+	}
+
+	if err := client.DeleteAlermanagerConfig(ctx, "tenant-a"); err != nil {
+		t.Fatalf("DeleteAlermanagerConfig: %v", err)
+	}
+	if err := oatesting.VerifyMimirAlertmanagerConfigDeleted(ctx, reader, "tenant-a", timeout, interval); err != nil {
+		t.Errorf("VerifyMimirAlertmanagerConfigDeleted: %v", err)
+	}
+
+	server.FailWith(http.StatusServiceUnavailable, "ingester unavailable")
+	var apiErr *mimir.APIError
+	if err := client.CreateAlertmanagerConfig(ctx, config, nil, "tenant-a"); !errors.As(err, &apiErr) ||
+		apiErr.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("expected the configured failure, got %v", err)
+	}
+}
+
+func TestWaitForConnectionStatus(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = openawarenessv1beta1.AddToScheme(scheme)
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(&openawarenessv1beta1.ClientConfig{}).Build()
+
+	clientConfig, err := oatesting.CreateClientConfig(ctx, k8sClient, "mimir", "default", "http://mimir", openawarenessv1beta1.Mimir, nil)
+	if err != nil {
+		t.Fatalf("CreateClientConfig: %v", err)
+	}
+
+	_, err = oatesting.WaitForConnectionStatus(ctx, k8sClient, "mimir", "default",
+		openawarenessv1beta1.ConnectionStatusConnected, 50*time.Millisecond, interval)
+	if !errors.Is(err, oatesting.ErrTimeout) {
+		t.Fatalf("expected a timeout before the status is set, got %v", err)
+	}
+
+	clientConfig.Status.ConnectionStatus = openawarenessv1beta1.ConnectionStatusConnected
+	clientConfig.Status.Conditions = []metav1.Condition{{
+		Type: openawarenessv1beta1.ConditionTypeReady, Status: metav1.ConditionTrue,
+		Reason: openawarenessv1beta1.ReasonConnected, LastTransitionTime: metav1.Now(),
+	}}
+	if err := k8sClient.Status().Update(ctx, clientConfig); err != nil {
+		t.Fatalf("updating status: %v", err)
+	}
+	got, err := oatesting.WaitForConnectionStatus(ctx, k8sClient, "mimir", "default",
+		openawarenessv1beta1.ConnectionStatusConnected, timeout, interval)
+	if err != nil {
+		t.Fatalf("WaitForConnectionStatus: %v", err)
+	}
+	if oatesting.FindCondition(got.Status.Conditions, openawarenessv1beta1.ConditionTypeReady) == nil {
+		t.Errorf("expected the Ready condition to be found")
+	}
+
+	if err := k8sClient.Delete(ctx, clientConfig); err != nil {
+		t.Fatalf("deleting: %v", err)
+	}
+	if err := oatesting.WaitForDeleted(ctx, k8sClient, clientConfig, timeout, interval); err != nil {
+		t.Errorf("WaitForDeleted: %v", err)
+	}
+}
+
+func TestFakeMimirFailureInjection(t *testing.T) {
+	ctx := context.Background()
+	server := oatesting.NewFakeMimir()
+	t.Cleanup(server.Close)
+	client, err := mimir.New(ctx, mimir.Config{Address: server.URL})
+	if err != nil {
+		t.Fatalf("creating client: %v", err)
+	}
+	group := rulefmt.RuleGroup{Name: "group"}
+
+	server.FailNext(1, http.StatusTooManyRequests, "per-tenant rate limit exceeded")
+	var apiErr *mimir.APIError
+	if err := client.CreateRuleGroup(ctx, "ns", group, "tenant-a"); !errors.As(err, &apiErr) ||
+		apiErr.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("expected a 429 for the first request, got %v", err)
+	}
+	if err := client.CreateRuleGroup(ctx, "ns", group, "tenant-a"); err != nil {
+		t.Fatalf("expected the second request to succeed, got %v", err)
+	}
+
+	server.SetLatency(time.Second)
+	timeoutCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	if _, err := client.ListRules(timeoutCtx, "ns", "tenant-a"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the slow response to time out, got %v", err)
+	}
+	server.SetLatency(0)
+
+	status, err := client.GetAlertmanagerStatus(ctx, "tenant-a")
+	if err != nil || status == "" {
+		t.Errorf("GetAlertmanagerStatus = %q, %v", status, err)
+	}
+
+	requests := server.Requests()
+	if len(requests) != 4 {
+		t.Fatalf("expected 4 recorded requests, got %v", requests)
+	}
+	if got := requests[1]; got.Method != http.MethodPost || got.Path != "/prometheus/config/v1/rules/ns" || got.TenantID != "tenant-a" {
+		t.Errorf("unexpected request %+v", got)
+	}
+}
+
+func TestFakeMimirOutOfBandChanges(t *testing.T) {
+	ctx := context.Background()
+	server := oatesting.NewFakeMimir()
+	t.Cleanup(server.Close)
+	reader, err := oatesting.NewMimirClient(ctx, server.URL)
+	if err != nil {
+		t.Fatalf("creating client: %v", err)
+	}
+
+	server.SetRuleGroup("tenant-a", "ns", rulefmt.RuleGroup{Name: "manual"})
+	server.SetAlertmanagerConfig("tenant-a", "route:\n  receiver: manual\n", nil)
+
+	if err := oatesting.VerifyMimirRuleGroup(ctx, reader, "tenant-a", "ns", "manual", timeout, interval); err != nil {
+		t.Errorf("VerifyMimirRuleGroup: %v", err)
+	}
+	if err := oatesting.VerifyMimirAlertmanagerConfig(ctx, reader, "tenant-a", "manual", timeout, interval); err != nil {
+		t.Errorf("VerifyMimirAlertmanagerConfig: %v", err)
+	}
+}
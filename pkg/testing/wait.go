@@ -0,0 +1,117 @@
+package testing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ErrTimeout is returned by the waiting and verifying helpers if the expected state is not
+// reached within the timeout.
+var ErrTimeout = errors.New("timed out")
+
+// poll calls check every interval until it reports true or the timeout expires. Errors of check
+// are retried; on timeout an error wrapping ErrTimeout is returned, naming what was awaited and
+// the last error of check.
+func poll(
+	ctx context.Context,
+	timeout, interval time.Duration,
+	what string,
+	check func(ctx context.Context) (bool, error),
+) error {
+	var lastErr error
+	err := wait.PollUntilContextTimeout(ctx, interval, timeout, true, func(ctx context.Context) (bool, error) {
+		done, err := check(ctx)
+		lastErr = err
+		return err == nil && done, nil
+	})
+	if err == nil {
+		return nil
+	}
+	if lastErr != nil {
+		return fmt.Errorf("%w after %s waiting until %s: %w", ErrTimeout, timeout, what, lastErr)
+	}
+	return fmt.Errorf("%w after %s waiting until %s", ErrTimeout, timeout, what)
+}
+
+// FindCondition searches for a condition by type in a list of conditions.
+// Returns the condition if found, or nil if not found.
+func FindCondition(conditions []metav1.Condition, conditionType string) *metav1.Condition {
+	for i := range conditions {
+		if conditions[i].Type == conditionType {
+			return &conditions[i]
+		}
+	}
+	return nil
+}
+
+// WaitForCreated waits for obj, identified by its name and namespace, to exist and reads it
+// into obj.
+func WaitForCreated(
+	ctx context.Context,
+	k8sClient client.Client,
+	obj client.Object,
+	timeout, interval time.Duration,
+) error {
+	return poll(ctx, timeout, interval, "the resource exists", func(ctx context.Context) (bool, error) {
+		return true, k8sClient.Get(ctx, client.ObjectKeyFromObject(obj), obj)
+	})
+}
+
+// WaitForFinalizer waits for the finalizer to be added to obj, which is read into obj.
+func WaitForFinalizer(
+	ctx context.Context,
+	k8sClient client.Client,
+	obj client.Object,
+	finalizer string,
+	timeout, interval time.Duration,
+) error {
+	return poll(ctx, timeout, interval, fmt.Sprintf("finalizer %s is added", finalizer),
+		func(ctx context.Context) (bool, error) {
+			if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(obj), obj); err != nil {
+				return false, err
+			}
+			for _, f := range obj.GetFinalizers() {
+				if f == finalizer {
+					return true, nil
+				}
+			}
+			return false, nil
+		})
+}
+
+// WaitForDeletionTimestamp waits for obj to be marked for deletion or to be gone.
+func WaitForDeletionTimestamp(
+	ctx context.Context,
+	k8sClient client.Client,
+	obj client.Object,
+	timeout, interval time.Duration,
+) error {
+	return poll(ctx, timeout, interval, "deletion timestamp is set", func(ctx context.Context) (bool, error) {
+		if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(obj), obj); err != nil {
+			return client.IgnoreNotFound(err) == nil, client.IgnoreNotFound(err)
+		}
+		return obj.GetDeletionTimestamp() != nil, nil
+	})
+}
+
+// WaitForDeleted waits for obj to be fully removed, e.g. after its finalizers were released.
+func WaitForDeleted(
+	ctx context.Context,
+	k8sClient client.Client,
+	obj client.Object,
+	timeout, interval time.Duration,
+) error {
+	return poll(ctx, timeout, interval, "the resource is deleted", func(ctx context.Context) (bool, error) {
+		err := k8sClient.Get(ctx, client.ObjectKeyFromObject(obj), obj)
+		if err == nil {
+			return false, nil
+		}
+		return client.IgnoreNotFound(err) == nil, client.IgnoreNotFound(err)
+	})
+}
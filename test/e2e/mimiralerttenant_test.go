@@ -284,6 +284,12 @@ receivers:
 
 	Context("When deleting a MimirAlertTenant", func() {
 		It("Should properly clean up resources", func() {
+			By("Confirming the deletion of the Alertmanager configuration")
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: alertTenantName, Namespace: testNamespace}, alertTenant)).
+				To(Succeed())
+			alertTenant.Annotations[utils.ConfirmDeleteAnnotation] = "true"
+			Expect(k8sClient.Update(ctx, alertTenant)).To(Succeed())
+
 			By("Deleting the MimirAlertTenant")
 			Expect(k8sClient.Delete(ctx, alertTenant)).To(Succeed())
 
@@ -6,10 +6,10 @@ import (
 
 	. "github.com/onsi/gomega"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	openawarenessv1beta1 "github.com/syndlex/openawareness-controller/api/openawareness/v1beta1"
+	oatesting "github.com/syndlex/openawareness-controller/pkg/testing"
 )
 
 // CreateClientConfig creates a ClientConfig resource for testing.
@@ -21,23 +21,7 @@ func CreateClientConfig(
 	clientType openawarenessv1beta1.ClientType,
 	annotations map[string]string,
 ) (*openawarenessv1beta1.ClientConfig, error) {
-	clientConfig := &openawarenessv1beta1.ClientConfig{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:        name,
-			Namespace:   namespace,
-			Annotations: annotations,
-		},
-		Spec: openawarenessv1beta1.ClientConfigSpec{
-			Address: address,
-			Type:    clientType,
-		},
-	}
-
-	if err := k8sClient.Create(ctx, clientConfig); err != nil {
-		return nil, err
-	}
-
-	return clientConfig, nil
+	return oatesting.CreateClientConfig(ctx, k8sClient, name, namespace, address, clientType, annotations)
 }
 
 // WaitForClientConfigFinalizerAdded waits for a finalizer to be added to a ClientConfig.
@@ -47,17 +31,10 @@ func WaitForClientConfigFinalizerAdded(
 	name, namespace string,
 	timeout, interval time.Duration,
 ) error {
-	clientConfig := &openawarenessv1beta1.ClientConfig{}
-	namespacedName := types.NamespacedName{Name: name, Namespace: namespace}
-
-	Eventually(func() bool {
-		if err := k8sClient.Get(ctx, namespacedName, clientConfig); err != nil {
-			return false
-		}
-		return len(clientConfig.Finalizers) > 0
-	}, timeout, interval).Should(BeTrue(), "Finalizer should be added to ClientConfig")
-
-	return nil
+	clientConfig := &openawarenessv1beta1.ClientConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+	}
+	return oatesting.WaitForFinalizer(ctx, k8sClient, clientConfig, oatesting.Finalizer, timeout, interval)
 }
 
 // WaitForConnectionStatus waits for ClientConfig ConnectionStatus to reach the expected value.
@@ -68,17 +45,7 @@ func WaitForConnectionStatus(
 	expectedStatus openawarenessv1beta1.ConnectionStatus,
 	timeout, interval time.Duration,
 ) (*openawarenessv1beta1.ClientConfig, error) {
-	clientConfig := &openawarenessv1beta1.ClientConfig{}
-	namespacedName := types.NamespacedName{Name: name, Namespace: namespace}
-
-	Eventually(func() openawarenessv1beta1.ConnectionStatus {
-		if err := k8sClient.Get(ctx, namespacedName, clientConfig); err != nil {
-			return ""
-		}
-		return clientConfig.Status.ConnectionStatus
-	}, timeout, interval).Should(Equal(expectedStatus), "ConnectionStatus should be "+string(expectedStatus))
-
-	return clientConfig, nil
+	return oatesting.WaitForConnectionStatus(ctx, k8sClient, name, namespace, expectedStatus, timeout, interval)
 }
 
 // WaitForConditionsSet waits for conditions to be set in ClientConfig status.
@@ -88,17 +55,7 @@ func WaitForConditionsSet(
 	name, namespace string,
 	timeout, interval time.Duration,
 ) (*openawarenessv1beta1.ClientConfig, error) {
-	clientConfig := &openawarenessv1beta1.ClientConfig{}
-	namespacedName := types.NamespacedName{Name: name, Namespace: namespace}
-
-	Eventually(func() bool {
-		if err := k8sClient.Get(ctx, namespacedName, clientConfig); err != nil {
-			return false
-		}
-		return len(clientConfig.Status.Conditions) > 0
-	}, timeout, interval).Should(BeTrue(), "Conditions should be set")
-
-	return clientConfig, nil
+	return oatesting.WaitForConditionsSet(ctx, k8sClient, name, namespace, timeout, interval)
 }
 
 // UpdateClientConfigAddress updates the address of a ClientConfig.
@@ -108,22 +65,14 @@ func UpdateClientConfigAddress(
 	k8sClient client.Client,
 	name, namespace string,
 	newAddress string,
-	timeout, interval time.Duration,
+	_, _ time.Duration,
 ) error {
-	Eventually(func() error {
-		clientConfig := &openawarenessv1beta1.ClientConfig{}
-		if err := k8sClient.Get(ctx, types.NamespacedName{
-			Name:      name,
-			Namespace: namespace,
-		}, clientConfig); err != nil {
-			return err
-		}
-
+	clientConfig := &openawarenessv1beta1.ClientConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+	}
+	return oatesting.Update(ctx, k8sClient, clientConfig, func() {
 		clientConfig.Spec.Address = newAddress
-		return k8sClient.Update(ctx, clientConfig)
-	}, timeout, interval).Should(Succeed(), "Should update ClientConfig address")
-
-	return nil
+	})
 }
 
 // AddClientConfigAnnotation adds an annotation to a ClientConfig.
@@ -133,25 +82,17 @@ func AddClientConfigAnnotation(
 	k8sClient client.Client,
 	name, namespace string,
 	key, value string,
-	timeout, interval time.Duration,
+	_, _ time.Duration,
 ) error {
-	Eventually(func() error {
-		clientConfig := &openawarenessv1beta1.ClientConfig{}
-		if err := k8sClient.Get(ctx, types.NamespacedName{
-			Name:      name,
-			Namespace: namespace,
-		}, clientConfig); err != nil {
-			return err
-		}
-
+	clientConfig := &openawarenessv1beta1.ClientConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+	}
+	return oatesting.Update(ctx, k8sClient, clientConfig, func() {
 		if clientConfig.Annotations == nil {
 			clientConfig.Annotations = make(map[string]string)
 		}
 		clientConfig.Annotations[key] = value
-		return k8sClient.Update(ctx, clientConfig)
-	}, timeout, interval).Should(Succeed(), "Should add annotation to ClientConfig")
-
-	return nil
+	})
 }
 
 // VerifyConnectedStatus verifies that a ClientConfig has Connected status with proper conditions.
@@ -4,11 +4,10 @@ import (
 	"context"
 	"time"
 
-	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	oatesting "github.com/syndlex/openawareness-controller/pkg/testing"
 )
 
 // CreateNamespace creates a test namespace.
@@ -19,27 +18,7 @@ func CreateNamespace(
 	name string,
 	timeout, interval time.Duration,
 ) (*corev1.Namespace, error) {
-	// Check if namespace exists from previous run and wait for it to be deleted
-	existingNs := &corev1.Namespace{}
-	err := k8sClient.Get(ctx, types.NamespacedName{Name: name}, existingNs)
-	if err == nil && existingNs.DeletionTimestamp != nil {
-		Eventually(func() bool {
-			err := k8sClient.Get(ctx, types.NamespacedName{Name: name}, existingNs)
-			return err != nil && client.IgnoreNotFound(err) == nil
-		}, timeout, interval).Should(BeTrue(), "Previous namespace should be deleted")
-	}
-
-	namespace := &corev1.Namespace{
-		ObjectMeta: metav1.ObjectMeta{
-			Name: name,
-		},
-	}
-
-	if err := k8sClient.Create(ctx, namespace); err != nil {
-		return nil, err
-	}
-
-	return namespace, nil
+	return oatesting.CreateNamespace(ctx, k8sClient, name, timeout, interval)
 }
 
 // DeleteNamespace deletes a test namespace and waits for it to be fully removed.
@@ -49,22 +28,7 @@ func DeleteNamespace(
 	namespace *corev1.Namespace,
 	timeout, interval time.Duration,
 ) error {
-	if namespace == nil {
-		return nil
-	}
-
-	if err := k8sClient.Delete(ctx, namespace); err != nil {
-		return client.IgnoreNotFound(err)
-	}
-
-	// Wait for namespace to be fully deleted
-	Eventually(func() bool {
-		ns := &corev1.Namespace{}
-		err := k8sClient.Get(ctx, types.NamespacedName{Name: namespace.Name}, ns)
-		return err != nil && client.IgnoreNotFound(err) == nil
-	}, timeout, interval).Should(BeTrue(), "Namespace should be deleted")
-
-	return nil
+	return oatesting.DeleteNamespace(ctx, k8sClient, namespace, timeout, interval)
 }
 
 // WaitForDeletionTimestamp waits for a resource to have its DeletionTimestamp set.
@@ -74,19 +38,5 @@ func WaitForDeletionTimestamp(
 	obj client.Object,
 	timeout, interval time.Duration,
 ) error {
-	namespacedName := types.NamespacedName{
-		Name:      obj.GetName(),
-		Namespace: obj.GetNamespace(),
-	}
-
-	Eventually(func() bool {
-		if err := k8sClient.Get(ctx, namespacedName, obj); err != nil {
-			// Resource is gone
-			return true
-		}
-		// Check if deletion timestamp is set
-		return obj.GetDeletionTimestamp() != nil
-	}, timeout, interval).Should(BeTrue(), "DeletionTimestamp should be set")
-
-	return nil
+	return oatesting.WaitForDeletionTimestamp(ctx, k8sClient, obj, timeout, interval)
 }
@@ -2,18 +2,16 @@ package helper
 
 import (
 	"context"
-	"fmt"
-	"strings"
 	"time"
 
 	. "github.com/onsi/gomega"
 	"github.com/syndlex/openawareness-controller/internal/controller/utils"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	openawarenessv1beta1 "github.com/syndlex/openawareness-controller/api/openawareness/v1beta1"
 	"github.com/syndlex/openawareness-controller/internal/mimir"
+	oatesting "github.com/syndlex/openawareness-controller/pkg/testing"
 )
 
 // CreateMimirAlertTenant creates a MimirAlertTenant resource for testing.
@@ -25,26 +23,7 @@ func CreateMimirAlertTenant(
 	config string,
 	templates map[string]string,
 ) (*openawarenessv1beta1.MimirAlertTenant, error) {
-	alertTenant := &openawarenessv1beta1.MimirAlertTenant{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      name,
-			Namespace: namespace,
-			Annotations: map[string]string{
-				utils.ClientNameAnnotation:  clientName,
-				utils.MimirTenantAnnotation: tenant,
-			},
-		},
-		Spec: openawarenessv1beta1.MimirAlertTenantSpec{
-			AlertmanagerConfig: config,
-			TemplateFiles:      templates,
-		},
-	}
-
-	if err := k8sClient.Create(ctx, alertTenant); err != nil {
-		return nil, err
-	}
-
-	return alertTenant, nil
+	return oatesting.CreateMimirAlertTenant(ctx, k8sClient, name, namespace, clientName, tenant, config, templates)
 }
 
 // WaitForMimirAlertTenantCreation waits for a MimirAlertTenant to be created and returns it.
@@ -54,14 +33,10 @@ func WaitForMimirAlertTenantCreation(
 	name, namespace string,
 	timeout, interval time.Duration,
 ) (*openawarenessv1beta1.MimirAlertTenant, error) {
-	alertTenant := &openawarenessv1beta1.MimirAlertTenant{}
-	namespacedName := types.NamespacedName{Name: name, Namespace: namespace}
-
-	Eventually(func() error {
-		return k8sClient.Get(ctx, namespacedName, alertTenant)
-	}, timeout, interval).Should(Succeed(), "MimirAlertTenant should be created")
-
-	return alertTenant, nil
+	alertTenant := &openawarenessv1beta1.MimirAlertTenant{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+	}
+	return alertTenant, oatesting.WaitForCreated(ctx, k8sClient, alertTenant, timeout, interval)
 }
 
 // WaitForFinalizerAdded waits for a finalizer to be added to a MimirAlertTenant.
@@ -71,22 +46,10 @@ func WaitForFinalizerAdded(
 	name, namespace, finalizer string,
 	timeout, interval time.Duration,
 ) error {
-	alertTenant := &openawarenessv1beta1.MimirAlertTenant{}
-	namespacedName := types.NamespacedName{Name: name, Namespace: namespace}
-
-	Eventually(func() bool {
-		if err := k8sClient.Get(ctx, namespacedName, alertTenant); err != nil {
-			return false
-		}
-		for _, f := range alertTenant.Finalizers {
-			if f == finalizer {
-				return true
-			}
-		}
-		return false
-	}, timeout, interval).Should(BeTrue(), "Finalizer should be added to MimirAlertTenant")
-
-	return nil
+	alertTenant := &openawarenessv1beta1.MimirAlertTenant{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+	}
+	return oatesting.WaitForFinalizer(ctx, k8sClient, alertTenant, finalizer, timeout, interval)
 }
 
 // WaitForSyncStatusUpdate waits for the SyncStatus to be updated (non-empty and not Pending).
@@ -96,18 +59,7 @@ func WaitForSyncStatusUpdate(
 	name, namespace string,
 	timeout, interval time.Duration,
 ) (*openawarenessv1beta1.MimirAlertTenant, error) {
-	alertTenant := &openawarenessv1beta1.MimirAlertTenant{}
-	namespacedName := types.NamespacedName{Name: name, Namespace: namespace}
-
-	Eventually(func() bool {
-		if err := k8sClient.Get(ctx, namespacedName, alertTenant); err != nil {
-			return false
-		}
-		return alertTenant.Status.SyncStatus != "" &&
-			alertTenant.Status.SyncStatus != openawarenessv1beta1.SyncStatusPending
-	}, timeout, interval).Should(BeTrue(), "Status should be updated by controller")
-
-	return alertTenant, nil
+	return oatesting.WaitForSyncStatus(ctx, k8sClient, name, namespace, timeout, interval)
 }
 
 // WaitForResourceDeleted waits for a MimirAlertTenant to be fully deleted from Kubernetes.
@@ -117,15 +69,10 @@ func WaitForResourceDeleted(
 	name, namespace string,
 	timeout, interval time.Duration,
 ) error {
-	alertTenant := &openawarenessv1beta1.MimirAlertTenant{}
-	namespacedName := types.NamespacedName{Name: name, Namespace: namespace}
-
-	Eventually(func() bool {
-		err := k8sClient.Get(ctx, namespacedName, alertTenant)
-		return err != nil && client.IgnoreNotFound(err) == nil
-	}, timeout, interval).Should(BeTrue(), "MimirAlertTenant should be fully deleted")
-
-	return nil
+	alertTenant := &openawarenessv1beta1.MimirAlertTenant{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+	}
+	return oatesting.WaitForDeleted(ctx, k8sClient, alertTenant, timeout, interval)
 }
 
 // UpdateMimirAlertTenantConfig updates the AlertmanagerConfig of a MimirAlertTenant.
@@ -135,22 +82,14 @@ func UpdateMimirAlertTenantConfig(
 	k8sClient client.Client,
 	name, namespace string,
 	newConfig string,
-	timeout, interval time.Duration,
+	_, _ time.Duration,
 ) error {
-	Eventually(func() error {
-		alertTenant := &openawarenessv1beta1.MimirAlertTenant{}
-		if err := k8sClient.Get(ctx, types.NamespacedName{
-			Name:      name,
-			Namespace: namespace,
-		}, alertTenant); err != nil {
-			return err
-		}
-
+	alertTenant := &openawarenessv1beta1.MimirAlertTenant{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+	}
+	return oatesting.Update(ctx, k8sClient, alertTenant, func() {
 		alertTenant.Spec.AlertmanagerConfig = newConfig
-		return k8sClient.Update(ctx, alertTenant)
-	}, timeout, interval).Should(Succeed(), "Should update AlertmanagerConfig")
-
-	return nil
+	})
 }
 
 // AddTemplateFile adds a new template file to a MimirAlertTenant.
@@ -160,22 +99,17 @@ func AddTemplateFile(
 	k8sClient client.Client,
 	name, namespace string,
 	templateName, templateContent string,
-	timeout, interval time.Duration,
+	_, _ time.Duration,
 ) error {
-	Eventually(func() error {
-		alertTenant := &openawarenessv1beta1.MimirAlertTenant{}
-		if err := k8sClient.Get(ctx, types.NamespacedName{
-			Name:      name,
-			Namespace: namespace,
-		}, alertTenant); err != nil {
-			return err
+	alertTenant := &openawarenessv1beta1.MimirAlertTenant{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+	}
+	return oatesting.Update(ctx, k8sClient, alertTenant, func() {
+		if alertTenant.Spec.TemplateFiles == nil {
+			alertTenant.Spec.TemplateFiles = map[string]string{}
 		}
-
 		alertTenant.Spec.TemplateFiles[templateName] = templateContent
-		return k8sClient.Update(ctx, alertTenant)
-	}, timeout, interval).Should(Succeed(), "Should add template file")
-
-	return nil
+	})
 }
 
 // VerifyMimirAlertTenantAnnotations verifies that required annotations are present.
@@ -238,20 +172,7 @@ func VerifyMimirAPIConfig(
 	expectedReceiver string,
 	timeout, interval time.Duration,
 ) error {
-	Eventually(func() error {
-		config, _, err := mimirClient.GetAlertmanagerConfig(ctx, tenantID)
-		if err != nil {
-			return fmt.Errorf("failed to get config from Mimir: %w", err)
-		}
-
-		if !strings.Contains(config, expectedReceiver) {
-			return fmt.Errorf("expected receiver '%s' not found in config", expectedReceiver)
-		}
-
-		return nil
-	}, timeout, interval).Should(Succeed(), "Configuration should be present in Mimir API")
-
-	return nil
+	return oatesting.VerifyMimirAlertmanagerConfig(ctx, mimirClient, tenantID, expectedReceiver, timeout, interval)
 }
 
 // VerifyMimirAPITemplate verifies that a template was pushed to Mimir API.
@@ -262,20 +183,7 @@ func VerifyMimirAPITemplate(
 	templateName string,
 	timeout, interval time.Duration,
 ) error {
-	Eventually(func() error {
-		_, templates, err := mimirClient.GetAlertmanagerConfig(ctx, tenantID)
-		if err != nil {
-			return fmt.Errorf("failed to get templates from Mimir: %w", err)
-		}
-
-		if _, exists := templates[templateName]; !exists {
-			return fmt.Errorf("expected template '%s' not found", templateName)
-		}
-
-		return nil
-	}, timeout, interval).Should(Succeed(), "Template should be present in Mimir API")
-
-	return nil
+	return oatesting.VerifyMimirTemplate(ctx, mimirClient, tenantID, templateName, timeout, interval)
 }
 
 // VerifyMimirAPIConfigDeleted verifies that configuration was deleted from Mimir API.
@@ -286,15 +194,5 @@ func VerifyMimirAPIConfigDeleted(
 	tenantID string,
 	timeout, interval time.Duration,
 ) error {
-	Eventually(func() bool {
-		config, templates, err := mimirClient.GetAlertmanagerConfig(ctx, tenantID)
-		if err != nil {
-			// Unexpected error
-			return false
-		}
-		// Config should be empty after deletion
-		return config == "" && (templates == nil || len(templates) == 0)
-	}, timeout, interval).Should(BeTrue(), "Configuration should be deleted from Mimir API")
-
-	return nil
+	return oatesting.VerifyMimirAlertmanagerConfigDeleted(ctx, mimirClient, tenantID, timeout, interval)
 }